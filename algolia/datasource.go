@@ -0,0 +1,266 @@
+package algolia
+
+// Data Source Adapter for Algolia search indexes
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/jsonpath"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceAlgolia struct {
+	Client        *http.Client
+	ApplicationID string
+	APIKey        string // A search-only key is enough
+	Index         string
+	UserAgent     string
+
+	Filters string // Algolia filter expression, e.g. "type:article AND year > 2020"
+
+	// Attribute mappings; nested attributes use dots, e.g. "author.name"
+	TitleAttribute   string   // Default "title"
+	URLAttribute     string   // Optional
+	DataAttributes   []string // Written as "attribute: value"
+	SnippetAttribute string   // Optional; its snippet around the match is written first, e.g. "content"
+	SnippetWords     int      // Snippet length; default 40
+
+	Site string // Site for topics and data; default "algolia/<index>"
+
+	hits topicid.Map[hit]
+}
+
+type hit struct {
+	ObjectID string
+	Title    string
+	URL      string
+	Snippet  string
+	Record   map[string]interface{}
+}
+
+func New() *DataSourceAlgolia {
+	return &DataSourceAlgolia{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		UserAgent:      "locus/algolia-datasource",
+		TitleAttribute: "title",
+		SnippetWords:   40,
+	}
+}
+
+// Init implements models.DataSource
+func (es *DataSourceAlgolia) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.ApplicationID == "" || es.APIKey == "" || es.Index == "" {
+		return errors.New("ApplicationID, APIKey and Index are required for Algolia DataSource")
+	}
+	if es.TitleAttribute == "" {
+		es.TitleAttribute = "title"
+	}
+	if es.SnippetWords <= 0 {
+		es.SnippetWords = 40
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Runs an empty one-hit query, which also verifies the key can search the index
+func (es *DataSourceAlgolia) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	var response struct{}
+	return es.query(ctx, url.Values{"query": {""}, "hitsPerPage": {"1"}}, &response) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceAlgolia) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Algolia",
+		Homepage: "https://www.algolia.com",
+		TermsURL: "https://www.algolia.com/policies/terms/",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Hits are topics in ranking order, titled by TitleAttribute
+func (es *DataSourceAlgolia) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Algolia DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("hitsPerPage", strconv.Itoa(count))
+	params.Set("attributesToHighlight", "[]")
+	if es.Filters != "" {
+		params.Set("filters", es.Filters)
+	}
+	if es.SnippetAttribute != "" {
+		params.Set("attributesToSnippet", fmt.Sprintf(`["%s:%d"]`, es.SnippetAttribute, es.SnippetWords))
+		params.Set("highlightPreTag", "")
+		params.Set("highlightPostTag", "")
+	}
+	var response struct {
+		Hits []map[string]interface{} `json:"hits"`
+	}
+	if err := es.query(ctx, params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Hits))
+	for _, record := range response.Hits {
+		h := hit{Record: record}
+		h.ObjectID = jsonpath.Format(record["objectID"])
+		if es.SnippetAttribute != "" {
+			h.Snippet = jsonpath.MustCompile("_snippetResult." + es.SnippetAttribute + ".value").Text(record)
+		}
+		delete(record, "_snippetResult")
+		delete(record, "_highlightResult")
+		h.Title = attribute(record, es.TitleAttribute)
+		if h.Title == "" {
+			h.Title = h.ObjectID
+		}
+		if es.URLAttribute != "" {
+			h.URL = attribute(record, es.URLAttribute)
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     h.Title,
+			SourceURL: h.URL,
+			Site:      es.site(),
+			TopicID:   es.hits.Put(es.ApplicationID+"/"+es.Index+"/"+h.ObjectID, h),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns one item: the title, the snippet when configured, and a line per DataAttributes entry
+func (es *DataSourceAlgolia) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	h, ok := es.hits.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Algolia topicID %d", topicID)
+	}
+
+	lines := []string{h.Title}
+	if h.Snippet != "" {
+		lines = append(lines, h.Snippet)
+	}
+	for _, name := range es.DataAttributes {
+		if v := attribute(h.Record, name); v != "" {
+			lines = append(lines, name+": "+v)
+		}
+	}
+	return []datasource.DataSourceData{{
+		DataText:  strings.Join(lines, "\n"),
+		SourceURL: h.URL,
+		Site:      es.site(),
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// query runs a search, trying Algolia's fallback hosts in turn when one is unreachable or failing
+func (es *DataSourceAlgolia) query(ctx context.Context, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	payload, err := json.Marshal(map[string]string{"params": params.Encode()})
+	if err != nil {
+		return err
+	}
+	hosts := []string{es.ApplicationID + "-dsn.algolia.net"}
+	for i := 1; i <= 3; i++ {
+		hosts = append(hosts, fmt.Sprintf("%s-%d.algolianet.com", es.ApplicationID, i))
+	}
+
+	var lastErr error
+	for _, host := range hosts {
+		uri := "https://" + host + "/1/indexes/" + url.PathEscape(es.Index) + "/query"
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Algolia-Application-Id", es.ApplicationID)
+		req.Header.Set("X-Algolia-API-Key", es.APIKey)
+		if es.UserAgent != "" {
+			req.Header.Set("User-Agent", es.UserAgent)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+			lastErr = fmt.Errorf("algolia request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+			// Client errors are final; server errors are retried on the next host
+			if resp.StatusCode < 500 {
+				return lastErr
+			}
+			continue
+		}
+		err = json.NewDecoder(resp.Body).Decode(target)
+		resp.Body.Close()
+		return err
+	}
+	return lastErr
+}
+
+func (es *DataSourceAlgolia) site() string {
+	if es.Site != "" {
+		return es.Site
+	}
+	return "algolia/" + es.Index
+}
+
+// Helpers
+
+// attribute renders a possibly dotted attribute of a record as text
+func attribute(record map[string]interface{}, name string) string {
+	if value, ok := record[name]; ok {
+		return strings.TrimSpace(jsonpath.Format(value))
+	}
+	text, err := jsonpath.Lookup(record, name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(text)
+}
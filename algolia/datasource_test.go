@@ -0,0 +1,110 @@
+package algolia
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// redirect sends every request to srv, recording the hosts that were asked for
+type redirect struct {
+	srv   *httptest.Server
+	hosts []string
+}
+
+func (rt *redirect) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.hosts = append(rt.hosts, r.URL.Host)
+	u, _ := url.Parse(rt.srv.URL)
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host = u.Scheme, u.Host
+	r.Header.Set("X-Original-Host", rt.hosts[len(rt.hosts)-1])
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func newSource(t *testing.T, handler http.HandlerFunc) (*DataSourceAlgolia, *redirect) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	rt := &redirect{srv: srv}
+	es := New()
+	es.Client = &http.Client{Transport: rt}
+	es.ApplicationID = "APP"
+	es.APIKey = "key"
+	es.Index = "docs"
+	return es, rt
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es, rt := newSource(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Original-Host") == "APP-dsn.algolia.net" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path != "/1/indexes/docs/query" || r.Header.Get("X-Algolia-Application-Id") != "APP" || r.Header.Get("X-Algolia-API-Key") != "key" {
+			t.Errorf("unexpected request %s %v", r.URL, r.Header)
+		}
+		var body struct {
+			Params string `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		q, _ := url.ParseQuery(body.Params)
+		if q.Get("query") != "install" || q.Get("hitsPerPage") != "5" || q.Get("filters") != "type:guide" ||
+			q.Get("attributesToSnippet") != `["content:20"]` || q.Get("attributesToHighlight") != "[]" {
+			t.Errorf("unexpected params %s", body.Params)
+		}
+		fmt.Fprint(w, `{"hits":[
+			{"objectID":"42","title":"Installing","url":"https://example.com/install","meta":{"version":"2.1"},"tags":["setup","cli"],
+				"_snippetResult":{"content":{"value":"Run the installer …","matchLevel":"full"}},"_highlightResult":{}},
+			{"objectID":43}
+		]}`)
+	})
+	es.Filters = "type:guide"
+	es.URLAttribute = "url"
+	es.SnippetAttribute = "content"
+	es.SnippetWords = 20
+	es.DataAttributes = []string{"meta.version", "tags", "_snippetResult"}
+
+	topics, err := es.FetchTopics(5, "install")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Installing|https://example.com/install", "43|"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].SourceURL; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+	if got := strings.Join(rt.hosts, " "); got != "APP-dsn.algolia.net APP-1.algolianet.com" {
+		t.Errorf("hosts = %q", got)
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "Installing\nRun the installer …\nmeta.version: 2.1\ntags: setup, cli"
+	if len(data) != 1 || data[0].DataText != wantData || data[0].Site != "algolia/docs" {
+		t.Errorf("FetchData = %+v, want %q", data, wantData)
+	}
+}
+
+func TestClientErrorIsFinal(t *testing.T) {
+	es, rt := newSource(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Invalid API key"}`, http.StatusForbidden)
+	})
+	if _, err := es.FetchTopics(5, "install"); err == nil || !strings.Contains(err.Error(), "status 403") {
+		t.Errorf("FetchTopics = %v", err)
+	}
+	if len(rt.hosts) != 1 {
+		t.Errorf("tried %d hosts after a client error", len(rt.hosts))
+	}
+}
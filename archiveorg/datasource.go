@@ -0,0 +1,361 @@
+package archiveorg
+
+// Data Source Adapter for Internet Archive items (advanced search and metadata APIs)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultExcerpt    = 8000
+)
+
+type DataSourceArchiveOrg struct {
+	Client       *http.Client
+	BaseURL      string
+	UserAgent    string
+	MediaTypes   []string // e.g. "texts", "audio", "movies", "software"; empty means all
+	Collection   string   // Restrict to one collection, e.g. "gutenberg"
+	ExcerptBytes int      // How much OCR text to download for texts items
+
+	items topicid.Map[string]
+}
+
+// multi decodes metadata fields that are a string for single values and an array otherwise
+type multi []string
+
+func (m *multi) UnmarshalJSON(data []byte) error {
+	var many []string
+	if err := json.Unmarshal(data, &many); err == nil {
+		*m = many
+		return nil
+	}
+	var one string
+	if err := json.Unmarshal(data, &one); err != nil {
+		return err
+	}
+	*m = multi{one}
+	return nil
+}
+
+func (m multi) String() string {
+	return strings.Join(m, "; ")
+}
+
+func New() *DataSourceArchiveOrg {
+	return &DataSourceArchiveOrg{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:      "https://archive.org",
+		UserAgent:    "locus/archiveorg-datasource",
+		ExcerptBytes: defaultExcerpt,
+	}
+}
+
+// Init implements models.DataSource
+// The Internet Archive requires no initialization
+func (es *DataSourceArchiveOrg) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceArchiveOrg) CheckAvailability() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", "identifier:texts")
+	params.Set("rows", "1")
+	params.Set("output", "json")
+	return es.doJSON(ctx, "/advancedsearch.php?"+params.Encode(), &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceArchiveOrg) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "Internet Archive",
+		Homepage:    "https://archive.org",
+		Attribution: "Internet Archive",
+		TermsURL:    "https://archive.org/about/terms.php",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each item is a topic titled "Title — creator (date, mediatype)", most downloaded first
+func (es *DataSourceArchiveOrg) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Internet Archive DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	clauses := []string{"(" + query + ")"}
+	if len(es.MediaTypes) > 0 {
+		clauses = append(clauses, "mediatype:("+strings.Join(es.MediaTypes, " OR ")+")")
+	}
+	if es.Collection != "" {
+		clauses = append(clauses, "collection:("+es.Collection+")")
+	}
+	params := url.Values{}
+	params.Set("q", strings.Join(clauses, " AND "))
+	for _, field := range []string{"identifier", "title", "creator", "date", "mediatype"} {
+		params.Add("fl[]", field)
+	}
+	params.Set("sort[]", "downloads desc")
+	params.Set("rows", strconv.Itoa(count))
+	params.Set("output", "json")
+
+	var response struct {
+		Response struct {
+			Docs []struct {
+				Identifier string `json:"identifier"`
+				Title      multi  `json:"title"`
+				Creator    multi  `json:"creator"`
+				Date       string `json:"date"`
+				MediaType  string `json:"mediatype"`
+			} `json:"docs"`
+		} `json:"response"`
+	}
+	if err := es.doJSON(ctx, "/advancedsearch.php?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Response.Docs))
+	for _, doc := range response.Response.Docs {
+		title := doc.Title.String()
+		if title == "" {
+			title = doc.Identifier
+		}
+		if creator := doc.Creator.String(); creator != "" {
+			title += " — " + creator
+		}
+		details := []string{}
+		if doc.Date != "" {
+			details = append(details, strings.SplitN(doc.Date, "T", 2)[0])
+		}
+		if doc.MediaType != "" {
+			details = append(details, doc.MediaType)
+		}
+		if len(details) > 0 {
+			title += " (" + strings.Join(details, ", ") + ")"
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: es.itemLink(doc.Identifier),
+			Site:      doc.MediaType,
+			TopicID:   es.items.Put(doc.Identifier, doc.Identifier),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the item metadata and description, then for texts an excerpt of the OCR'd full text
+func (es *DataSourceArchiveOrg) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	identifier, ok := es.items.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Internet Archive topicID %d", topicID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var item struct {
+		Metadata struct {
+			Title       multi  `json:"title"`
+			Creator     multi  `json:"creator"`
+			Date        string `json:"date"`
+			MediaType   string `json:"mediatype"`
+			Description multi  `json:"description"`
+			Subject     multi  `json:"subject"`
+			Language    multi  `json:"language"`
+			Collection  multi  `json:"collection"`
+		} `json:"metadata"`
+		Files []struct {
+			Name   string `json:"name"`
+			Format string `json:"format"`
+		} `json:"files"`
+	}
+	if err := es.doJSON(ctx, "/metadata/"+url.PathEscape(identifier), &item); err != nil {
+		return nil, err
+	}
+
+	meta := item.Metadata
+	var b strings.Builder
+	b.WriteString(meta.Title.String())
+	for _, field := range []struct {
+		label string
+		value string
+	}{
+		{"Creator", meta.Creator.String()},
+		{"Date", meta.Date},
+		{"Media type", meta.MediaType},
+		{"Language", meta.Language.String()},
+		{"Subjects", meta.Subject.String()},
+		{"Collections", meta.Collection.String()},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(&b, "\n%s: %s", field.label, field.value)
+		}
+	}
+	var formats []string
+	seen := map[string]bool{}
+	ocrFile := ""
+	for _, file := range item.Files {
+		if file.Format == "DjVuTXT" && ocrFile == "" {
+			ocrFile = file.Name
+		}
+		if file.Format != "" && file.Format != "Metadata" && !seen[file.Format] {
+			seen[file.Format] = true
+			formats = append(formats, file.Format)
+		}
+	}
+	if len(formats) > 0 {
+		b.WriteString("\nFormats: " + strings.Join(formats, ", "))
+	}
+	if description := htmlToText(strings.Join(meta.Description, "\n")); description != "" {
+		b.WriteString("\n\n" + description)
+	}
+
+	results := []datasource.DataSourceData{{
+		DataText:  b.String(),
+		SourceURL: es.itemLink(identifier),
+		Site:      meta.MediaType,
+		AnswerID:  topicID,
+	}}
+	if count == 1 || ocrFile == "" {
+		return results, nil
+	}
+	// OCR text is missing or restricted for lending-library items; the metadata stands on its own
+	if excerpt, err := es.fetchExcerpt(ctx, identifier, ocrFile); err == nil && excerpt != "" {
+		results = append(results, datasource.DataSourceData{
+			DataText:  excerpt,
+			SourceURL: fmt.Sprintf("%s/stream/%s/%s", strings.TrimRight(es.BaseURL, "/"), url.PathEscape(identifier), url.PathEscape(ocrFile)),
+			Site:      meta.MediaType,
+			AnswerID:  topicid.Hash(identifier + "/" + ocrFile),
+		})
+	}
+	return results, nil
+}
+
+// fetchExcerpt downloads the start of an OCR text file with a range request
+func (es *DataSourceArchiveOrg) fetchExcerpt(ctx context.Context, identifier, file string) (string, error) {
+	limit := es.ExcerptBytes
+	if limit <= 0 {
+		limit = defaultExcerpt
+	}
+	uri := fmt.Sprintf("%s/download/%s/%s", strings.TrimRight(es.BaseURL, "/"), url.PathEscape(identifier), url.PathEscape(file))
+	body, err := es.get(ctx, uri, fmt.Sprintf("bytes=0-%d", limit-1))
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	// Servers that ignore the range still only get read up to the limit
+	raw, err := io.ReadAll(io.LimitReader(body, int64(limit)))
+	if err != nil {
+		return "", err
+	}
+	text := strings.ToValidUTF8(string(raw), "")
+	// Drop the partial word at the cut and collapse the OCR's hard line breaks into paragraphs
+	if i := strings.LastIndexAny(text, " \n"); i > 0 && len(raw) == limit {
+		text = text[:i]
+	}
+	var paragraphs []string
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if paragraph = strings.Join(strings.Fields(paragraph), " "); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n"), nil
+}
+
+func (es *DataSourceArchiveOrg) itemLink(identifier string) string {
+	return strings.TrimRight(es.BaseURL, "/") + "/details/" + url.PathEscape(identifier)
+}
+
+// doJSON performs an HTTP GET request against the archive and decodes the JSON response into target
+func (es *DataSourceArchiveOrg) doJSON(ctx context.Context, path string, target interface{}) error {
+	body, err := es.get(ctx, strings.TrimRight(es.BaseURL, "/")+path, "")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(target)
+}
+
+// get performs an HTTP GET request, optionally for a byte range. The caller must close the returned body.
+func (es *DataSourceArchiveOrg) get(ctx context.Context, uri, byteRange string) (io.ReadCloser, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("archive.org request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// Helpers
+
+// htmlToText flattens item descriptions, which uploaders often write in HTML
+func htmlToText(in string) string {
+	if !strings.Contains(in, "<") {
+		return strings.TrimSpace(in)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(in))
+	if err != nil {
+		return strings.TrimSpace(in)
+	}
+	doc.Find("br").ReplaceWithHtml("\n")
+	doc.Find("p, div, li").Each(func(_ int, s *goquery.Selection) {
+		s.AppendHtml("\n")
+	})
+	var lines []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,85 @@
+package archiveorg
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const ocr = "It was the best of times,\nit was the worst of times.\n\nIt was the age of wisdom."
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/advancedsearch.php", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "(dickens) AND mediatype:(texts OR audio) AND collection:(gutenberg)" || len(q["fl[]"]) != 5 || q.Get("sort[]") != "downloads desc" || q.Get("rows") != "5" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"response":{"docs":[
+			{"identifier":"taleoftwocities","title":"A Tale of Two Cities","creator":["Dickens, Charles","Browne, Hablot K."],"date":"1859-01-01T00:00:00Z","mediatype":"texts"},
+			{"identifier":"untitled-item","mediatype":"audio"}
+		]}}`)
+	})
+	mux.HandleFunc("/metadata/taleoftwocities", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"metadata":{"title":"A Tale of Two Cities","creator":"Dickens, Charles","date":"1859","mediatype":"texts","language":"eng","subject":["London","Paris"],"collection":"gutenberg","description":"<p>A novel.</p><p>Set in <b>London</b> and Paris.</p>"},
+			"files":[{"name":"tale.pdf","format":"Text PDF"},{"name":"tale_djvu.txt","format":"DjVuTXT"},{"name":"tale_meta.xml","format":"Metadata"}]}`)
+	})
+	mux.HandleFunc("/download/taleoftwocities/tale_djvu.txt", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "bytes=0-61" {
+			t.Errorf("Range = %q", r.Header.Get("Range"))
+		}
+		http.ServeContent(w, r, "tale_djvu.txt", time.Time{}, strings.NewReader(ocr))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.MediaTypes = []string{"texts", "audio"}
+	es.Collection = "gutenberg"
+	es.ExcerptBytes = 62
+
+	topics, err := es.FetchTopics(5, "dickens")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"A Tale of Two Cities — Dickens, Charles; Browne, Hablot K. (1859-01-01, texts)",
+		"untitled-item (audio)",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := []string{
+		"A Tale of Two Cities\nCreator: Dickens, Charles\nDate: 1859\nMedia type: texts\nLanguage: eng\nSubjects: London; Paris\nCollections: gutenberg\nFormats: Text PDF, DjVuTXT\n\nA novel.\nSet in London and Paris.",
+		"It was the best of times, it was the worst of times.\n\nIt was",
+	}
+	if len(data) != len(wantData) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range wantData {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+	if data[1].SourceURL != es.BaseURL+"/stream/taleoftwocities/tale_djvu.txt" {
+		t.Errorf("excerpt SourceURL = %q", data[1].SourceURL)
+	}
+}
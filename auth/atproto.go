@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AppPassword manages an AT Protocol (Bluesky) session created from an app password.
+// Sessions are created with com.atproto.server.createSession and renewed with
+// com.atproto.server.refreshSession once the access JWT expires.
+type AppPassword struct {
+	Client     *http.Client
+	Host       string // PDS root, e.g. https://bsky.social
+	Identifier string // Handle, DID or email of the account
+	Password   string // App password generated under Settings > App Passwords
+
+	cache Cache
+}
+
+// Token returns a valid access JWT for the account
+func (a *AppPassword) Token(ctx context.Context) (string, error) {
+	if a.cache.Fetch == nil {
+		a.cache.Fetch = a.createSession
+		a.cache.Refresh = a.refreshSession
+	}
+	return a.cache.Token(ctx)
+}
+
+// Invalidate forces the session to be refreshed on the next call
+func (a *AppPassword) Invalidate() {
+	a.cache.Invalidate()
+}
+
+func (a *AppPassword) createSession(ctx context.Context) (Token, error) {
+	if a.Identifier == "" || a.Password == "" {
+		return Token{}, fmt.Errorf("auth: identifier and app password are required")
+	}
+	body, err := json.Marshal(map[string]string{
+		"identifier": a.Identifier,
+		"password":   a.Password,
+	})
+	if err != nil {
+		return Token{}, err
+	}
+	return a.session(ctx, "com.atproto.server.createSession", bytes.NewReader(body), "")
+}
+
+func (a *AppPassword) refreshSession(ctx context.Context, current Token) (Token, error) {
+	return a.session(ctx, "com.atproto.server.refreshSession", nil, current.RefreshToken)
+}
+
+func (a *AppPassword) session(ctx context.Context, method string, body io.Reader, bearer string) (Token, error) {
+	client := a.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(a.Host, "/")+"/xrpc/"+method, body)
+	if err != nil {
+		return Token{}, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Token{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return Token{}, fmt.Errorf("auth: %s failed: status %d: %s", method, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	var session struct {
+		AccessJwt  string `json:"accessJwt"`
+		RefreshJwt string `json:"refreshJwt"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+		return Token{}, err
+	}
+	return Token{
+		AccessToken:  session.AccessJwt,
+		RefreshToken: session.RefreshJwt,
+		Expiry:       jwtExpiry(session.AccessJwt),
+	}, nil
+}
+
+// jwtExpiry reads the exp claim of a JWT without verifying it; the server remains the authority
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
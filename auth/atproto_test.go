@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func jwt(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return "header." + payload + ".signature"
+}
+
+func TestAppPassword(t *testing.T) {
+	expired := jwt(time.Now().Add(-time.Hour))
+	valid := jwt(time.Now().Add(time.Hour))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.server.createSession":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["identifier"] != "alice.bsky.social" || body["password"] != "app-pass" {
+				t.Errorf("createSession with %v", body)
+			}
+			fmt.Fprintf(w, `{"accessJwt":%q,"refreshJwt":"refresh"}`, expired)
+		case "/xrpc/com.atproto.server.refreshSession":
+			if r.Header.Get("Authorization") != "Bearer refresh" {
+				t.Errorf("refreshSession authorized with %q", r.Header.Get("Authorization"))
+			}
+			fmt.Fprintf(w, `{"accessJwt":%q,"refreshJwt":"refresh2"}`, valid)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	a := &AppPassword{Host: srv.URL, Identifier: "alice.bsky.social", Password: "app-pass"}
+	if token, err := a.Token(context.Background()); err != nil || token != expired {
+		t.Fatalf("Token = %q, %v", token, err)
+	}
+	if token, err := a.Token(context.Background()); err != nil || token != valid {
+		t.Errorf("Token after expiry = %q, %v, want the refreshed token", token, err)
+	}
+}
+
+func TestJWTExpiry(t *testing.T) {
+	exp := time.Unix(1700000000, 0)
+	if got := jwtExpiry(jwt(exp)); !got.Equal(exp) {
+		t.Errorf("jwtExpiry = %v, want %v", got, exp)
+	}
+	if got := jwtExpiry("not-a-jwt"); !got.IsZero() {
+		t.Errorf("jwtExpiry of a malformed token = %v", got)
+	}
+}
@@ -0,0 +1,72 @@
+// Package auth provides credential handling shared by adapters whose APIs
+// hand out short-lived session or bearer tokens instead of static keys.
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// expiryDelta refreshes tokens slightly early so in-flight requests never carry an expired one
+const expiryDelta = time.Minute
+
+// Token is a bearer credential with an optional refresh token and expiry
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	Expiry       time.Time // Zero means the token does not expire
+}
+
+// Valid reports whether the token is set and not about to expire
+func (t Token) Valid() bool {
+	if t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.Expiry)
+}
+
+// Cache hands out a cached token, calling Fetch for a new one when none is held
+// and Refresh (if set) when the held token has expired. It is safe for concurrent use.
+type Cache struct {
+	Fetch   func(ctx context.Context) (Token, error)
+	Refresh func(ctx context.Context, current Token) (Token, error)
+
+	mu    sync.Mutex
+	token Token
+}
+
+// Token returns a valid access token, fetching or refreshing one as needed
+func (c *Cache) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token.Valid() {
+		return c.token.AccessToken, nil
+	}
+	if c.Refresh != nil && c.token.RefreshToken != "" {
+		if token, err := c.Refresh(ctx, c.token); err == nil && token.AccessToken != "" {
+			c.token = token
+			return token.AccessToken, nil
+		}
+	}
+	if c.Fetch == nil {
+		return "", errors.New("auth: no token fetch function configured")
+	}
+	token, err := c.Fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("auth: token endpoint returned an empty token")
+	}
+	c.token = token
+	return token.AccessToken, nil
+}
+
+// Invalidate drops the access token so the next call refreshes it, e.g. after a 401 response
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	c.token.AccessToken = ""
+	c.mu.Unlock()
+}
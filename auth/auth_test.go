@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheRefreshesExpiredToken(t *testing.T) {
+	fetches, refreshes := 0, 0
+	c := Cache{
+		Fetch: func(ctx context.Context) (Token, error) {
+			fetches++
+			return Token{AccessToken: "first", RefreshToken: "refresh", Expiry: time.Now().Add(30 * time.Second)}, nil
+		},
+		Refresh: func(ctx context.Context, current Token) (Token, error) {
+			refreshes++
+			if current.RefreshToken != "refresh" {
+				t.Errorf("refreshed with %q", current.RefreshToken)
+			}
+			return Token{AccessToken: "second"}, nil
+		},
+	}
+	// The fetched token expires within expiryDelta, so the next call refreshes it
+	for _, want := range []string{"first", "second", "second"} {
+		got, err := c.Token(context.Background())
+		if err != nil || got != want {
+			t.Errorf("Token = %q, %v, want %q", got, err, want)
+		}
+	}
+	if fetches != 1 || refreshes != 1 {
+		t.Errorf("%d fetches and %d refreshes, want 1 of each", fetches, refreshes)
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	fetches := 0
+	c := Cache{Fetch: func(ctx context.Context) (Token, error) {
+		fetches++
+		return Token{AccessToken: "token"}, nil
+	}}
+	c.Token(context.Background())
+	c.Invalidate()
+	c.Token(context.Background())
+	if fetches != 2 {
+		t.Errorf("%d fetches, want a new token after Invalidate", fetches)
+	}
+}
+
+func TestCacheErrors(t *testing.T) {
+	var c Cache
+	if _, err := c.Token(context.Background()); err == nil {
+		t.Error("Token succeeded without a Fetch function")
+	}
+	c.Fetch = func(ctx context.Context) (Token, error) { return Token{}, nil }
+	if _, err := c.Token(context.Background()); err == nil {
+		t.Error("Token accepted an empty access token")
+	}
+	failed := errors.New("failed")
+	c.Fetch = func(ctx context.Context) (Token, error) { return Token{}, failed }
+	if _, err := c.Token(context.Background()); !errors.Is(err, failed) {
+		t.Errorf("Token = %v, want the fetch error", err)
+	}
+}
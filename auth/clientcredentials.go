@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ClientCredentials obtains application tokens with the OAuth 2.0 client credentials grant
+// (RFC 6749 section 4.4). There is no refresh token; a new token is requested once the old one expires.
+type ClientCredentials struct {
+	Client       *http.Client
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	// SendInBody sends the client ID and secret as form fields instead of HTTP Basic auth,
+	// which some providers (Twitch) require
+	SendInBody bool
+
+	cache Cache
+}
+
+// Token returns a valid application access token
+func (c *ClientCredentials) Token(ctx context.Context) (string, error) {
+	if c.cache.Fetch == nil {
+		c.cache.Fetch = c.fetch
+	}
+	return c.cache.Token(ctx)
+}
+
+// Invalidate forces a new token to be requested on the next call, e.g. after a 401 response
+func (c *ClientCredentials) Invalidate() {
+	c.cache.Invalidate()
+}
+
+func (c *ClientCredentials) fetch(ctx context.Context) (Token, error) {
+	if c.ClientID == "" || c.ClientSecret == "" {
+		return Token{}, fmt.Errorf("auth: client ID and secret are required")
+	}
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	if c.SendInBody {
+		form.Set("client_id", c.ClientID)
+		form.Set("client_secret", c.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if !c.SendInBody {
+		req.SetBasicAuth(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Token{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return Token{}, fmt.Errorf("auth: token request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return Token{}, err
+	}
+	token := Token{AccessToken: response.AccessToken}
+	if response.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
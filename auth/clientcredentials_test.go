@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestClientCredentials(t *testing.T) {
+	for _, inBody := range []bool{false, true} {
+		requests := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			r.ParseForm()
+			if r.Method != http.MethodPost || r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != "read write" {
+				t.Errorf("token request %s %v", r.Method, r.PostForm)
+			}
+			id, secret, basic := r.BasicAuth()
+			// RFC 6749 section 2.3.1 form-encodes the credentials before Basic encoding
+			id, _ = url.QueryUnescape(id)
+			secret, _ = url.QueryUnescape(secret)
+			if inBody {
+				id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+			}
+			if basic == inBody || id != "id" || secret != "s%cret" {
+				t.Errorf("credentials %q, %q, basic auth %v", id, secret, basic)
+			}
+			fmt.Fprintf(w, `{"access_token":"token%d","expires_in":3600}`, requests)
+		}))
+
+		c := &ClientCredentials{TokenURL: srv.URL, ClientID: "id", ClientSecret: "s%cret", Scopes: []string{"read", "write"}, SendInBody: inBody}
+		for _, want := range []string{"token1", "token1"} {
+			if got, err := c.Token(context.Background()); err != nil || got != want {
+				t.Errorf("Token = %q, %v, want %q", got, err, want)
+			}
+		}
+		c.Invalidate()
+		if got, err := c.Token(context.Background()); err != nil || got != "token2" {
+			t.Errorf("Token after Invalidate = %q, %v", got, err)
+		}
+		srv.Close()
+	}
+}
+
+func TestClientCredentialsRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	c := &ClientCredentials{TokenURL: srv.URL, ClientID: "id", ClientSecret: "wrong"}
+	if _, err := c.Token(context.Background()); err == nil {
+		t.Error("Token succeeded with rejected credentials")
+	}
+}
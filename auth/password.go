@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PasswordGrant obtains user tokens with the OAuth 2.0 resource owner password credentials grant
+// (RFC 6749 section 4.3), as self-hosted apps such as Wallabag use, and renews them with the refresh
+// token grant.
+type PasswordGrant struct {
+	Client       *http.Client
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Username     string
+	Password     string
+
+	cache Cache
+}
+
+// Token returns a valid access token for the user
+func (p *PasswordGrant) Token(ctx context.Context) (string, error) {
+	if p.cache.Fetch == nil {
+		p.cache.Fetch = p.fetch
+		p.cache.Refresh = p.refresh
+	}
+	return p.cache.Token(ctx)
+}
+
+// Invalidate forces the token to be refreshed on the next call, e.g. after a 401 response
+func (p *PasswordGrant) Invalidate() {
+	p.cache.Invalidate()
+}
+
+func (p *PasswordGrant) fetch(ctx context.Context) (Token, error) {
+	if p.Username == "" || p.Password == "" {
+		return Token{}, fmt.Errorf("auth: username and password are required")
+	}
+	form := url.Values{}
+	form.Set("grant_type", "password")
+	form.Set("username", p.Username)
+	form.Set("password", p.Password)
+	return p.request(ctx, form)
+}
+
+func (p *PasswordGrant) refresh(ctx context.Context, current Token) (Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", current.RefreshToken)
+	return p.request(ctx, form)
+}
+
+func (p *PasswordGrant) request(ctx context.Context, form url.Values) (Token, error) {
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	form.Set("client_id", p.ClientID)
+	form.Set("client_secret", p.ClientSecret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Token{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return Token{}, fmt.Errorf("auth: token request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var response struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return Token{}, err
+	}
+	token := Token{AccessToken: response.AccessToken, RefreshToken: response.RefreshToken}
+	if response.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPasswordGrant(t *testing.T) {
+	var grants []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form := r.PostForm
+		grants = append(grants, form.Get("grant_type"))
+		if form.Get("client_id") != "id" || form.Get("client_secret") != "secret" {
+			t.Errorf("client credentials %v", form)
+		}
+		switch form.Get("grant_type") {
+		case "password":
+			if form.Get("username") != "alice" || form.Get("password") != "pa&ss" {
+				t.Errorf("user credentials %v", form)
+			}
+			// Expires within the refresh margin, so the next call refreshes it
+			fmt.Fprint(w, `{"access_token":"first","refresh_token":"refresh1","expires_in":30}`)
+		case "refresh_token":
+			if form.Get("refresh_token") != "refresh1" {
+				t.Errorf("refresh token %q", form.Get("refresh_token"))
+			}
+			fmt.Fprint(w, `{"access_token":"second","refresh_token":"refresh2","expires_in":3600}`)
+		}
+	}))
+	defer srv.Close()
+
+	p := &PasswordGrant{TokenURL: srv.URL, ClientID: "id", ClientSecret: "secret", Username: "alice", Password: "pa&ss"}
+	for _, want := range []string{"first", "second", "second"} {
+		if got, err := p.Token(context.Background()); err != nil || got != want {
+			t.Errorf("Token = %q, %v, want %q", got, err, want)
+		}
+	}
+	if fmt.Sprint(grants) != "[password refresh_token]" {
+		t.Errorf("grants %v", grants)
+	}
+}
+
+func TestPasswordGrantRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	p := &PasswordGrant{TokenURL: srv.URL, ClientID: "id", ClientSecret: "secret", Username: "alice", Password: "wrong"}
+	if _, err := p.Token(context.Background()); err == nil {
+		t.Error("Token succeeded with rejected credentials")
+	}
+	p = &PasswordGrant{TokenURL: srv.URL, ClientID: "id", ClientSecret: "secret"}
+	if _, err := p.Token(context.Background()); err == nil {
+		t.Error("Token succeeded without a username")
+	}
+}
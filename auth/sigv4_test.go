@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSigV4(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://bucket.s3.eu-west-1.amazonaws.com/docs/a b+c.md?prefix=docs/&list-type=2", nil)
+	req.Header.Set("User-Agent", "test")
+	s := SigV4{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session", Region: "eu-west-1"}
+	if err := s.Sign(req, EmptyPayloadHash); err != nil {
+		t.Fatal(err)
+	}
+
+	if req.URL.RawPath != "/docs/a%20b%2Bc.md" || req.URL.RawQuery != "list-type=2&prefix=docs%2F" {
+		t.Errorf("canonical URL %s ? %s", req.URL.RawPath, req.URL.RawQuery)
+	}
+	if req.Header.Get("X-Amz-Security-Token") != "session" || req.Header.Get("X-Amz-Content-Sha256") != EmptyPayloadHash {
+		t.Errorf("headers %v", req.Header)
+	}
+
+	// Recompute the signature from the canonical request as the AWS documentation spells it out
+	amzDate := req.Header.Get("X-Amz-Date")
+	day := amzDate[:8]
+	canonical := "GET\n/docs/a%20b%2Bc.md\nlist-type=2&prefix=docs%2F\n" +
+		"host:bucket.s3.eu-west-1.amazonaws.com\n" +
+		"x-amz-content-sha256:" + EmptyPayloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n" +
+		"x-amz-security-token:session\n\n" +
+		"host;x-amz-content-sha256;x-amz-date;x-amz-security-token\n" + EmptyPayloadHash
+	hash := sha256.Sum256([]byte(canonical))
+	scope := day + "/eu-west-1/s3/aws4_request"
+	key := []byte("AWS4secret")
+	for _, part := range []string{day, "eu-west-1", "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])))
+	want := "AWS4-HMAC-SHA256 Credential=AKID/" + scope +
+		", SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token, Signature=" + hex.EncodeToString(mac.Sum(nil))
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %s\nwant %s", got, want)
+	}
+	if !strings.HasSuffix(req.URL.String(), "/docs/a%20b%2Bc.md?list-type=2&prefix=docs%2F") {
+		t.Errorf("sent URL %s differs from the signed one", req.URL)
+	}
+}
+
+func TestSigV4RequiresCredentials(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://bucket.s3.amazonaws.com/", nil)
+	if err := (SigV4{AccessKeyID: "AKID"}).Sign(req, EmptyPayloadHash); err == nil {
+		t.Error("Sign succeeded without a secret")
+	}
+}
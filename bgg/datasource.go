@@ -0,0 +1,416 @@
+package bgg
+
+// Data Source Adapter for BoardGameGeek (XML API2)
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultTopicCount = 5
+	// The thing endpoint accepts at most 20 IDs per request
+	thingBatch = 20
+)
+
+type DataSourceBGG struct {
+	Client     *http.Client
+	BaseURL    string
+	Token      string // Application token; BGG requires one for XML API2 since 2025
+	UserAgent  string
+	Expansions bool // Include expansions in search results
+
+	rateLimiter *rate.Limiter
+	things      topicid.Map[thing]
+}
+
+type value struct {
+	Value string `xml:"value,attr"`
+}
+
+type thing struct {
+	ID    int64  `xml:"id,attr"`
+	Type  string `xml:"type,attr"`
+	Names []struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"name"`
+	Description   string `xml:"description"`
+	YearPublished value  `xml:"yearpublished"`
+	MinPlayers    value  `xml:"minplayers"`
+	MaxPlayers    value  `xml:"maxplayers"`
+	PlayingTime   value  `xml:"playingtime"`
+	MinAge        value  `xml:"minage"`
+	Links         []struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:"value,attr"`
+	} `xml:"link"`
+	Polls []struct {
+		Name    string `xml:"name,attr"`
+		Results []struct {
+			NumPlayers string `xml:"numplayers,attr"`
+			Result     []struct {
+				Value    string `xml:"value,attr"`
+				NumVotes int    `xml:"numvotes,attr"`
+			} `xml:"result"`
+		} `xml:"results"`
+	} `xml:"poll"`
+	Ratings struct {
+		UsersRated    value `xml:"usersrated"`
+		Average       value `xml:"average"`
+		BayesAverage  value `xml:"bayesaverage"`
+		AverageWeight value `xml:"averageweight"`
+		NumWeights    value `xml:"numweights"`
+		Ranks         []struct {
+			FriendlyName string `xml:"friendlyname,attr"`
+			Value        string `xml:"value,attr"`
+		} `xml:"ranks>rank"`
+	} `xml:"statistics>ratings"`
+}
+
+func New() *DataSourceBGG {
+	return &DataSourceBGG{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://boardgamegeek.com/xmlapi2",
+		UserAgent: "locus/bgg-datasource",
+	}
+}
+
+// Init implements models.DataSource
+// BGG throttles aggressively, so requests are limited to one every two seconds
+func (es *DataSourceBGG) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.rateLimiter == nil {
+		es.rateLimiter = rate.NewLimiter(rate.Every(2*time.Second), 1)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceBGG) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("id", "13")
+	return es.doXML(ctx, "thing", params, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceBGG) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "BoardGameGeek",
+		Homepage:            "https://boardgamegeek.com",
+		Attribution:         "Powered by BoardGameGeek",
+		AttributionRequired: true,
+		TermsURL:            "https://boardgamegeek.com/xmlapi/termsofuse",
+	}
+}
+
+// Describe implements source.Describer
+func (es *DataSourceBGG) Describe() source.Capabilities {
+	return source.Capabilities{Name: es.Info().Name, Data: true, RateLimit: source.RateLimit{Requests: 1, Per: 2 * time.Second}}
+}
+
+// FetchTopics implements models.DataSource
+// Matches are ordered by number of ratings, since BGG search itself is unranked; topics read
+// "CATAN (1995; 3–4 players; rated 7.09 by 120000)"
+func (es *DataSourceBGG) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for BGG DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("type", "boardgame")
+	if es.Expansions {
+		params.Set("type", "boardgame,boardgameexpansion")
+	}
+	var search struct {
+		Items []struct {
+			ID   int64  `xml:"id,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"item"`
+	}
+	if err := es.doXML(ctx, "search", params, &search); err != nil {
+		return nil, err
+	}
+
+	// Searching type=boardgame also returns expansions, once per matching name
+	seen := map[int64]bool{}
+	var ids []string
+	for _, item := range search.Items {
+		if seen[item.ID] || (item.Type == "boardgameexpansion" && !es.Expansions) {
+			continue
+		}
+		seen[item.ID] = true
+		ids = append(ids, strconv.FormatInt(item.ID, 10))
+		if len(ids) >= thingBatch {
+			break
+		}
+	}
+	if len(ids) == 0 {
+		return []datasource.DataSourceTopic{}, nil
+	}
+
+	params = url.Values{}
+	params.Set("id", strings.Join(ids, ","))
+	params.Set("stats", "1")
+	var things struct {
+		Items []thing `xml:"item"`
+	}
+	if err := es.doXML(ctx, "thing", params, &things); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(things.Items, func(i, j int) bool {
+		return atoi(things.Items[i].Ratings.UsersRated.Value) > atoi(things.Items[j].Ratings.UsersRated.Value)
+	})
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, t := range things.Items {
+		if len(results) >= count {
+			break
+		}
+		if t.Type == "boardgameexpansion" && !es.Expansions {
+			continue
+		}
+		var details []string
+		if t.YearPublished.Value != "" && t.YearPublished.Value != "0" {
+			details = append(details, t.YearPublished.Value)
+		}
+		if players := playerRange(t); players != "" {
+			details = append(details, players+" players")
+		}
+		if rated := atoi(t.Ratings.UsersRated.Value); rated > 0 {
+			details = append(details, fmt.Sprintf("rated %s by %d", formatScore(t.Ratings.Average.Value), rated))
+		}
+		title := primaryName(t)
+		if len(details) > 0 {
+			title += " (" + strings.Join(details, "; ") + ")"
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: thingLink(t),
+			Site:      "boardgamegeek.com",
+			TopicID:   es.things.Put(strconv.FormatInt(t.ID, 10), t),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the game description followed by its ratings, weight, rank, player counts (with the
+// community's best and recommended counts), playing time, designers, categories and mechanics
+func (es *DataSourceBGG) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	t, ok := es.things.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown BGG topicID %d", topicID)
+	}
+
+	var b strings.Builder
+	b.WriteString(primaryName(t))
+	if t.YearPublished.Value != "" && t.YearPublished.Value != "0" {
+		b.WriteString(" (" + t.YearPublished.Value + ")")
+	}
+	if description := strings.TrimSpace(t.Description); description != "" {
+		b.WriteString("\n\n" + description)
+	}
+	b.WriteString("\n")
+
+	r := t.Ratings
+	if rated := atoi(r.UsersRated.Value); rated > 0 {
+		fmt.Fprintf(&b, "\nAverage rating: %s/10 from %d ratings (Geek rating %s)",
+			formatScore(r.Average.Value), rated, formatScore(r.BayesAverage.Value))
+	}
+	if atoi(r.NumWeights.Value) > 0 {
+		fmt.Fprintf(&b, "\nWeight: %s/5", formatScore(r.AverageWeight.Value))
+	}
+	for _, rank := range r.Ranks {
+		// Unranked games report "Not Ranked"
+		if _, err := strconv.Atoi(rank.Value); err == nil {
+			fmt.Fprintf(&b, "\n%s: #%s", rank.FriendlyName, rank.Value)
+		}
+	}
+	if players := playerRange(t); players != "" {
+		b.WriteString("\nPlayers: " + players)
+	}
+	best, recommended := suggestedPlayers(t)
+	if best != "" {
+		b.WriteString("\nBest with: " + best)
+	}
+	if recommended != "" {
+		b.WriteString("\nRecommended with: " + recommended)
+	}
+	if minutes := atoi(t.PlayingTime.Value); minutes > 0 {
+		fmt.Fprintf(&b, "\nPlaying time: %d min", minutes)
+	}
+	if age := atoi(t.MinAge.Value); age > 0 {
+		fmt.Fprintf(&b, "\nAge: %d+", age)
+	}
+	for _, link := range []struct {
+		label string
+		kind  string
+	}{
+		{"Designers", "boardgamedesigner"},
+		{"Publishers", "boardgamepublisher"},
+		{"Categories", "boardgamecategory"},
+		{"Mechanics", "boardgamemechanic"},
+	} {
+		if values := linkValues(t, link.kind); values != "" {
+			b.WriteString("\n" + link.label + ": " + values)
+		}
+	}
+
+	return []datasource.DataSourceData{{
+		DataText:  strings.TrimSpace(b.String()),
+		SourceURL: thingLink(t),
+		Site:      "boardgamegeek.com",
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// doXML waits for the rate limiter, performs an XML API2 request and decodes the response into target
+func (es *DataSourceBGG) doXML(ctx context.Context, endpoint string, params url.Values, target interface{}) error {
+	if es.rateLimiter != nil {
+		if err := es.rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + "/" + endpoint + "?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	if es.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+es.Token)
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("bgg request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return xml.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func primaryName(t thing) string {
+	for _, name := range t.Names {
+		if name.Type == "primary" {
+			return name.Value
+		}
+	}
+	if len(t.Names) > 0 {
+		return t.Names[0].Value
+	}
+	return strconv.FormatInt(t.ID, 10)
+}
+
+func playerRange(t thing) string {
+	minPlayers, maxPlayers := atoi(t.MinPlayers.Value), atoi(t.MaxPlayers.Value)
+	switch {
+	case minPlayers <= 0:
+		return ""
+	case maxPlayers <= minPlayers:
+		return strconv.Itoa(minPlayers)
+	}
+	return fmt.Sprintf("%d–%d", minPlayers, maxPlayers)
+}
+
+// suggestedPlayers reads the "suggested_numplayers" poll. A count is best when "Best" outvotes the
+// other answers, and recommended when "Best" and "Recommended" together outvote "Not Recommended".
+func suggestedPlayers(t thing) (string, string) {
+	var best, recommended []string
+	for _, poll := range t.Polls {
+		if poll.Name != "suggested_numplayers" {
+			continue
+		}
+		for _, results := range poll.Results {
+			votes := map[string]int{}
+			for _, result := range results.Result {
+				votes[result.Value] = result.NumVotes
+			}
+			yes, maybe, no := votes["Best"], votes["Recommended"], votes["Not Recommended"]
+			if yes+maybe+no == 0 {
+				continue
+			}
+			if yes > maybe && yes > no {
+				best = append(best, results.NumPlayers)
+			}
+			if yes+maybe > no {
+				recommended = append(recommended, results.NumPlayers)
+			}
+		}
+	}
+	return strings.Join(best, ", "), strings.Join(recommended, ", ")
+}
+
+func linkValues(t thing, kind string) string {
+	var values []string
+	for _, link := range t.Links {
+		if link.Type == kind {
+			values = append(values, link.Value)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+func formatScore(s string) string {
+	score, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	return strconv.FormatFloat(score, 'f', 2, 64)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func thingLink(t thing) string {
+	return fmt.Sprintf("https://boardgamegeek.com/%s/%d", t.Type, t.ID)
+}
@@ -0,0 +1,94 @@
+package bgg
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("query") != "catan" || q.Get("type") != "boardgame" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, `<items total="4">
+			<item type="boardgame" id="278"><name type="primary" value="Catan Card Game"/></item>
+			<item type="boardgame" id="13"><name type="primary" value="CATAN"/></item>
+			<item type="boardgame" id="13"><name type="alternate" value="Settlers of Catan"/></item>
+			<item type="boardgameexpansion" id="926"><name type="primary" value="CATAN: Seafarers"/></item>
+		</items>`)
+	})
+	mux.HandleFunc("/thing", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("id") != "278,13" || q.Get("stats") != "1" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `<items>
+			<item type="boardgame" id="278"><name type="primary" value="Catan Card Game"/><yearpublished value="1996"/>
+				<minplayers value="2"/><maxplayers value="2"/>
+				<statistics><ratings><usersrated value="5000"/><average value="6.5"/></ratings></statistics></item>
+			<item type="boardgame" id="13"><name type="alternate" value="Settlers of Catan"/><name type="primary" value="CATAN"/>
+				<description>Trade, build and settle.</description><yearpublished value="1995"/>
+				<minplayers value="3"/><maxplayers value="4"/><playingtime value="120"/><minage value="10"/>
+				<poll name="suggested_numplayers">
+					<results numplayers="3"><result value="Best" numvotes="50"/><result value="Recommended" numvotes="100"/><result value="Not Recommended" numvotes="10"/></results>
+					<results numplayers="4"><result value="Best" numvotes="300"/><result value="Recommended" numvotes="40"/><result value="Not Recommended" numvotes="5"/></results>
+				</poll>
+				<link type="boardgamedesigner" value="Klaus Teuber"/><link type="boardgamemechanic" value="Dice Rolling"/><link type="boardgamemechanic" value="Trading"/>
+				<statistics><ratings><usersrated value="120000"/><average value="7.0912"/><bayesaverage value="6.9"/>
+					<averageweight value="2.29"/><numweights value="8000"/>
+					<ranks><rank friendlyname="Board Game Rank" value="500"/><rank friendlyname="Family Game Rank" value="Not Ranked"/></ranks>
+				</ratings></statistics></item>
+		</items>`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.Token = "token"
+	es.rateLimiter = rate.NewLimiter(rate.Inf, 1)
+
+	topics, err := es.FetchTopics(5, "catan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"CATAN (1995; 3–4 players; rated 7.09 by 120000)",
+		"Catan Card Game (1996; 2 players; rated 6.50 by 5000)",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+	if topics[0].SourceURL != "https://boardgamegeek.com/boardgame/13" {
+		t.Errorf("SourceURL = %q", topics[0].SourceURL)
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "CATAN (1995)\n\nTrade, build and settle.\n\nAverage rating: 7.09/10 from 120000 ratings (Geek rating 6.90)\nWeight: 2.29/5\n" +
+		"Board Game Rank: #500\nPlayers: 3–4\nBest with: 4\nRecommended with: 3, 4\nPlaying time: 120 min\nAge: 10+\n" +
+		"Designers: Klaus Teuber\nMechanics: Dice Rolling, Trading"
+	if len(data) != 1 || data[0].DataText != text {
+		t.Errorf("FetchData = %+v, want %q", data, text)
+	}
+}
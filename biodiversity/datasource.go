@@ -0,0 +1,352 @@
+package biodiversity
+
+// Data Source Adapter for species and taxa from iNaturalist, with GBIF occurrence counts
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/time/rate"
+)
+
+const defaultTopicCount = 5
+
+// taxonomyRanks are the ancestor ranks listed in the classification line
+var taxonomyRanks = map[string]bool{
+	"kingdom": true, "phylum": true, "class": true, "order": true, "family": true, "genus": true,
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]+>`)
+
+type DataSourceBiodiversity struct {
+	Client    *http.Client
+	BaseURL   string // iNaturalist API
+	GBIFURL   string // GBIF API; empty to skip occurrence counts
+	UserAgent string
+	Rank      string // Restrict search to one rank, e.g. "species"
+	Locale    string // Language for common names, e.g. "en"
+
+	rateLimiter *rate.Limiter
+	taxa        topicid.Map[taxon]
+}
+
+type taxon struct {
+	ID                  int64  `json:"id"`
+	Name                string `json:"name"`
+	Rank                string `json:"rank"`
+	PreferredCommonName string `json:"preferred_common_name"`
+	ObservationsCount   int64  `json:"observations_count"`
+	IconicTaxonName     string `json:"iconic_taxon_name"`
+	WikipediaURL        string `json:"wikipedia_url"`
+	Extinct             bool   `json:"extinct"`
+	ConservationStatus  *struct {
+		StatusName string `json:"status_name"`
+		Authority  string `json:"authority"`
+	} `json:"conservation_status"`
+}
+
+func New() *DataSourceBiodiversity {
+	return &DataSourceBiodiversity{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://api.inaturalist.org/v1",
+		GBIFURL:   "https://api.gbif.org/v1",
+		UserAgent: "locus/biodiversity-datasource",
+		Locale:    "en",
+	}
+}
+
+// Init implements models.DataSource
+// iNaturalist asks API clients to stay around one request per second
+func (es *DataSourceBiodiversity) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.rateLimiter == nil {
+		es.rateLimiter = rate.NewLimiter(rate.Limit(1), 2)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceBiodiversity) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", "quercus")
+	params.Set("per_page", "1")
+	return es.doJSON(ctx, es.BaseURL+"/taxa", params, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceBiodiversity) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "iNaturalist",
+		Homepage:            "https://www.inaturalist.org",
+		Attribution:         "Observations from iNaturalist (https://www.inaturalist.org)",
+		AttributionRequired: true,
+		TermsURL:            "https://www.inaturalist.org/pages/terms",
+	}
+}
+
+// Describe implements source.Describer
+func (es *DataSourceBiodiversity) Describe() source.Capabilities {
+	return source.Capabilities{Name: es.Info().Name, Data: true, RateLimit: source.RateLimit{Requests: 1, Per: time.Second}}
+}
+
+// FetchTopics implements models.DataSource
+// Topics read "Snow Leopard (Panthera uncia, species; Endangered)"
+func (es *DataSourceBiodiversity) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Biodiversity DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("per_page", strconv.Itoa(count))
+	if es.Rank != "" {
+		params.Set("rank", es.Rank)
+	}
+	if es.Locale != "" {
+		params.Set("locale", es.Locale)
+	}
+	var response struct {
+		Results []taxon `json:"results"`
+	}
+	if err := es.doJSON(ctx, es.BaseURL+"/taxa", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Results))
+	for _, t := range response.Results {
+		details := t.Name + ", " + t.Rank
+		if t.Extinct {
+			details += "; extinct"
+		} else if t.ConservationStatus != nil && t.ConservationStatus.StatusName != "" {
+			details += "; " + titleCase(t.ConservationStatus.StatusName)
+		}
+		title := t.Name
+		if t.PreferredCommonName != "" {
+			title = titleCase(t.PreferredCommonName)
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s (%s)", title, details),
+			SourceURL: taxonLink(t.ID),
+			Site:      t.IconicTaxonName,
+			TopicID:   es.taxa.Put(strconv.FormatInt(t.ID, 10), t),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the Wikipedia-derived description, then a profile with the classification, conservation
+// statuses, iNaturalist observation count and GBIF occurrence record count
+func (es *DataSourceBiodiversity) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	t, ok := es.taxa.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Biodiversity topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	params := url.Values{}
+	if es.Locale != "" {
+		params.Set("locale", es.Locale)
+	}
+	var response struct {
+		Results []struct {
+			taxon
+			WikipediaSummary string `json:"wikipedia_summary"`
+			Ancestors        []struct {
+				Name string `json:"name"`
+				Rank string `json:"rank"`
+			} `json:"ancestors"`
+			ConservationStatuses []struct {
+				StatusName string `json:"status_name"`
+				Authority  string `json:"authority"`
+				Place      *struct {
+					DisplayName string `json:"display_name"`
+				} `json:"place"`
+			} `json:"conservation_statuses"`
+		} `json:"results"`
+	}
+	if err := es.doJSON(ctx, fmt.Sprintf("%s/taxa/%d", es.BaseURL, t.ID), params, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Results) == 0 {
+		return nil, fmt.Errorf("iNaturalist taxon %d not found", t.ID)
+	}
+	details := response.Results[0]
+
+	name := details.Name
+	if details.PreferredCommonName != "" {
+		name = fmt.Sprintf("%s (%s)", titleCase(details.PreferredCommonName), details.Name)
+	}
+	var results []datasource.DataSourceData
+	if summary := strings.TrimSpace(htmlTag.ReplaceAllString(details.WikipediaSummary, "")); summary != "" {
+		link := details.WikipediaURL
+		if link == "" {
+			link = taxonLink(t.ID)
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  name + "\n\n" + summary,
+			SourceURL: link,
+			Site:      "wikipedia",
+			AnswerID:  topicID,
+		})
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s, %s", name, details.Rank)
+	var classification []string
+	for _, ancestor := range details.Ancestors {
+		if taxonomyRanks[ancestor.Rank] {
+			classification = append(classification, fmt.Sprintf("%s %s", titleCase(ancestor.Rank), ancestor.Name))
+		}
+	}
+	if len(classification) > 0 {
+		b.WriteString("\nClassification: " + strings.Join(classification, " › "))
+	}
+	if details.Extinct {
+		b.WriteString("\nExtinct")
+	}
+	for _, status := range details.ConservationStatuses {
+		place := "Global"
+		if status.Place != nil && status.Place.DisplayName != "" {
+			place = status.Place.DisplayName
+		}
+		fmt.Fprintf(&b, "\nConservation status (%s): %s", place, titleCase(status.StatusName))
+		if status.Authority != "" {
+			b.WriteString(", " + status.Authority)
+		}
+	}
+	fmt.Fprintf(&b, "\niNaturalist observations: %d", details.ObservationsCount)
+	if es.GBIFURL != "" {
+		// GBIF is a supplement; the taxon profile stands without it
+		if occurrences, err := es.gbifOccurrences(ctx, details.Name); err == nil && occurrences >= 0 {
+			fmt.Fprintf(&b, "\nGBIF occurrence records: %d", occurrences)
+		}
+	}
+	results = append(results, datasource.DataSourceData{
+		DataText:  b.String(),
+		SourceURL: taxonLink(t.ID),
+		Site:      "inaturalist.org",
+		AnswerID:  topicid.Hash(fmt.Sprintf("inat/%d#profile", t.ID)),
+	})
+	if count > 0 && len(results) > count {
+		results = results[:count]
+	}
+	return results, nil
+}
+
+// gbifOccurrences matches a scientific name against the GBIF backbone and counts its occurrence
+// records; it returns -1 when the name has no confident match
+func (es *DataSourceBiodiversity) gbifOccurrences(ctx context.Context, name string) (int64, error) {
+	params := url.Values{}
+	params.Set("name", name)
+	var match struct {
+		UsageKey  int64  `json:"usageKey"`
+		MatchType string `json:"matchType"`
+	}
+	if err := es.doJSON(ctx, strings.TrimRight(es.GBIFURL, "/")+"/species/match", params, &match); err != nil {
+		return 0, err
+	}
+	if match.UsageKey == 0 || match.MatchType == "NONE" || match.MatchType == "HIGHERRANK" {
+		return -1, nil
+	}
+
+	params = url.Values{}
+	params.Set("taxonKey", strconv.FormatInt(match.UsageKey, 10))
+	params.Set("limit", "0")
+	var occurrences struct {
+		Count int64 `json:"count"`
+	}
+	if err := es.doJSON(ctx, strings.TrimRight(es.GBIFURL, "/")+"/occurrence/search", params, &occurrences); err != nil {
+		return 0, err
+	}
+	return occurrences.Count, nil
+}
+
+// doJSON waits for the rate limiter, performs an HTTP GET and decodes the JSON response into target
+func (es *DataSourceBiodiversity) doJSON(ctx context.Context, endpoint string, params url.Values, target interface{}) error {
+	if es.rateLimiter != nil {
+		if err := es.rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := endpoint
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("biodiversity request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(r)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+func taxonLink(id int64) string {
+	return fmt.Sprintf("https://www.inaturalist.org/taxa/%d", id)
+}
@@ -0,0 +1,95 @@
+package biodiversity
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/inat/taxa", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "snow leopard" || q.Get("per_page") != "5" || q.Get("rank") != "species" || q.Get("locale") != "en" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"results":[
+			{"id":41970,"name":"Panthera uncia","rank":"species","preferred_common_name":"snow leopard","iconic_taxon_name":"Mammalia","conservation_status":{"status_name":"vulnerable"}},
+			{"id":1,"name":"Panthera spelaea","rank":"species","extinct":true}
+		]}`)
+	})
+	mux.HandleFunc("/inat/taxa/41970", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"results":[{"id":41970,"name":"Panthera uncia","rank":"species","preferred_common_name":"snow leopard","observations_count":1200,
+			"wikipedia_url":"https://en.wikipedia.org/wiki/Snow_leopard","wikipedia_summary":"The <b>snow leopard</b> is a big cat.",
+			"ancestors":[{"name":"Animalia","rank":"kingdom"},{"name":"Carnivora","rank":"order"},{"name":"Pantherinae","rank":"subfamily"},{"name":"Panthera","rank":"genus"}],
+			"conservation_statuses":[{"status_name":"vulnerable","authority":"IUCN Red List"},{"status_name":"endangered","place":{"display_name":"Nepal"}}]}]}`)
+	})
+	mux.HandleFunc("/gbif/species/match", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") != "Panthera uncia" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"usageKey":5219416,"matchType":"EXACT"}`)
+	})
+	mux.HandleFunc("/gbif/occurrence/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("taxonKey") != "5219416" || q.Get("limit") != "0" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"count":4321}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL + "/inat"
+	es.GBIFURL = srv.URL + "/gbif"
+	es.Rank = "species"
+	es.rateLimiter = rate.NewLimiter(rate.Inf, 1)
+
+	topics, err := es.FetchTopics(5, "snow leopard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Snow Leopard (Panthera uncia, species; Vulnerable)",
+		"Panthera spelaea (Panthera spelaea, species; extinct)",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := []string{
+		"Snow Leopard (Panthera uncia)\n\nThe snow leopard is a big cat.",
+		"Snow Leopard (Panthera uncia), species\nClassification: Kingdom Animalia › Order Carnivora › Genus Panthera\n" +
+			"Conservation status (Global): Vulnerable, IUCN Red List\nConservation status (Nepal): Endangered\n" +
+			"iNaturalist observations: 1200\nGBIF occurrence records: 4321",
+	}
+	if len(data) != len(wantData) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range wantData {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+	if data[0].SourceURL != "https://en.wikipedia.org/wiki/Snow_leopard" {
+		t.Errorf("summary SourceURL = %q", data[0].SourceURL)
+	}
+}
@@ -0,0 +1,275 @@
+package biorxiv
+
+// Data Source Adapter for the bioRxiv / medRxiv preprint APIs
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	pageSize          = 100 // Fixed by the API
+)
+
+type DataSourceBioRxiv struct {
+	Client    *http.Client
+	BaseURL   string
+	UserAgent string
+	Server    string    // "biorxiv" or "medrxiv"
+	Category  string    // Optional subject category, e.g. "neuroscience" or "epidemiology"
+	From      time.Time // Start of the posting-date window; defaults to Days before To
+	To        time.Time // End of the posting-date window; defaults to today
+	Days      int       // Window length used when From is unset
+	MaxPages  int       // Pages of 100 preprints scanned per search
+
+	preprints topicid.Map[string]
+}
+
+func New() *DataSourceBioRxiv {
+	return &DataSourceBioRxiv{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://api.biorxiv.org",
+		UserAgent: "locus/biorxiv-datasource",
+		Server:    "biorxiv",
+		Days:      30,
+		MaxPages:  3,
+	}
+}
+
+// Init implements models.DataSource
+// Validates the server name; the API requires no credentials
+func (es *DataSourceBioRxiv) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.Server != "biorxiv" && es.Server != "medrxiv" {
+		return fmt.Errorf("unsupported bioRxiv server %q: use biorxiv or medrxiv", es.Server)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceBioRxiv) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	var page detailsPage
+	return es.doJSON(ctx, fmt.Sprintf("/details/%s/1d/0/json", es.Server), &page) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceBioRxiv) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "bioRxiv",
+		Homepage:    "https://www.biorxiv.org",
+		Attribution: "bioRxiv",
+		TermsURL:    "https://www.biorxiv.org/about-biorxiv",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// The API has no text search, so preprints in the configured date window (and category)
+// are scanned page by page and kept when every query term appears in the title or abstract
+func (es *DataSourceBioRxiv) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for bioRxiv DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+	terms := strings.Fields(strings.ToLower(query))
+	maxPages := es.MaxPages
+	if maxPages <= 0 {
+		maxPages = 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	from, to := es.window()
+	results := make([]datasource.DataSourceTopic, 0, count)
+	seen := map[string]struct{}{}
+	for page := 0; page < maxPages && len(results) < count; page++ {
+		path := fmt.Sprintf("/details/%s/%s/%s/%d/json", es.Server, from, to, page*pageSize)
+		if es.Category != "" {
+			path += "?category=" + strings.ReplaceAll(strings.ToLower(es.Category), " ", "_")
+		}
+		var response detailsPage
+		if err := es.doJSON(ctx, path, &response); err != nil {
+			// Keep what earlier pages produced when a later page times out
+			if len(results) > 0 {
+				break
+			}
+			return nil, err
+		}
+		for _, item := range response.Collection {
+			if len(results) >= count {
+				break
+			}
+			if _, ok := seen[item.DOI]; ok || !matches(item, terms) {
+				continue
+			}
+			seen[item.DOI] = struct{}{}
+			results = append(results, datasource.DataSourceTopic{
+				Topic:     item.Title,
+				SourceURL: es.preprintLink(item.DOI, item.Version),
+				Site:      item.Category,
+				TopicID:   es.preprints.Put(item.DOI, item.DOI),
+			})
+		}
+		if len(response.Collection) < pageSize {
+			break
+		}
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the abstract of the latest version followed by the preprint's version history
+func (es *DataSourceBioRxiv) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	doi, ok := es.preprints.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown bioRxiv topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var response detailsPage
+	if err := es.doJSON(ctx, fmt.Sprintf("/details/%s/%s/na/json", es.Server, doi), &response); err != nil {
+		return nil, err
+	}
+	if len(response.Collection) == 0 {
+		return []datasource.DataSourceData{}, nil
+	}
+
+	// Versions are returned oldest first
+	latest := response.Collection[len(response.Collection)-1]
+	results := make([]datasource.DataSourceData, 0, 2)
+	if abstract := strings.TrimSpace(latest.Abstract); abstract != "" {
+		results = append(results, datasource.DataSourceData{
+			DataText:  abstract,
+			SourceURL: es.preprintLink(latest.DOI, latest.Version),
+			Site:      latest.Category,
+			AnswerID:  topicID,
+		})
+	}
+
+	var history strings.Builder
+	history.WriteString("Version history:")
+	for _, version := range response.Collection {
+		fmt.Fprintf(&history, "\nv%s posted %s (%s, %s)", version.Version, version.Date, version.Type, version.License)
+	}
+	if latest.Published != "" && latest.Published != "NA" {
+		fmt.Fprintf(&history, "\nPublished as https://doi.org/%s", latest.Published)
+	}
+	if count <= 0 || len(results) < count {
+		results = append(results, datasource.DataSourceData{
+			DataText:  history.String(),
+			SourceURL: es.preprintLink(latest.DOI, latest.Version),
+			Site:      latest.Category,
+			AnswerID:  topicid.Hash(doi + "#versions"),
+		})
+	}
+	return results, nil
+}
+
+// detailsPage mirrors a /details response page
+type detailsPage struct {
+	Collection []preprint `json:"collection"`
+}
+
+// preprint is one version of a preprint as returned by /details
+type preprint struct {
+	DOI       string `json:"doi"`
+	Title     string `json:"title"`
+	Date      string `json:"date"`
+	Version   string `json:"version"`
+	Type      string `json:"type"`
+	License   string `json:"license"`
+	Category  string `json:"category"`
+	Abstract  string `json:"abstract"`
+	Published string `json:"published"`
+}
+
+// window returns the configured posting-date interval formatted for the API
+func (es *DataSourceBioRxiv) window() (string, string) {
+	to := es.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := es.From
+	if from.IsZero() {
+		days := es.Days
+		if days <= 0 {
+			days = 30
+		}
+		from = to.AddDate(0, 0, -days)
+	}
+	return from.Format("2006-01-02"), to.Format("2006-01-02")
+}
+
+func (es *DataSourceBioRxiv) preprintLink(doi, version string) string {
+	return fmt.Sprintf("https://www.%s.org/content/%sv%s", es.Server, doi, version)
+}
+
+// doJSON performs an HTTP GET request against the bioRxiv API and decodes the JSON response into target
+func (es *DataSourceBioRxiv) doJSON(ctx context.Context, path string, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(es.BaseURL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("biorxiv request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func matches(item preprint, terms []string) bool {
+	text := strings.ToLower(item.Title + " " + item.Abstract)
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
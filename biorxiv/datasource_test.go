@@ -0,0 +1,91 @@
+package biorxiv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchTopicsScansPages(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Query().Get("category") != "cell_biology" {
+			t.Errorf("category = %q", r.URL.Query().Get("category"))
+		}
+		var page detailsPage
+		switch r.URL.Path {
+		case "/details/biorxiv/2024-01-01/2024-01-31/0/json":
+			// A full page of non-matching preprints, so the next page is requested
+			for i := 0; i < pageSize; i++ {
+				page.Collection = append(page.Collection, preprint{DOI: fmt.Sprintf("10.1101/%d", i), Title: "Unrelated"})
+			}
+			page.Collection[5] = preprint{DOI: "10.1101/a", Version: "2", Title: "Mitochondrial fission", Abstract: "In yeast", Category: "cell biology"}
+		case "/details/biorxiv/2024-01-01/2024-01-31/100/json":
+			page.Collection = []preprint{
+				{DOI: "10.1101/a", Title: "Mitochondrial fission in yeast"},
+				{DOI: "10.1101/b", Version: "1", Title: "Yeast mitochondrial dynamics", Category: "cell biology"},
+			}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.BaseURL = srv.URL
+	es.Category = "Cell Biology"
+	es.From = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	es.To = time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	topics, err := es.FetchTopics(5, "Yeast mitochondrial")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[0].SourceURL != "https://www.biorxiv.org/content/10.1101/av2" || topics[1].Topic != "Yeast mitochondrial dynamics" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	if len(paths) != 2 {
+		t.Errorf("requested %q, want two pages", paths)
+	}
+}
+
+func TestFetchData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/details/medrxiv/10.1101/x/na/json" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"collection":[
+			{"doi":"10.1101/x","version":"1","date":"2024-01-01","type":"new results","license":"cc_by","abstract":"Old"},
+			{"doi":"10.1101/x","version":"2","date":"2024-02-01","type":"new results","license":"cc_by","abstract":" New ","category":"epidemiology","published":"10.1000/journal.1"}
+		]}`)
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.BaseURL = srv.URL
+	es.Server = "medrxiv"
+	topicID := es.preprints.Put("10.1101/x", "10.1101/x")
+	data, err := es.FetchData(5, topicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 || data[0].DataText != "New" || data[0].SourceURL != "https://www.medrxiv.org/content/10.1101/xv2" {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	history := "Version history:\nv1 posted 2024-01-01 (new results, cc_by)\nv2 posted 2024-02-01 (new results, cc_by)\nPublished as https://doi.org/10.1000/journal.1"
+	if data[1].DataText != history {
+		t.Errorf("history = %q, want %q", data[1].DataText, history)
+	}
+}
+
+func TestInitRejectsUnknownServer(t *testing.T) {
+	es := New()
+	es.Server = "arxiv"
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted an unsupported server")
+	}
+}
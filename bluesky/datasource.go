@@ -0,0 +1,289 @@
+package bluesky
+
+// Data Source Adapter for Bluesky via the AT Protocol XRPC API
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/auth"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceBluesky struct {
+	Client      *http.Client
+	BaseURL     string // PDS root that authenticates and proxies app.bsky requests
+	UserAgent   string
+	Identifier  string // Set by user: handle, DID or email of the account
+	AppPassword string // Set by user: an app password, never the account password
+	Sort        string // "top" or "latest"
+	Language    string // Optional language filter, e.g. "en"
+
+	session *auth.AppPassword
+	posts   topicid.Map[string]
+}
+
+func New() *DataSourceBluesky {
+	return &DataSourceBluesky{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://bsky.social",
+		UserAgent: "locus/bluesky-datasource",
+		Sort:      "top",
+	}
+}
+
+// Init implements models.DataSource
+// Sets up the app-password session; the session itself is created lazily on first use
+func (es *DataSourceBluesky) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.Identifier == "" || es.AppPassword == "" {
+		return errors.New("Identifier and AppPassword are required for Bluesky DataSource")
+	}
+	if es.session == nil {
+		es.session = &auth.AppPassword{
+			Client:     es.Client,
+			Host:       es.BaseURL,
+			Identifier: es.Identifier,
+			Password:   es.AppPassword,
+		}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceBluesky) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("actor", es.Identifier)
+	return es.doXRPC(ctx, "app.bsky.actor.getProfile", params, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceBluesky) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Bluesky",
+		Homepage: "https://bsky.app",
+		TermsURL: "https://bsky.social/about/support/tos",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Uses app.bsky.feed.searchPosts; each post is a topic and its author handle is reported as the Site
+func (es *DataSourceBluesky) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Bluesky DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(count))
+	if es.Sort != "" {
+		params.Set("sort", es.Sort)
+	}
+	if es.Language != "" {
+		params.Set("lang", es.Language)
+	}
+
+	var response struct {
+		Posts []post `json:"posts"`
+	}
+	if err := es.doXRPC(ctx, "app.bsky.feed.searchPosts", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Posts))
+	for _, item := range response.Posts {
+		title := summarize(item.Record.Text, 120)
+		if title == "" {
+			continue
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: item.webURL(),
+			Site:      item.Author.Handle,
+			TopicID:   es.posts.Put(item.URI, item.URI),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Fetches the post thread and returns the post followed by its most-liked direct replies
+func (es *DataSourceBluesky) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	uri, ok := es.posts.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Bluesky topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	params := url.Values{}
+	params.Set("uri", uri)
+	params.Set("depth", "1")
+	params.Set("parentHeight", "0")
+
+	var response struct {
+		Thread struct {
+			Post    *post `json:"post"`
+			Replies []struct {
+				Post *post `json:"post"`
+			} `json:"replies"`
+		} `json:"thread"`
+	}
+	if err := es.doXRPC(ctx, "app.bsky.feed.getPostThread", params, &response); err != nil {
+		return nil, err
+	}
+	if response.Thread.Post == nil {
+		return []datasource.DataSourceData{}, nil
+	}
+
+	replies := make([]post, 0, len(response.Thread.Replies))
+	for _, reply := range response.Thread.Replies {
+		// Blocked or deleted replies come back without a post view
+		if reply.Post != nil {
+			replies = append(replies, *reply.Post)
+		}
+	}
+	sort.SliceStable(replies, func(i, j int) bool {
+		return replies[i].LikeCount > replies[j].LikeCount
+	})
+
+	posts := append([]post{*response.Thread.Post}, replies...)
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, item := range posts {
+		if len(results) >= count {
+			break
+		}
+		if strings.TrimSpace(item.Record.Text) == "" {
+			continue
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  item.format(),
+			SourceURL: item.webURL(),
+			Site:      item.Author.Handle,
+			AnswerID:  topicid.Hash(item.URI),
+		})
+	}
+	return results, nil
+}
+
+// post mirrors app.bsky.feed.defs#postView
+type post struct {
+	URI    string `json:"uri"`
+	Author struct {
+		Handle      string `json:"handle"`
+		DisplayName string `json:"displayName"`
+	} `json:"author"`
+	Record struct {
+		Text      string `json:"text"`
+		CreatedAt string `json:"createdAt"`
+	} `json:"record"`
+	ReplyCount  int `json:"replyCount"`
+	RepostCount int `json:"repostCount"`
+	LikeCount   int `json:"likeCount"`
+}
+
+// webURL converts the at:// URI into a bsky.app link
+func (p post) webURL() string {
+	rkey := p.URI[strings.LastIndex(p.URI, "/")+1:]
+	return fmt.Sprintf("https://bsky.app/profile/%s/post/%s", p.Author.Handle, rkey)
+}
+
+func (p post) format() string {
+	author := "@" + p.Author.Handle
+	if p.Author.DisplayName != "" {
+		author = fmt.Sprintf("%s (@%s)", p.Author.DisplayName, p.Author.Handle)
+	}
+	return fmt.Sprintf("%s, %s - %d replies, %d reposts, %d likes:\n%s",
+		author, p.Record.CreatedAt, p.ReplyCount, p.RepostCount, p.LikeCount, strings.TrimSpace(p.Record.Text))
+}
+
+// doXRPC performs an authenticated XRPC query and decodes the JSON response into target
+func (es *DataSourceBluesky) doXRPC(ctx context.Context, method string, params url.Values, target interface{}) error {
+	token, err := es.session.Token(ctx)
+	if err != nil {
+		return err
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + "/xrpc/" + method
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := es.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		es.session.Invalidate()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("bluesky %s failed: status %d: %s", method, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+
+// summarize shortens text to at most limit runes on a word boundary, for use as a topic title
+func summarize(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	cut := string(runes[:limit])
+	if i := strings.LastIndex(cut, " "); i > limit/2 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
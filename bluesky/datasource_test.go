@@ -0,0 +1,90 @@
+package bluesky
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/locus-search/datasource/internal/topicid"
+)
+
+const postURI = "at://did:plc:abc/app.bsky.feed.post/3kxyz"
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/xrpc/com.atproto.server.createSession", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"accessJwt":"access","refreshJwt":"refresh"}`)
+	})
+	mux.HandleFunc("/xrpc/app.bsky.feed.searchPosts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		q := r.URL.Query()
+		if q.Get("q") != "atproto" || q.Get("sort") != "latest" || q.Get("lang") != "en" || q.Get("limit") != "5" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"posts":[
+			{"uri":%q,"author":{"handle":"alice.bsky.social"},"record":{"text":"Building on  atproto"}},
+			{"uri":"at://did:plc:abc/app.bsky.feed.post/empty","record":{"text":" "}}
+		]}`, postURI)
+	})
+	mux.HandleFunc("/xrpc/app.bsky.feed.getPostThread", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("uri") != postURI {
+			t.Errorf("thread uri = %q", r.URL.Query().Get("uri"))
+		}
+		fmt.Fprintf(w, `{"thread":{
+			"post":{"uri":%q,"author":{"handle":"alice.bsky.social","displayName":"Alice"},"record":{"text":"Building on atproto","createdAt":"2024-01-02"},"likeCount":9},
+			"replies":[
+				{"post":{"uri":"at://did:plc:b/app.bsky.feed.post/r1","author":{"handle":"bob"},"record":{"text":"Nice"},"likeCount":1}},
+				{"notFound":true},
+				{"post":{"uri":"at://did:plc:c/app.bsky.feed.post/r2","author":{"handle":"carol"},"record":{"text":"Agreed"},"likeCount":5}}
+			]
+		}}`, postURI)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.Identifier, es.AppPassword = "alice.bsky.social", "app-pass"
+	es.Sort = "latest"
+	es.Language = "en"
+
+	topics, err := es.FetchTopics(5, "atproto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Building on atproto" || topics[0].Site != "alice.bsky.social" ||
+		topics[0].SourceURL != "https://bsky.app/profile/alice.bsky.social/post/3kxyz" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 3 {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	want := "Alice (@alice.bsky.social), 2024-01-02 - 0 replies, 0 reposts, 9 likes:\nBuilding on atproto"
+	if data[0].DataText != want || data[0].AnswerID != topicid.Hash(postURI) {
+		t.Errorf("post = %+v", data[0])
+	}
+	if data[1].Site != "carol" || data[2].Site != "bob" {
+		t.Errorf("replies not ordered by likes: %+v", data[1:])
+	}
+}
+
+func TestInitRequiresAppPassword(t *testing.T) {
+	es := New()
+	es.Identifier = "alice.bsky.social"
+	if err := es.Init(); err == nil {
+		t.Error("Init succeeded without an AppPassword")
+	}
+}
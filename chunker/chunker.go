@@ -0,0 +1,235 @@
+// Package chunker splits long texts into bounded, optionally overlapping
+// chunks for data items. Chunks never cross a section heading, keep their
+// section title and position, and are cut at paragraph, then sentence, then
+// word boundaries, so each one reads on its own. Size and overlap are
+// measured in characters or approximate tokens.
+package chunker
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultSize is the chunk size used when Chunker.Size is zero
+const DefaultSize = 1500
+
+// Section is a titled part of a text
+type Section struct {
+	Title  string
+	Text   string
+	Offset int // Byte offset of Text in the whole text
+}
+
+// Chunk is one piece of a text
+type Chunk struct {
+	Text    string
+	Section string // Title of the section the chunk belongs to; empty before the first heading
+	Index   int    // Position among all chunks of the text, from 0
+	Start   int    // Byte offset of Text in the whole text
+	End     int
+}
+
+// Heading returns the title for a chunk's data item: the document title followed by the section title
+func (c Chunk) Heading(title string) string {
+	switch {
+	case c.Section == "":
+		return title
+	case title == "":
+		return c.Section
+	}
+	return title + " - " + c.Section
+}
+
+// Chunker splits texts. The zero value makes chunks of DefaultSize characters without overlap.
+type Chunker struct {
+	Size    int // Largest chunk, in units of Length; default DefaultSize
+	Overlap int // Text repeated from the end of one chunk at the start of the next, in units of Length
+	// Length measures text; default Characters. Use Tokens to bound chunks for a model's context.
+	Length func(string) int
+}
+
+// Default is a Chunker with the default size and no overlap
+var Default = &Chunker{}
+
+// Characters counts the characters of s
+func Characters(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// Tokens estimates the tokens of s for common subword tokenizers: about four characters per token,
+// but at least one per word
+func Tokens(s string) int {
+	n := (utf8.RuneCountInString(s) + 3) / 4
+	if words := len(strings.Fields(s)); words > n {
+		return words
+	}
+	return n
+}
+
+// Text splits text on its headings (see Sections) and chunks each section
+func (c *Chunker) Text(text string) []Chunk {
+	return c.Sections(Sections(text))
+}
+
+// Paragraphs chunks a list of paragraphs; offsets refer to the paragraphs joined by blank lines
+func (c *Chunker) Paragraphs(paragraphs []string) []Chunk {
+	return c.Sections([]Section{{Text: strings.Join(paragraphs, "\n\n")}})
+}
+
+// Sections chunks each section separately, numbering the chunks across all of them
+func (c *Chunker) Sections(sections []Section) []Chunk {
+	size := c.Size
+	if size <= 0 {
+		size = DefaultSize
+	}
+	length := c.Length
+	if length == nil {
+		length = Characters
+	}
+	overlap := c.Overlap
+	if overlap >= size {
+		overlap = size / 2
+	}
+
+	var out []Chunk
+	for _, s := range sections {
+		units := split(s.Text, 0, size, length, 0)
+		var current []span
+		emit := func() {
+			start, end := current[0].start, current[len(current)-1].end
+			out = append(out, Chunk{
+				Text:    strings.TrimSpace(s.Text[start:end]),
+				Section: s.Title,
+				Index:   len(out),
+				Start:   s.Offset + start,
+				End:     s.Offset + end,
+			})
+		}
+		for _, u := range units {
+			if len(current) > 0 && length(s.Text[current[0].start:u.end]) > size {
+				emit()
+				current = tail(s.Text, current, overlap, length)
+				// Overlap gives way when it would leave no room for the next unit
+				for len(current) > 0 && length(s.Text[current[0].start:u.end]) > size {
+					current = current[1:]
+				}
+			}
+			current = append(current, u)
+		}
+		if len(current) > 0 {
+			emit()
+		}
+	}
+	return out
+}
+
+// Wiki ("== History ==") and Markdown ("## History") headings
+var headingPattern = regexp.MustCompile(`(?m)^[ \t]*(?:(={2,6})[ \t]*(.+?)[ \t]*={2,6}|#{1,6}[ \t]+(.+?)[ \t]*#*)[ \t]*$`)
+
+// Sections splits text on wiki and Markdown heading lines. Text before the first heading is a section
+// without a title; sections without text are dropped.
+func Sections(text string) []Section {
+	var sections []Section
+	add := func(title string, start, end int) {
+		body := text[start:end]
+		if strings.TrimSpace(body) == "" {
+			return
+		}
+		sections = append(sections, Section{Title: title, Text: body, Offset: start})
+	}
+	title, start := "", 0
+	for _, m := range headingPattern.FindAllStringSubmatchIndex(text, -1) {
+		add(title, start, m[0])
+		if m[4] >= 0 {
+			title = text[m[4]:m[5]]
+		} else {
+			title = text[m[6]:m[7]]
+		}
+		start = m[1]
+	}
+	add(title, start, len(text))
+	return sections
+}
+
+// span is a piece of a section's text, by byte offsets
+type span struct {
+	start, end int
+}
+
+// Boundaries tried in turn when a piece is too long: paragraphs, sentences, words
+var boundaries = []*regexp.Regexp{
+	regexp.MustCompile(`\n[ \t]*\n\s*`),
+	regexp.MustCompile(`[.!?]["')\]]*\s+`),
+	regexp.MustCompile(`\s+`),
+}
+
+// split cuts text[offset:] into spans no longer than size, at the coarsest boundary that works. A
+// single word longer than size is cut between characters.
+func split(text string, offset, size int, length func(string) int, level int) []span {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	if length(text) <= size && level > 0 {
+		return []span{trim(text, offset)}
+	}
+	if level >= len(boundaries) {
+		return cut(text, offset, size, length)
+	}
+	var out []span
+	last := 0
+	pieces := boundaries[level].FindAllStringIndex(text, -1)
+	pieces = append(pieces, []int{len(text), len(text)})
+	for _, b := range pieces {
+		// Sentence and word boundaries keep their punctuation with the piece before them
+		end := b[0]
+		if level == 1 {
+			end = b[0] + len(strings.TrimRightFunc(text[b[0]:b[1]], unicode.IsSpace))
+		}
+		piece := text[last:end]
+		if length(piece) > size || level == 0 {
+			out = append(out, split(piece, offset+last, size, length, level+1)...)
+		} else if strings.TrimSpace(piece) != "" {
+			out = append(out, trim(piece, offset+last))
+		}
+		last = b[1]
+	}
+	return out
+}
+
+// cut splits a piece without boundaries between characters
+func cut(text string, offset, size int, length func(string) int) []span {
+	var out []span
+	start := 0
+	for i := range text {
+		if i > start && length(text[start:i]) >= size {
+			out = append(out, span{offset + start, offset + i})
+			start = i
+		}
+	}
+	return append(out, span{offset + start, offset + len(text)})
+}
+
+// trim returns the span of text without surrounding whitespace
+func trim(text string, offset int) span {
+	start := len(text) - len(strings.TrimLeftFunc(text, unicode.IsSpace))
+	end := len(strings.TrimRightFunc(text, unicode.IsSpace))
+	return span{offset + start, offset + end}
+}
+
+// tail returns the last spans of a chunk that fit in overlap, to start the next chunk with. It never
+// returns the whole chunk, so every chunk adds new text.
+func tail(text string, spans []span, overlap int, length func(string) int) []span {
+	if overlap <= 0 {
+		return nil
+	}
+	i := len(spans)
+	for i > 1 && length(text[spans[i-1].start:spans[len(spans)-1].end]) <= overlap {
+		i--
+	}
+	if i == len(spans) {
+		return nil
+	}
+	return append([]span(nil), spans[i:]...)
+}
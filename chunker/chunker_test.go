@@ -0,0 +1,144 @@
+package chunker
+
+import (
+	"strings"
+	"testing"
+)
+
+func texts(chunks []Chunk) []string {
+	var out []string
+	for _, c := range chunks {
+		out = append(out, c.Text)
+	}
+	return out
+}
+
+func TestParagraphBoundaries(t *testing.T) {
+	c := &Chunker{Size: 45}
+	chunks := c.Paragraphs([]string{
+		"Go is a programming language.",
+		"It has goroutines.",
+		"Channels connect them.",
+	})
+	want := []string{
+		"Go is a programming language.",
+		"It has goroutines.\n\nChannels connect them.",
+	}
+	got := texts(chunks)
+	if len(got) != len(want) {
+		t.Fatalf("Paragraphs = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, got[i], want[i])
+		}
+		if chunks[i].Index != i {
+			t.Errorf("chunk %d has Index %d", i, chunks[i].Index)
+		}
+	}
+}
+
+func TestSentenceBoundaries(t *testing.T) {
+	// One paragraph longer than Size is cut after its sentences
+	text := "The first sentence is here. The second one follows! Is this the third? The fourth ends it."
+	chunks := (&Chunker{Size: 55}).Text(text)
+	want := []string{
+		"The first sentence is here. The second one follows!",
+		"Is this the third? The fourth ends it.",
+	}
+	got := texts(chunks)
+	if len(got) != len(want) {
+		t.Fatalf("Text = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSizeLimit(t *testing.T) {
+	paragraph := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 20)
+	text := paragraph + "\n\n" + strings.Repeat("x", 250) + "\n\n" + paragraph
+	for _, size := range []int{30, 100, 400} {
+		chunks := (&Chunker{Size: size}).Text(text)
+		if len(chunks) < 2 {
+			t.Errorf("Size %d: %d chunks", size, len(chunks))
+		}
+		for _, c := range chunks {
+			if n := Characters(c.Text); n > size || n == 0 {
+				t.Errorf("Size %d: chunk %d has %d characters", size, c.Index, n)
+			}
+			if text[c.Start:c.End] != c.Text {
+				t.Errorf("Size %d: chunk %d offsets [%d:%d] don't match its text", size, c.Index, c.Start, c.End)
+			}
+		}
+	}
+
+	// Words longer than Size are cut between characters, not bytes
+	chunks := (&Chunker{Size: 4}).Text("ééééééééé")
+	if got := texts(chunks); len(got) != 3 || got[0] != "éééé" || got[2] != "é" {
+		t.Errorf("Text of a long word = %q", got)
+	}
+}
+
+func TestTokenSize(t *testing.T) {
+	text := strings.Repeat("word ", 100)
+	for _, c := range (&Chunker{Size: 20, Length: Tokens}).Text(text) {
+		if n := Tokens(c.Text); n > 20 {
+			t.Errorf("chunk %d has %d tokens", c.Index, n)
+		}
+	}
+	if n := Tokens("a b c"); n != 3 {
+		t.Errorf("Tokens(a b c) = %d, want one per word", n)
+	}
+	if n := Tokens("internationalization"); n != 5 {
+		t.Errorf("Tokens(internationalization) = %d, want 5", n)
+	}
+}
+
+func TestOverlap(t *testing.T) {
+	c := &Chunker{Size: 40, Overlap: 15}
+	chunks := c.Text("One two three. Four five six. Seven eight nine. Ten eleven twelve.")
+	want := []string{
+		"One two three. Four five six.",
+		"Four five six. Seven eight nine.", // Repeats the last sentence that fits in the overlap
+		"Ten eleven twelve.",               // "Seven eight nine." is longer than the overlap
+	}
+	got := texts(chunks)
+	if len(got) != len(want) {
+		t.Fatalf("Text = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSections(t *testing.T) {
+	text := "Intro text.\n\n== History ==\nFounded in 2009.\n\n## Design\nSimple.\n\n== Empty ==\n"
+	sections := Sections(text)
+	want := []struct{ title, text string }{
+		{"", "Intro text."},
+		{"History", "Founded in 2009."},
+		{"Design", "Simple."},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("Sections = %+v", sections)
+	}
+	for i, w := range want {
+		if sections[i].Title != w.title || strings.TrimSpace(sections[i].Text) != w.text {
+			t.Errorf("section %d = %+v, want %q: %q", i, sections[i], w.title, w.text)
+		}
+		if !strings.HasPrefix(text[sections[i].Offset:], sections[i].Text) {
+			t.Errorf("section %d offset %d doesn't match its text", i, sections[i].Offset)
+		}
+	}
+
+	// Chunks never span sections and carry their titles
+	chunks := Default.Text(text)
+	if len(chunks) != 3 || chunks[1].Section != "History" || chunks[1].Heading("Go") != "Go - History" || chunks[0].Heading("Go") != "Go" {
+		t.Errorf("Text = %+v", chunks)
+	}
+}
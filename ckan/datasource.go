@@ -0,0 +1,321 @@
+package ckan
+
+// Data Source Adapter for CKAN open-data portals (catalog.data.gov, data.gov.uk, ...)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceCKAN struct {
+	Client       *http.Client
+	BaseURL      string // Portal root, e.g. https://catalog.data.gov or https://data.gov.uk
+	APIKey       string // Only needed for private datasets
+	UserAgent    string
+	Organization string   // Restrict to one publisher by its CKAN name
+	Formats      []string // Restrict to datasets offering one of these resource formats, e.g. "CSV", "JSON"
+
+	datasets topicid.Map[string]
+}
+
+// dataset is the subset of a CKAN package used for topics and data
+type dataset struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Title        string `json:"title"`
+	Notes        string `json:"notes"`
+	LicenseTitle string `json:"license_title"`
+	Modified     string `json:"metadata_modified"`
+	Organization *struct {
+		Title string `json:"title"`
+	} `json:"organization"`
+	Tags []struct {
+		Name string `json:"name"`
+	} `json:"tags"`
+	Resources []struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Format      string `json:"format"`
+		URL         string `json:"url"`
+	} `json:"resources"`
+}
+
+func New() *DataSourceCKAN {
+	return &DataSourceCKAN{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://catalog.data.gov",
+		UserAgent: "locus/ckan-datasource",
+	}
+}
+
+// Init implements models.DataSource
+// CKAN requires no initialization beyond a portal URL
+func (es *DataSourceCKAN) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if strings.TrimSpace(es.BaseURL) == "" {
+		return errors.New("BaseURL is required for CKAN DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceCKAN) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	return es.action(ctx, "status_show", nil, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceCKAN) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "CKAN",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each matching dataset is a topic titled "Title (Publisher; CSV, JSON)"
+func (es *DataSourceCKAN) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for CKAN DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("rows", strconv.Itoa(count))
+	var filters []string
+	if es.Organization != "" {
+		filters = append(filters, "organization:"+strconv.Quote(es.Organization))
+	}
+	if len(es.Formats) > 0 {
+		quoted := make([]string, 0, len(es.Formats))
+		for _, format := range es.Formats {
+			quoted = append(quoted, strconv.Quote(format))
+		}
+		filters = append(filters, "res_format:("+strings.Join(quoted, " OR ")+")")
+	}
+	if len(filters) > 0 {
+		params.Set("fq", strings.Join(filters, " AND "))
+	}
+
+	var result struct {
+		Results []dataset `json:"results"`
+	}
+	if err := es.action(ctx, "package_search", params, &result); err != nil {
+		return nil, err
+	}
+
+	site := es.site()
+	results := make([]datasource.DataSourceTopic, 0, len(result.Results))
+	for _, ds := range result.Results {
+		var details []string
+		if ds.Organization != nil && ds.Organization.Title != "" {
+			details = append(details, ds.Organization.Title)
+		}
+		if formats := resourceFormats(ds); formats != "" {
+			details = append(details, formats)
+		}
+		title := ds.Title
+		if len(details) > 0 {
+			title = fmt.Sprintf("%s (%s)", ds.Title, strings.Join(details, "; "))
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: es.datasetLink(ds.Name),
+			Site:      site,
+			TopicID:   es.datasets.Put(ds.ID, ds.ID),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the dataset description and metadata, then one item per resource (name, format, link) up to count
+func (es *DataSourceCKAN) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	id, ok := es.datasets.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown CKAN topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	params := url.Values{}
+	params.Set("id", id)
+	var ds dataset
+	if err := es.action(ctx, "package_show", params, &ds); err != nil {
+		return nil, err
+	}
+
+	var summary strings.Builder
+	summary.WriteString(ds.Title)
+	if notes := strings.TrimSpace(ds.Notes); notes != "" {
+		summary.WriteString("\n\n" + notes)
+	}
+	summary.WriteString("\n")
+	if ds.Organization != nil && ds.Organization.Title != "" {
+		summary.WriteString("\nPublisher: " + ds.Organization.Title)
+	}
+	if ds.LicenseTitle != "" {
+		summary.WriteString("\nLicense: " + ds.LicenseTitle)
+	}
+	if ds.Modified != "" {
+		summary.WriteString("\nLast updated: " + strings.SplitN(ds.Modified, "T", 2)[0])
+	}
+	if len(ds.Tags) > 0 {
+		tags := make([]string, 0, len(ds.Tags))
+		for _, tag := range ds.Tags {
+			tags = append(tags, tag.Name)
+		}
+		summary.WriteString("\nTags: " + strings.Join(tags, ", "))
+	}
+	if formats := resourceFormats(ds); formats != "" {
+		summary.WriteString("\nFormats: " + formats)
+	}
+
+	site := es.site()
+	results := []datasource.DataSourceData{{
+		DataText:  strings.TrimSpace(summary.String()),
+		SourceURL: es.datasetLink(ds.Name),
+		Site:      site,
+		AnswerID:  topicID,
+	}}
+	for _, resource := range ds.Resources {
+		if len(results) >= count {
+			break
+		}
+		name := resource.Name
+		if name == "" {
+			name = "Unnamed resource"
+		}
+		text := fmt.Sprintf("%s [%s]: %s", name, strings.ToUpper(resource.Format), resource.URL)
+		if description := strings.TrimSpace(resource.Description); description != "" {
+			text += "\n" + description
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  text,
+			SourceURL: resource.URL,
+			Site:      site,
+			AnswerID:  topicid.Hash(ds.ID + "/" + resource.ID),
+		})
+	}
+	return results, nil
+}
+
+func (es *DataSourceCKAN) datasetLink(name string) string {
+	return strings.TrimRight(es.BaseURL, "/") + "/dataset/" + url.PathEscape(name)
+}
+
+// site reports the portal host, e.g. "catalog.data.gov"
+func (es *DataSourceCKAN) site() string {
+	if u, err := url.Parse(es.BaseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return es.BaseURL
+}
+
+// action calls a CKAN Action API function and decodes its result member into target
+func (es *DataSourceCKAN) action(ctx context.Context, name string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + "/api/3/action/" + name
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	if es.APIKey != "" {
+		req.Header.Set("Authorization", es.APIKey)
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("ckan request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var envelope struct {
+		Success bool            `json:"success"`
+		Result  json.RawMessage `json:"result"`
+		Error   *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return err
+	}
+	if !envelope.Success {
+		if envelope.Error != nil {
+			return fmt.Errorf("ckan %s failed: %s", name, envelope.Error.Message)
+		}
+		return fmt.Errorf("ckan %s failed", name)
+	}
+	return json.Unmarshal(envelope.Result, target)
+}
+
+// Helpers
+
+// resourceFormats lists the distinct resource formats of a dataset, e.g. "CSV, JSON"
+func resourceFormats(ds dataset) string {
+	seen := map[string]bool{}
+	var formats []string
+	for _, resource := range ds.Resources {
+		format := strings.ToUpper(strings.TrimSpace(resource.Format))
+		if format == "" || seen[format] {
+			continue
+		}
+		seen[format] = true
+		formats = append(formats, format)
+	}
+	return strings.Join(formats, ", ")
+}
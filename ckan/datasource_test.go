@@ -0,0 +1,82 @@
+package ckan
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const pkg = `{"id":"abc","name":"air-quality","title":"Air Quality","notes":"Hourly readings.","license_title":"Open Data Commons","metadata_modified":"2024-05-01T10:00:00",
+	"organization":{"title":"EPA"},"tags":[{"name":"air"},{"name":"pollution"}],
+	"resources":[{"id":"r1","name":"Readings","format":"csv","url":"https://data.example/readings.csv","description":"All stations."},{"id":"r2","format":"JSON","url":"https://data.example/readings.json"},{"id":"r3","format":"csv","url":"https://data.example/old.csv"}]}`
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/3/action/package_search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "air" || q.Get("rows") != "5" || q.Get("fq") != `organization:"epa-gov" AND res_format:("CSV" OR "JSON")` {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"success":true,"result":{"results":[%s]}}`, pkg)
+	})
+	mux.HandleFunc("/api/3/action/package_show", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") != "abc" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"success":true,"result":%s}`, pkg)
+	})
+	mux.HandleFunc("/api/3/action/status_show", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"success":false,"error":{"message":"Access denied"}}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.Organization = "epa-gov"
+	es.Formats = []string{"CSV", "JSON"}
+
+	topics, err := es.FetchTopics(5, "air")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Air Quality (EPA; CSV, JSON)" || topics[0].SourceURL != es.BaseURL+"/dataset/air-quality" ||
+		topics[0].Site != strings.TrimPrefix(es.BaseURL, "http://") {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Air Quality\n\nHourly readings.\n\nPublisher: EPA\nLicense: Open Data Commons\nLast updated: 2024-05-01\nTags: air, pollution\nFormats: CSV, JSON",
+		"Readings [CSV]: https://data.example/readings.csv\nAll stations.",
+		"Unnamed resource [JSON]: https://data.example/readings.json",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+	if data[1].SourceURL != "https://data.example/readings.csv" {
+		t.Errorf("resource SourceURL = %q", data[1].SourceURL)
+	}
+}
+
+func TestActionFailure(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	if es.CheckAvailability() {
+		t.Error("CheckAvailability ignored success: false")
+	}
+}
@@ -0,0 +1,471 @@
+package composite
+
+// Data Source combinator that fans a search out to several sources and merges their topics
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/fetcher"
+	"github.com/locus-search/datasource/guard"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+)
+
+const defaultTopicCount = 5
+
+// Member is a source registered with the composite under a name used in errors and results
+type Member struct {
+	Name   string
+	Source source.Source
+}
+
+type DataSourceComposite struct {
+	Members []Member
+	Merger  Merger // Ranks the members' topics together; default RoundRobin, see NewMerger for the others
+	// Diversity caps on the merged topics; lower-ranked topics take the places of those over a cap, so
+	// one prolific site or member cannot fill the list
+	MaxPerDomain int // Topics sharing a SourceURL host, or a Site when there is no URL; 0 for no limit
+	MaxPerSource int // Topics from one member; 0 for no limit
+
+	// topics maps the composite's topic IDs to the members' own, which overlap between members
+	topics topicid.Map[memberTopic]
+}
+
+// memberTopic is a topic as the member that returned it knows it
+type memberTopic struct {
+	Member  int   // Index into Members
+	TopicID int64 // The member's topic ID
+}
+
+func New() *DataSourceComposite {
+	return &DataSourceComposite{}
+}
+
+// Add registers a source under name, wrapped in a guard.Guard unless it already is one, so a member that
+// panics fails its own calls instead of taking down the process
+func (es *DataSourceComposite) Add(name string, s source.Source) {
+	if _, ok := s.(*guard.Guard); !ok {
+		s = guard.New(name, s)
+	}
+	es.Members = append(es.Members, Member{Name: name, Source: s})
+}
+
+// Init implements models.DataSource
+// Initializes every member; members that fail are reported together but do not stop the others
+func (es *DataSourceComposite) Init() error {
+	if len(es.Members) == 0 {
+		return errors.New("at least one member is required for Composite DataSource")
+	}
+	var errs []error
+	for _, m := range es.Members {
+		if err := m.Source.Init(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckAvailability implements models.DataSource
+// Reports whether any member is available
+func (es *DataSourceComposite) CheckAvailability() bool {
+	available := make(chan bool, len(es.Members))
+	for _, m := range es.Members {
+		go func(s source.Source) {
+			available <- s.CheckAvailability()
+		}(m.Source)
+	}
+	for range es.Members {
+		if <-available {
+			return true
+		}
+	}
+	return false
+}
+
+// Close implements source.Closer
+// Closes every member; members that fail are reported together but do not stop the others
+func (es *DataSourceComposite) Close(ctx context.Context) error {
+	var errs []error
+	for _, m := range es.Members {
+		if err := source.Close(ctx, m.Source); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FetchTopics implements models.DataSource
+// Queries all members concurrently and merges their results with Merger, by default round-robin in
+// member order, within MaxPerDomain and MaxPerSource. Members that fail are skipped; an error is returned only when every
+// member fails.
+func (es *DataSourceComposite) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return es.FetchTopicsFrom(nil, count, input)
+}
+
+// FetchTopicsFrom is FetchTopics restricted to the named members; nil names means all of them.
+// Names that aren't registered are ignored.
+func (es *DataSourceComposite) FetchTopicsFrom(names []string, count int, input string) ([]datasource.DataSourceTopic, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, errors.New("Missing search input for Composite DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	selected := make([]int, 0, len(es.Members))
+	for i, m := range es.Members {
+		if names == nil || contains(names, m.Name) {
+			selected = append(selected, i)
+		}
+	}
+	if len(es.Members) == 0 {
+		return nil, errors.New("at least one member is required for Composite DataSource")
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("none of %q are Composite members", names)
+	}
+
+	perMember := make([][]datasource.DataSourceTopic, len(selected))
+	errs := make([]error, len(selected))
+	var wg sync.WaitGroup
+	for i, m := range selected {
+		wg.Add(1)
+		go func(i int, m Member) {
+			defer wg.Done()
+			perMember[i], errs[i] = m.Source.FetchTopics(count, input)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("%s: %w", m.Name, errs[i])
+			}
+		}(i, es.Members[m])
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == len(selected) {
+		return nil, errors.Join(errs...)
+	}
+
+	found := make([]Found, 0, len(selected))
+	for i, m := range selected {
+		if errs[i] == nil {
+			found = append(found, Found{Member: m, Name: es.Members[m].Name, Source: es.Members[m].Source, Topics: perMember[i]})
+		}
+	}
+	merger := es.Merger
+	if merger == nil {
+		merger = RoundRobin{}
+	}
+	return es.pick(merger.Merge(input, found), count), nil
+}
+
+// pick takes up to count topics from the ranked candidates, skipping topics a member returned twice and
+// topics over the diversity caps, and gives them the composite's topic IDs
+func (es *DataSourceComposite) pick(candidates []Candidate, count int) []datasource.DataSourceTopic {
+	results := make([]datasource.DataSourceTopic, 0, count)
+	seen := map[int64]bool{}
+	perDomain := map[string]int{}
+	perSource := map[int]int{}
+	for _, c := range candidates {
+		if len(results) >= count {
+			break
+		}
+		t := c.Topic
+		id := compositeID(c.Member, t.TopicID)
+		if seen[id] {
+			continue
+		}
+		d := domain(t)
+		if es.MaxPerDomain > 0 && d != "" && perDomain[d] >= es.MaxPerDomain {
+			continue
+		}
+		if es.MaxPerSource > 0 && perSource[c.Member] >= es.MaxPerSource {
+			continue
+		}
+		seen[id] = true
+		perDomain[d]++
+		perSource[c.Member]++
+		t.TopicID = es.issue(c.Member, t.TopicID)
+		results = append(results, t)
+	}
+	return results
+}
+
+// FetchData implements models.DataSource
+// Forwards to the member that returned the topic, under the member's topic ID. The item answering the
+// topic itself carries the composite's topic ID as its AnswerID.
+func (es *DataSourceComposite) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	member, memberID, ok := es.member(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Composite topicID %d", topicID)
+	}
+	data, err := member.Source.FetchData(count, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", member.Name, err)
+	}
+	return answerIDs(data, memberID, topicID), nil
+}
+
+// FetchDataBatch implements source.Batcher
+// Batches the topics by the member that returned them and fetches from the members concurrently
+func (es *DataSourceComposite) FetchDataBatch(ctx context.Context, count int, topicIDs []int64) (map[int64][]datasource.DataSourceData, error) {
+	byMember := map[int][]int64{}
+	ids := map[memberTopic]int64{} // Back to the composite's IDs
+	var errs []error
+	for _, id := range topicIDs {
+		mt, ok := es.topics.Get(id)
+		if !ok || mt.Member >= len(es.Members) {
+			errs = append(errs, fmt.Errorf("unknown Composite topicID %d", id))
+			continue
+		}
+		byMember[mt.Member] = append(byMember[mt.Member], mt.TopicID)
+		ids[mt] = id
+	}
+
+	results := make(map[int64][]datasource.DataSourceData, len(topicIDs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for m, memberIDs := range byMember {
+		wg.Add(1)
+		go func(m int, memberIDs []int64) {
+			defer wg.Done()
+			member := es.Members[m]
+			data, err := source.FetchDataBatch(ctx, member.Source, count, memberIDs)
+			mu.Lock()
+			defer mu.Unlock()
+			for memberID, d := range data {
+				if id, ok := ids[memberTopic{m, memberID}]; ok {
+					results[id] = answerIDs(d, memberID, id)
+				}
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", member.Name, err))
+			}
+		}(m, memberIDs)
+	}
+	wg.Wait()
+	return results, errors.Join(errs...)
+}
+
+// StringID implements source.IDSource
+// IDs are the member's name and its ID for the topic, separated by a colon
+func (es *DataSourceComposite) StringID(topicID int64) (source.ID, bool) {
+	member, memberID, ok := es.member(topicID)
+	if !ok {
+		return "", false
+	}
+	id, ok := source.StringID(member.Source, memberID)
+	if !ok {
+		return "", false
+	}
+	return source.ID(member.Name + ":" + string(id)), true
+}
+
+// TopicID implements source.IDSource
+// Resolves the ID with the member named in it, which FetchData then forwards the topic to
+func (es *DataSourceComposite) TopicID(id source.ID) (int64, error) {
+	name, memberID, ok := strings.Cut(string(id), ":")
+	if ok {
+		for i, m := range es.Members {
+			if m.Name != name {
+				continue
+			}
+			topicID, err := source.TopicID(m.Source, source.ID(memberID))
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", m.Name, err)
+			}
+			return es.issue(i, topicID), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown Composite topic ID %q: %w", id, source.ErrBadQuery)
+}
+
+// LastModified implements source.Freshness, asking the member that found the topic
+func (es *DataSourceComposite) LastModified(topicID int64) time.Time {
+	member, memberID, ok := es.member(topicID)
+	if !ok {
+		return time.Time{}
+	}
+	return source.LastModified(member.Source, memberID)
+}
+
+// Wall implements source.Walled, asking the member that found the topic
+func (es *DataSourceComposite) Wall(topicID int64) string {
+	member, memberID, ok := es.member(topicID)
+	if !ok {
+		return ""
+	}
+	return source.Wall(member.Source, memberID)
+}
+
+// TopicInfo implements source.TopicInformer, asking the member that found the topic
+func (es *DataSourceComposite) TopicInfo(topicID int64) (source.SourceInfo, bool) {
+	member, memberID, ok := es.member(topicID)
+	if !ok {
+		return source.SourceInfo{}, false
+	}
+	return source.InfoFor(member.Source, memberID)
+}
+
+// Details implements source.Detailed, asking the member that found the topic
+func (es *DataSourceComposite) Details(topicID int64) (source.TopicDetails, bool) {
+	member, memberID, ok := es.member(topicID)
+	if !ok {
+		return source.TopicDetails{}, false
+	}
+	return source.Details(member.Source, memberID)
+}
+
+// Suggest implements source.Suggester
+// Asks every member that suggests concurrently and interleaves their suggestions, dropping repeats
+func (es *DataSourceComposite) Suggest(ctx context.Context, prefix string, count int) ([]string, error) {
+	if count <= 0 {
+		count = 10
+	}
+	perMember := make([][]string, len(es.Members))
+	errs := make([]error, len(es.Members))
+	var wg sync.WaitGroup
+	for i, m := range es.Members {
+		wg.Add(1)
+		go func(i int, m Member) {
+			defer wg.Done()
+			perMember[i], errs[i] = source.Suggest(ctx, m.Source, prefix, count)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("%s: %w", m.Name, errs[i])
+			}
+		}(i, m)
+	}
+	wg.Wait()
+
+	var suggestions []string
+	seen := map[string]bool{}
+	for rank := 0; len(suggestions) < count; rank++ {
+		added := false
+		for _, s := range perMember {
+			if rank >= len(s) {
+				continue
+			}
+			added = true
+			if key := strings.ToLower(s[rank]); !seen[key] && len(suggestions) < count {
+				seen[key] = true
+				suggestions = append(suggestions, s[rank])
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	if len(suggestions) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return suggestions, nil
+}
+
+// FetchHost implements fetcher.Hoster, for the host the member that returned the topic contacts
+func (es *DataSourceComposite) FetchHost(topicID int64) string {
+	member, memberID, ok := es.member(topicID)
+	if !ok {
+		return ""
+	}
+	if host := fetcher.Host(member.Source, memberID); host != "" {
+		return host
+	}
+	return "source:" + member.Name
+}
+
+// Related implements source.RelatedSource, asking the member that found the topic
+func (es *DataSourceComposite) Related(ctx context.Context, topicID int64, count int) ([]datasource.DataSourceTopic, error) {
+	mt, ok := es.topics.Get(topicID)
+	if !ok || mt.Member >= len(es.Members) {
+		return nil, fmt.Errorf("unknown Composite topicID %d", topicID)
+	}
+	topics, err := source.Related(ctx, es.Members[mt.Member].Source, mt.TopicID, count)
+	for i := range topics {
+		topics[i].TopicID = es.issue(mt.Member, topics[i].TopicID)
+	}
+	return topics, err
+}
+
+// Trending implements source.TrendingSource
+// Interleaves the trending topics of the members that have them, applying the diversity caps
+func (es *DataSourceComposite) Trending(ctx context.Context, count int) ([]datasource.DataSourceTopic, error) {
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	var found []Found
+	var errs []error
+	for i, m := range es.Members {
+		topics, err := source.Trending(ctx, m.Source, count)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.Name, err))
+			continue
+		}
+		found = append(found, Found{Member: i, Name: m.Name, Source: m.Source, Topics: topics})
+	}
+	results := es.pick(RoundRobin{}.Merge("", found), count)
+	if len(results) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return results, nil
+}
+
+// issue returns the composite's topic ID for a member's topic and remembers the member's ID for it
+func (es *DataSourceComposite) issue(member int, topicID int64) int64 {
+	id := compositeID(member, topicID)
+	es.topics.Set(id, memberTopic{Member: member, TopicID: topicID})
+	return id
+}
+
+// member returns the member that returned a topic and the member's topic ID
+func (es *DataSourceComposite) member(topicID int64) (Member, int64, bool) {
+	mt, ok := es.topics.Get(topicID)
+	if !ok || mt.Member >= len(es.Members) {
+		return Member{}, 0, false
+	}
+	return es.Members[mt.Member], mt.TopicID, true
+}
+
+// Helpers
+
+// compositeID derives the composite's topic ID from the member's index and its topic ID
+func compositeID(member int, topicID int64) int64 {
+	return topicid.Hash(strconv.Itoa(member) + ":" + strconv.FormatInt(topicID, 10))
+}
+
+// answerIDs gives the items answering a member's topic the composite's ID for it; items with their
+// own answer IDs keep them
+func answerIDs(data []datasource.DataSourceData, memberID, topicID int64) []datasource.DataSourceData {
+	for i := range data {
+		if data[i].AnswerID == memberID {
+			data[i].AnswerID = topicID
+		}
+	}
+	return data
+}
+
+// domain returns the host of a topic's URL without "www.", or its Site when it has no URL
+func domain(t datasource.DataSourceTopic) string {
+	if u, err := url.Parse(t.SourceURL); err == nil && u.Host != "" {
+		return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	}
+	return strings.ToLower(t.Site)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
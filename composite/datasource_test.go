@@ -0,0 +1,147 @@
+package composite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/guard"
+	"github.com/locus-search/datasource/source"
+)
+
+// fake is a member returning fixed topics, and data naming the topic it was asked for
+type fake struct {
+	topics []datasource.DataSourceTopic
+	text   string
+}
+
+func (f *fake) Init() error             { return nil }
+func (f *fake) CheckAvailability() bool { return true }
+
+func (f *fake) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return f.topics, nil
+}
+
+func (f *fake) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return []datasource.DataSourceData{{DataText: f.text, AnswerID: topicID}}, nil
+}
+
+func TestFetchDataNegativeTopicID(t *testing.T) {
+	// DuckDuckGo hashes URLs into the full int64 range
+	es := New()
+	es.Add("a", &fake{topics: []datasource.DataSourceTopic{{TopicID: -42, Topic: "negative"}}})
+	es.Add("b", &fake{topics: []datasource.DataSourceTopic{{TopicID: 7, Topic: "positive"}}})
+	topics, err := es.FetchTopics(5, "query")
+	if err != nil || len(topics) != 2 {
+		t.Fatalf("FetchTopics = %+v, %v", topics, err)
+	}
+
+	for _, topic := range topics {
+		data, err := es.FetchData(1, topic.TopicID)
+		if err != nil {
+			t.Fatalf("FetchData(%d): %v", topic.TopicID, err)
+		}
+		if len(data) != 1 || data[0].AnswerID != topic.TopicID {
+			t.Errorf("FetchData(%d) = %+v", topic.TopicID, data)
+		}
+	}
+}
+
+func TestFetchDataCollidingTopicIDs(t *testing.T) {
+	// Members number their topics independently, so the same ID names different topics
+	es := New()
+	es.Add("bgg", &fake{topics: []datasource.DataSourceTopic{{TopicID: 174430, Topic: "Gloomhaven (board game)"}}, text: "bgg"})
+	es.Add("igdb", &fake{topics: []datasource.DataSourceTopic{{TopicID: 174430, Topic: "Gloomhaven (video game)"}}, text: "igdb"})
+	topics, err := es.FetchTopics(5, "gloomhaven")
+	if err != nil || len(topics) != 2 {
+		t.Fatalf("FetchTopics = %+v, %v", topics, err)
+	}
+	if topics[0].TopicID == topics[1].TopicID {
+		t.Fatalf("both members' topics got ID %d", topics[0].TopicID)
+	}
+
+	want := map[string]string{"Gloomhaven (board game)": "bgg", "Gloomhaven (video game)": "igdb"}
+	for _, topic := range topics {
+		data, err := es.FetchData(1, topic.TopicID)
+		if err != nil {
+			t.Fatalf("FetchData(%d): %v", topic.TopicID, err)
+		}
+		if len(data) != 1 || data[0].DataText != want[topic.Topic] || data[0].AnswerID != topic.TopicID {
+			t.Errorf("FetchData(%q) = %+v, want data from %s", topic.Topic, data, want[topic.Topic])
+		}
+	}
+
+	batch, err := es.FetchDataBatch(context.Background(), 1, []int64{topics[0].TopicID, topics[1].TopicID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, topic := range topics {
+		if data := batch[topic.TopicID]; len(data) != 1 || data[0].DataText != want[topic.Topic] {
+			t.Errorf("FetchDataBatch[%q] = %+v, want data from %s", topic.Topic, data, want[topic.Topic])
+		}
+	}
+}
+
+func TestFetchDataUnknownTopicID(t *testing.T) {
+	es := New()
+	es.Add("a", &fake{})
+	for _, id := range []int64{0, -1, 99} {
+		if _, err := es.FetchData(1, id); err == nil {
+			t.Errorf("FetchData(%d) succeeded for a topic no member returned", id)
+		}
+	}
+}
+
+func TestFetchDataByID(t *testing.T) {
+	es := New()
+	es.Add("a", source.WithInfo(&fake{}, source.SourceInfo{Name: "a"}))
+	es.Add("b", &fake{})
+
+	// Members' IDs resolve without a search through the member named in the ID
+	data, err := source.FetchDataByID(context.Background(), es, 1, "b:-42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 {
+		t.Fatalf("FetchDataByID(b:-42) = %+v", data)
+	}
+	if id, ok := source.StringID(es, data[0].AnswerID); !ok || id != "b:-42" {
+		t.Errorf("StringID(%d) = %q, %v", data[0].AnswerID, id, ok)
+	}
+	for _, id := range []source.ID{"c:1", "a:not-a-number", "7"} {
+		if _, err := source.FetchDataByID(context.Background(), es, 1, id); err == nil {
+			t.Errorf("FetchDataByID(%q) succeeded", id)
+		}
+	}
+}
+
+// panicky is a member that panics on every fetch
+type panicky struct{ fake }
+
+func (p *panicky) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	panic("broken scraper")
+}
+
+func (p *panicky) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	panic("broken scraper")
+}
+
+func TestAddGuardsMembers(t *testing.T) {
+	es := New()
+	es.Add("broken", &panicky{})
+	es.Add("ok", &fake{topics: []datasource.DataSourceTopic{{TopicID: 7, Topic: "fine"}}})
+
+	topics, err := es.FetchTopics(5, "query")
+	if len(topics) != 1 || topics[0].Topic != "fine" {
+		t.Errorf("FetchTopics = %+v, %v; want the healthy member's topic", topics, err)
+	}
+	if err != nil && !errors.Is(err, guard.ErrPanic) {
+		t.Errorf("FetchTopics error %v does not match guard.ErrPanic", err)
+	}
+
+	id := es.issue(0, 1)
+	if _, err := es.FetchData(1, id); !errors.Is(err, guard.ErrPanic) {
+		t.Errorf("FetchData from the panicking member = %v, want guard.ErrPanic", err)
+	}
+}
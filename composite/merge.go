@@ -0,0 +1,254 @@
+package composite
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/dedupe"
+	"github.com/locus-search/datasource/rerank"
+	"github.com/locus-search/datasource/source"
+)
+
+// Found is what one member returned for a query, in the member's order
+type Found struct {
+	Member int    // Index into Members
+	Name   string // Member name
+	Source source.Source
+	Topics []datasource.DataSourceTopic
+}
+
+// Candidate is a topic in the merged ranking with the index of the member that found it
+type Candidate struct {
+	Topic  datasource.DataSourceTopic
+	Member int
+}
+
+// Merger ranks the topics all members found into one list, best first. The composite then drops
+// repeated topic IDs, applies the diversity caps and keeps count.
+type Merger interface {
+	Merge(input string, found []Found) []Candidate
+}
+
+// MergerFunc adapts a function to Merger
+type MergerFunc func(input string, found []Found) []Candidate
+
+func (f MergerFunc) Merge(input string, found []Found) []Candidate {
+	return f(input, found)
+}
+
+// Merger names for NewMerger, e.g. from a deployment's configuration
+const (
+	MergeRoundRobin = "round-robin"
+	MergeWeighted   = "weighted"
+	MergeNormalized = "normalized"
+	MergeRRF        = "rrf"
+	MergeScore      = "score"
+)
+
+// NewMerger returns the named merge strategy. Weights by member name apply to the weighted,
+// normalized, rrf and score strategies; members without one weigh 1.
+func NewMerger(name string, weights map[string]float64) (Merger, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", MergeRoundRobin:
+		return RoundRobin{}, nil
+	case MergeWeighted:
+		return Weighted{Weights: weights}, nil
+	case MergeNormalized:
+		return Normalized{Weights: weights}, nil
+	case MergeRRF:
+		return RRF{Weights: weights}, nil
+	case MergeScore:
+		return Scored{Weights: weights}, nil
+	}
+	return nil, fmt.Errorf("unknown merge strategy %q; available: %s, %s, %s, %s, %s", name, MergeRoundRobin, MergeWeighted, MergeNormalized, MergeRRF, MergeScore)
+}
+
+// RoundRobin takes each member's first topic in member order, then each member's second, and so on
+type RoundRobin struct{}
+
+func (RoundRobin) Merge(input string, found []Found) []Candidate {
+	var candidates []Candidate
+	for i := 0; ; i++ {
+		added := false
+		for _, f := range found {
+			if i < len(f.Topics) {
+				candidates = append(candidates, Candidate{Topic: f.Topics[i], Member: f.Member})
+				added = true
+			}
+		}
+		if !added {
+			return candidates
+		}
+	}
+}
+
+// Weighted ranks each topic by its member's weight divided by its rank in the member's list, so a member
+// weighing 2 places about twice as many topics near the top as one weighing 1
+type Weighted struct {
+	Weights map[string]float64 // By member name; default 1
+}
+
+func (w Weighted) Merge(input string, found []Found) []Candidate {
+	var scored []scoredCandidate
+	for _, f := range found {
+		weight := weightOf(w.Weights, f.Name)
+		for rank, t := range f.Topics {
+			scored = append(scored, scoredCandidate{Candidate{Topic: t, Member: f.Member}, weight / float64(rank+1)})
+		}
+	}
+	return sortScored(scored)
+}
+
+// Normalized ranks topics by relevance to the query, scaled to 0..1 within each member so members whose
+// titles score high in general do not crowd out the others, then multiplied by the member's weight
+type Normalized struct {
+	Weights map[string]float64 // By member name; default 1
+	// Score returns a relevance score for each topic; default BM25 of titles and URLs, see rerank
+	Score func(query string, topics []datasource.DataSourceTopic) []float64
+}
+
+func (n Normalized) Merge(input string, found []Found) []Candidate {
+	score := n.Score
+	if score == nil {
+		score = rerank.New(nil).Scores
+	}
+	// Scores are computed over all topics at once, so term rarity is judged across members
+	var all []datasource.DataSourceTopic
+	for _, f := range found {
+		all = append(all, f.Topics...)
+	}
+	scores := score(input, all)
+	var scored []scoredCandidate
+	offset := 0
+	for _, f := range found {
+		member := scores[offset : offset+len(f.Topics)]
+		offset += len(f.Topics)
+		lo, hi := 0.0, 0.0
+		for i, s := range member {
+			if i == 0 || s < lo {
+				lo = s
+			}
+			if i == 0 || s > hi {
+				hi = s
+			}
+		}
+		weight := weightOf(n.Weights, f.Name)
+		for i, t := range f.Topics {
+			normalized := 1.0
+			if hi > lo {
+				normalized = (member[i] - lo) / (hi - lo)
+			}
+			scored = append(scored, scoredCandidate{Candidate{Topic: t, Member: f.Member}, weight * normalized})
+		}
+	}
+	return sortScored(scored)
+}
+
+// RRF is reciprocal rank fusion: a page's score is the sum over the members that found it of weight/(K +
+// rank). Topics with the same canonical URL count as one page, kept from the member that ranked it best.
+type RRF struct {
+	K       float64            // Rank offset damping the top ranks; default 60
+	Weights map[string]float64 // By member name; default 1
+}
+
+func (r RRF) Merge(input string, found []Found) []Candidate {
+	k := r.K
+	if k <= 0 {
+		k = 60
+	}
+	type fused struct {
+		best  Candidate
+		rank  int
+		score float64
+		order int
+	}
+	pages := map[string]*fused{}
+	for _, f := range found {
+		weight := weightOf(r.Weights, f.Name)
+		for rank, t := range f.Topics {
+			key := pageKey(f.Member, t)
+			p, ok := pages[key]
+			if !ok {
+				p = &fused{best: Candidate{Topic: t, Member: f.Member}, rank: rank, order: len(pages)}
+				pages[key] = p
+			} else if rank < p.rank {
+				p.best, p.rank = Candidate{Topic: t, Member: f.Member}, rank
+			}
+			p.score += weight / (k + float64(rank+1))
+		}
+	}
+	ranked := make([]*fused, 0, len(pages))
+	for _, p := range pages {
+		ranked = append(ranked, p)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].order < ranked[j].order
+	})
+	candidates := make([]Candidate, len(ranked))
+	for i, p := range ranked {
+		candidates[i] = p.best
+	}
+	return candidates
+}
+
+// Scored ranks topics by the relevance score their members report, see source.TopicDetails, multiplied by
+// the member's weight. Topics without a score get the score of their rank, source.RankScore.
+type Scored struct {
+	Weights map[string]float64 // By member name; default 1
+}
+
+func (sc Scored) Merge(input string, found []Found) []Candidate {
+	var scored []scoredCandidate
+	for _, f := range found {
+		weight := weightOf(sc.Weights, f.Name)
+		for rank, t := range f.Topics {
+			score := source.RankScore(rank)
+			if f.Source != nil {
+				if details, ok := source.Details(f.Source, t.TopicID); ok && details.Score > 0 {
+					score = details.Score
+				}
+			}
+			scored = append(scored, scoredCandidate{Candidate{Topic: t, Member: f.Member}, weight * score})
+		}
+	}
+	return sortScored(scored)
+}
+
+type scoredCandidate struct {
+	Candidate
+	score float64
+}
+
+// sortScored orders by descending score; ties keep member order, then rank
+func sortScored(scored []scoredCandidate) []Candidate {
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+	candidates := make([]Candidate, len(scored))
+	for i, s := range scored {
+		candidates[i] = s.Candidate
+	}
+	return candidates
+}
+
+func weightOf(weights map[string]float64, name string) float64 {
+	if w, ok := weights[name]; ok && w >= 0 {
+		return w
+	}
+	return 1
+}
+
+// pageKey identifies a topic's page across members: its canonical URL, or without one its member and
+// topic ID, since members' IDs overlap
+func pageKey(member int, t datasource.DataSourceTopic) string {
+	if t.SourceURL != "" {
+		return dedupe.CanonicalURL(t.SourceURL)
+	}
+	return "#" + strconv.Itoa(member) + ":" + strconv.FormatInt(t.TopicID, 10)
+}
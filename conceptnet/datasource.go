@@ -0,0 +1,304 @@
+package conceptnet
+
+// Data Source Adapter for ConceptNet commonsense relations
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultEdgeCount  = 10
+	// Edges fetched per concept; popular concepts have thousands, most of them low-weight
+	edgeLimit = 500
+)
+
+type DataSourceConceptNet struct {
+	Client    *http.Client
+	BaseURL   string
+	UserAgent string
+	Language  string   // Concept language, e.g. "en"; edges to other languages are skipped
+	Relations []string // Relations offered as topics, without the /r/ prefix
+
+	relations topicid.Map[relation]
+}
+
+// relation is the set of edges of one type around a concept
+type relation struct {
+	Concept string
+	Rel     string
+	Edges   []Edge
+}
+
+// Edge is one ConceptNet assertion, e.g. "dog" IsA "pet"
+type Edge struct {
+	Start       string  // Label of the start node
+	Rel         string  // Relation name, e.g. "IsA"
+	End         string  // Label of the end node
+	Weight      float64 // Confidence; 1.0 is a typical single-source assertion
+	SurfaceText string  // Natural-language form from the source, with the terms in [[brackets]]
+}
+
+type node struct {
+	Label    string `json:"label"`
+	Language string `json:"language"`
+}
+
+func New() *DataSourceConceptNet {
+	return &DataSourceConceptNet{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://api.conceptnet.io",
+		UserAgent: "locus/conceptnet-datasource",
+		Language:  "en",
+		Relations: []string{"IsA", "UsedFor", "RelatedTo", "PartOf", "HasA", "CapableOf", "AtLocation", "Synonym"},
+	}
+}
+
+// Init implements models.DataSource
+func (es *DataSourceConceptNet) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.Language == "" {
+		es.Language = "en"
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceConceptNet) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	var response struct{}
+	return es.doJSON(ctx, "/c/"+es.Language+"/dog", url.Values{"limit": {"1"}}, &response) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceConceptNet) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "ConceptNet",
+		Homepage:            "https://conceptnet.io",
+		License:             "CC-BY-SA-4.0",
+		LicenseURL:          "https://creativecommons.org/licenses/by-sa/4.0/",
+		Attribution:         "ConceptNet 5 (https://conceptnet.io), CC BY-SA 4.0",
+		AttributionRequired: true,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each relation the concept takes part in is a topic, e.g. "dog IsA (24 edges)"; relations are ordered
+// as configured in Relations
+func (es *DataSourceConceptNet) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for ConceptNet DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	edges, err := es.Edges(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	grouped := map[string][]Edge{}
+	for _, e := range edges {
+		grouped[e.Rel] = append(grouped[e.Rel], e)
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, rel := range es.Relations {
+		if len(results) >= count {
+			break
+		}
+		group := grouped[rel]
+		if len(group) == 0 {
+			continue
+		}
+		r := relation{Concept: query, Rel: rel, Edges: group}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s %s (%d edges)", query, rel, len(group)),
+			SourceURL: es.conceptLink(query),
+			Site:      "conceptnet.io",
+			TopicID:   es.relations.Put(es.Language+"/"+conceptTerm(query)+"/"+rel, r),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the relation's edges, strongest first, one per item as "start Rel end (weight)" with the
+// source's surface text when there is one
+func (es *DataSourceConceptNet) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	r, ok := es.relations.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown ConceptNet topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultEdgeCount
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, e := range r.Edges {
+		if len(results) >= count {
+			break
+		}
+		text := fmt.Sprintf("%s %s %s (weight %s)", e.Start, e.Rel, e.End, strconv.FormatFloat(e.Weight, 'f', 2, 64))
+		if e.SurfaceText != "" {
+			text += "\n" + strings.NewReplacer("[[", "", "]]", "").Replace(e.SurfaceText)
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  text,
+			SourceURL: es.conceptLink(r.Concept),
+			Site:      "conceptnet.io",
+			AnswerID:  topicid.Hash(e.Start + "/" + e.Rel + "/" + e.End),
+		})
+	}
+	return results, nil
+}
+
+// Edges returns the concept's edges within the configured language, strongest first
+func (es *DataSourceConceptNet) Edges(ctx context.Context, term string) ([]Edge, error) {
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+	var response struct {
+		Edges []struct {
+			Rel struct {
+				Label string `json:"label"`
+			} `json:"rel"`
+			Start       node    `json:"start"`
+			End         node    `json:"end"`
+			Weight      float64 `json:"weight"`
+			SurfaceText string  `json:"surfaceText"`
+		} `json:"edges"`
+	}
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(edgeLimit))
+	if err := es.doJSON(ctx, "/c/"+es.Language+"/"+url.PathEscape(conceptTerm(term)), params, &response); err != nil {
+		return nil, err
+	}
+
+	edges := make([]Edge, 0, len(response.Edges))
+	for _, e := range response.Edges {
+		if e.Start.Language != es.Language || e.End.Language != es.Language {
+			continue
+		}
+		edges = append(edges, Edge{
+			Start:       e.Start.Label,
+			Rel:         e.Rel.Label,
+			End:         e.End.Label,
+			Weight:      e.Weight,
+			SurfaceText: e.SurfaceText,
+		})
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i].Weight > edges[j].Weight
+	})
+	return edges, nil
+}
+
+// ExpandQuery returns up to max terms closely related to term (synonyms, hypernyms and related
+// concepts, strongest first), for callers that broaden a search before querying other sources
+func (es *DataSourceConceptNet) ExpandQuery(term string, max int) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	edges, err := es.Edges(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+	self := strings.ToLower(strings.TrimSpace(term))
+	seen := map[string]bool{self: true}
+	var terms []string
+	for _, e := range edges {
+		if max > 0 && len(terms) >= max {
+			break
+		}
+		switch e.Rel {
+		case "Synonym", "IsA", "RelatedTo", "SimilarTo":
+		default:
+			continue
+		}
+		// The concept may be either end of the edge
+		other := e.End
+		if strings.ToLower(other) == self {
+			other = e.Start
+		}
+		key := strings.ToLower(other)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, other)
+	}
+	return terms, nil
+}
+
+// doJSON performs an API request and decodes the JSON response into target
+func (es *DataSourceConceptNet) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("conceptnet request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+
+// conceptTerm converts text to ConceptNet's URI form: lowercase with underscores for spaces
+func conceptTerm(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), "_")
+}
+
+func (es *DataSourceConceptNet) conceptLink(term string) string {
+	return "https://conceptnet.io/c/" + es.Language + "/" + url.PathEscape(conceptTerm(term))
+}
@@ -0,0 +1,84 @@
+package conceptnet
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/c/en/hot_dog", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "500" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"edges":[
+			{"rel":{"label":"IsA"},"start":{"label":"hot dog","language":"en"},"end":{"label":"sandwich","language":"en"},"weight":1.0,"surfaceText":"[[a hot dog]] is [[a sandwich]]"},
+			{"rel":{"label":"IsA"},"start":{"label":"hot dog","language":"en"},"end":{"label":"food","language":"en"},"weight":2.5},
+			{"rel":{"label":"Synonym"},"start":{"label":"frankfurter","language":"en"},"end":{"label":"hot dog","language":"en"},"weight":1.2},
+			{"rel":{"label":"Synonym"},"start":{"label":"hot dog","language":"en"},"end":{"label":"perrito caliente","language":"es"},"weight":3.0},
+			{"rel":{"label":"AtLocation"},"start":{"label":"hot dog","language":"en"},"end":{"label":"ballpark","language":"en"},"weight":0.5},
+			{"rel":{"label":"Desires"},"start":{"label":"hot dog","language":"en"},"end":{"label":"mustard","language":"en"},"weight":0.5}
+		]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL + "/"
+
+	topics, err := es.FetchTopics(5, "Hot  Dog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Hot  Dog IsA (2 edges)", "Hot  Dog AtLocation (1 edges)", "Hot  Dog Synonym (1 edges)"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+	if topics[0].SourceURL != "https://conceptnet.io/c/en/hot_dog" {
+		t.Errorf("SourceURL = %q", topics[0].SourceURL)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := []string{
+		"hot dog IsA food (weight 2.50)",
+		"hot dog IsA sandwich (weight 1.00)\na hot dog is a sandwich",
+	}
+	if len(data) != len(wantData) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range wantData {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+}
+
+func TestExpandQuery(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+
+	terms, err := es.ExpandQuery("hot dog", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(terms, "|"); got != "food|frankfurter|sandwich" {
+		t.Errorf("ExpandQuery = %q", terms)
+	}
+}
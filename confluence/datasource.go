@@ -0,0 +1,320 @@
+package confluence
+
+// Data Source Adapter for Confluence (Cloud, Server or Data Center) pages, searched with CQL
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/localfs"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultDataCount  = 3
+	// Page text is returned in chunks of about this many characters
+	chunkSize = 1500
+)
+
+type DataSourceConfluence struct {
+	Client *http.Client
+	// Site root: "https://example.atlassian.net/wiki" for Cloud or "https://confluence.example.com" for
+	// Server and Data Center
+	BaseURL   string
+	UserAgent string
+
+	// Cloud authenticates with the account email and an API token; Server and Data Center with a
+	// personal access Token
+	Username string
+	APIToken string
+	Token    string
+
+	Spaces []string // Optional space keys to search, e.g. "ENG"
+	Types  []string // Content types; default "page"; "blogpost" adds blog posts
+	CQL    string   // Optional CQL ANDed with the search, e.g. `label = "runbook"`
+
+	Site string // Site for topics and data; default the BaseURL host
+
+	pages topicid.Map[page]
+}
+
+type page struct {
+	ID      string
+	Title   string
+	Space   string
+	URL     string
+	Excerpt string
+}
+
+func New() *DataSourceConfluence {
+	return &DataSourceConfluence{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		UserAgent: "locus/confluence-datasource",
+		Types:     []string{"page"},
+	}
+}
+
+// Init implements models.DataSource
+func (es *DataSourceConfluence) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if strings.TrimSpace(es.BaseURL) == "" {
+		return errors.New("BaseURL is required for Confluence DataSource")
+	}
+	if es.Username != "" && es.APIToken == "" {
+		return errors.New("APIToken is required with Username for Confluence DataSource")
+	}
+	if len(es.Types) == 0 {
+		es.Types = []string{"page"}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Lists one space, which also verifies the credentials
+func (es *DataSourceConfluence) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	var response struct{}
+	return es.doJSON(ctx, "/rest/api/space?limit=1", &response) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceConfluence) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Confluence",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Pages matching a CQL text search are topics titled "Title (Space)", in relevance order
+func (es *DataSourceConfluence) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Confluence DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("cql", es.cql(query))
+	params.Set("limit", strconv.Itoa(count))
+	params.Set("excerpt", "highlight")
+	params.Set("expand", "content.space")
+	var response struct {
+		Results []struct {
+			Content struct {
+				ID    string `json:"id"`
+				Title string `json:"title"`
+				Space struct {
+					Key  string `json:"key"`
+					Name string `json:"name"`
+				} `json:"space"`
+			} `json:"content"`
+			Title     string `json:"title"`
+			Excerpt   string `json:"excerpt"`
+			URL       string `json:"url"`
+			Container struct {
+				Title string `json:"title"`
+			} `json:"resultGlobalContainer"`
+		} `json:"results"`
+		Links struct {
+			Base string `json:"base"`
+		} `json:"_links"`
+	}
+	if err := es.doJSON(ctx, "/rest/api/search?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+	base := response.Links.Base
+	if base == "" {
+		base = strings.TrimRight(es.BaseURL, "/")
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Results))
+	for _, r := range response.Results {
+		if r.Content.ID == "" {
+			continue
+		}
+		p := page{
+			ID:      r.Content.ID,
+			Title:   r.Content.Title,
+			Space:   r.Content.Space.Name,
+			URL:     base + r.URL,
+			Excerpt: cleanExcerpt(r.Excerpt),
+		}
+		if p.Title == "" {
+			p.Title = strings.ReplaceAll(cleanExcerpt(r.Title), "**", "")
+		}
+		if p.Space == "" {
+			p.Space = r.Container.Title
+		}
+		topic := p.Title
+		if p.Space != "" {
+			topic += " (" + p.Space + ")"
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     topic,
+			SourceURL: p.URL,
+			Site:      es.site(),
+			TopicID:   es.pages.Put(base+"/"+p.ID, p),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the search excerpt, then the page body converted from storage format in chunks of about
+// 1500 characters, each starting with the title
+func (es *DataSourceConfluence) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	p, ok := es.pages.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Confluence topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultDataCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var content struct {
+		Body struct {
+			Storage struct {
+				Value string `json:"value"`
+			} `json:"storage"`
+		} `json:"body"`
+	}
+	if err := es.doJSON(ctx, "/rest/api/content/"+url.PathEscape(p.ID)+"?expand=body.storage", &content); err != nil {
+		return nil, err
+	}
+
+	texts := []string{}
+	if p.Excerpt != "" {
+		texts = append(texts, p.Excerpt)
+	}
+	texts = append(texts, localfs.Chunks(storageText(content.Body.Storage.Value), chunkSize)...)
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, text := range texts {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		if len(results) > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%s/%s#%d", es.BaseURL, p.ID, len(results)))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  p.Title + "\n" + text,
+			SourceURL: p.URL,
+			Site:      es.site(),
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// cql builds the search: a text match on the input, limited to the content types and spaces
+func (es *DataSourceConfluence) cql(query string) string {
+	clauses := []string{"text ~ " + quote(query)}
+	clauses = append(clauses, "type in ("+quoteAll(es.Types)+")")
+	if len(es.Spaces) > 0 {
+		clauses = append(clauses, "space in ("+quoteAll(es.Spaces)+")")
+	}
+	if es.CQL != "" {
+		clauses = append(clauses, "("+es.CQL+")")
+	}
+	return strings.Join(clauses, " AND ")
+}
+
+func (es *DataSourceConfluence) doJSON(ctx context.Context, path string, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(es.BaseURL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case es.Token != "":
+		req.Header.Set("Authorization", "Bearer "+es.Token)
+	case es.Username != "":
+		req.SetBasicAuth(es.Username, es.APIToken)
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("confluence request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func (es *DataSourceConfluence) site() string {
+	if es.Site != "" {
+		return es.Site
+	}
+	if u, err := url.Parse(es.BaseURL); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	return "confluence"
+}
+
+// Helpers
+
+// quote makes a CQL string literal
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// cleanExcerpt turns Confluence's highlight markers into "**" and unescapes the HTML entities excerpts
+// and titles come with
+func cleanExcerpt(s string) string {
+	s = strings.NewReplacer("@@@hl@@@", "**", "@@@endhl@@@", "**").Replace(s)
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
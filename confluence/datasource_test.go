@@ -0,0 +1,118 @@
+package confluence
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /wiki/rest/api/search", func(w http.ResponseWriter, r *http.Request) {
+		if user, token, _ := r.BasicAuth(); user != "me@example.com" || token != "api-token" {
+			t.Errorf("searched as %q", user)
+		}
+		q := r.URL.Query()
+		wantCQL := `text ~ "deploy \"blue\"" AND type in ("page", "blogpost") AND space in ("ENG") AND (label = "runbook")`
+		if q.Get("cql") != wantCQL || q.Get("limit") != "3" || q.Get("excerpt") != "highlight" || q.Get("expand") != "content.space" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"results":[
+			{"content":{"id":"123","title":"Deploy runbook","space":{"key":"ENG","name":"Engineering"}},
+				"title":"@@@hl@@@Deploy@@@endhl@@@ runbook","excerpt":"How to @@@hl@@@deploy@@@endhl@@@ the &quot;blue&quot; stack",
+				"url":"/spaces/ENG/pages/123/Deploy+runbook"},
+			{"content":{"id":"124"},"title":"@@@hl@@@Deploy@@@endhl@@@ notes &amp; tips","excerpt":"",
+				"url":"/spaces/ENG/blog/124","resultGlobalContainer":{"title":"Engineering blog"}},
+			{"title":"A space result without content","url":"/spaces/ENG"}
+		],"_links":{"base":"https://example.atlassian.net/wiki"}}`)
+	})
+	mux.HandleFunc("GET /wiki/rest/api/content/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("expand") != "body.storage" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"body":{"storage":{"value":"<p>Merge to main.</p><p>Watch the dashboards.</p>"}}}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL + "/wiki/"
+	es.Username = "me@example.com"
+	es.APIToken = "api-token"
+	es.Spaces = []string{"ENG"}
+	es.Types = []string{"page", "blogpost"}
+	es.CQL = `label = "runbook"`
+	es.Site = "wiki"
+
+	topics, err := es.FetchTopics(3, `deploy "blue"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Deploy runbook (Engineering)|https://example.atlassian.net/wiki/spaces/ENG/pages/123/Deploy+runbook",
+		"Deploy notes & tips (Engineering blog)|https://example.atlassian.net/wiki/spaces/ENG/blog/124",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].SourceURL; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := []string{
+		`Deploy runbook` + "\n" + `How to **deploy** the "blue" stack`,
+		"Deploy runbook\nMerge to main.\n\nWatch the dashboards.",
+	}
+	if len(data) != len(wantData) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, w := range wantData {
+		if data[i].DataText != w || data[i].Site != "wiki" {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, w)
+		}
+	}
+	if data[0].AnswerID != topics[0].TopicID || data[1].AnswerID == data[0].AnswerID {
+		t.Errorf("answer IDs %d, %d for topic %d", data[0].AnswerID, data[1].AnswerID, topics[0].TopicID)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/space" || r.Header.Get("Authorization") != "Bearer pat" {
+			t.Errorf("unexpected request %s with %q", r.URL, r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, `{"results":[]}`)
+	}))
+	defer srv.Close()
+	es := New()
+	es.BaseURL = srv.URL
+	es.Token = "pat"
+	if !es.CheckAvailability() {
+		t.Error("CheckAvailability = false")
+	}
+}
+
+func TestRequestFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"Could not parse cql"}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	es := New()
+	es.BaseURL = srv.URL
+	if _, err := es.FetchTopics(3, "deploy"); err == nil || !strings.Contains(err.Error(), "Could not parse cql") {
+		t.Errorf("FetchTopics = %v", err)
+	}
+}
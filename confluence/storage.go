@@ -0,0 +1,215 @@
+package confluence
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// Macros whose parameters or bodies are not page text
+var skippedMacros = map[string]bool{
+	"toc": true, "children": true, "pagetree": true, "recently-updated": true, "contentbylabel": true,
+	"jira": true, "attachments": true, "gallery": true, "include": true, "excerpt-include": true,
+}
+
+// Panel macros rendered with a label before their body
+var panelLabels = map[string]string{
+	"info": "Info", "note": "Note", "warning": "Warning", "tip": "Tip",
+}
+
+// HTML elements that may be left unclosed. xml.HTMLAutoClose is not used because it matches local
+// names only and lists "link", which would close every ac:link.
+var voidElements = []string{"br", "hr", "img", "col", "wbr"}
+
+// node is an element or text of a storage format document. Names keep their prefix, e.g. "ac:link".
+type node struct {
+	Name     string
+	Attrs    map[string]string
+	Text     string // Set on text nodes, which have no name
+	Children []*node
+}
+
+// storageText converts Confluence storage format (XHTML with ac: and ri: elements) to plain text
+// with a blank line between paragraphs. Code macros keep their line breaks, panels are prefixed with
+// their kind, links to other pages keep their link text or page title, and layout-only macros such
+// as the table of contents are dropped.
+func storageText(storage string) string {
+	root := parseStorage(storage)
+
+	var paragraphs []string
+	var current strings.Builder
+	label := "" // Panel label for the next paragraph
+	flush := func() {
+		if text := strings.TrimSpace(current.String()); text != "" {
+			if label != "" {
+				text = label + ": " + text
+				label = ""
+			}
+			paragraphs = append(paragraphs, text)
+		}
+		current.Reset()
+	}
+	write := func(text string) {
+		if text == "" {
+			return
+		}
+		if s := current.String(); s != "" && !strings.HasSuffix(s, "\n") && !strings.HasSuffix(s, " ") {
+			current.WriteByte(' ')
+		}
+		current.WriteString(text)
+	}
+
+	var walk func(n *node)
+	walk = func(n *node) {
+		if n.Name == "" {
+			write(strings.Join(strings.Fields(n.Text), " "))
+			return
+		}
+		switch n.Name {
+		case "ac:parameter", "ac:image", "ac:emoticon", "ac:placeholder", "script", "style":
+			return
+		case "ac:structured-macro", "ac:macro":
+			name := n.Attrs["ac:name"]
+			if skippedMacros[name] {
+				return
+			}
+			flush()
+			label = panelLabels[name]
+			for _, child := range n.Children {
+				walk(child)
+			}
+			flush()
+			label = ""
+			return
+		case "ac:plain-text-body":
+			flush()
+			if text := strings.Trim(n.text(), "\r\n"); strings.TrimSpace(text) != "" {
+				paragraphs = append(paragraphs, text)
+			}
+			return
+		case "ac:link":
+			// The link body is the shown text; a bare link shows the target page's title
+			text := ""
+			for _, child := range n.Children {
+				switch child.Name {
+				case "ac:link-body", "ac:plain-text-link-body":
+					text = child.text()
+				case "ri:page", "ri:blog-post":
+					if text == "" {
+						text = child.Attrs["ri:content-title"]
+					}
+				case "ri:attachment":
+					if text == "" {
+						text = child.Attrs["ri:filename"]
+					}
+				}
+			}
+			write(strings.Join(strings.Fields(text), " "))
+			return
+		case "ac:task":
+			flush()
+			mark := "- [ ]"
+			var body string
+			for _, child := range n.Children {
+				switch child.Name {
+				case "ac:task-status":
+					if strings.TrimSpace(child.text()) == "complete" {
+						mark = "- [x]"
+					}
+				case "ac:task-body":
+					body = strings.Join(strings.Fields(child.text()), " ")
+				}
+			}
+			write(mark + " " + body)
+			flush()
+			return
+		case "br":
+			current.WriteByte('\n')
+			return
+		}
+
+		block := isBlock(n.Name)
+		if block {
+			flush()
+		}
+		switch n.Name {
+		case "li":
+			write("-")
+		case "td", "th":
+			if current.Len() > 0 {
+				write("|")
+			}
+		}
+		for _, child := range n.Children {
+			walk(child)
+		}
+		if block {
+			flush()
+		}
+	}
+	walk(root)
+	flush()
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// parseStorage reads storage format into a tree. The decoder runs in non-strict mode, which accepts
+// the undeclared ac: and ri: prefixes, HTML entities such as &nbsp; and unclosed void elements.
+func parseStorage(storage string) *node {
+	decoder := xml.NewDecoder(strings.NewReader("<root>" + storage + "</root>"))
+	decoder.Strict = false
+	decoder.AutoClose = voidElements
+	decoder.Entity = xml.HTMLEntity
+
+	root := &node{Name: "root"}
+	stack := []*node{root}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// Whatever was read before a syntax error is kept
+			return root
+		}
+		parent := stack[len(stack)-1]
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &node{Name: qualified(t.Name), Attrs: map[string]string{}}
+			for _, a := range t.Attr {
+				n.Attrs[qualified(a.Name)] = a.Value
+			}
+			parent.Children = append(parent.Children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			parent.Children = append(parent.Children, &node{Text: string(t)})
+		}
+	}
+}
+
+// text returns the concatenated text below n
+func (n *node) text() string {
+	if n.Name == "" {
+		return n.Text
+	}
+	var b strings.Builder
+	for _, child := range n.Children {
+		b.WriteString(child.text())
+	}
+	return b.String()
+}
+
+func qualified(name xml.Name) string {
+	if name.Space == "" {
+		return strings.ToLower(name.Local)
+	}
+	return strings.ToLower(name.Space + ":" + name.Local)
+}
+
+func isBlock(tag string) bool {
+	switch tag {
+	case "p", "div", "h1", "h2", "h3", "h4", "h5", "h6", "li", "tr", "pre", "blockquote",
+		"table", "ul", "ol", "dl", "dt", "dd", "hr", "ac:rich-text-body", "ac:layout-cell":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package confluence
+
+import "testing"
+
+func TestStorageText(t *testing.T) {
+	storage := `<h1>Runbook</h1>
+<ac:structured-macro ac:name="toc"><ac:parameter ac:name="maxLevel">2</ac:parameter></ac:structured-macro>
+<p>Restart&nbsp;the <strong>worker</strong> first.<br/>Then check the queue.</p>
+<ac:structured-macro ac:name="warning"><ac:rich-text-body><p>Never drop the table.</p></ac:rich-text-body></ac:structured-macro>
+<ac:structured-macro ac:name="code"><ac:parameter ac:name="language">bash</ac:parameter><ac:plain-text-body><![CDATA[systemctl restart worker
+journalctl -u worker]]></ac:plain-text-body></ac:structured-macro>
+<p>See <ac:link><ri:page ri:content-title="Queue metrics"/></ac:link> and <ac:link><ri:page ri:content-title="Alerts"/><ac:plain-text-link-body><![CDATA[the alert list]]></ac:plain-text-link-body></ac:link></p>
+<ul><li>One</li><li>Two</li></ul>
+<table><tbody><tr><th>Host</th><th>Role</th></tr><tr><td>db1</td><td>primary</td></tr></tbody></table>
+<ac:task-list><ac:task><ac:task-status>complete</ac:task-status><ac:task-body>Page on-call</ac:task-body></ac:task></ac:task-list>`
+
+	want := "Runbook\n\n" +
+		"Restart the worker first.\nThen check the queue.\n\n" +
+		"Warning: Never drop the table.\n\n" +
+		"systemctl restart worker\njournalctl -u worker\n\n" +
+		"See Queue metrics and the alert list\n\n" +
+		"- One\n\n- Two\n\n" +
+		"Host | Role\n\ndb1 | primary\n\n" +
+		"- [x] Page on-call"
+	if got := storageText(storage); got != want {
+		t.Errorf("storageText =\n%s\n\nwant\n%s", got, want)
+	}
+}
+
+func TestStorageTextMalformed(t *testing.T) {
+	// Text before a syntax error is kept
+	if got := storageText("<p>Kept</p><p>Cut <b>off"); got != "Kept\n\nCut off" {
+		t.Errorf("storageText = %q", got)
+	}
+}
@@ -0,0 +1,317 @@
+package courtlistener
+
+// Data Source Adapter for CourtListener case law (REST API v4)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultExcerpt    = 6000
+)
+
+// opinionTypes maps CourtListener opinion type codes to readable labels
+var opinionTypes = map[string]string{
+	"010combined":          "Combined opinion",
+	"015unamimous":         "Unanimous opinion", // Spelled this way by the API
+	"020lead":              "Lead opinion",
+	"025plurality":         "Plurality opinion",
+	"030concurrence":       "Concurrence",
+	"035concurrenceinpart": "Concurrence in part",
+	"040dissent":           "Dissent",
+	"050addendum":          "Addendum",
+	"060remittitur":        "Remittitur",
+	"070rehearing":         "Rehearing",
+	"080onthemerits":       "On the merits",
+	"090onmotiontostrike":  "On motion to strike",
+}
+
+type DataSourceCourtListener struct {
+	Client       *http.Client
+	BaseURL      string
+	Token        string // API token; v4 opinion endpoints require authentication
+	UserAgent    string
+	Courts       []string // Court IDs to search, e.g. "scotus", "ca9", "cal"; empty means all courts
+	FiledAfter   string   // YYYY-MM-DD
+	FiledBefore  string   // YYYY-MM-DD
+	ExcerptBytes int      // Opinion text is cut at a paragraph boundary near this size
+
+	cases topicid.Map[caseRef]
+}
+
+// caseRef is an opinion cluster (one decision) and the opinions written in it
+type caseRef struct {
+	Name     string
+	Court    string
+	URL      string
+	Opinions []int64
+}
+
+func New() *DataSourceCourtListener {
+	return &DataSourceCourtListener{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:      "https://www.courtlistener.com",
+		UserAgent:    "locus/courtlistener-datasource",
+		ExcerptBytes: defaultExcerpt,
+	}
+}
+
+// Init implements models.DataSource
+// CourtListener requires no initialization; a Token is needed for FetchData
+func (es *DataSourceCourtListener) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceCourtListener) CheckAvailability() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	return es.doJSON(ctx, "/api/rest/v4/", nil, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceCourtListener) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "CourtListener",
+		Homepage:    "https://www.courtlistener.com",
+		License:     "Public domain",
+		Attribution: "CourtListener, Free Law Project",
+		TermsURL:    "https://www.courtlistener.com/terms/",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Searches opinions; each decision is a topic titled "Case Name, citation (court, date filed)"
+func (es *DataSourceCourtListener) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for CourtListener DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("type", "o")
+	params.Set("order_by", "score desc")
+	if len(es.Courts) > 0 {
+		params.Set("court", strings.Join(es.Courts, " "))
+	}
+	if es.FiledAfter != "" {
+		params.Set("filed_after", es.FiledAfter)
+	}
+	if es.FiledBefore != "" {
+		params.Set("filed_before", es.FiledBefore)
+	}
+
+	var response struct {
+		Results []struct {
+			ClusterID   int64    `json:"cluster_id"`
+			CaseName    string   `json:"caseName"`
+			Court       string   `json:"court"`
+			CourtID     string   `json:"court_id"`
+			DateFiled   string   `json:"dateFiled"`
+			Citation    []string `json:"citation"`
+			AbsoluteURL string   `json:"absolute_url"`
+			Opinions    []struct {
+				ID int64 `json:"id"`
+			} `json:"opinions"`
+		} `json:"results"`
+	}
+	if err := es.doJSON(ctx, "/api/rest/v4/search/", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, hit := range response.Results {
+		if len(results) >= count {
+			break
+		}
+		ref := caseRef{
+			Name:  hit.CaseName,
+			Court: hit.CourtID,
+			URL:   strings.TrimRight(es.BaseURL, "/") + hit.AbsoluteURL,
+		}
+		for _, opinion := range hit.Opinions {
+			ref.Opinions = append(ref.Opinions, opinion.ID)
+		}
+		title := hit.CaseName
+		if len(hit.Citation) > 0 {
+			title += ", " + hit.Citation[0]
+		}
+		date := strings.SplitN(hit.DateFiled, "T", 2)[0]
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s (%s, %s)", title, hit.Court, date),
+			SourceURL: ref.URL,
+			Site:      hit.CourtID,
+			TopicID:   es.cases.Put(strconv.FormatInt(hit.ClusterID, 10), ref),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns an excerpt of each opinion in the decision (lead, concurrences, dissents), up to count
+func (es *DataSourceCourtListener) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	ref, ok := es.cases.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown CourtListener topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if es.Token == "" {
+		return nil, errors.New("Token is required to fetch CourtListener opinions")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, id := range ref.Opinions {
+		if len(results) >= count {
+			break
+		}
+		var opinion struct {
+			Type              string `json:"type"`
+			AuthorStr         string `json:"author_str"`
+			PlainText         string `json:"plain_text"`
+			HTMLWithCitations string `json:"html_with_citations"`
+			HTML              string `json:"html"`
+			HTMLLawbox        string `json:"html_lawbox"`
+			XMLHarvard        string `json:"xml_harvard"`
+		}
+		if err := es.doJSON(ctx, fmt.Sprintf("/api/rest/v4/opinions/%d/", id), nil, &opinion); err != nil {
+			if len(results) > 0 {
+				break
+			}
+			return nil, err
+		}
+
+		text := strings.TrimSpace(opinion.PlainText)
+		// Older opinions carry only one of the markup formats
+		for _, markup := range []string{opinion.HTMLWithCitations, opinion.HTML, opinion.HTMLLawbox, opinion.XMLHarvard} {
+			if text != "" {
+				break
+			}
+			text = markupToText(markup)
+		}
+		if text == "" {
+			continue
+		}
+
+		label := opinionTypes[opinion.Type]
+		if label == "" {
+			label = "Opinion"
+		}
+		if opinion.AuthorStr != "" {
+			label += " by " + opinion.AuthorStr
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  fmt.Sprintf("%s — %s\n\n%s", ref.Name, label, excerpt(text, es.ExcerptBytes)),
+			SourceURL: ref.URL,
+			Site:      ref.Court,
+			AnswerID:  id,
+		})
+	}
+	return results, nil
+}
+
+// doJSON performs an HTTP GET request against the CourtListener API and decodes the JSON response into target
+func (es *DataSourceCourtListener) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	if es.Token != "" {
+		req.Header.Set("Authorization", "Token "+es.Token)
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("courtlistener request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+
+// markupToText flattens opinion HTML or Harvard XML to paragraphs
+func markupToText(markup string) string {
+	if strings.TrimSpace(markup) == "" {
+		return ""
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(markup))
+	if err != nil {
+		return ""
+	}
+	var paragraphs []string
+	doc.Find("p, blockquote, h1, h2, h3, h4").Each(func(_ int, s *goquery.Selection) {
+		if text := strings.Join(strings.Fields(s.Text()), " "); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	})
+	if len(paragraphs) == 0 {
+		return strings.Join(strings.Fields(doc.Text()), " ")
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// excerpt shortens text to about limit bytes, cutting at the last paragraph break before the limit
+func excerpt(text string, limit int) string {
+	if limit <= 0 {
+		limit = defaultExcerpt
+	}
+	if len(text) <= limit {
+		return text
+	}
+	cut := strings.LastIndex(text[:limit], "\n\n")
+	if cut < limit/2 {
+		cut = strings.LastIndex(text[:limit], " ")
+	}
+	if cut <= 0 {
+		cut = limit
+	}
+	return strings.TrimSpace(text[:cut]) + " …"
+}
@@ -0,0 +1,99 @@
+package courtlistener
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/rest/v4/search/", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "miranda" || q.Get("type") != "o" || q.Get("court") != "scotus ca9" || q.Get("filed_after") != "1960-01-01" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"results":[{"cluster_id":107252,"caseName":"Miranda v. Arizona","court":"Supreme Court of the United States","court_id":"scotus","dateFiled":"1966-06-13T00:00:00-07:00","citation":["384 U.S. 436"],"absolute_url":"/opinion/107252/miranda-v-arizona/","opinions":[{"id":1},{"id":2},{"id":3}]}]}`)
+	})
+	mux.HandleFunc("/api/rest/v4/opinions/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token secret" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		switch strings.TrimPrefix(r.URL.Path, "/api/rest/v4/opinions/") {
+		case "1/":
+			fmt.Fprint(w, `{"type":"020lead","author_str":"Warren","plain_text":"The prosecution may not use statements."}`)
+		case "2/":
+			fmt.Fprint(w, `{"type":"040dissent","author_str":"Harlan","plain_text":"","html_with_citations":"<div><p>I  dissent.</p><p>Second paragraph.</p></div>"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSource(t *testing.T) *DataSourceCourtListener {
+	t.Helper()
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.Token = "secret"
+	es.Courts = []string{"scotus", "ca9"}
+	es.FiledAfter = "1960-01-01"
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "miranda")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Miranda v. Arizona, 384 U.S. 436 (Supreme Court of the United States, 1966-06-13)" ||
+		topics[0].SourceURL != es.BaseURL+"/opinion/107252/miranda-v-arizona/" || topics[0].Site != "scotus" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	// The third opinion fails after two were fetched, which ends the results rather than failing them
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Miranda v. Arizona — Lead opinion by Warren\n\nThe prosecution may not use statements.",
+		"Miranda v. Arizona — Dissent by Harlan\n\nI dissent.\n\nSecond paragraph.",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text || data[i].AnswerID != int64(i+1) {
+			t.Errorf("data %d = %+v, want %q", i, data[i], text)
+		}
+	}
+}
+
+func TestFetchDataRequiresToken(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "miranda")
+	if err != nil || len(topics) == 0 {
+		t.Fatalf("FetchTopics = %+v, %v", topics, err)
+	}
+	es.Token = ""
+	if _, err := es.FetchData(5, topics[0].TopicID); err == nil {
+		t.Error("FetchData succeeded without a token")
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	text := strings.Repeat("a", 60) + "\n\n" + strings.Repeat("b", 60)
+	if got := excerpt(text, 100); got != strings.Repeat("a", 60)+" …" {
+		t.Errorf("excerpt = %q", got)
+	}
+	if got := excerpt("short", 100); got != "short" {
+		t.Errorf("excerpt = %q", got)
+	}
+}
@@ -0,0 +1,283 @@
+package crates
+
+// Data Source Adapter for the crates.io registry API
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultTopicCount = 5
+	recentVersions    = 5
+)
+
+type DataSourceCrates struct {
+	Client    *http.Client
+	BaseURL   string
+	UserAgent string // crates.io requires an identifying agent with contact details
+
+	crates      topicid.Map[string]
+	rateLimiter *rate.Limiter
+}
+
+func New() *DataSourceCrates {
+	return &DataSourceCrates{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://crates.io/api/v1",
+		UserAgent: "locus/crates-datasource (https://github.com/locus-search/datasource)",
+	}
+}
+
+// Init implements models.DataSource
+// Applies the crates.io crawler policy of at most one request per second
+func (es *DataSourceCrates) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.UserAgent == "" {
+		return errors.New("UserAgent is required for crates.io DataSource")
+	}
+	if es.rateLimiter == nil {
+		es.rateLimiter = rate.NewLimiter(rate.Limit(1), 1)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceCrates) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	return es.doJSON(ctx, "/summary", nil, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceCrates) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "crates.io",
+		Homepage: "https://crates.io",
+		TermsURL: "https://crates.io/policies",
+	}
+}
+
+// Describe implements source.Describer
+func (es *DataSourceCrates) Describe() source.Capabilities {
+	return source.Capabilities{Name: es.Info().Name, Data: true, RateLimit: source.RateLimit{Requests: 1, Per: time.Second}}
+}
+
+// FetchTopics implements models.DataSource
+// Each matching crate is a topic titled "name: description"
+func (es *DataSourceCrates) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for crates.io DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("per_page", strconv.Itoa(count))
+
+	var response struct {
+		Crates []struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"crates"`
+	}
+	if err := es.doJSON(ctx, "/crates", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Crates))
+	for _, item := range response.Crates {
+		title := item.Name
+		if description := normalizeWhitespace(item.Description); description != "" {
+			title += ": " + description
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: crateLink(item.Name),
+			TopicID:   es.crates.Put(item.Name, item.Name),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns a summary (description, downloads, recent versions, links) followed by the README of the newest version
+func (es *DataSourceCrates) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	name, ok := es.crates.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown crates.io topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var response struct {
+		Crate struct {
+			Description     string `json:"description"`
+			Downloads       int64  `json:"downloads"`
+			RecentDownloads int64  `json:"recent_downloads"`
+			MaxVersion      string `json:"max_version"`
+			MaxStable       string `json:"max_stable_version"`
+			Repository      string `json:"repository"`
+			Documentation   string `json:"documentation"`
+		} `json:"crate"`
+		Versions []struct {
+			Num       string `json:"num"`
+			CreatedAt string `json:"created_at"`
+			Yanked    bool   `json:"yanked"`
+		} `json:"versions"`
+	}
+	if err := es.doJSON(ctx, "/crates/"+url.PathEscape(name), nil, &response); err != nil {
+		return nil, err
+	}
+
+	crate := response.Crate
+	var summary strings.Builder
+	summary.WriteString(normalizeWhitespace(crate.Description))
+	fmt.Fprintf(&summary, "\n\nDownloads: %d total, %d in the last 90 days", crate.Downloads, crate.RecentDownloads)
+	var versions []string
+	for _, version := range response.Versions {
+		if len(versions) >= recentVersions {
+			break
+		}
+		if version.Yanked {
+			continue
+		}
+		versions = append(versions, fmt.Sprintf("%s (%s)", version.Num, strings.SplitN(version.CreatedAt, "T", 2)[0]))
+	}
+	if len(versions) > 0 {
+		summary.WriteString("\nRecent versions: " + strings.Join(versions, ", "))
+	}
+	if crate.Repository != "" {
+		summary.WriteString("\nRepository: " + crate.Repository)
+	}
+	if crate.Documentation != "" {
+		summary.WriteString("\nDocumentation: " + crate.Documentation)
+	}
+	results := []datasource.DataSourceData{{
+		DataText:  strings.TrimSpace(summary.String()),
+		SourceURL: crateLink(name),
+		AnswerID:  topicID,
+	}}
+
+	version := crate.MaxStable
+	if version == "" {
+		version = crate.MaxVersion
+	}
+	if count == 1 || version == "" {
+		return results, nil
+	}
+	// Many crates have no README; that is not an error for the summary already collected
+	if readme, err := es.fetchReadme(ctx, name, version); err == nil && readme != "" {
+		results = append(results, datasource.DataSourceData{
+			DataText:  readme,
+			SourceURL: crateLink(name) + "/" + version,
+			AnswerID:  topicid.Hash(name + "@" + version + "#readme"),
+		})
+	}
+	return results, nil
+}
+
+// fetchReadme loads the rendered README for a crate version and flattens it to text
+func (es *DataSourceCrates) fetchReadme(ctx context.Context, name, version string) (string, error) {
+	body, err := es.get(ctx, fmt.Sprintf("/crates/%s/%s/readme", url.PathEscape(name), url.PathEscape(version)), nil)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return "", err
+	}
+	var blocks []string
+	doc.Find("h1, h2, h3, h4, p, li, pre").Each(func(_ int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "pre" {
+			blocks = append(blocks, strings.TrimSpace(s.Text()))
+			return
+		}
+		if text := normalizeWhitespace(s.Text()); text != "" {
+			blocks = append(blocks, text)
+		}
+	})
+	return strings.Join(blocks, "\n\n"), nil
+}
+
+// doJSON performs a rate-limited HTTP GET request against the crates.io API and decodes the JSON response into target
+func (es *DataSourceCrates) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	body, err := es.get(ctx, path, params)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(target)
+}
+
+// get waits for the rate limiter, then performs an HTTP GET request. The caller must close the returned body.
+func (es *DataSourceCrates) get(ctx context.Context, path string, params url.Values) (io.ReadCloser, error) {
+	if err := es.rateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", es.UserAgent)
+
+	resp, err := es.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("crates.io request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// Helpers
+func crateLink(name string) string {
+	return "https://crates.io/crates/" + url.PathEscape(name)
+}
+
+func normalizeWhitespace(in string) string {
+	return strings.Join(strings.Fields(in), " ")
+}
@@ -0,0 +1,75 @@
+package crates
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func newSource(t *testing.T) *DataSourceCrates {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/crates", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "serde" || r.URL.Query().Get("per_page") != "2" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"crates":[{"name":"serde","description":"A generic serialization/deserialization\n framework"},{"name":"serde_x","description":""}]}`)
+	})
+	mux.HandleFunc("/crates/serde", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"crate":{"description":"Serialization","downloads":100,"recent_downloads":10,"max_version":"2.0.0-rc","max_stable_version":"1.0.1","repository":"https://github.com/serde-rs/serde"},
+			"versions":[{"num":"2.0.0-rc","created_at":"2024-03-01T00:00:00Z"},{"num":"1.0.2","yanked":true},{"num":"1.0.1","created_at":"2024-01-01T00:00:00Z"}]}`)
+	})
+	mux.HandleFunc("/crates/serde/1.0.1/readme", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<h1>Serde</h1><p>Serde is a  framework.</p><pre>let x = 1;</pre>`)
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("request sent without a User-Agent")
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	es := New()
+	es.BaseURL = srv.URL
+	es.rateLimiter = rate.NewLimiter(rate.Inf, 1)
+	return es
+}
+
+func TestFetchTopics(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(2, "serde")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[0].Topic != "serde: A generic serialization/deserialization framework" ||
+		topics[0].SourceURL != "https://crates.io/crates/serde" || topics[1].Topic != "serde_x" {
+		t.Errorf("FetchTopics = %+v", topics)
+	}
+}
+
+func TestFetchData(t *testing.T) {
+	es := newSource(t)
+	topicID := es.crates.Put("serde", "serde")
+	data, err := es.FetchData(2, topicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	summary := "Serialization\n\nDownloads: 100 total, 10 in the last 90 days\nRecent versions: 2.0.0-rc (2024-03-01), 1.0.1 (2024-01-01)\nRepository: https://github.com/serde-rs/serde"
+	if data[0].DataText != summary {
+		t.Errorf("summary = %q, want %q", data[0].DataText, summary)
+	}
+	if data[1].DataText != "Serde\n\nSerde is a framework.\n\nlet x = 1;" || data[1].SourceURL != "https://crates.io/crates/serde/1.0.1" {
+		t.Errorf("readme = %+v", data[1])
+	}
+
+	if data, err := es.FetchData(1, topicID); err != nil || len(data) != 1 {
+		t.Errorf("FetchData(1) = %+v, %v, want only the summary", data, err)
+	}
+}
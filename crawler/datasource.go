@@ -0,0 +1,420 @@
+package crawler
+
+// Data Source Adapter that crawls a set of seed URLs into an in-memory index and searches it
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/chunker"
+	"github.com/locus-search/datasource/extract"
+	"github.com/locus-search/datasource/internal/robots"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultDataCount  = 3
+	// Pages larger than this are truncated before parsing
+	maxPageSize = 5 << 20
+)
+
+type DataSourceCrawler struct {
+	Client       *http.Client
+	Seeds        []string      // Start URLs
+	MaxDepth     int           // Link hops followed from a seed; default 2
+	MaxPages     int           // Pages indexed per crawl; default 100
+	SameHost     bool          // Only follow links to the seeds' hosts; default true
+	Delay        time.Duration // Minimum pause between requests to one host; robots.txt Crawl-delay can raise it
+	CrawlTimeout time.Duration // Deadline for a crawl started by FetchTopics; default 2 minutes
+	MaxAge       time.Duration // Age after which the next FetchTopics recrawls; 0 keeps the first crawl
+	UserAgent    string
+	Extractor    *extract.Extractor // Site overrides and quality threshold; default extract.Default
+	Chunker      *chunker.Chunker   // Splits page text into data items; default chunker.Default
+
+	robots  *robots.Checker
+	mu      sync.Mutex
+	index   []page
+	crawled time.Time
+	pages   topicid.Map[page]
+}
+
+// page is one indexed document
+type page struct {
+	URL        string
+	Title      string
+	Paragraphs []string
+	title      map[string]int // Word counts of the title
+	body       map[string]int // Word counts of the text
+}
+
+func New() *DataSourceCrawler {
+	return &DataSourceCrawler{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		MaxDepth:     2,
+		MaxPages:     100,
+		SameHost:     true,
+		Delay:        time.Second,
+		CrawlTimeout: 2 * time.Minute,
+		UserAgent:    "locus/crawler-datasource",
+	}
+}
+
+// Init implements models.DataSource
+func (es *DataSourceCrawler) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if len(es.Seeds) == 0 {
+		return errors.New("Seeds are required for Crawler DataSource")
+	}
+	for _, seed := range es.Seeds {
+		u, err := url.Parse(seed)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid seed URL %q", seed)
+		}
+	}
+	if es.MaxDepth < 0 {
+		es.MaxDepth = 0
+	}
+	if es.MaxPages <= 0 {
+		es.MaxPages = 100
+	}
+	if es.CrawlTimeout <= 0 {
+		es.CrawlTimeout = 2 * time.Minute
+	}
+	if es.robots == nil {
+		es.robots = robots.NewChecker(es.Client, es.UserAgent)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Reports whether the first seed can be fetched
+func (es *DataSourceCrawler) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, es.Seeds[0], nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("User-Agent", es.UserAgent)
+	resp, err := es.Client.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode < 500
+}
+
+// Info implements source.Informer
+func (es *DataSourceCrawler) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name: "Crawler",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Crawls on first use (or once the index is older than MaxAge) and ranks pages by query words in the
+// title, weighted three times, and the text. Call Crawl beforehand to keep searches fast.
+func (es *DataSourceCrawler) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Crawler DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	es.mu.Lock()
+	stale := es.crawled.IsZero() || (es.MaxAge > 0 && time.Since(es.crawled) > es.MaxAge)
+	es.mu.Unlock()
+	if stale {
+		ctx, cancel := context.WithTimeout(context.Background(), es.CrawlTimeout)
+		defer cancel()
+		if err := es.Crawl(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	es.mu.Lock()
+	index := es.index
+	es.mu.Unlock()
+	terms := words(query)
+	type scored struct {
+		page
+		score int
+	}
+	var matches []scored
+	for _, p := range index {
+		score := 0
+		for _, term := range terms {
+			score += 3*p.title[term] + p.body[term]
+		}
+		if score > 0 {
+			matches = append(matches, scored{p, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, m := range matches {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     m.Title,
+			SourceURL: m.URL,
+			Site:      host(m.URL),
+			TopicID:   es.pages.Put(m.URL, m.page),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the indexed page text in items of about 1500 characters, each starting with the title
+func (es *DataSourceCrawler) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	p, ok := es.pages.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Crawler topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultDataCount
+	}
+
+	c := es.Chunker
+	if c == nil {
+		c = chunker.Default
+	}
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, chunk := range c.Paragraphs(p.Paragraphs) {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		if chunk.Index > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%s#%d", p.URL, chunk.Index))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  p.Title + "\n" + chunk.Text,
+			SourceURL: p.URL,
+			Site:      host(p.URL),
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// Crawl fetches the seeds breadth-first, following links up to MaxDepth hops and MaxPages pages, and
+// replaces the index. Requests are sequential, wait Delay (or the host's Crawl-delay) between hits to
+// one host, and skip URLs robots.txt disallows or pages marked noindex. When ctx ends mid-crawl the
+// pages indexed so far are kept.
+func (es *DataSourceCrawler) Crawl(ctx context.Context) error {
+	if err := es.Init(); err != nil {
+		return err
+	}
+	type target struct {
+		url   string
+		depth int
+	}
+	hosts := map[string]bool{}
+	var queue []target
+	for _, seed := range es.Seeds {
+		u, _ := url.Parse(seed)
+		hosts[u.Host] = true
+		queue = append(queue, target{normalize(u), 0})
+	}
+
+	queued := map[string]bool{}
+	for _, t := range queue {
+		queued[t.url] = true
+	}
+	lastHit := map[string]time.Time{}
+	var index []page
+	var firstErr error
+	for len(queue) > 0 && len(index) < es.MaxPages && ctx.Err() == nil {
+		t := queue[0]
+		queue = queue[1:]
+		p, links, err := es.visit(ctx, t.url, lastHit)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if p != nil {
+			index = append(index, *p)
+		}
+		if t.depth >= es.MaxDepth {
+			continue
+		}
+		for _, link := range links {
+			u, err := url.Parse(link)
+			if err != nil || queued[link] || (es.SameHost && !hosts[u.Host]) {
+				continue
+			}
+			queued[link] = true
+			queue = append(queue, target{link, t.depth + 1})
+		}
+	}
+	if len(index) == 0 && firstErr != nil {
+		return firstErr
+	}
+
+	es.mu.Lock()
+	es.index, es.crawled = index, time.Now()
+	es.mu.Unlock()
+	return nil
+}
+
+// visit fetches one URL politely and returns its indexed page (nil for noindex pages) and its links
+func (es *DataSourceCrawler) visit(ctx context.Context, uri string, lastHit map[string]time.Time) (*page, []string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	rules, err := es.robots.Rules(ctx, u)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rules.Allowed(u.RequestURI()) {
+		return nil, nil, nil
+	}
+	delay := es.Delay
+	if rules.CrawlDelay > delay {
+		delay = rules.CrawlDelay
+	}
+	if wait := delay - time.Since(lastHit[u.Host]); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+	lastHit[u.Host] = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	resp, err := es.Client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("crawler request failed: status %d: %s", resp.StatusCode, uri)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, nil, nil
+	}
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	directives, _ := doc.Find(`meta[name="robots"]`).Attr("content")
+	directives = strings.ToLower(directives)
+	final := resp.Request.URL
+	var links []string
+	if !strings.Contains(directives, "nofollow") && !strings.Contains(directives, "none") {
+		doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+			href, _ := a.Attr("href")
+			if rel, _ := a.Attr("rel"); strings.Contains(rel, "nofollow") {
+				return
+			}
+			ref, err := url.Parse(strings.TrimSpace(href))
+			if err != nil {
+				return
+			}
+			link := final.ResolveReference(ref)
+			if link.Scheme == "http" || link.Scheme == "https" {
+				links = append(links, normalize(link))
+			}
+		})
+	}
+	if strings.Contains(directives, "noindex") || strings.Contains(directives, "none") {
+		return nil, links, nil
+	}
+
+	// Links are read first, as extraction removes navigation. Pages with little article text, such as
+	// link hubs and login forms, and pages behind a paywall or consent wall are followed but not indexed.
+	extractor := es.Extractor
+	if extractor == nil {
+		extractor = extract.Default
+	}
+	article, err := extractor.Document(doc, final.String())
+	if err != nil || article.Wall != "" {
+		return nil, links, nil
+	}
+	p := &page{URL: normalize(final), Title: article.Title, Paragraphs: article.Paragraphs}
+	if p.Title == "" {
+		p.Title = p.URL
+	}
+	p.title = counts(words(p.Title))
+	p.body = counts(words(article.Text()))
+	return p, links, nil
+}
+
+// Helpers
+
+// normalize drops the fragment so that anchors within a page are crawled once
+func normalize(u *url.URL) string {
+	clone := *u
+	clone.Fragment = ""
+	clone.RawFragment = ""
+	return clone.String()
+}
+
+// words splits text into lowercase letter and digit runs
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func counts(words []string) map[string]int {
+	m := make(map[string]int, len(words))
+	for _, w := range words {
+		m[w]++
+	}
+	return m
+}
+
+func host(uri string) string {
+	if u, err := url.Parse(uri); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
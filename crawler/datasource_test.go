@@ -0,0 +1,124 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	tides  = "Tides are the rise and fall of sea levels caused by the gravity of the Moon and the Sun acting on the oceans."
+	pools  = "Rock pools hold sea water at low tide, and the anemones and crabs living in them wait for the tides to return."
+	shells = "Shells wash up along the strand line after storms, carried in from deeper water by waves and by the tides."
+)
+
+// site serves a small site and records the paths it was asked for
+func site(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var visited []string
+	article := func(title, body, links string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `<html><head><title>%s</title></head><body><article><p>%s</p></article>%s</body></html>`, title, body, links)
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "User-agent: *\nDisallow: /private/\n")
+	})
+	mux.HandleFunc("/{$}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><ul>
+			<li><a href="/tides">Tides</a></li>
+			<li><a href="/tides#moon">The Moon</a></li>
+			<li><a href="pools">Pools</a></li>
+			<li><a href="/private/notes">Notes</a></li>
+			<li><a href="/secret" rel="nofollow">Secret</a></li>
+			<li><a href="/chart.pdf">Tide chart</a></li>
+			<li><a href="https://other.example/tides">Elsewhere</a></li>
+			<li><a href="mailto:tides@example.com">Mail</a></li>
+		</ul></body></html>`)
+	})
+	mux.HandleFunc("/tides", article("Tides", tides, ""))
+	mux.HandleFunc("/pools", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<html><head><meta name="robots" content="NOINDEX"><title>Pools</title></head>
+			<body><article><p>%s</p></article><a href="/shells">Shells</a></body></html>`, pools)
+	})
+	mux.HandleFunc("/shells", article("Shells", shells, `<a href="/deeper">Deeper</a>`))
+	mux.HandleFunc("/chart.pdf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		fmt.Fprint(w, "%PDF-1.4")
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		visited = append(visited, r.URL.Path)
+		mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), visited...)
+	}
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv, visited := site(t)
+	es := New()
+	es.Seeds = []string{srv.URL + "/"}
+	es.Delay = 0
+
+	topics, err := es.FetchTopics(5, "tides")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The title counts three times, so the page titled Tides ranks first
+	want := []string{"Tides|/tides", "Shells|/shells"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + strings.TrimPrefix(topics[i].SourceURL, srv.URL); got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+	got := strings.Join(visited(), " ")
+	if wantVisits := "/robots.txt / /tides /pools /chart.pdf /shells"; got != wantVisits {
+		t.Errorf("visited %q, want %q", got, wantVisits)
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].DataText != "Tides\n"+tides || data[0].AnswerID != topics[0].TopicID {
+		t.Errorf("FetchData = %+v", data)
+	}
+}
+
+func TestCrawlMaxPages(t *testing.T) {
+	srv, _ := site(t)
+	es := New()
+	es.Seeds = []string{srv.URL + "/tides", srv.URL + "/shells"}
+	es.Delay = 0
+	es.MaxDepth = 0
+	es.MaxPages = 1
+	if err := es.Crawl(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(es.index) != 1 || es.index[0].Title != "Tides" {
+		t.Errorf("index = %+v", es.index)
+	}
+}
+
+func TestInitRejectsBadSeed(t *testing.T) {
+	es := New()
+	es.Seeds = []string{"ftp://example.com/"}
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted an ftp seed")
+	}
+}
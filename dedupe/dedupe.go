@@ -0,0 +1,265 @@
+// Package dedupe drops near-duplicate topics and data items, such as the same
+// syndicated article returned by several news sources or a page and its
+// mirror, by comparing SimHash fingerprints.
+package dedupe
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+const (
+	// DefaultThreshold is the largest Hamming distance at which two fingerprints count as duplicates
+	DefaultThreshold = 3
+	defaultMemory    = 1024
+)
+
+// Deduper wraps a source, usually a composite, and drops topics and data items that are near-duplicates
+// of ones it has already returned. It implements source.Source, source.ContextSource,
+// source.OptionsSource and source.Pager.
+type Deduper struct {
+	Source    source.Source
+	Threshold int // Maximum Hamming distance between duplicates; New sets DefaultThreshold, 0 matches identical fingerprints only
+	// Memory is how many data fingerprints are remembered across FetchData calls, so the same text
+	// reached through topics from different sources is returned once; default 1024
+	Memory int
+
+	mu   sync.Mutex
+	seen []seenData
+}
+
+type seenData struct {
+	fingerprint uint64
+	topicID     int64
+}
+
+func New(s source.Source) *Deduper {
+	return &Deduper{Source: s, Threshold: DefaultThreshold, Memory: defaultMemory}
+}
+
+// Init implements models.DataSource
+func (d *Deduper) Init() error {
+	return d.Source.Init()
+}
+
+// CheckAvailability implements models.DataSource
+func (d *Deduper) CheckAvailability() bool {
+	return d.Source.CheckAvailability()
+}
+
+// Unwrap implements source.Wrapper
+func (d *Deduper) Unwrap() source.Source {
+	return d.Source
+}
+
+// FetchTopics implements models.DataSource
+// Drops topics whose URL matches an earlier topic once scheme, "www.", tracking parameters and trailing
+// slashes are ignored, or whose title is a near-duplicate of an earlier one. The first of each group is kept.
+func (d *Deduper) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return d.FetchTopicsContext(context.Background(), count, input)
+}
+
+// FetchTopicsContext implements source.ContextSource
+func (d *Deduper) FetchTopicsContext(ctx context.Context, count int, input string) ([]datasource.DataSourceTopic, error) {
+	topics, err := source.FetchTopicsContext(ctx, d.Source, count, input)
+	if err != nil {
+		return nil, err
+	}
+	return d.Topics(topics), nil
+}
+
+// FetchTopicsWithOptions implements source.OptionsSource
+func (d *Deduper) FetchTopicsWithOptions(ctx context.Context, query string, opts source.TopicOptions) ([]datasource.DataSourceTopic, error) {
+	topics, err := source.FetchTopicsWithOptions(ctx, d.Source, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	return d.Topics(topics), nil
+}
+
+// FetchTopicsPage implements source.Pager
+// Duplicates are dropped within each page; a page may repeat a topic from an earlier one
+func (d *Deduper) FetchTopicsPage(ctx context.Context, query string, opts source.TopicOptions, cursor string) (source.Page, error) {
+	page, err := source.FetchTopicsPage(ctx, d.Source, query, opts, cursor)
+	if err != nil {
+		return source.Page{}, err
+	}
+	page.Topics = d.Topics(page.Topics)
+	return page, nil
+}
+
+// FetchData implements models.DataSource
+// Drops items that are near-duplicates of an earlier item in the same response, or of an item recently
+// returned for a different topic
+func (d *Deduper) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return d.FetchDataContext(context.Background(), count, topicID)
+}
+
+// FetchDataContext implements source.ContextSource
+func (d *Deduper) FetchDataContext(ctx context.Context, count int, topicID int64) ([]datasource.DataSourceData, error) {
+	data, err := source.FetchDataContext(ctx, d.Source, count, topicID)
+	if err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	kept := make([]datasource.DataSourceData, 0, len(data))
+	var fingerprints []uint64
+	for _, item := range data {
+		// Items without text would all match each other's zero fingerprint
+		if blank(item.DataText) {
+			kept = append(kept, item)
+			continue
+		}
+		f := Fingerprint(item.DataText)
+		if d.duplicate(f, fingerprints) || d.seenElsewhere(f, topicID) {
+			continue
+		}
+		fingerprints = append(fingerprints, f)
+		kept = append(kept, item)
+	}
+	for _, f := range fingerprints {
+		d.remember(f, topicID)
+	}
+	return kept, nil
+}
+
+// Topics returns topics without near-duplicates, keeping the first of each group
+func (d *Deduper) Topics(topics []datasource.DataSourceTopic) []datasource.DataSourceTopic {
+	kept := make([]datasource.DataSourceTopic, 0, len(topics))
+	urls := map[string]bool{}
+	var fingerprints []uint64
+	for _, t := range topics {
+		key := CanonicalURL(t.SourceURL)
+		// Untitled topics are only compared by URL, as they would all match each other's zero fingerprint
+		text := title(t)
+		titled := !blank(text)
+		f := Fingerprint(text)
+		if (key != "" && urls[key]) || (titled && d.duplicate(f, fingerprints)) {
+			continue
+		}
+		if key != "" {
+			urls[key] = true
+		}
+		if titled {
+			fingerprints = append(fingerprints, f)
+		}
+		kept = append(kept, t)
+	}
+	return kept
+}
+
+func (d *Deduper) duplicate(f uint64, fingerprints []uint64) bool {
+	for _, other := range fingerprints {
+		if Distance(f, other) <= d.Threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// seenElsewhere reports whether f matches data recently returned for another topic; d.mu must be held
+func (d *Deduper) seenElsewhere(f uint64, topicID int64) bool {
+	for _, s := range d.seen {
+		if s.topicID != topicID && Distance(f, s.fingerprint) <= d.Threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// remember records f for topicID, forgetting the oldest fingerprints beyond Memory; d.mu must be held
+func (d *Deduper) remember(f uint64, topicID int64) {
+	limit := d.Memory
+	if limit == 0 {
+		limit = defaultMemory
+	}
+	if limit < 0 {
+		return
+	}
+	d.seen = append(d.seen, seenData{f, topicID})
+	if len(d.seen) > limit {
+		d.seen = d.seen[len(d.seen)-limit:]
+	}
+}
+
+// CanonicalURL normalizes a URL for duplicate detection: scheme, "www.", fragments, utm_* and similar
+// tracking parameters and trailing slashes are dropped
+func CanonicalURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(key, "utm_") || trackingParams[key] {
+			query.Del(key)
+		}
+	}
+	canonical := host + strings.TrimRight(u.EscapedPath(), "/")
+	if encoded := query.Encode(); encoded != "" {
+		canonical += "?" + encoded
+	}
+	return canonical
+}
+
+// Helpers
+
+var trackingParams = map[string]bool{"fbclid": true, "gclid": true, "mc_cid": true, "mc_eid": true, "ref": true, "ref_src": true}
+
+// title returns a topic's title without a trailing " - Publisher" or " | Publisher" suffix naming the
+// topic's site or host, which syndicated copies of an article usually differ in. Other suffixes are part
+// of the title: "Go 1.22 - Release Notes" and "Go 1.22 - Tutorial" are different pages.
+func title(t datasource.DataSourceTopic) string {
+	topic := t.Topic
+	for _, sep := range []string{" | ", " - ", " — "} {
+		if i := strings.LastIndex(topic, sep); i > 0 && publisher(topic[i+len(sep):], t) {
+			topic = topic[:i]
+		}
+	}
+	return topic
+}
+
+// publisher reports whether a title suffix names the topic's site, its host, or a label of the host,
+// ignoring case, spaces and punctuation: "Hacker News" for Site "Hacker News", "The Guardian" for
+// theguardian.com
+func publisher(suffix string, t datasource.DataSourceTopic) bool {
+	name := letters(suffix)
+	if name == "" {
+		return false
+	}
+	if name == letters(t.Site) {
+		return true
+	}
+	u, err := url.Parse(t.SourceURL)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if name == letters(host) {
+		return true
+	}
+	for _, label := range strings.Split(host, ".") {
+		if name == letters(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// letters lowercases text and keeps only its letters and digits
+func letters(text string) string {
+	return strings.Map(func(r rune) rune {
+		if notWord(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, text)
+}
@@ -0,0 +1,156 @@
+package dedupe
+
+import (
+	"context"
+	"testing"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+// fixed is a source returning the same topics and data for every call
+type fixed struct {
+	topics []datasource.DataSourceTopic
+	data   []datasource.DataSourceData
+}
+
+func (f *fixed) Init() error             { return nil }
+func (f *fixed) CheckAvailability() bool { return true }
+
+func (f *fixed) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return f.topics, nil
+}
+
+func (f *fixed) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return f.data, nil
+}
+
+func TestTopics(t *testing.T) {
+	d := New(nil)
+	topics := d.Topics([]datasource.DataSourceTopic{
+		{TopicID: 1, Topic: "Go 1.22 released with range over integers", SourceURL: "https://go.dev/blog/go1.22"},
+		{TopicID: 2, Topic: "Go 1.22 released with range over integers - Hacker News", SourceURL: "https://news.example.com/1", Site: "Hacker News"},
+		{TopicID: 3, Topic: "Something else entirely", SourceURL: "https://www.go.dev/blog/go1.22/?utm_source=feed"},
+		{TopicID: 4, Topic: "Rust 1.75 adds async fn in traits", SourceURL: "https://blog.rust-lang.org/1.75"},
+	})
+	var ids []int64
+	for _, topic := range topics {
+		ids = append(ids, topic.TopicID)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 4 {
+		t.Errorf("Topics kept %v, want [1 4]", ids)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	for _, tc := range []struct {
+		topic datasource.DataSourceTopic
+		want  string
+	}{
+		{datasource.DataSourceTopic{Topic: "Rates held - Reuters", Site: "Reuters"}, "Rates held"},
+		{datasource.DataSourceTopic{Topic: "Rates held | The Guardian", SourceURL: "https://www.theguardian.com/business/1"}, "Rates held"},
+		{datasource.DataSourceTopic{Topic: "Rates held — example.com", SourceURL: "https://example.com/1"}, "Rates held"},
+		// Suffixes that don't name the publisher are part of the title
+		{datasource.DataSourceTopic{Topic: "Go 1.22 - Release Notes", SourceURL: "https://go.dev/doc/go1.22"}, "Go 1.22 - Release Notes"},
+		{datasource.DataSourceTopic{Topic: "Python | Part 2", Site: "Real Python"}, "Python | Part 2"},
+		{datasource.DataSourceTopic{Topic: "- Reuters", Site: "Reuters"}, "- Reuters"},
+	} {
+		if got := title(tc.topic); got != tc.want {
+			t.Errorf("title(%q) = %q, want %q", tc.topic.Topic, got, tc.want)
+		}
+	}
+}
+
+func TestTopicsDifferentSuffixes(t *testing.T) {
+	d := New(nil)
+	topics := d.Topics([]datasource.DataSourceTopic{
+		{TopicID: 1, Topic: "Getting started with generics - Tutorial", SourceURL: "https://go.dev/doc/tutorial/generics"},
+		{TopicID: 2, Topic: "Getting started with generics - Reference", SourceURL: "https://go.dev/ref/spec"},
+	})
+	if len(topics) != 2 {
+		t.Errorf("Topics kept %+v, want both pages", topics)
+	}
+}
+
+func TestTopicsWithoutTitles(t *testing.T) {
+	d := New(nil)
+	topics := d.Topics([]datasource.DataSourceTopic{
+		{TopicID: 1, SourceURL: "https://example.com/a"},
+		{TopicID: 2, Topic: "  ", SourceURL: "https://example.com/b"},
+		{TopicID: 3, SourceURL: "https://example.com/c"},
+		{TopicID: 4, SourceURL: "https://example.com/a/"},
+	})
+	if len(topics) != 3 {
+		t.Errorf("Topics kept %+v, want the three distinct URLs", topics)
+	}
+}
+
+func TestFetchDataWithoutText(t *testing.T) {
+	d := New(&fixed{data: []datasource.DataSourceData{
+		{AnswerID: 1},
+		{AnswerID: 2, DataText: "..."},
+		{AnswerID: 3, DataText: "The quick brown fox jumps over the lazy dog"},
+		{AnswerID: 4, DataText: "The quick brown fox jumps over the lazy dog"},
+	}})
+	data, err := d.FetchData(5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, item := range data {
+		ids = append(ids, item.AnswerID)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Errorf("FetchData kept %v, want [1 2 3]", ids)
+	}
+
+	// Empty items of another topic are not duplicates of the first topic's
+	data, err = d.FetchData(5, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 {
+		t.Errorf("second topic kept %+v, want its two empty items", data)
+	}
+}
+
+// optioned is a fixed source that records the options and context it was searched with
+type optioned struct {
+	fixed
+	opts source.TopicOptions
+	ctx  context.Context
+}
+
+func (o *optioned) FetchTopicsWithOptions(ctx context.Context, query string, opts source.TopicOptions) ([]datasource.DataSourceTopic, error) {
+	o.ctx, o.opts = ctx, opts
+	return o.topics, nil
+}
+
+func TestFetchTopicsWithOptions(t *testing.T) {
+	inner := &optioned{fixed: fixed{topics: []datasource.DataSourceTopic{
+		{TopicID: 1, Topic: "Zürich", SourceURL: "https://de.example.org/Zürich"},
+		{TopicID: 2, Topic: "Zürich", SourceURL: "https://de.example.org/Zürich/"},
+	}}}
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "request")
+	opts := source.TopicOptions{Count: 5, Language: "de"}
+	topics, err := source.FetchTopicsWithOptions(ctx, New(inner), "zürich", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 {
+		t.Errorf("FetchTopicsWithOptions = %+v, want one topic", topics)
+	}
+	if inner.opts != opts || inner.ctx.Value(key{}) != "request" {
+		t.Errorf("source searched with %+v and a context without the caller's value", inner.opts)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	a := Fingerprint("The quick brown fox jumps over the lazy dog near the river bank")
+	b := Fingerprint("The quick brown fox jumps over the lazy dog near the river")
+	c := Fingerprint("Completely unrelated text about compilers and garbage collection")
+	if Distance(a, b) >= Distance(a, c) {
+		t.Errorf("similar texts are %d bits apart, unrelated ones %d", Distance(a, b), Distance(a, c))
+	}
+}
@@ -0,0 +1,58 @@
+package dedupe
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// shingleSize is the number of words per feature; texts shorter than this use single words
+const shingleSize = 3
+
+// Fingerprint returns the 64-bit SimHash of text over overlapping word shingles. Texts that differ in a
+// few words get fingerprints that differ in a few bits. Texts without words fingerprint to 0.
+func Fingerprint(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), notWord)
+	if len(words) == 0 {
+		return 0
+	}
+	size := shingleSize
+	if len(words) < size {
+		size = 1
+	}
+	var weights [64]int
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+	var fingerprint uint64
+	for bit, w := range weights {
+		if w > 0 {
+			fingerprint |= 1 << bit
+		}
+	}
+	return fingerprint
+}
+
+// Distance returns the Hamming distance between two fingerprints
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// blank reports whether text has no words to fingerprint
+func blank(text string) bool {
+	return strings.IndexFunc(text, func(r rune) bool { return !notWord(r) }) < 0
+}
+
+func notWord(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,347 @@
+package devdocs
+
+// Data Source Adapter for DevDocs documentation indexes (public or self-hosted)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceDevDocs struct {
+	Client    *http.Client
+	DocsURL   string // Where docset files are served: documents.devdocs.io, or <instance>/docs when self-hosted
+	SiteURL   string // DevDocs front end used for topic links
+	UserAgent string
+	Docsets   []string // Docset slugs as listed in docs.json, e.g. "go", "python~3.12", "postgresql~16"
+
+	mu      sync.RWMutex
+	indexes map[string][]entry
+}
+
+// entry is a single item of a docset's index.json
+type entry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+func New() *DataSourceDevDocs {
+	return &DataSourceDevDocs{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		DocsURL:   "https://documents.devdocs.io",
+		SiteURL:   "https://devdocs.io",
+		UserAgent: "locus/devdocs-datasource",
+		Docsets:   []string{"go", "python~3.12", "postgresql~16"},
+	}
+}
+
+// Init implements models.DataSource
+// Downloads the index of every configured docset so searches run in memory
+func (es *DataSourceDevDocs) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if len(es.Docsets) == 0 {
+		return errors.New("at least one docset is required for DevDocs DataSource")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+	return es.loadIndexes(ctx)
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceDevDocs) CheckAvailability() bool {
+	if len(es.Docsets) == 0 {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	var index struct {
+		Entries []entry `json:"entries"`
+	}
+	return es.doJSON(ctx, es.docURL(es.Docsets[0], "index.json"), &index) == nil && len(index.Entries) > 0
+}
+
+// Info implements source.Informer
+func (es *DataSourceDevDocs) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "DevDocs",
+		Homepage:    "https://devdocs.io",
+		Attribution: "DevDocs; each documentation set keeps its own license",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Ranks index entries by how closely their name matches the query; the docset is reported as the Site
+func (es *DataSourceDevDocs) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.ToLower(strings.TrimSpace(input))
+	if query == "" {
+		return nil, errors.New("Missing search input for DevDocs DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	if err := es.loadIndexes(ctx); err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		docset string
+		entry  entry
+		score  int
+	}
+	var matches []match
+	es.mu.RLock()
+	for _, docset := range es.Docsets {
+		for _, item := range es.indexes[docset] {
+			if score := matchScore(strings.ToLower(item.Name), query); score > 0 {
+				matches = append(matches, match{docset: docset, entry: item, score: score})
+			}
+		}
+	}
+	es.mu.RUnlock()
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return len(matches[i].entry.Name) < len(matches[j].entry.Name)
+	})
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, m := range matches {
+		if len(results) >= count {
+			break
+		}
+		title := m.entry.Name
+		if m.entry.Type != "" {
+			title = fmt.Sprintf("%s (%s)", m.entry.Name, m.entry.Type)
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: fmt.Sprintf("%s/%s/%s", strings.TrimRight(es.SiteURL, "/"), m.docset, m.entry.Path),
+			Site:      m.docset,
+			TopicID:   topicid.Hash(m.docset + "/" + m.entry.Path),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Loads the documentation page; entries pointing at an anchor return only that section
+func (es *DataSourceDevDocs) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	docset, item, ok := es.lookup(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown DevDocs topicID %d", topicID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	page, fragment, _ := strings.Cut(item.Path, "#")
+	body, err := es.get(ctx, es.docURL(docset, page+".html"))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, err
+	}
+
+	text := ""
+	if fragment != "" {
+		text = sectionText(doc, fragment)
+	}
+	if text == "" {
+		text = blockText(doc.Selection)
+	}
+	if text == "" {
+		return []datasource.DataSourceData{}, nil
+	}
+	return []datasource.DataSourceData{{
+		DataText:  text,
+		SourceURL: fmt.Sprintf("%s/%s/%s", strings.TrimRight(es.SiteURL, "/"), docset, item.Path),
+		Site:      docset,
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// loadIndexes fetches index.json for any configured docset not loaded yet
+func (es *DataSourceDevDocs) loadIndexes(ctx context.Context) error {
+	for _, docset := range es.Docsets {
+		es.mu.RLock()
+		_, loaded := es.indexes[docset]
+		es.mu.RUnlock()
+		if loaded {
+			continue
+		}
+		var index struct {
+			Entries []entry `json:"entries"`
+		}
+		if err := es.doJSON(ctx, es.docURL(docset, "index.json"), &index); err != nil {
+			return fmt.Errorf("devdocs: loading %s index: %w", docset, err)
+		}
+		es.mu.Lock()
+		if es.indexes == nil {
+			es.indexes = make(map[string][]entry)
+		}
+		es.indexes[docset] = index.Entries
+		es.mu.Unlock()
+	}
+	return nil
+}
+
+// lookup finds the index entry whose hashed docset/path equals topicID
+func (es *DataSourceDevDocs) lookup(topicID int64) (string, entry, bool) {
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+	for docset, entries := range es.indexes {
+		for _, item := range entries {
+			if topicid.Hash(docset+"/"+item.Path) == topicID {
+				return docset, item, true
+			}
+		}
+	}
+	return "", entry{}, false
+}
+
+func (es *DataSourceDevDocs) docURL(docset, file string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimRight(es.DocsURL, "/"), docset, file)
+}
+
+// doJSON performs an HTTP GET request and decodes the JSON response into target
+func (es *DataSourceDevDocs) doJSON(ctx context.Context, uri string, target interface{}) error {
+	body, err := es.get(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(target)
+}
+
+// get performs an HTTP GET request. The caller must close the returned body.
+func (es *DataSourceDevDocs) get(ctx context.Context, uri string) (io.ReadCloser, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("devdocs request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// Helpers
+
+// matchScore ranks exact names above prefixes above word matches above substrings; 0 means no match
+func matchScore(name, query string) int {
+	switch {
+	case name == query:
+		return 4
+	case strings.HasPrefix(name, query):
+		return 3
+	case strings.Contains(name, "."+query) || strings.Contains(name, " "+query):
+		return 2
+	case strings.Contains(name, query):
+		return 1
+	}
+	return 0
+}
+
+// sectionText returns the text from the anchored element up to the next heading of the same or higher level
+func sectionText(doc *goquery.Document, fragment string) string {
+	anchor := doc.Find("#" + cssEscape(fragment)).First()
+	if anchor.Length() == 0 {
+		return ""
+	}
+	level := headingLevel(goquery.NodeName(anchor))
+	if level == 0 {
+		return blockText(anchor)
+	}
+	parts := []string{strings.TrimSpace(anchor.Text())}
+	for next := anchor.Next(); next.Length() > 0; next = next.Next() {
+		if l := headingLevel(goquery.NodeName(next)); l > 0 && l <= level {
+			break
+		}
+		if text := blockText(next); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// blockText flattens a selection to text, keeping code blocks verbatim and one paragraph per block
+func blockText(s *goquery.Selection) string {
+	var blocks []string
+	s.Find("h1, h2, h3, h4, h5, h6, p, li, pre, dt, dd, td").Each(func(_ int, b *goquery.Selection) {
+		// Nested blocks (a <p> inside an <li>) are emitted by the outer block
+		if b.ParentsFiltered("li, dd, td").Length() > 0 && goquery.NodeName(b) != "pre" {
+			return
+		}
+		if goquery.NodeName(b) == "pre" {
+			blocks = append(blocks, strings.TrimRight(b.Text(), "\n"))
+			return
+		}
+		if text := strings.Join(strings.Fields(b.Text()), " "); text != "" {
+			blocks = append(blocks, text)
+		}
+	})
+	if len(blocks) == 0 {
+		return strings.Join(strings.Fields(s.Text()), " ")
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+func headingLevel(tag string) int {
+	if len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6' {
+		return int(tag[1] - '0')
+	}
+	return 0
+}
+
+// cssEscape escapes characters that are common in DevDocs anchors but special in CSS selectors
+func cssEscape(id string) string {
+	var b strings.Builder
+	for _, r := range id {
+		if strings.ContainsRune(".:()[]/%=+", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package devdocs
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newSource(t *testing.T) *DataSourceDevDocs {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/go/index.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"entries":[
+			{"name":"strings.Builder","path":"strings/index#Builder","type":"strings"},
+			{"name":"strings","path":"strings/index","type":"strings"},
+			{"name":"sort.Strings","path":"sort/index#Strings","type":"sort"},
+			{"name":"net/http","path":"net/http/index","type":"net"}
+		]}`)
+	})
+	mux.HandleFunc("/go/strings/index.html", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<h1>Package strings</h1><p>Package strings implements functions.</p>
+			<h2 id="Builder">type Builder</h2><p>A Builder builds a string.</p><pre>var b strings.Builder
+</pre>
+			<h2 id="Reader">type Reader</h2><p>A Reader reads.</p>`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	es := New()
+	es.DocsURL = srv.URL
+	es.SiteURL = "https://devdocs.example"
+	es.Docsets = []string{"go"}
+	return es
+}
+
+func TestFetchTopicsRanksMatches(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "Strings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"strings (strings)", "strings.Builder (strings)", "sort.Strings (sort)"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+	if topics[1].SourceURL != "https://devdocs.example/go/strings/index#Builder" || topics[1].Site != "go" {
+		t.Errorf("topic 1 = %+v", topics[1])
+	}
+}
+
+func TestFetchDataSection(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "strings.builder")
+	if err != nil || len(topics) != 1 {
+		t.Fatalf("FetchTopics = %+v, %v", topics, err)
+	}
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "type Builder\n\nA Builder builds a string.\n\nvar b strings.Builder"
+	if len(data) != 1 || data[0].DataText != want {
+		t.Errorf("FetchData = %+v, want %q", data, want)
+	}
+}
+
+func TestCSSEscape(t *testing.T) {
+	if got := cssEscape("Buffer.String"); got != `Buffer\.String` {
+		t.Errorf("cssEscape = %q", got)
+	}
+}
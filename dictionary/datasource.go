@@ -0,0 +1,449 @@
+package dictionary
+
+// Data Source Adapter for dictionary definitions from dictionaryapi.dev or Merriam-Webster
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+// Supported dictionaries
+const (
+	ProviderFreeDictionary = "dictionaryapi"  // dictionaryapi.dev, keyless (Wiktionary-derived)
+	ProviderMerriamWebster = "merriamwebster" // Collegiate Dictionary; requires an API key
+)
+
+var (
+	// mwLink matches Merriam-Webster cross-reference tokens such as {sx|run||} or {d_link|sprint|sprint:1}
+	mwLink = regexp.MustCompile(`\{(?:a_link|d_link|i_link|et_link|sx|dxt|mat)\|([^|}]*)[^}]*\}`)
+	mwMark = regexp.MustCompile(`\{[^}]*\}`)
+)
+
+type DataSourceDictionary struct {
+	Client    *http.Client
+	Provider  string // ProviderFreeDictionary or ProviderMerriamWebster
+	FreeURL   string
+	MWURL     string
+	APIKey    string // Merriam-Webster Collegiate key
+	UserAgent string
+	Language  string // dictionaryapi.dev language code, e.g. "en"
+	MaxSenses int    // Definitions returned per entry when count is not set
+
+	entries topicid.Map[entry]
+}
+
+// entry is one headword and part of speech, normalised across providers
+type entry struct {
+	Word          string
+	PartOfSpeech  string
+	Pronunciation string
+	Audio         string
+	Senses        []sense
+	Source        string
+}
+
+type sense struct {
+	Definition string
+	Examples   []string
+	Synonyms   []string
+	Antonyms   []string
+}
+
+func New() *DataSourceDictionary {
+	return &DataSourceDictionary{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		Provider:  ProviderFreeDictionary,
+		FreeURL:   "https://api.dictionaryapi.dev/api/v2/entries",
+		MWURL:     "https://www.dictionaryapi.com/api/v3/references/collegiate/json",
+		UserAgent: "locus/dictionary-datasource",
+		Language:  "en",
+		MaxSenses: 8,
+	}
+}
+
+// Init implements models.DataSource
+// Validates the provider configuration
+func (es *DataSourceDictionary) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	switch es.Provider {
+	case ProviderFreeDictionary:
+	case ProviderMerriamWebster:
+		if es.APIKey == "" {
+			return errors.New("APIKey is required for the Merriam-Webster backend")
+		}
+	default:
+		return fmt.Errorf("unknown dictionary provider %q", es.Provider)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceDictionary) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.lookup(ctx, "word")
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceDictionary) Info() source.SourceInfo {
+	if es.Provider == ProviderMerriamWebster {
+		return source.SourceInfo{
+			Name:                "Merriam-Webster",
+			Homepage:            "https://www.merriam-webster.com",
+			Attribution:         "Merriam-Webster's Collegiate® Dictionary",
+			AttributionRequired: true,
+			TermsURL:            "https://dictionaryapi.com/info/terms-of-service",
+		}
+	}
+	return source.SourceInfo{
+		Name:                "Free Dictionary API",
+		Homepage:            "https://dictionaryapi.dev",
+		License:             "CC-BY-SA-3.0",
+		LicenseURL:          "https://creativecommons.org/licenses/by-sa/3.0/",
+		Attribution:         "Definitions from Wiktionary via dictionaryapi.dev, CC BY-SA 3.0",
+		AttributionRequired: true,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each entry for the word is a topic, e.g. "run (verb) /rʌn/" and "run (noun) /rʌn/"
+func (es *DataSourceDictionary) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	word := strings.TrimSpace(input)
+	if word == "" {
+		return nil, errors.New("Missing search input for Dictionary DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	entries, err := es.lookup(ctx, word)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for i, e := range entries {
+		if len(results) >= count {
+			break
+		}
+		title := e.Word
+		if e.PartOfSpeech != "" {
+			title += " (" + e.PartOfSpeech + ")"
+		}
+		if e.Pronunciation != "" {
+			title += " " + e.Pronunciation
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: e.Source,
+			Site:      es.Provider,
+			TopicID:   es.entries.Put(fmt.Sprintf("%s/%s/%d", es.Provider, e.Word, i), e),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns one item per definition, each with the part of speech, pronunciation, examples and synonyms
+func (es *DataSourceDictionary) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	e, ok := es.entries.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Dictionary topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = es.MaxSenses
+	}
+
+	results := make([]datasource.DataSourceData, 0, len(e.Senses))
+	for i, s := range e.Senses {
+		if count > 0 && len(results) >= count {
+			break
+		}
+		var b strings.Builder
+		b.WriteString(e.Word)
+		if e.PartOfSpeech != "" {
+			b.WriteString(" (" + e.PartOfSpeech + ")")
+		}
+		if e.Pronunciation != "" {
+			b.WriteString(" " + e.Pronunciation)
+		}
+		fmt.Fprintf(&b, "\n%d. %s", i+1, s.Definition)
+		for _, example := range s.Examples {
+			b.WriteString("\nExample: " + example)
+		}
+		if len(s.Synonyms) > 0 {
+			b.WriteString("\nSynonyms: " + strings.Join(s.Synonyms, ", "))
+		}
+		if len(s.Antonyms) > 0 {
+			b.WriteString("\nAntonyms: " + strings.Join(s.Antonyms, ", "))
+		}
+		if i == 0 && e.Audio != "" {
+			b.WriteString("\nAudio: " + e.Audio)
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  b.String(),
+			SourceURL: e.Source,
+			Site:      es.Provider,
+			AnswerID:  topicid.Hash(fmt.Sprintf("%d#%d", topicID, i)),
+		})
+	}
+	return results, nil
+}
+
+// lookup fetches and normalises the entries for a word from the configured provider.
+// A word with no entries yields an empty list rather than an error.
+func (es *DataSourceDictionary) lookup(ctx context.Context, word string) ([]entry, error) {
+	if es.Provider == ProviderMerriamWebster {
+		return es.lookupMW(ctx, word)
+	}
+
+	language := es.Language
+	if language == "" {
+		language = "en"
+	}
+	uri := fmt.Sprintf("%s/%s/%s", strings.TrimRight(es.FreeURL, "/"), language, url.PathEscape(word))
+	var response []struct {
+		Word      string `json:"word"`
+		Phonetic  string `json:"phonetic"`
+		Phonetics []struct {
+			Text  string `json:"text"`
+			Audio string `json:"audio"`
+		} `json:"phonetics"`
+		Meanings []struct {
+			PartOfSpeech string `json:"partOfSpeech"`
+			Definitions  []struct {
+				Definition string   `json:"definition"`
+				Example    string   `json:"example"`
+				Synonyms   []string `json:"synonyms"`
+				Antonyms   []string `json:"antonyms"`
+			} `json:"definitions"`
+			Synonyms []string `json:"synonyms"`
+			Antonyms []string `json:"antonyms"`
+		} `json:"meanings"`
+		SourceURLs []string `json:"sourceUrls"`
+	}
+	found, err := es.doJSON(ctx, uri, &response)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	var entries []entry
+	for _, r := range response {
+		pronunciation, audio := r.Phonetic, ""
+		for _, phonetic := range r.Phonetics {
+			if pronunciation == "" {
+				pronunciation = phonetic.Text
+			}
+			if audio == "" {
+				audio = phonetic.Audio
+			}
+		}
+		source := "https://en.wiktionary.org/wiki/" + url.PathEscape(r.Word)
+		if len(r.SourceURLs) > 0 {
+			source = r.SourceURLs[0]
+		}
+		for _, meaning := range r.Meanings {
+			e := entry{Word: r.Word, PartOfSpeech: meaning.PartOfSpeech, Pronunciation: pronunciation, Audio: audio, Source: source}
+			for i, definition := range meaning.Definitions {
+				s := sense{Definition: definition.Definition, Synonyms: definition.Synonyms, Antonyms: definition.Antonyms}
+				if definition.Example != "" {
+					s.Examples = []string{definition.Example}
+				}
+				// Meaning-level synonyms apply to the whole part of speech; attach them once
+				if i == 0 {
+					s.Synonyms = append(s.Synonyms, meaning.Synonyms...)
+					s.Antonyms = append(s.Antonyms, meaning.Antonyms...)
+				}
+				e.Senses = append(e.Senses, s)
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// lookupMW reads the Collegiate Dictionary. Unknown words return a list of spelling suggestions
+// (plain strings) instead of entries, which is treated as no result.
+func (es *DataSourceDictionary) lookupMW(ctx context.Context, word string) ([]entry, error) {
+	params := url.Values{}
+	params.Set("key", es.APIKey)
+	uri := fmt.Sprintf("%s/%s?%s", strings.TrimRight(es.MWURL, "/"), url.PathEscape(word), params.Encode())
+	var raw []json.RawMessage
+	found, err := es.doJSON(ctx, uri, &raw)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	var entries []entry
+	for _, item := range raw {
+		var r struct {
+			Meta struct {
+				ID string `json:"id"`
+			} `json:"meta"`
+			HWI struct {
+				HW  string `json:"hw"`
+				PRS []struct {
+					MW    string `json:"mw"`
+					Sound struct {
+						Audio string `json:"audio"`
+					} `json:"sound"`
+				} `json:"prs"`
+			} `json:"hwi"`
+			FL       string          `json:"fl"`
+			Shortdef []string        `json:"shortdef"`
+			Def      json.RawMessage `json:"def"`
+		}
+		if err := json.Unmarshal(item, &r); err != nil {
+			// A suggestion string rather than an entry
+			continue
+		}
+		// Headwords mark syllable breaks with asterisks
+		headword := strings.ReplaceAll(r.HWI.HW, "*", "")
+		e := entry{
+			Word:         headword,
+			PartOfSpeech: r.FL,
+			Source:       "https://www.merriam-webster.com/dictionary/" + url.PathEscape(headword),
+		}
+		if len(r.HWI.PRS) > 0 {
+			e.Pronunciation = `\` + r.HWI.PRS[0].MW + `\`
+			e.Audio = mwAudio(r.HWI.PRS[0].Sound.Audio)
+		}
+		examples := mwExamples(r.Def)
+		for i, definition := range r.Shortdef {
+			s := sense{Definition: definition}
+			// Examples cannot be tied to short definitions exactly; attach them to the first sense
+			if i == 0 {
+				s.Examples = examples
+			}
+			e.Senses = append(e.Senses, s)
+		}
+		if len(e.Senses) > 0 {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// doJSON performs an HTTP GET and decodes the JSON response into target; a 404 reports found as false
+func (es *DataSourceDictionary) doJSON(ctx context.Context, uri string, target interface{}) (bool, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return false, fmt.Errorf("dictionary request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return true, json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+
+// mwExamples collects the verbal illustrations ("vis") nested anywhere in a Merriam-Webster definition section
+func mwExamples(def json.RawMessage) []string {
+	var tree interface{}
+	if len(def) == 0 || json.Unmarshal(def, &tree) != nil {
+		return nil
+	}
+	var examples []string
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch n := node.(type) {
+		case []interface{}:
+			if len(n) == 2 {
+				if tag, ok := n[0].(string); ok && tag == "vis" {
+					if list, ok := n[1].([]interface{}); ok {
+						for _, v := range list {
+							if vis, ok := v.(map[string]interface{}); ok {
+								if text, ok := vis["t"].(string); ok {
+									examples = append(examples, mwText(text))
+								}
+							}
+						}
+					}
+					return
+				}
+			}
+			for _, child := range n {
+				walk(child)
+			}
+		case map[string]interface{}:
+			for _, child := range n {
+				walk(child)
+			}
+		}
+	}
+	walk(tree)
+	return examples
+}
+
+// mwText strips Merriam-Webster formatting tokens, keeping the text of cross-references
+func mwText(s string) string {
+	s = strings.ReplaceAll(s, "{bc}", ": ")
+	s = mwLink.ReplaceAllString(s, "$1")
+	return strings.TrimSpace(mwMark.ReplaceAllString(s, ""))
+}
+
+// mwAudio builds the media URL for a pronunciation file, per the Merriam-Webster API documentation
+func mwAudio(name string) string {
+	if name == "" {
+		return ""
+	}
+	subdir := name[:1]
+	switch {
+	case strings.HasPrefix(name, "bix"):
+		subdir = "bix"
+	case strings.HasPrefix(name, "gg"):
+		subdir = "gg"
+	case !strings.ContainsAny(subdir, "abcdefghijklmnopqrstuvwxyz"):
+		subdir = "number"
+	}
+	return fmt.Sprintf("https://media.merriam-webster.com/audio/prons/en/us/mp3/%s/%s.mp3", subdir, name)
+}
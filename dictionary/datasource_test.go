@@ -0,0 +1,148 @@
+package dictionary
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/free/en/run", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"word":"run","phonetic":"","phonetics":[{"text":"/ɹʌn/"},{"audio":"https://api.dictionaryapi.dev/media/run.mp3"}],
+			"meanings":[
+				{"partOfSpeech":"verb","definitions":[
+					{"definition":"To move swiftly.","example":"She ran home.","synonyms":["sprint"]},
+					{"definition":"To flee."}
+				],"synonyms":["dash"],"antonyms":["walk"]},
+				{"partOfSpeech":"noun","definitions":[{"definition":"An act of running."}]}
+			],
+			"sourceUrls":["https://en.wiktionary.org/wiki/run"]}]`)
+	})
+	mux.HandleFunc("/free/en/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"title":"No Definitions Found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/mw/voluminous", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "key" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[{"meta":{"id":"voluminous"},"hwi":{"hw":"vo*lu*mi*nous","prs":[{"mw":"və-ˈlü-mə-nəs","sound":{"audio":"volumi01"}}]},
+			"fl":"adjective","shortdef":["having or marked by great volume or bulk","numerous"],
+			"def":[{"sseq":[[["sense",{"dt":[["text","{bc}having great volume"],["vis",[{"t":"a {wi}voluminous{/wi} skirt"}]]]}]]]}]}]`)
+	})
+	mux.HandleFunc("/mw/volumnous", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `["voluminous","volumes"]`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.FreeURL = srv.URL + "/free/"
+
+	topics, err := es.FetchTopics(5, "run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"run (verb) /ɹʌn/", "run (noun) /ɹʌn/"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+
+	data, err := es.FetchData(0, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := []string{
+		"run (verb) /ɹʌn/\n1. To move swiftly.\nExample: She ran home.\nSynonyms: sprint, dash\nAntonyms: walk\nAudio: https://api.dictionaryapi.dev/media/run.mp3",
+		"run (verb) /ɹʌn/\n2. To flee.",
+	}
+	if len(data) != len(wantData) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range wantData {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+	if data[0].SourceURL != "https://en.wiktionary.org/wiki/run" {
+		t.Errorf("SourceURL = %q", data[0].SourceURL)
+	}
+
+	topics, err = es.FetchTopics(5, "qwxz")
+	if err != nil || len(topics) != 0 {
+		t.Errorf("FetchTopics for an unknown word = %+v, %v", topics, err)
+	}
+}
+
+func TestFetchTopicsAndDataMerriamWebster(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.Provider = ProviderMerriamWebster
+	es.MWURL = srv.URL + "/mw"
+	es.APIKey = "key"
+
+	topics, err := es.FetchTopics(5, "voluminous")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != `voluminous (adjective) \və-ˈlü-mə-nəs\` ||
+		topics[0].SourceURL != "https://www.merriam-webster.com/dictionary/voluminous" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `voluminous (adjective) \və-ˈlü-mə-nəs\` + "\n1. having or marked by great volume or bulk\nExample: a voluminous skirt" +
+		"\nAudio: https://media.merriam-webster.com/audio/prons/en/us/mp3/v/volumi01.mp3"
+	if len(data) != 1 || data[0].DataText != want {
+		t.Errorf("FetchData = %+v, want %q", data, want)
+	}
+
+	// Misspellings return suggestions, not entries
+	topics, err = es.FetchTopics(5, "volumnous")
+	if err != nil || len(topics) != 0 {
+		t.Errorf("FetchTopics for a misspelling = %+v, %v", topics, err)
+	}
+}
+
+func TestMWText(t *testing.T) {
+	tests := map[string]string{
+		"{bc}to go {d_link|faster|fast:1} than a walk": ": to go faster than a walk",
+		"see {sx|run||}":      "see run",
+		"{it}very{/it} large": "very large",
+	}
+	for in, want := range tests {
+		if got := mwText(in); got != want {
+			t.Errorf("mwText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMWAudio(t *testing.T) {
+	tests := map[string]string{
+		"bixdog01": "bix",
+		"ggwash01": "gg",
+		"3d000001": "number",
+		"_abc0001": "number",
+		"heart001": "h",
+	}
+	for name, subdir := range tests {
+		want := "https://media.merriam-webster.com/audio/prons/en/us/mp3/" + subdir + "/" + name + ".mp3"
+		if got := mwAudio(name); got != want {
+			t.Errorf("mwAudio(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -2,27 +2,66 @@ package duckduckgo
 
 // External DataSource Adapter for DuckDuckGo HTML search
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	datasource "github.com/locus-search/datasource-sdk"
 	goquery "github.com/PuerkitoBio/goquery"
+	"github.com/locus-search/datasource/chunker"
+	"github.com/locus-search/datasource/extract"
+	"github.com/locus-search/datasource/internal/robots"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/pdftext"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
 )
 
-const defaultQuestionCount = 5
+const (
+	defaultQuestionCount = 5
+	defaultDataCount     = 3
+	// PDF pages are grouped into blocks of about this many characters before chunking
+	chunkSize = 1500
+)
 
 type DataSourceDuckDuckGo struct {
 	Client     *http.Client
 	BaseURL    string
+	SuggestURL string // Autocomplete endpoint for Suggest; default https://ac.duckduckgo.com/ac/
 	UserAgent  string
 	SiteFilter string
 	Debug      bool // Print lightweight fetch diagnostics when true
+	Extractor  *extract.Extractor // Page text extraction for FetchData; default extract.Default
+	Chunker    *chunker.Chunker   // Splits page text into data items; default chunker.Default
+	// Archive, when set, serves pages found behind a paywall or consent wall, e.g. wayback.New()
+	Archive source.Archive
+	// AllowPrivate lets TopicID accept URLs on loopback, private and link-local hosts, for intranet
+	// deployments; by default only URLs of public hosts and of returned results are fetched
+	AllowPrivate bool
+
+	robots  *robots.Checker
+	results topicid.Map[result]
+	related topicid.Map[[]string] // Related searches by lowercased query
+}
+
+// result is a search result remembered for FetchData
+type result struct {
+	Title   string
+	URL     string
+	Query   string  // The search that found it
+	Snippet string  // The result's summary on the search page
+	Score   float64 // From the result's rank, see source.RankScore
+	Wall    string  // Set by FetchData when the page was walled
 }
 
 func New() *DataSourceDuckDuckGo {
@@ -31,6 +70,7 @@ func New() *DataSourceDuckDuckGo {
 			Timeout: 8 * time.Second,
 		},
 		BaseURL:    "https://duckduckgo.com/html/",
+		SuggestURL: "https://ac.duckduckgo.com/ac/",
 		UserAgent:  "locus/duckduckgo-datasource",
 		SiteFilter: "",
 	}
@@ -44,9 +84,15 @@ func (es *DataSourceDuckDuckGo) Init() error {
 	if es.BaseURL == "" {
 		es.BaseURL = "https://duckduckgo.com/html/"
 	}
+	if es.SuggestURL == "" {
+		es.SuggestURL = "https://ac.duckduckgo.com/ac/"
+	}
 	if es.UserAgent == "" {
 		es.UserAgent = "locus/duckduckgo-datasource"
 	}
+	if es.robots == nil {
+		es.robots = robots.NewChecker(es.Client, es.UserAgent)
+	}
 	return nil
 }
 
@@ -56,9 +102,9 @@ func (es *DataSourceDuckDuckGo) CheckAvailability() bool {
 	if err := es.Init(); err != nil {
 		return false
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
 	defer cancel()
-	searchURL := es.buildSearchURL("duckduckgo")
+	searchURL := es.buildSearchURL("duckduckgo", source.TopicOptions{}, 0)
 	resp, err := es.doRequest(ctx, searchURL)
 	if err != nil {
 		return false
@@ -67,38 +113,93 @@ func (es *DataSourceDuckDuckGo) CheckAvailability() bool {
 	return resp.StatusCode >= 200 && resp.StatusCode < 400
 }
 
+// Close implements source.Closer
+// Closes the client's idle connections; the adapter can still be used and opens new ones as needed
+func (es *DataSourceDuckDuckGo) Close(ctx context.Context) error {
+	if es.Client != nil {
+		es.Client.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceDuckDuckGo) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "DuckDuckGo",
+		Homepage: "https://duckduckgo.com",
+		TermsURL: "https://duckduckgo.com/terms",
+	}
+}
+
+// Describe implements source.Describer
+func (es *DataSourceDuckDuckGo) Describe() source.Capabilities {
+	return source.Capabilities{
+		Name:       es.Info().Name,
+		Data:       true,
+		Pagination: true,
+		Context:    true,
+		Options:    []string{source.OptionLanguage, source.OptionRegion, source.OptionSafeSearch, source.OptionTimeRange},
+	}
+}
+
 // FetchTopics implements models.DataSource
 func (es *DataSourceDuckDuckGo) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	return es.FetchTopicsContext(ctx, count, input)
+}
+
+// FetchTopicsContext implements source.ContextSource
+func (es *DataSourceDuckDuckGo) FetchTopicsContext(ctx context.Context, count int, input string) ([]datasource.DataSourceTopic, error) {
+	return es.FetchTopicsWithOptions(ctx, input, source.TopicOptions{Count: count})
+}
+
+// FetchTopicsWithOptions implements source.OptionsSource
+// Region and Language select DuckDuckGo's region (kl), SafeSearch its safe search level (kp) and
+// TimeRange its date filter (df)
+func (es *DataSourceDuckDuckGo) FetchTopicsWithOptions(ctx context.Context, input string, opts source.TopicOptions) ([]datasource.DataSourceTopic, error) {
+	page, err := es.FetchTopicsPage(ctx, input, opts, "")
+	return page.Topics, err
+}
+
+// FetchTopicsPage implements source.Pager
+// The cursor holds the offset of the page's first result, sent as DuckDuckGo's s and dc parameters
+func (es *DataSourceDuckDuckGo) FetchTopicsPage(ctx context.Context, input string, opts source.TopicOptions, cursor string) (source.Page, error) {
 	query := strings.TrimSpace(input)
 	if query == "" {
-		return nil, errors.New("Missing Search Input for DuckDuckGo data source")
+		return source.Page{}, fmt.Errorf("Missing Search Input for DuckDuckGo data source: %w", source.ErrBadQuery)
+	}
+	offset, err := source.ParseOffsetCursor(cursor)
+	if err != nil {
+		return source.Page{}, err
 	}
+	count := opts.Count
 	if count <= 0 {
 		count = defaultQuestionCount
 	}
 	if err := es.Init(); err != nil {
-		return nil, err
+		return source.Page{}, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
-	defer cancel()
-
-	searchURL := es.buildSearchURL(query)
+	searchURL := es.buildSearchURL(query, opts, offset)
 	if es.Debug {
 		fmt.Printf("[duckduckgo] search url: %s\n", searchURL)
 	}
 	resp, err := es.doRequest(ctx, searchURL)
 	if err != nil {
-		return nil, err
+		return source.Page{}, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("duckduckgo request failed: status %d", resp.StatusCode)
+		return source.Page{}, source.NewStatusError("duckduckgo", resp, "")
 	}
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return nil, err
+		return source.Page{}, err
+	}
+	if err := challenged(doc); err != nil {
+		return source.Page{}, err
 	}
 	if es.Debug {
 		pageTitle := strings.TrimSpace(doc.Find("title").First().Text())
@@ -129,11 +230,12 @@ func (es *DataSourceDuckDuckGo) FetchTopics(count int, input string) ([]datasour
 			return true
 		}
 		seen[resolved] = struct{}{}
+		snippet := normalizeWhitespace(s.Closest(".result").Find(".result__snippet").First().Text())
 
 		results = append(results, datasource.DataSourceTopic{
 			Topic:   normalizeWhitespace(title),
 			SourceURL:  resolved,
-			TopicID: urlToID(resolved),
+			TopicID: es.remember(normalizeWhitespace(title), resolved, snippet, source.RankScore(offset+len(results))),
 			Site:       "duckduckgo",
 		})
 		return true
@@ -141,29 +243,367 @@ func (es *DataSourceDuckDuckGo) FetchTopics(count int, input string) ([]datasour
 
 	// If standard anchors are missing, fall back to a site-filtered scan
 	if len(results) == 0 {
-		results = es.fallbackResultLinks(doc, count, seen)
+		results = es.fallbackResultLinks(doc, count, offset, seen)
 		if es.Debug {
 			fmt.Printf("[duckduckgo] fallback results: %d\n", len(results))
 		}
 	}
 
+	// Remember the query and its related searches for Related
+	if related := relatedSearches(doc); len(related) > 0 {
+		es.related.Put(strings.ToLower(query), related)
+	}
+	for _, t := range results {
+		if r, ok := es.results.Get(t.TopicID); ok {
+			r.Query = query
+			es.results.Set(t.TopicID, r)
+		}
+	}
+
 	if len(results) == 0 {
+		return source.Page{}, nil
+	}
+	page := source.Page{Topics: results}
+	// DuckDuckGo pages hold more results than usually asked for; a next-page form means there are more
+	if len(results) == count || doc.Find(`input[name="s"]`).Length() > 0 {
+		page.Next = source.OffsetCursor(offset + len(results))
+	}
+	return page, nil
+}
+
+// FetchData implements models.DataSource
+func (es *DataSourceDuckDuckGo) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	return es.FetchDataContext(ctx, count, topicID)
+}
+
+// FetchDataContext implements source.ContextSource
+// Fetches the result page, unless robots.txt disallows it, and returns its main text in items of about
+// 1500 characters, each starting with the page title. Pages with little article text yield their meta
+// description instead; PDF results are extracted page by page. Pages behind a paywall or consent wall
+// are read from Archive when it has a copy.
+func (es *DataSourceDuckDuckGo) FetchDataContext(ctx context.Context, count int, topicID int64) ([]datasource.DataSourceData, error) {
+	r, ok := es.results.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown DuckDuckGo topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultDataCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	allowed, err := es.robots.Allowed(ctx, r.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("robots.txt disallows %s: %w", r.URL, source.ErrBlocked)
+	}
+	resp, err := es.doRequest(ctx, r.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, source.NewStatusError("duckduckgo result", resp, "")
+	}
+	body := bufio.NewReader(resp.Body)
+	head, _ := body.Peek(5)
+	var paragraphs []string
+	if pdftext.IsPDF(resp.Header.Get("Content-Type"), head) {
+		// PDF results (papers, specs, reports) are extracted page by page
+		doc, err := pdftext.Default.Reader(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		paragraphs = doc.Chunks(chunkSize)
+		if es.Debug {
+			fmt.Printf("[duckduckgo] extracted %d of %d PDF pages from %s\n", len(doc.Pages), doc.NumPages, r.URL)
+		}
+	} else {
+		extractor := es.Extractor
+		if extractor == nil {
+			extractor = extract.Default
+		}
+		article, err := extractor.Reader(body, resp.Request.URL.String())
+		paragraphs = article.Paragraphs
+		switch {
+		case errors.Is(err, extract.ErrLowQuality) && article.Description != "":
+			paragraphs = []string{article.Description}
+		case err != nil && !errors.Is(err, extract.ErrLowQuality):
+			return nil, err
+		}
+		if es.Debug {
+			fmt.Printf("[duckduckgo] extracted %d paragraphs (quality %.2f) from %s\n", len(article.Paragraphs), article.Quality, r.URL)
+		}
+		// Pages fetched by ID are titled with their URL until read
+		if r.Title == r.URL && article.Title != "" {
+			r.Title = article.Title
+			es.results.Set(topicID, r)
+		}
+		// Walled pages are read from the archive when it has a copy; otherwise the teaser is returned
+		wall := article.Wall
+		var archived []datasource.DataSourceData
+		if wall != "" {
+			if archived = es.archived(ctx, r, count); archived != nil {
+				wall = ""
+			}
+		}
+		if r.Wall != wall {
+			r.Wall = wall
+			es.results.Set(topicID, r)
+		}
+		if archived != nil {
+			return archived, nil
+		}
+	}
+
+	c := es.Chunker
+	if c == nil {
+		c = chunker.Default
+	}
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, chunk := range c.Paragraphs(paragraphs) {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		if chunk.Index > 0 {
+			answerID = urlToID(fmt.Sprintf("%s#%d", r.URL, chunk.Index))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  r.Title + "\n" + chunk.Text,
+			SourceURL: r.URL,
+			Site:      "duckduckgo",
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// Wall implements source.Walled
+func (es *DataSourceDuckDuckGo) Wall(topicID int64) string {
+	r, _ := es.results.Get(topicID)
+	return r.Wall
+}
+
+// FetchHost implements fetcher.Hoster
+// FetchData fetches each result's page from the page's own host
+func (es *DataSourceDuckDuckGo) FetchHost(topicID int64) string {
+	r, ok := es.results.Get(topicID)
+	if !ok {
+		return ""
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// StringID implements source.IDSource
+// A result's ID is its URL, which the int64 topic ID is a hash of
+func (es *DataSourceDuckDuckGo) StringID(topicID int64) (source.ID, bool) {
+	r, ok := es.results.Get(topicID)
+	if !ok {
+		return "", false
+	}
+	return source.ID(r.URL), true
+}
+
+// TopicID implements source.IDSource
+// Any public page's URL is an ID; FetchData then fetches the page whether or not a search returned it.
+// URLs of hosts that are or resolve to loopback, private or link-local addresses are rejected unless
+// AllowPrivate is set, so IDs from untrusted callers can't reach internal services.
+func (es *DataSourceDuckDuckGo) TopicID(id source.ID) (int64, error) {
+	u, err := url.Parse(string(id))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return 0, fmt.Errorf("topic ID %q is not a URL: %w", id, source.ErrBadQuery)
+	}
+	topicID := urlToID(string(id))
+	if _, ok := es.results.Get(topicID); ok {
+		return topicID, nil
+	}
+	if !es.AllowPrivate {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+		defer cancel()
+		if err := publicHost(ctx, u.Hostname()); err != nil {
+			return 0, fmt.Errorf("topic ID %q: %v: %w", id, err, source.ErrBadQuery)
+		}
+	}
+	es.results.Set(topicID, result{Title: string(id), URL: string(id)})
+	return topicID, nil
+}
+
+// Details implements source.Detailed
+// DuckDuckGo's HTML results carry a snippet but no dates, authors, thumbnails or scores; Score comes
+// from the rank
+func (es *DataSourceDuckDuckGo) Details(topicID int64) (source.TopicDetails, bool) {
+	r, ok := es.results.Get(topicID)
+	if !ok {
+		return source.TopicDetails{}, false
+	}
+	return source.TopicDetails{Snippet: r.Snippet, Score: r.Score}, true
+}
+
+// Suggest implements source.Suggester
+// Queries DuckDuckGo's autocomplete endpoint; the site filter does not apply
+func (es *DataSourceDuckDuckGo) Suggest(ctx context.Context, prefix string, count int) ([]string, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
 		return nil, nil
 	}
+	if count <= 0 {
+		count = 10
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	values.Set("q", prefix)
+	values.Set("type", "list")
+	resp, err := es.doRequest(ctx, es.SuggestURL+"?"+values.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, source.NewStatusError("duckduckgo autocomplete", resp, "")
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := parseSuggestions(body)
+	if err != nil {
+		return nil, fmt.Errorf("duckduckgo autocomplete: unexpected response: %w", err)
+	}
+	if len(suggestions) > count {
+		suggestions = suggestions[:count]
+	}
+	return suggestions, nil
+}
+
+// Related implements source.RelatedSource
+// Searches the related searches DuckDuckGo listed for the query that found the topic, taking results
+// from each in turn. Without related searches, the topic's title is searched instead.
+func (es *DataSourceDuckDuckGo) Related(ctx context.Context, topicID int64, count int) ([]datasource.DataSourceTopic, error) {
+	r, ok := es.results.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown DuckDuckGo topicID %d: %w", topicID, source.ErrBadQuery)
+	}
+	if count <= 0 {
+		count = defaultQuestionCount
+	}
+
+	var found [][]datasource.DataSourceTopic
+	phrases, _ := es.related.Get(topicid.Hash(strings.ToLower(r.Query)))
+	if len(phrases) == 0 {
+		// The title search also lists related searches, which are better than its own results
+		page, err := es.FetchTopicsPage(ctx, r.Title, source.TopicOptions{Count: count + 1}, "")
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, page.Topics)
+		phrases, _ = es.related.Get(topicid.Hash(strings.ToLower(r.Title)))
+	}
+	if len(phrases) > 0 {
+		found = found[:0]
+		// A few related searches, each asked for its share of count, keep the request count low
+		phrases = phrases[:min(len(phrases), 3)]
+		share := (count+len(phrases)-1)/len(phrases) + 1
+		for _, phrase := range phrases {
+			page, err := es.FetchTopicsPage(ctx, phrase, source.TopicOptions{Count: share}, "")
+			if err != nil {
+				return nil, err
+			}
+			found = append(found, page.Topics)
+		}
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	seen := map[int64]bool{topicID: true}
+	for i := 0; len(results) < count; i++ {
+		added := false
+		for _, topics := range found {
+			if i >= len(topics) {
+				continue
+			}
+			added = true
+			if t := topics[i]; !seen[t.TopicID] && len(results) < count {
+				seen[t.TopicID] = true
+				results = append(results, t)
+			}
+		}
+		if !added {
+			break
+		}
+	}
 	return results, nil
 }
 
-// FetchData implements models.DataSource. 
-// DuckDuckGo does not provide a way to fetch detailed data for a topic, so this is a no-op.
-func (es *DataSourceDuckDuckGo) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
-	return []datasource.DataSourceData{}, nil
+// archived returns up to count items from Archive's copy of a result page, each starting with the
+// title; nil when there is no Archive or it has no copy
+func (es *DataSourceDuckDuckGo) archived(ctx context.Context, r result, count int) []datasource.DataSourceData {
+	if es.Archive == nil {
+		return nil
+	}
+	data, err := es.Archive.FetchArchived(ctx, r.URL)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	if len(data) > count {
+		data = data[:count]
+	}
+	for i := range data {
+		data[i].DataText = r.Title + "\n" + data[i].DataText
+	}
+	return data
 }
 
-// buildSearchURL constructs the DuckDuckGo search URL with the given query and site filter if set.
-func (es *DataSourceDuckDuckGo) buildSearchURL(query string) string {
+// remember records a result for FetchData and Details and returns its topic ID
+func (es *DataSourceDuckDuckGo) remember(title, resolved, snippet string, score float64) int64 {
+	id := urlToID(resolved)
+	es.results.Set(id, result{Title: title, URL: resolved, Snippet: snippet, Score: score})
+	return id
+}
+
+// buildSearchURL constructs the DuckDuckGo search URL with the given query and site filter if set,
+// the options DuckDuckGo supports, and the offset of the first result.
+func (es *DataSourceDuckDuckGo) buildSearchURL(query string, opts source.TopicOptions, offset int) string {
 	base := strings.TrimRight(es.BaseURL, "/")
 	values := url.Values{}
 	values.Set("q", es.buildQuery(query))
+	if offset > 0 {
+		values.Set("s", strconv.Itoa(offset))
+		values.Set("dc", strconv.Itoa(offset+1))
+	}
+	if kl := regionCode(opts.Region, opts.Language); kl != "" {
+		values.Set("kl", kl)
+	}
+	switch opts.SafeSearch {
+	case source.SafeOff:
+		values.Set("kp", "-2")
+	case source.SafeModerate:
+		values.Set("kp", "-1")
+	case source.SafeStrict:
+		values.Set("kp", "1")
+	}
+	switch opts.TimeRange {
+	case source.PastDay:
+		values.Set("df", "d")
+	case source.PastWeek:
+		values.Set("df", "w")
+	case source.PastMonth:
+		values.Set("df", "m")
+	case source.PastYear:
+		values.Set("df", "y")
+	}
 	return fmt.Sprintf("%s/?%s", base, values.Encode())
 }
 
@@ -181,7 +621,8 @@ func (es *DataSourceDuckDuckGo) buildQuery(query string) string {
 }
 
 // fallbackResultLinks performs a broad scan of all anchor tags in the document to find links matching the site filter.
-func (es *DataSourceDuckDuckGo) fallbackResultLinks(doc *goquery.Document, count int, seen map[string]struct{}) []datasource.DataSourceTopic {
+// offset is the rank of the page's first result.
+func (es *DataSourceDuckDuckGo) fallbackResultLinks(doc *goquery.Document, count, offset int, seen map[string]struct{}) []datasource.DataSourceTopic {
 	targetHost := strings.TrimSpace(es.SiteFilter)
 	if targetHost == "" {
 		return nil
@@ -224,7 +665,7 @@ func (es *DataSourceDuckDuckGo) fallbackResultLinks(doc *goquery.Document, count
 		results = append(results, datasource.DataSourceTopic{
 			Topic:   normalizeWhitespace(title),
 			SourceURL:  resolved,
-			TopicID: urlToID(resolved),
+			TopicID: es.remember(normalizeWhitespace(title), resolved, "", source.RankScore(offset+len(results))),
 			Site:       "duckduckgo",
 		})
 		return true
@@ -284,6 +725,38 @@ func (es *DataSourceDuckDuckGo) doRequest(ctx context.Context, target string) (*
 }
 
 // Helpers
+
+// lookupIPAddr resolves host names for publicHost; replaced in tests
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// publicHost returns an error unless host is a public address or a name whose addresses all are. Names
+// are checked when resolved, not when the page is fetched.
+func publicHost(ctx context.Context, host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%s is not a public host", host)
+	}
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = append(ips, ip)
+	} else {
+		addrs, err := lookupIPAddr(ctx, host)
+		if err != nil {
+			return err
+		}
+		for _, a := range addrs {
+			ips = append(ips, a.IP)
+		}
+	}
+	for _, ip := range ips {
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+			ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+			return fmt.Errorf("%s is not a public address", ip)
+		}
+	}
+	return nil
+}
+
 func urlToID(raw string) int64 {
 	h := fnv.New64a()
 	_, _ = h.Write([]byte(raw))
@@ -293,3 +766,86 @@ func normalizeWhitespace(in string) string {
 	fields := strings.Fields(in)
 	return strings.Join(fields, " ")
 }
+
+// challenged returns ErrBlocked or ErrRateLimited when DuckDuckGo answered a search with a bot check
+// or a rate-limit notice instead of results
+func challenged(doc *goquery.Document) error {
+	if doc.Find(".anomaly-modal, .anomaly-modal__title, #challenge-form, form[action*='anomaly']").Length() > 0 {
+		return fmt.Errorf("duckduckgo served a bot check: %w", source.ErrBlocked)
+	}
+	text := strings.ToLower(doc.Find("body").Text())
+	switch {
+	case strings.Contains(text, "bots use duckduckgo too"), strings.Contains(text, "select all squares containing a duck"):
+		return fmt.Errorf("duckduckgo served a bot check: %w", source.ErrBlocked)
+	case strings.Contains(text, "if this error persists") && strings.Contains(text, "too many requests"):
+		return fmt.Errorf("duckduckgo is throttling requests: %w", source.ErrRateLimited)
+	}
+	return nil
+}
+
+// Default languages of regions whose DuckDuckGo code does not repeat the region, e.g. "us-en" rather
+// than "us-us"
+var regionLanguages = map[string]string{
+	"us": "en", "uk": "en", "au": "en", "ca": "en", "ie": "en", "in": "en", "nz": "en", "za": "en",
+	"sg": "en", "ph": "en", "my": "en", "pk": "en", "at": "de", "ch": "de", "be": "fr", "mx": "es",
+	"ar": "es", "cl": "es", "co": "es", "pe": "es", "br": "pt", "tw": "tzh", "hk": "tzh", "cn": "zh",
+	"se": "sv", "dk": "da", "il": "he", "ua": "uk", "gr": "el", "cz": "cs",
+}
+
+// regionCode returns DuckDuckGo's kl value for an ISO region and language code, e.g. "ch-fr"; empty
+// without a region, leaving DuckDuckGo's default of all regions
+func regionCode(region, language string) string {
+	region = strings.ToLower(strings.TrimSpace(region))
+	language = strings.ToLower(strings.TrimSpace(language))
+	if region == "" {
+		return ""
+	}
+	if region == "gb" {
+		region = "uk"
+	}
+	if language == "" {
+		language = regionLanguages[region]
+	}
+	if language == "" {
+		language = region
+	}
+	return region + "-" + language
+}
+
+// relatedSearches returns the queries a results page suggests as related searches
+func relatedSearches(doc *goquery.Document) []string {
+	var phrases []string
+	doc.Find(".related-searches a, .related-searches__item a, #related_searches a").Each(func(_ int, s *goquery.Selection) {
+		if phrase := normalizeWhitespace(s.Text()); phrase != "" {
+			phrases = append(phrases, phrase)
+		}
+	})
+	return phrases
+}
+
+// parseSuggestions reads autocomplete responses in the list format, [query, [suggestions]], or the
+// default format, [{"phrase": suggestion}, ...]
+func parseSuggestions(body []byte) ([]string, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, err
+	}
+	var suggestions []string
+	if len(items) == 2 && json.Unmarshal(items[1], &suggestions) == nil {
+		return suggestions, nil
+	}
+	suggestions = nil
+	for _, item := range items {
+		var p struct {
+			Phrase string `json:"phrase"`
+		}
+		if err := json.Unmarshal(item, &p); err != nil {
+			return nil, err
+		}
+		if p.Phrase != "" {
+			suggestions = append(suggestions, p.Phrase)
+		}
+	}
+	return suggestions, nil
+}
+
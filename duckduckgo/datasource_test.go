@@ -0,0 +1,170 @@
+package duckduckgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/locus-search/datasource/source"
+)
+
+const resultsPage = `<html><body>
+<div class="result"><a class="result__a" href="//duckduckgo.com/l/?uddg=https%3A%2F%2Fgo.dev%2Fdoc%2F&rut=x">Go  Documentation</a>
+<a class="result__snippet">The Go programming language documentation.</a></div>
+<div class="result"><a class="result__a" href="https://duckduckgo.com/y.js?ad_domain=example.com">Ad</a></div>
+<div class="result"><a class="result__a" href="https://pkg.go.dev/">Go Packages</a></div>
+<div class="result"><a class="result__url" href="https://pkg.go.dev/">pkg.go.dev</a></div>
+<div class="related-searches"><a>golang tutorial</a></div>
+</body></html>`
+
+func TestFetchTopics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "site:go.dev golang" || q.Get("kl") != "us-en" || q.Get("df") != "w" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if q.Get("s") != "10" || q.Get("dc") != "11" {
+			t.Errorf("offset s=%q dc=%q, want 10 and 11", q.Get("s"), q.Get("dc"))
+		}
+		fmt.Fprint(w, resultsPage)
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.BaseURL = srv.URL + "/html/"
+	es.SiteFilter = "go.dev"
+	page, err := es.FetchTopicsPage(context.Background(), "golang", source.TopicOptions{Count: 2, Region: "us", TimeRange: source.PastWeek}, source.OffsetCursor(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	topics := page.Topics
+	if len(topics) != 2 {
+		t.Fatalf("FetchTopicsPage = %+v", topics)
+	}
+	if topics[0].Topic != "Go Documentation" || topics[0].SourceURL != "https://go.dev/doc/" {
+		t.Errorf("topic 0 = %+v, want the uddg target", topics[0])
+	}
+	if topics[1].SourceURL != "https://pkg.go.dev/" {
+		t.Errorf("topic 1 = %+v, want the ad skipped", topics[1])
+	}
+	if page.Next != source.OffsetCursor(12) {
+		t.Errorf("Next = %q, want %q", page.Next, source.OffsetCursor(12))
+	}
+	if d, ok := es.Details(topics[0].TopicID); !ok || d.Snippet != "The Go programming language documentation." {
+		t.Errorf("Details = %+v, %v", d, ok)
+	}
+	if id, ok := es.StringID(topics[0].TopicID); !ok || id != "https://go.dev/doc/" {
+		t.Errorf("StringID = %q, %v", id, ok)
+	}
+}
+
+func TestFetchTopicsBotCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><div class="anomaly-modal">Unfortunately, bots use DuckDuckGo too.</div></body></html>`)
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.BaseURL = srv.URL
+	if _, err := es.FetchTopics(5, "golang"); !errors.Is(err, source.ErrBlocked) {
+		t.Errorf("FetchTopics on a bot check = %v, want ErrBlocked", err)
+	}
+}
+
+func TestFetchDataByID(t *testing.T) {
+	paragraph := strings.Repeat("Go is an open source programming language that makes it simple to build software. ", 8)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `<html><head><title>About Go</title></head><body><article><h1>About Go</h1><p>%s</p><p>%s</p></article></body></html>`, paragraph, paragraph)
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.AllowPrivate = true // The test server is on loopback
+	id := source.ID(srv.URL + "/about")
+	data, err := source.FetchDataByID(context.Background(), es, 1, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || !strings.HasPrefix(data[0].DataText, "About Go\n") || data[0].SourceURL != string(id) {
+		t.Fatalf("FetchDataByID = %+v", data)
+	}
+	if got, ok := es.StringID(data[0].AnswerID); !ok || got != id {
+		t.Errorf("StringID(%d) = %q, %v", data[0].AnswerID, got, ok)
+	}
+	if _, err := es.TopicID("not a url"); err == nil {
+		t.Error("TopicID accepted an ID that is not a URL")
+	}
+}
+
+func TestTopicIDPrivateHosts(t *testing.T) {
+	lookup := lookupIPAddr
+	defer func() { lookupIPAddr = lookup }()
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "intranet.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("10.1.2.3")}}, nil
+		case "go.dev":
+			return []net.IPAddr{{IP: net.ParseIP("216.239.32.21")}}, nil
+		}
+		return nil, fmt.Errorf("no such host %s", host)
+	}
+
+	es := New()
+	for _, id := range []source.ID{
+		"http://localhost:8080/admin",
+		"http://127.0.0.1/",
+		"http://[::1]/",
+		"http://10.0.0.5/metrics",
+		"http://192.168.1.1/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://0.0.0.0/",
+		"https://intranet.example.com/wiki", // Resolves to a private address among public ones
+		"https://unresolvable.example/",
+	} {
+		if _, err := es.TopicID(id); !errors.Is(err, source.ErrBadQuery) {
+			t.Errorf("TopicID(%q) = %v, want ErrBadQuery", id, err)
+		}
+	}
+	if _, err := es.TopicID("https://go.dev/doc/"); err != nil {
+		t.Errorf("TopicID of a public page = %v", err)
+	}
+
+	// Results a search returned are resolved without a lookup
+	topicID := es.remember("Intranet", "http://10.0.0.5/wiki", "", 0)
+	if got, err := es.TopicID("http://10.0.0.5/wiki"); err != nil || got != topicID {
+		t.Errorf("TopicID of a returned result = %d, %v; want %d", got, err, topicID)
+	}
+}
+
+func TestParseSuggestions(t *testing.T) {
+	for _, body := range []string{`["go",["golang","go tour"]]`, `[{"phrase":"golang"},{"phrase":"go tour"}]`} {
+		got, err := parseSuggestions([]byte(body))
+		if err != nil || strings.Join(got, "|") != "golang|go tour" {
+			t.Errorf("parseSuggestions(%s) = %q, %v", body, got, err)
+		}
+	}
+}
+
+func TestRegionCode(t *testing.T) {
+	tests := map[[2]string]string{
+		{"", "en"}:   "",
+		{"us", ""}:   "us-en",
+		{"GB", ""}:   "uk-en",
+		{"ch", "fr"}: "ch-fr",
+		{"de", ""}:   "de-de",
+	}
+	for in, want := range tests {
+		if got := regionCode(in[0], in[1]); got != want {
+			t.Errorf("regionCode(%q, %q) = %q, want %q", in[0], in[1], got, want)
+		}
+	}
+}
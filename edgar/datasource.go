@@ -0,0 +1,487 @@
+package edgar
+
+// Data Source Adapter for SEC EDGAR filings (full-text search and submissions APIs)
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/pdftext"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/net/html"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultTopicCount = 5
+	// Sections shorter than this are table-of-contents entries rather than content
+	minSectionLength = 200
+)
+
+var (
+	// itemHeading matches the item headings of periodic reports, e.g. "Item 1A. Risk Factors" or "ITEM 7."
+	itemHeading = regexp.MustCompile(`(?i)^item\s+(\d{1,2}[A-C]?)[.:\s]`)
+	// cikInput matches queries that name a company by CIK, e.g. "320193" or "CIK0000320193"
+	cikInput = regexp.MustCompile(`(?i)^(?:cik\s*:?\s*)?(\d{1,10})$`)
+)
+
+type DataSourceEDGAR struct {
+	Client         *http.Client
+	SearchURL      string   // EDGAR full-text search endpoint
+	SubmissionsURL string   // data.sec.gov submissions root
+	ArchivesURL    string   // Root of the filing archives
+	UserAgent      string   // The SEC requires "Company Name admin@example.com" style identification
+	Forms          []string // Restrict to form types, e.g. "10-K", "10-Q", "8-K"
+	From           string   // Earliest filing date, YYYY-MM-DD
+	To             string   // Latest filing date, YYYY-MM-DD
+
+	filings     topicid.Map[filing]
+	rateLimiter *rate.Limiter
+}
+
+// filing locates the primary document of a filing
+type filing struct {
+	CIK       string
+	Accession string // With dashes, e.g. 0000320193-23-000106
+	Document  string
+	Form      string
+	Company   string
+	Date      string
+}
+
+func New() *DataSourceEDGAR {
+	return &DataSourceEDGAR{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		SearchURL:      "https://efts.sec.gov/LATEST/search-index",
+		SubmissionsURL: "https://data.sec.gov/submissions",
+		ArchivesURL:    "https://www.sec.gov/Archives/edgar/data",
+	}
+}
+
+// Init implements models.DataSource
+// Requires User-Agent identification and applies the SEC fair-access limit of 10 requests per second
+func (es *DataSourceEDGAR) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if strings.TrimSpace(es.UserAgent) == "" {
+		return errors.New("UserAgent with a contact email is required for EDGAR DataSource")
+	}
+	if es.rateLimiter == nil {
+		es.rateLimiter = rate.NewLimiter(rate.Limit(10), 1)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceEDGAR) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	return es.doJSON(ctx, es.SearchURL+"?q=%22annual+report%22", &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceEDGAR) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "SEC EDGAR",
+		Homepage:    "https://www.sec.gov/edgar",
+		License:     "Public domain",
+		Attribution: "U.S. Securities and Exchange Commission",
+		TermsURL:    "https://www.sec.gov/about/privacy-information#security",
+	}
+}
+
+// Describe implements source.Describer
+func (es *DataSourceEDGAR) Describe() source.Capabilities {
+	return source.Capabilities{Name: es.Info().Name, Data: true, RateLimit: source.RateLimit{Requests: 10, Per: time.Second}}
+}
+
+// FetchTopics implements models.DataSource
+// Runs a full-text search, or lists a company's recent filings when the input is a CIK.
+// Topics read "10-K: Apple Inc. (2023-11-03)"
+func (es *DataSourceEDGAR) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for EDGAR DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	var filings []filing
+	var err error
+	if m := cikInput.FindStringSubmatch(query); m != nil {
+		filings, err = es.companyFilings(ctx, m[1], count)
+	} else {
+		filings, err = es.search(ctx, query, count)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(filings))
+	for _, f := range filings {
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s: %s (%s)", f.Form, f.Company, f.Date),
+			SourceURL: es.documentURL(f),
+			Site:      f.Company,
+			TopicID:   es.filings.Put(f.Accession+"/"+f.Document, f),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Downloads the primary document and returns up to count sections. Periodic reports are split on their
+// "Item" headings; other documents are returned in paragraph-sized chunks. PDF exhibits are extracted
+// page by page.
+func (es *DataSourceEDGAR) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	f, ok := es.filings.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown EDGAR topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	body, err := es.get(ctx, es.documentURL(f))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var text string
+	switch document := strings.ToLower(f.Document); {
+	case strings.HasSuffix(document, ".txt"):
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		text = string(raw)
+	case strings.HasSuffix(document, ".pdf"):
+		doc, err := pdftext.Default.Reader(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		text = doc.Text()
+	default:
+		root, err := html.Parse(body)
+		if err != nil {
+			return nil, err
+		}
+		text = documentText(root)
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, s := range splitItems(text) {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  s.Text,
+			SourceURL: es.documentURL(f),
+			Site:      f.Company,
+			AnswerID:  topicid.Hash(f.Accession + "#" + s.Item),
+		})
+	}
+	return results, nil
+}
+
+// search queries EDGAR full-text search; each hit names the accession and document as "adsh:filename"
+func (es *DataSourceEDGAR) search(ctx context.Context, query string, count int) ([]filing, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	if len(es.Forms) > 0 {
+		params.Set("forms", strings.Join(es.Forms, ","))
+	}
+	if es.From != "" || es.To != "" {
+		params.Set("dateRange", "custom")
+		params.Set("startdt", es.From)
+		params.Set("enddt", es.To)
+	}
+
+	var response struct {
+		Hits struct {
+			Hits []struct {
+				ID     string `json:"_id"`
+				Source struct {
+					CIKs         []string `json:"ciks"`
+					DisplayNames []string `json:"display_names"`
+					Form         string   `json:"form"`
+					FileDate     string   `json:"file_date"`
+				} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := es.doJSON(ctx, es.SearchURL+"?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	filings := make([]filing, 0, count)
+	seen := map[string]bool{}
+	for _, hit := range response.Hits.Hits {
+		if len(filings) >= count {
+			break
+		}
+		accession, document, found := strings.Cut(hit.ID, ":")
+		if !found || len(hit.Source.CIKs) == 0 || seen[accession] {
+			continue
+		}
+		seen[accession] = true
+		company := ""
+		if len(hit.Source.DisplayNames) > 0 {
+			// Display names carry the tickers and CIK, e.g. "Apple Inc.  (AAPL)  (CIK 0000320193)"
+			company = strings.TrimSpace(strings.SplitN(hit.Source.DisplayNames[0], "  (", 2)[0])
+		}
+		filings = append(filings, filing{
+			CIK:       strings.TrimLeft(hit.Source.CIKs[0], "0"),
+			Accession: accession,
+			Document:  document,
+			Form:      hit.Source.Form,
+			Company:   company,
+			Date:      hit.Source.FileDate,
+		})
+	}
+	return filings, nil
+}
+
+// companyFilings lists the most recent filings of a company from its submissions record
+func (es *DataSourceEDGAR) companyFilings(ctx context.Context, cik string, count int) ([]filing, error) {
+	number, err := strconv.ParseInt(cik, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	var response struct {
+		Name    string `json:"name"`
+		Filings struct {
+			Recent struct {
+				AccessionNumber []string `json:"accessionNumber"`
+				Form            []string `json:"form"`
+				FilingDate      []string `json:"filingDate"`
+				PrimaryDocument []string `json:"primaryDocument"`
+			} `json:"recent"`
+		} `json:"filings"`
+	}
+	uri := fmt.Sprintf("%s/CIK%010d.json", strings.TrimRight(es.SubmissionsURL, "/"), number)
+	if err := es.doJSON(ctx, uri, &response); err != nil {
+		return nil, err
+	}
+
+	recent := response.Filings.Recent
+	filings := make([]filing, 0, count)
+	for i := range recent.AccessionNumber {
+		if len(filings) >= count {
+			break
+		}
+		if i >= len(recent.Form) || i >= len(recent.FilingDate) || i >= len(recent.PrimaryDocument) {
+			break
+		}
+		if !es.wantForm(recent.Form[i]) || !es.inDateRange(recent.FilingDate[i]) || recent.PrimaryDocument[i] == "" {
+			continue
+		}
+		filings = append(filings, filing{
+			CIK:       strconv.FormatInt(number, 10),
+			Accession: recent.AccessionNumber[i],
+			Document:  recent.PrimaryDocument[i],
+			Form:      recent.Form[i],
+			Company:   response.Name,
+			Date:      recent.FilingDate[i],
+		})
+	}
+	return filings, nil
+}
+
+func (es *DataSourceEDGAR) wantForm(form string) bool {
+	if len(es.Forms) == 0 {
+		return true
+	}
+	for _, want := range es.Forms {
+		if strings.EqualFold(want, form) {
+			return true
+		}
+	}
+	return false
+}
+
+// inDateRange compares ISO dates as strings, which orders them correctly
+func (es *DataSourceEDGAR) inDateRange(date string) bool {
+	return (es.From == "" || date >= es.From) && (es.To == "" || date <= es.To)
+}
+
+func (es *DataSourceEDGAR) documentURL(f filing) string {
+	return fmt.Sprintf("%s/%s/%s/%s", strings.TrimRight(es.ArchivesURL, "/"), f.CIK, strings.ReplaceAll(f.Accession, "-", ""), f.Document)
+}
+
+// doJSON performs an HTTP GET request and decodes the JSON response into target
+func (es *DataSourceEDGAR) doJSON(ctx context.Context, uri string, target interface{}) error {
+	body, err := es.get(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(target)
+}
+
+// get waits for the rate limiter, then performs an HTTP GET request. The caller must close the returned body.
+func (es *DataSourceEDGAR) get(ctx context.Context, uri string) (io.ReadCloser, error) {
+	if es.rateLimiter != nil {
+		if err := es.rateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", es.UserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("edgar request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// Helpers
+
+// item is one section of a filing, keyed by its item number ("1A", "7") or a running chunk number
+type item struct {
+	Item string
+	Text string
+}
+
+// splitItems splits filing text on "Item" headings, dropping table-of-contents stubs.
+// Documents without item headings (8-K exhibits, proxy statements) are chunked by size instead.
+func splitItems(text string) []item {
+	var items []item
+	var current *item
+	var lines []string
+	flush := func() {
+		if current != nil {
+			current.Text = strings.TrimSpace(strings.Join(lines, "\n"))
+			if len(current.Text) >= minSectionLength {
+				items = append(items, *current)
+			}
+		}
+		lines = lines[:0]
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if m := itemHeading.FindStringSubmatch(line); m != nil && len(line) < 200 {
+			flush()
+			current = &item{Item: "item-" + strings.ToUpper(m[1])}
+		}
+		if current != nil {
+			lines = append(lines, line)
+		}
+	}
+	flush()
+	if len(items) > 0 {
+		return items
+	}
+	return chunkText(text, 4000)
+}
+
+// chunkText groups lines into chunks of roughly size bytes
+func chunkText(text string, size int) []item {
+	var items []item
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if b.Len() > 0 && b.Len()+len(line) > size {
+			items = append(items, item{Item: fmt.Sprintf("part-%d", len(items)+1), Text: b.String()})
+			b.Reset()
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+	}
+	if b.Len() > 0 {
+		items = append(items, item{Item: fmt.Sprintf("part-%d", len(items)+1), Text: b.String()})
+	}
+	return items
+}
+
+// documentText flattens filing HTML to text with one line per block element.
+// Inline XBRL hides its header facts in ix:header, which is skipped along with scripts and styles.
+func documentText(root *html.Node) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			if text := strings.Join(strings.Fields(n.Data), " "); text != "" {
+				if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+					b.WriteByte(' ')
+				}
+				b.WriteString(text)
+			}
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "script", "style", "head", "ix:header":
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "p", "div", "br", "tr", "li", "h1", "h2", "h3", "h4", "h5", "h6", "table":
+				b.WriteByte('\n')
+			}
+		}
+	}
+	walk(root)
+	return b.String()
+}
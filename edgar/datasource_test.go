@@ -0,0 +1,119 @@
+package edgar
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var (
+	riskFactors = strings.Repeat("Supply chains may be disrupted. ", 10)
+	mdna        = strings.Repeat("Net sales increased in all segments. ", 10)
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "supply chain" || q.Get("forms") != "10-K" || q.Get("dateRange") != "custom" || q.Get("startdt") != "2023-01-01" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("User-Agent") != "Example admin@example.com" {
+			t.Errorf("User-Agent = %q", r.Header.Get("User-Agent"))
+		}
+		fmt.Fprint(w, `{"hits":{"hits":[
+			{"_id":"0000320193-23-000106:aapl-20230930.htm","_source":{"ciks":["0000320193"],"display_names":["Apple Inc.  (AAPL)  (CIK 0000320193)"],"form":"10-K","file_date":"2023-11-03"}},
+			{"_id":"0000320193-23-000106:ex21.htm","_source":{"ciks":["0000320193"],"display_names":["Apple Inc.  (AAPL)  (CIK 0000320193)"],"form":"10-K","file_date":"2023-11-03"}},
+			{"_id":"malformed","_source":{"ciks":["1"]}}
+		]}}`)
+	})
+	mux.HandleFunc("/submissions/CIK0000320193.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"Apple Inc.","filings":{"recent":{
+			"accessionNumber":["0000320193-24-000001","0000320193-23-000106"],
+			"form":["8-K","10-K"],
+			"filingDate":["2024-02-01","2023-11-03"],
+			"primaryDocument":["aapl-8k.htm","aapl-20230930.htm"]
+		}}}`)
+	})
+	mux.HandleFunc("/Archives/320193/000032019323000106/aapl-20230930.htm", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<html><head><title>10-K</title></head><body>
+			<ix:header><p>dei:EntityRegistrantName</p></ix:header>
+			<p>Item 1A. Risk Factors</p><p>Item 7. Management's Discussion</p>
+			<p>Item 1A. Risk Factors</p><p>%s</p>
+			<p>Item 7. Management's Discussion</p><div>%s</div>
+		</body></html>`, riskFactors, mdna)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSource(t *testing.T) *DataSourceEDGAR {
+	t.Helper()
+	srv := newServer(t)
+	es := New()
+	es.SearchURL = srv.URL + "/search"
+	es.SubmissionsURL = srv.URL + "/submissions"
+	es.ArchivesURL = srv.URL + "/Archives"
+	es.UserAgent = "Example admin@example.com"
+	es.Forms = []string{"10-K"}
+	es.From = "2023-01-01"
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "supply chain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "10-K: Apple Inc. (2023-11-03)" || topics[0].Site != "Apple Inc." {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	if want := es.ArchivesURL + "/320193/000032019323000106/aapl-20230930.htm"; topics[0].SourceURL != want {
+		t.Errorf("SourceURL = %q, want %q", topics[0].SourceURL, want)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	if !strings.HasPrefix(data[0].DataText, "Item 1A. Risk Factors\nSupply chains") || strings.Contains(data[0].DataText, "dei:") {
+		t.Errorf("data 0 = %q", data[0].DataText)
+	}
+	if !strings.HasPrefix(data[1].DataText, "Item 7. Management's Discussion\nNet sales") {
+		t.Errorf("data 1 = %q", data[1].DataText)
+	}
+}
+
+func TestFetchTopicsByCIK(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "CIK0000320193")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The 8-K is outside the form filter
+	if len(topics) != 1 || topics[0].Topic != "10-K: Apple Inc. (2023-11-03)" {
+		t.Errorf("FetchTopics = %+v", topics)
+	}
+}
+
+func TestInitRequiresUserAgent(t *testing.T) {
+	if err := New().Init(); err == nil {
+		t.Error("Init succeeded without a User-Agent")
+	}
+}
+
+func TestSplitItemsChunksUnstructuredText(t *testing.T) {
+	line := strings.Repeat("x", 100)
+	items := splitItems(strings.Repeat(line+"\n", 100))
+	if len(items) != 3 || items[0].Item != "part-1" || items[2].Item != "part-3" {
+		t.Errorf("splitItems returned %d items", len(items))
+	}
+}
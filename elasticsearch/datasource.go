@@ -0,0 +1,393 @@
+package elasticsearch
+
+// Data Source Adapter for Elasticsearch and OpenSearch indexes
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/jsonpath"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultPageSize   = 100
+)
+
+// Query template placeholders
+const (
+	// PlaceholderQuery is replaced with the search input as a JSON string, quotes included
+	PlaceholderQuery = "{{query}}"
+	// PlaceholderSize is replaced with the number of hits requested
+	PlaceholderSize = "{{size}}"
+)
+
+type DataSourceElasticsearch struct {
+	Client    *http.Client
+	BaseURL   string // e.g. "https://localhost:9200"
+	Index     string // Index, alias or comma-separated list; wildcards allowed
+	UserAgent string
+	Username  string // Basic auth
+	Password  string
+	APIKey    string // Encoded API key ("ApiKey" scheme); takes precedence over basic auth
+
+	// QueryTemplate is the search request body with {{query}} and {{size}}. When empty a multi_match
+	// query over SearchFields is sent.
+	QueryTemplate string
+	SearchFields  []string // Default: TitleField boosted, then all fields
+
+	// Field mappings are JSON paths into _source; an empty IDField uses the hit's _id
+	IDField    string
+	TitleField string
+	URLField   string
+	DataFields []string // Written as "field: value"; empty writes every scalar top-level field
+
+	Site       string // Site for topics and data; default the index name
+	PageSize   int    // Hits per request; larger counts page with search_after. Default 100
+	TieBreaker string // Unique sortable field used with search_after, e.g. "id"; without it "_doc" is used
+
+	hits topicid.Map[Hit]
+}
+
+// Hit is one search result
+type Hit struct {
+	ID     string                 `json:"_id"`
+	Index  string                 `json:"_index"`
+	Score  float64                `json:"_score"`
+	Source map[string]interface{} `json:"_source"`
+	Sort   []interface{}          `json:"sort"`
+}
+
+func New() *DataSourceElasticsearch {
+	return &DataSourceElasticsearch{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:    "http://localhost:9200",
+		UserAgent:  "locus/elasticsearch-datasource",
+		TitleField: "title",
+		PageSize:   defaultPageSize,
+	}
+}
+
+// Init implements models.DataSource
+// Validates the index, template and field paths
+func (es *DataSourceElasticsearch) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.Index == "" {
+		return errors.New("Index is required for Elasticsearch DataSource")
+	}
+	if es.TitleField == "" {
+		return errors.New("TitleField is required for Elasticsearch DataSource")
+	}
+	if es.QueryTemplate != "" && !strings.Contains(es.QueryTemplate, PlaceholderQuery) {
+		return fmt.Errorf("QueryTemplate must contain %s", PlaceholderQuery)
+	}
+	if es.PageSize <= 0 {
+		es.PageSize = defaultPageSize
+	}
+	paths := append([]string{es.IDField, es.TitleField, es.URLField}, es.DataFields...)
+	for _, path := range paths {
+		if _, err := jsonpath.Compile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Reports whether the cluster answers and the index exists
+func (es *DataSourceElasticsearch) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	var response map[string]interface{}
+	return es.doJSON(ctx, http.MethodGet, "/"+url.PathEscape(es.Index)+"/_count", nil, &response) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceElasticsearch) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Elasticsearch",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Hits are topics in score order, titled by TitleField
+func (es *DataSourceElasticsearch) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Elasticsearch DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	hits, err := es.Search(ctx, query, count)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(hits))
+	for _, h := range hits {
+		id := h.ID
+		if es.IDField != "" {
+			if v := jsonpath.MustCompile(es.IDField).Text(h.Source); v != "" {
+				id = v
+			}
+		}
+		title := jsonpath.MustCompile(es.TitleField).Text(h.Source)
+		if title == "" {
+			title = id
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: es.sourceURL(h),
+			Site:      es.site(),
+			TopicID:   es.hits.Put(h.Index+"/"+id, h),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the hit's _source as one item of "field: value" lines under its title, with its score
+func (es *DataSourceElasticsearch) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	h, ok := es.hits.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Elasticsearch topicID %d", topicID)
+	}
+
+	lines := []string{jsonpath.MustCompile(es.TitleField).Text(h.Source)}
+	fields := es.DataFields
+	if len(fields) == 0 {
+		for name, value := range h.Source {
+			if jsonpath.Format(value) != "" {
+				fields = append(fields, name)
+			}
+		}
+		sort.Strings(fields)
+	}
+	for _, field := range fields {
+		if field == es.TitleField {
+			continue
+		}
+		if v := jsonpath.MustCompile(field).Text(h.Source); v != "" {
+			lines = append(lines, field+": "+v)
+		}
+	}
+	lines = append(lines, "score: "+strconv.FormatFloat(h.Score, 'f', 3, 64))
+	return []datasource.DataSourceData{{
+		DataText:  strings.Join(lines, "\n"),
+		SourceURL: es.sourceURL(h),
+		Site:      es.site(),
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// Search returns up to size hits for query in score order, paging with search_after when size exceeds
+// PageSize
+func (es *DataSourceElasticsearch) Search(ctx context.Context, query string, size int) ([]Hit, error) {
+	var hits []Hit
+	var after []interface{}
+	for len(hits) < size {
+		pageSize := min(size-len(hits), es.PageSize)
+		body, err := es.searchBody(query, pageSize, after)
+		if err != nil {
+			return nil, err
+		}
+		var response struct {
+			Hits struct {
+				Hits []Hit `json:"hits"`
+			} `json:"hits"`
+		}
+		if err := es.doJSON(ctx, http.MethodPost, "/"+url.PathEscape(es.Index)+"/_search", body, &response); err != nil {
+			return nil, err
+		}
+		page := response.Hits.Hits
+		hits = append(hits, page...)
+		if len(page) < pageSize || len(page[len(page)-1].Sort) == 0 {
+			break
+		}
+		after = page[len(page)-1].Sort
+	}
+	return hits, nil
+}
+
+// Scroll visits every document matching the request body with the scroll API, pageSize at a time,
+// until fn returns false or an error. It is meant for exports and reindexing rather than searches.
+func (es *DataSourceElasticsearch) Scroll(ctx context.Context, body map[string]interface{}, pageSize int, fn func(Hit) (bool, error)) error {
+	if err := es.Init(); err != nil {
+		return err
+	}
+	if pageSize <= 0 {
+		pageSize = es.PageSize
+	}
+	request := map[string]interface{}{}
+	for key, value := range body {
+		request[key] = value
+	}
+	request["size"] = pageSize
+
+	type page struct {
+		ScrollID string `json:"_scroll_id"`
+		Hits     struct {
+			Hits []Hit `json:"hits"`
+		} `json:"hits"`
+	}
+	var response page
+	if err := es.doJSON(ctx, http.MethodPost, "/"+url.PathEscape(es.Index)+"/_search?scroll=1m", request, &response); err != nil {
+		return err
+	}
+	defer func() {
+		if response.ScrollID != "" {
+			// Release the search context early rather than waiting for it to expire
+			var cleared map[string]interface{}
+			es.doJSON(context.Background(), http.MethodDelete, "/_search/scroll", map[string]interface{}{"scroll_id": response.ScrollID}, &cleared)
+		}
+	}()
+	for len(response.Hits.Hits) > 0 {
+		for _, h := range response.Hits.Hits {
+			more, err := fn(h)
+			if err != nil || !more {
+				return err
+			}
+		}
+		scrollID := response.ScrollID
+		response = page{}
+		if err := es.doJSON(ctx, http.MethodPost, "/_search/scroll", map[string]interface{}{"scroll": "1m", "scroll_id": scrollID}, &response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchBody builds a search request from QueryTemplate or the default multi_match query
+func (es *DataSourceElasticsearch) searchBody(query string, size int, after []interface{}) (map[string]interface{}, error) {
+	var body map[string]interface{}
+	if es.QueryTemplate != "" {
+		quoted, _ := json.Marshal(query)
+		filled := strings.NewReplacer(
+			PlaceholderQuery, string(quoted),
+			PlaceholderSize, strconv.Itoa(size),
+		).Replace(es.QueryTemplate)
+		if err := json.Unmarshal([]byte(filled), &body); err != nil {
+			return nil, fmt.Errorf("QueryTemplate is not valid JSON: %w", err)
+		}
+	} else {
+		fields := es.SearchFields
+		if len(fields) == 0 {
+			fields = []string{es.TitleField + "^2", "*"}
+		}
+		body = map[string]interface{}{
+			"query": map[string]interface{}{
+				"multi_match": map[string]interface{}{"query": query, "fields": fields},
+			},
+		}
+	}
+	body["size"] = size
+	if _, ok := body["sort"]; !ok {
+		tieBreaker := es.TieBreaker
+		if tieBreaker == "" {
+			tieBreaker = "_doc"
+		}
+		body["sort"] = []interface{}{
+			map[string]interface{}{"_score": "desc"},
+			map[string]interface{}{tieBreaker: "asc"},
+		}
+	}
+	if after != nil {
+		body["search_after"] = after
+	}
+	return body, nil
+}
+
+// doJSON sends a request with an optional JSON body and decodes the JSON response into target
+func (es *DataSourceElasticsearch) doJSON(ctx context.Context, method, path string, body interface{}, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(es.BaseURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	switch {
+	case es.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+es.APIKey)
+	case es.Username != "":
+		req.SetBasicAuth(es.Username, es.Password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("elasticsearch request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	// Numbers stay exact so that long sort values round-trip through search_after
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+// sourceURL returns URLField when mapped, or the document's API URL
+func (es *DataSourceElasticsearch) sourceURL(h Hit) string {
+	if es.URLField != "" {
+		if v := jsonpath.MustCompile(es.URLField).Text(h.Source); v != "" {
+			return v
+		}
+	}
+	return strings.TrimRight(es.BaseURL, "/") + "/" + url.PathEscape(h.Index) + "/_doc/" + url.PathEscape(h.ID)
+}
+
+func (es *DataSourceElasticsearch) site() string {
+	if es.Site != "" {
+		return es.Site
+	}
+	return es.Index
+}
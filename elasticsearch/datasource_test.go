@@ -0,0 +1,170 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// decode reads a JSON request body, keeping numbers exact
+func decode(t *testing.T, r *http.Request) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/docs-*/_search" || r.Header.Get("Authorization") != "ApiKey a2V5" {
+			t.Errorf("unexpected request %s %s %v", r.Method, r.URL, r.Header)
+		}
+		body := decode(t, r)
+		query, _ := json.Marshal(body["query"])
+		sort, _ := json.Marshal(body["sort"])
+		if string(query) != `{"multi_match":{"fields":["name^2","*"],"query":"solar panels"}}` || string(sort) != `[{"_score":"desc"},{"id":"asc"}]` {
+			t.Errorf("unexpected body %v", body)
+		}
+		switch after, _ := json.Marshal(body["search_after"]); string(after) {
+		case "null":
+			if body["size"] != json.Number("2") {
+				t.Errorf("unexpected size %v", body["size"])
+			}
+			fmt.Fprint(w, `{"hits":{"hits":[
+				{"_id":"a","_index":"docs-2024","_score":3.5,"_source":{"name":"Solar panels","kind":"guide","tags":["energy","roof"],"meta":{"x":1}},"sort":[3.5,1]},
+				{"_id":"b","_index":"docs-2024","_score":2,"_source":{"title":"untitled"},"sort":[2,9007199254740993]}
+			]}}`)
+		case "[2,9007199254740993]":
+			if body["size"] != json.Number("1") {
+				t.Errorf("unexpected size %v", body["size"])
+			}
+			fmt.Fprint(w, `{"hits":{"hits":[{"_id":"c","_index":"docs-2023","_score":1,"_source":{"name":"Panel wiring","url":"https://example.com/c"},"sort":[1,3]}]}}`)
+		default:
+			t.Errorf("unexpected search_after %s", after)
+		}
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.BaseURL = srv.URL + "/"
+	es.Index = "docs-*"
+	es.APIKey = "a2V5"
+	es.Username = "ignored"
+	es.TitleField = "name"
+	es.URLField = "url"
+	es.TieBreaker = "id"
+	es.PageSize = 2
+
+	topics, err := es.FetchTopics(3, "solar panels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Solar panels|" + srv.URL + "/docs-2024/_doc/a",
+		"b|" + srv.URL + "/docs-2024/_doc/b",
+		"Panel wiring|https://example.com/c",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].SourceURL; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+	if topics[0].Site != "docs-*" {
+		t.Errorf("Site = %q", topics[0].Site)
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "Solar panels\nkind: guide\ntags: energy, roof\nscore: 3.500"
+	if len(data) != 1 || data[0].DataText != wantData {
+		t.Errorf("FetchData = %+v, want %q", data, wantData)
+	}
+}
+
+func TestQueryTemplate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "elastic" || pass != "secret" {
+			t.Errorf("unexpected credentials %q %q", user, pass)
+		}
+		body := decode(t, r)
+		query, _ := json.Marshal(body["query"])
+		sort, _ := json.Marshal(body["sort"])
+		if string(query) != `{"match":{"body":"say \"hi\""}}` || string(sort) != `["_doc"]` || body["size"] != json.Number("5") {
+			t.Errorf("unexpected body %v", body)
+		}
+		fmt.Fprint(w, `{"hits":{"hits":[{"_id":"1","_index":"notes","_source":{"title":"Greeting","ref":"N-1"}}]}}`)
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.BaseURL = srv.URL
+	es.Index = "notes"
+	es.Username = "elastic"
+	es.Password = "secret"
+	es.IDField = "ref"
+	es.DataFields = []string{"ref", "missing"}
+	es.QueryTemplate = `{"query":{"match":{"body":{{query}}}},"size":{{size}},"sort":["_doc"]}`
+
+	topics, err := es.FetchTopics(5, `say "hi"`)
+	if err != nil || len(topics) != 1 || topics[0].Topic != "Greeting" {
+		t.Fatalf("FetchTopics = %+v, %v", topics, err)
+	}
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil || len(data) != 1 || data[0].DataText != "Greeting\nref: N-1\nscore: 0.000" {
+		t.Errorf("FetchData = %+v, %v", data, err)
+	}
+}
+
+func TestScroll(t *testing.T) {
+	var cleared string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := decode(t, r)
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/logs/_search" && r.URL.Query().Get("scroll") == "1m":
+			if body["size"] != json.Number("2") {
+				t.Errorf("unexpected body %v", body)
+			}
+			fmt.Fprint(w, `{"_scroll_id":"s1","hits":{"hits":[{"_id":"1"},{"_id":"2"}]}}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/_search/scroll":
+			if body["scroll_id"] == "s1" {
+				fmt.Fprint(w, `{"_scroll_id":"s2","hits":{"hits":[{"_id":"3"}]}}`)
+			} else {
+				fmt.Fprint(w, `{"_scroll_id":"s3","hits":{"hits":[]}}`)
+			}
+		case r.Method == http.MethodDelete && r.URL.Path == "/_search/scroll":
+			cleared = fmt.Sprint(body["scroll_id"])
+			fmt.Fprint(w, `{"succeeded":true}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.BaseURL = srv.URL
+	es.Index = "logs"
+	var ids []string
+	err := es.Scroll(context.Background(), map[string]interface{}{"query": map[string]interface{}{"match_all": struct{}{}}}, 2, func(h Hit) (bool, error) {
+		ids = append(ids, h.ID)
+		return true, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, ",") != "1,2,3" || cleared != "s3" {
+		t.Errorf("Scroll visited %q and cleared %q", ids, cleared)
+	}
+}
@@ -0,0 +1,66 @@
+// Package embed defines the hook for turning result text into vectors, so
+// federated results can be indexed in a vector store without fetching their
+// content again. The pipeline's Embed stage runs an Embedder over fetched
+// data and sets each item's Embedding. OpenAI, built with the "llm" build
+// tag, is an Embedder for OpenAI-compatible embeddings APIs.
+package embed
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// Embedder returns one vector per text, in order. Implementations are typically a model API client;
+// batching lets them send many texts per request.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Func adapts a single-text function, such as a vector.EmbedFunc, to Embedder
+type Func func(ctx context.Context, text string) ([]float32, error)
+
+func (f Func) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		v, err := f(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("embedding text %d: %w", i, err)
+		}
+		vectors[i] = v
+	}
+	return vectors, nil
+}
+
+// Normalize scales v to unit length in place, so dot products are cosine similarities, and returns it
+func Normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	norm := float32(1 / math.Sqrt(sum))
+	for i := range v {
+		v[i] *= norm
+	}
+	return v
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 when their lengths differ or either is zero
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
@@ -0,0 +1,51 @@
+package embed
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFunc(t *testing.T) {
+	f := Func(func(ctx context.Context, text string) ([]float32, error) {
+		if text == "" {
+			return nil, errors.New("empty text")
+		}
+		return []float32{float32(len(text))}, nil
+	})
+	vectors, err := f.Embed(context.Background(), []string{"a", "abc"})
+	if err != nil || len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][0] != 3 {
+		t.Errorf("Embed = %v, %v", vectors, err)
+	}
+	if _, err := f.Embed(context.Background(), []string{"a", ""}); err == nil || err.Error() != "embedding text 1: empty text" {
+		t.Errorf("Embed with a failing text = %v", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	v := Normalize([]float32{3, 4})
+	if math.Abs(float64(v[0])-0.6) > 1e-6 || math.Abs(float64(v[1])-0.8) > 1e-6 {
+		t.Errorf("Normalize = %v", v)
+	}
+	if v := Normalize([]float32{0, 0}); v[0] != 0 || v[1] != 0 {
+		t.Errorf("Normalize of the zero vector = %v", v)
+	}
+}
+
+func TestCosine(t *testing.T) {
+	for _, tc := range []struct {
+		a, b []float32
+		want float64
+	}{
+		{[]float32{1, 0}, []float32{2, 0}, 1},
+		{[]float32{1, 0}, []float32{0, 3}, 0},
+		{[]float32{1, 1}, []float32{-1, -1}, -1},
+		{[]float32{1, 0}, []float32{1, 0, 0}, 0},
+		{[]float32{0, 0}, []float32{1, 0}, 0},
+	} {
+		if got := Cosine(tc.a, tc.b); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("Cosine(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+//go:build llm
+
+package embed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAI embeds texts with an embeddings API: OpenAI itself, or a compatible server such as Ollama,
+// vLLM or llama.cpp. Built with the "llm" build tag.
+type OpenAI struct {
+	Client     *http.Client
+	BaseURL    string // Default "https://api.openai.com/v1"; e.g. "http://localhost:11434/v1" for Ollama
+	APIKey     string // Optional for local servers
+	Model      string // Default "text-embedding-3-small"
+	Dimensions int    // Vector length for models that can shorten their vectors; 0 for the model's own
+}
+
+func NewOpenAI(apiKey string) *OpenAI {
+	return &OpenAI{
+		Client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		BaseURL: "https://api.openai.com/v1",
+		APIKey:  apiKey,
+		Model:   "text-embedding-3-small",
+	}
+}
+
+// Embed implements Embedder, sending all texts in one request
+func (o *OpenAI) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	model := o.Model
+	if model == "" {
+		model = "text-embedding-3-small"
+	}
+	body, err := json.Marshal(struct {
+		Model          string   `json:"model"`
+		Input          []string `json:"input"`
+		Dimensions     int      `json:"dimensions,omitempty"`
+		EncodingFormat string   `json:"encoding_format"`
+	}{
+		Model:          model,
+		Input:          texts,
+		Dimensions:     o.Dimensions,
+		EncodingFormat: "float",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	base := o.BaseURL
+	if base == "" {
+		base = "https://api.openai.com/v1"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(base, "/")+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+	client := o.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("embedding request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	var response struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	// Vectors are matched to texts by index, which servers need not return in order
+	vectors := make([][]float32, len(texts))
+	for _, d := range response.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding response has index %d for %d texts", d.Index, len(texts))
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("embedding response has no vector for text %d", i)
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,80 @@
+//go:build llm
+
+package embed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s with %q", r.URL, r.Header.Get("Authorization"))
+		}
+		var request struct {
+			Model          string   `json:"model"`
+			Input          []string `json:"input"`
+			Dimensions     int      `json:"dimensions"`
+			EncodingFormat string   `json:"encoding_format"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+		if request.Model != "nomic-embed-text" || request.Dimensions != 2 || request.EncodingFormat != "float" ||
+			strings.Join(request.Input, "|") != "first|second" {
+			t.Errorf("unexpected request %+v", request)
+		}
+		// Out of order, as servers may answer
+		fmt.Fprint(w, `{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`)
+	}))
+	defer srv.Close()
+
+	o := NewOpenAI("key")
+	o.BaseURL = srv.URL + "/v1/"
+	o.Model = "nomic-embed-text"
+	o.Dimensions = 2
+	vectors, err := o.Embed(context.Background(), []string{"first", "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Errorf("Embed = %v", vectors)
+	}
+	if vectors, err := o.Embed(context.Background(), nil); vectors != nil || err != nil {
+		t.Errorf("Embed of no texts = %v, %v", vectors, err)
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	for body, want := range map[string]string{
+		`{"data":[{"index":0,"embedding":[1]}]}`:                             "no vector for text 1",
+		`{"data":[{"index":0,"embedding":[1]},{"index":2,"embedding":[1]}]}`: "index 2 for 2 texts",
+		`not json`: "invalid character",
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		}))
+		o := NewOpenAI("")
+		o.BaseURL = srv.URL
+		if _, err := o.Embed(context.Background(), []string{"a", "b"}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Embed with response %s = %v, want %q", body, err, want)
+		}
+		srv.Close()
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"invalid model"}}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	o := NewOpenAI("")
+	o.BaseURL = srv.URL
+	if _, err := o.Embed(context.Background(), []string{"a"}); err == nil || !strings.Contains(err.Error(), "status 400: {\"error\":{\"message\":\"invalid model\"}}") {
+		t.Errorf("Embed with status 400 = %v", err)
+	}
+}
@@ -0,0 +1,382 @@
+package europepmc
+
+// Data Source Adapter for the Europe PMC REST API
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceEuropePMC struct {
+	Client           *http.Client
+	BaseURL          string
+	UserAgent        string
+	IncludePreprints bool // Include preprints (source PPR) alongside peer-reviewed articles
+	OpenAccessOnly   bool // Only return articles whose full text can be retrieved
+
+	articles topicid.Map[article]
+}
+
+// article is the part of a search hit needed to fetch its abstract or full text
+type article struct {
+	ID         string
+	Source     string
+	PMCID      string
+	OpenAccess bool
+	URL        string
+}
+
+func New() *DataSourceEuropePMC {
+	return &DataSourceEuropePMC{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:          "https://www.ebi.ac.uk/europepmc/webservices/rest",
+		UserAgent:        "locus/europepmc-datasource",
+		IncludePreprints: true,
+	}
+}
+
+// Init implements models.DataSource
+// Europe PMC requires no initialization
+func (es *DataSourceEuropePMC) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceEuropePMC) CheckAvailability() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("query", "malaria")
+	params.Set("pageSize", "1")
+	params.Set("format", "json")
+	return es.doJSON(ctx, "/search", params, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceEuropePMC) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "Europe PMC",
+		Homepage:    "https://europepmc.org",
+		Attribution: "Europe PMC",
+		TermsURL:    "https://europepmc.org/Copyright",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each hit is a topic; the journal title (or "preprint") is reported as the Site
+func (es *DataSourceEuropePMC) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Europe PMC DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	search := "(" + query + ")"
+	if !es.IncludePreprints {
+		search += " NOT SRC:PPR"
+	}
+	if es.OpenAccessOnly {
+		search += " AND OPEN_ACCESS:y"
+	}
+	params := url.Values{}
+	params.Set("query", search)
+	params.Set("pageSize", strconv.Itoa(count))
+	params.Set("resultType", "lite")
+	params.Set("format", "json")
+
+	var response struct {
+		ResultList struct {
+			Result []struct {
+				ID           string `json:"id"`
+				Source       string `json:"source"`
+				PMCID        string `json:"pmcid"`
+				Title        string `json:"title"`
+				JournalTitle string `json:"journalTitle"`
+				IsOpenAccess string `json:"isOpenAccess"`
+			} `json:"result"`
+		} `json:"resultList"`
+	}
+	if err := es.doJSON(ctx, "/search", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.ResultList.Result))
+	for _, hit := range response.ResultList.Result {
+		if hit.ID == "" || hit.Title == "" {
+			continue
+		}
+		link := fmt.Sprintf("https://europepmc.org/article/%s/%s", hit.Source, hit.ID)
+		site := hit.JournalTitle
+		if hit.Source == "PPR" {
+			site = "preprint"
+		}
+		id := es.articles.Put(hit.Source+":"+hit.ID, article{
+			ID:         hit.ID,
+			Source:     hit.Source,
+			PMCID:      hit.PMCID,
+			OpenAccess: hit.IsOpenAccess == "Y",
+			URL:        link,
+		})
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     strings.TrimSuffix(hit.Title, "."),
+			SourceURL: link,
+			Site:      site,
+			TopicID:   id,
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Open-access articles return up to count full-text sections; other articles return their abstract
+func (es *DataSourceEuropePMC) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	item, ok := es.articles.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Europe PMC topicID %d", topicID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	if item.OpenAccess && item.PMCID != "" {
+		// Fall through to the abstract when the full text is missing or malformed
+		if results, err := es.fetchFullText(ctx, count, topicID, item); err == nil && len(results) > 0 {
+			return results, nil
+		}
+	}
+	return es.fetchAbstract(ctx, topicID, item)
+}
+
+func (es *DataSourceEuropePMC) fetchAbstract(ctx context.Context, topicID int64, item article) ([]datasource.DataSourceData, error) {
+	params := url.Values{}
+	params.Set("query", fmt.Sprintf("EXT_ID:%s AND SRC:%s", item.ID, item.Source))
+	params.Set("resultType", "core")
+	params.Set("format", "json")
+	var response struct {
+		ResultList struct {
+			Result []struct {
+				AbstractText string `json:"abstractText"`
+			} `json:"result"`
+		} `json:"resultList"`
+	}
+	if err := es.doJSON(ctx, "/search", params, &response); err != nil {
+		return nil, err
+	}
+	if len(response.ResultList.Result) == 0 {
+		return []datasource.DataSourceData{}, nil
+	}
+	abstract := htmlToText(response.ResultList.Result[0].AbstractText)
+	if abstract == "" {
+		return []datasource.DataSourceData{}, nil
+	}
+	return []datasource.DataSourceData{{
+		DataText:  abstract,
+		SourceURL: item.URL,
+		AnswerID:  topicID,
+	}}, nil
+}
+
+func (es *DataSourceEuropePMC) fetchFullText(ctx context.Context, count int, topicID int64, item article) ([]datasource.DataSourceData, error) {
+	body, err := es.get(ctx, "/"+url.PathEscape(item.PMCID)+"/fullTextXML", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	sections, err := parseSections(body)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for i, section := range sections {
+		if len(results) >= count {
+			break
+		}
+		text := section.Text
+		if section.Title != "" {
+			text = section.Title + "\n\n" + text
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  text,
+			SourceURL: item.URL,
+			AnswerID:  topicid.Hash(fmt.Sprintf("%s#%d", item.PMCID, i)),
+		})
+	}
+	return results, nil
+}
+
+// section is a run of body paragraphs sharing the same innermost section heading
+type section struct {
+	Title string
+	Text  string
+}
+
+// parseSections walks a JATS document and groups the <p> text in <body> by section title
+func parseSections(r io.Reader) ([]section, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	var sections []section
+	var titles []string
+	inBody := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return sections, nil
+		}
+		if err != nil {
+			return sections, err
+		}
+		switch v := token.(type) {
+		case xml.StartElement:
+			switch v.Name.Local {
+			case "body":
+				inBody = true
+			case "sec":
+				titles = append(titles, "")
+			case "title":
+				if inBody && len(titles) > 0 {
+					titles[len(titles)-1] = collectText(decoder)
+				}
+			case "p":
+				if !inBody {
+					continue
+				}
+				text := collectText(decoder)
+				if text == "" {
+					continue
+				}
+				title := ""
+				for i := len(titles) - 1; i >= 0; i-- {
+					if titles[i] != "" {
+						title = titles[i]
+						break
+					}
+				}
+				if n := len(sections); n > 0 && sections[n-1].Title == title {
+					sections[n-1].Text += "\n\n" + text
+				} else {
+					sections = append(sections, section{Title: title, Text: text})
+				}
+			}
+		case xml.EndElement:
+			switch v.Name.Local {
+			case "body":
+				inBody = false
+			case "sec":
+				if len(titles) > 0 {
+					titles = titles[:len(titles)-1]
+				}
+			}
+		}
+	}
+}
+
+// collectText consumes tokens up to the end of the current element and returns its whitespace-normalized text
+func collectText(decoder *xml.Decoder) string {
+	var b strings.Builder
+	depth := 1
+	for depth > 0 {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch v := token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			b.Write(v)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// doJSON performs an HTTP GET request against the Europe PMC API and decodes the JSON response into target
+func (es *DataSourceEuropePMC) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	body, err := es.get(ctx, path, params)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(target)
+}
+
+// get performs an HTTP GET request against the Europe PMC API. The caller must close the returned body.
+func (es *DataSourceEuropePMC) get(ctx context.Context, path string, params url.Values) (io.ReadCloser, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("europe pmc request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// Helpers
+
+// htmlToText strips the inline markup (<i>, <sup>, <h4>) Europe PMC leaves in abstracts
+func htmlToText(in string) string {
+	var b strings.Builder
+	inTag := false
+	for _, r := range in {
+		switch {
+		case r == '<':
+			inTag = true
+			b.WriteRune(' ')
+		case r == '>':
+			inTag = false
+		case !inTag:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,89 @@
+package europepmc
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch q.Get("resultType") {
+		case "lite":
+			if q.Get("query") != "(malaria) NOT SRC:PPR AND OPEN_ACCESS:y" || q.Get("pageSize") != "2" {
+				t.Errorf("search query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"resultList":{"result":[
+				{"id":"123","source":"MED","pmcid":"PMC1","title":"Malaria vaccines.","journalTitle":"Lancet","isOpenAccess":"Y"},
+				{"id":"456","source":"MED","title":"Closed access","journalTitle":"BMJ","isOpenAccess":"N"},
+				{"id":"","title":"No ID"}
+			]}}`)
+		case "core":
+			if q.Get("query") != "EXT_ID:456 AND SRC:MED" {
+				t.Errorf("abstract query %q", q.Get("query"))
+			}
+			fmt.Fprint(w, `{"resultList":{"result":[{"abstractText":"<h4>Background</h4>Mosquito <i>nets</i> help."}]}}`)
+		}
+	})
+	mux.HandleFunc("/PMC1/fullTextXML", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<article><front><p>Front matter</p></front><body>
+			<sec><title>Introduction</title><p>Malaria is <italic>common</italic>.</p><p>It is deadly.</p>
+				<sec><title>Scope</title><p>Africa.</p></sec>
+			</sec>
+			<sec><title>Methods</title><p>Trials.</p></sec>
+		</body></article>`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.IncludePreprints = false
+	es.OpenAccessOnly = true
+
+	topics, err := es.FetchTopics(2, "malaria")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[0].Topic != "Malaria vaccines" || topics[0].Site != "Lancet" ||
+		topics[0].SourceURL != "https://europepmc.org/article/MED/123" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(2, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Introduction\n\nMalaria is common.\n\nIt is deadly.", "Scope\n\nAfrica."}
+	if len(data) != len(want) {
+		t.Fatalf("full text FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("section %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+
+	data, err = es.FetchData(2, topics[1].TopicID)
+	if err != nil || len(data) != 1 || data[0].DataText != "Background Mosquito nets help." {
+		t.Errorf("abstract FetchData = %+v, %v", data, err)
+	}
+}
+
+func TestParseSections(t *testing.T) {
+	sections, err := parseSections(strings.NewReader(`<article><body><p>Untitled.</p><sec><p>Still untitled.</p></sec></body></article>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sections) != 1 || sections[0].Title != "" || sections[0].Text != "Untitled.\n\nStill untitled." {
+		t.Errorf("parseSections = %+v", sections)
+	}
+}
@@ -0,0 +1,477 @@
+package eurostat
+
+// Data Source Adapter for Eurostat statistics (catalogue and JSON-stat dissemination APIs)
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	// Without a time filter only this many recent periods are requested, which keeps responses small
+	defaultLastPeriods = 10
+)
+
+type DataSourceEurostat struct {
+	Client       *http.Client
+	BaseURL      string // Dissemination API root
+	Language     string // "EN", "FR" or "DE"
+	UserAgent    string
+	LastPeriods  int                 // Recent time periods returned when the query has no time filter
+	Filters      map[string][]string // Dimension filters applied to every query, e.g. {"geo": {"DE", "FR"}}
+	CatalogueTTL time.Duration
+
+	mu        sync.Mutex
+	catalogue []dataset
+	loadedAt  time.Time
+	slices    topicid.Map[slice]
+}
+
+// dataset is one entry of the Eurostat table of contents
+type dataset struct {
+	Code    string
+	Title   string
+	Updated string
+	Start   string
+	End     string
+}
+
+// slice is a dataset code with the dimension filter requested for it
+type slice struct {
+	Code    string
+	Title   string
+	Filters url.Values
+}
+
+func New() *DataSourceEurostat {
+	return &DataSourceEurostat{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:      "https://ec.europa.eu/eurostat/api/dissemination",
+		Language:     "EN",
+		UserAgent:    "locus/eurostat-datasource",
+		LastPeriods:  defaultLastPeriods,
+		CatalogueTTL: 24 * time.Hour,
+	}
+}
+
+// Init implements models.DataSource
+// The table of contents is downloaded on first search
+func (es *DataSourceEurostat) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceEurostat) CheckAvailability() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	body, err := es.get(ctx, es.dataURL("nama_10_gdp", url.Values{"geo": {"EU27_2020"}, "lastTimePeriod": {"1"}, "na_item": {"B1GQ"}, "unit": {"CP_MEUR"}}))
+	if err != nil {
+		return false
+	}
+	body.Close()
+	return true
+}
+
+// Info implements source.Informer
+func (es *DataSourceEurostat) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "Eurostat",
+		Homepage:            "https://ec.europa.eu/eurostat",
+		License:             "CC-BY-4.0",
+		LicenseURL:          "https://creativecommons.org/licenses/by/4.0/",
+		Attribution:         "Source: Eurostat",
+		AttributionRequired: true,
+		TermsURL:            "https://ec.europa.eu/eurostat/about-us/policies/copyright",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Searches dataset titles and codes. Tokens of the form dim=A,B in the input become a dimension filter
+// carried by the topic, e.g. "unemployment rate geo=DE,FR sinceTimePeriod=2015".
+func (es *DataSourceEurostat) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query, filters := parseQuery(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Eurostat DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	catalogue, err := es.loadCatalogue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		dataset dataset
+		score   int
+	}
+	terms := strings.Fields(strings.ToLower(query))
+	var matches []match
+	for _, ds := range catalogue {
+		if score := datasetScore(ds, strings.ToLower(query), terms); score > 0 {
+			matches = append(matches, match{dataset: ds, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, m := range matches {
+		if len(results) >= count {
+			break
+		}
+		ds := m.dataset
+		key := ds.Code + "?" + filters.Encode()
+		title := fmt.Sprintf("%s: %s (%s–%s, updated %s)", ds.Code, ds.Title, ds.Start, ds.End, ds.Updated)
+		if len(filters) > 0 {
+			title += " [" + filters.Encode() + "]"
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: datasetLink(ds.Code),
+			Site:      "eurostat",
+			TopicID:   es.slices.Put(key, slice{Code: ds.Code, Title: ds.Title, Filters: filters}),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Downloads the filtered slice as JSON-stat and returns up to count series, one per combination of the
+// non-time dimensions, labeled in the configured language, e.g. "Germany / Total / Percentage: 2021 3.6, 2022 3.1"
+func (es *DataSourceEurostat) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	sl, ok := es.slices.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Eurostat topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	params := url.Values{}
+	for dim, values := range es.Filters {
+		params[dim] = values
+	}
+	for dim, values := range sl.Filters {
+		params[dim] = values
+	}
+	if params.Get("time") == "" && params.Get("sinceTimePeriod") == "" && params.Get("untilTimePeriod") == "" && params.Get("lastTimePeriod") == "" {
+		last := es.LastPeriods
+		if last <= 0 {
+			last = defaultLastPeriods
+		}
+		params.Set("lastTimePeriod", strconv.Itoa(last))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	body, err := es.get(ctx, es.dataURL(sl.Code, params))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var stat jsonStat
+	if err := json.NewDecoder(body).Decode(&stat); err != nil {
+		return nil, err
+	}
+
+	series := stat.series()
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, s := range series {
+		if len(results) >= count {
+			break
+		}
+		text := fmt.Sprintf("%s — %s: %s", stat.Label, s.label, strings.Join(s.points, ", "))
+		results = append(results, datasource.DataSourceData{
+			DataText:  text,
+			SourceURL: datasetLink(sl.Code),
+			Site:      "eurostat",
+			AnswerID:  topicid.Hash(sl.Code + "?" + params.Encode() + "#" + s.key),
+		})
+	}
+	return results, nil
+}
+
+// loadCatalogue returns the table of contents, refreshing it once CatalogueTTL has passed
+func (es *DataSourceEurostat) loadCatalogue(ctx context.Context) ([]dataset, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.catalogue != nil && (es.CatalogueTTL <= 0 || time.Since(es.loadedAt) < es.CatalogueTTL) {
+		return es.catalogue, nil
+	}
+
+	uri := fmt.Sprintf("%s/catalogue/toc/txt?lang=%s", strings.TrimRight(es.BaseURL, "/"), strings.ToLower(es.language()))
+	body, err := es.get(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	// Tab-separated, quoted columns: title, code, type, last update, last structure change, data start, data end
+	var catalogue []dataset
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for header := true; scanner.Scan(); header = false {
+		if header {
+			continue
+		}
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 7 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(strings.Trim(fields[i], `"`))
+		}
+		// Folders only group datasets, and a dataset appears once under every folder that lists it
+		if fields[2] == "folder" || seen[fields[1]] {
+			continue
+		}
+		seen[fields[1]] = true
+		catalogue = append(catalogue, dataset{
+			Title:   fields[0],
+			Code:    fields[1],
+			Updated: fields[3],
+			Start:   fields[5],
+			End:     fields[6],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	es.catalogue = catalogue
+	es.loadedAt = time.Now()
+	return catalogue, nil
+}
+
+func (es *DataSourceEurostat) language() string {
+	if es.Language == "" {
+		return "EN"
+	}
+	return strings.ToUpper(es.Language)
+}
+
+func (es *DataSourceEurostat) dataURL(code string, params url.Values) string {
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	query.Set("format", "JSON")
+	query.Set("lang", es.language())
+	return fmt.Sprintf("%s/statistics/1.0/data/%s?%s", strings.TrimRight(es.BaseURL, "/"), url.PathEscape(code), query.Encode())
+}
+
+// get performs an HTTP GET request. The caller must close the returned body.
+func (es *DataSourceEurostat) get(ctx context.Context, uri string) (io.ReadCloser, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("eurostat request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// jsonStat is a JSON-stat 2.0 dataset as returned by the statistics API
+type jsonStat struct {
+	Label     string                       `json:"label"`
+	ID        []string                     `json:"id"`
+	Size      []int                        `json:"size"`
+	Value     json.RawMessage              `json:"value"` // Sparse object keyed by flat index, or a dense array
+	Dimension map[string]jsonStatDimension `json:"dimension"`
+}
+
+type jsonStatDimension struct {
+	Label    string `json:"label"`
+	Category struct {
+		Index map[string]int    `json:"index"`
+		Label map[string]string `json:"label"`
+	} `json:"category"`
+}
+
+// series is one time series of a JSON-stat cube
+type series struct {
+	key    string
+	label  string
+	points []string
+}
+
+// series groups the cube's values by every dimension except time. Dimensions with a single
+// category are left out of the labels since they are the same for every series.
+func (s jsonStat) series() []series {
+	values := map[int]float64{}
+	var dense []*float64
+	if err := json.Unmarshal(s.Value, &dense); err == nil {
+		for i, v := range dense {
+			if v != nil {
+				values[i] = *v
+			}
+		}
+	} else {
+		var sparse map[string]float64
+		if err := json.Unmarshal(s.Value, &sparse); err != nil {
+			return nil
+		}
+		for key, v := range sparse {
+			if i, err := strconv.Atoi(key); err == nil {
+				values[i] = v
+			}
+		}
+	}
+	if len(s.ID) == 0 || len(s.ID) != len(s.Size) {
+		return nil
+	}
+
+	// codes[d][position] is the category code at that position of dimension d
+	codes := make([][]string, len(s.ID))
+	for d, dim := range s.ID {
+		codes[d] = make([]string, s.Size[d])
+		for code, position := range s.Dimension[dim].Category.Index {
+			if position >= 0 && position < s.Size[d] {
+				codes[d][position] = code
+			}
+		}
+	}
+	label := func(d, position int) string {
+		code := codes[d][position]
+		if text := s.Dimension[s.ID[d]].Category.Label[code]; text != "" {
+			return text
+		}
+		return code
+	}
+
+	var order []string
+	byKey := map[string]*series{}
+	indexes := make([]int, 0, len(values))
+	for i := range values {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, flat := range indexes {
+		// Decompose the row-major flat index into one position per dimension
+		positions := make([]int, len(s.ID))
+		rest := flat
+		for d := len(s.ID) - 1; d >= 0; d-- {
+			if s.Size[d] == 0 {
+				return nil
+			}
+			positions[d] = rest % s.Size[d]
+			rest /= s.Size[d]
+		}
+
+		var keyParts, labelParts []string
+		period := ""
+		for d, dim := range s.ID {
+			if dim == "time" {
+				period = label(d, positions[d])
+				continue
+			}
+			keyParts = append(keyParts, dim+"="+codes[d][positions[d]])
+			if s.Size[d] > 1 {
+				labelParts = append(labelParts, label(d, positions[d]))
+			}
+		}
+		key := strings.Join(keyParts, "&")
+		current, ok := byKey[key]
+		if !ok {
+			current = &series{key: key, label: strings.Join(labelParts, " / ")}
+			if current.label == "" {
+				current.label = "All"
+			}
+			byKey[key] = current
+			order = append(order, key)
+		}
+		current.points = append(current.points, fmt.Sprintf("%s %s", period, strconv.FormatFloat(values[flat], 'f', -1, 64)))
+	}
+
+	result := make([]series, 0, len(order))
+	for _, key := range order {
+		result = append(result, *byKey[key])
+	}
+	return result
+}
+
+// Helpers
+
+// parseQuery separates dim=A,B filter tokens from the search words
+func parseQuery(input string) (string, url.Values) {
+	filters := url.Values{}
+	var words []string
+	for _, token := range strings.Fields(input) {
+		dim, values, found := strings.Cut(token, "=")
+		if !found || dim == "" || values == "" {
+			words = append(words, token)
+			continue
+		}
+		for _, value := range strings.Split(values, ",") {
+			if value != "" {
+				filters.Add(dim, value)
+			}
+		}
+	}
+	return strings.Join(words, " "), filters
+}
+
+// datasetScore ranks an exact code above title phrase matches above datasets matching every term
+func datasetScore(ds dataset, query string, terms []string) int {
+	title := strings.ToLower(ds.Title)
+	switch {
+	case strings.ToLower(ds.Code) == query:
+		return 100
+	case strings.Contains(title, query):
+		return 50
+	}
+	for _, term := range terms {
+		if !strings.Contains(title, term) && !strings.Contains(strings.ToLower(ds.Code), term) {
+			return 0
+		}
+	}
+	return len(terms)
+}
+
+func datasetLink(code string) string {
+	return "https://ec.europa.eu/eurostat/databrowser/view/" + url.PathEscape(code) + "/default/table"
+}
@@ -0,0 +1,111 @@
+package eurostat
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const toc = "\"title\"\t\"code\"\t\"type\"\t\"last update of data\"\t\"last table structure change\"\t\"data start\"\t\"data end\"\n" +
+	"\"Labour market\"\t\"labour\"\t\"folder\"\t\"\"\t\"\"\t\"\"\t\"\"\n" +
+	"\"Unemployment by sex and age\"\t\"une_rt_a\"\t\"dataset\"\t\"2024-04-25\"\t\"2024-04-25\"\t\"2003\"\t\"2023\"\n" +
+	"\"Unemployment by sex and age\"\t\"une_rt_a\"\t\"dataset\"\t\"2024-04-25\"\t\"2024-04-25\"\t\"2003\"\t\"2023\"\n" +
+	"\"Long-term unemployment rate\"\t\"une_ltu_a\"\t\"dataset\"\t\"2024-04-20\"\t\"2024-04-20\"\t\"2008\"\t\"2023\"\n" +
+	"\"GDP and main components\"\t\"nama_10_gdp\"\t\"dataset\"\t\"2024-04-22\"\t\"2024-04-22\"\t\"1975\"\t\"2023\"\n"
+
+const cube = `{"label":"Unemployment by sex and age","id":["geo","unit","time"],"size":[2,1,2],
+	"value":{"0":3.1,"1":3.0,"2":7.3,"3":7.4},
+	"dimension":{
+		"geo":{"category":{"index":{"DE":0,"FR":1},"label":{"DE":"Germany","FR":"France"}}},
+		"unit":{"category":{"index":{"PC_ACT":0},"label":{"PC_ACT":"Percentage"}}},
+		"time":{"category":{"index":{"2022":0,"2023":1}}}
+	}}`
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/catalogue/toc/txt", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("lang") != "en" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, toc)
+	})
+	mux.HandleFunc("/statistics/1.0/data/une_rt_a", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("format") != "JSON" || q.Get("lang") != "EN" || len(q["geo"]) != 2 || q.Get("sex") != "T" || q.Get("lastTimePeriod") != "10" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, cube)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.Filters = map[string][]string{"sex": {"T"}}
+
+	topics, err := es.FetchTopics(5, "unemployment geo=DE,FR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"une_rt_a: Unemployment by sex and age (2003–2023, updated 2024-04-25) [geo=DE&geo=FR]",
+		"une_ltu_a: Long-term unemployment rate (2008–2023, updated 2024-04-20) [geo=DE&geo=FR]",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := []string{
+		"Unemployment by sex and age — Germany: 2022 3.1, 2023 3",
+		"Unemployment by sex and age — France: 2022 7.3, 2023 7.4",
+	}
+	if len(data) != len(wantData) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range wantData {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+}
+
+func TestFetchTopicsExactCode(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	topics, err := es.FetchTopics(1, "NAMA_10_GDP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].SourceURL != "https://ec.europa.eu/eurostat/databrowser/view/nama_10_gdp/default/table" {
+		t.Errorf("FetchTopics = %+v", topics)
+	}
+}
+
+func TestDenseValues(t *testing.T) {
+	var time jsonStatDimension
+	time.Category.Index = map[string]int{"2021": 0, "2022": 1, "2023": 2}
+	stat := jsonStat{
+		ID:        []string{"time"},
+		Size:      []int{3},
+		Value:     []byte(`[1.5,null,2]`),
+		Dimension: map[string]jsonStatDimension{"time": time},
+	}
+	series := stat.series()
+	if len(series) != 1 || series[0].label != "All" || fmt.Sprint(series[0].points) != "[2021 1.5 2023 2]" {
+		t.Errorf("series = %+v", series)
+	}
+}
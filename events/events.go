@@ -0,0 +1,151 @@
+// Package events publishes what data sources do, so hosts can react without
+// polling: fetch completions, topics not seen before, source failures and
+// circuit-breaker transitions. A Bus fans events out to subscribers such as
+// an in-process channel or a webhook; Publisher wraps a source and publishes
+// its fetches.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Kind names what happened
+type Kind string
+
+const (
+	TopicsFetched  Kind = "topics.fetched"  // FetchTopics returned; Count is the number of topics
+	DataFetched    Kind = "data.fetched"    // FetchData returned; Count is the number of items
+	NewTopic       Kind = "topic.new"       // A topic ID the publisher has not returned before
+	SourceFailed   Kind = "source.failed"   // A fetch or Init returned an error
+	BreakerChanged Kind = "breaker.changed" // A circuit breaker changed State
+)
+
+// Circuit-breaker states for BreakerChanged events
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// Event is one occurrence. Fields that don't apply to the kind are empty.
+type Event struct {
+	Kind     Kind          `json:"kind"`
+	Source   string        `json:"source"`
+	Time     time.Time     `json:"time"`
+	Query    string        `json:"query,omitempty"`
+	TopicID  int64         `json:"topic_id,omitempty"`
+	Topic    string        `json:"topic,omitempty"` // Title, for NewTopic
+	URL      string        `json:"url,omitempty"`   // Source URL, for NewTopic
+	Count    int           `json:"count,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"` // Nanoseconds in JSON
+	Error    string        `json:"error,omitempty"`
+	State    string        `json:"state,omitempty"` // New breaker state, for BreakerChanged
+}
+
+// Subscriber receives events. Notify is called on the publishing goroutine, so it must not block;
+// subscribers that do slow work queue the event and return.
+type Subscriber interface {
+	Notify(e Event)
+}
+
+// SubscriberFunc adapts a function to Subscriber
+type SubscriberFunc func(e Event)
+
+func (f SubscriberFunc) Notify(e Event) {
+	f(e)
+}
+
+// Bus delivers published events to the subscribers interested in their kind. The zero value is ready
+// to use.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[int]subscription
+	nextID int
+}
+
+type subscription struct {
+	subscriber Subscriber
+	kinds      map[Kind]bool // nil for all kinds
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers s for events of the given kinds, or all kinds when none are given. The returned
+// function removes the subscription.
+func (b *Bus) Subscribe(s Subscriber, kinds ...Kind) func() {
+	sub := subscription{subscriber: s}
+	if len(kinds) > 0 {
+		sub.kinds = make(map[Kind]bool, len(kinds))
+		for _, k := range kinds {
+			sub.kinds[k] = true
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = map[int]subscription{}
+	}
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = sub
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs, id)
+	}
+}
+
+// Channel subscribes a buffered channel of the given size. Events that arrive while the buffer is full
+// are dropped rather than blocking the publisher. The returned function removes the subscription and
+// closes the channel.
+func (b *Bus) Channel(size int, kinds ...Kind) (<-chan Event, func()) {
+	ch := make(chan Event, size)
+	var mu sync.Mutex
+	closed := false
+	unsubscribe := b.Subscribe(SubscriberFunc(func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	}), kinds...)
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			unsubscribe()
+			mu.Lock()
+			closed = true
+			close(ch)
+			mu.Unlock()
+		})
+	}
+}
+
+// Publish delivers e to the interested subscribers, setting Time when it is zero. A nil Bus discards
+// events, so publishers need not check for one.
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	b.mu.RLock()
+	subs := make([]Subscriber, 0, len(b.subs))
+	for _, sub := range b.subs {
+		if sub.kinds == nil || sub.kinds[e.Kind] {
+			subs = append(subs, sub.subscriber)
+		}
+	}
+	b.mu.RUnlock()
+	for _, s := range subs {
+		s.Notify(e)
+	}
+}
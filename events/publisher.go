@@ -0,0 +1,102 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+// Topic IDs remembered for NewTopic events
+const defaultMemory = 4096
+
+// Publisher wraps a source and publishes its fetches to Bus under Name. It implements source.Source.
+type Publisher struct {
+	Source source.Source
+	Bus    *Bus
+	Name   string
+	// Memory is how many topic IDs are remembered to tell new topics from repeats; default 4096
+	Memory int
+
+	mu    sync.Mutex
+	seen  map[int64]bool
+	order []int64
+}
+
+func NewPublisher(name string, s source.Source, bus *Bus) *Publisher {
+	return &Publisher{Source: s, Bus: bus, Name: name, Memory: defaultMemory}
+}
+
+// Init implements models.DataSource
+func (p *Publisher) Init() error {
+	err := p.Source.Init()
+	if err != nil {
+		p.Bus.Publish(Event{Kind: SourceFailed, Source: p.Name, Error: err.Error()})
+	}
+	return err
+}
+
+// CheckAvailability implements models.DataSource
+func (p *Publisher) CheckAvailability() bool {
+	return p.Source.CheckAvailability()
+}
+
+// Unwrap implements source.Wrapper
+func (p *Publisher) Unwrap() source.Source {
+	return p.Source
+}
+
+// FetchTopics implements models.DataSource
+// Publishes TopicsFetched or SourceFailed, then NewTopic for each topic not returned before
+func (p *Publisher) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	start := time.Now()
+	topics, err := p.Source.FetchTopics(count, input)
+	if err != nil {
+		p.Bus.Publish(Event{Kind: SourceFailed, Source: p.Name, Query: input, Duration: time.Since(start), Error: err.Error()})
+		return nil, err
+	}
+	p.Bus.Publish(Event{Kind: TopicsFetched, Source: p.Name, Query: input, Count: len(topics), Duration: time.Since(start)})
+	for _, t := range topics {
+		if p.remember(t.TopicID) {
+			p.Bus.Publish(Event{Kind: NewTopic, Source: p.Name, Query: input, TopicID: t.TopicID, Topic: t.Topic, URL: t.SourceURL})
+		}
+	}
+	return topics, nil
+}
+
+// FetchData implements models.DataSource
+// Publishes DataFetched or SourceFailed
+func (p *Publisher) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	start := time.Now()
+	data, err := p.Source.FetchData(count, topicID)
+	if err != nil {
+		p.Bus.Publish(Event{Kind: SourceFailed, Source: p.Name, TopicID: topicID, Duration: time.Since(start), Error: err.Error()})
+		return nil, err
+	}
+	p.Bus.Publish(Event{Kind: DataFetched, Source: p.Name, TopicID: topicID, Count: len(data), Duration: time.Since(start)})
+	return data, nil
+}
+
+// remember records a topic ID and reports whether it is new, forgetting the oldest beyond Memory
+func (p *Publisher) remember(id int64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.seen[id] {
+		return false
+	}
+	if p.seen == nil {
+		p.seen = map[int64]bool{}
+	}
+	memory := p.Memory
+	if memory <= 0 {
+		memory = defaultMemory
+	}
+	p.seen[id] = true
+	p.order = append(p.order, id)
+	if len(p.order) > memory {
+		delete(p.seen, p.order[0])
+		p.order = p.order[1:]
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Webhook is a Subscriber that POSTs each event as JSON to a URL. Events are queued and sent in order
+// from a background goroutine, with retries on network errors and 5xx responses; when the queue is
+// full new events are dropped.
+type Webhook struct {
+	Client  *http.Client
+	URL     string
+	Secret  string // When set, the body's HMAC-SHA256 is sent hex-encoded as "X-Locus-Signature: sha256=..."
+	Headers map[string]string
+	Retries int // Attempts after the first; default 3, with 1s, 2s, 4s... between them
+	// OnError, when set, receives events that could not be delivered
+	OnError func(e Event, err error)
+
+	once    sync.Once
+	queue   chan Event
+	done    chan struct{}
+	closing sync.Once
+	mu      sync.RWMutex
+	closed  bool
+}
+
+// NewWebhook returns a Webhook for url with a queue of 256 events
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		Client:  &http.Client{Timeout: 8 * time.Second},
+		URL:     url,
+		Retries: 3,
+	}
+}
+
+// Notify implements Subscriber
+func (w *Webhook) Notify(e Event) {
+	w.start()
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return
+	}
+	select {
+	case w.queue <- e:
+	default:
+		if w.OnError != nil {
+			w.OnError(e, errors.New("webhook queue is full"))
+		}
+	}
+}
+
+// Close stops accepting events and waits for the queued ones to be sent
+func (w *Webhook) Close() {
+	w.start()
+	w.closing.Do(func() {
+		w.mu.Lock()
+		w.closed = true
+		close(w.queue)
+		w.mu.Unlock()
+	})
+	<-w.done
+}
+
+func (w *Webhook) start() {
+	w.once.Do(func() {
+		if w.Client == nil {
+			w.Client = &http.Client{Timeout: 8 * time.Second}
+		}
+		w.queue = make(chan Event, 256)
+		w.done = make(chan struct{})
+		go w.run()
+	})
+}
+
+func (w *Webhook) run() {
+	defer close(w.done)
+	for e := range w.queue {
+		if err := w.send(e); err != nil && w.OnError != nil {
+			w.OnError(e, err)
+		}
+	}
+}
+
+// send posts one event, retrying with exponential backoff
+func (w *Webhook) send(e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	retries := w.Retries
+	if retries < 0 {
+		retries = 0
+	}
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(body)
+		if err == nil || !retry || attempt >= retries {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post sends body once and reports whether a failure is worth retrying
+func (w *Webhook) post(body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Locus-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests,
+			fmt.Errorf("webhook request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return false, nil
+}
@@ -0,0 +1,79 @@
+package events
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebhook(t *testing.T) {
+	var mu sync.Mutex
+	var received []Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		if r.Header.Get("X-Locus-Signature") != "sha256="+hex.EncodeToString(mac.Sum(nil)) ||
+			r.Header.Get("Content-Type") != "application/json" || r.Header.Get("X-Tenant") != "acme" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		var e Event
+		if err := json.Unmarshal(body, &e); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		received = append(received, e)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL)
+	w.Secret = "secret"
+	w.Headers = map[string]string{"X-Tenant": "acme"}
+	w.OnError = func(e Event, err error) { t.Errorf("event %s not delivered: %v", e.Kind, err) }
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w.Notify(Event{Kind: TopicsFetched, Source: "wikipedia", Time: at, Query: "go", Count: 3, Duration: time.Second})
+	w.Notify(Event{Kind: SourceFailed, Source: "arxiv", Time: at, Error: "timeout"})
+	w.Close()
+	// Events after Close are dropped
+	w.Notify(Event{Kind: NewTopic})
+
+	if len(received) != 2 || received[0].Kind != TopicsFetched || received[1].Kind != SourceFailed {
+		t.Fatalf("received %+v", received)
+	}
+	if e := received[0]; !e.Time.Equal(at) || e.Query != "go" || e.Count != 3 || e.Duration != time.Second {
+		t.Errorf("decoded %+v", e)
+	}
+}
+
+func TestWebhookFailures(t *testing.T) {
+	status := http.StatusBadRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rejected", status)
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL)
+	var failed []error
+	w.OnError = func(e Event, err error) { failed = append(failed, err) }
+	w.Notify(Event{Kind: DataFetched})
+	w.Close()
+	if len(failed) != 1 {
+		t.Errorf("OnError called %d times for a rejected event", len(failed))
+	}
+
+	// Server errors and rate limits are retried, client errors are not
+	for code, want := range map[int]bool{http.StatusBadRequest: false, http.StatusTooManyRequests: true, http.StatusBadGateway: true} {
+		status = code
+		if retry, err := w.post([]byte("{}")); err == nil || retry != want {
+			t.Errorf("post with status %d = %v, %v; want retry %v", code, retry, err, want)
+		}
+	}
+}
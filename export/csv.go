@@ -0,0 +1,57 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Columns of CSV output, in order
+var csvHeader = []string{"kind", "source", "query", "topic_id", "answer_id", "title", "text", "url", "site", "license", "attribution", "time"}
+
+// CSVWriter writes a header row followed by one row per record. Times are RFC 3339 and zero IDs are
+// empty cells.
+type CSVWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func NewCSV(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// Write implements Writer
+func (w *CSVWriter) Write(r Record) error {
+	if !w.header {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.header = true
+	}
+	return w.w.Write([]string{
+		r.Kind, r.Source, r.Query, id(r.TopicID), id(r.AnswerID), r.Title, r.Text, r.URL, r.Site,
+		r.License, r.Attribution, r.Time.Format(time.RFC3339),
+	})
+}
+
+// Close implements Writer
+func (w *CSVWriter) Close() error {
+	if !w.header {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.header = true
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// Helpers
+
+func id(n int64) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatInt(n, 10)
+}
@@ -0,0 +1,127 @@
+// Package export serializes topics and data to files for analytics systems
+// and batch jobs. Writers stream one record at a time: JSONL and CSV are
+// built in, and Parquet is available with the "parquet" build tag.
+package export
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// Record kinds
+const (
+	KindTopic = "topic"
+	KindData  = "data"
+)
+
+// Record is one exported topic or data item. Topics and data share the layout so both fit in one file.
+type Record struct {
+	Kind        string    `json:"kind" parquet:"kind"`
+	Source      string    `json:"source,omitempty" parquet:"source"` // Name of the data source
+	Query       string    `json:"query,omitempty" parquet:"query"`   // Search input, for topics
+	TopicID     int64     `json:"topic_id,omitempty" parquet:"topic_id"`
+	AnswerID    int64     `json:"answer_id,omitempty" parquet:"answer_id"`
+	Title       string    `json:"title,omitempty" parquet:"title"` // Topic title
+	Text        string    `json:"text,omitempty" parquet:"text"`   // Data text
+	URL         string    `json:"url,omitempty" parquet:"url"`
+	Site        string    `json:"site,omitempty" parquet:"site"`
+	License     string    `json:"license,omitempty" parquet:"license"`         // From the source's SourceInfo; see source.InfoFor
+	Attribution string    `json:"attribution,omitempty" parquet:"attribution"` // Credit line to show with the content
+	Time        time.Time `json:"time" parquet:"time,timestamp"`
+}
+
+// TopicRecord returns the record for a topic found by source for query
+func TopicRecord(source, query string, t datasource.DataSourceTopic) Record {
+	return Record{
+		Kind:    KindTopic,
+		Source:  source,
+		Query:   query,
+		TopicID: t.TopicID,
+		Title:   t.Topic,
+		URL:     t.SourceURL,
+		Site:    t.Site,
+		Time:    time.Now().UTC(),
+	}
+}
+
+// DataRecord returns the record for a data item fetched by source for topicID
+func DataRecord(source string, topicID int64, d datasource.DataSourceData) Record {
+	return Record{
+		Kind:     KindData,
+		Source:   source,
+		TopicID:  topicID,
+		AnswerID: d.AnswerID,
+		Text:     d.DataText,
+		URL:      d.SourceURL,
+		Site:     d.Site,
+		Time:     time.Now().UTC(),
+	}
+}
+
+// Writer streams records to an output. Close flushes buffered records and writes any footer; it does
+// not close the underlying io.Writer.
+type Writer interface {
+	Write(r Record) error
+	Close() error
+}
+
+// Topics writes a record for each topic
+func Topics(w Writer, source, query string, topics []datasource.DataSourceTopic) error {
+	for _, t := range topics {
+		if err := w.Write(TopicRecord(source, query, t)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Data writes a record for each data item
+func Data(w Writer, source string, topicID int64, data []datasource.DataSourceData) error {
+	for _, d := range data {
+		if err := w.Write(DataRecord(source, topicID, d)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writers by format name; files built with tags add theirs
+var formats = map[string]func(io.Writer) Writer{
+	"jsonl": func(w io.Writer) Writer { return NewJSONL(w) },
+	"csv":   func(w io.Writer) Writer { return NewCSV(w) },
+}
+
+// Formats returns the available format names, e.g. for a --output flag's help
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New returns a Writer for the named format
+func New(format string, w io.Writer) (Writer, error) {
+	f, ok := formats[strings.ToLower(format)]
+	if !ok {
+		return nil, fmt.Errorf("unknown export format %q; available: %s", format, strings.Join(Formats(), ", "))
+	}
+	return f(w), nil
+}
+
+// FormatOf returns the format for a file name from its extension: .jsonl or .ndjson, .csv, .parquet
+func FormatOf(name string) string {
+	switch ext := strings.ToLower(path.Ext(name)); ext {
+	case ".jsonl", ".ndjson":
+		return "jsonl"
+	default:
+		return strings.TrimPrefix(ext, ".")
+	}
+}
@@ -0,0 +1,140 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+var exported = time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+// records returns a topic and a data item whose text needs quoting in every format
+func records() []Record {
+	topic := TopicRecord("wikipedia", "go, the language", datasource.DataSourceTopic{
+		TopicID: -42, Topic: `Go "golang"`, SourceURL: "https://en.wikipedia.org/wiki/Go", Site: "Wikipedia",
+	})
+	data := DataRecord("wikipedia", -42, datasource.DataSourceData{
+		AnswerID: 7, DataText: "Line one,\nline two <b>&</b> \"quoted\"", SourceURL: "https://en.wikipedia.org/wiki/Go#History",
+	})
+	data.License, data.Attribution = "CC BY-SA 4.0", "Wikipedia contributors"
+	topic.Time, data.Time = exported, exported
+	return []Record{topic, data}
+}
+
+// write writes records with a Writer of the format and returns the output
+func write(t *testing.T, format string, records []Record) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	w, err := New(format, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range records {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func compare(t *testing.T, got, want []Record) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("read %d records, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !got[i].Time.Equal(want[i].Time) {
+			t.Errorf("record %d time = %v, want %v", i, got[i].Time, want[i].Time)
+		}
+		got[i].Time = want[i].Time
+		if got[i] != want[i] {
+			t.Errorf("record %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestJSONLRoundTrip(t *testing.T) {
+	want := records()
+	out := write(t, "jsonl", want)
+
+	var got []Record
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, r)
+	}
+	compare(t, got, want)
+	if !bytes.Contains(out, []byte("<b>&</b>")) {
+		t.Errorf("HTML in text was escaped: %s", out)
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	want := records()
+	rows, err := csv.NewReader(bytes.NewReader(write(t, "csv", want))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) == 0 || strings.Join(rows[0], ",") != strings.Join(csvHeader, ",") {
+		t.Fatalf("header = %q", rows)
+	}
+
+	var got []Record
+	for _, row := range rows[1:] {
+		r := Record{Kind: row[0], Source: row[1], Query: row[2], Title: row[5], Text: row[6], URL: row[7], Site: row[8], License: row[9], Attribution: row[10]}
+		r.TopicID = parseID(t, row[3])
+		r.AnswerID = parseID(t, row[4])
+		if r.Time, err = time.Parse(time.RFC3339, row[11]); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, r)
+	}
+	compare(t, got, want)
+}
+
+func parseID(t *testing.T, cell string) int64 {
+	t.Helper()
+	if cell == "" {
+		return 0
+	}
+	n, err := strconv.ParseInt(cell, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestCSVEmpty(t *testing.T) {
+	if got := string(write(t, "csv", nil)); got != strings.Join(csvHeader, ",")+"\n" {
+		t.Errorf("empty CSV = %q, want the header", got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("JSONL", &bytes.Buffer{}); err != nil {
+		t.Errorf("New(JSONL) = %v", err)
+	}
+	if _, err := New("xml", &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "available: csv, jsonl") {
+		t.Errorf("New(xml) = %v", err)
+	}
+	for name, want := range map[string]string{
+		"out.jsonl": "jsonl", "out.NDJSON": "jsonl", "dir.v2/out.csv": "csv", "out.parquet": "parquet", "out": "",
+	} {
+		if got := FormatOf(name); got != want {
+			t.Errorf("FormatOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
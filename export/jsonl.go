@@ -0,0 +1,30 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// JSONLWriter writes one JSON object per line
+type JSONLWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func NewJSONL(w io.Writer) *JSONLWriter {
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	return &JSONLWriter{buf: buf, enc: enc}
+}
+
+// Write implements Writer
+func (w *JSONLWriter) Write(r Record) error {
+	return w.enc.Encode(r)
+}
+
+// Close implements Writer
+func (w *JSONLWriter) Close() error {
+	return w.buf.Flush()
+}
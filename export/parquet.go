@@ -0,0 +1,34 @@
+//go:build parquet
+
+package export
+
+import (
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func init() {
+	formats["parquet"] = func(w io.Writer) Writer { return NewParquet(w) }
+}
+
+// ParquetWriter writes records as rows of a Parquet file with Record's columns. Rows are buffered into
+// row groups; Close writes the last group and the footer. Built with the "parquet" build tag.
+type ParquetWriter struct {
+	w *parquet.GenericWriter[Record]
+}
+
+func NewParquet(w io.Writer) *ParquetWriter {
+	return &ParquetWriter{w: parquet.NewGenericWriter[Record](w)}
+}
+
+// Write implements Writer
+func (w *ParquetWriter) Write(r Record) error {
+	_, err := w.w.Write([]Record{r})
+	return err
+}
+
+// Close implements Writer
+func (w *ParquetWriter) Close() error {
+	return w.w.Close()
+}
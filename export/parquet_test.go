@@ -0,0 +1,21 @@
+//go:build parquet
+
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestParquetRoundTrip(t *testing.T) {
+	want := records()
+	out := write(t, "parquet", want)
+
+	got, err := parquet.Read[Record](bytes.NewReader(out), int64(len(out)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	compare(t, got, want)
+}
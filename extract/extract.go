@@ -0,0 +1,160 @@
+// Package extract pulls the readable text out of fetched web pages for the
+// adapters that return page content (DuckDuckGo, crawler, scrape, sitemap).
+// It builds on the readability heuristics, adds removal of cookie banners,
+// share bars and similar clutter, applies per-site overrides, and scores
+// the result so callers can fall back to a snippet when a page yields
+// little real text. Pages behind a paywall or consent wall are marked, so
+// callers can try an archived copy instead.
+package extract
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	"github.com/locus-search/datasource/internal/readability"
+)
+
+// DefaultMinQuality is the quality below which Extractor reports ErrLowQuality
+const DefaultMinQuality = 0.25
+
+// ErrLowQuality is returned with results whose quality is below the extractor's minimum
+var ErrLowQuality = errors.New("extract: page has too little article text")
+
+// Site overrides extraction for one site. Selectors are CSS; empty ones fall back to the heuristics.
+type Site struct {
+	Content string `json:"content"` // Element(s) holding the article text
+	Remove  string `json:"remove"`  // Elements to drop before extraction, e.g. reference lists
+	Title   string `json:"title"`   // Element holding the title
+}
+
+// DefaultSites are overrides for common sites whose layout defeats the heuristics
+var DefaultSites = map[string]Site{
+	"wikipedia.org": {
+		Content: "#mw-content-text .mw-parser-output",
+		Remove:  ".mw-editsection, .reference, .reflist, .navbox, .infobox, .hatnote, .metadata, .sidebar, #toc",
+		Title:   "#firstHeading",
+	},
+	"stackoverflow.com":     {Content: "#question .s-prose, .answer .s-prose", Title: "#question-header h1"},
+	"stackexchange.com":     {Content: "#question .s-prose, .answer .s-prose", Title: "#question-header h1"},
+	"github.com":            {Content: "article.markdown-body"},
+	"dev.to":                {Content: "#article-body"},
+	"medium.com":            {Content: "article", Remove: "[data-testid=headerClapButton], [aria-label=responses]"},
+	"developer.mozilla.org": {Content: "main .main-page-content, article", Remove: ".metadata, .bc-table"},
+}
+
+// Result is the extracted content of a page
+type Result struct {
+	Title       string
+	Description string
+	Paragraphs  []string
+	// Quality estimates how much of a real article was found, from 0 to 1: it rewards longer text
+	// and longer paragraphs and penalizes text that is mostly links
+	Quality float64
+	// Wall is Paywall or ConsentWall when the page seems to hide its content behind one, so the text
+	// is a teaser at most; empty otherwise
+	Wall string
+}
+
+// Text returns the paragraphs separated by blank lines
+func (r Result) Text() string {
+	return strings.Join(r.Paragraphs, "\n\n")
+}
+
+// Extractor extracts articles, applying per-site overrides. The zero value uses no overrides and
+// DefaultMinQuality.
+type Extractor struct {
+	Sites      map[string]Site // Keyed by host; a key also matches its subdomains
+	MinQuality float64         // Results below this come with ErrLowQuality; negative disables the check
+}
+
+// Default is an Extractor with DefaultSites
+var Default = &Extractor{Sites: DefaultSites}
+
+// New returns an Extractor with a copy of DefaultSites, ready for more overrides
+func New() *Extractor {
+	sites := make(map[string]Site, len(DefaultSites))
+	for host, s := range DefaultSites {
+		sites[host] = s
+	}
+	return &Extractor{Sites: sites, MinQuality: DefaultMinQuality}
+}
+
+// Reader parses the HTML page at pageURL from r and extracts it
+func (e *Extractor) Reader(r io.Reader, pageURL string) (Result, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return Result{}, err
+	}
+	return e.Document(doc, pageURL)
+}
+
+// Document extracts the article of a parsed page; pageURL selects the site override. The document is
+// modified: boilerplate and clutter are removed. A result with too little text is still returned, along
+// with ErrLowQuality, so callers can decide whether to use it.
+func (e *Extractor) Document(doc *goquery.Document, pageURL string) (Result, error) {
+	site := e.site(pageURL)
+	meta := readability.Metadata(doc)
+	r := Result{Title: meta.Title, Description: meta.Description}
+	if site.Title != "" {
+		if title := clean(doc.Find(site.Title).First().Text()); title != "" {
+			r.Title = title
+		}
+	}
+
+	// Walls are detected first, as their dialogs are removed as clutter
+	walls := detectWalls(doc)
+	readability.RemoveBoilerplate(doc)
+	removeClutter(doc)
+	if site.Remove != "" {
+		doc.Find(site.Remove).Remove()
+	}
+	var content *goquery.Selection
+	if site.Content != "" {
+		content = doc.Find(site.Content)
+	}
+	if content == nil || content.Length() == 0 {
+		content = readability.Content(doc)
+	}
+	for _, p := range readability.Paragraphs(content) {
+		if !boilerplateLine(p) {
+			r.Paragraphs = append(r.Paragraphs, p)
+		}
+	}
+	r.Quality = quality(content, r.Paragraphs)
+	r.Wall = walls.wall(r)
+
+	min := e.MinQuality
+	if min == 0 {
+		min = DefaultMinQuality
+	}
+	if r.Quality < min {
+		return r, ErrLowQuality
+	}
+	return r, nil
+}
+
+// site returns the override for pageURL's host or its closest parent domain
+func (e *Extractor) site(pageURL string) Site {
+	if len(e.Sites) == 0 {
+		return Site{}
+	}
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return Site{}
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	for host != "" {
+		if s, ok := e.Sites[host]; ok {
+			return s
+		}
+		i := strings.IndexByte(host, '.')
+		if i < 0 {
+			break
+		}
+		host = host[i+1:]
+	}
+	return Site{}
+}
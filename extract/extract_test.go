@@ -0,0 +1,152 @@
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// paragraph returns the nth paragraph of article text, long enough that readability keeps it
+func paragraph(n int) string {
+	return fmt.Sprintf("Point %d: Go is an open source programming language that makes it simple to build secure, scalable systems.", n)
+}
+
+// first returns the text of the first n paragraphs
+func first(n int) []string {
+	var text []string
+	for i := 1; i <= n; i++ {
+		text = append(text, paragraph(i))
+	}
+	return text
+}
+
+// html returns the first n paragraphs as HTML
+func html(n int) string {
+	return "<p>" + strings.Join(first(n), "</p><p>") + "</p>"
+}
+
+// article returns a page whose main content is n paragraphs, followed by extra
+func article(extra string, n int) string {
+	return `<html><head><title>About Go</title><meta name="description" content="An introduction"></head><body>` +
+		`<nav><a href="/">Home</a> <a href="/blog">Blog</a></nav>` +
+		`<article><h1>About Go</h1>` + html(n) + `</article>` +
+		extra + `</body></html>`
+}
+
+func TestDocument(t *testing.T) {
+	e := New()
+	for _, tc := range []struct {
+		name string
+		html string
+		url  string
+		want []string // Paragraphs, after the heading
+		wall string
+		err  error
+	}{
+		{
+			name: "article",
+			html: article("", 3),
+			want: first(3),
+		},
+		{
+			name: "clutter by class and ID",
+			html: article(`<div class="cookie-banner"><p>We would like to store small files on your device to improve things.</p></div>`+
+				`<div id="newsletter_signup"><p>Get the best stories about programming in your inbox weekly.</p></div>`+
+				`<aside class="related-posts"><p>Another article about a different programming language entirely.</p></aside>`, 3),
+			want: first(3),
+		},
+		{
+			name: "boilerplate lines",
+			html: `<html><body><article><h1>About Go</h1>` + html(3) +
+				`<p>Share this article with your friends and colleagues today.</p>` +
+				`<p>Copyright 2024 The Go Authors. All rights reserved.</p></article></body></html>`,
+			want: first(3),
+		},
+		{
+			name: "site override",
+			html: `<html><body><h1 id="firstHeading">Go (programming language)</h1>` +
+				`<div class="sidebar-nav"><p>Navigation text that the heuristics might otherwise pick up.</p></div>` +
+				`<div id="mw-content-text"><div class="mw-parser-output">` +
+				`<p>` + paragraph(1) + `<sup class="reference">[1]</sup></p>` +
+				`<div class="navbox"><p>Programming languages navigation box with many links in it.</p></div>` +
+				`<p>` + paragraph(2) + `</p></div></div></body></html>`,
+			url:  "https://en.wikipedia.org/wiki/Go_(programming_language)",
+			want: first(2),
+		},
+		{
+			name: "paywall teaser",
+			html: `<html><head><script type="application/ld+json">{"@type": "NewsArticle", "isAccessibleForFree": "false"}</script></head>` +
+				`<body><article><h1>About Go</h1>` + html(2) + `<p>The rest of this story is available to members who</p></article></body></html>`,
+			want: append(first(2), "The rest of this story is available to members who"),
+			wall: Paywall,
+		},
+		{
+			name: "consent wall",
+			html: `<html><body><div id="onetrust-consent-sdk"><p>We and our partners use cookies to personalise content.</p></div>` +
+				`<p>Before you continue to the site, please make a choice about tracking.</p></body></html>`,
+			want: []string{"Before you continue to the site, please make a choice about tracking."},
+			wall: ConsentWall,
+		},
+		{
+			name: "link list",
+			html: `<html><body><ul>` + strings.Repeat(`<li><a href="/x">A link to some other page on this site</a></li>`, 10) + `</ul></body></html>`,
+			want: []string{"A link to some other page on this site"},
+			err:  ErrLowQuality,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			url := tc.url
+			if url == "" {
+				url = "https://example.com/about"
+			}
+			r, err := e.Reader(strings.NewReader(tc.html), url)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("error = %v, want %v", err, tc.err)
+			}
+			paragraphs := r.Paragraphs
+			if len(paragraphs) > 0 && paragraphs[0] == "About Go" {
+				paragraphs = paragraphs[1:]
+			}
+			if strings.Join(paragraphs, "|") != strings.Join(tc.want, "|") {
+				t.Errorf("Paragraphs = %q, want %q", paragraphs, tc.want)
+			}
+			if r.Wall != tc.wall {
+				t.Errorf("Wall = %q, want %q", r.Wall, tc.wall)
+			}
+		})
+	}
+}
+
+func TestDocumentMetadata(t *testing.T) {
+	r, err := Default.Reader(strings.NewReader(article("", 20)), "https://example.com/about")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Title != "About Go" || r.Description != "An introduction" {
+		t.Errorf("metadata = %q, %q", r.Title, r.Description)
+	}
+	if r.Quality < 0.9 {
+		t.Errorf("Quality of a long article = %.2f", r.Quality)
+	}
+	if !strings.HasPrefix(r.Text(), "About Go\n\n"+paragraph(1)+"\n\n") {
+		t.Errorf("Text = %q", r.Text())
+	}
+}
+
+func TestSite(t *testing.T) {
+	e := &Extractor{Sites: map[string]Site{"example.com": {Content: "main"}, "docs.example.com": {Content: "#docs"}}}
+	for url, want := range map[string]string{
+		"https://www.example.com/a":    "main",
+		"https://blog.example.com/a":   "main", // Subdomains use the parent's override
+		"https://docs.example.com/a":   "#docs",
+		"https://a.docs.example.com/a": "#docs",
+		"https://example.org/a":        "",
+		"https://notexample.com/a":     "",
+		"://not a url":                 "",
+	} {
+		if got := e.site(url).Content; got != want {
+			t.Errorf("site(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package extract
+
+import (
+	"math"
+	"strings"
+
+	goquery "github.com/PuerkitoBio/goquery"
+)
+
+// Words in class names and IDs of elements that hold clutter rather than article text
+var clutterWords = map[string]bool{
+	"cookie": true, "cookies": true, "consent": true, "gdpr": true, "newsletter": true, "subscribe": true,
+	"share": true, "sharing": true, "social": true, "related": true, "recommended": true, "promo": true,
+	"advert": true, "advertisement": true, "ad": true, "ads": true, "sponsored": true, "sidebar": true,
+	"breadcrumb": true, "breadcrumbs": true, "popup": true, "modal": true, "comments": true, "disqus": true,
+}
+
+// Phrases of short paragraphs that are page furniture rather than content
+var boilerplatePhrases = []string{
+	"all rights reserved", "accept cookies", "we use cookies", "this site uses cookies", "cookie policy",
+	"sign up for our newsletter", "subscribe to our newsletter", "share this article", "share on",
+	"skip to content", "skip to main content", "advertisement", "read more:", "related articles",
+}
+
+// removeClutter drops elements whose class or ID names them as clutter, unless they hold most of the
+// page's text, as layout wrappers like "has-sidebar" do
+func removeClutter(doc *goquery.Document) {
+	total := len(clean(doc.Find("body").Text()))
+	doc.Find("[class], [id]").Each(func(_ int, s *goquery.Selection) {
+		if s.Is("html, body, main, article") || !clutterName(s) {
+			return
+		}
+		if total > 0 && len(clean(s.Text()))*2 > total {
+			return
+		}
+		s.Remove()
+	})
+}
+
+func clutterName(s *goquery.Selection) bool {
+	names := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+	for _, word := range strings.FieldsFunc(strings.ToLower(names), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}) {
+		if clutterWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+func boilerplateLine(p string) bool {
+	if len(p) > 200 {
+		return false
+	}
+	lower := strings.ToLower(p)
+	for _, phrase := range boilerplatePhrases {
+		if strings.Contains(lower, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// quality scores extracted paragraphs from 0 to 1: half for length (full marks at 1500 characters), 30%
+// for a low share of link text in the content element and 20% for paragraphs averaging 80 characters
+func quality(content *goquery.Selection, paragraphs []string) float64 {
+	if len(paragraphs) == 0 {
+		return 0
+	}
+	chars := 0
+	for _, p := range paragraphs {
+		chars += len(p)
+	}
+	length := math.Min(1, float64(chars)/1500)
+	average := math.Min(1, float64(chars)/float64(len(paragraphs))/80)
+
+	links := 0
+	content.Find("a").Each(func(_ int, a *goquery.Selection) {
+		links += len(clean(a.Text()))
+	})
+	density := 0.0
+	if text := len(clean(content.Text())); text > 0 {
+		density = float64(links) / float64(text)
+	}
+	linkScore := math.Max(0, 1-2*density)
+	return 0.5*length + 0.3*linkScore + 0.2*average
+}
+
+// Helpers
+
+// clean collapses runs of whitespace into single spaces
+func clean(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
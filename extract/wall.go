@@ -0,0 +1,107 @@
+package extract
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	goquery "github.com/PuerkitoBio/goquery"
+)
+
+// Walls a page can hide its content behind, for Result.Wall
+const (
+	Paywall     = "paywall"
+	ConsentWall = "consent"
+)
+
+// Elements of paywall vendors and common paywall containers
+const paywallSelector = `[class*="paywall"], [id*="paywall"], [data-testid*="paywall"], [class*="regwall"], ` +
+	`.tp-modal, .tp-backdrop, #piano-offer, .meteredContent, #gateway-content, .fc-paywall`
+
+// Elements of consent-management dialogs that block the page until answered
+const consentSelector = `.fc-consent-root, #sp_message_container, [id^="sp_message_container"], ` +
+	`.qc-cmp2-container, #didomi-host, #onetrust-consent-sdk, #CybotCookiebotDialog, .truste_overlay, ` +
+	`#usercentrics-root, form[action*="consent."]`
+
+// Phrases that appear in paywall prompts
+var paywallPhrases = []string{
+	"subscribe to continue reading", "subscribe to read", "to continue reading, subscribe",
+	"this article is for subscribers", "this content is for subscribers", "already a subscriber?",
+	"you have reached your limit of free articles", "you've reached your limit of free articles",
+	"create a free account to continue reading", "sign in to continue reading", "log in to continue reading",
+	"become a member to read",
+}
+
+// Phrases that appear in consent walls
+var consentPhrases = []string{
+	"before you continue to", "we and our partners use cookies", "manage your consent",
+	"reject all to decline", "accept all to continue",
+}
+
+// schema.org markup that news sites use to declare paywalled articles to search engines
+var notFreePattern = regexp.MustCompile(`(?i)"isAccessibleForFree"\s*:\s*"?false"?`)
+
+const (
+	// Text at least this long that ends a sentence is taken as a whole article despite wall signals
+	minCompleteText = 1500
+	// A page with a consent dialog and less text than this shows only the dialog
+	maxConsentText = 500
+)
+
+// wallSignals are what a page says about walls before extraction removes the dialogs
+type wallSignals struct {
+	notFree bool // Declared not accessible for free
+	paywall bool // Paywall elements or prompts
+	consent bool // Consent dialogs or prompts
+}
+
+func detectWalls(doc *goquery.Document) wallSignals {
+	var s wallSignals
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, script *goquery.Selection) bool {
+		s.notFree = notFreePattern.MatchString(script.Text())
+		return !s.notFree
+	})
+	if doc.Find(`[itemprop="isAccessibleForFree"][content="false"], [itemprop="isAccessibleForFree"][content="False"]`).Length() > 0 {
+		s.notFree = true
+	}
+	s.paywall = doc.Find(paywallSelector).Length() > 0
+	s.consent = doc.Find(consentSelector).Length() > 0
+
+	text := strings.ToLower(clean(doc.Find("body").Text()))
+	for _, phrase := range paywallPhrases {
+		if strings.Contains(text, phrase) {
+			s.paywall = true
+			break
+		}
+	}
+	for _, phrase := range consentPhrases {
+		if strings.Contains(text, phrase) {
+			s.consent = true
+			break
+		}
+	}
+	return s
+}
+
+// wall decides from the signals and the extracted text whether the page showed only a teaser or a
+// consent dialog
+func (s wallSignals) wall(r Result) string {
+	text := r.Text()
+	switch {
+	case (s.notFree || s.paywall) && truncated(text):
+		return Paywall
+	case s.consent && len(text) < maxConsentText:
+		return ConsentWall
+	}
+	return ""
+}
+
+// truncated reports whether text looks cut off: short, or ending mid-sentence or with an ellipsis
+func truncated(text string) bool {
+	text = strings.TrimSpace(text)
+	if len(text) < minCompleteText || strings.HasSuffix(text, "...") {
+		return true
+	}
+	last, _ := utf8.DecodeLastRuneInString(text)
+	return !strings.ContainsRune(`.!?"')]”’`, last)
+}
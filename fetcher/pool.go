@@ -0,0 +1,191 @@
+// Package fetcher hydrates many topics at once. A Pool calls FetchData for
+// a batch of topics concurrently under a global cap, while limiting how many
+// requests go to one host at a time and how often they start, so filling in
+// content for a hundred federated results is quick without hammering any
+// single site.
+package fetcher
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+	"golang.org/x/time/rate"
+)
+
+// Job is one topic to hydrate from the source that found it
+type Job struct {
+	Source source.Source
+	Topic  datasource.DataSourceTopic
+}
+
+// Result is the outcome of a job
+type Result struct {
+	Topic datasource.DataSourceTopic
+	Data  []datasource.DataSourceData
+	Err   error
+}
+
+// Hoster is implemented by sources whose FetchData contacts a host that depends on the topic, such as
+// web search adapters that fetch each result's page
+type Hoster interface {
+	// FetchHost returns the host FetchData contacts for the topic; "" when unknown
+	FetchHost(topicID int64) string
+}
+
+// Pool runs FetchData jobs concurrently. Jobs are grouped by the host FetchData contacts, see Host; jobs
+// without one are only subject to the global cap. A Pool may be reused and shared; its per-host limits
+// then apply across all runs, and are dropped once a host has been idle for HostDelay.
+type Pool struct {
+	Concurrency int           // Jobs in flight across all hosts; default 8
+	PerHost     int           // Jobs in flight for one host; default 2
+	HostDelay   time.Duration // Minimum time between job starts for one host; default 500ms, negative for none
+	Count       int           // Data items fetched per topic; default 3
+
+	mu    sync.Mutex
+	hosts map[string]*host
+}
+
+type host struct {
+	slots   chan struct{}
+	limiter *rate.Limiter
+	jobs    int       // Jobs holding or waiting for a slot; guarded by Pool.mu
+	idle    time.Time // When jobs last fell to zero
+}
+
+func New() *Pool {
+	return &Pool{Concurrency: 8, PerHost: 2, HostDelay: 500 * time.Millisecond, Count: 3}
+}
+
+// Hydrate fetches data for topics from one source; results are in the order of topics
+func (p *Pool) Hydrate(ctx context.Context, s source.Source, topics []datasource.DataSourceTopic) []Result {
+	jobs := make([]Job, len(topics))
+	for i, t := range topics {
+		jobs[i] = Job{Source: s, Topic: t}
+	}
+	return p.Run(ctx, jobs)
+}
+
+// Run fetches data for every job and returns the results in the order of jobs. Jobs not started
+// before ctx ends get ctx's error.
+func (p *Pool) Run(ctx context.Context, jobs []Job) []Result {
+	concurrency := p.Concurrency
+	if concurrency <= 0 {
+		concurrency = 8
+	}
+	count := p.Count
+	if count <= 0 {
+		count = 3
+	}
+	results := make([]Result, len(jobs))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		results[i].Topic = job.Topic
+		wg.Add(1)
+		go func(r *Result, job Job) {
+			defer wg.Done()
+			h := p.host(Host(job.Source, job.Topic.TopicID))
+			// Take the host slot first, so jobs queued behind a busy host don't hold global slots
+			if h != nil {
+				defer p.release(h)
+				select {
+				case h.slots <- struct{}{}:
+					defer func() { <-h.slots }()
+				case <-ctx.Done():
+					r.Err = ctx.Err()
+					return
+				}
+				if err := h.limiter.Wait(ctx); err != nil {
+					r.Err = err
+					return
+				}
+			}
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				r.Err = ctx.Err()
+				return
+			}
+			r.Data, r.Err = source.FetchDataContext(ctx, job.Source, count, job.Topic.TopicID)
+		}(&results[i], job)
+	}
+	wg.Wait()
+	return results
+}
+
+// host returns the limits for a host, creating them on first use, and counts a job against them until
+// release; nil for the empty host
+func (p *Pool) host(name string) *host {
+	if name == "" {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if h, ok := p.hosts[name]; ok {
+		h.jobs++
+		return h
+	}
+	perHost := p.PerHost
+	if perHost <= 0 {
+		perHost = 2
+	}
+	delay := p.HostDelay
+	if delay == 0 {
+		delay = 500 * time.Millisecond
+	}
+	limit := rate.Inf
+	if delay > 0 {
+		limit = rate.Every(delay)
+	}
+	if p.hosts == nil {
+		p.hosts = map[string]*host{}
+	}
+	// Forget hosts idle for longer than the delay, whose limiters would let a job start at once anyway
+	now := time.Now()
+	for k, h := range p.hosts {
+		if h.jobs == 0 && now.Sub(h.idle) >= delay {
+			delete(p.hosts, k)
+		}
+	}
+	h := &host{slots: make(chan struct{}, perHost), limiter: rate.NewLimiter(limit, 1), jobs: 1}
+	p.hosts[name] = h
+	return h
+}
+
+// release ends a job's claim on a host from host
+func (p *Pool) release(h *host) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h.jobs--
+	if h.jobs == 0 {
+		h.idle = time.Now()
+	}
+}
+
+// Host returns the key a topic's job is limited under: the host FetchData contacts for the topic when s,
+// or a source it wraps, implements Hoster, or else the source's own backend, named from its
+// Capabilities. It returns "" for unnamed sources.
+func Host(s source.Source, topicID int64) string {
+	for inner := s; inner != nil; {
+		if h, ok := inner.(Hoster); ok {
+			if name := h.FetchHost(topicID); name != "" {
+				return strings.TrimPrefix(strings.ToLower(name), "www.")
+			}
+			break
+		}
+		w, ok := inner.(source.Wrapper)
+		if !ok {
+			break
+		}
+		inner = w.Unwrap()
+	}
+	if name := source.Describe(s).Name; name != "" {
+		return "source:" + strings.ToLower(name)
+	}
+	return ""
+}
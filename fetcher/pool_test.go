@@ -0,0 +1,156 @@
+package fetcher
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+// pages is a source fetching each topic from the host named in its Site, recording how many fetches
+// are in flight for each host and when they start
+type pages struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+	peak     map[string]int
+	starts   []time.Time
+}
+
+func newPages() *pages {
+	return &pages{inFlight: map[string]int{}, peak: map[string]int{}}
+}
+
+func (p *pages) Init() error             { return nil }
+func (p *pages) CheckAvailability() bool { return true }
+
+func (p *pages) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return nil, nil
+}
+
+func (p *pages) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	host := p.FetchHost(topicID)
+	p.mu.Lock()
+	p.inFlight[host]++
+	p.peak[host] = max(p.peak[host], p.inFlight[host])
+	p.starts = append(p.starts, time.Now())
+	p.mu.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	p.mu.Lock()
+	p.inFlight[host]--
+	p.mu.Unlock()
+	return []datasource.DataSourceData{{DataText: host, AnswerID: topicID}}, nil
+}
+
+// FetchHost puts odd topics on one host and even ones on another
+func (p *pages) FetchHost(topicID int64) string {
+	if topicID%2 == 1 {
+		return "www.Odd.example"
+	}
+	return "even.example"
+}
+
+func topics(n int) []datasource.DataSourceTopic {
+	topics := make([]datasource.DataSourceTopic, n)
+	for i := range topics {
+		// The topic's URL is not where FetchData goes
+		topics[i] = datasource.DataSourceTopic{TopicID: int64(i), SourceURL: "https://search.example/result"}
+	}
+	return topics
+}
+
+func TestPerHostCap(t *testing.T) {
+	s := newPages()
+	p := &Pool{Concurrency: 8, PerHost: 2, HostDelay: -1}
+	results := p.Hydrate(context.Background(), s, topics(10))
+	for i, r := range results {
+		if r.Err != nil || len(r.Data) != 1 {
+			t.Fatalf("result %d = %+v", i, r)
+		}
+	}
+	for _, host := range []string{"www.Odd.example", "even.example"} {
+		if got := s.peak[host]; got != 2 {
+			t.Errorf("peak fetches for %s = %d, want 2", host, got)
+		}
+	}
+	if results[1].Data[0].DataText != "www.Odd.example" {
+		t.Errorf("result 1 = %+v", results[1])
+	}
+}
+
+func TestHostDelay(t *testing.T) {
+	s := newPages()
+	p := &Pool{Concurrency: 8, PerHost: 8, HostDelay: 50 * time.Millisecond}
+	// Three topics on the odd host
+	jobs := []Job{{s, datasource.DataSourceTopic{TopicID: 1}}, {s, datasource.DataSourceTopic{TopicID: 3}}, {s, datasource.DataSourceTopic{TopicID: 5}}}
+	p.Run(context.Background(), jobs)
+
+	if len(s.starts) != 3 {
+		t.Fatalf("%d fetches, want 3", len(s.starts))
+	}
+	for i := 1; i < len(s.starts); i++ {
+		// Allow for timer granularity
+		if gap := s.starts[i].Sub(s.starts[i-1]); gap < 40*time.Millisecond {
+			t.Errorf("fetch %d started %v after the previous one", i, gap)
+		}
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	p := &Pool{HostDelay: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	// The second topic on the even host waits an hour for its turn
+	results := p.Hydrate(ctx, newPages(), []datasource.DataSourceTopic{{TopicID: 2}, {TopicID: 4}})
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	if failed != 1 {
+		t.Errorf("results = %+v, want one fetched and one with ctx's error", results)
+	}
+}
+
+func TestIdleHostsEvicted(t *testing.T) {
+	s := newPages()
+	p := &Pool{HostDelay: -1}
+	p.Hydrate(context.Background(), s, topics(2)[1:])
+	if _, ok := p.hosts["odd.example"]; !ok {
+		t.Fatalf("hosts = %v, want odd.example", p.hosts)
+	}
+	p.Hydrate(context.Background(), s, topics(1))
+	if _, ok := p.hosts["odd.example"]; ok || len(p.hosts) != 1 {
+		t.Errorf("hosts = %v, want only even.example", p.hosts)
+	}
+}
+
+func TestHost(t *testing.T) {
+	named := source.WithInfo(newPages(), source.SourceInfo{Name: "Pages"})
+	if got := Host(named, 1); got != "odd.example" {
+		t.Errorf("Host through a wrapper = %q", got)
+	}
+	if got := Host(source.WithInfo(plain{}, source.SourceInfo{Name: "Archive"}), 1); got != "source:archive" {
+		t.Errorf("Host of a source without Hoster = %q", got)
+	}
+	if got := Host(plain{}, 1); got != "" {
+		t.Errorf("Host of an unnamed source = %q", got)
+	}
+}
+
+// plain is a source that doesn't say which host it contacts
+type plain struct{}
+
+func (plain) Init() error             { return nil }
+func (plain) CheckAvailability() bool { return true }
+
+func (plain) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return nil, nil
+}
+
+func (plain) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return nil, nil
+}
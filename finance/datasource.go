@@ -0,0 +1,355 @@
+package finance
+
+// Data Source Adapter for market data from Alpha Vantage
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/time/rate"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceFinance struct {
+	Client         *http.Client
+	BaseURL        string
+	APIKey         string
+	UserAgent      string
+	RequestsPerMin int // Free keys allow 5 requests per minute
+
+	symbols     topicid.Map[symbol]
+	rateLimiter *rate.Limiter
+}
+
+// symbol is a matched security
+type symbol struct {
+	Ticker   string
+	Name     string
+	Region   string
+	Currency string
+}
+
+func New() *DataSourceFinance {
+	return &DataSourceFinance{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:        "https://www.alphavantage.co/query",
+		UserAgent:      "locus/finance-datasource",
+		RequestsPerMin: 5,
+	}
+}
+
+// Init implements models.DataSource
+// Requires an API key and sets up the per-minute request budget
+func (es *DataSourceFinance) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.APIKey == "" {
+		return errors.New("APIKey is required for Finance DataSource")
+	}
+	if es.rateLimiter == nil {
+		perMin := es.RequestsPerMin
+		if perMin <= 0 {
+			perMin = 5
+		}
+		es.rateLimiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMin)), perMin)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceFinance) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("function", "MARKET_STATUS")
+	return es.doJSON(ctx, params, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceFinance) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Alpha Vantage",
+		Homepage: "https://www.alphavantage.co",
+		TermsURL: "https://www.alphavantage.co/terms_of_service/",
+	}
+}
+
+// Describe implements source.Describer
+func (es *DataSourceFinance) Describe() source.Capabilities {
+	perMin := es.RequestsPerMin
+	if perMin <= 0 {
+		perMin = 5
+	}
+	return source.Capabilities{Name: es.Info().Name, Data: true, RateLimit: source.RateLimit{Requests: perMin, Per: time.Minute}}
+}
+
+// FetchTopics implements models.DataSource
+// Matches tickers and company names; topics read "AAPL: Apple Inc (Equity, United States, USD)"
+func (es *DataSourceFinance) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Finance DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("function", "SYMBOL_SEARCH")
+	params.Set("keywords", query)
+	var response struct {
+		BestMatches []struct {
+			Symbol   string `json:"1. symbol"`
+			Name     string `json:"2. name"`
+			Type     string `json:"3. type"`
+			Region   string `json:"4. region"`
+			Currency string `json:"8. currency"`
+		} `json:"bestMatches"`
+	}
+	if err := es.doJSON(ctx, params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, match := range response.BestMatches {
+		if len(results) >= count {
+			break
+		}
+		sym := symbol{Ticker: match.Symbol, Name: match.Name, Region: match.Region, Currency: match.Currency}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s: %s (%s, %s, %s)", match.Symbol, match.Name, match.Type, match.Region, match.Currency),
+			SourceURL: quoteLink(match.Symbol),
+			Site:      match.Region,
+			TopicID:   es.symbols.Put(match.Symbol, sym),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the latest quote, then company key statistics, then recent headlines up to count items
+func (es *DataSourceFinance) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	sym, ok := es.symbols.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Finance topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	quote, err := es.fetchQuote(ctx, sym)
+	if err != nil {
+		return nil, err
+	}
+	results := []datasource.DataSourceData{{
+		DataText:  quote,
+		SourceURL: quoteLink(sym.Ticker),
+		AnswerID:  topicID,
+	}}
+
+	// Overview and news are only published for some listings and eat into the request budget; skip them on failure
+	if len(results) < count {
+		if stats, err := es.fetchOverview(ctx, sym); err == nil && stats != "" {
+			results = append(results, datasource.DataSourceData{
+				DataText:  stats,
+				SourceURL: quoteLink(sym.Ticker),
+				AnswerID:  topicid.Hash(sym.Ticker + "#overview"),
+			})
+		}
+	}
+	if len(results) < count {
+		if headlines, err := es.fetchHeadlines(ctx, sym, count-len(results)); err == nil {
+			results = append(results, headlines...)
+		}
+	}
+	return results, nil
+}
+
+func (es *DataSourceFinance) fetchQuote(ctx context.Context, sym symbol) (string, error) {
+	params := url.Values{}
+	params.Set("function", "GLOBAL_QUOTE")
+	params.Set("symbol", sym.Ticker)
+	var response struct {
+		Quote struct {
+			Open          string `json:"02. open"`
+			High          string `json:"03. high"`
+			Low           string `json:"04. low"`
+			Price         string `json:"05. price"`
+			Volume        string `json:"06. volume"`
+			LatestDay     string `json:"07. latest trading day"`
+			PreviousClose string `json:"08. previous close"`
+			Change        string `json:"09. change"`
+			ChangePercent string `json:"10. change percent"`
+		} `json:"Global Quote"`
+	}
+	if err := es.doJSON(ctx, params, &response); err != nil {
+		return "", err
+	}
+	q := response.Quote
+	if q.Price == "" {
+		return "", fmt.Errorf("no quote available for %s", sym.Ticker)
+	}
+	return fmt.Sprintf("%s (%s) closed at %s %s on %s, change %s (%s). Open %s, high %s, low %s, previous close %s, volume %s.",
+		sym.Name, sym.Ticker, q.Price, sym.Currency, q.LatestDay, q.Change, q.ChangePercent, q.Open, q.High, q.Low, q.PreviousClose, q.Volume), nil
+}
+
+func (es *DataSourceFinance) fetchOverview(ctx context.Context, sym symbol) (string, error) {
+	params := url.Values{}
+	params.Set("function", "OVERVIEW")
+	params.Set("symbol", sym.Ticker)
+	var overview map[string]string
+	if err := es.doJSON(ctx, params, &overview); err != nil {
+		return "", err
+	}
+	if len(overview) == 0 {
+		return "", nil
+	}
+
+	var b strings.Builder
+	if description := overview["Description"]; description != "" {
+		b.WriteString(description + "\n\n")
+	}
+	stats := []struct{ label, key string }{
+		{"Exchange", "Exchange"},
+		{"Sector", "Sector"},
+		{"Industry", "Industry"},
+		{"Market capitalization", "MarketCapitalization"},
+		{"P/E ratio", "PERatio"},
+		{"EPS", "EPS"},
+		{"Dividend yield", "DividendYield"},
+		{"52-week high", "52WeekHigh"},
+		{"52-week low", "52WeekLow"},
+		{"Analyst target price", "AnalystTargetPrice"},
+	}
+	for _, stat := range stats {
+		// Alpha Vantage reports absent values as "None" or "-"
+		if value := overview[stat.key]; value != "" && value != "None" && value != "-" {
+			fmt.Fprintf(&b, "%s: %s\n", stat.label, value)
+		}
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+func (es *DataSourceFinance) fetchHeadlines(ctx context.Context, sym symbol, limit int) ([]datasource.DataSourceData, error) {
+	params := url.Values{}
+	params.Set("function", "NEWS_SENTIMENT")
+	params.Set("tickers", sym.Ticker)
+	params.Set("sort", "LATEST")
+	params.Set("limit", strconv.Itoa(limit))
+	var response struct {
+		Feed []struct {
+			Title         string `json:"title"`
+			URL           string `json:"url"`
+			TimePublished string `json:"time_published"`
+			Source        string `json:"source"`
+			Summary       string `json:"summary"`
+		} `json:"feed"`
+	}
+	if err := es.doJSON(ctx, params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceData, 0, limit)
+	for _, item := range response.Feed {
+		if len(results) >= limit {
+			break
+		}
+		published := item.TimePublished
+		if t, err := time.Parse("20060102T150405", item.TimePublished); err == nil {
+			published = t.Format("2006-01-02 15:04")
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  fmt.Sprintf("%s (%s, %s)\n%s", item.Title, item.Source, published, item.Summary),
+			SourceURL: item.URL,
+			Site:      item.Source,
+			AnswerID:  topicid.Hash(item.URL),
+		})
+	}
+	return results, nil
+}
+
+// doJSON performs a rate-limited Alpha Vantage query and decodes the JSON response into target.
+// Alpha Vantage reports errors and exhausted quotas with status 200, so the body is inspected first.
+func (es *DataSourceFinance) doJSON(ctx context.Context, params url.Values, target interface{}) error {
+	if es.rateLimiter != nil {
+		if err := es.rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	params.Set("apikey", es.APIKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, es.BaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("finance request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var status struct {
+		ErrorMessage string `json:"Error Message"`
+		Note         string `json:"Note"`
+		Information  string `json:"Information"`
+	}
+	if json.Unmarshal(body, &status) == nil {
+		for _, message := range []string{status.ErrorMessage, status.Note, status.Information} {
+			if message != "" {
+				return fmt.Errorf("finance request failed: %s", message)
+			}
+		}
+	}
+	return json.Unmarshal(body, target)
+}
+
+// Helpers
+func quoteLink(ticker string) string {
+	return "https://finance.yahoo.com/quote/" + url.PathEscape(ticker)
+}
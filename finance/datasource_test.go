@@ -0,0 +1,83 @@
+package finance
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("apikey") != "key" {
+			t.Errorf("apikey = %q", q.Get("apikey"))
+		}
+		switch q.Get("function") {
+		case "SYMBOL_SEARCH":
+			if q.Get("keywords") != "apple" {
+				t.Errorf("keywords = %q", q.Get("keywords"))
+			}
+			fmt.Fprint(w, `{"bestMatches":[{"1. symbol":"AAPL","2. name":"Apple Inc","3. type":"Equity","4. region":"United States","8. currency":"USD"}]}`)
+		case "GLOBAL_QUOTE":
+			fmt.Fprint(w, `{"Global Quote":{"02. open":"1","03. high":"3","04. low":"0.5","05. price":"2","06. volume":"100","07. latest trading day":"2024-05-01","08. previous close":"1.5","09. change":"0.5","10. change percent":"33%"}}`)
+		case "OVERVIEW":
+			fmt.Fprint(w, `{"Description":"Makes phones.","Sector":"TECHNOLOGY","PERatio":"None","EPS":"6.4"}`)
+		case "NEWS_SENTIMENT":
+			if q.Get("tickers") != "AAPL" || q.Get("limit") != "1" {
+				t.Errorf("news query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"feed":[{"title":"Apple up","url":"https://news.example/1","time_published":"20240501T133000","source":"Wire","summary":"Shares rose."}]}`)
+		default:
+			fmt.Fprint(w, `{"Note":"Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute."}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.APIKey = "key"
+	es.RequestsPerMin = 60
+
+	topics, err := es.FetchTopics(5, "apple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "AAPL: Apple Inc (Equity, United States, USD)" || topics[0].SourceURL != "https://finance.yahoo.com/quote/AAPL" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Apple Inc (AAPL) closed at 2 USD on 2024-05-01, change 0.5 (33%). Open 1, high 3, low 0.5, previous close 1.5, volume 100.",
+		"Makes phones.\n\nSector: TECHNOLOGY\nEPS: 6.4",
+		"Apple up (Wire, 2024-05-01 13:30)\nShares rose.",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+}
+
+func TestQuotaNotice(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.APIKey = "key"
+	if err := es.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := es.doJSON(t.Context(), map[string][]string{"function": {"TIME_SERIES_DAILY"}}, &struct{}{}); err == nil {
+		t.Error("doJSON ignored a rate limit notice served with status 200")
+	}
+}
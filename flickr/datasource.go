@@ -0,0 +1,405 @@
+package flickr
+
+// Data Source Adapter for Flickr photo search (REST API)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+// License sets for the Licenses field, by Flickr license ID
+var (
+	// OpenLicenses is every Creative Commons and public domain license
+	OpenLicenses = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	// ReusableLicenses allows commercial use and modification
+	ReusableLicenses = []int{4, 5, 7, 8, 9, 10, 11, 12}
+)
+
+// licenseNames mirrors flickr.photos.licenses.getInfo
+var licenseNames = map[int]string{
+	0:  "All Rights Reserved",
+	1:  "CC BY-NC-SA 2.0",
+	2:  "CC BY-NC 2.0",
+	3:  "CC BY-NC-ND 2.0",
+	4:  "CC BY 2.0",
+	5:  "CC BY-SA 2.0",
+	6:  "CC BY-ND 2.0",
+	7:  "No known copyright restrictions",
+	8:  "United States Government Work",
+	9:  "CC0 1.0",
+	10: "Public Domain Mark 1.0",
+	11: "CC BY 4.0",
+	12: "CC BY-SA 4.0",
+	13: "CC BY-ND 4.0",
+	14: "CC BY-NC 4.0",
+	15: "CC BY-NC-SA 4.0",
+	16: "CC BY-NC-ND 4.0",
+}
+
+// exifLabels are the EXIF fields reported by FetchData, in order
+var exifLabels = []string{"Make", "Model", "Lens Model", "Exposure", "Aperture", "ISO Speed", "Focal Length", "Flash", "Date and Time (Original)"}
+
+type DataSourceFlickr struct {
+	Client    *http.Client
+	BaseURL   string
+	APIKey    string
+	UserAgent string
+	Licenses  []int // Flickr license IDs to search; empty for any, including all rights reserved
+	Geotagged bool  // Only return photos with a location
+
+	photos topicid.Map[photo]
+}
+
+type content struct {
+	Content string `json:"_content"`
+}
+
+type photo struct {
+	ID        string
+	Owner     string
+	Title     string
+	OwnerName string
+	License   int
+}
+
+func New() *DataSourceFlickr {
+	return &DataSourceFlickr{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://api.flickr.com/services/rest/",
+		UserAgent: "locus/flickr-datasource",
+		Licenses:  OpenLicenses,
+	}
+}
+
+// NSFWCapable reports that this source can return explicit or offensive photos, so content filters
+// wrapping it should screen its results
+func (es *DataSourceFlickr) NSFWCapable() bool {
+	return true
+}
+
+// Init implements models.DataSource
+// Requires an API key
+func (es *DataSourceFlickr) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.APIKey == "" {
+		return errors.New("APIKey is required for Flickr DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceFlickr) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	return es.call(ctx, "flickr.test.echo", url.Values{}, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceFlickr) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "Flickr",
+		Homepage:            "https://www.flickr.com",
+		Attribution:         "Photos from Flickr; credit each photographer as the photo's license requires",
+		AttributionRequired: true,
+		TermsURL:            "https://www.flickr.com/help/terms",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Photos are ordered by relevance; topics read "Title by Owner (CC BY 2.0)"
+func (es *DataSourceFlickr) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Flickr DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("text", query)
+	params.Set("sort", "relevance")
+	params.Set("content_types", "0")
+	params.Set("safe_search", "1")
+	params.Set("per_page", strconv.Itoa(count))
+	params.Set("extras", "license,owner_name")
+	if len(es.Licenses) > 0 {
+		licenses := make([]string, 0, len(es.Licenses))
+		for _, license := range es.Licenses {
+			licenses = append(licenses, strconv.Itoa(license))
+		}
+		params.Set("license", strings.Join(licenses, ","))
+	}
+	if es.Geotagged {
+		params.Set("has_geo", "1")
+	}
+	var response struct {
+		Photos struct {
+			Photo []struct {
+				ID        string `json:"id"`
+				Owner     string `json:"owner"`
+				Title     string `json:"title"`
+				OwnerName string `json:"ownername"`
+				License   string `json:"license"`
+			} `json:"photo"`
+		} `json:"photos"`
+	}
+	if err := es.call(ctx, "flickr.photos.search", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Photos.Photo))
+	for _, p := range response.Photos.Photo {
+		license, _ := strconv.Atoi(p.License)
+		title := strings.TrimSpace(p.Title)
+		if title == "" {
+			title = "Untitled"
+		}
+		ph := photo{ID: p.ID, Owner: p.Owner, Title: title, OwnerName: p.OwnerName, License: license}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s by %s (%s)", title, p.OwnerName, licenseName(license)),
+			SourceURL: photoLink(ph),
+			Site:      "flickr.com",
+			TopicID:   es.photos.Put(p.ID, ph),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the photo's description, date, tags, license, location and image URL, then its EXIF camera
+// settings when the owner allows them to be shown
+func (es *DataSourceFlickr) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	p, ok := es.photos.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Flickr topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	params := url.Values{}
+	params.Set("photo_id", p.ID)
+	var info struct {
+		Photo struct {
+			Title       content `json:"title"`
+			Description content `json:"description"`
+			Views       string  `json:"views"`
+			Dates       struct {
+				Taken string `json:"taken"`
+			} `json:"dates"`
+			Tags struct {
+				Tag []struct {
+					Raw string `json:"raw"`
+				} `json:"tag"`
+			} `json:"tags"`
+			Location *struct {
+				Latitude  string  `json:"latitude"`
+				Longitude string  `json:"longitude"`
+				Locality  content `json:"locality"`
+				Region    content `json:"region"`
+				Country   content `json:"country"`
+			} `json:"location"`
+		} `json:"photo"`
+	}
+	if err := es.call(ctx, "flickr.photos.getInfo", params, &info); err != nil {
+		return nil, err
+	}
+
+	details := info.Photo
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s by %s", p.Title, p.OwnerName)
+	if description := strings.TrimSpace(details.Description.Content); description != "" {
+		b.WriteString("\n\n" + description)
+	}
+	b.WriteString("\n")
+	if details.Dates.Taken != "" {
+		b.WriteString("\nTaken: " + details.Dates.Taken)
+	}
+	if location := details.Location; location != nil {
+		var place []string
+		for _, part := range []string{location.Locality.Content, location.Region.Content, location.Country.Content} {
+			if part != "" {
+				place = append(place, part)
+			}
+		}
+		fmt.Fprintf(&b, "\nLocation: %s,%s", location.Latitude, location.Longitude)
+		if len(place) > 0 {
+			b.WriteString(" (" + strings.Join(place, ", ") + ")")
+		}
+	}
+	if len(details.Tags.Tag) > 0 {
+		tags := make([]string, 0, len(details.Tags.Tag))
+		for _, tag := range details.Tags.Tag {
+			tags = append(tags, tag.Raw)
+		}
+		b.WriteString("\nTags: " + strings.Join(tags, ", "))
+	}
+	if details.Views != "" {
+		b.WriteString("\nViews: " + details.Views)
+	}
+	b.WriteString("\nLicense: " + licenseName(p.License))
+
+	var sizes struct {
+		Sizes struct {
+			Size []struct {
+				Label  string      `json:"label"`
+				Source string      `json:"source"`
+				Width  json.Number `json:"width"`
+				Height json.Number `json:"height"`
+			} `json:"size"`
+		} `json:"sizes"`
+	}
+	if err := es.call(ctx, "flickr.photos.getSizes", params, &sizes); err == nil && len(sizes.Sizes.Size) > 0 {
+		// Sizes are listed smallest first; the last is the largest available to this key
+		largest := sizes.Sizes.Size[len(sizes.Sizes.Size)-1]
+		fmt.Fprintf(&b, "\nImage: %s (%s×%s)", largest.Source, largest.Width, largest.Height)
+	}
+
+	results := []datasource.DataSourceData{{
+		DataText:  b.String(),
+		SourceURL: photoLink(p),
+		Site:      "flickr.com",
+		AnswerID:  topicID,
+	}}
+	if count == 1 {
+		return results, nil
+	}
+
+	// Owners can hide EXIF data; that is not an error for the photo itself
+	var exif struct {
+		Photo struct {
+			Camera string `json:"camera"`
+			EXIF   []struct {
+				Label string   `json:"label"`
+				Raw   content  `json:"raw"`
+				Clean *content `json:"clean"`
+			} `json:"exif"`
+		} `json:"photo"`
+	}
+	if err := es.call(ctx, "flickr.photos.getExif", params, &exif); err != nil {
+		return results, nil
+	}
+	values := map[string]string{}
+	for _, tag := range exif.Photo.EXIF {
+		if _, seen := values[tag.Label]; seen {
+			continue
+		}
+		value := tag.Raw.Content
+		if tag.Clean != nil && tag.Clean.Content != "" {
+			value = tag.Clean.Content
+		}
+		values[tag.Label] = value
+	}
+	var lines []string
+	if exif.Photo.Camera != "" {
+		lines = append(lines, "Camera: "+exif.Photo.Camera)
+	}
+	for _, label := range exifLabels {
+		if value := values[label]; value != "" {
+			lines = append(lines, label+": "+value)
+		}
+	}
+	if len(lines) > 0 {
+		results = append(results, datasource.DataSourceData{
+			DataText:  "EXIF for " + p.Title + ":\n" + strings.Join(lines, "\n"),
+			SourceURL: photoLink(p) + "meta/",
+			Site:      "flickr.com",
+			AnswerID:  topicid.Hash(p.ID + "#exif"),
+		})
+	}
+	return results, nil
+}
+
+// call invokes a Flickr REST method and decodes the JSON response into target.
+// Method failures arrive with status 200 as {"stat": "fail", "code": ..., "message": ...}.
+func (es *DataSourceFlickr) call(ctx context.Context, method string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	query.Set("method", method)
+	query.Set("api_key", es.APIKey)
+	query.Set("format", "json")
+	query.Set("nojsoncallback", "1")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, es.BaseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("flickr request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var status struct {
+		Stat    string `json:"stat"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return err
+	}
+	if status.Stat != "ok" {
+		return fmt.Errorf("flickr %s failed: error %d: %s", method, status.Code, status.Message)
+	}
+	return json.Unmarshal(body, target)
+}
+
+// Helpers
+func licenseName(id int) string {
+	if name, ok := licenseNames[id]; ok {
+		return name
+	}
+	return "license " + strconv.Itoa(id)
+}
+
+func photoLink(p photo) string {
+	return fmt.Sprintf("https://www.flickr.com/photos/%s/%s/", p.Owner, p.ID)
+}
@@ -0,0 +1,101 @@
+package flickr
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("api_key") != "key" || q.Get("format") != "json" || q.Get("nojsoncallback") != "1" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch q.Get("method") {
+		case "flickr.photos.search":
+			if q.Get("text") != "lighthouse" || q.Get("license") != "4,5,7,8,9,10,11,12" || q.Get("has_geo") != "1" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"stat":"ok","photos":{"photo":[
+				{"id":"101","owner":"12@N01","title":"Lighthouse at dusk","ownername":"Jane","license":"4"},
+				{"id":"102","owner":"34@N01","title":" ","ownername":"John","license":"99"}
+			]}}`)
+		case "flickr.photos.getInfo":
+			fmt.Fprint(w, `{"stat":"ok","photo":{"description":{"_content":"Taken from the pier."},"views":"321",
+				"dates":{"taken":"2023-07-01 20:15:00"},"tags":{"tag":[{"raw":"lighthouse"},{"raw":"sunset"}]},
+				"location":{"latitude":"50.1","longitude":"-5.2","locality":{"_content":"Penzance"},"region":{"_content":""},"country":{"_content":"United Kingdom"}}}}`)
+		case "flickr.photos.getSizes":
+			fmt.Fprint(w, `{"stat":"ok","sizes":{"size":[
+				{"label":"Small","source":"https://live.staticflickr.com/small.jpg","width":240,"height":160},
+				{"label":"Large","source":"https://live.staticflickr.com/large.jpg","width":"1024","height":"683"}
+			]}}`)
+		case "flickr.photos.getExif":
+			fmt.Fprint(w, `{"stat":"ok","photo":{"camera":"Canon EOS R5","exif":[
+				{"label":"Exposure","raw":{"_content":"1/250"}},
+				{"label":"Aperture","raw":{"_content":"8.0"},"clean":{"_content":"f/8.0"}},
+				{"label":"Exposure","raw":{"_content":"ignored"}}
+			]}}`)
+		default:
+			fmt.Fprint(w, `{"stat":"fail","code":112,"message":"Method not found"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.APIKey = "key"
+	es.Licenses = ReusableLicenses
+	es.Geotagged = true
+
+	topics, err := es.FetchTopics(5, "lighthouse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Lighthouse at dusk by Jane (CC BY 2.0)", "Untitled by John (license 99)"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+	if topics[0].SourceURL != "https://www.flickr.com/photos/12@N01/101/" {
+		t.Errorf("SourceURL = %q", topics[0].SourceURL)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := []string{
+		"Lighthouse at dusk by Jane\n\nTaken from the pier.\n\nTaken: 2023-07-01 20:15:00\nLocation: 50.1,-5.2 (Penzance, United Kingdom)" +
+			"\nTags: lighthouse, sunset\nViews: 321\nLicense: CC BY 2.0\nImage: https://live.staticflickr.com/large.jpg (1024×683)",
+		"EXIF for Lighthouse at dusk:\nCamera: Canon EOS R5\nExposure: 1/250\nAperture: f/8.0",
+	}
+	if len(data) != len(wantData) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range wantData {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+}
+
+func TestMethodFailure(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.APIKey = "key"
+	if es.CheckAvailability() {
+		t.Error("CheckAvailability succeeded for a failed method")
+	}
+}
@@ -0,0 +1,302 @@
+package gdelt
+
+// Data Source Adapter for the GDELT DOC 2.0 global news API
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultTopicCount = 5
+	maxRecords        = 250
+)
+
+type DataSourceGDELT struct {
+	Client        *http.Client
+	BaseURL       string
+	UserAgent     string
+	Timespan      string   // Search window, e.g. "24h", "1w", "3m"; GDELT covers roughly the last three months
+	SourceCountry []string // FIPS country names or codes, e.g. "US", "france"
+	SourceLang    []string // e.g. "english", "spanish"
+	Domains       []string // Restrict to publisher domains
+	MinTone       *float64 // Only articles at least this positive; GDELT tone runs roughly -10 to +10
+	MaxTone       *float64 // Only articles at most this positive, e.g. -5 for strongly negative coverage
+	Sort          string   // "hybridrel" (default), "datedesc", "tonedesc" or "toneasc"
+	FetchPages    bool     // Load each article page in FetchData for its description and lead paragraphs
+
+	articles    topicid.Map[article]
+	rateLimiter *rate.Limiter
+}
+
+type article struct {
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	SeenDate      string `json:"seendate"`
+	Domain        string `json:"domain"`
+	Language      string `json:"language"`
+	SourceCountry string `json:"sourcecountry"`
+}
+
+func New() *DataSourceGDELT {
+	return &DataSourceGDELT{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:    "https://api.gdeltproject.org/api/v2/doc/doc",
+		UserAgent:  "locus/gdelt-datasource",
+		Timespan:   "1w",
+		Sort:       "hybridrel",
+		FetchPages: true,
+	}
+}
+
+// Init implements models.DataSource
+// GDELT needs no key but asks clients to keep to one request every five seconds
+func (es *DataSourceGDELT) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.rateLimiter == nil {
+		es.rateLimiter = rate.NewLimiter(rate.Every(5*time.Second), 1)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceGDELT) CheckAvailability() bool {
+	es.Init()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.search(ctx, "news", 1)
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceGDELT) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "The GDELT Project",
+		Homepage:            "https://www.gdeltproject.org",
+		Attribution:         "The GDELT Project (https://www.gdeltproject.org)",
+		AttributionRequired: true,
+		TermsURL:            "https://www.gdeltproject.org/about.html#termsofuse",
+	}
+}
+
+// Describe implements source.Describer
+func (es *DataSourceGDELT) Describe() source.Capabilities {
+	return source.Capabilities{Name: es.Info().Name, Data: true, RateLimit: source.RateLimit{Requests: 1, Per: 5 * time.Second}}
+}
+
+// FetchTopics implements models.DataSource
+// Each article is a topic titled "Headline (domain, country, date)"
+func (es *DataSourceGDELT) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for GDELT DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if count > maxRecords {
+		count = maxRecords
+	}
+	es.Init()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	articles, err := es.search(ctx, query, count)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(articles))
+	for _, item := range articles {
+		details := []string{item.Domain}
+		if item.SourceCountry != "" {
+			details = append(details, item.SourceCountry)
+		}
+		if seen, err := time.Parse("20060102T150405Z", item.SeenDate); err == nil {
+			details = append(details, seen.Format("2006-01-02"))
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s (%s)", item.Title, strings.Join(details, ", ")),
+			SourceURL: item.URL,
+			Site:      item.Domain,
+			TopicID:   es.articles.Put(item.URL, item),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// GDELT indexes articles without their text, so the headline and metadata are returned, followed by
+// the page description and lead paragraphs when FetchPages is set
+func (es *DataSourceGDELT) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	item, ok := es.articles.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown GDELT topicID %d", topicID)
+	}
+
+	parts := []string{item.Title}
+	if es.FetchPages {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+		defer cancel()
+		// Publishers block or time out often enough that the metadata alone is still worth returning
+		if lead, err := es.fetchLead(ctx, item.URL); err == nil && lead != "" {
+			parts = append(parts, lead)
+		}
+	}
+	parts = append(parts, fmt.Sprintf("(%s, %s, %s, seen %s)", item.Domain, item.SourceCountry, item.Language, item.SeenDate))
+
+	return []datasource.DataSourceData{{
+		DataText:  strings.Join(parts, "\n\n"),
+		SourceURL: item.URL,
+		Site:      item.Domain,
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// search runs an artlist query with the configured filters appended as GDELT query operators
+func (es *DataSourceGDELT) search(ctx context.Context, query string, count int) ([]article, error) {
+	terms := []string{query}
+	terms = appendAlternatives(terms, "sourcecountry", es.SourceCountry)
+	terms = appendAlternatives(terms, "sourcelang", es.SourceLang)
+	terms = appendAlternatives(terms, "domain", es.Domains)
+	if es.MinTone != nil {
+		terms = append(terms, "tone>"+strconv.FormatFloat(*es.MinTone, 'f', -1, 64))
+	}
+	if es.MaxTone != nil {
+		terms = append(terms, "tone<"+strconv.FormatFloat(*es.MaxTone, 'f', -1, 64))
+	}
+
+	params := url.Values{}
+	params.Set("query", strings.Join(terms, " "))
+	params.Set("mode", "artlist")
+	params.Set("format", "json")
+	params.Set("maxrecords", strconv.Itoa(count))
+	if es.Timespan != "" {
+		params.Set("timespan", es.Timespan)
+	}
+	if es.Sort != "" {
+		params.Set("sort", es.Sort)
+	}
+
+	body, err := es.get(ctx, es.BaseURL+"?"+params.Encode(), true)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	// Query errors ("keyword too short", "invalid operator") come back as plain text with status 200
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] != '{' {
+		return nil, fmt.Errorf("gdelt query rejected: %s", strings.TrimSpace(string(raw)))
+	}
+	var response struct {
+		Articles []article `json:"articles"`
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &response); err != nil {
+			return nil, err
+		}
+	}
+	return response.Articles, nil
+}
+
+// fetchLead loads an article page and returns its description and first paragraphs
+func (es *DataSourceGDELT) fetchLead(ctx context.Context, pageURL string) (string, error) {
+	body, err := es.get(ctx, pageURL, false)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(body, 2<<20))
+	if err != nil {
+		return "", err
+	}
+
+	var parts []string
+	description, _ := doc.Find(`meta[property="og:description"], meta[name="description"]`).First().Attr("content")
+	if description = strings.Join(strings.Fields(description), " "); description != "" {
+		parts = append(parts, description)
+	}
+	doc.Find("article p, main p").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		// Lead paragraphs are long; short ones are bylines, captions and share prompts
+		if text := strings.Join(strings.Fields(s.Text()), " "); len(text) > 80 && text != description {
+			parts = append(parts, text)
+		}
+		return len(parts) < 4
+	})
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// get performs an HTTP GET request, waiting for the rate limiter when it targets the GDELT API.
+// The caller must close the returned body.
+func (es *DataSourceGDELT) get(ctx context.Context, uri string, api bool) (io.ReadCloser, error) {
+	if api && es.rateLimiter != nil {
+		if err := es.rateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("gdelt request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// Helpers
+
+// appendAlternatives adds "op:a" for one value or "(op:a OR op:b)" for several
+func appendAlternatives(terms []string, operator string, values []string) []string {
+	if len(values) == 0 {
+		return terms
+	}
+	alternatives := make([]string, 0, len(values))
+	for _, value := range values {
+		alternatives = append(alternatives, operator+":"+value)
+	}
+	if len(alternatives) == 1 {
+		return append(terms, alternatives[0])
+	}
+	return append(terms, "("+strings.Join(alternatives, " OR ")+")")
+}
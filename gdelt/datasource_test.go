@@ -0,0 +1,83 @@
+package gdelt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+var lead = strings.Repeat("The summit ended with a joint statement on trade. ", 3)
+
+func newSource(t *testing.T) *DataSourceGDELT {
+	t.Helper()
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("mode") != "artlist" || q.Get("format") != "json" || q.Get("maxrecords") != "5" || q.Get("timespan") != "1w" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch q.Get("query") {
+		case "summit (sourcecountry:US OR sourcecountry:france) tone<-2":
+			fmt.Fprintf(w, `{"articles":[{"url":"%s/article","title":"Summit ends","seendate":"20240501T120000Z","domain":"news.example","language":"English","sourcecountry":"United States"}]}`, srv.URL)
+		case "a":
+			fmt.Fprint(w, "The specified phrase is too short.")
+		default:
+			t.Errorf("unexpected query %q", q.Get("query"))
+		}
+	})
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<html><head><meta property="og:description" content="Leaders  met in Paris."></head>
+			<body><article><p>By Staff</p><p>%s</p></article></body></html>`, lead)
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	es := New()
+	es.BaseURL = srv.URL + "/api"
+	es.rateLimiter = rate.NewLimiter(rate.Inf, 1)
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	es.SourceCountry = []string{"US", "france"}
+	maxTone := -2.0
+	es.MaxTone = &maxTone
+
+	topics, err := es.FetchTopics(5, "summit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Summit ends (news.example, United States, 2024-05-01)" || topics[0].Site != "news.example" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Summit ends\n\nLeaders met in Paris.\n\n" + strings.TrimSpace(lead) + "\n\n(news.example, United States, English, seen 20240501T120000Z)"
+	if len(data) != 1 || data[0].DataText != want {
+		t.Errorf("FetchData = %+v, want %q", data, want)
+	}
+}
+
+func TestQueryRejected(t *testing.T) {
+	es := newSource(t)
+	if _, err := es.FetchTopics(5, "a"); err == nil || !strings.Contains(err.Error(), "too short") {
+		t.Errorf("FetchTopics error = %v", err)
+	}
+}
+
+func TestAppendAlternatives(t *testing.T) {
+	terms := appendAlternatives([]string{"q"}, "domain", []string{"bbc.co.uk"})
+	terms = appendAlternatives(terms, "sourcelang", nil)
+	if strings.Join(terms, " ") != "q domain:bbc.co.uk" {
+		t.Errorf("appendAlternatives = %q", terms)
+	}
+}
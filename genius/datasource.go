@@ -0,0 +1,324 @@
+package genius
+
+// Data Source Adapter for Genius songs and annotations
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	// Referents are requested in one page; popular songs have a few dozen
+	referentPage = 50
+)
+
+type DataSourceGenius struct {
+	Client      *http.Client
+	BaseURL     string
+	AccessToken string // Client access token from the Genius API console
+	UserAgent   string
+
+	songs topicid.Map[song]
+}
+
+type artist struct {
+	Name string `json:"name"`
+}
+
+type song struct {
+	ID     int64
+	Title  string
+	Artist string
+	URL    string
+}
+
+func New() *DataSourceGenius {
+	return &DataSourceGenius{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://api.genius.com",
+		UserAgent: "locus/genius-datasource",
+	}
+}
+
+// Init implements models.DataSource
+// Requires a client access token
+func (es *DataSourceGenius) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.AccessToken == "" {
+		return errors.New("AccessToken is required for Genius DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceGenius) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", "hello")
+	params.Set("per_page", "1")
+	return es.doJSON(ctx, "/search", params, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceGenius) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "Genius",
+		Homepage:            "https://genius.com",
+		Attribution:         "Lyrics and annotations from Genius",
+		AttributionRequired: true,
+		TermsURL:            "https://genius.com/static/terms",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each song is a topic titled "Song by Artist (release date)"
+func (es *DataSourceGenius) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Genius DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("per_page", strconv.Itoa(count))
+	var response struct {
+		Response struct {
+			Hits []struct {
+				Type   string `json:"type"`
+				Result struct {
+					ID                    int64  `json:"id"`
+					FullTitle             string `json:"full_title"`
+					Title                 string `json:"title"`
+					URL                   string `json:"url"`
+					ReleaseDateForDisplay string `json:"release_date_for_display"`
+					PrimaryArtist         artist `json:"primary_artist"`
+				} `json:"result"`
+			} `json:"hits"`
+		} `json:"response"`
+	}
+	if err := es.doJSON(ctx, "/search", params, &response); err != nil {
+		return nil, err
+	}
+
+	hits := response.Response.Hits
+	results := make([]datasource.DataSourceTopic, 0, len(hits))
+	for _, hit := range hits {
+		if hit.Type != "song" {
+			continue
+		}
+		r := hit.Result
+		title := r.FullTitle
+		if r.ReleaseDateForDisplay != "" {
+			title += " (" + r.ReleaseDateForDisplay + ")"
+		}
+		s := song{ID: r.ID, Title: r.Title, Artist: r.PrimaryArtist.Name, URL: r.URL}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: r.URL,
+			Site:      r.PrimaryArtist.Name,
+			TopicID:   es.songs.Put(strconv.FormatInt(r.ID, 10), s),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the song's "About" description, then its most upvoted annotations, each quoting the lyric
+// fragment it explains. Full lyrics are not returned.
+func (es *DataSourceGenius) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	s, ok := es.songs.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Genius topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	params := url.Values{}
+	params.Set("text_format", "plain")
+	var songResponse struct {
+		Response struct {
+			Song struct {
+				Description struct {
+					Plain string `json:"plain"`
+				} `json:"description"`
+				ReleaseDateForDisplay string `json:"release_date_for_display"`
+				Album                 *struct {
+					Name string `json:"name"`
+				} `json:"album"`
+				WriterArtists   []artist `json:"writer_artists"`
+				ProducerArtists []artist `json:"producer_artists"`
+			} `json:"song"`
+		} `json:"response"`
+	}
+	if err := es.doJSON(ctx, fmt.Sprintf("/songs/%d", s.ID), params, &songResponse); err != nil {
+		return nil, err
+	}
+
+	details := songResponse.Response.Song
+	var about strings.Builder
+	fmt.Fprintf(&about, "%s by %s", s.Title, s.Artist)
+	if details.Album != nil && details.Album.Name != "" {
+		about.WriteString(", from " + details.Album.Name)
+	}
+	if details.ReleaseDateForDisplay != "" {
+		about.WriteString(" (" + details.ReleaseDateForDisplay + ")")
+	}
+	if names := artistNames(details.WriterArtists); names != "" {
+		about.WriteString("\nWritten by: " + names)
+	}
+	if names := artistNames(details.ProducerArtists); names != "" {
+		about.WriteString("\nProduced by: " + names)
+	}
+	// Songs without an About section have the placeholder "?"
+	if description := strings.TrimSpace(details.Description.Plain); description != "" && description != "?" {
+		about.WriteString("\n\n" + description)
+	}
+	results := []datasource.DataSourceData{{
+		DataText:  about.String(),
+		SourceURL: s.URL,
+		Site:      s.Artist,
+		AnswerID:  topicID,
+	}}
+	if count == 1 {
+		return results, nil
+	}
+
+	params = url.Values{}
+	params.Set("song_id", strconv.FormatInt(s.ID, 10))
+	params.Set("text_format", "plain")
+	params.Set("per_page", strconv.Itoa(referentPage))
+	var referentResponse struct {
+		Response struct {
+			Referents []struct {
+				ID          int64  `json:"id"`
+				Fragment    string `json:"fragment"`
+				URL         string `json:"url"`
+				Annotations []struct {
+					VotesTotal int `json:"votes_total"`
+					Body       struct {
+						Plain string `json:"plain"`
+					} `json:"body"`
+				} `json:"annotations"`
+			} `json:"referents"`
+		} `json:"response"`
+	}
+	if err := es.doJSON(ctx, "/referents", params, &referentResponse); err != nil {
+		return results, nil
+	}
+
+	type annotation struct {
+		id       int64
+		fragment string
+		text     string
+		url      string
+		votes    int
+	}
+	var annotations []annotation
+	for _, referent := range referentResponse.Response.Referents {
+		if len(referent.Annotations) == 0 {
+			continue
+		}
+		a := referent.Annotations[0]
+		if text := strings.TrimSpace(a.Body.Plain); text != "" {
+			annotations = append(annotations, annotation{id: referent.ID, fragment: referent.Fragment, text: text, url: referent.URL, votes: a.VotesTotal})
+		}
+	}
+	sort.SliceStable(annotations, func(i, j int) bool {
+		return annotations[i].votes > annotations[j].votes
+	})
+	for _, a := range annotations {
+		if len(results) >= count {
+			break
+		}
+		link := a.url
+		if link == "" {
+			link = s.URL
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  fmt.Sprintf("“%s”\n\n%s", strings.TrimSpace(a.fragment), a.text),
+			SourceURL: link,
+			Site:      s.Artist,
+			AnswerID:  a.id,
+		})
+	}
+	return results, nil
+}
+
+// doJSON performs an authenticated Genius API request and decodes the JSON response into target
+func (es *DataSourceGenius) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+es.AccessToken)
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("genius request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func artistNames(artists []artist) string {
+	names := make([]string, 0, len(artists))
+	for _, artist := range artists {
+		names = append(names, artist.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,88 @@
+package genius
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "bohemian" || q.Get("per_page") != "5" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, `{"meta":{"status":200},"response":{"hits":[
+			{"type":"song","result":{"id":1063,"full_title":"Bohemian Rhapsody by Queen","title":"Bohemian Rhapsody","url":"https://genius.com/Queen-bohemian-rhapsody-lyrics","release_date_for_display":"October 31, 1975","primary_artist":{"name":"Queen"}}},
+			{"type":"album","result":{"id":9}}
+		]}}`)
+	})
+	mux.HandleFunc("/songs/1063", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("text_format") != "plain" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"response":{"song":{"description":{"plain":"A six-minute suite."},"release_date_for_display":"October 31, 1975",
+			"album":{"name":"A Night at the Opera"},"writer_artists":[{"name":"Freddie Mercury"}],"producer_artists":[{"name":"Roy Thomas Baker"},{"name":"Queen"}]}}}`)
+	})
+	mux.HandleFunc("/referents", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("song_id") != "1063" || q.Get("per_page") != "50" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"response":{"referents":[
+			{"id":1,"fragment":"Is this the real life?","url":"https://genius.com/1","annotations":[{"votes_total":10,"body":{"plain":"The opening question."}}]},
+			{"id":2,"fragment":"Scaramouche","annotations":[{"votes_total":90,"body":{"plain":"A stock clown character."}}]},
+			{"id":3,"fragment":"Unannotated","annotations":[]}
+		]}}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.AccessToken = "token"
+
+	topics, err := es.FetchTopics(5, "bohemian")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Bohemian Rhapsody by Queen (October 31, 1975)" || topics[0].Site != "Queen" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Bohemian Rhapsody by Queen, from A Night at the Opera (October 31, 1975)\nWritten by: Freddie Mercury\nProduced by: Roy Thomas Baker, Queen\n\nA six-minute suite.",
+		"“Scaramouche”\n\nA stock clown character.",
+		"“Is this the real life?”\n\nThe opening question.",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+	if data[1].SourceURL != topics[0].SourceURL || data[2].SourceURL != "https://genius.com/1" {
+		t.Errorf("annotation links = %q, %q", data[1].SourceURL, data[2].SourceURL)
+	}
+}
+
+func TestInitRequiresToken(t *testing.T) {
+	if err := New().Init(); err == nil {
+		t.Error("Init succeeded without an access token")
+	}
+}
@@ -0,0 +1,309 @@
+package gitlab
+
+// Data Source Adapter for GitLab (gitlab.com or self-hosted) search
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceGitLab struct {
+	Client    *http.Client
+	BaseURL   string // Instance root, e.g. https://gitlab.com or https://gitlab.example.com
+	UserAgent string
+	Token     string   // Personal or project access token with read_api scope; required for issue and MR search
+	Group     string   // Optional group ID or full path to scope searches to
+	Scopes    []string // Any of "projects", "issues" and "merge_requests"
+
+	refs topicid.Map[ref]
+}
+
+// ref identifies the project, issue or merge request behind a topic
+type ref struct {
+	Scope       string
+	ProjectID   int64
+	IID         int64
+	URL         string
+	Site        string
+	Description string
+}
+
+func New() *DataSourceGitLab {
+	return &DataSourceGitLab{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://gitlab.com",
+		UserAgent: "locus/gitlab-datasource",
+		Scopes:    []string{"projects", "issues", "merge_requests"},
+	}
+}
+
+// Init implements models.DataSource
+// Validates the configured search scopes
+func (es *DataSourceGitLab) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if strings.TrimSpace(es.BaseURL) == "" {
+		return errors.New("BaseURL is required for GitLab DataSource")
+	}
+	for _, scope := range es.Scopes {
+		switch scope {
+		case "projects", "issues", "merge_requests":
+		default:
+			return fmt.Errorf("unsupported GitLab search scope %q", scope)
+		}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceGitLab) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("per_page", "1")
+	return es.doJSON(ctx, "/api/v4/projects", params, &[]struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceGitLab) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "GitLab",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Searches every configured scope and interleaves the results; the project path is reported as the Site
+func (es *DataSourceGitLab) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for GitLab DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	path := "/api/v4/search"
+	if es.Group != "" {
+		path = "/api/v4/groups/" + url.PathEscape(es.Group) + "/search"
+	}
+
+	perScope := make([][]datasource.DataSourceTopic, 0, len(es.Scopes))
+	var firstErr error
+	for _, scope := range es.Scopes {
+		params := url.Values{}
+		params.Set("scope", scope)
+		params.Set("search", query)
+		params.Set("per_page", strconv.Itoa(count))
+		var hits []struct {
+			ID                int64  `json:"id"`
+			IID               int64  `json:"iid"`
+			ProjectID         int64  `json:"project_id"`
+			Title             string `json:"title"`
+			Name              string `json:"name_with_namespace"`
+			PathWithNamespace string `json:"path_with_namespace"`
+			Description       string `json:"description"`
+			WebURL            string `json:"web_url"`
+			References        struct {
+				Full string `json:"full"`
+			} `json:"references"`
+		}
+		if err := es.doJSON(ctx, path, params, &hits); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		topics := make([]datasource.DataSourceTopic, 0, len(hits))
+		for _, hit := range hits {
+			item := ref{Scope: scope, URL: hit.WebURL, Description: hit.Description}
+			title := hit.Title
+			if scope == "projects" {
+				item.ProjectID = hit.ID
+				item.Site = hit.PathWithNamespace
+				title = hit.Name
+			} else {
+				item.ProjectID = hit.ProjectID
+				item.IID = hit.IID
+				item.Site = projectPath(hit.References.Full)
+			}
+			topics = append(topics, datasource.DataSourceTopic{
+				Topic:     title,
+				SourceURL: hit.WebURL,
+				Site:      item.Site,
+				TopicID:   es.refs.Put(fmt.Sprintf("%s:%d", scope, hit.ID), item),
+			})
+		}
+		perScope = append(perScope, topics)
+	}
+	if len(perScope) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for i := 0; len(results) < count; i++ {
+		added := false
+		for _, topics := range perScope {
+			if i < len(topics) && len(results) < count {
+				results = append(results, topics[i])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Projects return their description; issues and merge requests return their description
+// followed by discussion threads, one data item per thread
+func (es *DataSourceGitLab) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	item, ok := es.refs.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown GitLab topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	if description := strings.TrimSpace(item.Description); description != "" {
+		results = append(results, datasource.DataSourceData{
+			DataText:  description,
+			SourceURL: item.URL,
+			Site:      item.Site,
+			AnswerID:  topicID,
+		})
+	}
+	if item.Scope == "projects" {
+		return results, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	params := url.Values{}
+	params.Set("per_page", strconv.Itoa(count*2))
+	path := fmt.Sprintf("/api/v4/projects/%d/%s/%d/discussions", item.ProjectID, item.Scope, item.IID)
+	var discussions []struct {
+		ID    string `json:"id"`
+		Notes []struct {
+			ID     int64  `json:"id"`
+			Body   string `json:"body"`
+			System bool   `json:"system"`
+			Author struct {
+				Username string `json:"username"`
+			} `json:"author"`
+		} `json:"notes"`
+	}
+	if err := es.doJSON(ctx, path, params, &discussions); err != nil {
+		return nil, err
+	}
+
+	for _, discussion := range discussions {
+		if len(results) >= count {
+			break
+		}
+		var b strings.Builder
+		var firstNote int64
+		for _, note := range discussion.Notes {
+			// System notes record label changes, assignments and similar events
+			if note.System || strings.TrimSpace(note.Body) == "" {
+				continue
+			}
+			if firstNote == 0 {
+				firstNote = note.ID
+			} else {
+				b.WriteString("\n\n")
+			}
+			fmt.Fprintf(&b, "%s:\n%s", note.Author.Username, strings.TrimSpace(note.Body))
+		}
+		if firstNote == 0 {
+			continue
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  b.String(),
+			SourceURL: fmt.Sprintf("%s#note_%d", item.URL, firstNote),
+			Site:      item.Site,
+			AnswerID:  firstNote,
+		})
+	}
+	return results, nil
+}
+
+// doJSON performs an HTTP GET request against the GitLab REST API and decodes the JSON response into target
+func (es *DataSourceGitLab) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	if es.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", es.Token)
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("gitlab request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+
+// projectPath extracts "group/project" from a full reference such as "group/project#12" or "group/project!34"
+func projectPath(reference string) string {
+	if i := strings.IndexAny(reference, "#!"); i >= 0 {
+		return reference[:i]
+	}
+	return reference
+}
@@ -0,0 +1,103 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/groups/my-group/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("search") != "runner" || q.Get("per_page") != "3" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch q.Get("scope") {
+		case "projects":
+			fmt.Fprint(w, `[
+				{"id":1,"name_with_namespace":"My Group / Runner","path_with_namespace":"my-group/runner","description":"CI runner","web_url":"https://gitlab.example/my-group/runner"},
+				{"id":2,"name_with_namespace":"My Group / Runner Docs","path_with_namespace":"my-group/runner-docs","web_url":"https://gitlab.example/my-group/runner-docs"}
+			]`)
+		case "issues":
+			fmt.Fprint(w, `[{"id":10,"iid":4,"project_id":1,"title":"Runner hangs","description":"It hangs.","web_url":"https://gitlab.example/my-group/runner/-/issues/4","references":{"full":"my-group/runner#4"}}]`)
+		default:
+			http.Error(w, "scope not allowed", http.StatusForbidden)
+		}
+	})
+	mux.HandleFunc("/api/v4/projects/1/issues/4/discussions", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id":"a","notes":[{"id":100,"body":"added ~bug label","system":true}]},
+			{"id":"b","notes":[
+				{"id":101,"body":"Same here","author":{"username":"alice"}},
+				{"id":102,"body":"Fixed in main","author":{"username":"bob"}}
+			]}
+		]`)
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "token" {
+			t.Errorf("PRIVATE-TOKEN = %q", r.Header.Get("PRIVATE-TOKEN"))
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsInterleavesScopes(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.Token = "token"
+	es.Group = "my-group"
+
+	topics, err := es.FetchTopics(3, "runner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"My Group / Runner", "Runner hangs", "My Group / Runner Docs"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+	if topics[1].Site != "my-group/runner" {
+		t.Errorf("issue Site = %q", topics[1].Site)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil || len(data) != 1 || data[0].DataText != "CI runner" {
+		t.Errorf("project FetchData = %+v, %v", data, err)
+	}
+
+	data, err = es.FetchData(5, topics[1].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 || data[0].DataText != "It hangs." || data[1].DataText != "alice:\nSame here\n\nbob:\nFixed in main" {
+		t.Fatalf("issue FetchData = %+v", data)
+	}
+	if data[1].AnswerID != 101 || data[1].SourceURL != "https://gitlab.example/my-group/runner/-/issues/4#note_101" {
+		t.Errorf("thread = %+v", data[1])
+	}
+}
+
+func TestInitRejectsUnknownScope(t *testing.T) {
+	es := New()
+	es.Scopes = []string{"wiki_blobs"}
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted an unsupported scope")
+	}
+}
+
+func TestProjectPath(t *testing.T) {
+	for in, want := range map[string]string{"group/project#12": "group/project", "a/b/c!34": "a/b/c", "group/project": "group/project"} {
+		if got := projectPath(in); got != want {
+			t.Errorf("projectPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
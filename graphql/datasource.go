@@ -0,0 +1,316 @@
+package graphql
+
+// Data Source Adapter for any GraphQL API, configured with query documents and JSON path mappings
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/jsonpath"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+// Field maps a JSON path, relative to an item, to a line of data text
+type Field struct {
+	Label string `json:"label"` // Written as "Label: value"; empty writes the value alone
+	Path  string `json:"path"`
+}
+
+// Config describes a GraphQL source. It can be written in Go or loaded from a JSON file with LoadConfig;
+// "${VAR}" references in the token and header values are expanded from the environment when loaded.
+type Config struct {
+	Endpoint    string            `json:"endpoint"`
+	BearerToken string            `json:"bearer_token"`
+	Headers     map[string]string `json:"headers"`
+
+	// SearchQuery is the GraphQL document run by FetchTopics. The input and topic count are passed as
+	// the variables named by QueryVariable and CountVariable; Variables adds fixed ones.
+	SearchQuery   string                 `json:"search_query"`
+	QueryVariable string                 `json:"query_variable"`
+	CountVariable string                 `json:"count_variable"` // Empty to not send a count
+	Variables     map[string]interface{} `json:"variables"`
+
+	// Items selects the result list in the search response, e.g. "data.search.nodes[*]". The
+	// remaining paths are relative to one item.
+	Items  string `json:"items"`
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Detail string `json:"detail"` // Optional; appended to the title in parentheses
+	Site   string `json:"site"`   // Fixed Site for topics and data
+
+	// DataQuery optionally fetches the full item for FetchData, receiving the item ID in the variable
+	// named by IDVariable; DataItem selects the item in its response. Without it, FetchData reads the
+	// fields from the search result.
+	DataQuery  string  `json:"data_query"`
+	IDVariable string  `json:"id_variable"`
+	DataItem   string  `json:"data_item"`
+	Data       []Field `json:"data"`
+}
+
+type DataSourceGraphQL struct {
+	Config
+	Client    *http.Client
+	UserAgent string
+
+	items topicid.Map[item]
+}
+
+type item struct {
+	ID    string
+	Title string
+	URL   string
+	Value interface{} // The decoded search result item
+}
+
+func New() *DataSourceGraphQL {
+	return &DataSourceGraphQL{
+		Config: Config{
+			QueryVariable: "query",
+			CountVariable: "first",
+			IDVariable:    "id",
+		},
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		UserAgent: "locus/graphql-datasource",
+	}
+}
+
+// LoadConfig returns an adapter configured from a JSON file holding a Config
+func LoadConfig(path string) (*DataSourceGraphQL, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	es := New()
+	if err := json.Unmarshal(raw, &es.Config); err != nil {
+		return nil, fmt.Errorf("graphql config %s: %w", path, err)
+	}
+	es.BearerToken = os.ExpandEnv(es.BearerToken)
+	for key, value := range es.Headers {
+		es.Headers[key] = os.ExpandEnv(value)
+	}
+	return es, es.Init()
+}
+
+// Init implements models.DataSource
+// Validates the configuration and its JSON paths
+func (es *DataSourceGraphQL) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.Endpoint == "" || es.SearchQuery == "" {
+		return errors.New("Endpoint and SearchQuery are required for GraphQL DataSource")
+	}
+	if es.Items == "" || es.ID == "" || es.Title == "" {
+		return errors.New("Items, ID and Title paths are required for GraphQL DataSource")
+	}
+	paths := []string{es.Items, es.ID, es.Title, es.URL, es.Detail, es.DataItem}
+	for _, field := range es.Data {
+		paths = append(paths, field.Path)
+	}
+	for _, path := range paths {
+		if _, err := jsonpath.Compile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Sends an introspection query for the schema's query type name
+func (es *DataSourceGraphQL) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.Execute(ctx, "{ __schema { queryType { name } } }", nil)
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceGraphQL) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "GraphQL",
+		Homepage: es.Endpoint,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each item selected by Items is a topic titled by the Title path and, when set, "(Detail)"
+func (es *DataSourceGraphQL) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for GraphQL DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	variables := map[string]interface{}{}
+	for key, value := range es.Variables {
+		variables[key] = value
+	}
+	variables[es.QueryVariable] = query
+	if es.CountVariable != "" {
+		variables[es.CountVariable] = count
+	}
+	doc, err := es.Execute(ctx, es.SearchQuery, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, value := range jsonpath.MustCompile(es.Items).Select(doc) {
+		if len(results) >= count {
+			break
+		}
+		i := item{
+			ID:    text(value, es.ID),
+			Title: text(value, es.Title),
+			URL:   text(value, es.URL),
+			Value: value,
+		}
+		if i.ID == "" {
+			continue
+		}
+		title := i.Title
+		if detail := text(value, es.Detail); detail != "" {
+			title += " (" + detail + ")"
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: i.URL,
+			Site:      es.Site,
+			TopicID:   es.items.Put(es.Endpoint+"#"+i.ID, i),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns one item: the title followed by a line per configured Data field that has a value
+func (es *DataSourceGraphQL) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	i, ok := es.items.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown GraphQL topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	value := i.Value
+	if es.DataQuery != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+		defer cancel()
+		doc, err := es.Execute(ctx, es.DataQuery, map[string]interface{}{es.IDVariable: i.ID})
+		if err != nil {
+			return nil, err
+		}
+		found, ok := jsonpath.MustCompile(es.DataItem).First(doc)
+		if !ok || found == nil {
+			return nil, fmt.Errorf("graphql item %q not found", i.ID)
+		}
+		value = found
+	}
+
+	lines := []string{i.Title}
+	for _, field := range es.Data {
+		v := text(value, field.Path)
+		if v == "" {
+			continue
+		}
+		if field.Label != "" {
+			v = field.Label + ": " + v
+		}
+		lines = append(lines, v)
+	}
+	return []datasource.DataSourceData{{
+		DataText:  strings.Join(lines, "\n"),
+		SourceURL: i.URL,
+		Site:      es.Site,
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// Execute sends a GraphQL request and returns the decoded response document (with its "data" member).
+// Errors reported alongside data are tolerated; errors without data fail the request.
+func (es *DataSourceGraphQL) Execute(ctx context.Context, query string, variables map[string]interface{}) (interface{}, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	payload, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, es.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if es.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+es.BearerToken)
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	for key, value := range es.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("graphql request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var doc map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if errs, ok := doc["errors"].([]interface{}); ok && len(errs) > 0 && doc["data"] == nil {
+		messages := make([]string, 0, len(errs))
+		for _, e := range errs {
+			messages = append(messages, jsonpath.MustCompile("message").Text(e))
+		}
+		return nil, fmt.Errorf("graphql query failed: %s", strings.Join(messages, "; "))
+	}
+	return doc, nil
+}
+
+// Helpers
+
+// text evaluates an already validated path against value; an empty path yields ""
+func text(value interface{}, path string) string {
+	if path == "" {
+		return ""
+	}
+	return strings.TrimSpace(jsonpath.MustCompile(path).Text(value))
+}
@@ -0,0 +1,122 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("X-Tenant") != "acme" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		var request struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case strings.HasPrefix(request.Query, "query Search"):
+			v := request.Variables
+			if v["q"] != "gopher" || v["first"] != float64(5) || v["type"] != "REPOSITORY" {
+				t.Errorf("unexpected variables %v", v)
+			}
+			fmt.Fprint(w, `{"data":{"search":{"nodes":[
+				{"id":"R1","name":"gopher","url":"https://example.com/gopher","description":" A mascot "},
+				{"name":"no id"}
+			]}}}`)
+		case strings.HasPrefix(request.Query, "query Repo"):
+			if request.Variables["id"] != "R1" {
+				t.Errorf("unexpected variables %v", request.Variables)
+			}
+			fmt.Fprint(w, `{"data":{"node":{"stars":42,"topics":["go","mascot"],"license":null}},"errors":[{"message":"license: not authorised"}]}`)
+		default:
+			fmt.Fprint(w, `{"data":null,"errors":[{"message":"syntax error"},{"message":"unknown field"}]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+const config = `{
+	"endpoint": %q,
+	"bearer_token": "${GRAPHQL_TEST_TOKEN}",
+	"headers": {"X-Tenant": "acme"},
+	"search_query": "query Search($q: String!, $first: Int) { search(query: $q, first: $first) { nodes { id name url description } } }",
+	"query_variable": "q",
+	"variables": {"type": "REPOSITORY"},
+	"items": "data.search.nodes[*]",
+	"id": "id",
+	"title": "name",
+	"url": "url",
+	"detail": "description",
+	"site": "example.com",
+	"data_query": "query Repo($id: ID!) { node(id: $id) { stars topics license } }",
+	"data_item": "data.node",
+	"data": [{"label": "Stars", "path": "stars"}, {"path": "topics"}, {"label": "License", "path": "license"}]
+}`
+
+func newSource(t *testing.T) *DataSourceGraphQL {
+	t.Helper()
+	srv := newServer(t)
+	path := filepath.Join(t.TempDir(), "graphql.json")
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(config, srv.URL)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GRAPHQL_TEST_TOKEN", "token")
+	es, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "gopher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "gopher (A mascot)" || topics[0].SourceURL != "https://example.com/gopher" || topics[0].Site != "example.com" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "gopher\nStars: 42\ngo, mascot"
+	if len(data) != 1 || data[0].DataText != want {
+		t.Errorf("FetchData = %+v, want %q", data, want)
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	es := newSource(t)
+	_, err := es.Execute(context.Background(), "{ broken", nil)
+	if err == nil || err.Error() != "graphql query failed: syntax error; unknown field" {
+		t.Errorf("Execute = %v", err)
+	}
+}
+
+func TestInitRejectsBadPath(t *testing.T) {
+	es := New()
+	es.Endpoint = "http://127.0.0.1:0"
+	es.SearchQuery = "{ search }"
+	es.Items = "data.items[0"
+	es.ID = "id"
+	es.Title = "title"
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted a malformed Items path")
+	}
+}
@@ -0,0 +1,183 @@
+// Package guard isolates the host process from faulty adapters. Guard wraps a
+// source, turns panics into errors instead of crashes, annotates every error
+// with the source name and operation, and counts failures in expvar, so one
+// buggy scraper or third-party plugin can't take the host down and its
+// failures are visible on /debug/vars.
+package guard
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"runtime/debug"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+// Operations, for Error.Op
+const (
+	OpInit              = "Init"
+	OpCheckAvailability = "CheckAvailability"
+	OpFetchTopics       = "FetchTopics"
+	OpFetchData         = "FetchData"
+)
+
+// ErrPanic matches errors from calls that panicked
+var ErrPanic = errors.New("adapter panicked")
+
+// Failure counters by source name, published with expvar
+var (
+	Failures = expvar.NewMap("datasource_failures") // Calls that returned an error or panicked
+	Panics   = expvar.NewMap("datasource_panics")   // Calls that panicked
+)
+
+// Error is a failed call to a guarded source
+type Error struct {
+	Source string
+	Op     string
+	Err    error  // What the call returned; nil for a panic
+	Panic  any    // The recovered value when the call panicked
+	Stack  []byte // Stack trace of the panic
+}
+
+func (e *Error) Error() string {
+	if e.Panic != nil {
+		return fmt.Sprintf("%s %s: panic: %v", e.Source, e.Op, e.Panic)
+	}
+	return fmt.Sprintf("%s %s: %v", e.Source, e.Op, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports ErrPanic for panics
+func (e *Error) Is(target error) bool {
+	return target == ErrPanic && e.Panic != nil
+}
+
+// Guard wraps a source and recovers its panics. Errors are returned as *Error. It implements
+// source.ContextSource.
+type Guard struct {
+	Source source.Source
+	Name   string
+	// OnFailure, when set, is called with every failure, e.g. to log panics with their stacks
+	OnFailure func(err *Error)
+}
+
+// New guards s under name
+func New(name string, s source.Source) *Guard {
+	return &Guard{Source: s, Name: name}
+}
+
+// Init implements models.DataSource
+func (g *Guard) Init() (err error) {
+	defer g.catch(OpInit, &err)
+	return g.wrap(OpInit, g.Source.Init())
+}
+
+// CheckAvailability implements models.DataSource
+// A source that panics is unavailable
+func (g *Guard) CheckAvailability() (available bool) {
+	var err error
+	defer func() {
+		if err != nil {
+			available = false
+		}
+	}()
+	defer g.catch(OpCheckAvailability, &err)
+	return g.Source.CheckAvailability()
+}
+
+// Unwrap implements source.Wrapper
+func (g *Guard) Unwrap() source.Source {
+	return g.Source
+}
+
+// FetchTopics implements models.DataSource
+func (g *Guard) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return g.FetchTopicsContext(context.Background(), count, input)
+}
+
+// FetchTopicsContext implements source.ContextSource
+func (g *Guard) FetchTopicsContext(ctx context.Context, count int, input string) (topics []datasource.DataSourceTopic, err error) {
+	defer g.catch(OpFetchTopics, &err)
+	topics, err = source.FetchTopicsContext(ctx, g.Source, count, input)
+	return topics, g.wrap(OpFetchTopics, err)
+}
+
+// FetchTopicsWithOptions implements source.OptionsSource
+func (g *Guard) FetchTopicsWithOptions(ctx context.Context, query string, opts source.TopicOptions) (topics []datasource.DataSourceTopic, err error) {
+	defer g.catch(OpFetchTopics, &err)
+	topics, err = source.FetchTopicsWithOptions(ctx, g.Source, query, opts)
+	return topics, g.wrap(OpFetchTopics, err)
+}
+
+// FetchTopicsPage implements source.Pager
+func (g *Guard) FetchTopicsPage(ctx context.Context, query string, opts source.TopicOptions, cursor string) (page source.Page, err error) {
+	defer g.catch(OpFetchTopics, &err)
+	page, err = source.FetchTopicsPage(ctx, g.Source, query, opts, cursor)
+	return page, g.wrap(OpFetchTopics, err)
+}
+
+// FetchData implements models.DataSource
+func (g *Guard) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return g.FetchDataContext(context.Background(), count, topicID)
+}
+
+// FetchDataContext implements source.ContextSource
+func (g *Guard) FetchDataContext(ctx context.Context, count int, topicID int64) (data []datasource.DataSourceData, err error) {
+	defer g.catch(OpFetchData, &err)
+	data, err = source.FetchDataContext(ctx, g.Source, count, topicID)
+	return data, g.wrap(OpFetchData, err)
+}
+
+// FetchDataBatch implements source.Batcher
+func (g *Guard) FetchDataBatch(ctx context.Context, count int, topicIDs []int64) (data map[int64][]datasource.DataSourceData, err error) {
+	defer g.catch(OpFetchData, &err)
+	data, err = source.FetchDataBatch(ctx, g.Source, count, topicIDs)
+	return data, g.wrap(OpFetchData, err)
+}
+
+// wrap annotates err with the source and operation and counts it; errors already annotated by an inner
+// Guard are kept as they are
+func (g *Guard) wrap(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	e = &Error{Source: g.name(), Op: op, Err: err}
+	g.fail(e)
+	return e
+}
+
+// catch recovers a panic into an *Error stored in err; deferred by every method
+func (g *Guard) catch(op string, err *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	e := &Error{Source: g.name(), Op: op, Panic: v, Stack: debug.Stack()}
+	Panics.Add(e.Source, 1)
+	g.fail(e)
+	*err = e
+}
+
+func (g *Guard) fail(e *Error) {
+	Failures.Add(g.name(), 1)
+	if g.OnFailure != nil {
+		g.OnFailure(e)
+	}
+}
+
+func (g *Guard) name() string {
+	if g.Name == "" {
+		return "source"
+	}
+	return g.Name
+}
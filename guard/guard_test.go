@@ -0,0 +1,94 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"testing"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// broken is a source that panics on every fetch and fails Init
+type broken struct{}
+
+var errInit = errors.New("missing API key")
+
+func (broken) Init() error             { return errInit }
+func (broken) CheckAvailability() bool { panic("nil client") }
+
+func (broken) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	panic("index out of range")
+}
+
+func (broken) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	var m map[int64][]datasource.DataSourceData
+	m[topicID] = nil // Assignment to a nil map
+	return nil, nil
+}
+
+// counter returns the value of a counter in m, 0 when unset
+func counter(m *expvar.Map, name string) int64 {
+	if v, ok := m.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestPanic(t *testing.T) {
+	const name = "panic-test"
+	var reported []*Error
+	g := New(name, broken{})
+	g.OnFailure = func(err *Error) { reported = append(reported, err) }
+	failures, panics := counter(Failures, name), counter(Panics, name)
+
+	_, err := g.FetchData(1, 7)
+	if !errors.Is(err, ErrPanic) {
+		t.Fatalf("FetchData = %v, want ErrPanic", err)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Source != name || e.Op != OpFetchData || e.Panic == nil || len(e.Stack) == 0 {
+		t.Errorf("FetchData error = %+v", e)
+	}
+	if _, err := g.FetchTopicsContext(context.Background(), 1, "query"); !errors.Is(err, ErrPanic) {
+		t.Errorf("FetchTopicsContext = %v, want ErrPanic", err)
+	}
+	if g.CheckAvailability() {
+		t.Error("CheckAvailability of a panicking source = true")
+	}
+
+	if got := counter(Panics, name) - panics; got != 3 {
+		t.Errorf("panics counted %d, want 3", got)
+	}
+	if got := counter(Failures, name) - failures; got != 3 {
+		t.Errorf("failures counted %d, want 3", got)
+	}
+	if len(reported) != 3 {
+		t.Errorf("OnFailure called %d times, want 3", len(reported))
+	}
+}
+
+func TestError(t *testing.T) {
+	const name = "error-test"
+	failures, panics := counter(Failures, name), counter(Panics, name)
+	// An inner guard's errors pass through the outer one unchanged and are counted once
+	g := New("outer", New(name, broken{}))
+
+	err := g.Init()
+	if !errors.Is(err, errInit) || errors.Is(err, ErrPanic) {
+		t.Errorf("Init = %v, want errInit", err)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Source != name || e.Op != OpInit {
+		t.Errorf("Init error = %+v", e)
+	}
+	if got := counter(Failures, name) - failures; got != 1 {
+		t.Errorf("failures counted %d, want 1", got)
+	}
+	if got := counter(Panics, name) - panics; got != 0 {
+		t.Errorf("panics counted %d, want 0", got)
+	}
+	if got := counter(Failures, "outer"); got != 0 {
+		t.Errorf("outer guard counted %d failures", got)
+	}
+}
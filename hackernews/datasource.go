@@ -0,0 +1,260 @@
+package hackernews
+
+// Data Source Adapter for Hacker News (Algolia search + Firebase item API)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceHackerNews struct {
+	Client     *http.Client
+	SearchURL  string // Algolia HN search API root
+	ItemURL    string // Firebase item API root
+	UserAgent  string
+	Tags       string        // Algolia tag filter, e.g. "story", "show_hn" or "ask_hn"
+	MinPoints  int           // Only return stories with at least this many points
+	MaxAge     time.Duration // Only return stories created within this window when non-zero
+	SortByDate bool          // Newest first instead of relevance
+}
+
+func New() *DataSourceHackerNews {
+	return &DataSourceHackerNews{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		SearchURL: "https://hn.algolia.com/api/v1",
+		ItemURL:   "https://hacker-news.firebaseio.com/v0",
+		UserAgent: "locus/hackernews-datasource",
+		Tags:      "story",
+	}
+}
+
+// Init implements models.DataSource
+// Hacker News requires no initialization
+func (es *DataSourceHackerNews) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceHackerNews) CheckAvailability() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	var id int64
+	return es.doJSON(ctx, strings.TrimRight(es.ItemURL, "/")+"/maxitem.json", &id) == nil && id > 0
+}
+
+// Info implements source.Informer
+func (es *DataSourceHackerNews) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Hacker News",
+		Homepage: "https://news.ycombinator.com",
+		TermsURL: "https://www.ycombinator.com/legal/",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Searches stories through Algolia, applying the configured tag, points and age filters
+func (es *DataSourceHackerNews) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Hacker News DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("hitsPerPage", strconv.Itoa(count))
+	if es.Tags != "" {
+		params.Set("tags", es.Tags)
+	}
+	var filters []string
+	if es.MinPoints > 0 {
+		filters = append(filters, fmt.Sprintf("points>=%d", es.MinPoints))
+	}
+	if es.MaxAge > 0 {
+		filters = append(filters, fmt.Sprintf("created_at_i>%d", time.Now().Add(-es.MaxAge).Unix()))
+	}
+	if len(filters) > 0 {
+		params.Set("numericFilters", strings.Join(filters, ","))
+	}
+	endpoint := "/search"
+	if es.SortByDate {
+		endpoint = "/search_by_date"
+	}
+
+	var response struct {
+		Hits []struct {
+			ObjectID string `json:"objectID"`
+			Title    string `json:"title"`
+			URL      string `json:"url"`
+		} `json:"hits"`
+	}
+	uri := strings.TrimRight(es.SearchURL, "/") + endpoint + "?" + params.Encode()
+	if err := es.doJSON(ctx, uri, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Hits))
+	for _, hit := range response.Hits {
+		id, err := strconv.ParseInt(hit.ObjectID, 10, 64)
+		if err != nil || strings.TrimSpace(hit.Title) == "" {
+			continue
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     hit.Title,
+			SourceURL: itemLink(id),
+			TopicID:   id,
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the story text (Ask HN and similar posts) followed by its top-ranked comments
+func (es *DataSourceHackerNews) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	story, err := es.fetchItem(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceData, 0, count+1)
+	if text := htmlToText(story.Text); text != "" {
+		results = append(results, datasource.DataSourceData{
+			DataText:  text,
+			SourceURL: itemLink(story.ID),
+			AnswerID:  story.ID,
+		})
+	}
+
+	// Kids are already in ranked order; fetch a few spares in case some are deleted
+	kids := story.Kids
+	if len(kids) > count+3 {
+		kids = kids[:count+3]
+	}
+	comments := make([]item, len(kids))
+	var wg sync.WaitGroup
+	for i, kid := range kids {
+		wg.Add(1)
+		go func(i int, kid int64) {
+			defer wg.Done()
+			if comment, err := es.fetchItem(ctx, kid); err == nil {
+				comments[i] = comment
+			}
+		}(i, kid)
+	}
+	wg.Wait()
+
+	for _, comment := range comments {
+		if len(results) >= count {
+			break
+		}
+		text := htmlToText(comment.Text)
+		if comment.ID == 0 || comment.Deleted || comment.Dead || text == "" {
+			continue
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  fmt.Sprintf("%s:\n%s", comment.By, text),
+			SourceURL: itemLink(comment.ID),
+			AnswerID:  comment.ID,
+		})
+	}
+	return results, nil
+}
+
+// item mirrors the Firebase item representation for stories and comments
+type item struct {
+	ID      int64   `json:"id"`
+	By      string  `json:"by"`
+	Text    string  `json:"text"`
+	Kids    []int64 `json:"kids"`
+	Deleted bool    `json:"deleted"`
+	Dead    bool    `json:"dead"`
+}
+
+func (es *DataSourceHackerNews) fetchItem(ctx context.Context, id int64) (item, error) {
+	var result item
+	uri := fmt.Sprintf("%s/item/%d.json", strings.TrimRight(es.ItemURL, "/"), id)
+	if err := es.doJSON(ctx, uri, &result); err != nil {
+		return item{}, err
+	}
+	if result.ID == 0 {
+		return item{}, fmt.Errorf("hacker news item %d not found", id)
+	}
+	return result, nil
+}
+
+// doJSON performs an HTTP GET request and decodes the JSON response into target
+func (es *DataSourceHackerNews) doJSON(ctx context.Context, uri string, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("hacker news request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func itemLink(id int64) string {
+	return fmt.Sprintf("https://news.ycombinator.com/item?id=%d", id)
+}
+
+// htmlToText flattens the small HTML subset HN uses (<p>, <a>, <i>, <pre>) into plain text
+func htmlToText(in string) string {
+	if strings.TrimSpace(in) == "" {
+		return ""
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(strings.ReplaceAll(in, "<p>", "\n\n")))
+	if err != nil {
+		return strings.TrimSpace(in)
+	}
+	return strings.TrimSpace(doc.Text())
+}
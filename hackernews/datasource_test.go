@@ -0,0 +1,80 @@
+package hackernews
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchTopics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/search_by_date" || q.Get("query") != "sqlite" || q.Get("tags") != "story" || q.Get("hitsPerPage") != "3" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if q.Get("numericFilters") != "points>=100" {
+			t.Errorf("numericFilters = %q", q.Get("numericFilters"))
+		}
+		fmt.Fprint(w, `{"hits":[
+			{"objectID":"123","title":"SQLite is not a toy database","url":"https://example.com/sqlite"},
+			{"objectID":"abc","title":"Not numeric"},
+			{"objectID":"124","title":" "}
+		]}`)
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.SearchURL = srv.URL
+	es.MinPoints = 100
+	es.SortByDate = true
+	topics, err := es.FetchTopics(3, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].TopicID != 123 || topics[0].Topic != "SQLite is not a toy database" ||
+		topics[0].SourceURL != "https://news.ycombinator.com/item?id=123" {
+		t.Errorf("FetchTopics = %+v", topics)
+	}
+}
+
+func TestFetchData(t *testing.T) {
+	items := map[string]string{
+		"/item/1.json": `{"id":1,"by":"pg","text":"Ask HN: <i>what</i> do you use?<p>Second paragraph","kids":[2,3,4,5]}`,
+		"/item/2.json": `{"id":2,"by":"alice","text":"SQLite &amp; Postgres"}`,
+		"/item/3.json": `{"id":3,"deleted":true}`,
+		"/item/4.json": `{"id":4,"by":"bob","text":"Flat files","dead":true}`,
+		"/item/5.json": `{"id":5,"by":"carol","text":"<a href=\"https://example.com\">example.com</a>"}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := items[r.URL.Path]
+		if !ok {
+			body = "null"
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.ItemURL = srv.URL
+	data, err := es.FetchData(5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Ask HN: what do you use?\n\nSecond paragraph", "alice:\nSQLite & Postgres", "carol:\nexample.com"}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+	if data[1].AnswerID != 2 || data[1].SourceURL != "https://news.ycombinator.com/item?id=2" {
+		t.Errorf("comment = %+v", data[1])
+	}
+
+	if _, err := es.FetchData(5, 99); err == nil {
+		t.Error("FetchData succeeded for an item that does not exist")
+	}
+}
@@ -0,0 +1,408 @@
+package igdb
+
+// Data Source Adapter for IGDB video game metadata, with Steam user review summaries
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/auth"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultTopicCount = 5
+	// steamSource is the IGDB external game source (and legacy category) for Steam
+	steamSource = 1
+	reviewSize  = 3
+	reviewBytes = 1500
+)
+
+// gameFields is the Apicalypse field list requested for every game
+const gameFields = "name,summary,storyline,url,first_release_date,total_rating,total_rating_count," +
+	"genres.name,platforms.name,themes.name,game_modes.name," +
+	"involved_companies.company.name,involved_companies.developer,involved_companies.publisher," +
+	"external_games.uid,external_games.category,external_games.external_game_source"
+
+type DataSourceIGDB struct {
+	Client       *http.Client
+	BaseURL      string
+	AuthURL      string
+	SteamURL     string
+	ClientID     string // Twitch application client ID
+	ClientSecret string
+	UserAgent    string
+	SteamReviews bool // Add Steam review summaries for games with a Steam release
+
+	credentials *auth.ClientCredentials
+	rateLimiter *rate.Limiter
+	games       topicid.Map[game]
+}
+
+type named struct {
+	Name string `json:"name"`
+}
+
+type game struct {
+	ID                int64   `json:"id"`
+	Name              string  `json:"name"`
+	Summary           string  `json:"summary"`
+	Storyline         string  `json:"storyline"`
+	URL               string  `json:"url"`
+	FirstReleaseDate  int64   `json:"first_release_date"`
+	TotalRating       float64 `json:"total_rating"`
+	TotalRatingCount  int     `json:"total_rating_count"`
+	Genres            []named `json:"genres"`
+	Platforms         []named `json:"platforms"`
+	Themes            []named `json:"themes"`
+	GameModes         []named `json:"game_modes"`
+	InvolvedCompanies []struct {
+		Company   named `json:"company"`
+		Developer bool  `json:"developer"`
+		Publisher bool  `json:"publisher"`
+	} `json:"involved_companies"`
+	ExternalGames []struct {
+		UID      string `json:"uid"`
+		Category int    `json:"category"`
+		Source   int    `json:"external_game_source"`
+	} `json:"external_games"`
+}
+
+func New() *DataSourceIGDB {
+	return &DataSourceIGDB{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:      "https://api.igdb.com/v4",
+		AuthURL:      "https://id.twitch.tv/oauth2/token",
+		SteamURL:     "https://store.steampowered.com",
+		UserAgent:    "locus/igdb-datasource",
+		SteamReviews: true,
+	}
+}
+
+// Init implements models.DataSource
+// Requires Twitch application credentials; IGDB allows 4 requests per second
+func (es *DataSourceIGDB) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.ClientID == "" || es.ClientSecret == "" {
+		return errors.New("ClientID and ClientSecret are required for IGDB DataSource")
+	}
+	if es.credentials == nil {
+		es.credentials = &auth.ClientCredentials{
+			Client:       es.Client,
+			TokenURL:     es.AuthURL,
+			ClientID:     es.ClientID,
+			ClientSecret: es.ClientSecret,
+			SendInBody:   true,
+		}
+	}
+	if es.rateLimiter == nil {
+		es.rateLimiter = rate.NewLimiter(rate.Limit(4), 1)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceIGDB) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	var games []game
+	return es.query(ctx, "/games", "fields name; limit 1;", &games) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceIGDB) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "IGDB",
+		Homepage:            "https://www.igdb.com",
+		Attribution:         "Data provided by IGDB.com",
+		AttributionRequired: true,
+		TermsURL:            "https://api-docs.igdb.com/#terms-of-use",
+	}
+}
+
+// Describe implements source.Describer
+func (es *DataSourceIGDB) Describe() source.Capabilities {
+	return source.Capabilities{Name: es.Info().Name, Data: true, RateLimit: source.RateLimit{Requests: 4, Per: time.Second}}
+}
+
+// FetchTopics implements models.DataSource
+// Topics read "Name (2017; Action, Adventure; Switch, Wii U)"
+func (es *DataSourceIGDB) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for IGDB DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	// Apicalypse strings cannot contain unescaped quotes
+	query = strings.NewReplacer(`\`, " ", `"`, " ").Replace(query)
+	body := fmt.Sprintf("search \"%s\"; fields %s; limit %d;", query, gameFields, count)
+	var games []game
+	if err := es.query(ctx, "/games", body, &games); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(games))
+	for _, g := range games {
+		var details []string
+		if g.FirstReleaseDate > 0 {
+			details = append(details, strconv.Itoa(time.Unix(g.FirstReleaseDate, 0).UTC().Year()))
+		}
+		if genres := joinNames(g.Genres); genres != "" {
+			details = append(details, genres)
+		}
+		if platforms := joinNames(g.Platforms); platforms != "" {
+			details = append(details, platforms)
+		}
+		title := g.Name
+		if len(details) > 0 {
+			title += " (" + strings.Join(details, "; ") + ")"
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: g.URL,
+			Site:      "igdb.com",
+			TopicID:   es.games.Put(strconv.FormatInt(g.ID, 10), g),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the game description with release, genre, platform and company details, then the Steam
+// review summary and most helpful reviews when the game is on Steam
+func (es *DataSourceIGDB) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	g, ok := es.games.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown IGDB topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString(g.Name)
+	if g.Summary != "" {
+		b.WriteString("\n\n" + g.Summary)
+	}
+	if g.Storyline != "" {
+		b.WriteString("\n\nStory: " + g.Storyline)
+	}
+	b.WriteString("\n")
+	if g.FirstReleaseDate > 0 {
+		b.WriteString("\nReleased: " + time.Unix(g.FirstReleaseDate, 0).UTC().Format("2006-01-02"))
+	}
+	var developers, publishers []string
+	for _, company := range g.InvolvedCompanies {
+		if company.Developer {
+			developers = append(developers, company.Company.Name)
+		}
+		if company.Publisher {
+			publishers = append(publishers, company.Company.Name)
+		}
+	}
+	for _, line := range []struct {
+		label string
+		value string
+	}{
+		{"Developer", strings.Join(developers, ", ")},
+		{"Publisher", strings.Join(publishers, ", ")},
+		{"Genres", joinNames(g.Genres)},
+		{"Themes", joinNames(g.Themes)},
+		{"Modes", joinNames(g.GameModes)},
+		{"Platforms", joinNames(g.Platforms)},
+	} {
+		if line.value != "" {
+			b.WriteString("\n" + line.label + ": " + line.value)
+		}
+	}
+	if g.TotalRatingCount > 0 {
+		fmt.Fprintf(&b, "\nRating: %.0f/100 (%d ratings)", g.TotalRating, g.TotalRatingCount)
+	}
+
+	results := []datasource.DataSourceData{{
+		DataText:  strings.TrimSpace(b.String()),
+		SourceURL: g.URL,
+		Site:      "igdb.com",
+		AnswerID:  topicID,
+	}}
+	appID := steamAppID(g)
+	if count == 1 || !es.SteamReviews || appID == "" {
+		return results, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	// Reviews are a supplement; the IGDB record stands on its own
+	if reviews, err := es.steamReviews(ctx, g.Name, appID); err == nil && reviews != "" {
+		results = append(results, datasource.DataSourceData{
+			DataText:  reviews,
+			SourceURL: "https://store.steampowered.com/app/" + appID,
+			Site:      "store.steampowered.com",
+			AnswerID:  topicid.Hash("steam/" + appID + "#reviews"),
+		})
+	}
+	return results, nil
+}
+
+// steamReviews summarises Steam user reviews, e.g. "Very Positive (12,345 positive, 678 negative)",
+// followed by the most helpful English reviews
+func (es *DataSourceIGDB) steamReviews(ctx context.Context, name, appID string) (string, error) {
+	params := url.Values{}
+	params.Set("json", "1")
+	params.Set("language", "english")
+	params.Set("filter", "all")
+	params.Set("purchase_type", "all")
+	params.Set("num_per_page", strconv.Itoa(reviewSize))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(es.SteamURL, "/")+"/appreviews/"+appID+"?"+params.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	resp, err := es.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("steam request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var response struct {
+		Success      int `json:"success"`
+		QuerySummary struct {
+			ReviewScoreDesc string `json:"review_score_desc"`
+			TotalPositive   int    `json:"total_positive"`
+			TotalNegative   int    `json:"total_negative"`
+		} `json:"query_summary"`
+		Reviews []struct {
+			Review  string `json:"review"`
+			VotedUp bool   `json:"voted_up"`
+			VotesUp int    `json:"votes_up"`
+			Author  struct {
+				PlaytimeForever int `json:"playtime_forever"`
+			} `json:"author"`
+		} `json:"reviews"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", err
+	}
+	summary := response.QuerySummary
+	if response.Success != 1 || summary.TotalPositive+summary.TotalNegative == 0 {
+		return "", nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Steam reviews for %s: %s (%d positive, %d negative)",
+		name, summary.ReviewScoreDesc, summary.TotalPositive, summary.TotalNegative)
+	for _, review := range response.Reviews {
+		text := strings.TrimSpace(review.Review)
+		if text == "" {
+			continue
+		}
+		verdict := "Not recommended"
+		if review.VotedUp {
+			verdict = "Recommended"
+		}
+		fmt.Fprintf(&b, "\n\n%s, %.1f hours played, %d found helpful:\n%s",
+			verdict, float64(review.Author.PlaytimeForever)/60, review.VotesUp, truncate(text, reviewBytes))
+	}
+	return b.String(), nil
+}
+
+// query sends an Apicalypse request body to an IGDB endpoint and decodes the JSON array response into target.
+// A 401 drops the cached token so the next request authenticates again.
+func (es *DataSourceIGDB) query(ctx context.Context, path, body string, target interface{}) error {
+	token, err := es.credentials.Token(ctx)
+	if err != nil {
+		return err
+	}
+	if es.rateLimiter != nil {
+		if err := es.rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(es.BaseURL, "/")+path, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Client-ID", es.ClientID)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := es.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		es.credentials.Invalidate()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("igdb request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func steamAppID(g game) string {
+	for _, external := range g.ExternalGames {
+		if (external.Source == steamSource || external.Category == steamSource) && external.UID != "" {
+			return external.UID
+		}
+	}
+	return ""
+}
+
+func joinNames(items []named) string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func truncate(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	cut := strings.LastIndex(text[:limit], " ")
+	if cut <= 0 {
+		cut = limit
+	}
+	return text[:cut] + "…"
+}
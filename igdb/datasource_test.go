@@ -0,0 +1,95 @@
+package igdb
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("client_id") != "id" || r.PostForm.Get("client_secret") != "secret" {
+			t.Errorf("token request %v", r.PostForm)
+		}
+		fmt.Fprint(w, `{"access_token":"token","expires_in":5000000}`)
+	})
+	mux.HandleFunc("/v4/games", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.Header.Get("Client-ID") != "id" || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("unexpected request %s %v", r.Method, r.Header)
+		}
+		if want := `search "zelda  breath"; fields ` + gameFields + `; limit 5;`; string(body) != want {
+			t.Errorf("body = %q, want %q", body, want)
+		}
+		fmt.Fprint(w, `[{"id":7346,"name":"The Legend of Zelda: Breath of the Wild","summary":"Step into a world of adventure.","url":"https://www.igdb.com/games/zelda-botw",
+			"first_release_date":1488499200,"total_rating":96.4,"total_rating_count":3000,
+			"genres":[{"name":"Adventure"},{"name":"RPG"}],"platforms":[{"name":"Switch"},{"name":"Wii U"}],"game_modes":[{"name":"Single player"}],
+			"involved_companies":[{"company":{"name":"Nintendo EPD"},"developer":true},{"company":{"name":"Nintendo"},"publisher":true}],
+			"external_games":[{"uid":"B01N1083WZ","category":20},{"uid":"123","external_game_source":1}]}]`)
+	})
+	mux.HandleFunc("/appreviews/123", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("json") != "1" || q.Get("language") != "english" || q.Get("num_per_page") != "3" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"success":1,"query_summary":{"review_score_desc":"Very Positive","total_positive":900,"total_negative":100},
+			"reviews":[{"review":"Wonderful.","voted_up":true,"votes_up":42,"author":{"playtime_forever":90}},{"review":"  "}]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL + "/v4"
+	es.AuthURL = srv.URL + "/oauth2/token"
+	es.SteamURL = srv.URL
+	es.ClientID = "id"
+	es.ClientSecret = "secret"
+
+	topics, err := es.FetchTopics(5, `zelda "breath`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "The Legend of Zelda: Breath of the Wild (2017; Adventure, RPG; Switch, Wii U)" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"The Legend of Zelda: Breath of the Wild\n\nStep into a world of adventure.\n\nReleased: 2017-03-03\nDeveloper: Nintendo EPD\nPublisher: Nintendo\n" +
+			"Genres: Adventure, RPG\nModes: Single player\nPlatforms: Switch, Wii U\nRating: 96/100 (3000 ratings)",
+		"Steam reviews for The Legend of Zelda: Breath of the Wild: Very Positive (900 positive, 100 negative)\n\nRecommended, 1.5 hours played, 42 found helpful:\nWonderful.",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+	if data[1].SourceURL != "https://store.steampowered.com/app/123" {
+		t.Errorf("review SourceURL = %q", data[1].SourceURL)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("one two three", 9); got != "one two…" {
+		t.Errorf("truncate = %q", got)
+	}
+	if got := truncate(strings.Repeat("x", 10), 4); got != "xxxx…" {
+		t.Errorf("truncate = %q", got)
+	}
+}
@@ -0,0 +1,338 @@
+package images
+
+// Data Source Adapter for licensed stock photos from Unsplash or Pexels
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+// Supported photo libraries
+const (
+	ProviderUnsplash = "unsplash" // Requires an access key
+	ProviderPexels   = "pexels"   // Requires an API key
+)
+
+type DataSourceImages struct {
+	Client      *http.Client
+	Provider    string // ProviderUnsplash or ProviderPexels
+	UnsplashURL string
+	PexelsURL   string
+	APIKey      string // Unsplash access key or Pexels API key
+	UserAgent   string
+	Orientation string // "landscape", "portrait" or "squarish"; empty for any
+
+	photos topicid.Map[photo]
+}
+
+// photo is a search result normalised across providers
+type photo struct {
+	Provider        string
+	ID              string
+	Description     string
+	Width           int
+	Height          int
+	Page            string // Photo page on the provider's site
+	ImageURL        string // Large rendition suitable for display
+	OriginalURL     string
+	Photographer    string
+	PhotographerURL string
+	Color           string
+}
+
+func New() *DataSourceImages {
+	return &DataSourceImages{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		Provider:    ProviderUnsplash,
+		UnsplashURL: "https://api.unsplash.com",
+		PexelsURL:   "https://api.pexels.com/v1",
+		UserAgent:   "locus/images-datasource",
+	}
+}
+
+// NSFWCapable reports that this source can return explicit or offensive photos, so content filters
+// wrapping it should screen its results
+func (es *DataSourceImages) NSFWCapable() bool {
+	return true
+}
+
+// Init implements models.DataSource
+// Validates the provider configuration
+func (es *DataSourceImages) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	switch es.Provider {
+	case ProviderUnsplash, ProviderPexels:
+	default:
+		return fmt.Errorf("unknown images provider %q", es.Provider)
+	}
+	if es.APIKey == "" {
+		return errors.New("APIKey is required for Images DataSource")
+	}
+	switch es.Orientation {
+	case "", "landscape", "portrait", "squarish":
+	default:
+		return fmt.Errorf("unknown photo orientation %q", es.Orientation)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceImages) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.search(ctx, "nature", 1)
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceImages) Info() source.SourceInfo {
+	if es.Provider == ProviderPexels {
+		return source.SourceInfo{
+			Name:                "Pexels",
+			Homepage:            "https://www.pexels.com",
+			License:             "Pexels License",
+			LicenseURL:          "https://www.pexels.com/license/",
+			Attribution:         "Photos provided by Pexels",
+			AttributionRequired: true,
+			TermsURL:            "https://www.pexels.com/api/documentation/#guidelines",
+		}
+	}
+	return source.SourceInfo{
+		Name:                "Unsplash",
+		Homepage:            "https://unsplash.com",
+		License:             "Unsplash License",
+		LicenseURL:          "https://unsplash.com/license",
+		Attribution:         "Photos from Unsplash; credit the photographer",
+		AttributionRequired: true,
+		TermsURL:            "https://unsplash.com/api-terms",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each photo is a topic, e.g. "A red fox in the snow (5184×3456, photo by Jane Doe)"
+func (es *DataSourceImages) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Images DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	photos, err := es.search(ctx, query, count)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(photos))
+	for _, p := range photos {
+		description := p.Description
+		if description == "" {
+			description = "Photo " + p.ID
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s (%d×%d, photo by %s)", description, p.Width, p.Height, p.Photographer),
+			SourceURL: p.Page,
+			Site:      p.Provider,
+			TopicID:   es.photos.Put(p.Provider+"/"+p.ID, p),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the photo's image URLs, dimensions, photographer, license and a ready-made attribution line
+func (es *DataSourceImages) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	p, ok := es.photos.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Images topicID %d", topicID)
+	}
+
+	license, licenseURL, site := "Unsplash License", "https://unsplash.com/license", "Unsplash"
+	if p.Provider == ProviderPexels {
+		license, licenseURL, site = "Pexels License", "https://www.pexels.com/license/", "Pexels"
+	}
+	var b strings.Builder
+	if p.Description != "" {
+		b.WriteString(p.Description + "\n\n")
+	}
+	fmt.Fprintf(&b, "Image: %s\nOriginal: %s\nDimensions: %d×%d", p.ImageURL, p.OriginalURL, p.Width, p.Height)
+	if p.Color != "" {
+		b.WriteString("\nDominant colour: " + p.Color)
+	}
+	fmt.Fprintf(&b, "\nPhotographer: %s (%s)", p.Photographer, p.PhotographerURL)
+	fmt.Fprintf(&b, "\nLicense: %s (%s)", license, licenseURL)
+	fmt.Fprintf(&b, "\nAttribution: Photo by %s on %s", p.Photographer, site)
+	return []datasource.DataSourceData{{
+		DataText:  b.String(),
+		SourceURL: p.Page,
+		Site:      p.Provider,
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// search queries the configured provider and normalises the results
+func (es *DataSourceImages) search(ctx context.Context, query string, count int) ([]photo, error) {
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("per_page", strconv.Itoa(count))
+
+	if es.Provider == ProviderPexels {
+		switch es.Orientation {
+		case "":
+		case "squarish":
+			// Pexels calls square photos "square"
+			params.Set("orientation", "square")
+		default:
+			params.Set("orientation", es.Orientation)
+		}
+		var response struct {
+			Photos []struct {
+				ID              int64  `json:"id"`
+				Width           int    `json:"width"`
+				Height          int    `json:"height"`
+				URL             string `json:"url"`
+				Alt             string `json:"alt"`
+				AvgColor        string `json:"avg_color"`
+				Photographer    string `json:"photographer"`
+				PhotographerURL string `json:"photographer_url"`
+				Src             struct {
+					Original string `json:"original"`
+					Large2x  string `json:"large2x"`
+				} `json:"src"`
+			} `json:"photos"`
+		}
+		if err := es.doJSON(ctx, es.PexelsURL+"/search", params, &response); err != nil {
+			return nil, err
+		}
+		photos := make([]photo, 0, len(response.Photos))
+		for _, p := range response.Photos {
+			photos = append(photos, photo{
+				Provider:        ProviderPexels,
+				ID:              strconv.FormatInt(p.ID, 10),
+				Description:     p.Alt,
+				Width:           p.Width,
+				Height:          p.Height,
+				Page:            p.URL,
+				ImageURL:        p.Src.Large2x,
+				OriginalURL:     p.Src.Original,
+				Photographer:    p.Photographer,
+				PhotographerURL: p.PhotographerURL,
+				Color:           p.AvgColor,
+			})
+		}
+		return photos, nil
+	}
+
+	if es.Orientation != "" {
+		params.Set("orientation", es.Orientation)
+	}
+	var response struct {
+		Results []struct {
+			ID             string `json:"id"`
+			Width          int    `json:"width"`
+			Height         int    `json:"height"`
+			Color          string `json:"color"`
+			Description    string `json:"description"`
+			AltDescription string `json:"alt_description"`
+			URLs           struct {
+				Full    string `json:"full"`
+				Regular string `json:"regular"`
+			} `json:"urls"`
+			Links struct {
+				HTML string `json:"html"`
+			} `json:"links"`
+			User struct {
+				Name  string `json:"name"`
+				Links struct {
+					HTML string `json:"html"`
+				} `json:"links"`
+			} `json:"user"`
+		} `json:"results"`
+	}
+	if err := es.doJSON(ctx, es.UnsplashURL+"/search/photos", params, &response); err != nil {
+		return nil, err
+	}
+	photos := make([]photo, 0, len(response.Results))
+	for _, p := range response.Results {
+		description := p.Description
+		if description == "" {
+			description = p.AltDescription
+		}
+		photos = append(photos, photo{
+			Provider:        ProviderUnsplash,
+			ID:              p.ID,
+			Description:     strings.TrimSpace(description),
+			Width:           p.Width,
+			Height:          p.Height,
+			Page:            p.Links.HTML,
+			ImageURL:        p.URLs.Regular,
+			OriginalURL:     p.URLs.Full,
+			Photographer:    p.User.Name,
+			PhotographerURL: p.User.Links.HTML,
+			Color:           p.Color,
+		})
+	}
+	return photos, nil
+}
+
+// doJSON performs an authenticated request against the configured provider and decodes the JSON response into target
+func (es *DataSourceImages) doJSON(ctx context.Context, endpoint string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if es.Provider == ProviderPexels {
+		req.Header.Set("Authorization", es.APIKey)
+	} else {
+		req.Header.Set("Authorization", "Client-ID "+es.APIKey)
+		req.Header.Set("Accept-Version", "v1")
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s request failed: status %d: %s", es.Provider, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
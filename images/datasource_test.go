@@ -0,0 +1,108 @@
+package images
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search/photos", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Client-ID key" || r.Header.Get("Accept-Version") != "v1" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		q := r.URL.Query()
+		if q.Get("query") != "fox" || q.Get("per_page") != "5" || q.Get("orientation") != "squarish" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"results":[{"id":"abc","width":5184,"height":3456,"color":"#c0a080","description":null,
+			"alt_description":" a red fox in the snow ","urls":{"full":"https://images.unsplash.com/full","regular":"https://images.unsplash.com/regular"},
+			"links":{"html":"https://unsplash.com/photos/abc"},"user":{"name":"Jane Doe","links":{"html":"https://unsplash.com/@jane"}}}]}`)
+	})
+	mux.HandleFunc("/v1/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "key" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		q := r.URL.Query()
+		if q.Get("query") != "fox" || q.Get("orientation") != "square" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"photos":[{"id":42,"width":4000,"height":4000,"url":"https://www.pexels.com/photo/42/","alt":"",
+			"avg_color":"#804020","photographer":"John Roe","photographer_url":"https://www.pexels.com/@john",
+			"src":{"original":"https://images.pexels.com/42.jpeg","large2x":"https://images.pexels.com/42-large.jpeg"}}]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndDataUnsplash(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.UnsplashURL = srv.URL
+	es.APIKey = "key"
+	es.Orientation = "squarish"
+
+	topics, err := es.FetchTopics(5, "fox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "a red fox in the snow (5184×3456, photo by Jane Doe)" ||
+		topics[0].SourceURL != "https://unsplash.com/photos/abc" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "a red fox in the snow\n\nImage: https://images.unsplash.com/regular\nOriginal: https://images.unsplash.com/full" +
+		"\nDimensions: 5184×3456\nDominant colour: #c0a080\nPhotographer: Jane Doe (https://unsplash.com/@jane)" +
+		"\nLicense: Unsplash License (https://unsplash.com/license)\nAttribution: Photo by Jane Doe on Unsplash"
+	if len(data) != 1 || data[0].DataText != want {
+		t.Errorf("FetchData = %+v, want %q", data, want)
+	}
+}
+
+func TestFetchTopicsAndDataPexels(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.Provider = ProviderPexels
+	es.PexelsURL = srv.URL + "/v1"
+	es.APIKey = "key"
+	es.Orientation = "squarish"
+
+	topics, err := es.FetchTopics(5, "fox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Photo 42 (4000×4000, photo by John Roe)" || topics[0].Site != ProviderPexels {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Image: https://images.pexels.com/42-large.jpeg\nOriginal: https://images.pexels.com/42.jpeg" +
+		"\nDimensions: 4000×4000\nDominant colour: #804020\nPhotographer: John Roe (https://www.pexels.com/@john)" +
+		"\nLicense: Pexels License (https://www.pexels.com/license/)\nAttribution: Photo by John Roe on Pexels"
+	if len(data) != 1 || data[0].DataText != want {
+		t.Errorf("FetchData = %+v, want %q", data, want)
+	}
+}
+
+func TestInit(t *testing.T) {
+	es := New()
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted a missing APIKey")
+	}
+	es.APIKey = "key"
+	es.Provider = "flickr"
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted an unknown provider")
+	}
+}
@@ -0,0 +1,173 @@
+// Package jsonpath evaluates the small JSONPath subset used by the
+// declarative adapters to map API responses onto topics and data: dotted
+// member names, array indexes and [*] wildcards, e.g.
+// "$.data.search.edges[*].node.title". Paths are evaluated against documents
+// decoded by encoding/json into interface{} values.
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// step is one segment of a compiled path: a member name, an index, or a wildcard
+type step struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// Path is a compiled JSONPath expression
+type Path struct {
+	raw   string
+	steps []step
+}
+
+// Compile parses a path. A leading "$" or "$." is optional; an empty path selects the document itself.
+func Compile(path string) (Path, error) {
+	p := Path{raw: path}
+	rest := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(path), "$"), ".")
+	for rest != "" {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return Path{}, fmt.Errorf("jsonpath %q: unclosed [", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			switch {
+			case inner == "*":
+				p.steps = append(p.steps, step{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				p.steps = append(p.steps, step{name: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return Path{}, fmt.Errorf("jsonpath %q: bad index %q", path, inner)
+				}
+				p.steps = append(p.steps, step{index: index, isIndex: true})
+			}
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "*" {
+				p.steps = append(p.steps, step{wildcard: true})
+			} else {
+				p.steps = append(p.steps, step{name: name})
+			}
+			rest = strings.TrimPrefix(rest[end:], ".")
+		}
+	}
+	return p, nil
+}
+
+// MustCompile is like Compile but panics on a malformed path
+func MustCompile(path string) Path {
+	p, err := Compile(path)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the path as written
+func (p Path) String() string {
+	return p.raw
+}
+
+// Select returns every value the path matches in doc. Missing members and
+// out-of-range indexes match nothing rather than failing.
+func (p Path) Select(doc interface{}) []interface{} {
+	current := []interface{}{doc}
+	for _, s := range p.steps {
+		var next []interface{}
+		for _, node := range current {
+			switch n := node.(type) {
+			case map[string]interface{}:
+				if s.wildcard {
+					for _, v := range n {
+						next = append(next, v)
+					}
+				} else if v, ok := n[s.name]; ok && !s.isIndex {
+					next = append(next, v)
+				}
+			case []interface{}:
+				switch {
+				case s.wildcard:
+					next = append(next, n...)
+				case s.isIndex:
+					index := s.index
+					if index < 0 {
+						index += len(n)
+					}
+					if index >= 0 && index < len(n) {
+						next = append(next, n[index])
+					}
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// First returns the first value the path matches in doc
+func (p Path) First(doc interface{}) (interface{}, bool) {
+	values := p.Select(doc)
+	if len(values) == 0 {
+		return nil, false
+	}
+	return values[0], true
+}
+
+// Text returns the first match rendered as text, or "" when nothing matches.
+// Arrays of scalars are joined with ", ".
+func (p Path) Text(doc interface{}) string {
+	value, ok := p.First(doc)
+	if !ok {
+		return ""
+	}
+	return Format(value)
+}
+
+// Lookup compiles path and returns its first match in doc rendered as text
+func Lookup(doc interface{}, path string) (string, error) {
+	p, err := Compile(path)
+	if err != nil {
+		return "", err
+	}
+	return p.Text(doc), nil
+}
+
+// Format renders a decoded JSON value as text: strings as is, whole numbers
+// without a fraction, arrays of scalars comma-separated, and objects as "".
+func Format(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := Format(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const doc = `{"data":{"search":{"edges":[
+	{"node":{"title":"First","tags":["a","b"],"stars":12,"archived":false}},
+	{"node":{"title":"Second","tags":[],"stars":1.5,"meta":{"weird key":"x"}}}
+]}}}`
+
+func TestText(t *testing.T) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"$.data.search.edges[0].node.title":             "First",
+		"data.search.edges[-1].node.title":              "Second",
+		"$.data.search.edges[*].node.title":             "First",
+		"data.search.edges[0].node.tags":                "a, b",
+		"data.search.edges[0].node.stars":               "12",
+		"data.search.edges[1].node.stars":               "1.5",
+		"data.search.edges[0].node.archived":            "false",
+		"data.search.edges[1].node.meta['weird key']":   "x",
+		"data.search.edges[1].node.meta[\"weird key\"]": "x",
+		"data.search.edges[5].node.title":               "",
+		"data.search.edges[0].node.missing":             "",
+		"data.search.edges[0].node":                     "",
+		"data.search.edges[0].title":                    "",
+	}
+	for path, want := range tests {
+		got, err := Lookup(v, path)
+		if err != nil {
+			t.Errorf("Lookup(%q): %v", path, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("Lookup(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestSelectWildcard(t *testing.T) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		t.Fatal(err)
+	}
+	titles := MustCompile("$.data.search.edges[*].node.title").Select(v)
+	if len(titles) != 2 || titles[0] != "First" || titles[1] != "Second" {
+		t.Errorf("Select = %v", titles)
+	}
+	if values := MustCompile("data.search.*").Select(v); len(values) != 1 {
+		t.Errorf("member wildcard selected %d values", len(values))
+	}
+	if values := MustCompile("").Select(v); len(values) != 1 {
+		t.Errorf("empty path selected %d values", len(values))
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, path := range []string{"items[0", "items[x]"} {
+		if _, err := Compile(path); err == nil {
+			t.Errorf("Compile(%q) succeeded", path)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Package readability extracts the main text of an HTML page, dropping
+// navigation, boilerplate and scripts. It is a small heuristic in the spirit
+// of Mozilla's Readability: semantic containers (<article>, <main>) are
+// preferred, otherwise the element holding the most paragraph text wins.
+package readability
+
+import (
+	"io"
+	"strings"
+
+	goquery "github.com/PuerkitoBio/goquery"
+)
+
+// Elements that never hold article text
+const boilerplate = "script, style, noscript, template, svg, iframe, form, nav, header, footer, aside, " +
+	"[role=navigation], [role=banner], [role=contentinfo], [aria-hidden=true]"
+
+// Elements whose text becomes one paragraph each
+const blocks = "p, h1, h2, h3, h4, h5, h6, li, pre, blockquote, dt, dd, td, figcaption"
+
+// Paragraphs shorter than this are dropped unless they are headings or list items
+const minParagraph = 25
+
+// Article is the readable content of a page
+type Article struct {
+	Title       string
+	Description string
+	Paragraphs  []string
+}
+
+// Text returns the paragraphs separated by blank lines
+func (a Article) Text() string {
+	return strings.Join(a.Paragraphs, "\n\n")
+}
+
+// FromReader parses an HTML document and extracts its article
+func FromReader(r io.Reader) (Article, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return Article{}, err
+	}
+	return FromDocument(doc), nil
+}
+
+// FromDocument extracts the article of a parsed document. The document is modified: boilerplate
+// elements are removed.
+func FromDocument(doc *goquery.Document) Article {
+	a := Metadata(doc)
+	RemoveBoilerplate(doc)
+	a.Paragraphs = Paragraphs(Content(doc))
+	return a
+}
+
+// Metadata returns the article's title and description from the document's meta tags or <title>
+func Metadata(doc *goquery.Document) Article {
+	a := Article{
+		Title:       meta(doc, "og:title"),
+		Description: meta(doc, "og:description"),
+	}
+	if a.Title == "" {
+		a.Title = clean(doc.Find("title").First().Text())
+	}
+	if a.Description == "" {
+		a.Description = meta(doc, "description")
+	}
+	return a
+}
+
+// RemoveBoilerplate removes scripts, navigation, headers, footers and other elements that never hold
+// article text
+func RemoveBoilerplate(doc *goquery.Document) {
+	doc.Find(boilerplate).Remove()
+}
+
+// Paragraphs returns the block-level text of a selection, one entry per paragraph, heading or list
+// item. A selection without block elements yields its whole text as one paragraph.
+func Paragraphs(s *goquery.Selection) []string {
+	var paragraphs []string
+	seen := map[string]bool{}
+	s.Find(blocks).Each(func(_ int, b *goquery.Selection) {
+		// Text of nested blocks (a <p> in an <li>) is taken at the innermost level
+		if b.Find(blocks).Length() > 0 {
+			return
+		}
+		text := clean(b.Text())
+		if text == "" || seen[text] {
+			return
+		}
+		if len(text) < minParagraph && !b.Is("h1, h2, h3, h4, h5, h6, li, dt, dd") {
+			return
+		}
+		seen[text] = true
+		paragraphs = append(paragraphs, text)
+	})
+	if len(paragraphs) == 0 {
+		if text := clean(s.Text()); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	}
+	return paragraphs
+}
+
+// Content picks the element most likely to hold the article
+func Content(doc *goquery.Document) *goquery.Selection {
+	for _, selector := range []string{"article", "main", "[role=main]", "#content", ".content"} {
+		if s := doc.Find(selector); s.Length() == 1 && len(clean(s.Text())) > 200 {
+			return s
+		}
+	}
+	var best *goquery.Selection
+	bestScore := 0
+	doc.Find("div, section, td").Each(func(_ int, s *goquery.Selection) {
+		score := 0
+		s.ChildrenFiltered("p, pre, blockquote").Each(func(_ int, p *goquery.Selection) {
+			score += len(clean(p.Text()))
+		})
+		if score > bestScore {
+			best, bestScore = s, score
+		}
+	})
+	if best != nil {
+		return best
+	}
+	return doc.Find("body")
+}
+
+// Helpers
+
+func meta(doc *goquery.Document, name string) string {
+	value, _ := doc.Find(`meta[property="` + name + `"], meta[name="` + name + `"]`).First().Attr("content")
+	return clean(value)
+}
+
+// clean collapses runs of whitespace into single spaces
+func clean(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
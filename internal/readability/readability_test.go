@@ -0,0 +1,57 @@
+package readability
+
+import (
+	"strings"
+	"testing"
+)
+
+const page = `<html><head>
+<title>Fallback title</title>
+<meta property="og:title" content=" The   Article ">
+<meta name="description" content="What it is about">
+</head><body>
+<header><p>Site header with a long enough line of text</p></header>
+<nav><ul><li>Home</li><li>About</li></ul></nav>
+<div id="sidebar"><p>Sidebar text that is long enough to count.</p></div>
+<div class="post">
+	<h1>Heading</h1>
+	<p>The first paragraph of the article, which is long.</p>
+	<p>Tiny</p>
+	<ul><li>Item</li><li><p>A paragraph nested in a list item.</p></li></ul>
+	<p>The first paragraph of the article, which is long.</p>
+	<p>The second paragraph of the article is longer still.</p>
+	<script>var x = "<p>not text at all, just a script body</p>";</script>
+</div>
+<footer><p>Copyright notice that is long enough to count</p></footer>
+</body></html>`
+
+func TestFromReader(t *testing.T) {
+	a, err := FromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Title != "The Article" || a.Description != "What it is about" {
+		t.Errorf("metadata = %q, %q", a.Title, a.Description)
+	}
+	want := []string{
+		"Heading",
+		"The first paragraph of the article, which is long.",
+		"Item",
+		"A paragraph nested in a list item.",
+		"The second paragraph of the article is longer still.",
+	}
+	if strings.Join(a.Paragraphs, "|") != strings.Join(want, "|") {
+		t.Errorf("Paragraphs = %q, want %q", a.Paragraphs, want)
+	}
+}
+
+func TestFromReaderArticle(t *testing.T) {
+	text := strings.Repeat("Words in the article element. ", 10)
+	a, err := FromReader(strings.NewReader(`<title>T</title><div><p>Elsewhere on the page, a paragraph.</p></div><article>` + text + `</article>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Title != "T" || len(a.Paragraphs) != 1 || a.Paragraphs[0] != strings.TrimSpace(text) {
+		t.Errorf("FromReader = %+v", a)
+	}
+}
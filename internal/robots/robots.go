@@ -0,0 +1,246 @@
+// Package robots parses robots.txt files (RFC 9309) and answers whether a
+// URL may be fetched, caching the rules per host. It is shared by the
+// adapters that fetch arbitrary web pages: scrape, sitemap and crawler.
+package robots
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Files larger than this are truncated, as RFC 9309 allows
+const maxSize = 500 * 1024
+
+// Rules are the directives of the group that applies to one user agent
+type Rules struct {
+	allow      []string
+	disallow   []string
+	CrawlDelay time.Duration // Zero when the group sets none
+	Sitemaps   []string      // Sitemap URLs listed anywhere in the file
+}
+
+// AllowAll is the rule set used when a host has no robots.txt
+var AllowAll = &Rules{}
+
+// DisallowAll is the rule set used when a host's robots.txt is unreachable
+var DisallowAll = &Rules{disallow: []string{"/"}}
+
+// Parse reads a robots.txt file and returns the rules for agent, falling back to the "*" group.
+// Agent is matched by its product token, e.g. "locus" for "locus/scrape-datasource".
+func Parse(r io.Reader, agent string) *Rules {
+	token := strings.ToLower(agent)
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+
+	var specific, fallback *Rules
+	var sitemaps []string
+	var current []*Rules // Groups the rules being read belong to
+	inAgents := false    // Whether the previous line was a user-agent line
+	scanner := bufio.NewScanner(io.LimitReader(r, maxSize))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		// Sitemap lines stand outside the groups
+		if key == "sitemap" {
+			if value != "" {
+				sitemaps = append(sitemaps, value)
+			}
+			continue
+		}
+		if key == "user-agent" {
+			if !inAgents {
+				current = nil
+			}
+			inAgents = true
+			name := strings.ToLower(value)
+			switch {
+			case name == "*":
+				if fallback == nil {
+					fallback = &Rules{}
+				}
+				current = append(current, fallback)
+			case name == token:
+				if specific == nil {
+					specific = &Rules{}
+				}
+				current = append(current, specific)
+			}
+			continue
+		}
+		inAgents = false
+		for _, rules := range current {
+			switch key {
+			case "allow":
+				if value != "" {
+					rules.allow = append(rules.allow, value)
+				}
+			case "disallow":
+				// An empty Disallow allows everything and adds no rule
+				if value != "" {
+					rules.disallow = append(rules.disallow, value)
+				}
+			case "crawl-delay":
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					rules.CrawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+	}
+	rules := specific
+	if rules == nil {
+		rules = fallback
+	}
+	if rules == nil {
+		rules = &Rules{}
+	}
+	rules.Sitemaps = sitemaps
+	return rules
+}
+
+// Allowed reports whether path (with its query) may be fetched. The longest matching rule wins and
+// Allow wins a tie.
+func (r *Rules) Allowed(path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	best, allowed := -1, true
+	for _, pattern := range r.disallow {
+		if len(pattern) > best && match(pattern, path) {
+			best, allowed = len(pattern), false
+		}
+	}
+	for _, pattern := range r.allow {
+		if len(pattern) >= best && match(pattern, path) {
+			best, allowed = len(pattern), true
+		}
+	}
+	return allowed
+}
+
+// Checker fetches and caches robots.txt rules per scheme and host
+type Checker struct {
+	Client    *http.Client
+	UserAgent string
+
+	mu    sync.Mutex
+	rules map[string]*Rules
+}
+
+// NewChecker returns a Checker identifying itself as userAgent
+func NewChecker(client *http.Client, userAgent string) *Checker {
+	return &Checker{Client: client, UserAgent: userAgent}
+}
+
+// Allowed reports whether rawURL may be fetched by the Checker's user agent
+func (c *Checker) Allowed(ctx context.Context, rawURL string) (bool, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false, err
+	}
+	rules, err := c.Rules(ctx, u)
+	if err != nil {
+		return false, err
+	}
+	return rules.Allowed(u.RequestURI()), nil
+}
+
+// Rules returns the rules for u's host, fetching its robots.txt on first use. A missing file (4xx)
+// allows everything; a server error disallows everything, as RFC 9309 requires. Network errors are
+// returned and not cached.
+func (c *Checker) Rules(ctx context.Context, u *url.URL) (*Rules, error) {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("robots: unsupported URL scheme %q", u.Scheme)
+	}
+	key := u.Scheme + "://" + u.Host
+	c.mu.Lock()
+	rules, ok := c.rules[key]
+	c.mu.Unlock()
+	if ok {
+		return rules, nil
+	}
+
+	rules, err := c.fetch(ctx, key+"/robots.txt")
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.rules == nil {
+		c.rules = map[string]*Rules{}
+	}
+	c.rules[key] = rules
+	c.mu.Unlock()
+	return rules, nil
+}
+
+func (c *Checker) fetch(ctx context.Context, uri string) (*Rules, error) {
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return Parse(resp.Body, c.UserAgent), nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return AllowAll, nil
+	}
+	return DisallowAll, nil
+}
+
+// Helpers
+
+// match reports whether path matches a robots.txt pattern, where "*" matches any run of characters
+// and a trailing "$" anchors the end
+func match(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		last := i == len(parts)-2
+		if last && anchored {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	return !anchored || rest == ""
+}
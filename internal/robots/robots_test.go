@@ -0,0 +1,108 @@
+package robots
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const file = `# Example
+User-agent: *
+Disallow: /private
+Allow: /private/public$
+Crawl-delay: 2
+
+User-agent: Locus
+User-agent: other
+Disallow: /*.pdf$
+Disallow: /search
+Allow: /search/help
+Crawl-delay: 0.5
+
+Sitemap: https://example.com/sitemap.xml
+`
+
+func TestParse(t *testing.T) {
+	rules := Parse(strings.NewReader(file), "locus/scrape-datasource")
+	tests := map[string]bool{
+		"/":                   true,
+		"/private":            true, // Only the "*" group disallows it
+		"/report.pdf":         false,
+		"/report.pdf?v=2":     true,
+		"/search?q=x":         false,
+		"/search/help":        true,
+		"/robots.txt":         true,
+		"/files/a/report.pdf": false,
+	}
+	for path, want := range tests {
+		if got := rules.Allowed(path); got != want {
+			t.Errorf("Allowed(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if rules.CrawlDelay != 500*time.Millisecond {
+		t.Errorf("CrawlDelay = %v", rules.CrawlDelay)
+	}
+	if len(rules.Sitemaps) != 1 || rules.Sitemaps[0] != "https://example.com/sitemap.xml" {
+		t.Errorf("Sitemaps = %q", rules.Sitemaps)
+	}
+}
+
+func TestParseFallback(t *testing.T) {
+	rules := Parse(strings.NewReader(file), "somebot")
+	tests := map[string]bool{
+		"/private/x":      false,
+		"/private/public": true,
+		"/report.pdf":     true,
+	}
+	for path, want := range tests {
+		if got := rules.Allowed(path); got != want {
+			t.Errorf("Allowed(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if rules.CrawlDelay != 2*time.Second {
+		t.Errorf("CrawlDelay = %v", rules.CrawlDelay)
+	}
+}
+
+func TestChecker(t *testing.T) {
+	var fetches atomic.Int32
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if r.URL.Path != "/robots.txt" || r.Header.Get("User-Agent") != "locus/test" {
+			t.Errorf("unexpected request %s %v", r.URL, r.Header)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte("User-agent: *\nDisallow: /admin\n"))
+	}))
+	defer srv.Close()
+
+	c := NewChecker(srv.Client(), "locus/test")
+	ctx := context.Background()
+	for path, want := range map[string]bool{"/admin/users": false, "/index.html": true} {
+		got, err := c.Allowed(ctx, srv.URL+path)
+		if err != nil || got != want {
+			t.Errorf("Allowed(%q) = %v, %v; want %v", path, got, err, want)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("robots.txt fetched %d times, want 1", n)
+	}
+
+	// Rules are cached per host; a fresh checker sees the new status
+	for s, want := range map[int]bool{http.StatusNotFound: true, http.StatusServiceUnavailable: false} {
+		status = s
+		c := NewChecker(srv.Client(), "locus/test")
+		got, err := c.Allowed(ctx, srv.URL+"/admin")
+		if err != nil || got != want {
+			t.Errorf("with status %d: Allowed(/admin) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := c.Allowed(ctx, "ftp://example.com/file"); err == nil {
+		t.Error("Allowed accepted an ftp URL")
+	}
+}
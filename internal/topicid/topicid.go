@@ -0,0 +1,65 @@
+// Package topicid maps the string keys used by many backends (URLs, slugs,
+// DOIs) onto the int64 topic IDs of the datasource SDK, and remembers them so
+// FetchData can resolve an ID that was previously handed out by FetchTopics.
+package topicid
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+const defaultCapacity = 4096
+
+// Hash returns a stable, non-negative ID for the given key
+func Hash(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64() &^ (1 << 63))
+}
+
+// Map remembers values by hashed key. The zero value is ready to use and is
+// safe for concurrent use. Once Capacity entries are held the oldest ones are
+// evicted, so long-running hosts don't grow without bound.
+type Map[V any] struct {
+	Capacity int // Maximum entries retained; 0 uses a default of 4096
+
+	mu    sync.Mutex
+	items map[int64]V
+	order []int64
+}
+
+// Put stores value under the hash of key and returns that ID
+func (m *Map[V]) Put(key string, value V) int64 {
+	id := Hash(key)
+	m.Set(id, value)
+	return id
+}
+
+// Set stores value under an ID that was handed out elsewhere, e.g. by a wrapped data source
+func (m *Map[V]) Set(id int64, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.items == nil {
+		m.items = make(map[int64]V)
+	}
+	if _, ok := m.items[id]; !ok {
+		m.order = append(m.order, id)
+		limit := m.Capacity
+		if limit <= 0 {
+			limit = defaultCapacity
+		}
+		for len(m.order) > limit {
+			delete(m.items, m.order[0])
+			m.order = m.order[1:]
+		}
+	}
+	m.items[id] = value
+}
+
+// Get returns the value stored for id, if it is still retained
+func (m *Map[V]) Get(id int64) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.items[id]
+	return value, ok
+}
@@ -0,0 +1,28 @@
+package topicid
+
+import "testing"
+
+func TestHash(t *testing.T) {
+	for _, key := range []string{"", "https://example.com/", "doi:10.1000/182"} {
+		if id := Hash(key); id < 0 || id != Hash(key) {
+			t.Errorf("Hash(%q) = %d, want a stable non-negative ID", key, id)
+		}
+	}
+}
+
+func TestMapEvictsOldest(t *testing.T) {
+	m := Map[string]{Capacity: 2}
+	a := m.Put("a", "A")
+	b := m.Put("b", "B")
+	m.Set(a, "A2") // Updating an entry does not make room
+	c := m.Put("c", "C")
+
+	if _, ok := m.Get(a); ok {
+		t.Error("the oldest entry was retained past Capacity")
+	}
+	for id, want := range map[int64]string{b: "B", c: "C"} {
+		if got, ok := m.Get(id); !ok || got != want {
+			t.Errorf("Get(%d) = %q, %v, want %q", id, got, ok, want)
+		}
+	}
+}
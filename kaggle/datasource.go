@@ -0,0 +1,430 @@
+package kaggle
+
+// Data Source Adapter for Kaggle dataset and competition search
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	// Files listed in a dataset or competition's data item
+	maxFiles = 50
+)
+
+type DataSourceKaggle struct {
+	Client    *http.Client
+	BaseURL   string
+	UserAgent string
+	Username  string   // Set by user: from kaggle.json or Settings > API
+	Key       string   // Set by user: the API key paired with Username
+	Scopes    []string // Any of "datasets" and "competitions"
+
+	refs topicid.Map[ref]
+}
+
+// ref identifies the dataset or competition behind a topic
+type ref struct {
+	Scope       string
+	Ref         string // "owner/slug" for datasets, the slug for competitions
+	Title       string
+	Topic       string // Title with the usability score or reward
+	URL         string
+	Description string
+	Details     []string // "label: value" lines shown with the description
+}
+
+func New() *DataSourceKaggle {
+	return &DataSourceKaggle{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://www.kaggle.com/api/v1",
+		UserAgent: "locus/kaggle-datasource",
+		Scopes:    []string{"datasets", "competitions"},
+	}
+}
+
+// Init implements models.DataSource
+// Validates the credentials and the configured search scopes
+func (es *DataSourceKaggle) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.Username == "" || es.Key == "" {
+		return errors.New("Username and Key are required for Kaggle DataSource")
+	}
+	for _, scope := range es.Scopes {
+		switch scope {
+		case "datasets", "competitions":
+		default:
+			return fmt.Errorf("unsupported Kaggle search scope %q", scope)
+		}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceKaggle) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("page", "1")
+	params.Set("search", "kaggle")
+	return es.doJSON(ctx, "/datasets/list", params, &[]struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceKaggle) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Kaggle",
+		Homepage: "https://www.kaggle.com",
+		TermsURL: "https://www.kaggle.com/terms",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Searches every configured scope and interleaves the results. Datasets are titled with their usability
+// score, e.g. "Titanic passengers (dataset, usability 0.94)"; competitions with their reward.
+func (es *DataSourceKaggle) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Kaggle DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	perScope := make([][]datasource.DataSourceTopic, 0, len(es.Scopes))
+	var firstErr error
+	for _, scope := range es.Scopes {
+		var refs []ref
+		var err error
+		if scope == "datasets" {
+			refs, err = es.searchDatasets(ctx, query)
+		} else {
+			refs, err = es.searchCompetitions(ctx, query)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		topics := make([]datasource.DataSourceTopic, 0, len(refs))
+		for _, item := range refs {
+			topics = append(topics, datasource.DataSourceTopic{
+				Topic:     item.Topic,
+				SourceURL: item.URL,
+				Site:      "kaggle.com",
+				TopicID:   es.refs.Put(scope+":"+item.Ref, item),
+			})
+		}
+		perScope = append(perScope, topics)
+	}
+	if len(perScope) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for i := 0; len(results) < count; i++ {
+		added := false
+		for _, topics := range perScope {
+			if i < len(topics) && len(results) < count {
+				results = append(results, topics[i])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the description with its details (usability, license, downloads or reward, deadline), then
+// the file listing with sizes
+func (es *DataSourceKaggle) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	item, ok := es.refs.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Kaggle topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	description := item.Description
+	var files []file
+	var err error
+	if item.Scope == "datasets" {
+		var metadata struct {
+			Info struct {
+				Description string `json:"description"`
+			} `json:"info"`
+		}
+		// The search listing leaves the description out; the metadata has the full Markdown
+		if es.doJSON(ctx, "/datasets/metadata/"+item.Ref, nil, &metadata) == nil && metadata.Info.Description != "" {
+			description = metadata.Info.Description
+		}
+		files, err = es.datasetFiles(ctx, item.Ref)
+	} else {
+		files, err = es.competitionFiles(ctx, item.Ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []string{item.Title}
+	if d := strings.TrimSpace(description); d != "" {
+		lines = append(lines, d)
+	}
+	lines = append(lines, item.Details...)
+	results := make([]datasource.DataSourceData, 0, 2)
+	results = append(results, datasource.DataSourceData{
+		DataText:  strings.Join(lines, "\n"),
+		SourceURL: item.URL,
+		Site:      "kaggle.com",
+		AnswerID:  topicID,
+	})
+	if len(files) > 0 && count > 1 {
+		var b strings.Builder
+		fmt.Fprintf(&b, "Files in %s:", item.Title)
+		for i, f := range files {
+			if i == maxFiles {
+				fmt.Fprintf(&b, "\n… and %d more", len(files)-maxFiles)
+				break
+			}
+			fmt.Fprintf(&b, "\n- %s (%s)", f.Name, humanBytes(f.Size))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  b.String(),
+			SourceURL: item.URL + "/data",
+			Site:      "kaggle.com",
+			AnswerID:  topicid.Hash(item.Scope + ":" + item.Ref + "#files"),
+		})
+	}
+	return results, nil
+}
+
+func (es *DataSourceKaggle) searchDatasets(ctx context.Context, query string) ([]ref, error) {
+	params := url.Values{}
+	params.Set("search", query)
+	params.Set("page", "1")
+	var datasets []struct {
+		Ref             string  `json:"ref"`
+		Title           string  `json:"title"`
+		Subtitle        string  `json:"subtitle"`
+		URL             string  `json:"url"`
+		OwnerName       string  `json:"ownerName"`
+		LicenseName     string  `json:"licenseName"`
+		UsabilityRating float64 `json:"usabilityRating"`
+		TotalBytes      int64   `json:"totalBytes"`
+		DownloadCount   int64   `json:"downloadCount"`
+		VoteCount       int64   `json:"voteCount"`
+		LastUpdated     string  `json:"lastUpdated"`
+	}
+	if err := es.doJSON(ctx, "/datasets/list", params, &datasets); err != nil {
+		return nil, err
+	}
+	refs := make([]ref, 0, len(datasets))
+	for _, d := range datasets {
+		if d.Ref == "" {
+			continue
+		}
+		usability := strconv.FormatFloat(d.UsabilityRating, 'f', 2, 64)
+		item := ref{
+			Scope:       "datasets",
+			Ref:         d.Ref,
+			Title:       d.Title,
+			Topic:       d.Title + " (dataset, usability " + usability + ")",
+			URL:         d.URL,
+			Description: d.Subtitle,
+			Details: []string{
+				"usability: " + usability,
+				"owner: " + d.OwnerName,
+				"license: " + d.LicenseName,
+				"size: " + humanBytes(d.TotalBytes),
+				"downloads: " + strconv.FormatInt(d.DownloadCount, 10),
+				"votes: " + strconv.FormatInt(d.VoteCount, 10),
+			},
+		}
+		if d.LastUpdated != "" {
+			item.Details = append(item.Details, "updated: "+d.LastUpdated)
+		}
+		if item.URL == "" {
+			item.URL = "https://www.kaggle.com/datasets/" + d.Ref
+		}
+		refs = append(refs, item)
+	}
+	return refs, nil
+}
+
+func (es *DataSourceKaggle) searchCompetitions(ctx context.Context, query string) ([]ref, error) {
+	params := url.Values{}
+	params.Set("search", query)
+	params.Set("page", "1")
+	var competitions []struct {
+		Ref         string `json:"ref"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		URL         string `json:"url"`
+		Category    string `json:"category"`
+		Reward      string `json:"reward"`
+		Deadline    string `json:"deadline"`
+		TeamCount   int64  `json:"teamCount"`
+	}
+	if err := es.doJSON(ctx, "/competitions/list", params, &competitions); err != nil {
+		return nil, err
+	}
+	refs := make([]ref, 0, len(competitions))
+	for _, c := range competitions {
+		// Newer API versions return the competition URL as its ref
+		slug := strings.TrimRight(c.Ref, "/")
+		if i := strings.LastIndexByte(slug, '/'); i >= 0 {
+			slug = slug[i+1:]
+		}
+		if slug == "" {
+			continue
+		}
+		topic := c.Title + " (competition"
+		if c.Reward != "" {
+			topic += ", " + c.Reward
+		}
+		item := ref{
+			Scope:       "competitions",
+			Ref:         slug,
+			Title:       c.Title,
+			Topic:       topic + ")",
+			URL:         c.URL,
+			Description: c.Description,
+			Details: []string{
+				"category: " + c.Category,
+				"reward: " + c.Reward,
+				"deadline: " + c.Deadline,
+				"teams: " + strconv.FormatInt(c.TeamCount, 10),
+			},
+		}
+		if item.URL == "" {
+			item.URL = "https://www.kaggle.com/competitions/" + slug
+		}
+		refs = append(refs, item)
+	}
+	return refs, nil
+}
+
+type file struct {
+	Name string `json:"name"`
+	Size int64  `json:"totalBytes"`
+}
+
+func (es *DataSourceKaggle) datasetFiles(ctx context.Context, datasetRef string) ([]file, error) {
+	var response struct {
+		Files []file `json:"datasetFiles"`
+	}
+	if err := es.doJSON(ctx, "/datasets/list/"+datasetRef, nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Files, nil
+}
+
+// competitionFiles lists a competition's data files; the API answers with a bare array or, in newer
+// versions, an object holding one. Competitions whose rules are not accepted answer 403 and list nothing.
+func (es *DataSourceKaggle) competitionFiles(ctx context.Context, slug string) ([]file, error) {
+	var raw json.RawMessage
+	if err := es.doJSON(ctx, "/competitions/data/list/"+url.PathEscape(slug), nil, &raw); err != nil {
+		if strings.Contains(err.Error(), "status 403") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var files []file
+	if err := json.Unmarshal(raw, &files); err == nil {
+		return files, nil
+	}
+	var wrapped struct {
+		Files []file `json:"files"`
+	}
+	if err := json.Unmarshal(raw, &wrapped); err != nil {
+		return nil, err
+	}
+	return wrapped.Files, nil
+}
+
+// doJSON performs an HTTP GET request against the Kaggle API and decodes the JSON response into target
+func (es *DataSourceKaggle) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri += "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(es.Username, es.Key)
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("kaggle request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+
+// humanBytes formats a size in binary units, e.g. "1.2 MB"
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return strconv.FormatFloat(float64(n)/float64(div), 'f', 1, 64) + " " + string("KMGTPE"[exp]) + "B"
+}
@@ -0,0 +1,156 @@
+package kaggle
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	search := func(w http.ResponseWriter, r *http.Request) bool {
+		if user, key, _ := r.BasicAuth(); user != "alice" || key != "key" {
+			t.Errorf("requested as %q", user)
+		}
+		if q := r.URL.Query(); q.Get("search") != "titanic" || q.Get("page") != "1" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+			return false
+		}
+		return true
+	}
+	mux.HandleFunc("GET /api/v1/datasets/list", func(w http.ResponseWriter, r *http.Request) {
+		if search(w, r) {
+			fmt.Fprint(w, `[
+				{"ref":"heptapod/titanic","title":"Titanic","subtitle":"Passenger survival","url":"https://www.kaggle.com/datasets/heptapod/titanic",
+					"ownerName":"Heptapod","licenseName":"CC0","usabilityRating":0.941,"totalBytes":34877,"downloadCount":120000,"voteCount":900,"lastUpdated":"2024-01-02T03:04:05Z"},
+				{"ref":"someone/titanic-extended","title":"Titanic extended","usabilityRating":0.5},
+				{"ref":"","title":"Broken"}
+			]`)
+		}
+	})
+	mux.HandleFunc("GET /api/v1/competitions/list", func(w http.ResponseWriter, r *http.Request) {
+		if search(w, r) {
+			fmt.Fprint(w, `[{"ref":"https://www.kaggle.com/competitions/titanic","title":"Titanic - Machine Learning from Disaster",
+				"description":"Predict survival","category":"Getting Started","reward":"Knowledge","deadline":"2030-01-01T00:00:00Z","teamCount":15000}]`)
+		}
+	})
+	mux.HandleFunc("GET /api/v1/datasets/metadata/heptapod/titanic", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"info":{"description":"## About\nData on passengers of the RMS Titanic."}}`)
+	})
+	mux.HandleFunc("GET /api/v1/datasets/list/heptapod/titanic", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"datasetFiles":[{"name":"train.csv","totalBytes":61194},{"name":"test.csv","totalBytes":28629}]}`)
+	})
+	mux.HandleFunc("GET /api/v1/competitions/data/list/titanic", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"files":[{"name":"gender_submission.csv","totalBytes":3258}]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSource(srv *httptest.Server) *DataSourceKaggle {
+	es := New()
+	es.BaseURL = srv.URL + "/api/v1/"
+	es.Username = "alice"
+	es.Key = "key"
+	return es
+}
+
+func TestFetchTopics(t *testing.T) {
+	es := newSource(newServer(t))
+	topics, err := es.FetchTopics(5, "titanic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Scopes are interleaved
+	want := []string{
+		"Titanic (dataset, usability 0.94)|https://www.kaggle.com/datasets/heptapod/titanic",
+		"Titanic - Machine Learning from Disaster (competition, Knowledge)|https://www.kaggle.com/competitions/titanic",
+		"Titanic extended (dataset, usability 0.50)|https://www.kaggle.com/datasets/someone/titanic-extended",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].SourceURL; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{
+		"Titanic\n## About\nData on passengers of the RMS Titanic.\nusability: 0.94\nowner: Heptapod\nlicense: CC0\n" +
+			"size: 34.1 KB\ndownloads: 120000\nvotes: 900\nupdated: 2024-01-02T03:04:05Z",
+		"Files in Titanic:\n- train.csv (59.8 KB)\n- test.csv (28.0 KB)",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, w := range want {
+		if data[i].DataText != w {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, w)
+		}
+	}
+	if data[1].SourceURL != "https://www.kaggle.com/datasets/heptapod/titanic/data" {
+		t.Errorf("files SourceURL = %s", data[1].SourceURL)
+	}
+
+	data, err = es.FetchData(5, topics[1].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 || !strings.HasPrefix(data[0].DataText, "Titanic - Machine Learning from Disaster\nPredict survival\ncategory: Getting Started") ||
+		data[1].DataText != "Files in Titanic - Machine Learning from Disaster:\n- gender_submission.csv (3.2 KB)" {
+		t.Errorf("FetchData = %q", data)
+	}
+}
+
+func TestFetchTopicsScopeFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/datasets/list" {
+			fmt.Fprint(w, `[{"ref":"heptapod/titanic","title":"Titanic"}]`)
+			return
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	es := newSource(srv)
+	es.BaseURL = srv.URL
+
+	// A failing scope is skipped when another answers
+	topics, err := es.FetchTopics(5, "titanic")
+	if err != nil || len(topics) != 1 {
+		t.Errorf("FetchTopics = %+v, %v", topics, err)
+	}
+	es.Scopes = []string{"competitions"}
+	if _, err := es.FetchTopics(5, "titanic"); err == nil || !strings.Contains(err.Error(), "status 503") {
+		t.Errorf("FetchTopics with every scope failing = %v", err)
+	}
+}
+
+func TestCompetitionFilesForbidden(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"You must accept this competition's rules"}`, http.StatusForbidden)
+	}))
+	defer srv.Close()
+	es := newSource(srv)
+	files, err := es.competitionFiles(t.Context(), "titanic")
+	if err != nil || files != nil {
+		t.Errorf("competitionFiles = %v, %v", files, err)
+	}
+}
+
+func TestHumanBytes(t *testing.T) {
+	tests := map[int64]string{0: "0 B", 1023: "1023 B", 1024: "1.0 KB", 1536: "1.5 KB", 5 << 30: "5.0 GB"}
+	for n, want := range tests {
+		if got := humanBytes(n); got != want {
+			t.Errorf("humanBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,505 @@
+package lastfm
+
+// Data Source Adapter for Last.fm artist, track and tag data
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	listSize          = 10
+)
+
+// Search kinds
+const (
+	KindArtist = "artist"
+	KindTrack  = "track"
+	KindTag    = "tag"
+)
+
+var (
+	// readMore matches the link Last.fm appends to every bio and wiki summary
+	readMore = regexp.MustCompile(`\s*<a href="[^"]*">Read more on Last\.fm</a>\.?`)
+	htmlTag  = regexp.MustCompile(`<[^>]+>`)
+)
+
+type DataSourceLastFM struct {
+	Client    *http.Client
+	BaseURL   string
+	APIKey    string
+	UserAgent string
+	Kind      string // KindArtist, KindTrack or KindTag
+
+	entries topicid.Map[entry]
+}
+
+// entry identifies an artist, a track (with its artist) or a tag
+type entry struct {
+	Kind   string
+	Name   string
+	Artist string
+	URL    string
+}
+
+type named struct {
+	Name string `json:"name"`
+}
+
+func New() *DataSourceLastFM {
+	return &DataSourceLastFM{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://ws.audioscrobbler.com/2.0/",
+		UserAgent: "locus/lastfm-datasource",
+		Kind:      KindArtist,
+	}
+}
+
+// Init implements models.DataSource
+// Requires an API key
+func (es *DataSourceLastFM) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.APIKey == "" {
+		return errors.New("APIKey is required for Last.fm DataSource")
+	}
+	switch es.Kind {
+	case KindArtist, KindTrack, KindTag:
+		return nil
+	}
+	return fmt.Errorf("unknown Last.fm search kind %q", es.Kind)
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceLastFM) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("limit", "1")
+	return es.call(ctx, "chart.getTopArtists", params, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceLastFM) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "Last.fm",
+		Homepage:            "https://www.last.fm",
+		Attribution:         "Data from Last.fm",
+		AttributionRequired: true,
+		TermsURL:            "https://www.last.fm/api/tos",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Topics are artists ("Name (1,234,567 listeners)"), tracks ("Title — Artist (...)") or tags
+func (es *DataSourceLastFM) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Last.fm DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	var entries []entry
+	var listeners []string
+	switch es.Kind {
+	case KindArtist:
+		params := url.Values{}
+		params.Set("artist", query)
+		params.Set("limit", strconv.Itoa(count))
+		var response struct {
+			Results struct {
+				Matches struct {
+					Artist []struct {
+						Name      string `json:"name"`
+						Listeners string `json:"listeners"`
+						URL       string `json:"url"`
+					} `json:"artist"`
+				} `json:"artistmatches"`
+			} `json:"results"`
+		}
+		if err := es.call(ctx, "artist.search", params, &response); err != nil {
+			return nil, err
+		}
+		for _, a := range response.Results.Matches.Artist {
+			entries = append(entries, entry{Kind: KindArtist, Name: a.Name, URL: a.URL})
+			listeners = append(listeners, a.Listeners)
+		}
+	case KindTrack:
+		params := url.Values{}
+		params.Set("track", query)
+		params.Set("limit", strconv.Itoa(count))
+		var response struct {
+			Results struct {
+				Matches struct {
+					Track []struct {
+						Name      string `json:"name"`
+						Artist    string `json:"artist"`
+						Listeners string `json:"listeners"`
+						URL       string `json:"url"`
+					} `json:"track"`
+				} `json:"trackmatches"`
+			} `json:"results"`
+		}
+		if err := es.call(ctx, "track.search", params, &response); err != nil {
+			return nil, err
+		}
+		for _, t := range response.Results.Matches.Track {
+			entries = append(entries, entry{Kind: KindTrack, Name: t.Name, Artist: t.Artist, URL: t.URL})
+			listeners = append(listeners, t.Listeners)
+		}
+	case KindTag:
+		// Last.fm has no tag search; the query is looked up as a tag name
+		params := url.Values{}
+		params.Set("tag", query)
+		var response struct {
+			Tag struct {
+				Name  string `json:"name"`
+				Reach int64  `json:"reach"`
+			} `json:"tag"`
+		}
+		if err := es.call(ctx, "tag.getInfo", params, &response); err != nil {
+			return nil, err
+		}
+		if response.Tag.Name != "" && response.Tag.Reach > 0 {
+			entries = append(entries, entry{Kind: KindTag, Name: response.Tag.Name, URL: "https://www.last.fm/tag/" + url.PathEscape(response.Tag.Name)})
+			listeners = append(listeners, strconv.FormatInt(response.Tag.Reach, 10))
+		}
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(entries))
+	for i, e := range entries {
+		if len(results) >= count {
+			break
+		}
+		title := e.Name
+		if e.Artist != "" {
+			title += " — " + e.Artist
+		}
+		unit := "listeners"
+		if e.Kind == KindTag {
+			unit = "users tagging"
+		}
+		title = fmt.Sprintf("%s (%s %s)", title, listeners[i], unit)
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: e.URL,
+			Site:      e.Kind,
+			TopicID:   es.entries.Put(e.Kind+"/"+e.Artist+"/"+e.Name, e),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the bio or wiki summary with listener statistics and top tags, then a list of
+// similar artists or tracks (for tags, the tag's top artists)
+func (es *DataSourceLastFM) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	e, ok := es.entries.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Last.fm topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var summary string
+	var related []string
+	var relatedLabel string
+	var err error
+	switch e.Kind {
+	case KindArtist:
+		summary, related, err = es.artistInfo(ctx, e)
+		relatedLabel = "Similar artists to " + e.Name
+	case KindTrack:
+		summary, related, err = es.trackInfo(ctx, e)
+		relatedLabel = fmt.Sprintf("Similar tracks to %s — %s", e.Name, e.Artist)
+	default:
+		summary, related, err = es.tagInfo(ctx, e)
+		relatedLabel = "Top artists tagged " + e.Name
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := []datasource.DataSourceData{{
+		DataText:  summary,
+		SourceURL: e.URL,
+		Site:      e.Kind,
+		AnswerID:  topicID,
+	}}
+	if count != 1 && len(related) > 0 {
+		results = append(results, datasource.DataSourceData{
+			DataText:  relatedLabel + ":\n" + strings.Join(related, "\n"),
+			SourceURL: e.URL,
+			Site:      e.Kind,
+			AnswerID:  topicid.Hash(e.Kind + "/" + e.Artist + "/" + e.Name + "#related"),
+		})
+	}
+	return results, nil
+}
+
+func (es *DataSourceLastFM) artistInfo(ctx context.Context, e entry) (string, []string, error) {
+	params := url.Values{}
+	params.Set("artist", e.Name)
+	params.Set("autocorrect", "1")
+	var response struct {
+		Artist struct {
+			Name  string `json:"name"`
+			Stats struct {
+				Listeners string `json:"listeners"`
+				Playcount string `json:"playcount"`
+			} `json:"stats"`
+			Similar struct {
+				Artist []named `json:"artist"`
+			} `json:"similar"`
+			Tags struct {
+				Tag []named `json:"tag"`
+			} `json:"tags"`
+			Bio struct {
+				Summary string `json:"summary"`
+			} `json:"bio"`
+		} `json:"artist"`
+	}
+	if err := es.call(ctx, "artist.getInfo", params, &response); err != nil {
+		return "", nil, err
+	}
+	a := response.Artist
+	var b strings.Builder
+	b.WriteString(a.Name)
+	if bio := cleanSummary(a.Bio.Summary); bio != "" {
+		b.WriteString("\n\n" + bio)
+	}
+	fmt.Fprintf(&b, "\n\nListeners: %s\nScrobbles: %s", a.Stats.Listeners, a.Stats.Playcount)
+	if tags := joinNames(a.Tags.Tag); tags != "" {
+		b.WriteString("\nTags: " + tags)
+	}
+
+	related := make([]string, 0, len(a.Similar.Artist))
+	for _, similar := range a.Similar.Artist {
+		related = append(related, similar.Name)
+	}
+	// The info response carries only five similar artists; ask for a longer list when available
+	more := url.Values{}
+	more.Set("artist", e.Name)
+	more.Set("limit", strconv.Itoa(listSize))
+	var similar struct {
+		SimilarArtists struct {
+			Artist []struct {
+				Name  string `json:"name"`
+				Match string `json:"match"`
+			} `json:"artist"`
+		} `json:"similarartists"`
+	}
+	if err := es.call(ctx, "artist.getSimilar", more, &similar); err == nil && len(similar.SimilarArtists.Artist) > 0 {
+		related = related[:0]
+		for _, s := range similar.SimilarArtists.Artist {
+			related = append(related, fmt.Sprintf("%s (match %s)", s.Name, formatMatch(s.Match)))
+		}
+	}
+	return b.String(), related, nil
+}
+
+func (es *DataSourceLastFM) trackInfo(ctx context.Context, e entry) (string, []string, error) {
+	params := url.Values{}
+	params.Set("artist", e.Artist)
+	params.Set("track", e.Name)
+	params.Set("autocorrect", "1")
+	var response struct {
+		Track struct {
+			Name      string `json:"name"`
+			Duration  string `json:"duration"`
+			Listeners string `json:"listeners"`
+			Playcount string `json:"playcount"`
+			Artist    named  `json:"artist"`
+			Album     *struct {
+				Title string `json:"title"`
+			} `json:"album"`
+			TopTags struct {
+				Tag []named `json:"tag"`
+			} `json:"toptags"`
+			Wiki *struct {
+				Summary string `json:"summary"`
+			} `json:"wiki"`
+		} `json:"track"`
+	}
+	if err := es.call(ctx, "track.getInfo", params, &response); err != nil {
+		return "", nil, err
+	}
+	t := response.Track
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s by %s", t.Name, t.Artist.Name)
+	if t.Album != nil && t.Album.Title != "" {
+		b.WriteString(", from " + t.Album.Title)
+	}
+	if t.Wiki != nil {
+		if wiki := cleanSummary(t.Wiki.Summary); wiki != "" {
+			b.WriteString("\n\n" + wiki)
+		}
+	}
+	fmt.Fprintf(&b, "\n\nListeners: %s\nScrobbles: %s", t.Listeners, t.Playcount)
+	if ms, err := strconv.ParseInt(t.Duration, 10, 64); err == nil && ms > 0 {
+		fmt.Fprintf(&b, "\nDuration: %d:%02d", ms/60000, ms/1000%60)
+	}
+	if tags := joinNames(t.TopTags.Tag); tags != "" {
+		b.WriteString("\nTags: " + tags)
+	}
+
+	similarParams := url.Values{}
+	similarParams.Set("artist", e.Artist)
+	similarParams.Set("track", e.Name)
+	similarParams.Set("limit", strconv.Itoa(listSize))
+	var similar struct {
+		SimilarTracks struct {
+			Track []struct {
+				Name   string `json:"name"`
+				Artist named  `json:"artist"`
+			} `json:"track"`
+		} `json:"similartracks"`
+	}
+	var related []string
+	if err := es.call(ctx, "track.getSimilar", similarParams, &similar); err == nil {
+		for _, s := range similar.SimilarTracks.Track {
+			related = append(related, s.Name+" — "+s.Artist.Name)
+		}
+	}
+	return b.String(), related, nil
+}
+
+func (es *DataSourceLastFM) tagInfo(ctx context.Context, e entry) (string, []string, error) {
+	params := url.Values{}
+	params.Set("tag", e.Name)
+	var response struct {
+		Tag struct {
+			Name  string `json:"name"`
+			Reach int64  `json:"reach"`
+			Total int64  `json:"total"`
+			Wiki  struct {
+				Summary string `json:"summary"`
+			} `json:"wiki"`
+		} `json:"tag"`
+	}
+	if err := es.call(ctx, "tag.getInfo", params, &response); err != nil {
+		return "", nil, err
+	}
+	t := response.Tag
+	var b strings.Builder
+	b.WriteString(t.Name)
+	if wiki := cleanSummary(t.Wiki.Summary); wiki != "" {
+		b.WriteString("\n\n" + wiki)
+	}
+	fmt.Fprintf(&b, "\n\nUsers tagging: %d\nTimes applied: %d", t.Reach, t.Total)
+
+	topParams := url.Values{}
+	topParams.Set("tag", e.Name)
+	topParams.Set("limit", strconv.Itoa(listSize))
+	var top struct {
+		TopArtists struct {
+			Artist []named `json:"artist"`
+		} `json:"topartists"`
+	}
+	var related []string
+	if err := es.call(ctx, "tag.getTopArtists", topParams, &top); err == nil {
+		for _, a := range top.TopArtists.Artist {
+			related = append(related, a.Name)
+		}
+	}
+	return b.String(), related, nil
+}
+
+// call invokes a Last.fm API method and decodes the JSON response into target.
+// Method errors can arrive with status 200 as {"error": 6, "message": "..."}.
+func (es *DataSourceLastFM) call(ctx context.Context, method string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	params.Set("method", method)
+	params.Set("api_key", es.APIKey)
+	params.Set("format", "json")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, es.BaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var failure struct {
+		Error   int    `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &failure) == nil && failure.Error != 0 {
+		return fmt.Errorf("lastfm %s failed: error %d: %s", method, failure.Error, failure.Message)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if len(body) > 4096 {
+			body = body[:4096]
+		}
+		return fmt.Errorf("lastfm request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, target)
+}
+
+// Helpers
+func cleanSummary(summary string) string {
+	summary = readMore.ReplaceAllString(summary, "")
+	return strings.TrimSpace(htmlTag.ReplaceAllString(summary, ""))
+}
+
+func joinNames(items []named) string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatMatch renders a similarity score such as "0.873" as a percentage
+func formatMatch(match string) string {
+	value, err := strconv.ParseFloat(match, 64)
+	if err != nil {
+		return match
+	}
+	return fmt.Sprintf("%.0f%%", value*100)
+}
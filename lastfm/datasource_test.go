@@ -0,0 +1,82 @@
+package lastfm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("api_key") != "key" || q.Get("format") != "json" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch q.Get("method") {
+		case "artist.search":
+			if q.Get("artist") != "radiohead" || q.Get("limit") != "5" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"results":{"artistmatches":{"artist":[{"name":"Radiohead","listeners":"6000000","url":"https://www.last.fm/music/Radiohead"}]}}}`)
+		case "artist.getInfo":
+			if q.Get("artist") != "Radiohead" || q.Get("autocorrect") != "1" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"artist":{"name":"Radiohead","stats":{"listeners":"6000000","playcount":"900000000"},
+				"similar":{"artist":[{"name":"Thom Yorke"}]},"tags":{"tag":[{"name":"alternative"},{"name":"rock"}]},
+				"bio":{"summary":"Radiohead are an <b>English</b> rock band. <a href=\"https://www.last.fm/music/Radiohead\">Read more on Last.fm</a>"}}}`)
+		case "artist.getSimilar":
+			fmt.Fprint(w, `{"similarartists":{"artist":[{"name":"Thom Yorke","match":"1"},{"name":"Muse","match":"0.873"}]}}`)
+		case "tag.getInfo":
+			fmt.Fprint(w, `{"error":6,"message":"Tag not found"}`)
+		default:
+			t.Errorf("unexpected method %q", q.Get("method"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.APIKey = "key"
+
+	topics, err := es.FetchTopics(5, "radiohead")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Radiohead (6000000 listeners)" || topics[0].Site != KindArtist {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Radiohead\n\nRadiohead are an English rock band.\n\nListeners: 6000000\nScrobbles: 900000000\nTags: alternative, rock",
+		"Similar artists to Radiohead:\nThom Yorke (match 100%)\nMuse (match 87%)",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+}
+
+func TestMethodError(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.APIKey = "key"
+	es.Kind = KindTag
+	_, err := es.FetchTopics(5, "nonexistent")
+	if err == nil || err.Error() != "lastfm tag.getInfo failed: error 6: Tag not found" {
+		t.Errorf("FetchTopics error = %v", err)
+	}
+}
@@ -0,0 +1,330 @@
+package lemmy
+
+// Data Source Adapter for Lemmy federated forums (API v3)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceLemmy struct {
+	Client        *http.Client
+	BaseURL       string // Instance root, e.g. https://lemmy.world
+	UserAgent     string
+	Token         string // Optional JWT for instances that require login
+	Community     string // Restrict searches to this community name when set
+	Sort          string // Lemmy sort type, e.g. TopAll, New, Hot
+	IncludeGroups bool   // Also return matching communities as topics
+
+	refs topicid.Map[ref]
+}
+
+// ref identifies the Lemmy object behind a topic, since posts and communities share an ID space
+type ref struct {
+	Kind string
+	ID   int64
+	URL  string
+	Site string
+}
+
+func New() *DataSourceLemmy {
+	return &DataSourceLemmy{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://lemmy.world",
+		UserAgent: "locus/lemmy-datasource",
+		Sort:      "TopAll",
+	}
+}
+
+// Init implements models.DataSource
+// Lemmy requires no initialization beyond an instance URL
+func (es *DataSourceLemmy) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if strings.TrimSpace(es.BaseURL) == "" {
+		return errors.New("BaseURL is required for Lemmy DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceLemmy) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	return es.doJSON(ctx, "/api/v3/site", nil, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceLemmy) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Lemmy",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Searches posts (and optionally communities); the community name is reported as the Site
+func (es *DataSourceLemmy) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Lemmy DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("type_", "Posts")
+	if es.IncludeGroups {
+		params.Set("type_", "All")
+	}
+	params.Set("limit", strconv.Itoa(count))
+	params.Set("listing_type", "All")
+	if es.Sort != "" {
+		params.Set("sort", es.Sort)
+	}
+	if es.Community != "" {
+		params.Set("community_name", es.Community)
+	}
+
+	var response struct {
+		Posts []struct {
+			Post struct {
+				ID   int64  `json:"id"`
+				Name string `json:"name"`
+			} `json:"post"`
+			Community struct {
+				Name string `json:"name"`
+			} `json:"community"`
+		} `json:"posts"`
+		Communities []struct {
+			Community struct {
+				ID    int64  `json:"id"`
+				Name  string `json:"name"`
+				Title string `json:"title"`
+			} `json:"community"`
+		} `json:"communities"`
+	}
+	if err := es.doJSON(ctx, "/api/v3/search", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, view := range response.Communities {
+		if len(results) >= count {
+			break
+		}
+		community := view.Community
+		link := es.localLink("/c/" + community.Name)
+		id := es.refs.Put(fmt.Sprintf("community:%d", community.ID), ref{Kind: "community", ID: community.ID, URL: link, Site: community.Name})
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     community.Title,
+			SourceURL: link,
+			Site:      community.Name,
+			TopicID:   id,
+		})
+	}
+	for _, view := range response.Posts {
+		if len(results) >= count {
+			break
+		}
+		post := view.Post
+		link := es.localLink(fmt.Sprintf("/post/%d", post.ID))
+		id := es.refs.Put(fmt.Sprintf("post:%d", post.ID), ref{Kind: "post", ID: post.ID, URL: link, Site: view.Community.Name})
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     post.Name,
+			SourceURL: link,
+			Site:      view.Community.Name,
+			TopicID:   id,
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Posts return their body followed by the top comments; communities return their description
+func (es *DataSourceLemmy) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	target, ok := es.refs.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Lemmy topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	if target.Kind == "community" {
+		return es.fetchCommunity(ctx, target)
+	}
+	return es.fetchPost(ctx, count, target)
+}
+
+func (es *DataSourceLemmy) fetchCommunity(ctx context.Context, target ref) ([]datasource.DataSourceData, error) {
+	params := url.Values{}
+	params.Set("id", strconv.FormatInt(target.ID, 10))
+	var response struct {
+		CommunityView struct {
+			Community struct {
+				Description string `json:"description"`
+			} `json:"community"`
+		} `json:"community_view"`
+	}
+	if err := es.doJSON(ctx, "/api/v3/community", params, &response); err != nil {
+		return nil, err
+	}
+	description := strings.TrimSpace(response.CommunityView.Community.Description)
+	if description == "" {
+		return []datasource.DataSourceData{}, nil
+	}
+	return []datasource.DataSourceData{{
+		DataText:  description,
+		SourceURL: target.URL,
+		Site:      target.Site,
+		AnswerID:  target.ID,
+	}}, nil
+}
+
+func (es *DataSourceLemmy) fetchPost(ctx context.Context, count int, target ref) ([]datasource.DataSourceData, error) {
+	results := make([]datasource.DataSourceData, 0, count+1)
+
+	params := url.Values{}
+	params.Set("id", strconv.FormatInt(target.ID, 10))
+	var post struct {
+		PostView struct {
+			Post struct {
+				Body string `json:"body"`
+				URL  string `json:"url"`
+			} `json:"post"`
+		} `json:"post_view"`
+	}
+	if err := es.doJSON(ctx, "/api/v3/post", params, &post); err != nil {
+		return nil, err
+	}
+	body := strings.TrimSpace(post.PostView.Post.Body)
+	if body == "" && post.PostView.Post.URL != "" {
+		body = post.PostView.Post.URL
+	}
+	if body != "" {
+		results = append(results, datasource.DataSourceData{
+			DataText:  body,
+			SourceURL: target.URL,
+			Site:      target.Site,
+			AnswerID:  target.ID,
+		})
+	}
+
+	params = url.Values{}
+	params.Set("post_id", strconv.FormatInt(target.ID, 10))
+	params.Set("sort", "Top")
+	params.Set("max_depth", "1")
+	params.Set("limit", strconv.Itoa(count))
+	params.Set("type_", "All")
+	var comments struct {
+		Comments []struct {
+			Comment struct {
+				ID      int64  `json:"id"`
+				Content string `json:"content"`
+				Deleted bool   `json:"deleted"`
+				Removed bool   `json:"removed"`
+			} `json:"comment"`
+			Creator struct {
+				Name string `json:"name"`
+			} `json:"creator"`
+			Counts struct {
+				Score int `json:"score"`
+			} `json:"counts"`
+		} `json:"comments"`
+	}
+	if err := es.doJSON(ctx, "/api/v3/comment/list", params, &comments); err != nil {
+		return nil, err
+	}
+	for _, view := range comments.Comments {
+		if len(results) >= count {
+			break
+		}
+		comment := view.Comment
+		content := strings.TrimSpace(comment.Content)
+		if comment.Deleted || comment.Removed || content == "" {
+			continue
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  fmt.Sprintf("%s (%d points):\n%s", view.Creator.Name, view.Counts.Score, content),
+			SourceURL: es.localLink(fmt.Sprintf("/comment/%d", comment.ID)),
+			Site:      target.Site,
+			AnswerID:  comment.ID,
+		})
+	}
+	return results, nil
+}
+
+// localLink builds a link on the configured instance so federated content opens locally
+func (es *DataSourceLemmy) localLink(path string) string {
+	return strings.TrimRight(es.BaseURL, "/") + path
+}
+
+// doJSON performs an HTTP GET request against the Lemmy API and decodes the JSON response into target
+func (es *DataSourceLemmy) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	if es.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+es.Token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("lemmy request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
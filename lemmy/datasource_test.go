@@ -0,0 +1,96 @@
+package lemmy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "rust" || q.Get("type_") != "All" || q.Get("community_name") != "programming" || q.Get("sort") != "TopAll" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("Authorization") != "Bearer jwt" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, `{
+			"communities":[{"community":{"id":7,"name":"rust","title":"Rust Programming"}}],
+			"posts":[{"post":{"id":7,"name":"Rust 2024 edition"},"community":{"name":"programming"}}]
+		}`)
+	})
+	mux.HandleFunc("/api/v3/community", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"community_view":{"community":{"description":" All things Rust "}}}`)
+	})
+	mux.HandleFunc("/api/v3/post", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") != "7" {
+			t.Errorf("post id = %q", r.URL.Query().Get("id"))
+		}
+		fmt.Fprint(w, `{"post_view":{"post":{"body":"","url":"https://blog.rust-lang.org/"}}}`)
+	})
+	mux.HandleFunc("/api/v3/comment/list", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("post_id") != "7" || r.URL.Query().Get("max_depth") != "1" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"comments":[
+			{"comment":{"id":20,"content":"gone","removed":true}},
+			{"comment":{"id":21,"content":"Finally!"},"creator":{"name":"ferris"},"counts":{"score":42}}
+		]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.Token = "jwt"
+	es.Community = "programming"
+	es.IncludeGroups = true
+
+	topics, err := es.FetchTopics(5, "rust")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	community, post := topics[0], topics[1]
+	if community.Topic != "Rust Programming" || community.SourceURL != srv.URL+"/c/rust" || community.Site != "rust" {
+		t.Errorf("community = %+v", community)
+	}
+	if post.Topic != "Rust 2024 edition" || post.SourceURL != srv.URL+"/post/7" || post.Site != "programming" {
+		t.Errorf("post = %+v", post)
+	}
+	if community.TopicID == post.TopicID {
+		t.Error("a community and a post with the same Lemmy ID share a topic ID")
+	}
+
+	data, err := es.FetchData(5, community.TopicID)
+	if err != nil || len(data) != 1 || data[0].DataText != "All things Rust" {
+		t.Errorf("community FetchData = %+v, %v", data, err)
+	}
+
+	data, err = es.FetchData(5, post.TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 || data[0].DataText != "https://blog.rust-lang.org/" || data[1].DataText != "ferris (42 points):\nFinally!" {
+		t.Fatalf("post FetchData = %+v", data)
+	}
+	if data[1].AnswerID != 21 || data[1].SourceURL != srv.URL+"/comment/21" {
+		t.Errorf("comment = %+v", data[1])
+	}
+}
+
+func TestFetchDataUnknownTopic(t *testing.T) {
+	if _, err := New().FetchData(5, 12345); err == nil {
+		t.Error("FetchData succeeded for a topic that was never returned")
+	}
+}
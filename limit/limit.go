@@ -0,0 +1,187 @@
+// Package limit caps in-flight requests, per source and across every source
+// in a process, so a burst of queries cannot open hundreds of simultaneous
+// connections to Wikipedia or DuckDuckGo. Limiter wraps a source with its own
+// semaphore and, optionally, a Semaphore shared by all wrapped sources; wrap
+// each member of a composite with the same Global to cap the whole set.
+package limit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+// ErrBusy is returned when no slot frees up within the Limiter's Wait
+var ErrBusy = errors.New("limit: too many requests in flight")
+
+// Semaphore bounds how many holders run at once
+type Semaphore struct {
+	slots chan struct{}
+}
+
+// NewSemaphore returns a semaphore with n slots
+func NewSemaphore(n int) *Semaphore {
+	if n <= 0 {
+		n = 1
+	}
+	return &Semaphore{slots: make(chan struct{}, n)}
+}
+
+// Acquire takes a slot, waiting until one is free or ctx ends
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release returns a slot taken by Acquire
+func (s *Semaphore) Release() {
+	<-s.slots
+}
+
+// InFlight returns the number of slots taken
+func (s *Semaphore) InFlight() int {
+	return len(s.slots)
+}
+
+// Limiter wraps a source and limits its in-flight calls to Max, and the in-flight calls of all sources
+// sharing Global to Global's size. It implements source.Source, source.ContextSource,
+// source.OptionsSource and source.Pager; calls wait for a slot under the caller's context.
+type Limiter struct {
+	Source source.Source
+	Name   string        // Used in errors
+	Max    int           // Calls in flight for this source; 0 for no per-source limit
+	Global *Semaphore    // Shared across sources, e.g. every member of a composite; nil for none
+	Wait   time.Duration // How long a call waits for a slot before failing with ErrBusy; default timeout.Topics, Data or Check
+
+	once  sync.Once
+	local *Semaphore
+}
+
+// New wraps s, allowing n calls in flight
+func New(name string, s source.Source, n int) *Limiter {
+	return &Limiter{Source: s, Name: name, Max: n}
+}
+
+// Init implements models.DataSource
+func (l *Limiter) Init() error {
+	return l.Source.Init()
+}
+
+// CheckAvailability implements models.DataSource
+func (l *Limiter) CheckAvailability() bool {
+	release, err := l.acquire(context.Background(), timeout.Check)
+	if err != nil {
+		return false
+	}
+	defer release()
+	return l.Source.CheckAvailability()
+}
+
+// Unwrap implements source.Wrapper
+func (l *Limiter) Unwrap() source.Source {
+	return l.Source
+}
+
+// FetchTopics implements models.DataSource
+func (l *Limiter) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return l.FetchTopicsContext(context.Background(), count, input)
+}
+
+// FetchTopicsContext implements source.ContextSource
+func (l *Limiter) FetchTopicsContext(ctx context.Context, count int, input string) ([]datasource.DataSourceTopic, error) {
+	release, err := l.acquire(ctx, timeout.Topics)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return source.FetchTopicsContext(ctx, l.Source, count, input)
+}
+
+// FetchTopicsWithOptions implements source.OptionsSource
+func (l *Limiter) FetchTopicsWithOptions(ctx context.Context, query string, opts source.TopicOptions) ([]datasource.DataSourceTopic, error) {
+	release, err := l.acquire(ctx, timeout.Topics)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return source.FetchTopicsWithOptions(ctx, l.Source, query, opts)
+}
+
+// FetchTopicsPage implements source.Pager
+func (l *Limiter) FetchTopicsPage(ctx context.Context, query string, opts source.TopicOptions, cursor string) (source.Page, error) {
+	release, err := l.acquire(ctx, timeout.Topics)
+	if err != nil {
+		return source.Page{}, err
+	}
+	defer release()
+	return source.FetchTopicsPage(ctx, l.Source, query, opts, cursor)
+}
+
+// FetchData implements models.DataSource
+func (l *Limiter) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return l.FetchDataContext(context.Background(), count, topicID)
+}
+
+// FetchDataContext implements source.ContextSource
+func (l *Limiter) FetchDataContext(ctx context.Context, count int, topicID int64) ([]datasource.DataSourceData, error) {
+	release, err := l.acquire(ctx, timeout.Data)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return source.FetchDataContext(ctx, l.Source, count, topicID)
+}
+
+// acquire takes the per-source slot, then the global one, within Wait or else the operation's deadline.
+// It fails with ctx's error when ctx ends first.
+func (l *Limiter) acquire(ctx context.Context, deadline time.Duration) (func(), error) {
+	l.once.Do(func() {
+		if l.Max > 0 {
+			l.local = NewSemaphore(l.Max)
+		}
+	})
+	wait := l.Wait
+	if wait <= 0 {
+		wait = deadline
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	var held []*Semaphore
+	release := func() {
+		for _, s := range held {
+			s.Release()
+		}
+	}
+	for _, s := range []*Semaphore{l.local, l.Global} {
+		if s == nil {
+			continue
+		}
+		if err := s.Acquire(waitCtx); err != nil {
+			release()
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("%s: %w", l.name(), ErrBusy)
+		}
+		held = append(held, s)
+	}
+	return release, nil
+}
+
+func (l *Limiter) name() string {
+	if l.Name == "" {
+		return "source"
+	}
+	return l.Name
+}
@@ -0,0 +1,161 @@
+package limit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+// gated is a source whose fetches block until gate is closed or their context ends
+type gated struct {
+	gate    chan struct{}
+	started chan struct{}
+	opts    source.TopicOptions
+}
+
+func newGated() *gated {
+	return &gated{gate: make(chan struct{}), started: make(chan struct{}, 10)}
+}
+
+func (g *gated) Init() error             { return nil }
+func (g *gated) CheckAvailability() bool { return true }
+
+func (g *gated) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return g.FetchTopicsContext(context.Background(), count, input)
+}
+
+func (g *gated) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return g.FetchDataContext(context.Background(), count, topicID)
+}
+
+func (g *gated) FetchTopicsContext(ctx context.Context, count int, input string) ([]datasource.DataSourceTopic, error) {
+	return []datasource.DataSourceTopic{{TopicID: 1, Topic: input}}, nil
+}
+
+func (g *gated) FetchTopicsWithOptions(ctx context.Context, query string, opts source.TopicOptions) ([]datasource.DataSourceTopic, error) {
+	g.opts = opts
+	return []datasource.DataSourceTopic{{TopicID: 1, Topic: query}}, nil
+}
+
+func (g *gated) FetchDataContext(ctx context.Context, count int, topicID int64) ([]datasource.DataSourceData, error) {
+	g.started <- struct{}{}
+	select {
+	case <-g.gate:
+		return []datasource.DataSourceData{{DataText: "data", AnswerID: topicID}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// hold starts a FetchData through l that blocks until g's gate opens, and returns its error channel
+func hold(t *testing.T, l *Limiter, g *gated) chan error {
+	t.Helper()
+	errs := make(chan error, 1)
+	go func() {
+		_, err := l.FetchData(1, 1)
+		errs <- err
+	}()
+	<-g.started
+	return errs
+}
+
+func TestLimiterBusy(t *testing.T) {
+	g := newGated()
+	l := New("gated", g, 1)
+	l.Wait = 20 * time.Millisecond
+	held := hold(t, l, g)
+
+	if _, err := l.FetchData(1, 2); !errors.Is(err, ErrBusy) {
+		t.Errorf("FetchData with no free slot = %v, want ErrBusy", err)
+	}
+	close(g.gate)
+	if err := <-held; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.FetchData(1, 2); err != nil {
+		t.Errorf("FetchData after the slot was released = %v", err)
+	}
+}
+
+func TestLimiterCallerContext(t *testing.T) {
+	g := newGated()
+	l := New("gated", g, 1)
+	held := hold(t, l, g)
+	defer close(g.gate)
+
+	// Waiting for a slot ends with the caller's context, not the default wait
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := l.FetchDataContext(ctx, 1, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FetchDataContext = %v, want the caller's deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("FetchDataContext waited %v", elapsed)
+	}
+	select {
+	case err := <-held:
+		t.Fatalf("held fetch ended early: %v", err)
+	default:
+	}
+}
+
+func TestLimiterReleasesCanceledFetch(t *testing.T) {
+	g := newGated()
+	l := New("gated", g, 1)
+	l.Wait = 50 * time.Millisecond
+
+	// A fetch canceled mid-call gives its slot back when the source returns
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := l.FetchDataContext(ctx, 1, 1)
+		errs <- err
+	}()
+	<-g.started
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled FetchDataContext = %v", err)
+	}
+	close(g.gate)
+	if _, err := l.FetchData(1, 2); err != nil {
+		t.Errorf("FetchData after a canceled fetch = %v", err)
+	}
+}
+
+func TestLimiterGlobal(t *testing.T) {
+	global := NewSemaphore(1)
+	a, b := newGated(), newGated()
+	la, lb := New("a", a, 0), New("b", b, 0)
+	la.Global, lb.Global = global, global
+	lb.Wait = 20 * time.Millisecond
+	held := hold(t, la, a)
+
+	if _, err := lb.FetchData(1, 1); !errors.Is(err, ErrBusy) {
+		t.Errorf("FetchData on another source sharing the global slot = %v, want ErrBusy", err)
+	}
+	close(a.gate)
+	<-held
+	if n := global.InFlight(); n != 0 {
+		t.Errorf("%d global slots held after the fetch", n)
+	}
+}
+
+func TestLimiterForwardsOptions(t *testing.T) {
+	g := newGated()
+	l := New("gated", g, 1)
+	opts := source.TopicOptions{Count: 3, Language: "de"}
+	if _, err := source.FetchTopicsWithOptions(context.Background(), l, "query", opts); err != nil {
+		t.Fatal(err)
+	}
+	if g.opts != opts {
+		t.Errorf("source got options %+v, want %+v", g.opts, opts)
+	}
+	if c := source.Describe(l); !c.Context {
+		t.Errorf("Describe = %+v, want Context", c)
+	}
+}
@@ -0,0 +1,287 @@
+package loc
+
+// Data Source Adapter for the Library of Congress digital collections (loc.gov JSON API)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/time/rate"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceLoC struct {
+	Client    *http.Client
+	BaseURL   string
+	UserAgent string
+	Format    string // Restrict to one format endpoint, e.g. "photos", "newspapers", "manuscripts", "maps", "audio"
+	DateRange string // e.g. "1900/1950"
+
+	items       topicid.Map[string]
+	rateLimiter *rate.Limiter
+}
+
+// multi decodes fields that loc.gov sends either as a string or as an array of strings
+type multi []string
+
+func (m *multi) UnmarshalJSON(data []byte) error {
+	var many []string
+	if err := json.Unmarshal(data, &many); err == nil {
+		*m = many
+		return nil
+	}
+	var one string
+	if err := json.Unmarshal(data, &one); err != nil {
+		// Some fields hold objects for rare items; they carry nothing worth showing
+		*m = nil
+		return nil
+	}
+	*m = multi{one}
+	return nil
+}
+
+func New() *DataSourceLoC {
+	return &DataSourceLoC{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://www.loc.gov",
+		UserAgent: "locus/loc-datasource",
+	}
+}
+
+// Init implements models.DataSource
+// loc.gov blocks clients that exceed about 20 requests per 10 seconds, so requests are paced at 2 per second
+func (es *DataSourceLoC) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.rateLimiter == nil {
+		es.rateLimiter = rate.NewLimiter(rate.Limit(2), 1)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceLoC) CheckAvailability() bool {
+	es.Init()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", "library")
+	params.Set("c", "1")
+	return es.doJSON(ctx, es.searchURL(params), &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceLoC) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "Library of Congress",
+		Homepage:    "https://www.loc.gov",
+		Attribution: "Library of Congress",
+		TermsURL:    "https://www.loc.gov/legal/",
+	}
+}
+
+// Describe implements source.Describer
+func (es *DataSourceLoC) Describe() source.Capabilities {
+	return source.Capabilities{Name: es.Info().Name, Data: true, RateLimit: source.RateLimit{Requests: 2, Per: time.Second}}
+}
+
+// FetchTopics implements models.DataSource
+// Each item is a topic titled "Title (date; original format)"
+func (es *DataSourceLoC) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Library of Congress DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	es.Init()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("c", strconv.Itoa(count))
+	if es.DateRange != "" {
+		params.Set("dates", es.DateRange)
+	}
+	var response struct {
+		Results []struct {
+			ID             string `json:"id"`
+			Title          string `json:"title"`
+			Date           string `json:"date"`
+			URL            string `json:"url"`
+			OriginalFormat multi  `json:"original_format"`
+			PartOf         multi  `json:"partof"`
+		} `json:"results"`
+	}
+	if err := es.doJSON(ctx, es.searchURL(params), &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Results))
+	for _, item := range response.Results {
+		// Search results also include collection and web pages, which have no item record
+		if !strings.Contains(item.ID, "/item/") && !strings.Contains(item.ID, "/resource/") {
+			continue
+		}
+		var details []string
+		if item.Date != "" {
+			details = append(details, item.Date)
+		}
+		if len(item.OriginalFormat) > 0 {
+			details = append(details, strings.Join(item.OriginalFormat, ", "))
+		}
+		title := item.Title
+		if len(details) > 0 {
+			title = fmt.Sprintf("%s (%s)", item.Title, strings.Join(details, "; "))
+		}
+		site := ""
+		if len(item.PartOf) > 0 {
+			site = item.PartOf[0]
+		}
+		link := item.URL
+		if link == "" {
+			link = item.ID
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: link,
+			Site:      site,
+			TopicID:   es.items.Put(item.ID, item.ID),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the item's catalog record: summary, descriptions and notes, followed by creators, subjects and rights
+func (es *DataSourceLoC) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	id, ok := es.items.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Library of Congress topicID %d", topicID)
+	}
+	es.Init()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	uri, err := url.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	uri.Scheme = "https"
+	params := uri.Query()
+	params.Set("fo", "json")
+	uri.RawQuery = params.Encode()
+
+	var response struct {
+		Item struct {
+			Title            string `json:"title"`
+			Date             string `json:"date"`
+			Summary          multi  `json:"summary"`
+			Description      multi  `json:"description"`
+			Notes            multi  `json:"notes"`
+			ContributorNames multi  `json:"contributor_names"`
+			SubjectHeadings  multi  `json:"subject_headings"`
+			Medium           multi  `json:"medium"`
+			Location         multi  `json:"location"`
+			RightsAdvisory   multi  `json:"rights_advisory"`
+		} `json:"item"`
+	}
+	if err := es.doJSON(ctx, uri.String(), &response); err != nil {
+		return nil, err
+	}
+
+	item := response.Item
+	var b strings.Builder
+	b.WriteString(item.Title)
+	if item.Date != "" {
+		b.WriteString(" (" + item.Date + ")")
+	}
+	for _, block := range [][]string{item.Summary, item.Description, item.Notes} {
+		for _, paragraph := range block {
+			if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+				b.WriteString("\n\n" + paragraph)
+			}
+		}
+	}
+	b.WriteString("\n")
+	for _, field := range []struct {
+		label  string
+		values multi
+	}{
+		{"Contributors", item.ContributorNames},
+		{"Subjects", item.SubjectHeadings},
+		{"Medium", item.Medium},
+		{"Location", item.Location},
+		{"Rights", item.RightsAdvisory},
+	} {
+		if len(field.values) > 0 {
+			fmt.Fprintf(&b, "\n%s: %s", field.label, strings.Join(field.values, "; "))
+		}
+	}
+
+	return []datasource.DataSourceData{{
+		DataText:  strings.TrimSpace(b.String()),
+		SourceURL: strings.Replace(id, "http://", "https://", 1),
+		AnswerID:  topicID,
+	}}, nil
+}
+
+func (es *DataSourceLoC) searchURL(params url.Values) string {
+	params.Set("fo", "json")
+	path := "/search/"
+	if es.Format != "" {
+		path = "/" + url.PathEscape(es.Format) + "/"
+	}
+	return strings.TrimRight(es.BaseURL, "/") + path + "?" + params.Encode()
+}
+
+// doJSON waits for the rate limiter, then performs an HTTP GET request and decodes the JSON response into target
+func (es *DataSourceLoC) doJSON(ctx context.Context, uri string, target interface{}) error {
+	if es.rateLimiter != nil {
+		if err := es.rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("loc.gov request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
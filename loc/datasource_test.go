@@ -0,0 +1,71 @@
+package loc
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+// newSource serves loc.gov over TLS, since item records are always requested with https
+func newSource(t *testing.T) *DataSourceLoC {
+	t.Helper()
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/photos/", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "bridge" || q.Get("c") != "5" || q.Get("fo") != "json" || q.Get("dates") != "1900/1950" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		// Item IDs use http, as on loc.gov
+		base := strings.Replace(srv.URL, "https://", "http://", 1)
+		fmt.Fprintf(w, `{"results":[
+			{"id":"%[1]s/collections/bridges/","title":"Bridges collection"},
+			{"id":"%[1]s/item/2017123/","title":"Brooklyn Bridge","date":"1910","original_format":["photo, print, drawing"],"partof":["detroit publishing company"],"url":"%[1]s/item/2017123/"}
+		]}`, base)
+	})
+	mux.HandleFunc("/item/2017123/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fo") != "json" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"item":{"title":"Brooklyn Bridge","date":"1910","summary":"View from Manhattan.","description":["Photograph.",""],
+			"notes":{"unexpected":"object"},"contributor_names":["Detroit Publishing Co."],"subject_headings":["Bridges","New York"],"rights_advisory":"No known restrictions"}}`)
+	})
+	srv = httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+
+	es := New()
+	es.BaseURL = srv.URL
+	es.Client = srv.Client()
+	es.rateLimiter = rate.NewLimiter(rate.Inf, 1)
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	es.Format = "photos"
+	es.DateRange = "1900/1950"
+
+	topics, err := es.FetchTopics(5, "bridge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Brooklyn Bridge (1910; photo, print, drawing)" || topics[0].Site != "detroit publishing company" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Brooklyn Bridge (1910)\n\nView from Manhattan.\n\nPhotograph.\n\nContributors: Detroit Publishing Co.\nSubjects: Bridges; New York\nRights: No known restrictions"
+	if len(data) != 1 || data[0].DataText != want {
+		t.Fatalf("FetchData = %+v, want %q", data, want)
+	}
+	if !strings.HasPrefix(data[0].SourceURL, "https://") {
+		t.Errorf("SourceURL = %q", data[0].SourceURL)
+	}
+}
@@ -0,0 +1,154 @@
+package localfs
+
+// Data Source Adapter for a local directory of Markdown, text and HTML files, for offline deployments
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultDataCount  = 3
+	// File contents are returned in chunks of about this many characters
+	chunkSize = 1500
+)
+
+type DataSourceLocalFS struct {
+	Root       string        // Directory to index
+	Extensions []string      // Indexed file types, e.g. ".md"; DefaultExtensions when empty
+	MaxAge     time.Duration // Age after which the next search rebuilds the index; default 5 minutes
+	Site       string        // Site for topics and data; default "localfs"
+
+	index *Index
+	docs  topicid.Map[Document]
+}
+
+func New() *DataSourceLocalFS {
+	return &DataSourceLocalFS{
+		MaxAge: 5 * time.Minute,
+		Site:   "localfs",
+	}
+}
+
+// Init implements models.DataSource
+// Builds the index of Root
+func (es *DataSourceLocalFS) Init() error {
+	if es.Root == "" {
+		return errors.New("Root is required for LocalFS DataSource")
+	}
+	if es.Site == "" {
+		es.Site = "localfs"
+	}
+	if es.index == nil {
+		root, err := filepath.Abs(es.Root)
+		if err != nil {
+			return err
+		}
+		es.index = NewIndex(root)
+		es.index.Extensions = es.Extensions
+	}
+	if es.index.Built().IsZero() {
+		return es.index.Build()
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceLocalFS) CheckAvailability() bool {
+	info, err := os.Stat(es.Root)
+	return err == nil && info.IsDir()
+}
+
+// Info implements source.Informer
+func (es *DataSourceLocalFS) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name: "Local files",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each matching file is a topic titled by its front matter title, first heading or file name
+func (es *DataSourceLocalFS) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for LocalFS DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+	if es.MaxAge > 0 && time.Since(es.index.Built()) > es.MaxAge {
+		if err := es.index.Build(); err != nil {
+			return nil, err
+		}
+	}
+
+	matches := es.index.Search(query, count)
+	results := make([]datasource.DataSourceTopic, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     m.Title,
+			SourceURL: es.fileURL(m.Path),
+			Site:      es.Site,
+			TopicID:   es.docs.Put(m.Path, m.Document),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the file's text in chunks of about 1500 characters split at paragraph breaks, each starting
+// with the title
+func (es *DataSourceLocalFS) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	doc, ok := es.docs.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown LocalFS topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultDataCount
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, chunk := range Chunks(doc.Body, chunkSize) {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		if len(results) > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%s#%d", doc.Path, len(results)))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  doc.Title + "\n" + chunk,
+			SourceURL: es.fileURL(doc.Path),
+			Site:      es.Site,
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// LastModified implements source.Freshness
+func (es *DataSourceLocalFS) LastModified(topicID int64) time.Time {
+	doc, _ := es.docs.Get(topicID)
+	return doc.ModTime
+}
+
+// fileURL returns a file:// URL for a document path
+func (es *DataSourceLocalFS) fileURL(path string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(es.index.Root, filepath.FromSlash(path)))}
+	return u.String()
+}
@@ -0,0 +1,93 @@
+package localfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeFiles creates files under a temporary root, keyed by slash-separated path
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"guides/backup.md":  "---\ntitle: Backup guide\ntags: [ops, \"#storage\"]\n---\n# Ignored heading\n\nRun the backup job nightly.\n\nVerify restores monthly.\n",
+		"notes/restore.txt": "# Restore notes\n\nRestores need the backup key.\n",
+		"site/index.html":   "<html><head><title>Status page</title></head><body><p>Nothing about the topic here, just status text.</p></body></html>",
+		"image.png":         "backup",
+		".git/backup.md":    "# Hidden backup\n",
+	})
+	es := New()
+	es.Root = root
+
+	topics, err := es.FetchTopics(5, "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Backup guide", "Restore notes"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+	if wantURL := "file://" + filepath.ToSlash(filepath.Join(root, "guides", "backup.md")); topics[0].SourceURL != wantURL {
+		t.Errorf("SourceURL = %q, want %q", topics[0].SourceURL, wantURL)
+	}
+	if es.LastModified(topics[0].TopicID).IsZero() {
+		t.Error("LastModified is zero")
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "Backup guide\n# Ignored heading\n\nRun the backup job nightly.\n\nVerify restores monthly."
+	if len(data) != 1 || data[0].DataText != wantData || data[0].Site != "localfs" {
+		t.Errorf("FetchData = %+v, want %q", data, wantData)
+	}
+}
+
+func TestFetchTopicsRebuilds(t *testing.T) {
+	root := writeFiles(t, map[string]string{"a.md": "# Alpha\n"})
+	es := New()
+	es.Root = root
+	es.MaxAge = time.Nanosecond
+	if topics, err := es.FetchTopics(5, "beta"); err != nil || len(topics) != 0 {
+		t.Fatalf("FetchTopics = %+v, %v", topics, err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "b.md"), []byte("# Beta\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	topics, err := es.FetchTopics(5, "beta")
+	if err != nil || len(topics) != 1 || topics[0].Topic != "Beta" {
+		t.Errorf("FetchTopics after adding a file = %+v, %v", topics, err)
+	}
+}
+
+func TestInitMissingRoot(t *testing.T) {
+	es := New()
+	es.Root = filepath.Join(t.TempDir(), "missing")
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted a missing Root")
+	}
+	if es.CheckAvailability() {
+		t.Error("CheckAvailability reported a missing Root")
+	}
+}
@@ -0,0 +1,361 @@
+package localfs
+
+// Directory indexer shared by the localfs and obsidian adapters
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/locus-search/datasource/internal/readability"
+)
+
+// DefaultExtensions are the file types indexed when Index.Extensions is empty
+var DefaultExtensions = []string{".md", ".markdown", ".txt", ".html", ".htm"}
+
+// Document is one indexed file
+type Document struct {
+	Path    string            // Slash-separated, relative to the index root
+	Title   string            // Front matter title, first heading, HTML title, or the file name
+	Meta    map[string]string // Front matter values; lists are joined with ", "
+	Tags    []string          // From the "tags" front matter key
+	Body    string            // Text without front matter; HTML is reduced to its readable text
+	ModTime time.Time
+
+	words map[string]int // Word counts of the title and body
+}
+
+// Match is a search result
+type Match struct {
+	Document
+	Score int
+}
+
+// Index holds the documents of a directory tree in memory. Hidden files and directories (such as .git
+// or .obsidian) are skipped.
+type Index struct {
+	Root        string
+	Extensions  []string // Lowercase, with the dot; DefaultExtensions when empty
+	MaxFileSize int64    // Larger files are skipped; default 2 MB
+
+	// Transform, when set, adjusts each document after parsing, e.g. to resolve wiki links. Returning
+	// false drops the document.
+	Transform func(doc *Document) bool
+
+	mu    sync.RWMutex
+	docs  []Document
+	built time.Time
+}
+
+// NewIndex returns an index of root with the default extensions
+func NewIndex(root string) *Index {
+	return &Index{Root: root, MaxFileSize: 2 << 20}
+}
+
+// Build walks the root and replaces the indexed documents. Unreadable files are skipped.
+func (ix *Index) Build() error {
+	extensions := ix.Extensions
+	if len(extensions) == 0 {
+		extensions = DefaultExtensions
+	}
+	maxSize := ix.MaxFileSize
+	if maxSize <= 0 {
+		maxSize = 2 << 20
+	}
+
+	var docs []Document
+	err := filepath.WalkDir(ix.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == ix.Root {
+				return err
+			}
+			return nil
+		}
+		if path != ix.Root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !hasExtension(path, extensions) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxSize {
+			return nil
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(ix.Root, path)
+		if err != nil {
+			return nil
+		}
+		doc := Parse(filepath.ToSlash(rel), raw)
+		doc.ModTime = info.ModTime()
+		if ix.Transform != nil && !ix.Transform(&doc) {
+			return nil
+		}
+		doc.words = counts(words(doc.Title + " " + doc.Body))
+		docs = append(docs, doc)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Path < docs[j].Path })
+
+	ix.mu.Lock()
+	ix.docs, ix.built = docs, time.Now()
+	ix.mu.Unlock()
+	return nil
+}
+
+// Built returns when the index was last built, or the zero time
+func (ix *Index) Built() time.Time {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.built
+}
+
+// Documents returns the indexed documents ordered by path
+func (ix *Index) Documents() []Document {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.docs
+}
+
+// Search ranks documents against query. The whole query as a substring of the title, path or body
+// scores highest; then each query word found in the title or body, exactly or, for words of five or
+// more letters, within one typo.
+func (ix *Index) Search(query string, limit int) []Match {
+	phrase := strings.ToLower(strings.TrimSpace(query))
+	terms := words(phrase)
+	if phrase == "" {
+		return nil
+	}
+
+	var matches []Match
+	for _, doc := range ix.Documents() {
+		score := 0
+		if strings.Contains(strings.ToLower(doc.Title), phrase) {
+			score += 20
+		}
+		if strings.Contains(strings.ToLower(doc.Path), phrase) {
+			score += 10
+		}
+		if strings.Contains(strings.ToLower(doc.Body), phrase) {
+			score += 5
+		}
+		title := counts(words(doc.Title))
+		for _, term := range terms {
+			if title[term] > 0 {
+				score += 5
+			}
+			if n := doc.words[term]; n > 0 {
+				score += min(n, 5)
+				continue
+			}
+			if len([]rune(term)) >= 5 && fuzzy(term, doc.words) {
+				score++
+			}
+		}
+		if score > 0 {
+			matches = append(matches, Match{Document: doc, Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Parse builds a document from a file's contents: front matter is split off, HTML is reduced to text,
+// and the title is taken from the front matter, the first heading, or the file name
+func Parse(path string, raw []byte) Document {
+	doc := Document{Path: path}
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".html" || ext == ".htm" {
+		if article, err := readability.FromReader(bytes.NewReader(raw)); err == nil {
+			doc.Title = article.Title
+			doc.Body = article.Text()
+		}
+	} else {
+		doc.Meta, doc.Body = ParseFrontMatter(string(raw))
+		doc.Title = doc.Meta["title"]
+		if doc.Title == "" {
+			doc.Title = firstHeading(doc.Body)
+		}
+	}
+	if doc.Title == "" {
+		base := filepath.Base(path)
+		doc.Title = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	for _, tag := range strings.Split(doc.Meta["tags"], ",") {
+		if tag = strings.TrimPrefix(strings.TrimSpace(tag), "#"); tag != "" {
+			doc.Tags = append(doc.Tags, tag)
+		}
+	}
+	return doc
+}
+
+// ParseFrontMatter splits YAML ("---") or TOML ("+++") front matter from text. Only flat keys are
+// read: "key: value" or "key = value", inline lists ("[a, b]") and YAML block lists ("- a"), which
+// are joined with ", ". Text without front matter is returned unchanged with a nil map.
+func ParseFrontMatter(text string) (map[string]string, string) {
+	text = strings.TrimPrefix(text, "\ufeff")
+	var fence, separator string
+	switch {
+	case strings.HasPrefix(text, "---\n"), strings.HasPrefix(text, "---\r\n"):
+		fence, separator = "---", ":"
+	case strings.HasPrefix(text, "+++\n"), strings.HasPrefix(text, "+++\r\n"):
+		fence, separator = "+++", "="
+	default:
+		return nil, text
+	}
+
+	meta := map[string]string{}
+	lines := strings.SplitAfter(text, "\n")
+	consumed := len(lines[0]) // Opening fence
+	lastKey := ""
+	for _, line := range lines[1:] {
+		consumed += len(line)
+		line = strings.TrimRight(line, "\r\n")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == fence {
+			return meta, strings.TrimLeft(text[consumed:], "\r\n")
+		}
+		if strings.HasPrefix(trimmed, "- ") && lastKey != "" {
+			item := unquote(strings.TrimSpace(trimmed[2:]))
+			if meta[lastKey] != "" {
+				meta[lastKey] += ", "
+			}
+			meta[lastKey] += item
+			continue
+		}
+		key, value, ok := strings.Cut(line, separator)
+		if !ok || strings.HasPrefix(line, " ") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lastKey = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+			items := strings.Split(value[1:len(value)-1], ",")
+			for i := range items {
+				items[i] = unquote(strings.TrimSpace(items[i]))
+			}
+			value = strings.Join(items, ", ")
+		}
+		meta[lastKey] = unquote(value)
+	}
+	// An unclosed fence is not front matter
+	return nil, text
+}
+
+// Chunks splits text at blank lines into blocks of roughly size characters without splitting a
+// paragraph
+func Chunks(text string, size int) []string {
+	var out []string
+	var current strings.Builder
+	for _, p := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if current.Len() > 0 && current.Len()+len(p) > size {
+			out = append(out, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(p)
+	}
+	if current.Len() > 0 {
+		out = append(out, current.String())
+	}
+	return out
+}
+
+// Helpers
+
+func hasExtension(path string, extensions []string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// firstHeading returns the text of the first Markdown "#" heading
+func firstHeading(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "#") {
+			if heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#")); heading != "" {
+				return heading
+			}
+		}
+	}
+	return ""
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// words splits text into lowercase letter and digit runs
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func counts(words []string) map[string]int {
+	m := make(map[string]int, len(words))
+	for _, w := range words {
+		m[w]++
+	}
+	return m
+}
+
+// fuzzy reports whether any word is within one edit (insertion, deletion or substitution) of term
+func fuzzy(term string, vocabulary map[string]int) bool {
+	t := []rune(term)
+	for w := range vocabulary {
+		if withinOneEdit(t, []rune(w)) {
+			return true
+		}
+	}
+	return false
+}
+
+func withinOneEdit(a, b []rune) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(b)-len(a) > 1 {
+		return false
+	}
+	i := 0
+	for i < len(a) && a[i] == b[i] {
+		i++
+	}
+	if len(a) == len(b) {
+		return i == len(a) || string(a[i+1:]) == string(b[i+1:])
+	}
+	return string(a[i:]) == string(b[i+1:])
+}
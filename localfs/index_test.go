@@ -0,0 +1,63 @@
+package localfs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		text string
+		meta map[string]string
+		body string
+	}{
+		{"---\ntitle: \"Hello\"\ntags:\n  - a\n  - 'b'\n# comment: no\n---\nBody\n", map[string]string{"title": "Hello", "tags": "a, b"}, "Body\n"},
+		{"+++\r\ntitle = 'Hi'\r\naliases = [\"x\", \"y\"]\r\n+++\r\n\r\nBody", map[string]string{"title": "Hi", "aliases": "x, y"}, "Body"},
+		{"---\ntitle: unclosed\nBody", nil, "---\ntitle: unclosed\nBody"},
+		{"Plain text", nil, "Plain text"},
+	}
+	for _, test := range tests {
+		meta, body := ParseFrontMatter(test.text)
+		if body != test.body || len(meta) != len(test.meta) {
+			t.Errorf("ParseFrontMatter(%q) = %v, %q", test.text, meta, body)
+			continue
+		}
+		for key, value := range test.meta {
+			if meta[key] != value {
+				t.Errorf("ParseFrontMatter(%q)[%q] = %q, want %q", test.text, key, meta[key], value)
+			}
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	doc := Parse("notes/daily-log.md", []byte("---\ntags: [\"#work\", home]\n---\nNo heading here\n"))
+	if doc.Title != "daily-log" || strings.Join(doc.Tags, "|") != "work|home" {
+		t.Errorf("Parse = %+v", doc)
+	}
+}
+
+func TestSearchFuzzy(t *testing.T) {
+	ix := &Index{docs: []Document{
+		{Path: "a.md", Title: "Kubernetes", Body: "Cluster setup", words: counts(words("Kubernetes Cluster setup"))},
+		{Path: "b.md", Title: "Other", Body: "Nothing", words: counts(words("Other Nothing"))},
+	}}
+	matches := ix.Search("kubernets", 5)
+	if len(matches) != 1 || matches[0].Path != "a.md" {
+		t.Errorf("Search with a typo = %+v", matches)
+	}
+	if matches := ix.Search("clustr", 5); len(matches) != 1 {
+		t.Errorf("Search with a deletion = %+v", matches)
+	}
+	if matches := ix.Search("cat", 5); len(matches) != 0 {
+		t.Errorf("Search matched a short word fuzzily: %+v", matches)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	got := Chunks("aaaa\r\n\r\nbbbb\n\n\n\ncccccccc\n\nddd", 10)
+	want := []string{"aaaa\n\nbbbb", "cccccccc", "ddd"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Chunks = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,515 @@
+package manpages
+
+// Offline Data Source Adapter for the host's installed man pages and tldr pages
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+var (
+	// sectionDir matches man section directories such as man1, man3p or man8
+	sectionDir = regexp.MustCompile(`^man([1-9n][a-z]*)$`)
+	// renderedHeading matches section headings in rendered output, e.g. "DESCRIPTION" or "EXIT STATUS"
+	renderedHeading = regexp.MustCompile(`^[A-Z][A-Z0-9 ,/-]*$`)
+	// roffEscape matches the escapes left after the common ones are replaced
+	roffEscape = regexp.MustCompile(`\\(\[[^\]]*\]|\([a-zA-Z0-9]{2}|\*[a-zA-Z(]|f[A-Z0-9]|s[-+]?[0-9]|.)`)
+)
+
+type DataSourceManPages struct {
+	ManPath   []string // Man page roots; defaults to $MANPATH or the usual system locations
+	TldrPath  string   // Optional root of a tldr-pages checkout or cache (the directory holding common/, linux/, ...)
+	Sections  []string // Restrict to these sections, e.g. "1" and "8"; empty means all
+	ManBinary string   // man(1) used for rendering; the built-in roff stripper is used when unavailable
+
+	mu    sync.RWMutex
+	pages map[int64]*page
+}
+
+// page is one indexed command or function with the files that document it
+type page struct {
+	Name        string
+	Section     string
+	Description string
+	ManFile     string
+	TldrFile    string
+}
+
+func New() *DataSourceManPages {
+	return &DataSourceManPages{
+		ManBinary: "man",
+	}
+}
+
+// Init implements models.DataSource
+// Walks the man and tldr directories and reads each page's NAME line into an in-memory index
+func (es *DataSourceManPages) Init() error {
+	roots := es.ManPath
+	if len(roots) == 0 {
+		roots = defaultManPath()
+	}
+	pages := map[int64]*page{}
+	for _, root := range roots {
+		es.indexManRoot(root, pages)
+	}
+	if es.TldrPath != "" {
+		es.indexTldr(pages)
+	}
+	if len(pages) == 0 {
+		return errors.New("no man or tldr pages found for ManPages DataSource")
+	}
+	es.mu.Lock()
+	es.pages = pages
+	es.mu.Unlock()
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// The source is available once the index holds at least one page
+func (es *DataSourceManPages) CheckAvailability() bool {
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+	return len(es.pages) > 0
+}
+
+// Info implements source.Informer
+func (es *DataSourceManPages) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Man pages",
+		Homepage: "https://manpages.debian.org",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Matches the query against page names first and one-line descriptions second; topics read "ls(1): list directory contents"
+func (es *DataSourceManPages) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.ToLower(strings.TrimSpace(input))
+	if query == "" {
+		return nil, errors.New("Missing search input for ManPages DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if !es.CheckAvailability() {
+		if err := es.Init(); err != nil {
+			return nil, err
+		}
+	}
+
+	type match struct {
+		id    int64
+		page  *page
+		score int
+	}
+	terms := strings.Fields(query)
+	var matches []match
+	es.mu.RLock()
+	for id, p := range es.pages {
+		if score := pageScore(p, query, terms); score > 0 {
+			matches = append(matches, match{id: id, page: p, score: score})
+		}
+	}
+	es.mu.RUnlock()
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		if matches[i].page.Name != matches[j].page.Name {
+			return matches[i].page.Name < matches[j].page.Name
+		}
+		return matches[i].page.Section < matches[j].page.Section
+	})
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, m := range matches {
+		if len(results) >= count {
+			break
+		}
+		title := m.page.Name
+		if m.page.Section != "" {
+			title = fmt.Sprintf("%s(%s)", m.page.Name, m.page.Section)
+		}
+		if m.page.Description != "" {
+			title += ": " + m.page.Description
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: m.page.sourceURL(),
+			Site:      m.page.Section,
+			TopicID:   m.id,
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the tldr examples (when present) followed by the rendered man page, one item per man section
+func (es *DataSourceManPages) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	es.mu.RLock()
+	p, ok := es.pages[topicID]
+	es.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown ManPages topicID %d", topicID)
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	if p.TldrFile != "" {
+		if raw, err := os.ReadFile(p.TldrFile); err == nil {
+			results = append(results, datasource.DataSourceData{
+				DataText:  strings.TrimSpace(string(raw)),
+				SourceURL: "file://" + p.TldrFile,
+				Site:      "tldr",
+				AnswerID:  topicid.Hash(p.TldrFile),
+			})
+		}
+	}
+	if p.ManFile == "" {
+		return results, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	text, err := es.render(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range splitRendered(text) {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  section.Text,
+			SourceURL: "file://" + p.ManFile,
+			Site:      p.Section,
+			AnswerID:  topicid.Hash(p.ManFile + "#" + section.Heading),
+		})
+	}
+	return results, nil
+}
+
+func (p *page) sourceURL() string {
+	if p.ManFile != "" {
+		return "file://" + p.ManFile
+	}
+	return "file://" + p.TldrFile
+}
+
+// indexManRoot adds every page under root/manN to pages
+func (es *DataSourceManPages) indexManRoot(root string, pages map[int64]*page) {
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		m := sectionDir.FindStringSubmatch(dir.Name())
+		if !dir.IsDir() || m == nil || !es.wantSection(m[1]) {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(root, dir.Name()))
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			name, section, ok := parseManFilename(file.Name())
+			if !ok {
+				continue
+			}
+			key := name + "." + section
+			id := topicid.Hash(key)
+			if _, exists := pages[id]; exists {
+				// Earlier roots take precedence, as they do for man(1)
+				continue
+			}
+			path := filepath.Join(root, dir.Name(), file.Name())
+			pages[id] = &page{
+				Name:        name,
+				Section:     section,
+				Description: readDescription(path),
+				ManFile:     path,
+			}
+		}
+	}
+}
+
+// indexTldr attaches tldr pages to their commands, adding tldr-only commands as section-less pages
+func (es *DataSourceManPages) indexTldr(pages map[int64]*page) {
+	byName := map[string]*page{}
+	for _, p := range pages {
+		if existing, ok := byName[p.Name]; !ok || p.Section < existing.Section {
+			byName[p.Name] = p
+		}
+	}
+	// Platform-specific pages override common ones, matching the tldr client lookup order
+	for _, platform := range []string{"common", "linux", "osx"} {
+		files, _ := filepath.Glob(filepath.Join(es.TldrPath, platform, "*.md"))
+		for _, file := range files {
+			name := strings.TrimSuffix(filepath.Base(file), ".md")
+			if p, ok := byName[name]; ok {
+				p.TldrFile = file
+				continue
+			}
+			p := &page{Name: name, Description: readTldrDescription(file), TldrFile: file}
+			pages[topicid.Hash(name+".tldr")] = p
+			byName[name] = p
+		}
+	}
+}
+
+func (es *DataSourceManPages) wantSection(section string) bool {
+	if len(es.Sections) == 0 {
+		return true
+	}
+	for _, want := range es.Sections {
+		if strings.HasPrefix(section, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// render formats a man page as plain text, preferring the host's man(1)
+func (es *DataSourceManPages) render(ctx context.Context, p *page) (string, error) {
+	if es.ManBinary != "" {
+		if bin, err := exec.LookPath(es.ManBinary); err == nil {
+			cmd := exec.CommandContext(ctx, bin, p.Section, p.Name)
+			cmd.Env = append(os.Environ(), "MANPAGER=cat", "PAGER=cat", "MANWIDTH=100", "MAN_KEEP_FORMATTING=0")
+			if out, err := cmd.Output(); err == nil && len(out) > 0 {
+				return stripOverstrike(string(out)), nil
+			}
+		}
+	}
+	source, err := readManFile(p.ManFile)
+	if err != nil {
+		return "", err
+	}
+	return roffToText(source), nil
+}
+
+// renderedSection is one top-level section (NAME, SYNOPSIS, ...) of a rendered page
+type renderedSection struct {
+	Heading string
+	Text    string
+}
+
+func splitRendered(text string) []renderedSection {
+	var sections []renderedSection
+	var current *renderedSection
+	var lines []string
+	flush := func() {
+		if current != nil {
+			current.Text = strings.TrimSpace(strings.Join(lines, "\n"))
+			if current.Text != "" {
+				sections = append(sections, *current)
+			}
+		}
+		lines = lines[:0]
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if renderedHeading.MatchString(line) {
+			flush()
+			current = &renderedSection{Heading: line}
+		}
+		if current != nil {
+			lines = append(lines, line)
+		}
+	}
+	flush()
+	return sections
+}
+
+// Helpers
+
+func defaultManPath() []string {
+	if env := os.Getenv("MANPATH"); env != "" {
+		var roots []string
+		for _, root := range filepath.SplitList(env) {
+			if root != "" {
+				roots = append(roots, root)
+			}
+		}
+		if len(roots) > 0 {
+			return roots
+		}
+	}
+	return []string{"/usr/local/share/man", "/usr/share/man", "/usr/local/man", "/opt/homebrew/share/man"}
+}
+
+// parseManFilename splits "tar.1.gz" or "printf.3p" into name and section
+func parseManFilename(file string) (string, string, bool) {
+	file = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(file, ".gz"), ".bz2"), ".xz")
+	i := strings.LastIndex(file, ".")
+	if i <= 0 || i == len(file)-1 {
+		return "", "", false
+	}
+	return file[:i], file[i+1:], true
+}
+
+func readManFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return "", err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	raw, err := io.ReadAll(io.LimitReader(r, 4<<20))
+	return string(raw), err
+}
+
+// readDescription extracts the one-line summary from the NAME section ("ls \- list directory contents")
+func readDescription(path string) string {
+	source, err := readManFile(path)
+	if err != nil {
+		return ""
+	}
+	scanner := bufio.NewScanner(strings.NewReader(source))
+	inName := false
+	var parts []string
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		upper := strings.ToUpper(line)
+		if strings.HasPrefix(upper, ".SH") {
+			if inName {
+				break
+			}
+			inName = strings.Contains(upper, "NAME")
+			continue
+		}
+		// mdoc pages carry the summary in an .Nd macro
+		if strings.HasPrefix(line, ".Nd ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, ".Nd "))
+		}
+		if inName && line != "" && !strings.HasPrefix(line, ".") {
+			parts = append(parts, line)
+		}
+	}
+	summary := roffToText(strings.Join(parts, " "))
+	if _, after, ok := strings.Cut(summary, " - "); ok {
+		return strings.TrimSpace(after)
+	}
+	return ""
+}
+
+// readTldrDescription returns the first "> " description line of a tldr page
+func readTldrDescription(path string) string {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(raw), "\n") {
+		if strings.HasPrefix(line, "> ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "> "))
+		}
+	}
+	return ""
+}
+
+// pageScore ranks exact name matches above prefix matches above description matches; 0 means no match
+func pageScore(p *page, query string, terms []string) int {
+	name := strings.ToLower(p.Name)
+	score := 0
+	switch {
+	case name == query:
+		score = 100
+	case strings.HasPrefix(name, query):
+		score = 50
+	case strings.Contains(name, query):
+		score = 25
+	default:
+		description := strings.ToLower(p.Description)
+		for _, term := range terms {
+			if !strings.Contains(description, term) {
+				return 0
+			}
+		}
+		score = 10
+	}
+	// Prefer user commands and admin commands for equal matches
+	if p.Section == "1" || p.Section == "8" || p.TldrFile != "" {
+		score++
+	}
+	return score
+}
+
+// stripOverstrike removes the backspace-based bold and underline some man implementations still emit
+func stripOverstrike(in string) string {
+	if !strings.Contains(in, "\b") {
+		return in
+	}
+	var out bytes.Buffer
+	for _, r := range in {
+		if r == '\b' {
+			if out.Len() > 0 {
+				out.Truncate(out.Len() - 1)
+			}
+			continue
+		}
+		out.WriteRune(r)
+	}
+	return out.String()
+}
+
+// roffToText is a minimal man(7) formatter used when man(1) is not installed
+func roffToText(source string) string {
+	replacer := strings.NewReplacer(`\-`, "-", `\(em`, "—", `\(en`, "–", `\(aq`, "'", `\(dq`, `"`,
+		`\(bu`, "•", `\e`, "\x00", `\&`, "", `\ `, " ", `\~`, " ", `\|`, "", `\^`, "")
+	var b strings.Builder
+	for _, line := range strings.Split(source, "\n") {
+		if strings.HasPrefix(line, `.\"`) || strings.HasPrefix(line, `'\"`) {
+			continue
+		}
+		if strings.HasPrefix(line, ".") {
+			macro, args, _ := strings.Cut(strings.TrimPrefix(line, "."), " ")
+			args = strings.ReplaceAll(args, `"`, "")
+			switch macro {
+			case "SH", "Sh":
+				b.WriteString("\n" + strings.ToUpper(args) + "\n")
+			case "SS", "Ss":
+				b.WriteString("\n   " + args + "\n")
+			case "PP", "LP", "P", "TP", "IP", "Pp", "sp", "br":
+				b.WriteString("\n")
+				if macro == "IP" && args != "" {
+					b.WriteString(args + " ")
+				}
+			case "B", "I", "BR", "IR", "RB", "RI", "BI", "IB", "Nm", "Ar", "Fl", "Cm":
+				b.WriteString(args + " ")
+			}
+			continue
+		}
+		b.WriteString(line + "\n")
+	}
+	// \e was parked as NUL so the escape stripper can't consume the character after it
+	text := roffEscape.ReplaceAllString(replacer.Replace(b.String()), "")
+	return strings.TrimSpace(strings.ReplaceAll(text, "\x00", `\`))
+}
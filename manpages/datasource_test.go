@@ -0,0 +1,122 @@
+package manpages
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const lsPage = `.\" Comment
+.TH LS 1
+.SH NAME
+ls \- list directory contents
+.SH SYNOPSIS
+.B ls
+[\fIOPTION\fR]... [\fIFILE\fR]...
+.SH DESCRIPTION
+List information about the FILEs.
+.PP
+Sort entries alphabetically.
+`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newSource(t *testing.T) *DataSourceManPages {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "man", "man1", "ls.1"), lsPage)
+	writeFile(t, filepath.Join(root, "man", "man3", "lsearch.3"), ".SH NAME\nlsearch \\- linear search of an array\n")
+	writeFile(t, filepath.Join(root, "man", "man5", "passwd.5"), ".SH NAME\npasswd \\- the password file\n")
+
+	f, err := os.Create(filepath.Join(root, "man", "man1", "tar.1.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	gz.Write([]byte(".SH NAME\ntar \\- an archiving utility\n"))
+	gz.Close()
+	f.Close()
+
+	writeFile(t, filepath.Join(root, "tldr", "common", "ls.md"), "# ls\n\n> List directory contents.\n\n- List files:\n\n`ls`\n")
+	writeFile(t, filepath.Join(root, "tldr", "common", "exa.md"), "# exa\n\n> A modern replacement for ls.\n")
+
+	es := New()
+	es.ManPath = []string{filepath.Join(root, "man")}
+	es.TldrPath = filepath.Join(root, "tldr")
+	es.Sections = []string{"1", "3"}
+	es.ManBinary = ""
+	if err := es.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return es
+}
+
+func TestFetchTopics(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "ls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, topic := range topics {
+		titles = append(titles, topic.Topic)
+	}
+	want := []string{"ls(1): list directory contents", "lsearch(3): linear search of an array", "exa: A modern replacement for ls."}
+	if strings.Join(titles, "|") != strings.Join(want, "|") {
+		t.Errorf("FetchTopics = %q, want %q", titles, want)
+	}
+
+	topics, err = es.FetchTopics(5, "archiving")
+	if err != nil || len(topics) != 1 || topics[0].Topic != "tar(1): an archiving utility" {
+		t.Errorf("FetchTopics by description = %+v, %v", topics, err)
+	}
+	if topics, _ := es.FetchTopics(5, "passwd"); len(topics) != 0 {
+		t.Errorf("FetchTopics returned a page outside Sections: %+v", topics)
+	}
+}
+
+func TestFetchData(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(1, "ls")
+	if err != nil || len(topics) != 1 {
+		t.Fatalf("FetchTopics = %+v, %v", topics, err)
+	}
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 4 || data[0].Site != "tldr" || !strings.Contains(data[0].DataText, "- List files:") {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	if data[1].DataText != "NAME\nls - list directory contents" {
+		t.Errorf("NAME = %q", data[1].DataText)
+	}
+	if data[2].DataText != "SYNOPSIS\nls [OPTION]... [FILE]..." {
+		t.Errorf("SYNOPSIS = %q", data[2].DataText)
+	}
+	if data[3].DataText != "DESCRIPTION\nList information about the FILEs.\n\nSort entries alphabetically." {
+		t.Errorf("DESCRIPTION = %q", data[3].DataText)
+	}
+}
+
+func TestParseManFilename(t *testing.T) {
+	tests := map[string][2]string{"tar.1.gz": {"tar", "1"}, "printf.3p": {"printf", "3p"}, "x.y.8.xz": {"x.y", "8"}}
+	for file, want := range tests {
+		if name, section, ok := parseManFilename(file); !ok || name != want[0] || section != want[1] {
+			t.Errorf("parseManFilename(%q) = %q, %q, %v", file, name, section, ok)
+		}
+	}
+	if _, _, ok := parseManFilename("README"); ok {
+		t.Error("parseManFilename accepted a file without a section")
+	}
+}
@@ -0,0 +1,301 @@
+package mastodon
+
+// Data Source Adapter for Mastodon instance search
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceMastodon struct {
+	Client          *http.Client
+	BaseURL         string // Instance root, e.g. https://mastodon.social
+	UserAgent       string
+	AccessToken     string // Set by user; full-text status search requires an authenticated account
+	IncludeHashtags bool   // Also return matching hashtags as topics
+
+	refs topicid.Map[ref]
+}
+
+// ref identifies the status or hashtag behind a topic
+type ref struct {
+	Kind string
+	Key  string
+	URL  string
+}
+
+func New() *DataSourceMastodon {
+	return &DataSourceMastodon{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:         "https://mastodon.social",
+		UserAgent:       "locus/mastodon-datasource",
+		IncludeHashtags: true,
+	}
+}
+
+// Init implements models.DataSource
+// Mastodon requires an instance URL and an access token
+func (es *DataSourceMastodon) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if strings.TrimSpace(es.BaseURL) == "" {
+		return errors.New("BaseURL is required for Mastodon DataSource")
+	}
+	if es.AccessToken == "" {
+		return errors.New("AccessToken is required for Mastodon DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Verifies the token against the instance, which also proves the instance is reachable
+func (es *DataSourceMastodon) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	return es.doJSON(ctx, "/api/v1/apps/verify_credentials", nil, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceMastodon) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Mastodon",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Matching hashtags come first (when enabled), followed by matching statuses
+func (es *DataSourceMastodon) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Mastodon DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(count))
+	if !es.IncludeHashtags {
+		params.Set("type", "statuses")
+	}
+
+	var response struct {
+		Statuses []status `json:"statuses"`
+		Hashtags []struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"hashtags"`
+	}
+	if err := es.doJSON(ctx, "/api/v2/search", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	if es.IncludeHashtags {
+		for _, tag := range response.Hashtags {
+			if len(results) >= count {
+				break
+			}
+			id := es.refs.Put("tag:"+tag.Name, ref{Kind: "tag", Key: tag.Name, URL: tag.URL})
+			results = append(results, datasource.DataSourceTopic{
+				Topic:     "#" + tag.Name,
+				SourceURL: tag.URL,
+				TopicID:   id,
+			})
+		}
+	}
+	for _, item := range response.Statuses {
+		if len(results) >= count {
+			break
+		}
+		title := summarize(htmlToText(item.Content), 120)
+		if title == "" {
+			continue
+		}
+		id := es.refs.Put("status:"+item.ID, ref{Kind: "status", Key: item.ID, URL: item.URL})
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: item.URL,
+			Site:      item.Account.Acct,
+			TopicID:   id,
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// A status topic returns the status and its replies; a hashtag topic returns its most recent posts
+func (es *DataSourceMastodon) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	target, ok := es.refs.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Mastodon topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var statuses []status
+	if target.Kind == "tag" {
+		params := url.Values{}
+		params.Set("limit", strconv.Itoa(count))
+		if err := es.doJSON(ctx, "/api/v1/timelines/tag/"+url.PathEscape(target.Key), params, &statuses); err != nil {
+			return nil, err
+		}
+	} else {
+		var root status
+		if err := es.doJSON(ctx, "/api/v1/statuses/"+url.PathEscape(target.Key), nil, &root); err != nil {
+			return nil, err
+		}
+		var thread struct {
+			Descendants []status `json:"descendants"`
+		}
+		if err := es.doJSON(ctx, "/api/v1/statuses/"+url.PathEscape(target.Key)+"/context", nil, &thread); err != nil {
+			return nil, err
+		}
+		statuses = append([]status{root}, thread.Descendants...)
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, item := range statuses {
+		if len(results) >= count {
+			break
+		}
+		// Boosts carry their content on the reblogged status
+		if item.Reblog != nil {
+			item = *item.Reblog
+		}
+		text := htmlToText(item.Content)
+		if text == "" {
+			continue
+		}
+		answerID, _ := strconv.ParseInt(item.ID, 10, 64)
+		results = append(results, datasource.DataSourceData{
+			DataText:  formatStatus(item, text),
+			SourceURL: item.URL,
+			Site:      item.Account.Acct,
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// status mirrors the Mastodon Status entity fields used by this adapter
+type status struct {
+	ID              string `json:"id"`
+	URL             string `json:"url"`
+	Content         string `json:"content"`
+	CreatedAt       string `json:"created_at"`
+	RepliesCount    int    `json:"replies_count"`
+	ReblogsCount    int    `json:"reblogs_count"`
+	FavouritesCount int    `json:"favourites_count"`
+	Account         struct {
+		Acct        string `json:"acct"`
+		DisplayName string `json:"display_name"`
+	} `json:"account"`
+	Reblog *status `json:"reblog"`
+}
+
+// formatStatus prefixes the status text with its author and engagement counts
+func formatStatus(item status, text string) string {
+	author := item.Account.Acct
+	if item.Account.DisplayName != "" {
+		author = fmt.Sprintf("%s (@%s)", item.Account.DisplayName, item.Account.Acct)
+	}
+	return fmt.Sprintf("%s, %s - %d replies, %d boosts, %d favourites:\n%s",
+		author, item.CreatedAt, item.RepliesCount, item.ReblogsCount, item.FavouritesCount, text)
+}
+
+// doJSON performs an authenticated HTTP GET request against the Mastodon API and decodes the JSON response into target
+func (es *DataSourceMastodon) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+es.AccessToken)
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("mastodon request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func htmlToText(in string) string {
+	if strings.TrimSpace(in) == "" {
+		return ""
+	}
+	in = strings.NewReplacer("<br>", "\n", "<br/>", "\n", "<br />", "\n", "</p>", "\n\n").Replace(in)
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(in))
+	if err != nil {
+		return strings.TrimSpace(in)
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+// summarize shortens text to at most limit runes on a word boundary, for use as a topic title
+func summarize(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	cut := string(runes[:limit])
+	if i := strings.LastIndex(cut, " "); i > limit/2 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
@@ -0,0 +1,99 @@
+package mastodon
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "fediverse" || r.URL.Query().Get("limit") != "3" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{
+			"hashtags":[{"name":"fediverse","url":"https://mastodon.example/tags/fediverse"}],
+			"statuses":[
+				{"id":"100","url":"https://mastodon.example/@a/100","content":"<p>Hello <b>fediverse</b></p>","account":{"acct":"a@example"}},
+				{"id":"101","content":"<p></p>"}
+			]
+		}`)
+	})
+	mux.HandleFunc("/api/v1/timelines/tag/fediverse", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id":"200","reblog":{"id":"201","url":"https://mastodon.example/@b/201","content":"<p>Boosted</p>","account":{"acct":"b"}}},
+			{"id":"202","content":"<p>Own post</p>","account":{"acct":"c"}}
+		]`)
+	})
+	mux.HandleFunc("/api/v1/statuses/100", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"100","content":"<p>Hello<br>fediverse</p>","created_at":"2024-01-02","replies_count":1,"reblogs_count":2,"favourites_count":3,"account":{"acct":"a","display_name":"Alice"}}`)
+	})
+	mux.HandleFunc("/api/v1/statuses/100/context", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"descendants":[{"id":"102","content":"<p>Hi!</p>","account":{"acct":"d"}}]}`)
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.AccessToken = "token"
+
+	topics, err := es.FetchTopics(3, "fediverse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[0].Topic != "#fediverse" || topics[1].Topic != "Hello fediverse" || topics[1].Site != "a@example" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 || !strings.HasSuffix(data[0].DataText, ":\nBoosted") || data[0].AnswerID != 201 || data[0].Site != "b" {
+		t.Errorf("hashtag FetchData = %+v", data)
+	}
+
+	data, err = es.FetchData(5, topics[1].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("status FetchData = %+v", data)
+	}
+	want := "Alice (@a), 2024-01-02 - 1 replies, 2 boosts, 3 favourites:\nHello\nfediverse"
+	if data[0].DataText != want {
+		t.Errorf("status = %q, want %q", data[0].DataText, want)
+	}
+	if data[1].AnswerID != 102 {
+		t.Errorf("reply = %+v", data[1])
+	}
+}
+
+func TestInitRequiresToken(t *testing.T) {
+	if err := New().Init(); err == nil {
+		t.Error("Init succeeded without an AccessToken")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	if got := summarize("short  text", 20); got != "short text" {
+		t.Errorf("summarize = %q", got)
+	}
+	if got := summarize("a long status that needs to be cut", 20); got != "a long status that…" {
+		t.Errorf("summarize = %q", got)
+	}
+}
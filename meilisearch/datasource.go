@@ -0,0 +1,306 @@
+package meilisearch
+
+// Data Source Adapter for Meilisearch indexes
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/jsonpath"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceMeilisearch struct {
+	Client    *http.Client
+	BaseURL   string // e.g. "http://localhost:7700"
+	APIKey    string // Search or admin key; optional for unprotected instances
+	Index     string // Index uid
+	UserAgent string
+
+	Filter string   // Meilisearch filter expression, e.g. "lang = en AND year > 2020"
+	Sort   []string // e.g. ["date:desc"]; ranking rules apply when empty
+
+	// Attribute mappings; nested attributes use dots, e.g. "author.name"
+	PrimaryKey string   // Default "id"
+	TitleField string   // Default "title"
+	URLField   string   // Optional
+	DataFields []string // Written as "field: value"; empty writes every highlighted field
+
+	// Highlighted attributes are cropped around the matches and returned in the data with the matches
+	// wrapped in HighlightTags; default all attributes, wrapped in "**"
+	HighlightFields []string
+	HighlightTags   [2]string
+	CropLength      int // Words kept around matches; default 30
+
+	Site string // Site for topics and data; default "meilisearch/<index>"
+
+	hits topicid.Map[hit]
+}
+
+type hit struct {
+	ID        string
+	Title     string
+	URL       string
+	Score     float64
+	Document  map[string]interface{}
+	Formatted map[string]interface{} // Highlighted and cropped copy of the document
+}
+
+func New() *DataSourceMeilisearch {
+	return &DataSourceMeilisearch{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:       "http://localhost:7700",
+		UserAgent:     "locus/meilisearch-datasource",
+		PrimaryKey:    "id",
+		TitleField:    "title",
+		HighlightTags: [2]string{"**", "**"},
+		CropLength:    30,
+	}
+}
+
+// Init implements models.DataSource
+func (es *DataSourceMeilisearch) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.Index == "" {
+		return errors.New("Index is required for Meilisearch DataSource")
+	}
+	if es.PrimaryKey == "" {
+		es.PrimaryKey = "id"
+	}
+	if es.TitleField == "" {
+		es.TitleField = "title"
+	}
+	if es.CropLength <= 0 {
+		es.CropLength = 30
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Checks the instance health endpoint
+func (es *DataSourceMeilisearch) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	var health struct {
+		Status string `json:"status"`
+	}
+	if err := es.doJSON(ctx, http.MethodGet, "/health", nil, &health); err != nil {
+		return false
+	}
+	return health.Status == "available"
+}
+
+// Info implements source.Informer
+func (es *DataSourceMeilisearch) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Meilisearch",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Hits are topics in ranking order, titled by TitleField
+func (es *DataSourceMeilisearch) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Meilisearch DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	highlight := es.HighlightFields
+	if len(highlight) == 0 {
+		highlight = []string{"*"}
+	}
+	request := map[string]interface{}{
+		"q":                     query,
+		"limit":                 count,
+		"attributesToHighlight": highlight,
+		"attributesToCrop":      highlight,
+		"cropLength":            es.CropLength,
+		"highlightPreTag":       es.HighlightTags[0],
+		"highlightPostTag":      es.HighlightTags[1],
+		"showRankingScore":      true,
+	}
+	if es.Filter != "" {
+		request["filter"] = es.Filter
+	}
+	if len(es.Sort) > 0 {
+		request["sort"] = es.Sort
+	}
+	var response struct {
+		Hits []map[string]interface{} `json:"hits"`
+	}
+	if err := es.doJSON(ctx, http.MethodPost, "/indexes/"+url.PathEscape(es.Index)+"/search", request, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Hits))
+	for _, doc := range response.Hits {
+		h := hit{Document: doc}
+		h.Formatted, _ = doc["_formatted"].(map[string]interface{})
+		h.Score, _ = doc["_rankingScore"].(float64)
+		delete(doc, "_formatted")
+		delete(doc, "_rankingScore")
+		h.ID = field(doc, es.PrimaryKey)
+		h.Title = field(doc, es.TitleField)
+		if h.Title == "" {
+			h.Title = h.ID
+		}
+		if es.URLField != "" {
+			h.URL = field(doc, es.URLField)
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     h.Title,
+			SourceURL: h.URL,
+			Site:      es.site(),
+			TopicID:   es.hits.Put(es.Index+"/"+h.ID, h),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns one item: the title, then "field: value" lines using the highlighted and cropped values where
+// Meilisearch returned them, then the ranking score
+func (es *DataSourceMeilisearch) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	h, ok := es.hits.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Meilisearch topicID %d", topicID)
+	}
+
+	fields := es.DataFields
+	if len(fields) == 0 {
+		fields = highlighted(h.Formatted, es.HighlightTags[0])
+	}
+	lines := []string{h.Title}
+	for _, name := range fields {
+		if name == es.TitleField || name == es.PrimaryKey || name == es.URLField {
+			continue
+		}
+		value := field(h.Formatted, name)
+		if value == "" {
+			value = field(h.Document, name)
+		}
+		if value != "" {
+			lines = append(lines, name+": "+value)
+		}
+	}
+	if h.Score > 0 {
+		lines = append(lines, "score: "+strconv.FormatFloat(h.Score, 'f', 3, 64))
+	}
+	return []datasource.DataSourceData{{
+		DataText:  strings.Join(lines, "\n"),
+		SourceURL: h.URL,
+		Site:      es.site(),
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// doJSON sends a request with an optional JSON body and decodes the JSON response into target
+func (es *DataSourceMeilisearch) doJSON(ctx context.Context, method, path string, body interface{}, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(es.BaseURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if es.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+es.APIKey)
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("meilisearch request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func (es *DataSourceMeilisearch) site() string {
+	if es.Site != "" {
+		return es.Site
+	}
+	return "meilisearch/" + es.Index
+}
+
+// Helpers
+
+// field renders a possibly dotted attribute of a document as text
+func field(doc map[string]interface{}, name string) string {
+	if doc == nil {
+		return ""
+	}
+	if value, ok := doc[name]; ok {
+		return strings.TrimSpace(jsonpath.Format(value))
+	}
+	text, err := jsonpath.Lookup(doc, name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(text)
+}
+
+// highlighted returns the top-level attributes of a formatted document that contain a highlight, in
+// a stable order
+func highlighted(formatted map[string]interface{}, preTag string) []string {
+	var names []string
+	for name, value := range formatted {
+		if strings.Contains(jsonpath.Format(value), preTag) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,96 @@
+package meilisearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /indexes/movies/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := json.Marshal(body)
+		want := `{"attributesToCrop":["*"],"attributesToHighlight":["*"],"cropLength":30,"filter":"year = 1968",` +
+			`"highlightPostTag":"**","highlightPreTag":"**","limit":5,"q":"space","showRankingScore":true,"sort":["year:desc"]}`
+		if string(got) != want {
+			t.Errorf("unexpected body %s", got)
+		}
+		fmt.Fprint(w, `{"hits":[
+			{"id":7,"title":"Space Odyssey","overview":"A voyage into space.","year":1968,"director":{"name":"Kubrick"},
+				"links":{"imdb":"https://example.com/7"},"_rankingScore":0.9125,
+				"_formatted":{"id":"7","title":"**Space** Odyssey","overview":"A voyage into **space**.","year":"1968"}},
+			{"id":"x-1","overview":"No title"}
+		]}`)
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"available"}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSource(t *testing.T) *DataSourceMeilisearch {
+	t.Helper()
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL + "/"
+	es.APIKey = "key"
+	es.Index = "movies"
+	es.Filter = "year = 1968"
+	es.Sort = []string{"year:desc"}
+	es.URLField = "links.imdb"
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	if !es.CheckAvailability() {
+		t.Error("CheckAvailability = false")
+	}
+	topics, err := es.FetchTopics(5, "space")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Space Odyssey|https://example.com/7", "x-1|"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].SourceURL; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+	if topics[0].Site != "meilisearch/movies" {
+		t.Errorf("Site = %q", topics[0].Site)
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "Space Odyssey\noverview: A voyage into **space**.\nscore: 0.912"
+	if len(data) != 1 || data[0].DataText != wantData {
+		t.Errorf("FetchData = %+v, want %q", data, wantData)
+	}
+
+	es.DataFields = []string{"director.name", "year", "missing"}
+	data, err = es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData = "Space Odyssey\ndirector.name: Kubrick\nyear: 1968\nscore: 0.912"
+	if len(data) != 1 || data[0].DataText != wantData {
+		t.Errorf("FetchData with DataFields = %+v, want %q", data, wantData)
+	}
+}
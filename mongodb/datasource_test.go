@@ -0,0 +1,108 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// stage returns the value of the pipeline stage with the operator, failing if it is missing
+func stage(t *testing.T, es *DataSourceMongoDB, operator string) interface{} {
+	t.Helper()
+	for _, s := range es.pipeline("solar wind", 3) {
+		if s[0].Key == operator {
+			return s[0].Value
+		}
+	}
+	t.Fatalf("pipeline has no %s stage", operator)
+	return nil
+}
+
+func TestPipelineText(t *testing.T) {
+	es := New()
+	es.filter = bson.M{"lang": "en"}
+	es.URLField = "links.self"
+	es.SnippetFields = []string{"author.name", "year"}
+
+	match := stage(t, es, "$match")
+	want := bson.M{"$and": bson.A{bson.M{"$text": bson.M{"$search": "solar wind"}}, bson.M{"lang": "en"}}}
+	if !reflect.DeepEqual(match, want) {
+		t.Errorf("$match = %v, want %v", match, want)
+	}
+	if limit := stage(t, es, "$limit"); limit != 3 {
+		t.Errorf("$limit = %v", limit)
+	}
+	project := stage(t, es, "$project").(bson.M)
+	if project["title"] != "$title" || project["url"] != "$links.self" ||
+		!reflect.DeepEqual(project["snippets"], bson.A{"$author.name", "$year"}) ||
+		!reflect.DeepEqual(project["score"], bson.M{"$meta": "textScore"}) {
+		t.Errorf("$project = %v", project)
+	}
+	if _, ok := project["data"]; ok {
+		t.Errorf("$project without DataFields = %v", project)
+	}
+}
+
+func TestPipelineAtlas(t *testing.T) {
+	es := New()
+	es.Mode = SearchAtlas
+	es.AtlasIndex = "articles"
+
+	search := stage(t, es, "$search")
+	want := bson.M{"index": "articles", "text": bson.M{"query": "solar wind", "path": bson.M{"wildcard": "*"}}}
+	if !reflect.DeepEqual(search, want) {
+		t.Errorf("$search = %v, want %v", search, want)
+	}
+	es.AtlasPaths = []string{"title", "body"}
+	if path := stage(t, es, "$search").(bson.M)["text"].(bson.M)["path"]; !reflect.DeepEqual(path, []string{"title", "body"}) {
+		t.Errorf("$search path = %v", path)
+	}
+	if score := stage(t, es, "$project").(bson.M)["score"]; !reflect.DeepEqual(score, bson.M{"$meta": "searchScore"}) {
+		t.Errorf("score = %v", score)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	id, _ := bson.ObjectIDFromHex("65a1f0c2e4b0a1b2c3d4e5f6")
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, ""},
+		{"  text ", "text"},
+		{int32(7), "7"},
+		{int64(-42), "-42"},
+		{2.5, "2.5"},
+		{true, "true"},
+		{id, "65a1f0c2e4b0a1b2c3d4e5f6"},
+		{bson.NewDateTimeFromTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)), "2024-05-01T12:00:00Z"},
+		{bson.A{"a", nil, int32(2)}, "a, 2"},
+		{bson.D{{Key: "name", Value: "Ada"}}, `{"name":"Ada"}`},
+	}
+	for _, test := range tests {
+		if got := format(test.value); got != test.want {
+			t.Errorf("format(%#v) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestInitRejectsBadFilter(t *testing.T) {
+	es := New()
+	es.URI = "mongodb://127.0.0.1:1"
+	es.Database = "db"
+	es.Collection = "docs"
+	es.Filter = `{"lang": `
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted an invalid Filter")
+	}
+	es.Filter = `{"year": {"$gte": 2000}}`
+	if err := es.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer es.Close()
+	if !reflect.DeepEqual(es.filter, bson.M{"year": bson.D{{Key: "$gte", Value: int32(2000)}}}) {
+		t.Errorf("filter = %#v", es.filter)
+	}
+}
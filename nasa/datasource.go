@@ -0,0 +1,345 @@
+package nasa
+
+// Data Source Adapter for the NASA Image and Video Library and Astronomy Picture of the Day
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	maxMediaLinks     = 4
+)
+
+var (
+	// apodInput matches "apod", "apod 2024-04-08" or a bare date
+	apodInput = regexp.MustCompile(`^(?i:apod)?\s*(\d{4}-\d{2}-\d{2})?$`)
+	htmlTag   = regexp.MustCompile(`<[^>]+>`)
+)
+
+type DataSourceNASA struct {
+	Client     *http.Client
+	BaseURL    string // Image and Video Library
+	APIURL     string // api.nasa.gov, for APOD
+	APIKey     string // api.nasa.gov key; DEMO_KEY works at low volume
+	UserAgent  string
+	MediaTypes []string // Any of "image", "video", "audio"
+
+	items topicid.Map[item]
+}
+
+// item is a library asset or an APOD entry
+type item struct {
+	NASAID       string   `json:"nasa_id"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	DateCreated  string   `json:"date_created"`
+	Center       string   `json:"center"`
+	Photographer string   `json:"photographer"`
+	MediaType    string   `json:"media_type"`
+	Keywords     []string `json:"keywords"`
+
+	apod *apod
+}
+
+type apod struct {
+	Date        string `json:"date"`
+	Title       string `json:"title"`
+	Explanation string `json:"explanation"`
+	URL         string `json:"url"`
+	HDURL       string `json:"hdurl"`
+	MediaType   string `json:"media_type"`
+	Copyright   string `json:"copyright"`
+}
+
+func New() *DataSourceNASA {
+	return &DataSourceNASA{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:    "https://images-api.nasa.gov",
+		APIURL:     "https://api.nasa.gov",
+		APIKey:     "DEMO_KEY",
+		UserAgent:  "locus/nasa-datasource",
+		MediaTypes: []string{"image", "video"},
+	}
+}
+
+// Init implements models.DataSource
+func (es *DataSourceNASA) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceNASA) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", "apollo")
+	params.Set("page_size", "1")
+	return es.doJSON(ctx, es.BaseURL+"/search", params, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceNASA) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "NASA Image and Video Library",
+		Homepage:    "https://images.nasa.gov",
+		License:     "Public domain",
+		Attribution: "Courtesy NASA",
+		TermsURL:    "https://www.nasa.gov/nasa-brand-center/images-and-media/",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Input of "apod", "apod YYYY-MM-DD" or a bare date returns that Astronomy Picture of the Day;
+// anything else searches the Image and Video Library, e.g. "Earthrise (image, 1968-12-24)"
+func (es *DataSourceNASA) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for NASA DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	if m := apodInput.FindStringSubmatch(query); m != nil {
+		picture, err := es.fetchAPOD(ctx, m[1])
+		if err != nil {
+			return nil, err
+		}
+		return []datasource.DataSourceTopic{{
+			Topic:     fmt.Sprintf("%s (Astronomy Picture of the Day, %s)", picture.Title, picture.Date),
+			SourceURL: apodLink(picture.Date),
+			Site:      "apod.nasa.gov",
+			TopicID:   es.items.Put("apod/"+picture.Date, item{Title: picture.Title, apod: picture}),
+		}}, nil
+	}
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("page_size", strconv.Itoa(count))
+	if len(es.MediaTypes) > 0 {
+		params.Set("media_type", strings.Join(es.MediaTypes, ","))
+	}
+	var response struct {
+		Collection struct {
+			Items []struct {
+				Data []item `json:"data"`
+			} `json:"items"`
+		} `json:"collection"`
+	}
+	if err := es.doJSON(ctx, es.BaseURL+"/search", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, entry := range response.Collection.Items {
+		if len(results) >= count {
+			break
+		}
+		if len(entry.Data) == 0 {
+			continue
+		}
+		i := entry.Data[0]
+		label := i.MediaType
+		if len(i.DateCreated) >= 10 {
+			label += ", " + i.DateCreated[:10]
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s (%s)", i.Title, label),
+			SourceURL: detailsLink(i.NASAID),
+			Site:      i.Center,
+			TopicID:   es.items.Put(i.NASAID, i),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the caption or APOD explanation with credits, keywords and links to the original media files
+func (es *DataSourceNASA) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	i, ok := es.items.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown NASA topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	if picture := i.apod; picture != nil {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s\nAstronomy Picture of the Day, %s", picture.Title, picture.Date)
+		if picture.Copyright != "" {
+			b.WriteString("\nCredit: " + strings.TrimSpace(picture.Copyright))
+		}
+		b.WriteString("\n\n" + picture.Explanation)
+		b.WriteString("\n\n" + titleCase(picture.MediaType) + ": " + picture.URL)
+		if picture.HDURL != "" {
+			b.WriteString("\nHigh resolution: " + picture.HDURL)
+		}
+		return []datasource.DataSourceData{{
+			DataText:  b.String(),
+			SourceURL: apodLink(picture.Date),
+			Site:      "apod.nasa.gov",
+			AnswerID:  topicID,
+		}}, nil
+	}
+
+	var b strings.Builder
+	b.WriteString(i.Title)
+	if description := strings.TrimSpace(htmlTag.ReplaceAllString(i.Description, "")); description != "" {
+		b.WriteString("\n\n" + description)
+	}
+	b.WriteString("\n")
+	if len(i.DateCreated) >= 10 {
+		b.WriteString("\nDate: " + i.DateCreated[:10])
+	}
+	if i.Center != "" {
+		b.WriteString("\nCenter: " + i.Center)
+	}
+	if i.Photographer != "" {
+		b.WriteString("\nPhotographer: " + i.Photographer)
+	}
+	if len(i.Keywords) > 0 {
+		b.WriteString("\nKeywords: " + strings.Join(i.Keywords, ", "))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	// Media links are a supplement; the caption stands without them
+	if links, err := es.fetchAssets(ctx, i); err == nil && len(links) > 0 {
+		b.WriteString("\n\nMedia:\n" + strings.Join(links, "\n"))
+	}
+	return []datasource.DataSourceData{{
+		DataText:  strings.TrimSpace(b.String()),
+		SourceURL: detailsLink(i.NASAID),
+		Site:      i.Center,
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// fetchAPOD returns the Astronomy Picture of the Day for date (YYYY-MM-DD), or today's when date is empty
+func (es *DataSourceNASA) fetchAPOD(ctx context.Context, date string) (*apod, error) {
+	params := url.Values{}
+	params.Set("api_key", es.APIKey)
+	if date != "" {
+		params.Set("date", date)
+	}
+	var picture apod
+	if err := es.doJSON(ctx, strings.TrimRight(es.APIURL, "/")+"/planetary/apod", params, &picture); err != nil {
+		return nil, err
+	}
+	return &picture, nil
+}
+
+// fetchAssets lists the original-quality files of a library item, skipping thumbnails and metadata
+func (es *DataSourceNASA) fetchAssets(ctx context.Context, i item) ([]string, error) {
+	var response struct {
+		Collection struct {
+			Items []struct {
+				Href string `json:"href"`
+			} `json:"items"`
+		} `json:"collection"`
+	}
+	if err := es.doJSON(ctx, strings.TrimRight(es.BaseURL, "/")+"/asset/"+url.PathEscape(i.NASAID), nil, &response); err != nil {
+		return nil, err
+	}
+	var links []string
+	for _, asset := range response.Collection.Items {
+		href := asset.Href
+		if strings.HasSuffix(href, ".json") || strings.Contains(href, "~thumb") || strings.HasSuffix(href, ".srt") || strings.HasSuffix(href, ".vtt") {
+			continue
+		}
+		// Originals and large renditions first
+		if strings.Contains(href, "~orig") || strings.Contains(href, "~large") {
+			links = append([]string{href}, links...)
+		} else {
+			links = append(links, href)
+		}
+	}
+	if len(links) > maxMediaLinks {
+		links = links[:maxMediaLinks]
+	}
+	return links, nil
+}
+
+// doJSON performs an HTTP GET and decodes the JSON response into target
+func (es *DataSourceNASA) doJSON(ctx context.Context, endpoint string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := endpoint
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("nasa request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func detailsLink(nasaID string) string {
+	return "https://images.nasa.gov/details/" + url.PathEscape(nasaID)
+}
+
+// apodLink builds the archive page URL, e.g. https://apod.nasa.gov/apod/ap240408.html
+func apodLink(date string) string {
+	compact := strings.ReplaceAll(date, "-", "")
+	if len(compact) != 8 {
+		return "https://apod.nasa.gov/apod/astropix.html"
+	}
+	return "https://apod.nasa.gov/apod/ap" + compact[2:] + ".html"
+}
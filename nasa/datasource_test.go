@@ -0,0 +1,98 @@
+package nasa
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "earthrise" || q.Get("page_size") != "5" || q.Get("media_type") != "image,video" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"collection":{"items":[
+			{"data":[]},
+			{"data":[{"nasa_id":"as08-14-2383","title":"Earthrise","description":"<p>Earth rising over the lunar horizon.</p>",
+				"date_created":"1968-12-24T00:00:00Z","center":"JSC","photographer":"Bill Anders","media_type":"image","keywords":["Apollo 8","Moon"]}]}
+		]}}`)
+	})
+	mux.HandleFunc("/asset/as08-14-2383", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"collection":{"items":[
+			{"href":"https://images-assets.nasa.gov/image/as08-14-2383/as08-14-2383~small.jpg"},
+			{"href":"https://images-assets.nasa.gov/image/as08-14-2383/as08-14-2383~thumb.jpg"},
+			{"href":"https://images-assets.nasa.gov/image/as08-14-2383/as08-14-2383~orig.jpg"},
+			{"href":"https://images-assets.nasa.gov/image/as08-14-2383/metadata.json"}
+		]}}`)
+	})
+	mux.HandleFunc("/planetary/apod", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("api_key") != "DEMO_KEY" || q.Get("date") != "2024-04-08" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"date":"2024-04-08","title":"Total Eclipse","explanation":"The Moon covers the Sun.",
+			"url":"https://apod.nasa.gov/apod/image/eclipse.jpg","hdurl":"https://apod.nasa.gov/apod/image/eclipse_hd.jpg",
+			"media_type":"image","copyright":" Jane Doe\n"}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSource(t *testing.T) *DataSourceNASA {
+	t.Helper()
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.APIURL = srv.URL + "/"
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "earthrise")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Earthrise (image, 1968-12-24)" || topics[0].Site != "JSC" ||
+		topics[0].SourceURL != "https://images.nasa.gov/details/as08-14-2383" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Earthrise\n\nEarth rising over the lunar horizon.\n\nDate: 1968-12-24\nCenter: JSC\nPhotographer: Bill Anders\nKeywords: Apollo 8, Moon" +
+		"\n\nMedia:\nhttps://images-assets.nasa.gov/image/as08-14-2383/as08-14-2383~orig.jpg" +
+		"\nhttps://images-assets.nasa.gov/image/as08-14-2383/as08-14-2383~small.jpg"
+	if len(data) != 1 || data[0].DataText != want {
+		t.Errorf("FetchData = %+v, want %q", data, want)
+	}
+}
+
+func TestFetchTopicsAPOD(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "APOD 2024-04-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Total Eclipse (Astronomy Picture of the Day, 2024-04-08)" ||
+		topics[0].SourceURL != "https://apod.nasa.gov/apod/ap240408.html" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Total Eclipse\nAstronomy Picture of the Day, 2024-04-08\nCredit: Jane Doe\n\nThe Moon covers the Sun." +
+		"\n\nImage: https://apod.nasa.gov/apod/image/eclipse.jpg\nHigh resolution: https://apod.nasa.gov/apod/image/eclipse_hd.jpg"
+	if len(data) != 1 || data[0].DataText != want {
+		t.Errorf("FetchData = %+v, want %q", data, want)
+	}
+}
@@ -0,0 +1,252 @@
+package newsapi
+
+// Data Source Adapter for NewsAPI (newsapi.org or a compatible service)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+// Endpoints
+const (
+	EndpointEverything   = "everything"    // Full archive search; supports Language, From, To, Domains and SortBy
+	EndpointTopHeadlines = "top-headlines" // Breaking headlines; supports Country and Category
+)
+
+// truncationMarker matches the "… [+2104 chars]" suffix NewsAPI appends to content snippets
+var truncationMarker = regexp.MustCompile(`\s*…?\s*\[\+\d+ chars\]$`)
+
+type DataSourceNewsAPI struct {
+	Client    *http.Client
+	BaseURL   string
+	APIKey    string
+	UserAgent string
+	Endpoint  string   // EndpointEverything or EndpointTopHeadlines
+	Language  string   // ISO 639-1 code, e.g. "en"
+	From      string   // Oldest article date, ISO 8601
+	To        string   // Newest article date, ISO 8601
+	Sources   []string // NewsAPI source IDs, e.g. "bbc-news"; cannot be combined with Country or Category
+	Domains   []string // Restrict to these domains, e.g. "reuters.com"
+	Country   string   // ISO 3166-1 code for top headlines, e.g. "us"
+	Category  string   // Top-headline category, e.g. "technology"
+	SortBy    string   // "relevancy", "popularity" or "publishedAt"
+
+	articles topicid.Map[article]
+}
+
+type article struct {
+	Source struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"source"`
+	Author      string `json:"author"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	PublishedAt string `json:"publishedAt"`
+	Content     string `json:"content"`
+}
+
+func New() *DataSourceNewsAPI {
+	return &DataSourceNewsAPI{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://newsapi.org/v2",
+		UserAgent: "locus/newsapi-datasource",
+		Endpoint:  EndpointEverything,
+		SortBy:    "relevancy",
+	}
+}
+
+// Init implements models.DataSource
+// Requires an API key
+func (es *DataSourceNewsAPI) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.APIKey == "" {
+		return errors.New("APIKey is required for NewsAPI DataSource")
+	}
+	if es.Endpoint != EndpointEverything && es.Endpoint != EndpointTopHeadlines {
+		return fmt.Errorf("unknown NewsAPI endpoint %q", es.Endpoint)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceNewsAPI) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("language", "en")
+	return es.doJSON(ctx, "/top-headlines/sources", params, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceNewsAPI) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "NewsAPI",
+		Homepage:            "https://newsapi.org",
+		Attribution:         "Powered by NewsAPI.org",
+		AttributionRequired: true,
+		TermsURL:            "https://newsapi.org/terms",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each article is a topic titled "Headline (Source, date)"
+func (es *DataSourceNewsAPI) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for NewsAPI DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("pageSize", strconv.Itoa(count))
+	if len(es.Sources) > 0 {
+		params.Set("sources", strings.Join(es.Sources, ","))
+	}
+	if es.Endpoint == EndpointEverything {
+		setIf(params, "language", es.Language)
+		setIf(params, "from", es.From)
+		setIf(params, "to", es.To)
+		setIf(params, "sortBy", es.SortBy)
+		if len(es.Domains) > 0 {
+			params.Set("domains", strings.Join(es.Domains, ","))
+		}
+	} else if len(es.Sources) == 0 {
+		setIf(params, "country", es.Country)
+		setIf(params, "category", es.Category)
+	}
+
+	var response struct {
+		Articles []article `json:"articles"`
+	}
+	if err := es.doJSON(ctx, "/"+es.Endpoint, params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Articles))
+	for _, item := range response.Articles {
+		// Articles withdrawn by the publisher are returned as "[Removed]" placeholders
+		if item.Title == "" || item.Title == "[Removed]" || item.URL == "" {
+			continue
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s (%s, %s)", item.Title, item.Source.Name, strings.SplitN(item.PublishedAt, "T", 2)[0]),
+			SourceURL: item.URL,
+			Site:      item.Source.Name,
+			TopicID:   es.articles.Put(item.URL, item),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the article description and content snippet. NewsAPI only carries the first ~200 characters of
+// the body; callers wanting the full text should fetch SourceURL.
+func (es *DataSourceNewsAPI) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	item, ok := es.articles.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown NewsAPI topicID %d", topicID)
+	}
+
+	parts := []string{item.Title}
+	if description := strings.TrimSpace(item.Description); description != "" {
+		parts = append(parts, description)
+	}
+	content := strings.TrimSpace(truncationMarker.ReplaceAllString(item.Content, ""))
+	// The snippet often just repeats the description
+	if content != "" && !strings.HasPrefix(item.Description, content) {
+		parts = append(parts, content)
+	}
+	byline := item.Source.Name
+	if item.Author != "" {
+		byline = item.Author + ", " + byline
+	}
+	parts = append(parts, fmt.Sprintf("(%s, %s)", byline, item.PublishedAt))
+
+	return []datasource.DataSourceData{{
+		DataText:  strings.Join(parts, "\n\n"),
+		SourceURL: item.URL,
+		Site:      item.Source.Name,
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// doJSON performs an HTTP GET request against the NewsAPI and decodes the JSON response into target
+func (es *DataSourceNewsAPI) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Api-Key", es.APIKey)
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Errors carry {"status":"error","code":"...","message":"..."}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		var failure struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &failure) == nil && failure.Message != "" {
+			return fmt.Errorf("newsapi request failed: status %d: %s: %s", resp.StatusCode, failure.Code, failure.Message)
+		}
+		return fmt.Errorf("newsapi request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func setIf(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
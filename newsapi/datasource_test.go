@@ -0,0 +1,95 @@
+package newsapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/everything", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "fusion" || q.Get("pageSize") != "5" || q.Get("language") != "en" || q.Get("domains") != "bbc.co.uk,reuters.com" || q.Get("sortBy") != "relevancy" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("X-Api-Key") != "key" {
+			t.Errorf("X-Api-Key = %q", r.Header.Get("X-Api-Key"))
+		}
+		fmt.Fprint(w, `{"status":"ok","articles":[
+			{"source":{"name":"[Removed]"},"title":"[Removed]","url":"https://removed.com"},
+			{"source":{"id":"reuters","name":"Reuters"},"author":"Jane Doe","title":"Fusion record","description":"Scientists sustained a plasma.","url":"https://reuters.com/fusion","publishedAt":"2024-02-08T12:00:00Z","content":"Scientists sustained a plasma for five seconds… [+2104 chars]"},
+			{"source":{"name":"BBC"},"title":"Fusion explained","description":"What fusion is.","url":"https://bbc.co.uk/fusion","publishedAt":"2024-02-09T08:00:00Z","content":"What fusion is."}
+		]}`)
+	})
+	mux.HandleFunc("/top-headlines", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("country") != "us" || q.Get("category") != "science" || q.Has("language") {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"status":"error","code":"apiKeyInvalid","message":"Your API key is invalid."}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.APIKey = "key"
+	es.Language = "en"
+	es.Domains = []string{"bbc.co.uk", "reuters.com"}
+
+	topics, err := es.FetchTopics(5, "fusion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[0].Topic != "Fusion record (Reuters, 2024-02-08)" || topics[1].Topic != "Fusion explained (BBC, 2024-02-09)" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	want := []string{
+		"Fusion record\n\nScientists sustained a plasma.\n\nScientists sustained a plasma for five seconds\n\n(Jane Doe, Reuters, 2024-02-08T12:00:00Z)",
+		"Fusion explained\n\nWhat fusion is.\n\n(BBC, 2024-02-09T08:00:00Z)",
+	}
+	for i, text := range want {
+		data, err := es.FetchData(1, topics[i].TopicID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != 1 || data[0].DataText != text || data[0].SourceURL != topics[i].SourceURL {
+			t.Errorf("FetchData(%d) = %+v, want %q", i, data, text)
+		}
+	}
+}
+
+func TestTopHeadlinesError(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.APIKey = "key"
+	es.Endpoint = EndpointTopHeadlines
+	es.Language = "en"
+	es.Country = "us"
+	es.Category = "science"
+
+	_, err := es.FetchTopics(5, "fusion")
+	if err == nil || err.Error() != "newsapi request failed: status 401: apiKeyInvalid: Your API key is invalid." {
+		t.Errorf("FetchTopics error = %v", err)
+	}
+}
+
+func TestInit(t *testing.T) {
+	es := New()
+	if err := es.Init(); err == nil {
+		t.Error("Init succeeded without an API key")
+	}
+	es.APIKey = "key"
+	es.Endpoint = "sources"
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted an unknown endpoint")
+	}
+}
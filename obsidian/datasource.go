@@ -0,0 +1,262 @@
+package obsidian
+
+// Data Source Adapter for an Obsidian vault: notes are searched with the localfs indexer, with wikilinks,
+// tags, aliases and backlinks taken into account
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/localfs"
+	"github.com/locus-search/datasource/source"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultDataCount  = 3
+	// Note contents are returned in chunks of about this many characters
+	chunkSize = 1500
+)
+
+type DataSourceObsidian struct {
+	Vault     string        // Vault directory
+	VaultName string        // Name used in obsidian:// links; default the directory name
+	MaxAge    time.Duration // Age after which the next search rebuilds the index; default 5 minutes
+	Site      string        // Site for topics and data; default "obsidian/<vault name>"
+
+	mu    sync.Mutex
+	index *localfs.Index
+	vault *vault
+	notes topicid.Map[localfs.Document]
+}
+
+func New() *DataSourceObsidian {
+	return &DataSourceObsidian{
+		MaxAge: 5 * time.Minute,
+	}
+}
+
+// Init implements models.DataSource
+// Builds the index and link graph of Vault
+func (es *DataSourceObsidian) Init() error {
+	if es.Vault == "" {
+		return errors.New("Vault is required for Obsidian DataSource")
+	}
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.index == nil {
+		root, err := filepath.Abs(es.Vault)
+		if err != nil {
+			return err
+		}
+		if es.VaultName == "" {
+			es.VaultName = filepath.Base(root)
+		}
+		es.index = localfs.NewIndex(root)
+		es.index.Extensions = []string{".md"}
+		es.index.Transform = transform
+	}
+	if es.index.Built().IsZero() {
+		return es.build()
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Reports whether Vault is a directory with an .obsidian settings folder
+func (es *DataSourceObsidian) CheckAvailability() bool {
+	info, err := os.Stat(filepath.Join(es.Vault, ".obsidian"))
+	return err == nil && info.IsDir()
+}
+
+// Info implements source.Informer
+func (es *DataSourceObsidian) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name: "Obsidian",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Notes are ranked by the localfs search, with extra weight for alias and tag matches and for notes
+// many others link to. Topics are titled by the note, with its backlink count, e.g. "Setup (3 backlinks)".
+func (es *DataSourceObsidian) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Obsidian DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+	v, err := es.current()
+	if err != nil {
+		return nil, err
+	}
+
+	phrase := strings.ToLower(strings.TrimPrefix(query, "#"))
+	scores := map[string]int{}
+	for _, m := range es.index.Search(query, 0) {
+		scores[m.Path] = m.Score
+	}
+	for p, doc := range v.notes {
+		for _, a := range aliases(doc) {
+			if alias := strings.ToLower(a); alias == phrase {
+				scores[p] += 20
+			} else if strings.Contains(alias, phrase) {
+				scores[p] += 10
+			}
+		}
+		for _, tag := range doc.Tags {
+			if t := strings.ToLower(tag); t == phrase || strings.HasPrefix(t, phrase+"/") {
+				scores[p] += 10
+			}
+		}
+	}
+	type scored struct {
+		path  string
+		score float64
+	}
+	ranked := make([]scored, 0, len(scores))
+	for p, score := range scores {
+		if score > 0 {
+			// Backlinks break ties and nudge hub notes up without outweighing the text match
+			ranked = append(ranked, scored{p, float64(score) + math.Log1p(float64(len(v.backlinks[p])))})
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].path < ranked[j].path
+	})
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, r := range ranked {
+		if len(results) >= count {
+			break
+		}
+		doc := v.notes[r.path]
+		topic := doc.Title
+		switch n := len(v.backlinks[r.path]); n {
+		case 0:
+		case 1:
+			topic += " (1 backlink)"
+		default:
+			topic += " (" + strconv.Itoa(n) + " backlinks)"
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     topic,
+			SourceURL: es.noteURL(doc.Path),
+			Site:      es.site(),
+			TopicID:   es.notes.Put(doc.Path, doc),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the note with its wikilinks resolved to note titles, in chunks of about 1500 characters
+// starting with the title. The first chunk also lists the note's aliases, tags and backlinks.
+func (es *DataSourceObsidian) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	doc, ok := es.notes.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Obsidian topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultDataCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+	v, err := es.current()
+	if err != nil {
+		return nil, err
+	}
+
+	var header []string
+	if a := aliases(doc); len(a) > 0 {
+		header = append(header, "Aliases: "+strings.Join(a, ", "))
+	}
+	if len(doc.Tags) > 0 {
+		header = append(header, "Tags: #"+strings.Join(doc.Tags, " #"))
+	}
+	if links := v.backlinks[doc.Path]; len(links) > 0 {
+		titles := make([]string, 0, len(links))
+		for _, p := range links {
+			titles = append(titles, v.notes[p].Title)
+		}
+		header = append(header, "Linked from: "+strings.Join(titles, ", "))
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, chunk := range localfs.Chunks(v.renderLinks(doc.Body), chunkSize) {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		text := doc.Title + "\n" + chunk
+		if len(results) == 0 && len(header) > 0 {
+			text = doc.Title + "\n" + strings.Join(header, "\n") + "\n\n" + chunk
+		}
+		if len(results) > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%s#%d", doc.Path, len(results)))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  text,
+			SourceURL: es.noteURL(doc.Path),
+			Site:      es.site(),
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// current returns the link graph, rebuilding the index first once it is older than MaxAge
+func (es *DataSourceObsidian) current() (*vault, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.MaxAge > 0 && time.Since(es.index.Built()) > es.MaxAge {
+		if err := es.build(); err != nil {
+			return nil, err
+		}
+	}
+	return es.vault, nil
+}
+
+// build rebuilds the index and the link graph; es.mu must be held
+func (es *DataSourceObsidian) build() error {
+	if err := es.index.Build(); err != nil {
+		return err
+	}
+	es.vault = newVault(es.index.Documents())
+	return nil
+}
+
+// noteURL returns an obsidian:// link that opens the note in the app
+func (es *DataSourceObsidian) noteURL(notePath string) string {
+	return "obsidian://open?vault=" + url.PathEscape(es.VaultName) +
+		"&file=" + url.PathEscape(strings.TrimSuffix(notePath, path.Ext(notePath)))
+}
+
+func (es *DataSourceObsidian) site() string {
+	if es.Site != "" {
+		return es.Site
+	}
+	return "obsidian/" + es.VaultName
+}
@@ -0,0 +1,93 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeVault(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "My Vault")
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	root := writeVault(t, map[string]string{
+		".obsidian/app.json":  "{}",
+		"Projects/Backups.md": "---\naliases: [Restore plan, DR]\n---\n# Backups\n\nRun the backup nightly, see [[Storage#Quotas]] and [[storage|the disks]].\n\nTags live inline: #ops/backup\n\n%%private note about backup%%",
+		"Storage.md":          "# Storage\n\nDisks and quotas. Links back to [[Restore plan]].\n\n![[diagram.png]] ![[Ideas]]",
+		"Daily/2024-05-01.md": "Checked the backup logs with `#notatag` in [[Backups]].",
+		"Ideas.md":            "# Ideas\n\nNothing relevant.",
+	})
+	es := New()
+	es.Vault = root
+	if !es.CheckAvailability() {
+		t.Error("CheckAvailability = false for a vault")
+	}
+
+	topics, err := es.FetchTopics(5, "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Backups (2 backlinks)", "2024-05-01"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if topics[i].Topic != w || topics[i].Site != "obsidian/My Vault" {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, w)
+		}
+	}
+	if topics[0].SourceURL != "obsidian://open?vault=My%20Vault&file=Projects%2FBackups" {
+		t.Errorf("SourceURL = %s", topics[0].SourceURL)
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "Backups\nAliases: Restore plan, DR\nTags: #ops/backup\nLinked from: 2024-05-01, Storage\n\n" +
+		"# Backups\n\nRun the backup nightly, see Storage > Quotas and the disks.\n\nTags live inline: #ops/backup"
+	if len(data) != 1 || data[0].DataText != wantData {
+		t.Errorf("FetchData = %q, want %q", data, wantData)
+	}
+}
+
+func TestAliasAndTagSearch(t *testing.T) {
+	root := writeVault(t, map[string]string{
+		"Backups.md": "---\naliases: Restore plan\ntags: ops\n---\nNightly jobs.",
+		"Other.md":   "Mentions a restore plan in passing.",
+		"Tagged.md":  "Inline #ops/oncall tag.",
+	})
+	es := New()
+	es.Vault = root
+
+	topics, err := es.FetchTopics(5, "restore plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) == 0 || topics[0].Topic != "Backups" {
+		t.Errorf("alias search = %+v", topics)
+	}
+	topics, err = es.FetchTopics(5, "#ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := map[string]bool{}
+	for _, topic := range topics {
+		titles[topic.Topic] = true
+	}
+	if len(topics) != 2 || !titles["Backups"] || !titles["Tagged"] {
+		t.Errorf("tag search = %+v", topics)
+	}
+}
@@ -0,0 +1,143 @@
+package obsidian
+
+// Wikilink, tag and alias handling for Obsidian vaults
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/locus-search/datasource/localfs"
+)
+
+var (
+	// [[target]], [[target#heading]], [[target#^block]], [[target|shown text]] and the ![[embed]] forms
+	wikilink = regexp.MustCompile(`(!?)\[\[([^\[\]|#]*)(#[^\[\]|]*)?(?:\|([^\[\]]*))?\]\]`)
+	// Inline #tags; nested tags use slashes and a tag needs at least one non-digit
+	inlineTag = regexp.MustCompile(`(?:^|[\s(,])#([\p{L}\p{N}_/-]*[\p{L}_/-][\p{L}\p{N}_/-]*)`)
+	// Fenced code blocks, inline code and %%comments%%, which hold no tags or links
+	code    = regexp.MustCompile("(?s)```.*?```|`[^`\n]*`")
+	comment = regexp.MustCompile(`(?s)%%.*?%%`)
+)
+
+// vault is the link graph of an index build
+type vault struct {
+	notes     map[string]localfs.Document // By path
+	names     map[string]string           // Lowercase path, file name or alias without ".md" to path
+	backlinks map[string][]string         // Path to the paths linking to it, sorted
+}
+
+// transform is the index Transform: it drops comments and adds inline tags and front matter aliases
+func transform(doc *localfs.Document) bool {
+	doc.Body = strings.TrimSpace(comment.ReplaceAllString(doc.Body, ""))
+	seen := map[string]bool{}
+	for _, tag := range doc.Tags {
+		seen[strings.ToLower(tag)] = true
+	}
+	for _, m := range inlineTag.FindAllStringSubmatch(code.ReplaceAllString(doc.Body, ""), -1) {
+		tag := strings.TrimRight(m[1], "/")
+		if tag != "" && !seen[strings.ToLower(tag)] {
+			seen[strings.ToLower(tag)] = true
+			doc.Tags = append(doc.Tags, tag)
+		}
+	}
+	return true
+}
+
+// aliases returns the front matter "aliases" (or "alias") of a note
+func aliases(doc localfs.Document) []string {
+	value := doc.Meta["aliases"]
+	if value == "" {
+		value = doc.Meta["alias"]
+	}
+	var out []string
+	for _, a := range strings.Split(value, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// newVault resolves every wikilink of the documents to build the backlink map
+func newVault(docs []localfs.Document) *vault {
+	v := &vault{
+		notes:     make(map[string]localfs.Document, len(docs)),
+		names:     make(map[string]string, len(docs)*2),
+		backlinks: map[string][]string{},
+	}
+	// Full paths win over file names, which win over aliases, as in Obsidian
+	for _, doc := range docs {
+		v.notes[doc.Path] = doc
+		for _, a := range aliases(doc) {
+			v.names[strings.ToLower(a)] = doc.Path
+		}
+	}
+	for _, doc := range docs {
+		v.names[noteName(path.Base(doc.Path))] = doc.Path
+	}
+	for _, doc := range docs {
+		v.names[noteName(doc.Path)] = doc.Path
+	}
+
+	for _, doc := range docs {
+		linked := map[string]bool{}
+		for _, m := range wikilink.FindAllStringSubmatch(code.ReplaceAllString(doc.Body, ""), -1) {
+			target, ok := v.resolve(m[2])
+			if ok && target != doc.Path && !linked[target] {
+				linked[target] = true
+				v.backlinks[target] = append(v.backlinks[target], doc.Path)
+			}
+		}
+	}
+	for target := range v.backlinks {
+		sort.Strings(v.backlinks[target])
+	}
+	return v
+}
+
+// resolve finds the note a link target names
+func (v *vault) resolve(target string) (string, bool) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return "", false
+	}
+	p, ok := v.names[noteName(target)]
+	return p, ok
+}
+
+// renderLinks replaces wikilinks with readable text: the shown text when given, else the linked note's
+// title and heading, e.g. "[[setup#Install]]" gives "Setup guide > Install". Embeds of notes become
+// "(embedded: Title)" and embeds of other files are dropped.
+func (v *vault) renderLinks(body string) string {
+	return wikilink.ReplaceAllStringFunc(body, func(link string) string {
+		m := wikilink.FindStringSubmatch(link)
+		embed, target, heading, shown := m[1] == "!", m[2], strings.TrimPrefix(m[3], "#"), m[4]
+		notePath, ok := v.resolve(target)
+		title := strings.TrimSpace(target)
+		if ok {
+			title = v.notes[notePath].Title
+		}
+		if embed {
+			if !ok {
+				return ""
+			}
+			return "(embedded: " + title + ")"
+		}
+		if shown = strings.TrimSpace(shown); shown != "" {
+			return shown
+		}
+		if heading != "" && !strings.HasPrefix(heading, "^") {
+			if title == "" {
+				return heading
+			}
+			return title + " > " + heading
+		}
+		return title
+	})
+}
+
+// noteName normalizes a link target or path for lookup: lowercase, without the ".md" extension
+func noteName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), ".md"))
+}
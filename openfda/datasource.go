@@ -0,0 +1,499 @@
+package openfda
+
+// Data Source Adapter for openFDA drug labels, adverse event reports and recalls
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+// Label sections returned as data, in order
+var labelSections = []struct{ Field, Label string }{
+	{"boxed_warning", "Boxed warning"},
+	{"indications_and_usage", "Indications and usage"},
+	{"dosage_and_administration", "Dosage and administration"},
+	{"contraindications", "Contraindications"},
+	{"warnings_and_cautions", "Warnings and precautions"},
+	{"warnings", "Warnings"},
+	{"adverse_reactions", "Adverse reactions"},
+	{"drug_interactions", "Drug interactions"},
+	{"use_in_specific_populations", "Use in specific populations"},
+	{"overdosage", "Overdosage"},
+}
+
+type DataSourceOpenFDA struct {
+	Client    *http.Client
+	BaseURL   string
+	UserAgent string
+	APIKey    string   // Optional; raises the daily limit from 1,000 to 120,000 requests
+	Scopes    []string // Any of "labels", "events" and "recalls"
+
+	refs topicid.Map[ref]
+}
+
+// ref holds a record's labeled sections, which the search response already carries in full
+type ref struct {
+	Title    string
+	URL      string
+	Site     string
+	Sections []section
+}
+
+type section struct {
+	Label string
+	Text  string
+}
+
+func New() *DataSourceOpenFDA {
+	return &DataSourceOpenFDA{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://api.fda.gov",
+		UserAgent: "locus/openfda-datasource",
+		Scopes:    []string{"labels", "events", "recalls"},
+	}
+}
+
+// Init implements models.DataSource
+// Validates the configured search scopes
+func (es *DataSourceOpenFDA) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	for _, scope := range es.Scopes {
+		switch scope {
+		case "labels", "events", "recalls":
+		default:
+			return fmt.Errorf("unsupported openFDA search scope %q", scope)
+		}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceOpenFDA) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("limit", "1")
+	var response struct{}
+	return es.doJSON(ctx, "/drug/label.json", params, &response) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceOpenFDA) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "openFDA",
+		Homepage:    "https://open.fda.gov",
+		License:     "CC0-1.0",
+		LicenseURL:  "https://creativecommons.org/publicdomain/zero/1.0/",
+		Attribution: "openFDA, U.S. Food and Drug Administration",
+		TermsURL:    "https://open.fda.gov/terms/",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Searches every configured scope by drug or product name and interleaves the results: labels are
+// titled "Brand (generic) label", events by their reactions and recalls by product and class
+func (es *DataSourceOpenFDA) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(strings.ReplaceAll(input, `"`, ""))
+	if query == "" {
+		return nil, errors.New("Missing search input for openFDA DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	perScope := make([][]datasource.DataSourceTopic, 0, len(es.Scopes))
+	var firstErr error
+	for _, scope := range es.Scopes {
+		var topics []datasource.DataSourceTopic
+		var err error
+		switch scope {
+		case "labels":
+			topics, err = es.searchLabels(ctx, query, count)
+		case "events":
+			topics, err = es.searchEvents(ctx, query, count)
+		case "recalls":
+			topics, err = es.searchRecalls(ctx, query, count)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		perScope = append(perScope, topics)
+	}
+	if len(perScope) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for i := 0; len(results) < count; i++ {
+		added := false
+		for _, topics := range perScope {
+			if i < len(topics) && len(results) < count {
+				results = append(results, topics[i])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns one item per labeled section: label sections such as indications and warnings, or the
+// details of an adverse event report or recall
+func (es *DataSourceOpenFDA) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	item, ok := es.refs.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown openFDA topicID %d", topicID)
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for i, s := range item.Sections {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		if i > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%s#%s", item.URL, s.Label))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  item.Title + "\n" + s.Label + ":\n" + s.Text,
+			SourceURL: item.URL,
+			Site:      item.Site,
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+func (es *DataSourceOpenFDA) searchLabels(ctx context.Context, query string, limit int) ([]datasource.DataSourceTopic, error) {
+	var response struct {
+		Results []map[string]json.RawMessage `json:"results"`
+	}
+	search := fmt.Sprintf(`openfda.brand_name:"%[1]s" openfda.generic_name:"%[1]s" openfda.substance_name:"%[1]s"`, query)
+	if err := es.search(ctx, "/drug/label.json", search, limit, &response); err != nil {
+		return nil, err
+	}
+	topics := make([]datasource.DataSourceTopic, 0, len(response.Results))
+	for _, record := range response.Results {
+		// Section fields vary by label, so the record is kept raw and only the metadata is decoded
+		var meta struct {
+			SetID   string
+			OpenFDA struct {
+				BrandName        []string `json:"brand_name"`
+				GenericName      []string `json:"generic_name"`
+				ManufacturerName []string `json:"manufacturer_name"`
+			}
+		}
+		if json.Unmarshal(record["set_id"], &meta.SetID) != nil || meta.SetID == "" {
+			continue
+		}
+		if raw, ok := record["openfda"]; ok {
+			_ = json.Unmarshal(raw, &meta.OpenFDA)
+		}
+		brand, generic := first(meta.OpenFDA.BrandName), first(meta.OpenFDA.GenericName)
+		title := brand
+		switch {
+		case title == "":
+			title = generic
+		case generic != "" && !strings.EqualFold(generic, brand):
+			title += " (" + strings.ToLower(generic) + ")"
+		}
+		if title == "" {
+			title = meta.SetID
+		}
+		item := ref{
+			Title: title + " label",
+			URL:   "https://dailymed.nlm.nih.gov/dailymed/lookup.cfm?setid=" + url.QueryEscape(meta.SetID),
+			Site:  first(meta.OpenFDA.ManufacturerName),
+		}
+		if item.Site == "" {
+			item.Site = "openFDA"
+		}
+		for _, s := range labelSections {
+			var paragraphs []string
+			if err := json.Unmarshal(record[s.Field], &paragraphs); err == nil && len(paragraphs) > 0 {
+				item.Sections = append(item.Sections, section{s.Label, strings.Join(paragraphs, "\n\n")})
+			}
+		}
+		topics = append(topics, datasource.DataSourceTopic{
+			Topic:     item.Title,
+			SourceURL: item.URL,
+			Site:      item.Site,
+			TopicID:   es.refs.Put("label:"+meta.SetID, item),
+		})
+	}
+	return topics, nil
+}
+
+func (es *DataSourceOpenFDA) searchEvents(ctx context.Context, query string, limit int) ([]datasource.DataSourceTopic, error) {
+	var response struct {
+		Results []struct {
+			SafetyReportID string `json:"safetyreportid"`
+			ReceiveDate    string `json:"receivedate"`
+			Serious        string `json:"serious"`
+			Patient        struct {
+				Age      string `json:"patientonsetage"`
+				AgeUnit  string `json:"patientonsetageunit"`
+				Sex      string `json:"patientsex"`
+				Reaction []struct {
+					Term    string `json:"reactionmeddrapt"`
+					Outcome string `json:"reactionoutcome"`
+				} `json:"reaction"`
+				Drug []struct {
+					Product          string `json:"medicinalproduct"`
+					Characterization string `json:"drugcharacterization"`
+					Indication       string `json:"drugindication"`
+				} `json:"drug"`
+			} `json:"patient"`
+		} `json:"results"`
+	}
+	search := fmt.Sprintf(`patient.drug.openfda.brand_name:"%[1]s" patient.drug.openfda.generic_name:"%[1]s" patient.drug.medicinalproduct:"%[1]s"`, query)
+	if err := es.search(ctx, "/drug/event.json", search, limit, &response); err != nil {
+		return nil, err
+	}
+	topics := make([]datasource.DataSourceTopic, 0, len(response.Results))
+	for _, r := range response.Results {
+		if r.SafetyReportID == "" {
+			continue
+		}
+		var reactions, drugs []string
+		for _, reaction := range r.Patient.Reaction {
+			term := strings.ToLower(reaction.Term)
+			if outcome := reactionOutcomes[reaction.Outcome]; outcome != "" {
+				term += " (" + outcome + ")"
+			}
+			reactions = append(reactions, term)
+		}
+		for _, d := range r.Patient.Drug {
+			drug := d.Product
+			if role := drugRoles[d.Characterization]; role != "" {
+				drug += " (" + role + ")"
+			}
+			if d.Indication != "" {
+				drug += " for " + strings.ToLower(d.Indication)
+			}
+			drugs = append(drugs, drug)
+		}
+		date := isoDate(r.ReceiveDate)
+		details := []string{"received: " + date}
+		if r.Serious == "1" {
+			details = append(details, "serious: yes")
+		} else {
+			details = append(details, "serious: no")
+		}
+		if r.Patient.Age != "" {
+			details = append(details, "patient age: "+r.Patient.Age+ageUnits[r.Patient.AgeUnit])
+		}
+		if sex := sexes[r.Patient.Sex]; sex != "" {
+			details = append(details, "patient sex: "+sex)
+		}
+		item := ref{
+			Title: fmt.Sprintf("Adverse event report %s (%s): %s", r.SafetyReportID, date, summarize(strings.Join(reactions, ", "), 100)),
+			URL:   es.apiURL("/drug/event.json", `safetyreportid:"`+r.SafetyReportID+`"`),
+			Site:  "FDA Adverse Event Reporting System",
+			Sections: []section{
+				{"Reactions", strings.Join(reactions, "\n")},
+				{"Drugs", strings.Join(drugs, "\n")},
+				{"Report", strings.Join(details, "\n")},
+			},
+		}
+		topics = append(topics, datasource.DataSourceTopic{
+			Topic:     item.Title,
+			SourceURL: item.URL,
+			Site:      item.Site,
+			TopicID:   es.refs.Put("event:"+r.SafetyReportID, item),
+		})
+	}
+	return topics, nil
+}
+
+func (es *DataSourceOpenFDA) searchRecalls(ctx context.Context, query string, limit int) ([]datasource.DataSourceTopic, error) {
+	var response struct {
+		Results []struct {
+			RecallNumber         string `json:"recall_number"`
+			Classification       string `json:"classification"`
+			Status               string `json:"status"`
+			ProductDescription   string `json:"product_description"`
+			ReasonForRecall      string `json:"reason_for_recall"`
+			RecallingFirm        string `json:"recalling_firm"`
+			DistributionPattern  string `json:"distribution_pattern"`
+			RecallInitiationDate string `json:"recall_initiation_date"`
+			CodeInfo             string `json:"code_info"`
+		} `json:"results"`
+	}
+	search := fmt.Sprintf(`product_description:"%[1]s" reason_for_recall:"%[1]s" openfda.brand_name:"%[1]s"`, query)
+	if err := es.search(ctx, "/drug/enforcement.json", search, limit, &response); err != nil {
+		return nil, err
+	}
+	topics := make([]datasource.DataSourceTopic, 0, len(response.Results))
+	for _, r := range response.Results {
+		if r.RecallNumber == "" {
+			continue
+		}
+		details := []string{
+			"classification: " + r.Classification,
+			"status: " + r.Status,
+			"recalling firm: " + r.RecallingFirm,
+			"initiated: " + isoDate(r.RecallInitiationDate),
+		}
+		if r.DistributionPattern != "" {
+			details = append(details, "distribution: "+r.DistributionPattern)
+		}
+		if r.CodeInfo != "" {
+			details = append(details, "lots: "+r.CodeInfo)
+		}
+		item := ref{
+			Title: fmt.Sprintf("Recall %s: %s (%s, %s)", r.RecallNumber, summarize(r.ProductDescription, 80), r.Classification, r.Status),
+			URL:   es.apiURL("/drug/enforcement.json", `recall_number:"`+r.RecallNumber+`"`),
+			Site:  r.RecallingFirm,
+			Sections: []section{
+				{"Reason for recall", r.ReasonForRecall},
+				{"Product", r.ProductDescription},
+				{"Recall", strings.Join(details, "\n")},
+			},
+		}
+		if item.Site == "" {
+			item.Site = "openFDA"
+		}
+		topics = append(topics, datasource.DataSourceTopic{
+			Topic:     item.Title,
+			SourceURL: item.URL,
+			Site:      item.Site,
+			TopicID:   es.refs.Put("recall:"+r.RecallNumber, item),
+		})
+	}
+	return topics, nil
+}
+
+// search runs an openFDA query; "No matches found" answers 404, which is an empty result
+func (es *DataSourceOpenFDA) search(ctx context.Context, path, search string, limit int, target interface{}) error {
+	params := url.Values{}
+	params.Set("search", search)
+	params.Set("limit", strconv.Itoa(limit))
+	err := es.doJSON(ctx, path, params, target)
+	if err != nil && strings.Contains(err.Error(), "status 404") {
+		return nil
+	}
+	return err
+}
+
+// apiURL links a single record by its search, since events and recalls have no public web page
+func (es *DataSourceOpenFDA) apiURL(path, search string) string {
+	params := url.Values{}
+	params.Set("search", search)
+	return strings.TrimRight(es.BaseURL, "/") + path + "?" + params.Encode()
+}
+
+// doJSON performs an HTTP GET request against the openFDA API and decodes the JSON response into target
+func (es *DataSourceOpenFDA) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.APIKey != "" {
+		params.Set("api_key", es.APIKey)
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri += "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("openfda request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+
+// Codes used by adverse event reports
+var (
+	reactionOutcomes = map[string]string{
+		"1": "recovered", "2": "recovering", "3": "not recovered", "4": "recovered with sequelae", "5": "fatal",
+	}
+	drugRoles = map[string]string{"1": "suspect", "2": "concomitant", "3": "interacting"}
+	sexes     = map[string]string{"1": "male", "2": "female"}
+	ageUnits  = map[string]string{
+		"800": " decades", "801": " years", "802": " months", "803": " weeks", "804": " days", "805": " hours",
+	}
+)
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
+
+// isoDate turns openFDA's YYYYMMDD dates into YYYY-MM-DD
+func isoDate(date string) string {
+	if len(date) != 8 {
+		return date
+	}
+	return date[:4] + "-" + date[4:6] + "-" + date[6:]
+}
+
+// summarize shortens text to at most limit runes on a word boundary
+func summarize(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	cut := string(runes[:limit])
+	if i := strings.LastIndex(cut, " "); i > limit/2 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
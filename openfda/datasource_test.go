@@ -0,0 +1,151 @@
+package openfda
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	check := func(r *http.Request, field string) {
+		q := r.URL.Query()
+		if !strings.Contains(q.Get("search"), field+`:"ibuprofen"`) || q.Get("limit") != "5" || q.Get("api_key") != "key" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+	}
+	mux.HandleFunc("GET /drug/label.json", func(w http.ResponseWriter, r *http.Request) {
+		check(r, "openfda.generic_name")
+		fmt.Fprint(w, `{"meta":{},"results":[
+			{"set_id":"abc-123","openfda":{"brand_name":["Advil"],"generic_name":["IBUPROFEN"],"manufacturer_name":["Pfizer"]},
+				"indications_and_usage":["Temporarily relieves minor aches."],"warnings":["Allergy alert.","Stomach bleeding warning."],
+				"spl_product_data_elements":["unused"]},
+			{"set_id":"def-456","openfda":{"generic_name":["ibuprofen"]}},
+			{"openfda":{"brand_name":["No set ID"]}}
+		]}`)
+	})
+	mux.HandleFunc("GET /drug/event.json", func(w http.ResponseWriter, r *http.Request) {
+		check(r, "patient.drug.medicinalproduct")
+		fmt.Fprint(w, `{"results":[{"safetyreportid":"10003300","receivedate":"20140312","serious":"1",
+			"patient":{"patientonsetage":"56","patientonsetageunit":"801","patientsex":"2",
+				"reaction":[{"reactionmeddrapt":"NAUSEA","reactionoutcome":"1"},{"reactionmeddrapt":"Headache"}],
+				"drug":[{"medicinalproduct":"IBUPROFEN","drugcharacterization":"1","drugindication":"PAIN"}]}}]}`)
+	})
+	mux.HandleFunc("GET /drug/enforcement.json", func(w http.ResponseWriter, r *http.Request) {
+		check(r, "product_description")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":{"code":"NOT_FOUND","message":"No matches found!"}}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.APIKey = "key"
+
+	topics, err := es.FetchTopics(5, `"ibuprofen"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Labels and events are interleaved; no recall matches
+	want := []string{
+		"Advil (ibuprofen) label|Pfizer",
+		"Adverse event report 10003300 (2014-03-12): nausea (recovered), headache|FDA Adverse Event Reporting System",
+		"ibuprofen label|openFDA",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].Site; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+	if topics[0].SourceURL != "https://dailymed.nlm.nih.gov/dailymed/lookup.cfm?setid=abc-123" {
+		t.Errorf("label SourceURL = %s", topics[0].SourceURL)
+	}
+	if topics[1].SourceURL != srv.URL+"/drug/event.json?search=safetyreportid%3A%2210003300%22" {
+		t.Errorf("event SourceURL = %s", topics[1].SourceURL)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := []string{
+		"Advil (ibuprofen) label\nIndications and usage:\nTemporarily relieves minor aches.",
+		"Advil (ibuprofen) label\nWarnings:\nAllergy alert.\n\nStomach bleeding warning.",
+	}
+	if len(data) != len(wantData) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, w := range wantData {
+		if data[i].DataText != w {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, w)
+		}
+	}
+
+	data, err = es.FetchData(5, topics[1].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 3 || !strings.HasSuffix(data[1].DataText, "Drugs:\nIBUPROFEN (suspect) for pain") ||
+		!strings.HasSuffix(data[2].DataText, "Report:\nreceived: 2014-03-12\nserious: yes\npatient age: 56 years\npatient sex: female") {
+		t.Errorf("FetchData = %q", data)
+	}
+}
+
+func TestRecalls(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"results":[{"recall_number":"D-0001-2024","classification":"Class II","status":"Ongoing",
+			"product_description":"Ibuprofen Tablets, 200 mg, 100-count bottles","reason_for_recall":"Failed dissolution specifications",
+			"recalling_firm":"Acme Pharma","recall_initiation_date":"20240105","code_info":"Lot 123"}]}`)
+	}))
+	defer srv.Close()
+	es := New()
+	es.BaseURL = srv.URL
+	es.Scopes = []string{"recalls"}
+
+	topics, err := es.FetchTopics(5, "ibuprofen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Recall D-0001-2024: Ibuprofen Tablets, 200 mg, 100-count bottles (Class II, Ongoing)" || topics[0].Site != "Acme Pharma" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || !strings.HasSuffix(data[0].DataText, "Reason for recall:\nFailed dissolution specifications") {
+		t.Errorf("FetchData = %q", data)
+	}
+}
+
+func TestFetchTopicsFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"code":"API_KEY_INVALID"}}`, http.StatusForbidden)
+	}))
+	defer srv.Close()
+	es := New()
+	es.BaseURL = srv.URL
+	if _, err := es.FetchTopics(5, "ibuprofen"); err == nil || !strings.Contains(err.Error(), "API_KEY_INVALID") {
+		t.Errorf("FetchTopics = %v", err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	if got := summarize("one  two three four", 12); got != "one two…" {
+		t.Errorf("summarize = %q", got)
+	}
+	if got := summarize("short", 12); got != "short" {
+		t.Errorf("summarize = %q", got)
+	}
+}
@@ -0,0 +1,318 @@
+package opentdb
+
+// Data Source Adapter for Open Trivia DB question sets
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultTopicCount    = 5
+	defaultQuestionCount = 10
+	// The API serves at most 50 questions per request
+	maxQuestions = 50
+	// The API allows one request per IP every five seconds
+	ratePeriod = 5 * time.Second
+)
+
+// responseCodes explains the API's non-zero response_code values
+var responseCodes = map[int]string{
+	1: "not enough questions for the query",
+	2: "invalid parameter",
+	3: "session token not found",
+	4: "session token exhausted",
+	5: "rate limited",
+}
+
+type DataSourceOpenTDB struct {
+	Client     *http.Client
+	BaseURL    string
+	UserAgent  string
+	Difficulty string // "easy", "medium" or "hard"; empty for any
+	Type       string // "multiple" or "boolean"; empty for any
+
+	mu          sync.Mutex
+	categories  []category
+	rateLimiter *rate.Limiter
+	topics      topicid.Map[category]
+}
+
+type category struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func New() *DataSourceOpenTDB {
+	return &DataSourceOpenTDB{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://opentdb.com",
+		UserAgent: "locus/opentdb-datasource",
+	}
+}
+
+// Init implements models.DataSource
+// The API allows one request every five seconds per IP
+func (es *DataSourceOpenTDB) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	switch es.Difficulty {
+	case "", "easy", "medium", "hard":
+	default:
+		return fmt.Errorf("unknown Open Trivia DB difficulty %q", es.Difficulty)
+	}
+	switch es.Type {
+	case "", "multiple", "boolean":
+	default:
+		return fmt.Errorf("unknown Open Trivia DB question type %q", es.Type)
+	}
+	if es.rateLimiter == nil {
+		es.rateLimiter = rate.NewLimiter(rate.Every(ratePeriod), 1)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceOpenTDB) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.loadCategories(ctx)
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceOpenTDB) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "Open Trivia Database",
+		Homepage:            "https://opentdb.com",
+		License:             "CC-BY-SA-4.0",
+		LicenseURL:          "https://creativecommons.org/licenses/by-sa/4.0/",
+		Attribution:         "Open Trivia Database (https://opentdb.com), CC BY-SA 4.0",
+		AttributionRequired: true,
+	}
+}
+
+// Describe implements source.Describer
+func (es *DataSourceOpenTDB) Describe() source.Capabilities {
+	return source.Capabilities{Name: es.Info().Name, Data: true, RateLimit: source.RateLimit{Requests: 1, Per: ratePeriod}}
+}
+
+// FetchTopics implements models.DataSource
+// Topics are the trivia categories whose names share a word with the query, e.g. "film" matches
+// "Entertainment: Film"; categories matching more query words come first
+func (es *DataSourceOpenTDB) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Open Trivia DB DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	categories, err := es.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		category category
+		score    int
+	}
+	var matches []match
+	words := strings.Fields(strings.ToLower(query))
+	for _, c := range categories {
+		name := strings.ToLower(c.Name)
+		score := 0
+		for _, word := range words {
+			if strings.Contains(name, word) {
+				score++
+			}
+		}
+		if score > 0 {
+			matches = append(matches, match{category: c, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, m := range matches {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     m.category.Name,
+			SourceURL: "https://opentdb.com/browse.php?type=Category&query=" + url.QueryEscape(m.category.Name),
+			Site:      "opentdb.com",
+			TopicID:   es.topics.Put(strconv.Itoa(m.category.ID), m.category),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns count questions from the category (10 by default, at most 50), each with its correct
+// answer, the other choices, and its difficulty
+func (es *DataSourceOpenTDB) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	c, ok := es.topics.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Open Trivia DB topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultQuestionCount
+	}
+	if count > maxQuestions {
+		count = maxQuestions
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	// The rate limiter may hold the request for up to one period before it is sent
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data+ratePeriod)
+	defer cancel()
+	params := url.Values{}
+	params.Set("amount", strconv.Itoa(count))
+	params.Set("category", strconv.Itoa(c.ID))
+	// RFC 3986 encoding avoids the HTML entities of the default encoding
+	params.Set("encode", "url3986")
+	if es.Difficulty != "" {
+		params.Set("difficulty", es.Difficulty)
+	}
+	if es.Type != "" {
+		params.Set("type", es.Type)
+	}
+	var response struct {
+		ResponseCode int `json:"response_code"`
+		Results      []struct {
+			Type             string   `json:"type"`
+			Difficulty       string   `json:"difficulty"`
+			Question         string   `json:"question"`
+			CorrectAnswer    string   `json:"correct_answer"`
+			IncorrectAnswers []string `json:"incorrect_answers"`
+		} `json:"results"`
+	}
+	if err := es.doJSON(ctx, "/api.php", params, &response); err != nil {
+		return nil, err
+	}
+	if response.ResponseCode != 0 {
+		return nil, fmt.Errorf("opentdb request failed: response code %d: %s", response.ResponseCode, responseCodes[response.ResponseCode])
+	}
+
+	results := make([]datasource.DataSourceData, 0, len(response.Results))
+	for _, q := range response.Results {
+		question := unescape(q.Question)
+		answer := unescape(q.CorrectAnswer)
+		var b strings.Builder
+		b.WriteString(question)
+		b.WriteString("\nAnswer: " + answer)
+		if q.Type == "multiple" {
+			choices := []string{answer}
+			for _, incorrect := range q.IncorrectAnswers {
+				choices = append(choices, unescape(incorrect))
+			}
+			sort.Strings(choices)
+			b.WriteString("\nChoices: " + strings.Join(choices, "; "))
+		} else {
+			b.WriteString("\nChoices: True; False")
+		}
+		b.WriteString("\nDifficulty: " + unescape(q.Difficulty))
+		results = append(results, datasource.DataSourceData{
+			DataText:  b.String(),
+			SourceURL: "https://opentdb.com/",
+			Site:      c.Name,
+			AnswerID:  topicid.Hash(strconv.Itoa(c.ID) + "/" + question),
+		})
+	}
+	return results, nil
+}
+
+// loadCategories returns the category list, which is requested once and kept for the adapter's lifetime
+func (es *DataSourceOpenTDB) loadCategories(ctx context.Context) ([]category, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.categories != nil {
+		return es.categories, nil
+	}
+	var response struct {
+		TriviaCategories []category `json:"trivia_categories"`
+	}
+	if err := es.doJSON(ctx, "/api_category.php", url.Values{}, &response); err != nil {
+		return nil, err
+	}
+	es.categories = response.TriviaCategories
+	return es.categories, nil
+}
+
+// doJSON waits for the rate limiter, performs an API request and decodes the JSON response into target
+func (es *DataSourceOpenTDB) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	if es.rateLimiter != nil {
+		if err := es.rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("opentdb request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func unescape(s string) string {
+	if decoded, err := url.PathUnescape(s); err == nil {
+		return decoded
+	}
+	return s
+}
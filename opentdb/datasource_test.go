@@ -0,0 +1,86 @@
+package opentdb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func newSource(t *testing.T) *DataSourceOpenTDB {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api_category.php", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"trivia_categories":[{"id":9,"name":"General Knowledge"},{"id":11,"name":"Entertainment: Film"},{"id":14,"name":"Entertainment: Television"},{"id":15,"name":"Entertainment: Video Games"}]}`)
+	})
+	mux.HandleFunc("/api.php", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("amount") != "2" || q.Get("encode") != "url3986" || q.Get("difficulty") != "easy" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if q.Get("category") != "11" {
+			fmt.Fprint(w, `{"response_code":1,"results":[]}`)
+			return
+		}
+		fmt.Fprint(w, `{"response_code":0,"results":[
+			{"type":"multiple","difficulty":"easy","question":"Who%20directed%20%22Jaws%22%3F","correct_answer":"Steven%20Spielberg","incorrect_answers":["George%20Lucas","James%20Cameron","Ridley%20Scott"]},
+			{"type":"boolean","difficulty":"easy","question":"%22Up%22%20is%20a%20Pixar%20film.","correct_answer":"True","incorrect_answers":["False"]}
+		]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	es := New()
+	es.BaseURL = srv.URL
+	es.Difficulty = "easy"
+	es.rateLimiter = rate.NewLimiter(rate.Inf, 1)
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "entertainment film")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Entertainment: Film", "Entertainment: Television", "Entertainment: Video Games"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+
+	data, err := es.FetchData(2, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := []string{
+		"Who directed \"Jaws\"?\nAnswer: Steven Spielberg\nChoices: George Lucas; James Cameron; Ridley Scott; Steven Spielberg\nDifficulty: easy",
+		"\"Up\" is a Pixar film.\nAnswer: True\nChoices: True; False\nDifficulty: easy",
+	}
+	if len(data) != len(wantData) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range wantData {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+
+	if _, err := es.FetchData(2, topics[1].TopicID); err == nil {
+		t.Error("FetchData ignored response code 1")
+	}
+}
+
+func TestInitRejectsUnknownDifficulty(t *testing.T) {
+	es := New()
+	es.Difficulty = "extreme"
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted an unknown difficulty")
+	}
+}
@@ -0,0 +1,288 @@
+// Package pdftext extracts plain text from PDF documents, page by page, for
+// adapters whose FetchData lands on PDFs (S3 and WebDAV documents, EDGAR
+// exhibits, PDF search results). Text is rebuilt from glyph positions, so
+// words, lines and paragraphs come out in reading order for single-column
+// layouts. Size, page and character limits keep large or hostile files from
+// tying up a request.
+package pdftext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Default limits
+const (
+	DefaultMaxSize  = 20 << 20
+	DefaultMaxPages = 100
+	DefaultMaxChars = 200000
+)
+
+var (
+	// ErrTooLarge is returned for documents larger than the extractor's MaxSize
+	ErrTooLarge = errors.New("pdftext: document is too large")
+	// ErrNoText is returned for documents without a text layer, such as scans
+	ErrNoText = errors.New("pdftext: document has no extractable text")
+)
+
+// Page is the text of one page
+type Page struct {
+	Number int // 1-based
+	Text   string
+}
+
+// Document is the extracted text of a PDF
+type Document struct {
+	Title     string // From the document info dictionary; often empty
+	NumPages  int    // Pages in the file, including those beyond MaxPages
+	Pages     []Page // Pages with text, in order
+	Truncated bool   // Whether MaxPages or MaxChars cut the text short
+}
+
+// Text returns the pages separated by blank lines
+func (d Document) Text() string {
+	texts := make([]string, len(d.Pages))
+	for i, p := range d.Pages {
+		texts[i] = p.Text
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// Chunks splits the text into pieces of about size characters along paragraph boundaries. Each page's
+// text is introduced by a "Page N" line, so chunks can be traced back to the page they came from.
+func (d Document) Chunks(size int) []string {
+	var out []string
+	var current strings.Builder
+	for _, p := range d.Pages {
+		paragraphs := strings.Split(p.Text, "\n\n")
+		paragraphs[0] = "Page " + strconv.Itoa(p.Number) + "\n" + paragraphs[0]
+		for _, para := range paragraphs {
+			if current.Len() > 0 && current.Len()+len(para) > size {
+				out = append(out, current.String())
+				current.Reset()
+			}
+			if current.Len() > 0 {
+				current.WriteString("\n\n")
+			}
+			current.WriteString(para)
+		}
+	}
+	if current.Len() > 0 {
+		out = append(out, current.String())
+	}
+	return out
+}
+
+// Extractor extracts text within limits. The zero value uses the default limits.
+type Extractor struct {
+	MaxSize  int64 // Largest document read, in bytes; default DefaultMaxSize
+	MaxPages int   // Pages extracted; default DefaultMaxPages
+	MaxChars int   // Characters extracted across all pages; default DefaultMaxChars
+}
+
+// Default is an Extractor with the default limits
+var Default = &Extractor{}
+
+// IsPDF reports whether a response is a PDF, from its Content-Type or, when that is missing or
+// generic, the start of its body
+func IsPDF(contentType string, head []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/pdf", "application/x-pdf":
+		return true
+	case "", "application/octet-stream", "binary/octet-stream":
+		return bytes.HasPrefix(head, []byte("%PDF-"))
+	}
+	return false
+}
+
+// Reader reads a PDF from r, up to MaxSize bytes, and extracts its text
+func (e *Extractor) Reader(ctx context.Context, r io.Reader) (Document, error) {
+	limit := e.MaxSize
+	if limit <= 0 {
+		limit = DefaultMaxSize
+	}
+	raw, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return Document{}, err
+	}
+	if int64(len(raw)) > limit {
+		return Document{}, ErrTooLarge
+	}
+	return e.Bytes(ctx, raw)
+}
+
+// Bytes extracts the text of the PDF in raw
+func (e *Extractor) Bytes(ctx context.Context, raw []byte) (doc Document, err error) {
+	limit := e.MaxSize
+	if limit <= 0 {
+		limit = DefaultMaxSize
+	}
+	if int64(len(raw)) > limit {
+		return Document{}, ErrTooLarge
+	}
+	// The parser panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			doc, err = Document{}, fmt.Errorf("pdftext: malformed PDF: %v", r)
+		}
+	}()
+	reader, err := pdf.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return Document{}, fmt.Errorf("pdftext: %w", err)
+	}
+
+	maxPages, maxChars := e.MaxPages, e.MaxChars
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+	if maxChars <= 0 {
+		maxChars = DefaultMaxChars
+	}
+	doc.Title = strings.TrimSpace(reader.Trailer().Key("Info").Key("Title").Text())
+	doc.NumPages = reader.NumPage()
+	chars := 0
+	for n := 1; n <= doc.NumPages; n++ {
+		if err := ctx.Err(); err != nil {
+			return Document{}, err
+		}
+		if n > maxPages || chars >= maxChars {
+			doc.Truncated = true
+			break
+		}
+		page := reader.Page(n)
+		if page.V.IsNull() {
+			continue
+		}
+		text := pageText(page)
+		if text == "" {
+			continue
+		}
+		if chars+len(text) > maxChars {
+			text = truncate(text, maxChars-chars)
+			doc.Truncated = true
+		}
+		chars += len(text)
+		doc.Pages = append(doc.Pages, Page{Number: n, Text: text})
+	}
+	if len(doc.Pages) == 0 {
+		return doc, ErrNoText
+	}
+	return doc, nil
+}
+
+// pageText rebuilds a page's text from its glyphs: glyphs on one baseline form a line, a gap wider than
+// a fraction of the font size is a space, and a gap between lines larger than usual starts a paragraph
+func pageText(page pdf.Page) (text string) {
+	defer func() {
+		if recover() != nil {
+			text = ""
+		}
+	}()
+	glyphs := page.Content().Text
+
+	type line struct {
+		y, size float64
+		text    strings.Builder
+	}
+	var lines []*line
+	var cur *line
+	var lastEnd float64
+	for _, g := range glyphs {
+		if g.S == "" {
+			continue
+		}
+		size := g.FontSize
+		if size <= 0 {
+			size = 10
+		}
+		if cur == nil || abs(g.Y-cur.y) > size*0.5 {
+			cur = &line{y: g.Y, size: size}
+			lines = append(lines, cur)
+		} else if gap := g.X - lastEnd; gap > size*0.2 || gap < -size {
+			// A jump back to the left on the same baseline is a new text run, e.g. a table cell
+			cur.text.WriteByte(' ')
+		}
+		cur.text.WriteString(g.S)
+		lastEnd = g.X + g.W
+	}
+
+	// Typical line spacing, to tell paragraph breaks apart from ordinary line breaks
+	var gaps []float64
+	for i := 1; i < len(lines); i++ {
+		if gap := lines[i-1].y - lines[i].y; gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	spacing := 0.0
+	if len(gaps) > 0 {
+		sort.Float64s(gaps)
+		spacing = gaps[len(gaps)/2]
+	}
+
+	var b strings.Builder
+	for i, l := range lines {
+		s := strings.Join(strings.Fields(l.text.String()), " ")
+		if s == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			gap := lines[i-1].y - l.y
+			prev := b.String()
+			switch {
+			case gap < 0 || (spacing > 0 && gap > spacing*1.4):
+				b.WriteString("\n\n")
+			case strings.HasSuffix(prev, "-") && startsLower(s):
+				// Rejoin a word hyphenated across lines
+				trimmed := strings.TrimSuffix(prev, "-")
+				b.Reset()
+				b.WriteString(trimmed)
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(s)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Helpers
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+func startsLower(s string) bool {
+	for _, r := range s {
+		return unicode.IsLower(r)
+	}
+	return false
+}
+
+// truncate cuts s to at most n bytes on a word boundary
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	if n <= 0 {
+		return ""
+	}
+	cut := s[:n]
+	if i := strings.LastIndexAny(cut, " \n"); i > n/2 {
+		cut = cut[:i]
+	}
+	return strings.ToValidUTF8(cut, "")
+}
@@ -0,0 +1,130 @@
+package pdftext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// release-notes.pdf has two pages of Courier text: a heading and two paragraphs, one with a word
+// hyphenated across lines, then a single line
+func fixture(t *testing.T) []byte {
+	t.Helper()
+	raw, err := os.ReadFile("testdata/release-notes.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestBytes(t *testing.T) {
+	doc, err := Default.Bytes(context.Background(), fixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Title != "Release Notes 2.0" || doc.NumPages != 2 || doc.Truncated {
+		t.Errorf("Document = %+v", doc)
+	}
+	want := []string{
+		"Release Notes\n\n" +
+			"Version 2.0 adds streaming exports and a new query planner that is considerably faster for wide tables.\n\n" +
+			"Upgrading requires no changes to existing configuration files.",
+		"Known issues are listed on the project website.",
+	}
+	if len(doc.Pages) != len(want) {
+		t.Fatalf("Pages = %+v", doc.Pages)
+	}
+	for i, p := range doc.Pages {
+		if p.Number != i+1 || p.Text != want[i] {
+			t.Errorf("page %d = %d: %q, want %q", i, p.Number, p.Text, want[i])
+		}
+	}
+	if !strings.HasSuffix(doc.Text(), "files.\n\nKnown issues are listed on the project website.") {
+		t.Errorf("Text = %q", doc.Text())
+	}
+}
+
+func TestLimits(t *testing.T) {
+	raw := fixture(t)
+	doc, err := (&Extractor{MaxPages: 1}).Bytes(context.Background(), raw)
+	if err != nil || len(doc.Pages) != 1 || !doc.Truncated || doc.NumPages != 2 {
+		t.Errorf("MaxPages 1 = %+v, %v", doc, err)
+	}
+
+	doc, err = (&Extractor{MaxChars: 40}).Bytes(context.Background(), raw)
+	if err != nil || !doc.Truncated || len(doc.Text()) > 40+len("\n\n") {
+		t.Fatalf("MaxChars 40 = %+v, %v", doc, err)
+	}
+	if got := doc.Pages[0].Text; got != "Release Notes\n\nVersion 2.0 adds" {
+		t.Errorf("truncated text = %q, want a cut at a word boundary", got)
+	}
+
+	if _, err := (&Extractor{MaxSize: 100}).Reader(context.Background(), bytes.NewReader(raw)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Reader over MaxSize = %v, want ErrTooLarge", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Default.Bytes(ctx, raw); !errors.Is(err, context.Canceled) {
+		t.Errorf("Bytes after cancel = %v", err)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	raw := fixture(t)
+	for name, data := range map[string][]byte{
+		"not a PDF": []byte("<html>not a pdf</html>"),
+		"truncated": raw[:len(raw)/2],
+	} {
+		if _, err := Default.Bytes(context.Background(), data); err == nil {
+			t.Errorf("%s: Bytes succeeded", name)
+		}
+	}
+}
+
+func TestChunks(t *testing.T) {
+	doc, err := Default.Bytes(context.Background(), fixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks := doc.Chunks(80)
+	want := []string{
+		"Page 1\nRelease Notes",
+		"Version 2.0 adds streaming exports and a new query planner that is considerably faster for wide tables.",
+		"Upgrading requires no changes to existing configuration files.",
+		"Page 2\nKnown issues are listed on the project website.",
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("Chunks = %q", chunks)
+	}
+	for i := range want {
+		if chunks[i] != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, chunks[i], want[i])
+		}
+	}
+	if got := doc.Chunks(1000); len(got) != 1 || !strings.HasPrefix(got[0], "Page 1\nRelease Notes\n\n") {
+		t.Errorf("Chunks(1000) = %q", got)
+	}
+}
+
+func TestIsPDF(t *testing.T) {
+	for _, tc := range []struct {
+		contentType string
+		head        string
+		want        bool
+	}{
+		{"application/pdf", "", true},
+		{"application/pdf; charset=binary", "", true},
+		{"application/octet-stream", "%PDF-1.4", true},
+		{"", "%PDF-", true},
+		{"", "<html>", false},
+		{"text/html", "%PDF-", false},
+	} {
+		if got := IsPDF(tc.contentType, []byte(tc.head)); got != tc.want {
+			t.Errorf("IsPDF(%q, %q) = %v, want %v", tc.contentType, tc.head, got, tc.want)
+		}
+	}
+}
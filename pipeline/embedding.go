@@ -0,0 +1,52 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/locus-search/datasource/embed"
+)
+
+// Embed sets Embedding on data items with the Embedder, sending BatchSize texts per call. Items that
+// already have an embedding are skipped, and a failed batch leaves its items without one.
+type Embed struct {
+	Embedder  embed.Embedder
+	BatchSize int  // Texts per Embed call; default 32
+	Normalize bool // Scale vectors to unit length
+}
+
+func (e Embed) Data(ctx context.Context, data []Data) ([]Data, error) {
+	if e.Embedder == nil {
+		return data, nil
+	}
+	size := e.BatchSize
+	if size <= 0 {
+		size = 32
+	}
+	var pending []int
+	for i := range data {
+		if data[i].Embedding == nil && data[i].DataText != "" {
+			pending = append(pending, i)
+		}
+	}
+	for start := 0; start < len(pending); start += size {
+		if err := ctx.Err(); err != nil {
+			return data, nil
+		}
+		batch := pending[start:min(start+size, len(pending))]
+		texts := make([]string, len(batch))
+		for j, i := range batch {
+			texts[j] = data[i].DataText
+		}
+		vectors, err := e.Embedder.Embed(ctx, texts)
+		if err != nil || len(vectors) != len(batch) {
+			continue
+		}
+		for j, i := range batch {
+			if e.Normalize {
+				embed.Normalize(vectors[j])
+			}
+			data[i].Embedding = vectors[j]
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,67 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// fakeEmbedder returns [length, batch number] for every text and fails the batches listed in fail
+type fakeEmbedder struct {
+	batches [][]string
+	fail    map[int]bool
+}
+
+func (f *fakeEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	n := len(f.batches)
+	f.batches = append(f.batches, texts)
+	if f.fail[n] {
+		return nil, errors.New("model overloaded")
+	}
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = []float32{float32(len(text)), float32(n)}
+	}
+	return vectors, nil
+}
+
+func item(text string) Data {
+	return Data{DataSourceData: datasource.DataSourceData{DataText: text}}
+}
+
+func TestEmbed(t *testing.T) {
+	f := &fakeEmbedder{fail: map[int]bool{1: true}}
+	data := []Data{item("a"), item(""), item("bb"), item("ccc"), item("dddd"), item("eeeee")}
+	data[4].Embedding = []float32{9}
+
+	data, err := Embed{Embedder: f, BatchSize: 2}.Data(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Blank and already embedded items are skipped
+	var batches []string
+	for _, b := range f.batches {
+		batches = append(batches, strings.Join(b, ","))
+	}
+	if got := strings.Join(batches, "|"); got != "a,bb|ccc,eeeee" {
+		t.Errorf("batches = %q", got)
+	}
+	for i, want := range [][]float32{{1, 0}, nil, {2, 0}, nil, {9}, nil} {
+		if got := data[i].Embedding; len(got) != len(want) || (len(want) > 0 && got[0] != want[0]) {
+			t.Errorf("item %d embedding = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestEmbedNormalize(t *testing.T) {
+	data, err := Embed{Embedder: &fakeEmbedder{}, Normalize: true}.Data(context.Background(), []Data{item("abc")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := data[0].Embedding; len(v) != 2 || v[0] != 1 || v[1] != 0 {
+		t.Errorf("normalized embedding = %v", v)
+	}
+}
@@ -0,0 +1,93 @@
+package pipeline
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Scripts that identify a language on their own, or a default for the script
+var scriptLanguages = []struct {
+	Table    *unicode.RangeTable
+	Language string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Thai, "th"},
+	{unicode.Greek, "el"},
+	{unicode.Hebrew, "he"},
+	{unicode.Arabic, "ar"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Cyrillic, "ru"},
+}
+
+// Frequent short words of languages written in Latin script
+var stopWords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "in", "that", "for", "with", "was", "are", "this", "it", "on"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "mit", "ein", "eine", "den", "von", "zu", "auf", "für"},
+	"fr": {"le", "la", "les", "et", "est", "des", "une", "du", "dans", "pour", "pas", "que", "qui", "sur"},
+	"es": {"el", "la", "los", "las", "y", "es", "del", "una", "por", "con", "para", "que", "se", "en"},
+	"it": {"il", "la", "di", "che", "e", "è", "per", "una", "del", "della", "con", "non", "sono", "gli"},
+	"pt": {"o", "a", "os", "as", "de", "que", "do", "da", "em", "um", "uma", "para", "com", "não"},
+	"nl": {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "te", "met", "voor", "zijn", "ook"},
+}
+
+// Language guesses the ISO 639-1 code of text: by script for non-Latin text, otherwise by counting
+// common words. It returns "" when the text gives too little to go on.
+func Language(text string) string {
+	counts := map[string]int{}
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range scriptLanguages {
+			if unicode.Is(s.Table, r) {
+				counts[s.Language]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+	// Japanese mixes kana with Han characters, so any kana decides it
+	if counts["ja"] > 0 {
+		return "ja"
+	}
+	best, bestCount := "", 0
+	for language, n := range counts {
+		if n > bestCount {
+			best, bestCount = language, n
+		}
+	}
+	if bestCount*2 > letters {
+		return best
+	}
+
+	hits := map[string]int{}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, w := range words {
+		for language, list := range stopWords {
+			for _, s := range list {
+				if w == s {
+					hits[language]++
+					break
+				}
+			}
+		}
+	}
+	best, bestCount = "", 0
+	for language, n := range hits {
+		if n > bestCount || (n == bestCount && language < best) {
+			best, bestCount = language, n
+		}
+	}
+	// Short titles often contain no stop words at all; one hit is not enough to decide
+	if bestCount < 2 {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,269 @@
+// Package pipeline runs topics and data from a source through ordered
+// enrichment stages: normalizing URLs, detecting language, fetching
+// thumbnails, scoring, filtering, summarizing and embedding. Each stage
+// implements TopicStage, DataStage or both, so consumers can insert their
+// own steps anywhere.
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+// Freshness says how current a topic or data item is, so consumers can show and reason about staleness
+type Freshness struct {
+	FetchedAt    time.Time // When the pipeline got it from the source
+	ExpiresAt    time.Time // FetchedAt plus the pipeline's TTL; zero without a TTL
+	LastModified time.Time // When the source says the content last changed; zero when unknown
+}
+
+// Topic is a topic with the fields stages fill in
+type Topic struct {
+	datasource.DataSourceTopic
+	Freshness
+	Origin      source.SourceInfo // License and attribution of the source that returned it
+	Language    string            // ISO 639-1 code, e.g. "en"
+	Thumbnail   string            // Image URL
+	Snippet     string            // Excerpt from the search result, see source.TopicDetails
+	PublishedAt time.Time         // Zero when the source does not report it
+	Author      string
+	Score       float64           // Relevance to the query; higher is better. Starts as the source's score.
+	Meta        map[string]string // Anything else a custom stage wants to pass along
+}
+
+// Data is a data item with the fields stages fill in
+type Data struct {
+	datasource.DataSourceData
+	Freshness
+	Origin    source.SourceInfo
+	Paywalled bool // The page was behind a paywall or consent wall, so the text is a teaser at most
+	Language  string
+	Summary   string    // Set by the Summarize stage
+	Embedding []float32 // Set by the Embed stage
+	Meta      map[string]string
+}
+
+// TopicStage transforms the topics found for query. It may modify, drop, add or reorder them.
+type TopicStage interface {
+	Topics(ctx context.Context, query string, topics []Topic) ([]Topic, error)
+}
+
+// DataStage transforms the data items fetched for a topic
+type DataStage interface {
+	Data(ctx context.Context, data []Data) ([]Data, error)
+}
+
+// TopicFunc adapts a function to TopicStage
+type TopicFunc func(ctx context.Context, query string, topics []Topic) ([]Topic, error)
+
+func (f TopicFunc) Topics(ctx context.Context, query string, topics []Topic) ([]Topic, error) {
+	return f(ctx, query, topics)
+}
+
+// DataFunc adapts a function to DataStage
+type DataFunc func(ctx context.Context, data []Data) ([]Data, error)
+
+func (f DataFunc) Data(ctx context.Context, data []Data) ([]Data, error) {
+	return f(ctx, data)
+}
+
+// Pipeline wraps a source and runs its results through the stages in order. It implements
+// source.Source, returning the plain SDK types; FetchEnrichedTopics and FetchEnrichedData return the
+// fields stages added.
+type Pipeline struct {
+	Source      source.Source
+	TopicStages []TopicStage
+	DataStages  []DataStage
+	Timeout     time.Duration // Deadline for a fetch and its stages together; default timeout.Topics or timeout.Data
+	TTL         time.Duration // How long results stay fresh, for ExpiresAt; 0 leaves ExpiresAt zero
+}
+
+func New(s source.Source) *Pipeline {
+	return &Pipeline{Source: s}
+}
+
+// Add appends stage to TopicStages, DataStages or both, depending on what it implements
+func (p *Pipeline) Add(stage interface{}) {
+	if s, ok := stage.(TopicStage); ok {
+		p.TopicStages = append(p.TopicStages, s)
+	}
+	if s, ok := stage.(DataStage); ok {
+		p.DataStages = append(p.DataStages, s)
+	}
+}
+
+// Init implements models.DataSource
+func (p *Pipeline) Init() error {
+	return p.Source.Init()
+}
+
+// CheckAvailability implements models.DataSource
+func (p *Pipeline) CheckAvailability() bool {
+	return p.Source.CheckAvailability()
+}
+
+// Unwrap implements source.Wrapper
+func (p *Pipeline) Unwrap() source.Source {
+	return p.Source
+}
+
+// FetchTopics implements models.DataSource
+func (p *Pipeline) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return p.FetchTopicsContext(context.Background(), count, input)
+}
+
+// FetchTopicsContext implements source.ContextSource
+// Timeout applies within ctx's own deadline
+func (p *Pipeline) FetchTopicsContext(ctx context.Context, count int, input string) ([]datasource.DataSourceTopic, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout(timeout.Topics))
+	defer cancel()
+	topics, err := p.FetchEnrichedTopics(ctx, count, input)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]datasource.DataSourceTopic, len(topics))
+	for i, t := range topics {
+		results[i] = t.DataSourceTopic
+	}
+	return results, nil
+}
+
+// FetchTopicsWithOptions implements source.OptionsSource
+func (p *Pipeline) FetchTopicsWithOptions(ctx context.Context, query string, opts source.TopicOptions) ([]datasource.DataSourceTopic, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout(timeout.Topics))
+	defer cancel()
+	topics, err := p.FetchEnrichedTopicsWithOptions(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]datasource.DataSourceTopic, len(topics))
+	for i, t := range topics {
+		results[i] = t.DataSourceTopic
+	}
+	return results, nil
+}
+
+// FetchTopicsPage implements source.Pager
+func (p *Pipeline) FetchTopicsPage(ctx context.Context, query string, opts source.TopicOptions, cursor string) (source.Page, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout(timeout.Topics))
+	defer cancel()
+	topics, next, err := p.FetchEnrichedTopicsPage(ctx, query, opts, cursor)
+	if err != nil {
+		return source.Page{}, err
+	}
+	page := source.Page{Topics: make([]datasource.DataSourceTopic, len(topics)), Next: next}
+	for i, t := range topics {
+		page.Topics[i] = t.DataSourceTopic
+	}
+	return page, nil
+}
+
+// FetchData implements models.DataSource
+func (p *Pipeline) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return p.FetchDataContext(context.Background(), count, topicID)
+}
+
+// FetchDataContext implements source.ContextSource
+// Timeout applies within ctx's own deadline
+func (p *Pipeline) FetchDataContext(ctx context.Context, count int, topicID int64) ([]datasource.DataSourceData, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout(timeout.Data))
+	defer cancel()
+	data, err := p.FetchEnrichedData(ctx, count, topicID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]datasource.DataSourceData, len(data))
+	for i, d := range data {
+		results[i] = d.DataSourceData
+	}
+	return results, nil
+}
+
+// FetchEnrichedTopics searches the source and runs the topics through TopicStages
+func (p *Pipeline) FetchEnrichedTopics(ctx context.Context, count int, input string) ([]Topic, error) {
+	return p.FetchEnrichedTopicsWithOptions(ctx, input, source.TopicOptions{Count: count})
+}
+
+// FetchEnrichedTopicsWithOptions is FetchEnrichedTopics with per-query options for the source
+func (p *Pipeline) FetchEnrichedTopicsWithOptions(ctx context.Context, input string, opts source.TopicOptions) ([]Topic, error) {
+	topics, _, err := p.FetchEnrichedTopicsPage(ctx, input, opts, "")
+	return topics, err
+}
+
+// FetchEnrichedTopicsPage fetches a page of the source's results, see source.Pager, and runs it through
+// TopicStages. It returns the cursor for the next page.
+func (p *Pipeline) FetchEnrichedTopicsPage(ctx context.Context, input string, opts source.TopicOptions, cursor string) ([]Topic, string, error) {
+	page, err := source.FetchTopicsPage(ctx, p.Source, input, opts, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	fetched := time.Now().UTC()
+	topics := make([]Topic, len(page.Topics))
+	for i, t := range page.Topics {
+		origin, _ := source.InfoFor(p.Source, t.TopicID)
+		details, _ := source.Details(p.Source, t.TopicID)
+		topics[i] = Topic{
+			DataSourceTopic: t,
+			Freshness:       p.freshness(fetched, t.TopicID),
+			Origin:          origin,
+			Thumbnail:       details.ThumbnailURL,
+			Snippet:         details.Snippet,
+			PublishedAt:     details.PublishedAt,
+			Author:          details.Author,
+			Score:           details.Score,
+		}
+	}
+	for _, stage := range p.TopicStages {
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
+		}
+		if topics, err = stage.Topics(ctx, input, topics); err != nil {
+			return nil, "", err
+		}
+	}
+	return topics, page.Next, nil
+}
+
+// FetchEnrichedData fetches the topic's data from the source and runs it through DataStages
+func (p *Pipeline) FetchEnrichedData(ctx context.Context, count int, topicID int64) ([]Data, error) {
+	found, err := source.FetchDataContext(ctx, p.Source, count, topicID)
+	if err != nil {
+		return nil, err
+	}
+	freshness := p.freshness(time.Now().UTC(), topicID)
+	origin, _ := source.InfoFor(p.Source, topicID)
+	walled := source.Wall(p.Source, topicID) != ""
+	data := make([]Data, len(found))
+	for i, d := range found {
+		data[i] = Data{DataSourceData: d, Freshness: freshness, Origin: origin, Paywalled: walled}
+	}
+	for _, stage := range p.DataStages {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if data, err = stage.Data(ctx, data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// freshness returns the dates of a result for topicID fetched at the given time
+func (p *Pipeline) freshness(fetched time.Time, topicID int64) Freshness {
+	f := Freshness{FetchedAt: fetched, LastModified: source.LastModified(p.Source, topicID)}
+	if p.TTL > 0 {
+		f.ExpiresAt = fetched.Add(p.TTL)
+	}
+	return f
+}
+
+func (p *Pipeline) timeout(def time.Duration) time.Duration {
+	if p.Timeout > 0 {
+		return p.Timeout
+	}
+	return def
+}
@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+var modified = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+// keyed is a source with string IDs that knows when each topic changed
+type keyed struct {
+	ids map[int64]source.ID
+}
+
+func (k *keyed) Init() error             { return nil }
+func (k *keyed) CheckAvailability() bool { return true }
+
+func (k *keyed) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return nil, nil
+}
+
+func (k *keyed) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if _, ok := k.ids[topicID]; !ok {
+		return nil, errors.New("unknown topic")
+	}
+	return []datasource.DataSourceData{{DataText: string(k.ids[topicID]), AnswerID: topicID}}, nil
+}
+
+func (k *keyed) StringID(topicID int64) (source.ID, bool) {
+	id, ok := k.ids[topicID]
+	return id, ok
+}
+
+func (k *keyed) TopicID(id source.ID) (int64, error) {
+	k.ids[-99] = id
+	return -99, nil
+}
+
+func (k *keyed) LastModified(topicID int64) time.Time {
+	if _, ok := k.ids[topicID]; ok {
+		return modified
+	}
+	return time.Time{}
+}
+
+func TestFetchDataByIDEnriches(t *testing.T) {
+	p := New(&keyed{ids: map[int64]source.ID{}})
+	var seen []Data
+	p.Add(DataFunc(func(ctx context.Context, data []Data) ([]Data, error) {
+		seen = data
+		return data, nil
+	}))
+
+	data, err := source.FetchDataByID(context.Background(), p, 1, "https://example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].AnswerID != -99 {
+		t.Fatalf("FetchDataByID = %+v", data)
+	}
+	if len(seen) != 1 || !seen[0].LastModified.Equal(modified) {
+		t.Errorf("data stages saw %+v, want LastModified %v", seen, modified)
+	}
+}
@@ -0,0 +1,181 @@
+package pipeline
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/rerank"
+)
+
+// NormalizeURLs cleans SourceURLs on topics and data: the scheme and host are lowercased, default
+// ports, fragments and utm_* style tracking parameters are dropped
+type NormalizeURLs struct{}
+
+func (NormalizeURLs) Topics(ctx context.Context, query string, topics []Topic) ([]Topic, error) {
+	for i := range topics {
+		topics[i].SourceURL = NormalizeURL(topics[i].SourceURL)
+	}
+	return topics, nil
+}
+
+func (NormalizeURLs) Data(ctx context.Context, data []Data) ([]Data, error) {
+	for i := range data {
+		data[i].SourceURL = NormalizeURL(data[i].SourceURL)
+	}
+	return data, nil
+}
+
+// NormalizeURL returns raw in the form NormalizeURLs uses; URLs that don't parse or aren't http(s)
+// are returned unchanged
+func NormalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return raw
+	}
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	u.Host = host
+	if port != "" {
+		u.Host += ":" + port
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			if strings.HasPrefix(key, "utm_") || trackingParams[key] {
+				query.Del(key)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+// DetectLanguage sets Language on topics from their titles and on data from their text
+type DetectLanguage struct{}
+
+func (DetectLanguage) Topics(ctx context.Context, query string, topics []Topic) ([]Topic, error) {
+	for i := range topics {
+		if topics[i].Language == "" {
+			topics[i].Language = Language(topics[i].Topic)
+		}
+	}
+	return topics, nil
+}
+
+func (DetectLanguage) Data(ctx context.Context, data []Data) ([]Data, error) {
+	for i := range data {
+		if data[i].Language == "" {
+			data[i].Language = Language(data[i].DataText)
+		}
+	}
+	return data, nil
+}
+
+// Score sets each topic's Score to the BM25 relevance of its title and URL to the query, so topics
+// from different sources can be compared; see the rerank package. The order is left alone.
+type Score struct {
+	K1, B float64 // BM25 parameters; default rerank.DefaultK1 and rerank.DefaultB
+}
+
+func (s Score) Topics(ctx context.Context, query string, topics []Topic) ([]Topic, error) {
+	plain := make([]datasource.DataSourceTopic, len(topics))
+	for i, t := range topics {
+		plain[i] = t.DataSourceTopic
+	}
+	r := rerank.Reranker{K1: s.K1, B: s.B}
+	for i, score := range r.Scores(query, plain) {
+		topics[i].Score = score
+	}
+	return topics, nil
+}
+
+// TopicFilter keeps the topics it returns true for
+type TopicFilter func(Topic) bool
+
+func (f TopicFilter) Topics(ctx context.Context, query string, topics []Topic) ([]Topic, error) {
+	kept := topics[:0]
+	for _, t := range topics {
+		if f(t) {
+			kept = append(kept, t)
+		}
+	}
+	return kept, nil
+}
+
+// DataFilter keeps the data items it returns true for
+type DataFilter func(Data) bool
+
+func (f DataFilter) Data(ctx context.Context, data []Data) ([]Data, error) {
+	kept := data[:0]
+	for _, d := range data {
+		if f(d) {
+			kept = append(kept, d)
+		}
+	}
+	return kept, nil
+}
+
+// MinScore keeps topics scored at least min; place it after Score
+func MinScore(min float64) TopicFilter {
+	return func(t Topic) bool { return t.Score >= min }
+}
+
+// Languages keeps topics and data in one of the given languages, and those whose language is unknown.
+// Place it after DetectLanguage.
+type Languages []string
+
+func (l Languages) Topics(ctx context.Context, query string, topics []Topic) ([]Topic, error) {
+	return TopicFilter(func(t Topic) bool { return l.allowed(t.Language) }).Topics(ctx, query, topics)
+}
+
+func (l Languages) Data(ctx context.Context, data []Data) ([]Data, error) {
+	return DataFilter(func(d Data) bool { return l.allowed(d.Language) }).Data(ctx, data)
+}
+
+func (l Languages) allowed(language string) bool {
+	if language == "" {
+		return true
+	}
+	for _, code := range l {
+		if code == language {
+			return true
+		}
+	}
+	return false
+}
+
+// Attribute appends the source's credit line to data text whose license requires attribution, so
+// consumers that only see the plain SDK types still show it
+type Attribute struct {
+	All bool // Also credit sources that don't require it
+}
+
+func (a Attribute) Data(ctx context.Context, data []Data) ([]Data, error) {
+	for i := range data {
+		origin := data[i].Origin
+		if origin.Attribution == "" || !(origin.AttributionRequired || a.All) {
+			continue
+		}
+		if !strings.Contains(data[i].DataText, origin.Attribution) {
+			data[i].DataText = strings.TrimRight(data[i].DataText, "\n") + "\n\n" + origin.Attribution
+		}
+	}
+	return data, nil
+}
+
+// Helpers
+
+var trackingParams = map[string]bool{"fbclid": true, "gclid": true, "mc_cid": true, "mc_eid": true, "ref_src": true}
@@ -0,0 +1,50 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+
+	"github.com/locus-search/datasource/summarize"
+)
+
+// Summarize sets Summary on data items with the Summarizer. Items that already have a summary or are
+// shorter than MinLength are skipped, and failures leave Summary empty.
+type Summarize struct {
+	Summarizer  summarize.Summarizer // Default summarize.None
+	MinLength   int                  // Shorter texts, in bytes, are not summarized; default 500
+	Concurrency int                  // Items summarized at once; default 4
+}
+
+func (s Summarize) Data(ctx context.Context, data []Data) ([]Data, error) {
+	summarizer := summarize.Or(s.Summarizer)
+	minLength := s.MinLength
+	if minLength <= 0 {
+		minLength = 500
+	}
+	limit := s.Concurrency
+	if limit <= 0 {
+		limit = 4
+	}
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := range data {
+		if data[i].Summary != "" || len(data[i].DataText) < minLength {
+			continue
+		}
+		wg.Add(1)
+		go func(item *Data) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				return
+			}
+			if summary, err := summarizer.Summarize(ctx, item.DataText); err == nil {
+				item.Summary = summary
+			}
+		}(&data[i])
+	}
+	wg.Wait()
+	return data, nil
+}
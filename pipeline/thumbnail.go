@@ -0,0 +1,125 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	"github.com/locus-search/datasource/internal/robots"
+)
+
+// Pages are read up to this many bytes; the meta tags are in the head
+const maxPageSize = 256 << 10
+
+// Thumbnails fetches each topic's page and sets Thumbnail from its og:image, twitter:image or
+// image_src link. Topics that already have a thumbnail, aren't http(s) or are disallowed by
+// robots.txt are skipped, and fetch failures leave Thumbnail empty.
+type Thumbnails struct {
+	Client      *http.Client
+	UserAgent   string
+	Concurrency int // Pages fetched at once; default 4
+
+	once   sync.Once
+	robots *robots.Checker
+}
+
+func NewThumbnails() *Thumbnails {
+	return &Thumbnails{
+		Client:      &http.Client{Timeout: 8 * time.Second},
+		UserAgent:   "locus/pipeline",
+		Concurrency: 4,
+	}
+}
+
+func (t *Thumbnails) Topics(ctx context.Context, query string, topics []Topic) ([]Topic, error) {
+	t.once.Do(func() {
+		if t.Client == nil {
+			t.Client = &http.Client{Timeout: 8 * time.Second}
+		}
+		t.robots = robots.NewChecker(t.Client, t.UserAgent)
+	})
+	limit := t.Concurrency
+	if limit <= 0 {
+		limit = 4
+	}
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := range topics {
+		if topics[i].Thumbnail != "" || !strings.HasPrefix(topics[i].SourceURL, "http") {
+			continue
+		}
+		wg.Add(1)
+		go func(topic *Topic) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				return
+			}
+			if image, err := t.fetch(ctx, topic.SourceURL); err == nil {
+				topic.Thumbnail = image
+			}
+		}(&topics[i])
+	}
+	wg.Wait()
+	return topics, nil
+}
+
+// fetch returns the preview image URL declared by the page at pageURL
+func (t *Thumbnails) fetch(ctx context.Context, pageURL string) (string, error) {
+	if allowed, err := t.robots.Allowed(ctx, pageURL); err != nil || !allowed {
+		return "", fmt.Errorf("robots.txt disallows %s", pageURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/html")
+	if t.UserAgent != "" {
+		req.Header.Set("User-Agent", t.UserAgent)
+	}
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("thumbnail request failed: status %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return "", fmt.Errorf("thumbnail request failed: content type %s", ct)
+	}
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return "", err
+	}
+	for _, selector := range []string{
+		`meta[property="og:image:secure_url"]`, `meta[property="og:image"]`,
+		`meta[name="twitter:image"]`, `meta[name="twitter:image:src"]`,
+	} {
+		if image := strings.TrimSpace(doc.Find(selector).First().AttrOr("content", "")); image != "" {
+			return resolve(resp.Request.URL, image), nil
+		}
+	}
+	if image := strings.TrimSpace(doc.Find(`link[rel="image_src"]`).First().AttrOr("href", "")); image != "" {
+		return resolve(resp.Request.URL, image), nil
+	}
+	return "", fmt.Errorf("no preview image on %s", pageURL)
+}
+
+// Helpers
+
+func resolve(base *url.URL, ref string) string {
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
@@ -0,0 +1,478 @@
+package podcast
+
+// Data Source Adapter for podcast directories (iTunes Search API or Podcast Index)
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+// Supported directories
+const (
+	ProviderITunes       = "itunes"       // Apple's keyless search API
+	ProviderPodcastIndex = "podcastindex" // Open directory; requires an API key and secret
+)
+
+type DataSourcePodcast struct {
+	Client         *http.Client
+	Provider       string // ProviderITunes or ProviderPodcastIndex
+	ITunesURL      string
+	IndexURL       string
+	APIKey         string // Podcast Index key
+	APISecret      string // Podcast Index secret
+	UserAgent      string
+	Country        string // iTunes storefront, e.g. "US"
+	SearchEpisodes bool   // Return individual episodes as topics instead of shows
+
+	items topicid.Map[item]
+}
+
+// item is a show or an episode
+type item struct {
+	Episode  bool
+	ID       int64 // Collection or track ID on iTunes, feed or episode ID on Podcast Index
+	Show     string
+	Title    string
+	Author   string
+	Notes    string
+	AudioURL string
+	Link     string
+	Date     string
+	Duration time.Duration
+}
+
+func New() *DataSourcePodcast {
+	return &DataSourcePodcast{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		Provider:  ProviderITunes,
+		ITunesURL: "https://itunes.apple.com",
+		IndexURL:  "https://api.podcastindex.org/api/1.0",
+		UserAgent: "locus/podcast-datasource",
+		Country:   "US",
+	}
+}
+
+// Init implements models.DataSource
+// Validates the provider configuration
+func (es *DataSourcePodcast) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	switch es.Provider {
+	case ProviderITunes:
+	case ProviderPodcastIndex:
+		if es.APIKey == "" || es.APISecret == "" {
+			return errors.New("APIKey and APISecret are required for the Podcast Index backend")
+		}
+		if es.SearchEpisodes {
+			return errors.New("episode search is only supported by the iTunes backend")
+		}
+	default:
+		return fmt.Errorf("unknown podcast provider %q", es.Provider)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourcePodcast) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.searchShows(ctx, "news", 1)
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourcePodcast) Info() source.SourceInfo {
+	if es.Provider == ProviderPodcastIndex {
+		return source.SourceInfo{
+			Name:        "Podcast Index",
+			Homepage:    "https://podcastindex.org",
+			Attribution: "Podcast Index (https://podcastindex.org)",
+		}
+	}
+	return source.SourceInfo{
+		Name:     "Apple Podcasts",
+		Homepage: "https://podcasts.apple.com",
+		TermsURL: "https://www.apple.com/legal/internet-services/itunes/",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Topics are shows ("Show by Author") or, with SearchEpisodes, episodes ("Episode — Show (date)")
+func (es *DataSourcePodcast) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Podcast DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	var found []item
+	var err error
+	if es.SearchEpisodes {
+		found, err = es.searchEpisodes(ctx, query, count)
+	} else {
+		found, err = es.searchShows(ctx, query, count)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(found))
+	for _, it := range found {
+		title := it.Title
+		if it.Author != "" {
+			title += " by " + it.Author
+		}
+		if it.Episode {
+			title = fmt.Sprintf("%s — %s (%s)", it.Title, it.Show, it.Date)
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: it.Link,
+			Site:      it.Show,
+			TopicID:   es.items.Put(fmt.Sprintf("%s/%t/%d", es.Provider, it.Episode, it.ID), it),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// An episode returns its show notes and audio URL. A show returns its description followed by
+// its most recent episodes, each with notes and audio URL, up to count items.
+func (es *DataSourcePodcast) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	it, ok := es.items.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Podcast topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if it.Episode {
+		return []datasource.DataSourceData{episodeData(it, topicID)}, nil
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	show := it.Title
+	if it.Author != "" {
+		show += " by " + it.Author
+	}
+	if it.Notes != "" {
+		show += "\n\n" + it.Notes
+	}
+	results := []datasource.DataSourceData{{
+		DataText:  show,
+		SourceURL: it.Link,
+		Site:      it.Show,
+		AnswerID:  topicID,
+	}}
+	if count == 1 {
+		return results, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var episodes []item
+	var err error
+	if es.Provider == ProviderPodcastIndex {
+		episodes, err = es.indexEpisodes(ctx, it, count-1)
+	} else {
+		episodes, err = es.itunesEpisodes(ctx, it, count-1)
+	}
+	if err != nil {
+		return results, nil
+	}
+	for _, episode := range episodes {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, episodeData(episode, topicid.Hash(fmt.Sprintf("%s/true/%d", es.Provider, episode.ID))))
+	}
+	return results, nil
+}
+
+func (es *DataSourcePodcast) searchShows(ctx context.Context, query string, count int) ([]item, error) {
+	if es.Provider == ProviderPodcastIndex {
+		params := url.Values{}
+		params.Set("q", query)
+		params.Set("max", strconv.Itoa(count))
+		var response struct {
+			Feeds []struct {
+				ID          int64  `json:"id"`
+				Title       string `json:"title"`
+				Author      string `json:"author"`
+				Description string `json:"description"`
+				Link        string `json:"link"`
+				URL         string `json:"url"`
+			} `json:"feeds"`
+		}
+		if err := es.doJSON(ctx, es.indexURL("/search/byterm", params), &response); err != nil {
+			return nil, err
+		}
+		shows := make([]item, 0, len(response.Feeds))
+		for _, feed := range response.Feeds {
+			link := feed.Link
+			if link == "" {
+				link = feed.URL
+			}
+			shows = append(shows, item{ID: feed.ID, Show: feed.Title, Title: feed.Title, Author: feed.Author, Notes: htmlToText(feed.Description), Link: link})
+		}
+		return shows, nil
+	}
+
+	params := es.itunesParams()
+	params.Set("term", query)
+	params.Set("entity", "podcast")
+	params.Set("limit", strconv.Itoa(count))
+	var response struct {
+		Results []itunesResult `json:"results"`
+	}
+	if err := es.doJSON(ctx, strings.TrimRight(es.ITunesURL, "/")+"/search?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+	shows := make([]item, 0, len(response.Results))
+	for _, r := range response.Results {
+		notes := ""
+		if len(r.Genres) > 0 {
+			notes = "Genres: " + strings.Join(r.Genres, ", ")
+		}
+		shows = append(shows, item{ID: r.CollectionID, Show: r.CollectionName, Title: r.CollectionName, Author: r.ArtistName, Notes: notes, Link: r.CollectionViewURL})
+	}
+	return shows, nil
+}
+
+func (es *DataSourcePodcast) searchEpisodes(ctx context.Context, query string, count int) ([]item, error) {
+	params := es.itunesParams()
+	params.Set("term", query)
+	params.Set("entity", "podcastEpisode")
+	params.Set("limit", strconv.Itoa(count))
+	var response struct {
+		Results []itunesResult `json:"results"`
+	}
+	if err := es.doJSON(ctx, strings.TrimRight(es.ITunesURL, "/")+"/search?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+	episodes := make([]item, 0, len(response.Results))
+	for _, r := range response.Results {
+		episodes = append(episodes, r.episode())
+	}
+	return episodes, nil
+}
+
+// itunesEpisodes looks up the latest episodes of an iTunes show
+func (es *DataSourcePodcast) itunesEpisodes(ctx context.Context, show item, count int) ([]item, error) {
+	params := es.itunesParams()
+	params.Set("id", strconv.FormatInt(show.ID, 10))
+	params.Set("entity", "podcastEpisode")
+	params.Set("limit", strconv.Itoa(count))
+	var response struct {
+		Results []itunesResult `json:"results"`
+	}
+	if err := es.doJSON(ctx, strings.TrimRight(es.ITunesURL, "/")+"/lookup?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+	episodes := make([]item, 0, count)
+	for _, r := range response.Results {
+		// The lookup returns the show itself first
+		if r.Kind != "podcast-episode" {
+			continue
+		}
+		episodes = append(episodes, r.episode())
+	}
+	return episodes, nil
+}
+
+// indexEpisodes lists the latest episodes of a Podcast Index feed
+func (es *DataSourcePodcast) indexEpisodes(ctx context.Context, show item, count int) ([]item, error) {
+	params := url.Values{}
+	params.Set("id", strconv.FormatInt(show.ID, 10))
+	params.Set("max", strconv.Itoa(count))
+	var response struct {
+		Items []struct {
+			ID            int64  `json:"id"`
+			Title         string `json:"title"`
+			Description   string `json:"description"`
+			Link          string `json:"link"`
+			EnclosureURL  string `json:"enclosureUrl"`
+			DatePublished int64  `json:"datePublished"`
+			Duration      int64  `json:"duration"`
+		} `json:"items"`
+	}
+	if err := es.doJSON(ctx, es.indexURL("/episodes/byfeedid", params), &response); err != nil {
+		return nil, err
+	}
+	episodes := make([]item, 0, len(response.Items))
+	for _, episode := range response.Items {
+		link := episode.Link
+		if link == "" {
+			link = episode.EnclosureURL
+		}
+		episodes = append(episodes, item{
+			Episode:  true,
+			ID:       episode.ID,
+			Show:     show.Title,
+			Title:    episode.Title,
+			Notes:    htmlToText(episode.Description),
+			AudioURL: episode.EnclosureURL,
+			Link:     link,
+			Date:     time.Unix(episode.DatePublished, 0).UTC().Format("2006-01-02"),
+			Duration: time.Duration(episode.Duration) * time.Second,
+		})
+	}
+	return episodes, nil
+}
+
+func (es *DataSourcePodcast) itunesParams() url.Values {
+	params := url.Values{}
+	params.Set("media", "podcast")
+	if es.Country != "" {
+		params.Set("country", es.Country)
+	}
+	return params
+}
+
+func (es *DataSourcePodcast) indexURL(path string, params url.Values) string {
+	return strings.TrimRight(es.IndexURL, "/") + path + "?" + params.Encode()
+}
+
+// doJSON performs an HTTP GET request and decodes the JSON response into target.
+// Podcast Index requests are signed with the key, the current time and sha1(key+secret+time).
+func (es *DataSourcePodcast) doJSON(ctx context.Context, uri string, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	if es.Provider == ProviderPodcastIndex {
+		now := strconv.FormatInt(time.Now().Unix(), 10)
+		digest := sha1.Sum([]byte(es.APIKey + es.APISecret + now))
+		req.Header.Set("X-Auth-Key", es.APIKey)
+		req.Header.Set("X-Auth-Date", now)
+		req.Header.Set("Authorization", hex.EncodeToString(digest[:]))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("podcast request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// itunesResult is a show or episode in iTunes Search API responses
+type itunesResult struct {
+	Kind              string   `json:"kind"`
+	CollectionID      int64    `json:"collectionId"`
+	CollectionName    string   `json:"collectionName"`
+	CollectionViewURL string   `json:"collectionViewUrl"`
+	ArtistName        string   `json:"artistName"`
+	Genres            []string `json:"genres"`
+	TrackID           int64    `json:"trackId"`
+	TrackName         string   `json:"trackName"`
+	TrackViewURL      string   `json:"trackViewUrl"`
+	TrackTimeMillis   int64    `json:"trackTimeMillis"`
+	EpisodeURL        string   `json:"episodeUrl"`
+	Description       string   `json:"description"`
+	ReleaseDate       string   `json:"releaseDate"`
+}
+
+func (r itunesResult) episode() item {
+	return item{
+		Episode:  true,
+		ID:       r.TrackID,
+		Show:     r.CollectionName,
+		Title:    r.TrackName,
+		Notes:    htmlToText(r.Description),
+		AudioURL: r.EpisodeURL,
+		Link:     r.TrackViewURL,
+		Date:     strings.SplitN(r.ReleaseDate, "T", 2)[0],
+		Duration: time.Duration(r.TrackTimeMillis) * time.Millisecond,
+	}
+}
+
+// Helpers
+func episodeData(episode item, answerID int64) datasource.DataSourceData {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s — %s (%s", episode.Title, episode.Show, episode.Date)
+	if episode.Duration > 0 {
+		fmt.Fprintf(&b, ", %s", episode.Duration.Round(time.Minute))
+	}
+	b.WriteString(")")
+	if episode.Notes != "" {
+		b.WriteString("\n\n" + episode.Notes)
+	}
+	if episode.AudioURL != "" {
+		b.WriteString("\n\nAudio: " + episode.AudioURL)
+	}
+	return datasource.DataSourceData{
+		DataText:  b.String(),
+		SourceURL: episode.Link,
+		Site:      episode.Show,
+		AnswerID:  answerID,
+	}
+}
+
+// htmlToText flattens show notes, which are often HTML, keeping one line per paragraph
+func htmlToText(in string) string {
+	if !strings.Contains(in, "<") {
+		return strings.TrimSpace(in)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(in))
+	if err != nil {
+		return strings.TrimSpace(in)
+	}
+	doc.Find("br").ReplaceWithHtml("\n")
+	doc.Find("p, li").Each(func(_ int, s *goquery.Selection) {
+		s.AppendHtml("\n")
+	})
+	var lines []string
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
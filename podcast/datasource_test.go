@@ -0,0 +1,105 @@
+package podcast
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("term") != "history" || q.Get("entity") != "podcast" || q.Get("media") != "podcast" || q.Get("country") != "US" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"results":[{"kind":"podcast","collectionId":42,"collectionName":"History Hour","artistName":"BBC","collectionViewUrl":"https://podcasts.apple.com/42","genres":["History","Podcasts"]}]}`)
+	})
+	mux.HandleFunc("/lookup", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("id") != "42" || q.Get("entity") != "podcastEpisode" || q.Get("limit") != "2" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"results":[
+			{"kind":"podcast","collectionId":42,"collectionName":"History Hour"},
+			{"kind":"podcast-episode","trackId":7,"collectionName":"History Hour","trackName":"The Moon Landing","trackViewUrl":"https://podcasts.apple.com/42?i=7","episodeUrl":"https://cdn.example/7.mp3","trackTimeMillis":2700000,"releaseDate":"2024-05-01T05:00:00Z","description":"<p>Apollo 11.</p><p>With guests.</p>"}
+		]}`)
+	})
+	mux.HandleFunc("/index/search/byterm", func(w http.ResponseWriter, r *http.Request) {
+		now := r.Header.Get("X-Auth-Date")
+		digest := sha1.Sum([]byte("key" + "secret" + now))
+		if r.Header.Get("X-Auth-Key") != "key" || r.Header.Get("Authorization") != hex.EncodeToString(digest[:]) {
+			t.Errorf("unsigned request: %v", r.Header)
+		}
+		fmt.Fprint(w, `{"feeds":[{"id":9,"title":"Open Source Hour","author":"Jo","description":"Talk<br>about code","url":"https://feed.example/rss"}]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := New()
+	es.ITunesURL = newServer(t).URL
+
+	topics, err := es.FetchTopics(5, "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "History Hour by BBC" || topics[0].SourceURL != "https://podcasts.apple.com/42" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"History Hour by BBC\n\nGenres: History, Podcasts",
+		"The Moon Landing — History Hour (2024-05-01, 45m0s)\n\nApollo 11.\nWith guests.\n\nAudio: https://cdn.example/7.mp3",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+}
+
+func TestPodcastIndexSignsRequests(t *testing.T) {
+	es := New()
+	es.Provider = ProviderPodcastIndex
+	es.IndexURL = newServer(t).URL + "/index"
+	es.APIKey = "key"
+	es.APISecret = "secret"
+
+	topics, err := es.FetchTopics(5, "open source")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Open Source Hour by Jo" || topics[0].SourceURL != "https://feed.example/rss" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil || len(data) != 1 || data[0].DataText != "Open Source Hour by Jo\n\nTalk\nabout code" {
+		t.Errorf("FetchData = %+v, %v", data, err)
+	}
+}
+
+func TestInit(t *testing.T) {
+	es := New()
+	es.Provider = ProviderPodcastIndex
+	if err := es.Init(); err == nil {
+		t.Error("Init succeeded without Podcast Index credentials")
+	}
+	es.Provider = "stitcher"
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted an unknown provider")
+	}
+}
@@ -0,0 +1,342 @@
+package pubmed
+
+// Data Source Adapter for PubMed via NCBI E-utilities
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/time/rate"
+)
+
+const defaultTopicCount = 5
+
+type DataSourcePubMed struct {
+	Client    *http.Client
+	BaseURL   string
+	UserAgent string
+	APIKey    string // Optional; raises the NCBI limit from 3 to 10 requests per second
+	Tool      string // Identifies the application to NCBI, as their usage policy asks
+	Email     string // Contact address NCBI can use before blocking abusive traffic
+
+	rateLimiter *rate.Limiter
+}
+
+func New() *DataSourcePubMed {
+	return &DataSourcePubMed{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://eutils.ncbi.nlm.nih.gov/entrez/eutils",
+		UserAgent: "locus/pubmed-datasource",
+		Tool:      "locus",
+	}
+}
+
+// Init implements models.DataSource
+// Sizes the rate limiter for the NCBI policy: 3 requests per second, or 10 with an API key
+func (es *DataSourcePubMed) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.rateLimiter == nil {
+		limit := rate.Limit(3)
+		if es.APIKey != "" {
+			limit = rate.Limit(10)
+		}
+		es.rateLimiter = rate.NewLimiter(limit, 1)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourcePubMed) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("retmode", "json")
+	body, err := es.get(ctx, "einfo.fcgi", params)
+	if err != nil {
+		return false
+	}
+	body.Close()
+	return true
+}
+
+// Info implements source.Informer
+func (es *DataSourcePubMed) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "PubMed",
+		Homepage:    "https://pubmed.ncbi.nlm.nih.gov",
+		Attribution: "PubMed, National Library of Medicine",
+		TermsURL:    "https://www.ncbi.nlm.nih.gov/home/about/policies/",
+	}
+}
+
+// Describe implements source.Describer
+func (es *DataSourcePubMed) Describe() source.Capabilities {
+	limit := 3
+	if es.APIKey != "" {
+		limit = 10
+	}
+	return source.Capabilities{Name: es.Info().Name, Data: true, RateLimit: source.RateLimit{Requests: limit, Per: time.Second}}
+}
+
+// FetchTopics implements models.DataSource
+// Runs esearch for matching PMIDs and esummary for their titles; PMIDs are used as topic IDs
+func (es *DataSourcePubMed) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for PubMed DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("term", query)
+	params.Set("retmax", strconv.Itoa(count))
+	params.Set("sort", "relevance")
+	params.Set("retmode", "json")
+
+	var search struct {
+		Result struct {
+			IDList []string `json:"idlist"`
+		} `json:"esearchresult"`
+		Error string `json:"error"`
+	}
+	if err := es.doJSON(ctx, "esearch.fcgi", params, &search); err != nil {
+		return nil, err
+	}
+	if search.Error != "" {
+		return nil, fmt.Errorf("pubmed error: %s", search.Error)
+	}
+	if len(search.Result.IDList) == 0 {
+		return []datasource.DataSourceTopic{}, nil
+	}
+
+	params = url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("id", strings.Join(search.Result.IDList, ","))
+	params.Set("retmode", "json")
+	var summary struct {
+		Result map[string]json.RawMessage `json:"result"`
+	}
+	if err := es.doJSON(ctx, "esummary.fcgi", params, &summary); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(search.Result.IDList))
+	// Iterate the esearch order, which carries the relevance ranking
+	for _, pmid := range search.Result.IDList {
+		raw, ok := summary.Result[pmid]
+		if !ok {
+			continue
+		}
+		var doc struct {
+			Title  string `json:"title"`
+			Source string `json:"source"`
+		}
+		if json.Unmarshal(raw, &doc) != nil || doc.Title == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(pmid, 10, 64)
+		if err != nil {
+			continue
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     doc.Title,
+			SourceURL: articleLink(id),
+			Site:      doc.Source,
+			TopicID:   id,
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Uses efetch to return the structured abstract with the article's MeSH terms appended
+func (es *DataSourcePubMed) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	params := url.Values{}
+	params.Set("db", "pubmed")
+	params.Set("id", strconv.FormatInt(topicID, 10))
+	params.Set("rettype", "abstract")
+	params.Set("retmode", "xml")
+
+	body, err := es.get(ctx, "efetch.fcgi", params)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var set struct {
+		Articles []struct {
+			Citation struct {
+				Article struct {
+					Journal struct {
+						Title string `xml:"Title"`
+					} `xml:"Journal"`
+					Abstract []abstractText `xml:"Abstract>AbstractText"`
+				} `xml:"Article"`
+				MeSH []struct {
+					Descriptor string   `xml:"DescriptorName"`
+					Qualifiers []string `xml:"QualifierName"`
+				} `xml:"MeshHeadingList>MeshHeading"`
+			} `xml:"MedlineCitation"`
+		} `xml:"PubmedArticle"`
+	}
+	if err := xml.NewDecoder(body).Decode(&set); err != nil {
+		return nil, err
+	}
+	if len(set.Articles) == 0 {
+		return []datasource.DataSourceData{}, nil
+	}
+
+	citation := set.Articles[0].Citation
+	var b strings.Builder
+	for _, section := range citation.Article.Abstract {
+		text := strings.TrimSpace(section.Text)
+		if text == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		if section.Label != "" {
+			b.WriteString(section.Label + ": ")
+		}
+		b.WriteString(text)
+	}
+	if b.Len() == 0 {
+		return []datasource.DataSourceData{}, nil
+	}
+	if len(citation.MeSH) > 0 {
+		terms := make([]string, 0, len(citation.MeSH))
+		for _, heading := range citation.MeSH {
+			term := heading.Descriptor
+			if len(heading.Qualifiers) > 0 {
+				term += "/" + strings.Join(heading.Qualifiers, "/")
+			}
+			terms = append(terms, term)
+		}
+		b.WriteString("\n\nMeSH terms: " + strings.Join(terms, "; "))
+	}
+
+	return []datasource.DataSourceData{{
+		DataText:  b.String(),
+		SourceURL: articleLink(topicID),
+		Site:      citation.Article.Journal.Title,
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// abstractText is one (optionally labelled) abstract section. Its text is collected
+// across nested markup such as <i> or <sup>, which encoding/xml would otherwise drop.
+type abstractText struct {
+	Label string
+	Text  string
+}
+
+func (t *abstractText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "Label" {
+			t.Label = attr.Value
+		}
+	}
+	var b strings.Builder
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch v := token.(type) {
+		case xml.CharData:
+			b.Write(v)
+		case xml.EndElement:
+			if v.Name == start.Name {
+				t.Text = b.String()
+				return nil
+			}
+		}
+	}
+}
+
+// doJSON performs a rate-limited E-utilities request and decodes the JSON response into target
+func (es *DataSourcePubMed) doJSON(ctx context.Context, endpoint string, params url.Values, target interface{}) error {
+	body, err := es.get(ctx, endpoint, params)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(target)
+}
+
+// get waits for the rate limiter, then performs an HTTP GET against an E-utilities endpoint.
+// The caller must close the returned body.
+func (es *DataSourcePubMed) get(ctx context.Context, endpoint string, params url.Values) (io.ReadCloser, error) {
+	if err := es.rateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	if es.APIKey != "" {
+		params.Set("api_key", es.APIKey)
+	}
+	if es.Tool != "" {
+		params.Set("tool", es.Tool)
+	}
+	if es.Email != "" {
+		params.Set("email", es.Email)
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + "/" + endpoint + "?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := es.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("pubmed request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// Helpers
+func articleLink(pmid int64) string {
+	return fmt.Sprintf("https://pubmed.ncbi.nlm.nih.gov/%d/", pmid)
+}
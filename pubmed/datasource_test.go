@@ -0,0 +1,81 @@
+package pubmed
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("api_key") != "key" || q.Get("tool") != "locus" || q.Get("db") != "pubmed" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/esearch.fcgi":
+			if q.Get("term") != "crispr" || q.Get("retmax") != "3" {
+				t.Errorf("esearch query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"esearchresult":{"idlist":["222","111","333"]}}`)
+		case "/esummary.fcgi":
+			if q.Get("id") != "222,111,333" {
+				t.Errorf("esummary ids %q", q.Get("id"))
+			}
+			fmt.Fprint(w, `{"result":{"uids":["111","222","333"],
+				"111":{"title":"Second","source":"Science"},
+				"222":{"title":"First","source":"Nature"},
+				"333":{"title":""}}}`)
+		case "/efetch.fcgi":
+			if q.Get("id") != "222" || q.Get("retmode") != "xml" {
+				t.Errorf("efetch query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `<PubmedArticleSet><PubmedArticle><MedlineCitation>
+				<Article><Journal><Title>Nature</Title></Journal><Abstract>
+					<AbstractText Label="BACKGROUND">CRISPR in <i>E. coli</i>.</AbstractText>
+					<AbstractText Label="RESULTS">It works.</AbstractText>
+				</Abstract></Article>
+				<MeshHeadingList>
+					<MeshHeading><DescriptorName>Gene Editing</DescriptorName><QualifierName>methods</QualifierName></MeshHeading>
+					<MeshHeading><DescriptorName>Escherichia coli</DescriptorName></MeshHeading>
+				</MeshHeadingList>
+			</MedlineCitation></PubmedArticle></PubmedArticleSet>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopics(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.APIKey = "key"
+	topics, err := es.FetchTopics(3, "crispr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[0].TopicID != 222 || topics[0].Topic != "First" || topics[0].Site != "Nature" || topics[1].TopicID != 111 {
+		t.Fatalf("FetchTopics = %+v, want the esearch order", topics)
+	}
+	if topics[0].SourceURL != "https://pubmed.ncbi.nlm.nih.gov/222/" {
+		t.Errorf("SourceURL = %q", topics[0].SourceURL)
+	}
+}
+
+func TestFetchData(t *testing.T) {
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.APIKey = "key"
+	data, err := es.FetchData(1, 222)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "BACKGROUND: CRISPR in E. coli.\n\nRESULTS: It works.\n\nMeSH terms: Gene Editing/methods; Escherichia coli"
+	if len(data) != 1 || data[0].DataText != want || data[0].Site != "Nature" || data[0].AnswerID != 222 {
+		t.Errorf("FetchData = %+v, want %q", data, want)
+	}
+}
@@ -0,0 +1,374 @@
+// Package queue decouples topic discovery from content fetching. FetchData
+// work items are stored in an embedded bbolt database, deduplicated by
+// canonical URL or topic ID, handed to workers in order and retried with
+// exponential backoff, so a burst of discovered topics is hydrated steadily
+// and survives restarts.
+package queue
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/dedupe"
+	"github.com/locus-search/datasource/source"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Item states
+const (
+	StatePending = "pending"
+	StateRunning = "running"
+	StateDone    = "done"
+	StateFailed  = "failed" // Gave up after MaxAttempts
+)
+
+var (
+	itemsBucket   = []byte("items")   // Item key -> Item JSON
+	pendingBucket = []byte("pending") // Sequence -> item key, in the order items become ready
+)
+
+// ErrNotFound is returned for item keys the queue does not hold
+var ErrNotFound = errors.New("queue: item not found")
+
+// Item is one FetchData work item
+type Item struct {
+	Key       string    `json:"key"`    // Deduplication key; see Key
+	Source    string    `json:"source"` // Name of the source to fetch from
+	ID        source.ID `json:"id"`     // The topic's string ID, which unlike its int64 ID outlives the process
+	Title     string    `json:"title,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	Count     int       `json:"count"` // Data items to fetch
+	State     string    `json:"state"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	Added     time.Time `json:"added"`
+	NotBefore time.Time `json:"not_before"` // Earliest time of the next attempt
+	Updated   time.Time `json:"updated"`
+}
+
+// Key returns the deduplication key of a topic from the named source: its canonical URL, so the same page
+// found by several sources or queries is fetched once, or the source name and topic ID when it has no URL
+func Key(name string, id source.ID, t datasource.DataSourceTopic) string {
+	if t.SourceURL != "" {
+		return "url:" + dedupe.CanonicalURL(t.SourceURL)
+	}
+	return "topic:" + name + ":" + string(id)
+}
+
+// Stats counts items by state
+type Stats struct {
+	Pending, Running, Done, Failed int
+}
+
+// Queue is a persistent FIFO of work items. It is safe for concurrent use.
+type Queue struct {
+	MaxAttempts int           // Attempts before an item fails for good; default 5
+	Backoff     time.Duration // Delay before the first retry, doubled for each further one; default 30 seconds
+	MaxBackoff  time.Duration // Default 1 hour
+
+	db *bolt.DB
+}
+
+// Open opens or creates the queue database at path. Items left running by a process that stopped
+// mid-fetch are made pending again.
+func Open(path string) (*Queue, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	q := &Queue{MaxAttempts: 5, Backoff: 30 * time.Second, MaxBackoff: time.Hour, db: db}
+	err = db.Update(func(tx *bolt.Tx) error {
+		items, err := tx.CreateBucketIfNotExists(itemsBucket)
+		if err != nil {
+			return err
+		}
+		pending, err := tx.CreateBucketIfNotExists(pendingBucket)
+		if err != nil {
+			return err
+		}
+		var stale []Item
+		err = items.ForEach(func(k, v []byte) error {
+			var item Item
+			if err := json.Unmarshal(v, &item); err != nil {
+				return err
+			}
+			if item.State == StateRunning {
+				stale = append(stale, item)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, item := range stale {
+			item.State = StatePending
+			if err := enqueue(items, pending, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return q, nil
+}
+
+// Close closes the database
+func (q *Queue) Close() error {
+	return q.db.Close()
+}
+
+// Add enqueues a FetchData of up to count items for a topic found by the named source, under the topic's
+// string ID from source.StringID. It reports false when an item with the same key is already queued,
+// running or done; failed items are queued again.
+func (q *Queue) Add(name string, count int, t datasource.DataSourceTopic, id source.ID) (bool, error) {
+	now := time.Now().UTC()
+	item := Item{
+		Key:       Key(name, id, t),
+		Source:    name,
+		ID:        id,
+		Title:     t.Topic,
+		URL:       t.SourceURL,
+		Count:     count,
+		State:     StatePending,
+		Added:     now,
+		NotBefore: now,
+	}
+	added := false
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		items, pending := tx.Bucket(itemsBucket), tx.Bucket(pendingBucket)
+		if existing, ok, err := get(items, item.Key); err != nil {
+			return err
+		} else if ok && existing.State != StateFailed {
+			return nil
+		}
+		added = true
+		return enqueue(items, pending, item)
+	})
+	return added, err
+}
+
+// Next claims the oldest pending item that is due, marking it running. It reports false when no item
+// is due.
+func (q *Queue) Next() (Item, bool, error) {
+	var claimed Item
+	found := false
+	now := time.Now().UTC()
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		items, pending := tx.Bucket(itemsBucket), tx.Bucket(pendingBucket)
+		// Entries are deleted after the scan; deleting under a bbolt cursor can skip the next entry
+		var consumed [][]byte
+		c := pending.Cursor()
+		for seq, key := c.First(); seq != nil; seq, key = c.Next() {
+			item, ok, err := get(items, string(key))
+			if err != nil {
+				return err
+			}
+			if !ok || item.State != StatePending {
+				// Left over from an item that was purged or claimed through a later entry
+				consumed = append(consumed, append([]byte(nil), seq...))
+				continue
+			}
+			if item.NotBefore.After(now) {
+				continue
+			}
+			consumed = append(consumed, append([]byte(nil), seq...))
+			item.State = StateRunning
+			item.Attempts++
+			item.Updated = now
+			claimed, found = item, true
+			if err := put(items, item); err != nil {
+				return err
+			}
+			break
+		}
+		for _, seq := range consumed {
+			if err := pending.Delete(seq); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return claimed, found, err
+}
+
+// Done marks a claimed item as finished
+func (q *Queue) Done(key string) error {
+	return q.update(key, func(item *Item) {
+		item.State = StateDone
+		item.LastError = ""
+	})
+}
+
+// Fail records a failed attempt at a claimed item. The item is retried after the backoff delay, or
+// marked failed once it has had MaxAttempts attempts.
+func (q *Queue) Fail(key string, cause error) error {
+	maxAttempts := q.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	return q.db.Update(func(tx *bolt.Tx) error {
+		items, pending := tx.Bucket(itemsBucket), tx.Bucket(pendingBucket)
+		item, ok, err := get(items, key)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNotFound
+		}
+		item.LastError = cause.Error()
+		item.Updated = time.Now().UTC()
+		if item.Attempts >= maxAttempts {
+			item.State = StateFailed
+			return put(items, item)
+		}
+		item.State = StatePending
+		item.NotBefore = item.Updated.Add(q.delay(item.Attempts))
+		return enqueue(items, pending, item)
+	})
+}
+
+// Get returns the item with key
+func (q *Queue) Get(key string) (Item, error) {
+	var item Item
+	err := q.db.View(func(tx *bolt.Tx) error {
+		var ok bool
+		var err error
+		item, ok, err = get(tx.Bucket(itemsBucket), key)
+		if err == nil && !ok {
+			err = ErrNotFound
+		}
+		return err
+	})
+	return item, err
+}
+
+// Stats counts the items in each state
+func (q *Queue) Stats() (Stats, error) {
+	var s Stats
+	err := q.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(itemsBucket).ForEach(func(k, v []byte) error {
+			var item Item
+			if err := json.Unmarshal(v, &item); err != nil {
+				return err
+			}
+			switch item.State {
+			case StatePending:
+				s.Pending++
+			case StateRunning:
+				s.Running++
+			case StateDone:
+				s.Done++
+			case StateFailed:
+				s.Failed++
+			}
+			return nil
+		})
+	})
+	return s, err
+}
+
+// Purge removes done and failed items last updated before cutoff, so their topics can be queued again.
+// It returns the number removed.
+func (q *Queue) Purge(cutoff time.Time) (int, error) {
+	removed := 0
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		items := tx.Bucket(itemsBucket)
+		var purged [][]byte
+		err := items.ForEach(func(k, v []byte) error {
+			var item Item
+			if err := json.Unmarshal(v, &item); err != nil {
+				return err
+			}
+			if (item.State == StateDone || item.State == StateFailed) && item.Updated.Before(cutoff) {
+				purged = append(purged, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range purged {
+			if err := items.Delete(k); err != nil {
+				return err
+			}
+		}
+		removed = len(purged)
+		return nil
+	})
+	return removed, err
+}
+
+// update applies change to an item and stores it
+func (q *Queue) update(key string, change func(*Item)) error {
+	return q.db.Update(func(tx *bolt.Tx) error {
+		items := tx.Bucket(itemsBucket)
+		item, ok, err := get(items, key)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNotFound
+		}
+		change(&item)
+		item.Updated = time.Now().UTC()
+		return put(items, item)
+	})
+}
+
+// delay returns the backoff before the attempt after the given number of attempts
+func (q *Queue) delay(attempts int) time.Duration {
+	backoff, limit := q.Backoff, q.MaxBackoff
+	if backoff <= 0 {
+		backoff = 30 * time.Second
+	}
+	if limit <= 0 {
+		limit = time.Hour
+	}
+	for i := 1; i < attempts && backoff < limit; i++ {
+		backoff *= 2
+	}
+	return min(backoff, limit)
+}
+
+// Helpers
+
+func get(items *bolt.Bucket, key string) (Item, bool, error) {
+	v := items.Get([]byte(key))
+	if v == nil {
+		return Item{}, false, nil
+	}
+	var item Item
+	if err := json.Unmarshal(v, &item); err != nil {
+		return Item{}, false, fmt.Errorf("queue item %s: %w", key, err)
+	}
+	return item, true, nil
+}
+
+func put(items *bolt.Bucket, item Item) error {
+	v, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return items.Put([]byte(item.Key), v)
+}
+
+// enqueue stores a pending item and appends it to the pending order
+func enqueue(items, pending *bolt.Bucket, item Item) error {
+	if err := put(items, item); err != nil {
+		return err
+	}
+	seq, err := pending.NextSequence()
+	if err != nil {
+		return err
+	}
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], seq)
+	return pending.Put(k[:], []byte(item.Key))
+}
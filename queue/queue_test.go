@@ -0,0 +1,163 @@
+package queue
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+func newQueue(t *testing.T) (*Queue, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "queue.db")
+	q, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { q.Close() })
+	return q, path
+}
+
+func TestAddDedupes(t *testing.T) {
+	q, _ := newQueue(t)
+	page := datasource.DataSourceTopic{TopicID: 1, Topic: "Go", SourceURL: "https://example.com/go?utm_source=feed"}
+	same := datasource.DataSourceTopic{TopicID: 2, Topic: "Go", SourceURL: "https://example.com/go"}
+	noURL := datasource.DataSourceTopic{TopicID: 3, Topic: "Rust"}
+
+	for _, tc := range []struct {
+		name  string
+		topic datasource.DataSourceTopic
+		id    string
+		want  bool
+	}{
+		{"a", page, "1", true},
+		{"b", same, "2", false}, // Same canonical URL from another source
+		{"a", noURL, "3", true},
+		{"a", noURL, "3", false},
+		{"b", noURL, "3", true}, // Without a URL, topic IDs are per source
+	} {
+		added, err := q.Add(tc.name, 3, tc.topic, source.ID(tc.id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if added != tc.want {
+			t.Errorf("Add(%s, %q) = %v, want %v", tc.name, tc.topic.Topic, added, tc.want)
+		}
+	}
+	if s, err := q.Stats(); err != nil || s.Pending != 3 {
+		t.Errorf("Stats = %+v, %v; want 3 pending", s, err)
+	}
+}
+
+func TestNextInOrder(t *testing.T) {
+	q, _ := newQueue(t)
+	for _, id := range []string{"x", "y"} {
+		if _, err := q.Add("a", 1, datasource.DataSourceTopic{Topic: id}, source.ID(id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, want := range []string{"x", "y"} {
+		item, ok, err := q.Next()
+		if err != nil || !ok {
+			t.Fatalf("Next = %+v, %v, %v", item, ok, err)
+		}
+		if string(item.ID) != want || item.State != StateRunning || item.Attempts != 1 {
+			t.Errorf("Next = %+v, want running item %s", item, want)
+		}
+	}
+	if _, ok, err := q.Next(); ok || err != nil {
+		t.Errorf("Next on an empty queue = %v, %v", ok, err)
+	}
+}
+
+func TestFailBacksOff(t *testing.T) {
+	q, _ := newQueue(t)
+	q.MaxAttempts = 3
+	q.Backoff = time.Minute
+	if _, err := q.Add("a", 1, datasource.DataSourceTopic{Topic: "flaky"}, source.ID("7")); err != nil {
+		t.Fatal(err)
+	}
+	item, _, err := q.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []time.Duration{time.Minute, 2 * time.Minute} {
+		if err := q.Fail(item.Key, errTest); err != nil {
+			t.Fatal(err)
+		}
+		got, err := q.Get(item.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.State != StatePending || got.LastError != errTest.Error() {
+			t.Errorf("after %d attempts: %+v", got.Attempts, got)
+		}
+		if delay := got.NotBefore.Sub(got.Updated); delay != want {
+			t.Errorf("after %d attempts: retry in %v, want %v", got.Attempts, delay, want)
+		}
+		// Not due yet
+		if _, ok, _ := q.Next(); ok {
+			t.Fatal("Next claimed an item during its backoff")
+		}
+		// Claim it again as if the backoff had passed
+		if err := q.update(item.Key, func(i *Item) { i.NotBefore = time.Time{} }); err != nil {
+			t.Fatal(err)
+		}
+		if item, _, err = q.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := q.Fail(item.Key, errTest); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := q.Get(item.Key); got.State != StateFailed || got.Attempts != 3 {
+		t.Errorf("after MaxAttempts: %+v", got)
+	}
+	// Failed items can be queued again
+	if added, err := q.Add("a", 1, datasource.DataSourceTopic{Topic: "flaky"}, source.ID("7")); err != nil || !added {
+		t.Errorf("Add of a failed item = %v, %v", added, err)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	q := &Queue{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	for attempts, want := range []time.Duration{time.Second, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if got := q.delay(attempts); got != want {
+			t.Errorf("delay(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestOpenResetsRunning(t *testing.T) {
+	q, path := newQueue(t)
+	if _, err := q.Add("a", 1, datasource.DataSourceTopic{Topic: "interrupted"}, source.ID("9")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := q.Next(); !ok || err != nil {
+		t.Fatalf("Next = %v, %v", ok, err)
+	}
+	// The process stops mid-fetch
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	if s, err := q.Stats(); err != nil || s.Pending != 1 || s.Running != 0 {
+		t.Errorf("Stats after reopening = %+v, %v", s, err)
+	}
+	item, ok, err := q.Next()
+	if err != nil || !ok || item.ID != "9" || item.Attempts != 2 {
+		t.Errorf("Next after reopening = %+v, %v, %v", item, ok, err)
+	}
+}
+
+var errTest = errors.New("backend down")
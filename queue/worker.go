@@ -0,0 +1,153 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+// Handler receives the data fetched for an item, e.g. to index or export it. An error counts as a
+// failed attempt and the item is retried.
+type Handler func(ctx context.Context, item Item, data []datasource.DataSourceData) error
+
+// Processor runs workers that take items from a queue and fetch their data from the named sources
+type Processor struct {
+	Queue   *Queue
+	Sources map[string]source.Source // By the names items were added under
+	Handle  Handler
+	Workers int           // Items fetched at once; default 4
+	Poll    time.Duration // Wait before checking again when no item is due; default 1 second
+}
+
+func NewProcessor(q *Queue, handle Handler) *Processor {
+	return &Processor{Queue: q, Sources: map[string]source.Source{}, Handle: handle, Workers: 4, Poll: time.Second}
+}
+
+// Run processes items until ctx is done. An item being fetched when ctx ends is left running and
+// becomes pending again the next time the queue is opened.
+func (p *Processor) Run(ctx context.Context) error {
+	workers := p.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+	poll := p.Poll
+	if poll <= 0 {
+		poll = time.Second
+	}
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				item, ok, err := p.Queue.Next()
+				if err != nil {
+					errs <- err
+					return
+				}
+				if !ok {
+					select {
+					case <-time.After(poll):
+					case <-ctx.Done():
+					}
+					continue
+				}
+				if err := p.process(ctx, item); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	if err, ok := <-errs; ok {
+		return err
+	}
+	return nil
+}
+
+// process fetches one claimed item and records the outcome; only queue errors are returned
+func (p *Processor) process(ctx context.Context, item Item) error {
+	s, ok := p.Sources[item.Source]
+	if !ok {
+		return p.Queue.Fail(item.Key, fmt.Errorf("no source named %q", item.Source))
+	}
+	topicID, err := source.TopicID(s, item.ID)
+	if err != nil {
+		return p.Queue.Fail(item.Key, err)
+	}
+	data, err := source.FetchDataContext(ctx, s, item.Count, topicID)
+	if err == nil && p.Handle != nil {
+		err = p.Handle(ctx, item, data)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return p.Queue.Fail(item.Key, err)
+	}
+	return p.Queue.Done(item.Key)
+}
+
+// Enqueuer wraps a source and queues a FetchData for every topic it finds, so a Processor can hydrate
+// them in the background. It implements source.Source.
+type Enqueuer struct {
+	Source source.Source
+	Queue  *Queue
+	Name   string // Source name items are added under; the Processor's Sources key
+	Count  int    // Data items fetched per topic; default 3
+}
+
+func NewEnqueuer(name string, s source.Source, q *Queue) *Enqueuer {
+	return &Enqueuer{Source: s, Queue: q, Name: name, Count: 3}
+}
+
+// Init implements models.DataSource
+func (e *Enqueuer) Init() error {
+	return e.Source.Init()
+}
+
+// CheckAvailability implements models.DataSource
+func (e *Enqueuer) CheckAvailability() bool {
+	return e.Source.CheckAvailability()
+}
+
+// Unwrap implements source.Wrapper
+func (e *Enqueuer) Unwrap() source.Source {
+	return e.Source
+}
+
+// FetchTopics implements models.DataSource
+// Returns the source's topics after queueing those not already queued or done. Topics are queued under
+// their string IDs, so a Processor can fetch them after a restart; a topic without one is an error.
+func (e *Enqueuer) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	topics, err := e.Source.FetchTopics(count, input)
+	if err != nil {
+		return nil, err
+	}
+	dataCount := e.Count
+	if dataCount <= 0 {
+		dataCount = 3
+	}
+	for _, t := range topics {
+		id, ok := source.StringID(e.Source, t.TopicID)
+		if !ok {
+			return nil, fmt.Errorf("queue: %s has no ID for topic %d", e.Name, t.TopicID)
+		}
+		if _, err := e.Queue.Add(e.Name, dataCount, t, id); err != nil {
+			return nil, err
+		}
+	}
+	return topics, nil
+}
+
+// FetchData implements models.DataSource
+func (e *Enqueuer) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return e.Source.FetchData(count, topicID)
+}
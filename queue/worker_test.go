@@ -0,0 +1,101 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+// pages is a source with URL IDs whose int64 topic IDs only last as long as the process, like
+// DuckDuckGo's
+type pages struct {
+	urls map[int64]string
+	next int64
+}
+
+func newPages() *pages { return &pages{urls: map[int64]string{}, next: 100} }
+
+func (p *pages) Init() error             { return nil }
+func (p *pages) CheckAvailability() bool { return true }
+
+func (p *pages) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	url := "https://example.com/" + input
+	id, _ := p.TopicID(source.ID(url))
+	return []datasource.DataSourceTopic{{TopicID: id, Topic: input, SourceURL: url}}, nil
+}
+
+func (p *pages) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	url, ok := p.urls[topicID]
+	if !ok {
+		return nil, fmt.Errorf("unknown topic %d", topicID)
+	}
+	return []datasource.DataSourceData{{DataText: "content of " + url, AnswerID: topicID}}, nil
+}
+
+func (p *pages) StringID(topicID int64) (source.ID, bool) {
+	url, ok := p.urls[topicID]
+	return source.ID(url), ok
+}
+
+func (p *pages) TopicID(id source.ID) (int64, error) {
+	p.next++
+	p.urls[p.next] = string(id)
+	return p.next, nil
+}
+
+func TestProcessorAfterRestart(t *testing.T) {
+	q, _ := newQueue(t)
+	if _, err := NewEnqueuer("pages", newPages(), q).FetchTopics(5, "golang"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh source, as after a restart, has never handed out the topic's int64 ID
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var mu sync.Mutex
+	var got []string
+	p := NewProcessor(q, func(ctx context.Context, item Item, data []datasource.DataSourceData) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, d := range data {
+			got = append(got, d.DataText)
+		}
+		cancel()
+		return nil
+	})
+	p.Sources["pages"] = newPages()
+	p.Poll = 10 * time.Millisecond
+	if err := p.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 1 || got[0] != "content of https://example.com/golang" {
+		t.Errorf("handled %q", got)
+	}
+	if s, err := q.Stats(); err != nil || s.Done != 1 {
+		t.Errorf("Stats = %+v, %v; want 1 done", s, err)
+	}
+}
+
+func TestProcessorUnknownSource(t *testing.T) {
+	q, _ := newQueue(t)
+	if _, err := q.Add("gone", 1, datasource.DataSourceTopic{Topic: "orphan"}, "1"); err != nil {
+		t.Fatal(err)
+	}
+	item, _, err := q.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewProcessor(q, nil)
+	if err := p.process(context.Background(), item); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := q.Get(item.Key); got.State != StatePending || got.LastError == "" {
+		t.Errorf("item from an unknown source = %+v, want a failed attempt", got)
+	}
+}
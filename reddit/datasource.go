@@ -0,0 +1,399 @@
+package reddit
+
+// Data Source Adapter for the Reddit OAuth API
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceReddit struct {
+	Client       *http.Client
+	BaseURL      string
+	AuthURL      string
+	UserAgent    string   // Reddit rejects generic agents; use "platform:app:version (by /u/name)"
+	ClientID     string   // Set by user, from https://www.reddit.com/prefs/apps
+	ClientSecret string   // Set by user
+	Subreddits   []string // Scope searches to these subreddits when set
+	Sort         string   // relevance, hot, top, new or comments
+	TimeWindow   string   // hour, day, week, month, year or all
+
+	mu            sync.Mutex
+	token         string
+	tokenExpiry   time.Time
+	rateRemaining float64
+	rateReset     time.Time
+}
+
+func New() *DataSourceReddit {
+	return &DataSourceReddit{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:    "https://oauth.reddit.com",
+		AuthURL:    "https://www.reddit.com/api/v1/access_token",
+		UserAgent:  "locus/reddit-datasource",
+		Sort:       "relevance",
+		TimeWindow: "all",
+	}
+}
+
+// NSFWCapable reports that this source can return explicit or offensive posts and comments, so content filters
+// wrapping it should screen its results
+func (es *DataSourceReddit) NSFWCapable() bool {
+	return true
+}
+
+// Init implements models.DataSource
+// Reddit requires application credentials for the OAuth API
+func (es *DataSourceReddit) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.ClientID == "" || es.ClientSecret == "" {
+		return errors.New("ClientID and ClientSecret are required for Reddit DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Obtains an application token and runs a single-result search
+func (es *DataSourceReddit) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", "reddit")
+	params.Set("limit", "1")
+	return es.doJSON(ctx, "/search", params, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceReddit) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Reddit",
+		Homepage: "https://www.reddit.com",
+		TermsURL: "https://www.redditinc.com/policies/data-api-terms",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each matching post is a topic; its base36 post ID is decoded into the topic ID
+func (es *DataSourceReddit) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Reddit DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("type", "link")
+	params.Set("limit", strconv.Itoa(count))
+	params.Set("raw_json", "1")
+	if es.Sort != "" {
+		params.Set("sort", es.Sort)
+	}
+	if es.TimeWindow != "" {
+		params.Set("t", es.TimeWindow)
+	}
+	path := "/search"
+	if len(es.Subreddits) > 0 {
+		path = "/r/" + strings.Join(es.Subreddits, "+") + "/search"
+		params.Set("restrict_sr", "1")
+	}
+
+	var response listing
+	if err := es.doJSON(ctx, path, params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, child := range response.Data.Children {
+		if child.Kind != "t3" || len(results) >= count {
+			continue
+		}
+		id, err := strconv.ParseInt(child.Data.ID, 36, 64)
+		if err != nil {
+			continue
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     child.Data.Title,
+			SourceURL: "https://www.reddit.com" + child.Data.Permalink,
+			Site:      child.Data.Subreddit,
+			TopicID:   id,
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the post body (for self posts) followed by the top comment threads.
+// Each thread is a top-level comment with its highest-ranked replies quoted beneath it.
+func (es *DataSourceReddit) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	postID := strconv.FormatInt(topicID, 36)
+	params := url.Values{}
+	params.Set("sort", "top")
+	params.Set("limit", strconv.Itoa(count))
+	params.Set("depth", "2")
+	params.Set("raw_json", "1")
+
+	// The comments endpoint returns two listings: the post itself, then its comment tree
+	var response []listing
+	if err := es.doJSON(ctx, "/comments/"+postID, params, &response); err != nil {
+		return nil, err
+	}
+	if len(response) < 2 {
+		return []datasource.DataSourceData{}, nil
+	}
+
+	results := make([]datasource.DataSourceData, 0, count+1)
+	for _, child := range response[0].Data.Children {
+		post := child.Data
+		if child.Kind != "t3" || strings.TrimSpace(post.Selftext) == "" {
+			continue
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  strings.TrimSpace(post.Selftext),
+			SourceURL: "https://www.reddit.com" + post.Permalink,
+			Site:      post.Subreddit,
+			AnswerID:  topicID,
+		})
+	}
+	for _, child := range response[1].Data.Children {
+		if len(results) >= count {
+			break
+		}
+		comment := child.Data
+		if child.Kind != "t1" || isRemoved(comment.Body) {
+			continue
+		}
+		answerID, err := strconv.ParseInt(comment.ID, 36, 64)
+		if err != nil {
+			continue
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  formatThread(comment),
+			SourceURL: "https://www.reddit.com" + comment.Permalink,
+			Site:      comment.Subreddit,
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// listing mirrors the Listing envelope shared by Reddit search and comment responses
+type listing struct {
+	Data struct {
+		Children []struct {
+			Kind string `json:"kind"`
+			Data thing  `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// thing holds the post (t3) and comment (t1) fields used by this adapter
+type thing struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Selftext  string `json:"selftext"`
+	Body      string `json:"body"`
+	Author    string `json:"author"`
+	Score     int    `json:"score"`
+	Subreddit string `json:"subreddit"`
+	Permalink string `json:"permalink"`
+	// Replies is an empty string when a comment has no replies, so decode it lazily
+	Replies json.RawMessage `json:"replies"`
+}
+
+// formatThread renders a comment and up to two of its top replies as plain text
+func formatThread(comment thing) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%d points):\n%s", comment.Author, comment.Score, strings.TrimSpace(comment.Body))
+	var replies listing
+	if len(comment.Replies) == 0 || json.Unmarshal(comment.Replies, &replies) != nil {
+		return b.String()
+	}
+	added := 0
+	for _, child := range replies.Data.Children {
+		if added >= 2 {
+			break
+		}
+		if child.Kind != "t1" || isRemoved(child.Data.Body) {
+			continue
+		}
+		fmt.Fprintf(&b, "\n\n> %s (%d points):\n> %s", child.Data.Author, child.Data.Score,
+			strings.ReplaceAll(strings.TrimSpace(child.Data.Body), "\n", "\n> "))
+		added++
+	}
+	return b.String()
+}
+
+func isRemoved(body string) bool {
+	body = strings.TrimSpace(body)
+	return body == "" || body == "[removed]" || body == "[deleted]"
+}
+
+// accessToken returns a cached application-only token, requesting a new one when it is close to expiry
+func (es *DataSourceReddit) accessToken(ctx context.Context) (string, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.token != "" && time.Now().Before(es.tokenExpiry) {
+		return es.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, es.AuthURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(es.ClientID, es.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := es.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("reddit token request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Error       string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("reddit token request failed: %s", token.Error)
+	}
+	es.token = token.AccessToken
+	// Refresh a minute early so in-flight requests never carry an expired token
+	es.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return es.token, nil
+}
+
+// waitForRateLimit blocks until the rate-limit window resets when the previous response reported no remaining requests
+func (es *DataSourceReddit) waitForRateLimit(ctx context.Context) error {
+	es.mu.Lock()
+	wait := time.Duration(0)
+	if !es.rateReset.IsZero() && es.rateRemaining < 1 {
+		wait = time.Until(es.rateReset)
+	}
+	es.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return fmt.Errorf("reddit rate limit exhausted: resets in %s", wait.Round(time.Second))
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recordRateLimit stores the X-Ratelimit-* headers Reddit attaches to every OAuth response
+func (es *DataSourceReddit) recordRateLimit(header http.Header) {
+	remaining, err := strconv.ParseFloat(header.Get("X-Ratelimit-Remaining"), 64)
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseFloat(header.Get("X-Ratelimit-Reset"), 64)
+	if err != nil {
+		return
+	}
+	es.mu.Lock()
+	es.rateRemaining = remaining
+	es.rateReset = time.Now().Add(time.Duration(reset * float64(time.Second)))
+	es.mu.Unlock()
+}
+
+// doJSON performs an authenticated HTTP GET request against the Reddit OAuth API and decodes the JSON response into target
+func (es *DataSourceReddit) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	if err := es.waitForRateLimit(ctx); err != nil {
+		return err
+	}
+	token, err := es.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := es.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	es.recordRateLimit(resp.Header)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		// Force a fresh token on the next call
+		es.mu.Lock()
+		es.token = ""
+		es.mu.Unlock()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("reddit request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
@@ -0,0 +1,112 @@
+package reddit
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "id" || pass != "secret" {
+			t.Errorf("token request authenticated as %q, %q", user, pass)
+		}
+		if r.Method != http.MethodPost || r.FormValue("grant_type") != "client_credentials" {
+			t.Errorf("token request %s with grant_type %q", r.Method, r.FormValue("grant_type"))
+		}
+		fmt.Fprint(w, `{"access_token":"tok","expires_in":3600}`)
+	})
+	mux.HandleFunc("/r/golang+rust/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		q := r.URL.Query()
+		if q.Get("q") != "generics" || q.Get("restrict_sr") != "1" || q.Get("limit") != "2" || q.Get("sort") != "top" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"data":{"children":[
+			{"kind":"t5","data":{"id":"zz","title":"A subreddit"}},
+			{"kind":"t3","data":{"id":"abc","title":"Generics in Go","subreddit":"golang","permalink":"/r/golang/comments/abc/generics/"}},
+			{"kind":"t3","data":{"id":"abd","title":"Generics in Rust","subreddit":"rust","permalink":"/r/rust/comments/abd/generics/"}}
+		]}}`)
+	})
+	mux.HandleFunc("/comments/abc", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sort") != "top" {
+			t.Errorf("comments sorted by %q", r.URL.Query().Get("sort"))
+		}
+		fmt.Fprint(w, `[
+			{"data":{"children":[{"kind":"t3","data":{"id":"abc","selftext":" Are they worth it? ","subreddit":"golang","permalink":"/r/golang/comments/abc/generics/"}}]}},
+			{"data":{"children":[
+				{"kind":"t1","data":{"id":"c1","body":"[removed]"}},
+				{"kind":"t1","data":{"id":"c2","author":"gopher","score":12,"body":"Yes.","subreddit":"golang","permalink":"/r/golang/comments/abc/generics/c2/","replies":{"data":{"children":[
+					{"kind":"t1","data":{"author":"rustacean","score":3,"body":"Mostly\nyes."}}
+				]}}}},
+				{"kind":"t1","data":{"id":"c3","author":"other","score":1,"body":"No.","replies":""}}
+			]}}
+		]`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopics(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.AuthURL = srv.URL + "/token"
+	es.ClientID, es.ClientSecret = "id", "secret"
+	es.Subreddits = []string{"golang", "rust"}
+	es.Sort = "top"
+
+	topics, err := es.FetchTopics(2, "generics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	want, _ := strconv.ParseInt("abc", 36, 64)
+	if topics[0].TopicID != want || topics[0].Topic != "Generics in Go" || topics[0].Site != "golang" ||
+		topics[0].SourceURL != "https://www.reddit.com/r/golang/comments/abc/generics/" {
+		t.Errorf("topic 0 = %+v", topics[0])
+	}
+}
+
+func TestFetchData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.AuthURL = srv.URL + "/token"
+	es.ClientID, es.ClientSecret = "id", "secret"
+
+	topicID, _ := strconv.ParseInt("abc", 36, 64)
+	data, err := es.FetchData(5, topicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 3 {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	if data[0].DataText != "Are they worth it?" || data[0].AnswerID != topicID {
+		t.Errorf("post = %+v", data[0])
+	}
+	thread := "gopher (12 points):\nYes.\n\n> rustacean (3 points):\n> Mostly\n> yes."
+	if data[1].DataText != thread {
+		t.Errorf("thread = %q, want %q", data[1].DataText, thread)
+	}
+	if !strings.HasPrefix(data[2].DataText, "other (1 points):") {
+		t.Errorf("comment without replies = %q", data[2].DataText)
+	}
+}
+
+func TestInitRequiresCredentials(t *testing.T) {
+	if err := New().Init(); err == nil {
+		t.Error("Init succeeded without a ClientID and ClientSecret")
+	}
+}
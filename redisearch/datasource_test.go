@@ -0,0 +1,174 @@
+package redisearch
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newServer starts a RESP2 server answering FT.SEARCH with reply and other commands but HELLO with OK,
+// and returns its URL and the FT.SEARCH commands it received
+func newServer(t *testing.T, reply string) (string, func() [][]string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	searches := make(chan []string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					args, err := readCommand(r)
+					if err != nil {
+						return
+					}
+					switch {
+					case strings.EqualFold(args[0], "FT.SEARCH"):
+						searches <- args
+						io.WriteString(conn, reply)
+					case strings.EqualFold(args[0], "HELLO"):
+						// As a server before Redis 6, so the client falls back to plain RESP2
+						io.WriteString(conn, "-ERR unknown command 'HELLO'\r\n")
+					default:
+						io.WriteString(conn, "+OK\r\n")
+					}
+				}
+			}()
+		}
+	}()
+	return "redis://" + ln.Addr().String(), func() [][]string {
+		var got [][]string
+		for len(searches) > 0 {
+			got = append(got, <-searches)
+		}
+		return got
+	}
+}
+
+// readCommand reads a command sent as an array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+// bulk encodes a RESP2 bulk string
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+// array encodes strings as a RESP2 array of bulk strings
+func array(items ...string) string {
+	s := fmt.Sprintf("*%d\r\n", len(items))
+	for _, item := range items {
+		s += bulk(item)
+	}
+	return s
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	reply := "*7\r\n:2\r\n" +
+		bulk("doc:guide") + bulk("1.5") + array("title", "The **install** guide", "url", "https://example.com/guide", "body", "Run the **install** script", "lang", "en") +
+		bulk("doc:bare") + bulk("0.5") + array("body", "No title here")
+	url, searches := newServer(t, reply)
+	es := New()
+	es.URL = url
+	es.Index = "idx:docs"
+	es.Filter = "@lang:{en}"
+	es.URLField = "url"
+	es.DataFields = []string{"lang"}
+	es.HighlightFields = []string{"body"}
+	es.Summarize = true
+	defer es.Close()
+
+	topics, err := es.FetchTopics(3, "install v1.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"The install guide|https://example.com/guide", "doc:bare|"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].SourceURL; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+
+	wantArgs := []string{"FT.SEARCH", "idx:docs", `(install v1\.2) @lang:{en}`, "WITHSCORES",
+		"RETURN", "4", "title", "url", "lang", "body",
+		"SUMMARIZE", "FIELDS", "1", "body",
+		"HIGHLIGHT", "FIELDS", "1", "body", "TAGS", "**", "**",
+		"LIMIT", "0", "3", "DIALECT", "2"}
+	if got := searches(); len(got) != 1 || !reflect.DeepEqual(got[0], wantArgs) {
+		t.Errorf("sent %q, want %q", got, wantArgs)
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "The install guide\nbody: Run the **install** script\nlang: en\nscore: 1.500"
+	if len(data) != 1 || data[0].DataText != wantData || data[0].Site != "redis/idx:docs" {
+		t.Errorf("FetchData = %+v, want %q", data, wantData)
+	}
+}
+
+func TestFetchTopicsError(t *testing.T) {
+	url, _ := newServer(t, "-idx:missing: no such index\r\n")
+	es := New()
+	es.URL = url
+	es.Index = "idx:missing"
+	defer es.Close()
+	if _, err := es.FetchTopics(3, "install"); err == nil || !strings.Contains(err.Error(), "no such index") {
+		t.Errorf("FetchTopics = %v", err)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := map[string]string{
+		"plain words":    "plain words",
+		"user@host.com":  `user\@host\.com`,
+		"-negated (a|b)": `\-negated \(a\|b\)`,
+		`back\slash`:     `back\\slash`,
+	}
+	for in, want := range tests {
+		if got := escape(in); got != want {
+			t.Errorf("escape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
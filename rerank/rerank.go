@@ -0,0 +1,197 @@
+// Package rerank reorders topics by BM25 relevance of their titles and
+// snippets to the query. Each backend ranks by its own notion of relevance, so the order of
+// merged federated results says little; rescoring everything against the same
+// query puts them on one scale.
+package rerank
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+// BM25 parameters commonly used for short documents
+const (
+	DefaultK1 = 1.2
+	DefaultB  = 0.75
+)
+
+// Reranker wraps a source, usually a composite, and reorders its topics by BM25 score. It implements
+// source.Source, so it can be used anywhere a data source is expected.
+type Reranker struct {
+	Source source.Source
+	K1     float64 // Term frequency saturation; default DefaultK1
+	B      float64 // Length normalization; default DefaultB
+	// Candidates multiplies the requested count to give the reranker more results to choose from;
+	// default 2, so a search for 5 topics ranks 10 and keeps the best 5
+	Candidates int
+}
+
+func New(s source.Source) *Reranker {
+	return &Reranker{Source: s, K1: DefaultK1, B: DefaultB, Candidates: 2}
+}
+
+// Init implements models.DataSource
+func (r *Reranker) Init() error {
+	return r.Source.Init()
+}
+
+// CheckAvailability implements models.DataSource
+func (r *Reranker) CheckAvailability() bool {
+	return r.Source.CheckAvailability()
+}
+
+// Unwrap implements source.Wrapper
+func (r *Reranker) Unwrap() source.Source {
+	return r.Source
+}
+
+// FetchTopics implements models.DataSource
+// Fetches count×Candidates topics and returns the count with the best BM25 scores
+func (r *Reranker) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	if count <= 0 {
+		count = 5
+	}
+	candidates := count
+	if r.Candidates > 1 {
+		candidates *= r.Candidates
+	}
+	topics, err := r.Source.FetchTopics(candidates, input)
+	if err != nil {
+		return nil, err
+	}
+	topics = r.Sort(input, topics)
+	if len(topics) > count {
+		topics = topics[:count]
+	}
+	return topics, nil
+}
+
+// FetchData implements models.DataSource
+func (r *Reranker) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return r.Source.FetchData(count, topicID)
+}
+
+// Sort returns topics ordered by descending BM25 score against query. Topics with equal scores,
+// including those that match nothing, keep their original order.
+func (r *Reranker) Sort(query string, topics []datasource.DataSourceTopic) []datasource.DataSourceTopic {
+	scores := r.Scores(query, topics)
+	order := make([]int, len(topics))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	sorted := make([]datasource.DataSourceTopic, len(topics))
+	for i, o := range order {
+		sorted[i] = topics[o]
+	}
+	return sorted
+}
+
+// Scores returns the BM25 score of each topic against query. Document frequencies come from the
+// topics themselves, so terms shared by every result count for little.
+func (r *Reranker) Scores(query string, topics []datasource.DataSourceTopic) []float64 {
+	k1, b := r.K1, r.B
+	if k1 <= 0 {
+		k1 = DefaultK1
+	}
+	if b < 0 || b > 1 {
+		b = DefaultB
+	}
+
+	docs := make([][]string, len(topics))
+	df := map[string]int{}
+	total := 0
+	for i, t := range topics {
+		docs[i] = Terms(r.document(t))
+		total += len(docs[i])
+		seen := map[string]bool{}
+		for _, term := range docs[i] {
+			if !seen[term] {
+				seen[term] = true
+				df[term]++
+			}
+		}
+	}
+	scores := make([]float64, len(topics))
+	if total == 0 {
+		return scores
+	}
+	avgLen := float64(total) / float64(len(topics))
+	n := float64(len(topics))
+
+	queryTerms := map[string]bool{}
+	for _, term := range Terms(query) {
+		queryTerms[term] = true
+	}
+	for i, doc := range docs {
+		tf := map[string]int{}
+		for _, term := range doc {
+			if queryTerms[term] {
+				tf[term]++
+			}
+		}
+		for term, f := range tf {
+			idf := math.Log(1 + (n-float64(df[term])+0.5)/(float64(df[term])+0.5))
+			freq := float64(f)
+			scores[i] += idf * freq * (k1 + 1) / (freq + k1*(1-b+b*float64(len(doc))/avgLen))
+		}
+	}
+	return scores
+}
+
+// Terms splits text into lowercase words, dropping stop words and folding simple plurals
+func Terms(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := fields[:0]
+	for _, f := range fields {
+		if stopWords[f] {
+			continue
+		}
+		terms = append(terms, stem(f))
+	}
+	return terms
+}
+
+// document is the text a topic is scored on: its title, the words of its URL path and the snippet the
+// source reports for it, see source.Details
+func (r *Reranker) document(t datasource.DataSourceTopic) string {
+	text := t.Topic
+	if u, err := url.Parse(t.SourceURL); err == nil {
+		if p, err := url.PathUnescape(u.Path); err == nil {
+			text += " " + p
+		}
+	}
+	if d, ok := source.Details(r.Source, t.TopicID); ok && d.Snippet != "" {
+		text += " " + d.Snippet
+	}
+	return text
+}
+
+// Helpers
+
+func stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"do": true, "does": true, "for": true, "from": true, "how": true, "in": true, "is": true, "it": true,
+	"of": true, "on": true, "or": true, "that": true, "the": true, "this": true, "to": true, "was": true,
+	"what": true, "when": true, "where": true, "which": true, "who": true, "why": true, "with": true,
+}
@@ -0,0 +1,78 @@
+package rerank
+
+import (
+	"testing"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+// detailed returns fixed topics with snippets
+type detailed struct {
+	topics   []datasource.DataSourceTopic
+	snippets map[int64]string
+}
+
+func (d *detailed) Init() error             { return nil }
+func (d *detailed) CheckAvailability() bool { return true }
+
+func (d *detailed) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return d.topics, nil
+}
+
+func (d *detailed) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return nil, nil
+}
+
+func (d *detailed) Details(topicID int64) (source.TopicDetails, bool) {
+	snippet, ok := d.snippets[topicID]
+	return source.TopicDetails{Snippet: snippet}, ok
+}
+
+func TestFetchTopicsByTitle(t *testing.T) {
+	r := New(&detailed{topics: []datasource.DataSourceTopic{
+		{TopicID: 1, Topic: "Cooking with cast iron"},
+		{TopicID: 2, Topic: "Goroutines and channels in Go"},
+		{TopicID: 3, Topic: "Gardening tips"},
+	}})
+	topics, err := r.FetchTopics(1, "go channels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].TopicID != 2 {
+		t.Errorf("FetchTopics = %+v, want topic 2", topics)
+	}
+}
+
+func TestFetchTopicsBySnippet(t *testing.T) {
+	r := New(&detailed{
+		topics: []datasource.DataSourceTopic{
+			{TopicID: 1, Topic: "Weekly notes"},
+			{TopicID: 2, Topic: "Weekly notes"},
+		},
+		snippets: map[int64]string{
+			1: "Recipes for sourdough bread",
+			2: "Buffered channels block when full",
+		},
+	})
+	topics, err := r.FetchTopics(2, "buffered channels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[0].TopicID != 2 {
+		t.Errorf("FetchTopics = %+v, want topic 2 first for its snippet", topics)
+	}
+}
+
+func TestTerms(t *testing.T) {
+	got := Terms("The Libraries of Go channels")
+	want := []string{"library", "go", "channel"}
+	if len(got) != len(want) {
+		t.Fatalf("Terms = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Terms = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package restjson
+
+// Data Source Adapter for arbitrary JSON APIs, driven by a declarative config file
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/jsonpath"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultMaxPages   = 5
+)
+
+// Pagination modes
+const (
+	PaginateNone   = ""       // A single request
+	PaginatePage   = "page"   // Param carries a page number, starting at Start (default 1)
+	PaginateOffset = "offset" // Param carries an item offset, starting at Start and advancing by PageSize
+	PaginateCursor = "cursor" // Param carries the cursor read from NextCursor in the previous response
+)
+
+// URL template placeholders; {query} and {id} are query-escaped
+const (
+	PlaceholderQuery = "{query}"
+	PlaceholderCount = "{count}"
+	PlaceholderID    = "{id}"
+)
+
+// Field maps a JSON path, relative to an item, to a line of data text
+type Field struct {
+	Label string `json:"label"` // Written as "Label: value"; empty writes the value alone
+	Path  string `json:"path"`
+}
+
+// Pagination describes how to request further pages of search results
+type Pagination struct {
+	Mode       string `json:"mode"`
+	Param      string `json:"param"`
+	Start      int    `json:"start"`
+	PageSize   int    `json:"page_size"`  // Items per page; sent as SizeParam when that is set
+	SizeParam  string `json:"size_param"` // Optional
+	NextCursor string `json:"next_cursor"`
+	MaxPages   int    `json:"max_pages"` // Default 5
+}
+
+// Config describes a JSON API. "${VAR}" references in header values are expanded from the environment
+// by LoadConfig, so keys can stay out of the file. Paths use the JSONPath subset of internal/jsonpath
+// (members, indexes and [*]); JMESPath expressions are not supported.
+type Config struct {
+	SearchURL  string            `json:"search_url"` // e.g. "https://api.example.com/search?q={query}&limit={count}"
+	Headers    map[string]string `json:"headers"`
+	Pagination Pagination        `json:"pagination"`
+
+	// Items selects the result list in a search response, e.g. "results[*]"; the remaining paths are
+	// relative to one item
+	Items  string `json:"items"`
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Detail string `json:"detail"` // Optional; appended to the title in parentheses
+	Site   string `json:"site"`   // Fixed Site for topics and data
+
+	// DataURL optionally fetches the full item for FetchData, e.g. "https://api.example.com/items/{id}";
+	// DataItem selects the item in that response. Without it, FetchData reads the search result.
+	DataURL  string  `json:"data_url"`
+	DataItem string  `json:"data_item"`
+	Data     []Field `json:"data"`
+}
+
+type DataSourceRESTJSON struct {
+	Config
+	Client    *http.Client
+	UserAgent string
+
+	items topicid.Map[item]
+}
+
+type item struct {
+	ID    string
+	Title string
+	URL   string
+	Value interface{}
+}
+
+func New() *DataSourceRESTJSON {
+	return &DataSourceRESTJSON{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		UserAgent: "locus/restjson-datasource",
+	}
+}
+
+// LoadConfig returns an adapter configured from a JSON file holding a Config
+func LoadConfig(path string) (*DataSourceRESTJSON, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	es := New()
+	if err := json.Unmarshal(raw, &es.Config); err != nil {
+		return nil, fmt.Errorf("restjson config %s: %w", path, err)
+	}
+	for key, value := range es.Headers {
+		es.Headers[key] = os.ExpandEnv(value)
+	}
+	return es, es.Init()
+}
+
+// Init implements models.DataSource
+// Validates the configuration and its JSON paths
+func (es *DataSourceRESTJSON) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if !strings.Contains(es.SearchURL, PlaceholderQuery) {
+		return fmt.Errorf("SearchURL must contain %s", PlaceholderQuery)
+	}
+	if es.Items == "" || es.ID == "" || es.Title == "" {
+		return errors.New("Items, ID and Title paths are required for RESTJSON DataSource")
+	}
+	if es.DataURL != "" && !strings.Contains(es.DataURL, PlaceholderID) {
+		return fmt.Errorf("DataURL must contain %s", PlaceholderID)
+	}
+	switch es.Pagination.Mode {
+	case PaginateNone:
+	case PaginatePage, PaginateOffset, PaginateCursor:
+		if es.Pagination.Param == "" {
+			return errors.New("Pagination.Param is required when paginating")
+		}
+		if es.Pagination.Mode == PaginateCursor && es.Pagination.NextCursor == "" {
+			return errors.New("Pagination.NextCursor is required for cursor pagination")
+		}
+		if es.Pagination.Mode == PaginateOffset && es.Pagination.PageSize <= 0 {
+			return errors.New("Pagination.PageSize is required for offset pagination")
+		}
+	default:
+		return fmt.Errorf("unknown pagination mode %q", es.Pagination.Mode)
+	}
+	paths := []string{es.Items, es.ID, es.Title, es.URL, es.Detail, es.DataItem, es.Pagination.NextCursor}
+	for _, field := range es.Data {
+		paths = append(paths, field.Path)
+	}
+	for _, path := range paths {
+		if _, err := jsonpath.Compile(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceRESTJSON) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	var doc interface{}
+	return es.doJSON(ctx, es.expand(es.SearchURL, "test", 1), &doc) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceRESTJSON) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name: "REST API",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Requests pages until count items are collected, a page comes back empty, or MaxPages is reached
+func (es *DataSourceRESTJSON) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for RESTJSON DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	p := es.Pagination
+	maxPages := p.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+	if p.Mode == PaginateNone {
+		maxPages = 1
+	}
+	position := p.Start
+	if p.Mode == PaginatePage && position == 0 {
+		position = 1
+	}
+	cursor := ""
+	itemsPath := jsonpath.MustCompile(es.Items)
+
+	seen := map[string]bool{}
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for page := 0; page < maxPages && len(results) < count; page++ {
+		uri, err := es.pageURL(es.expand(es.SearchURL, query, count), position, cursor, page)
+		if err != nil {
+			return nil, err
+		}
+		var doc interface{}
+		if err := es.doJSON(ctx, uri, &doc); err != nil {
+			// Later pages are best effort; keep what the earlier ones returned
+			if page > 0 {
+				break
+			}
+			return nil, err
+		}
+		values := itemsPath.Select(doc)
+		if len(values) == 0 {
+			break
+		}
+		for _, value := range values {
+			if len(results) >= count {
+				break
+			}
+			i := item{ID: text(value, es.ID), Title: text(value, es.Title), URL: text(value, es.URL), Value: value}
+			if i.ID == "" || seen[i.ID] {
+				continue
+			}
+			seen[i.ID] = true
+			title := i.Title
+			if detail := text(value, es.Detail); detail != "" {
+				title += " (" + detail + ")"
+			}
+			results = append(results, datasource.DataSourceTopic{
+				Topic:     title,
+				SourceURL: i.URL,
+				Site:      es.Site,
+				TopicID:   es.items.Put(es.SearchURL+"#"+i.ID, i),
+			})
+		}
+
+		switch p.Mode {
+		case PaginatePage:
+			position++
+		case PaginateOffset:
+			position += p.PageSize
+		case PaginateCursor:
+			cursor = text(doc, p.NextCursor)
+			if cursor == "" {
+				return results, nil
+			}
+		}
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns one item: the title followed by a line per configured Data field that has a value
+func (es *DataSourceRESTJSON) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	i, ok := es.items.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown RESTJSON topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	value := i.Value
+	if es.DataURL != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+		defer cancel()
+		var doc interface{}
+		if err := es.doJSON(ctx, strings.ReplaceAll(es.DataURL, PlaceholderID, url.QueryEscape(i.ID)), &doc); err != nil {
+			return nil, err
+		}
+		found, ok := jsonpath.MustCompile(es.DataItem).First(doc)
+		if !ok || found == nil {
+			return nil, fmt.Errorf("restjson item %q not found", i.ID)
+		}
+		value = found
+	}
+
+	lines := []string{i.Title}
+	for _, field := range es.Data {
+		v := text(value, field.Path)
+		if v == "" {
+			continue
+		}
+		if field.Label != "" {
+			v = field.Label + ": " + v
+		}
+		lines = append(lines, v)
+	}
+	return []datasource.DataSourceData{{
+		DataText:  strings.Join(lines, "\n"),
+		SourceURL: i.URL,
+		Site:      es.Site,
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// expand fills the {query} and {count} placeholders of a URL template
+func (es *DataSourceRESTJSON) expand(template, query string, count int) string {
+	return strings.NewReplacer(
+		PlaceholderQuery, url.QueryEscape(query),
+		PlaceholderCount, strconv.Itoa(count),
+	).Replace(template)
+}
+
+// pageURL adds the pagination parameters for one page to a search URL
+func (es *DataSourceRESTJSON) pageURL(uri string, position int, cursor string, page int) (string, error) {
+	p := es.Pagination
+	if p.Mode == PaginateNone {
+		return uri, nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	params := u.Query()
+	if p.SizeParam != "" && p.PageSize > 0 {
+		params.Set(p.SizeParam, strconv.Itoa(p.PageSize))
+	}
+	switch p.Mode {
+	case PaginatePage, PaginateOffset:
+		params.Set(p.Param, strconv.Itoa(position))
+	case PaginateCursor:
+		// The first page is requested without a cursor
+		if page > 0 {
+			params.Set(p.Param, cursor)
+		}
+	}
+	u.RawQuery = params.Encode()
+	return u.String(), nil
+}
+
+// doJSON performs an HTTP GET with the configured headers and decodes the JSON response into target
+func (es *DataSourceRESTJSON) doJSON(ctx context.Context, uri string, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	for key, value := range es.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("restjson request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+
+// text evaluates an already validated path against value; an empty path yields ""
+func text(value interface{}, path string) string {
+	if path == "" {
+		return ""
+	}
+	return strings.TrimSpace(jsonpath.MustCompile(path).Text(value))
+}
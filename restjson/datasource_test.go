@@ -0,0 +1,105 @@
+package restjson
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "secret" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		q := r.URL.Query()
+		if q.Get("q") != "red panda" || q.Get("limit") != "3" || q.Get("size") != "2" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch q.Get("cursor") + q.Get("offset") {
+		case "", "0":
+			fmt.Fprint(w, `{"results":[{"id":"1","name":"Ailurus","url":"https://example.com/1","kind":"genus"},{"id":"1","name":"duplicate"}],"next":"c2"}`)
+		case "c2", "2":
+			fmt.Fprint(w, `{"results":[{"id":"2","name":"Firefox","kind":"species"},{"id":"3","name":"Styani"},{"id":"4","name":"too many"}],"next":""}`)
+		default:
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+			fmt.Fprint(w, `{"results":[]}`)
+		}
+	})
+	mux.HandleFunc("/items", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") != "1" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"item":{"summary":"A small mammal.","habitat":["forest","bamboo"],"weight":5.5}}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSource(t *testing.T, pagination Pagination) *DataSourceRESTJSON {
+	t.Helper()
+	srv := newServer(t)
+	es := New()
+	es.SearchURL = srv.URL + "/search?q={query}&limit={count}"
+	es.Headers = map[string]string{"X-API-Key": "secret"}
+	es.Pagination = pagination
+	es.Items = "results[*]"
+	es.ID = "id"
+	es.Title = "name"
+	es.URL = "url"
+	es.Detail = "kind"
+	es.Site = "example.com"
+	es.DataURL = srv.URL + "/items?id={id}"
+	es.DataItem = "item"
+	es.Data = []Field{{Path: "summary"}, {Label: "Habitat", Path: "habitat"}, {Label: "Weight", Path: "weight"}, {Label: "Missing", Path: "missing"}}
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	for _, pagination := range []Pagination{
+		{Mode: PaginateCursor, Param: "cursor", NextCursor: "next", PageSize: 2, SizeParam: "size"},
+		{Mode: PaginateOffset, Param: "offset", PageSize: 2, SizeParam: "size"},
+	} {
+		es := newSource(t, pagination)
+		topics, err := es.FetchTopics(3, "red panda")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"Ailurus (genus)", "Firefox (species)", "Styani"}
+		if len(topics) != len(want) {
+			t.Fatalf("%s: FetchTopics = %+v", pagination.Mode, topics)
+		}
+		for i, title := range want {
+			if topics[i].Topic != title {
+				t.Errorf("%s: topic %d = %q, want %q", pagination.Mode, i, topics[i].Topic, title)
+			}
+		}
+
+		data, err := es.FetchData(1, topics[0].TopicID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantData := "Ailurus\nA small mammal.\nHabitat: forest, bamboo\nWeight: 5.5"
+		if len(data) != 1 || data[0].DataText != wantData || data[0].SourceURL != "https://example.com/1" {
+			t.Errorf("%s: FetchData = %+v, want %q", pagination.Mode, data, wantData)
+		}
+	}
+}
+
+func TestInit(t *testing.T) {
+	tests := map[string]Pagination{
+		"cursor without NextCursor": {Mode: PaginateCursor, Param: "cursor"},
+		"offset without PageSize":   {Mode: PaginateOffset, Param: "offset"},
+		"page without Param":        {Mode: PaginatePage},
+		"unknown mode":              {Mode: "link"},
+	}
+	for name, pagination := range tests {
+		es := newSource(t, pagination)
+		if err := es.Init(); err == nil {
+			t.Errorf("Init accepted %s", name)
+		}
+	}
+}
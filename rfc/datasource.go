@@ -0,0 +1,376 @@
+package rfc
+
+// Data Source Adapter for IETF RFCs and Internet-Drafts (Datatracker API + rfc-editor.org)
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	// Topic IDs below this are RFC numbers; larger ones are hashed draft names
+	maxRFCNumber = 100000
+)
+
+// stdLevels maps Datatracker std_level slugs to their display names
+var stdLevels = map[string]string{
+	"std":  "Internet Standard",
+	"ds":   "Draft Standard",
+	"ps":   "Proposed Standard",
+	"bcp":  "Best Current Practice",
+	"inf":  "Informational",
+	"exp":  "Experimental",
+	"hist": "Historic",
+	"unkn": "Unknown",
+}
+
+var (
+	// sectionHeading matches numbered headings at column 0, e.g. "4.2.1.  Request Target"
+	sectionHeading = regexp.MustCompile(`^(?:\d+(?:\.\d+)*\.|Appendix [A-Z]\.)\s+\S`)
+	// pageFooter matches the "Author  Standards Track  [Page 12]" lines paginated RFCs carry
+	pageFooter = regexp.MustCompile(`\[Page \d+\]\s*$`)
+)
+
+type DataSourceRFC struct {
+	Client        *http.Client
+	TrackerURL    string // Datatracker root
+	EditorURL     string // rfc-editor.org root, used for RFC plaintext
+	DraftURL      string // Internet-Draft archive root
+	UserAgent     string
+	IncludeDrafts bool // Also search active Internet-Drafts
+	FullText      bool // Return the document text in sections instead of the abstract
+
+	drafts topicid.Map[document]
+}
+
+// document identifies an RFC or draft and carries what FetchData needs without a second lookup
+type document struct {
+	Name     string
+	Rev      string
+	Abstract string
+	URL      string
+}
+
+func New() *DataSourceRFC {
+	return &DataSourceRFC{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		TrackerURL: "https://datatracker.ietf.org",
+		EditorURL:  "https://www.rfc-editor.org",
+		DraftURL:   "https://www.ietf.org/archive/id",
+		UserAgent:  "locus/rfc-datasource",
+	}
+}
+
+// Init implements models.DataSource
+// The IETF APIs require no initialization
+func (es *DataSourceRFC) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceRFC) CheckAvailability() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("format", "json")
+	params.Set("limit", "1")
+	return es.doJSON(ctx, strings.TrimRight(es.TrackerURL, "/")+"/api/v1/doc/document/?"+params.Encode(), &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceRFC) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "RFC Editor",
+		Homepage:    "https://www.rfc-editor.org",
+		License:     "IETF Trust Legal Provisions",
+		LicenseURL:  "https://trustee.ietf.org/documents/trust-legal-provisions/",
+		Attribution: "IETF Trust and the persons identified as the document authors",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Searches document titles; topics read "RFC 9110: HTTP Semantics (Proposed Standard, 2022-06-06)"
+func (es *DataSourceRFC) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for RFC DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	types := []string{"rfc"}
+	if es.IncludeDrafts {
+		types = append(types, "draft")
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, docType := range types {
+		if len(results) >= count {
+			break
+		}
+		params := url.Values{}
+		params.Set("format", "json")
+		params.Set("type", docType)
+		params.Set("title__icontains", query)
+		params.Set("order_by", "-time")
+		params.Set("limit", strconv.Itoa(count-len(results)))
+		if docType == "draft" {
+			params.Set("states__slug", "active")
+		}
+
+		var response struct {
+			Objects []struct {
+				Name     string `json:"name"`
+				Title    string `json:"title"`
+				Rev      string `json:"rev"`
+				Time     string `json:"time"`
+				Abstract string `json:"abstract"`
+				StdLevel string `json:"std_level"`
+			} `json:"objects"`
+		}
+		uri := strings.TrimRight(es.TrackerURL, "/") + "/api/v1/doc/document/?" + params.Encode()
+		if err := es.doJSON(ctx, uri, &response); err != nil {
+			if len(results) > 0 {
+				break
+			}
+			return nil, err
+		}
+
+		for _, doc := range response.Objects {
+			date := strings.SplitN(doc.Time, "T", 2)[0]
+			if docType == "rfc" {
+				number, err := strconv.ParseInt(strings.TrimPrefix(doc.Name, "rfc"), 10, 64)
+				if err != nil {
+					continue
+				}
+				results = append(results, datasource.DataSourceTopic{
+					Topic:     fmt.Sprintf("RFC %d: %s (%s, %s)", number, doc.Title, stdLevel(doc.StdLevel), date),
+					SourceURL: es.rfcLink(number),
+					TopicID:   number,
+				})
+				continue
+			}
+			link := fmt.Sprintf("%s/doc/%s/%s/", strings.TrimRight(es.TrackerURL, "/"), doc.Name, doc.Rev)
+			id := es.drafts.Put(doc.Name, document{Name: doc.Name, Rev: doc.Rev, Abstract: doc.Abstract, URL: link})
+			results = append(results, datasource.DataSourceTopic{
+				Topic:     fmt.Sprintf("%s-%s: %s (Internet-Draft, %s)", doc.Name, doc.Rev, doc.Title, date),
+				SourceURL: link,
+				TopicID:   id,
+			})
+		}
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the abstract, or with FullText set, up to count numbered sections of the plaintext document
+func (es *DataSourceRFC) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var doc document
+	var textURL string
+	if topicID < maxRFCNumber {
+		doc = document{Name: fmt.Sprintf("rfc%d", topicID), URL: es.rfcLink(topicID)}
+		textURL = fmt.Sprintf("%s/rfc/rfc%d.txt", strings.TrimRight(es.EditorURL, "/"), topicID)
+	} else {
+		var ok bool
+		if doc, ok = es.drafts.Get(topicID); !ok {
+			return nil, fmt.Errorf("unknown RFC topicID %d", topicID)
+		}
+		textURL = fmt.Sprintf("%s/%s-%s.txt", strings.TrimRight(es.DraftURL, "/"), doc.Name, doc.Rev)
+	}
+
+	if !es.FullText {
+		abstract := doc.Abstract
+		if abstract == "" {
+			var err error
+			if abstract, err = es.fetchAbstract(ctx, doc.Name); err != nil {
+				return nil, err
+			}
+		}
+		abstract = strings.TrimSpace(abstract)
+		if abstract == "" {
+			return []datasource.DataSourceData{}, nil
+		}
+		return []datasource.DataSourceData{{
+			DataText:  abstract,
+			SourceURL: doc.URL,
+			AnswerID:  topicID,
+		}}, nil
+	}
+
+	body, err := es.get(ctx, textURL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	sections, err := splitSections(body)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, section := range sections {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  section.Text,
+			SourceURL: doc.URL + "#section-" + section.Number,
+			AnswerID:  topicid.Hash(doc.Name + "#" + section.Number),
+		})
+	}
+	return results, nil
+}
+
+// fetchAbstract reads the abstract of a single document from the Datatracker
+func (es *DataSourceRFC) fetchAbstract(ctx context.Context, name string) (string, error) {
+	var doc struct {
+		Abstract string `json:"abstract"`
+	}
+	uri := fmt.Sprintf("%s/api/v1/doc/document/%s/?format=json", strings.TrimRight(es.TrackerURL, "/"), url.PathEscape(name))
+	if err := es.doJSON(ctx, uri, &doc); err != nil {
+		return "", err
+	}
+	return doc.Abstract, nil
+}
+
+func (es *DataSourceRFC) rfcLink(number int64) string {
+	return fmt.Sprintf("%s/rfc/rfc%d.html", strings.TrimRight(es.EditorURL, "/"), number)
+}
+
+// section is one numbered section of a plaintext RFC
+type section struct {
+	Number string
+	Text   string
+}
+
+// splitSections breaks a plaintext RFC into numbered sections, dropping front matter,
+// the table of contents (which is indented) and the page headers and footers of paginated RFCs
+func splitSections(r io.Reader) ([]section, error) {
+	var sections []section
+	var current *section
+	var lines []string
+	flush := func() {
+		if current != nil {
+			current.Text = strings.TrimSpace(strings.Join(lines, "\n"))
+			if current.Text != "" {
+				sections = append(sections, *current)
+			}
+		}
+		lines = lines[:0]
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	skipHeader := false
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		// Form feeds separate pages; the line after one is the running header
+		if strings.HasPrefix(line, "\f") {
+			skipHeader = true
+			line = strings.TrimPrefix(line, "\f")
+			if line == "" {
+				continue
+			}
+		}
+		if skipHeader {
+			skipHeader = false
+			if strings.HasPrefix(line, "RFC ") || strings.HasPrefix(line, "Internet-Draft") {
+				continue
+			}
+		}
+		if pageFooter.MatchString(line) {
+			continue
+		}
+		if sectionHeading.MatchString(line) {
+			flush()
+			number := strings.TrimSuffix(strings.Fields(line)[0], ".")
+			if strings.HasPrefix(line, "Appendix ") {
+				number = "appendix-" + strings.TrimSuffix(strings.Fields(line)[1], ".")
+			}
+			current = &section{Number: number}
+		}
+		if current != nil {
+			lines = append(lines, line)
+		}
+	}
+	flush()
+	return sections, scanner.Err()
+}
+
+// doJSON performs an HTTP GET request and decodes the JSON response into target
+func (es *DataSourceRFC) doJSON(ctx context.Context, uri string, target interface{}) error {
+	body, err := es.get(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(target)
+}
+
+// get performs an HTTP GET request. The caller must close the returned body.
+func (es *DataSourceRFC) get(ctx context.Context, uri string) (io.ReadCloser, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("rfc request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// Helpers
+
+// stdLevel converts a std_level resource URI such as "/api/v1/name/stdlevelname/ps/" into a display name
+func stdLevel(resource string) string {
+	parts := strings.Split(strings.Trim(resource, "/"), "/")
+	if name, ok := stdLevels[parts[len(parts)-1]]; ok {
+		return name
+	}
+	return "Unknown"
+}
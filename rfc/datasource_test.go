@@ -0,0 +1,133 @@
+package rfc
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const rfcText = `Internet Engineering Task Force (IETF)                  R. Fielding, Ed.
+Request for Comments: 9110                                         Adobe
+
+                            HTTP Semantics
+
+Table of Contents
+
+   1.  Introduction
+
+1.  Introduction
+
+   The Hypertext Transfer Protocol (HTTP) is a family of protocols.
+
+Fielding, et al.             Standards Track                    [Page 1]
+` + "\f" + `
+RFC 9110                     HTTP Semantics                    June 2022
+
+   It continues here.
+
+1.1.  Purpose
+
+   Purpose text.
+
+Appendix A.  Collected ABNF
+
+   ABNF text.
+`
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/doc/document/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/doc/document/rfc9110/" {
+			fmt.Fprint(w, `{"abstract":" The abstract. "}`)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("title__icontains") != "http" || q.Get("format") != "json" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch q.Get("type") {
+		case "rfc":
+			fmt.Fprint(w, `{"objects":[
+				{"name":"rfc9110","title":"HTTP Semantics","time":"2022-06-06T12:00:00","std_level":"/api/v1/name/stdlevelname/std/"},
+				{"name":"bcp14","title":"Not an RFC number"}
+			]}`)
+		case "draft":
+			if q.Get("states__slug") != "active" || q.Get("limit") != "2" {
+				t.Errorf("draft query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"objects":[{"name":"draft-ietf-httpbis-foo","rev":"03","title":"HTTP Foo","time":"2024-01-01T00:00:00","abstract":"Draft abstract."}]}`)
+		}
+	})
+	mux.HandleFunc("/rfc/rfc9110.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, rfcText)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopics(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.TrackerURL = srv.URL
+	es.EditorURL = srv.URL
+	es.IncludeDrafts = true
+
+	topics, err := es.FetchTopics(3, "http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	if topics[0].TopicID != 9110 || topics[0].Topic != "RFC 9110: HTTP Semantics (Internet Standard, 2022-06-06)" || topics[0].SourceURL != srv.URL+"/rfc/rfc9110.html" {
+		t.Errorf("rfc = %+v", topics[0])
+	}
+	if topics[1].Topic != "draft-ietf-httpbis-foo-03: HTTP Foo (Internet-Draft, 2024-01-01)" {
+		t.Errorf("draft = %+v", topics[1])
+	}
+
+	data, err := es.FetchData(1, topics[1].TopicID)
+	if err != nil || len(data) != 1 || data[0].DataText != "Draft abstract." {
+		t.Errorf("draft FetchData = %+v, %v", data, err)
+	}
+	data, err = es.FetchData(1, 9110)
+	if err != nil || len(data) != 1 || data[0].DataText != "The abstract." {
+		t.Errorf("rfc FetchData = %+v, %v", data, err)
+	}
+}
+
+func TestFetchDataFullText(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.TrackerURL = srv.URL
+	es.EditorURL = srv.URL
+	es.FullText = true
+
+	data, err := es.FetchData(5, 9110)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 3 {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	intro := data[0].DataText
+	if !strings.HasPrefix(intro, "1.  Introduction") || !strings.Contains(intro, "It continues here.") || strings.Contains(intro, "[Page 1]") || strings.Contains(intro, "RFC 9110 ") {
+		t.Errorf("section 1 = %q", intro)
+	}
+	if data[0].SourceURL != srv.URL+"/rfc/rfc9110.html#section-1" || !strings.HasSuffix(data[2].SourceURL, "#section-appendix-A") {
+		t.Errorf("section links %q, %q", data[0].SourceURL, data[2].SourceURL)
+	}
+}
+
+func TestStdLevel(t *testing.T) {
+	if got := stdLevel("/api/v1/name/stdlevelname/ps/"); got != "Proposed Standard" {
+		t.Errorf("stdLevel = %q", got)
+	}
+	if got := stdLevel(""); got != "Unknown" {
+		t.Errorf("stdLevel of nothing = %q", got)
+	}
+}
@@ -0,0 +1,253 @@
+package router
+
+// Data Source combinator that classifies each query and searches only the sources suited to its class
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/composite"
+	"github.com/locus-search/datasource/source"
+)
+
+type DataSourceRouter struct {
+	Rules []Rule // Classification rules; default DefaultRules
+	// Routes maps a query class to the names of the members to search for it, e.g.
+	// {"code": {"stackexchange", "sourcegraph"}, "academic": {"pubmed", "europepmc"}}
+	Routes map[string][]string
+	// Default names the members searched when no routed class matches; empty searches all of them
+	Default []string
+	// Merging of the routed members' topics, as in composite.DataSourceComposite; Init applies them
+	Merger       composite.Merger
+	MaxPerDomain int
+	MaxPerSource int
+
+	members *composite.DataSourceComposite
+}
+
+// Classification is a query class with the points its rule earned
+type Classification struct {
+	Class string
+	Score int
+}
+
+func New() *DataSourceRouter {
+	return &DataSourceRouter{
+		Rules:   append([]Rule(nil), DefaultRules...),
+		Routes:  map[string][]string{},
+		members: composite.New(),
+	}
+}
+
+// Add registers a source under name, the name Routes and Default refer to
+func (es *DataSourceRouter) Add(name string, s source.Source) {
+	if es.members == nil {
+		es.members = composite.New()
+	}
+	es.members.Add(name, s)
+}
+
+// Init implements models.DataSource
+// Compiles the rules, checks that routes only name registered members, applies the merge settings and
+// initializes the members
+func (es *DataSourceRouter) Init() error {
+	if es.members == nil || len(es.members.Members) == 0 {
+		return errors.New("at least one member is required for Router DataSource")
+	}
+	if es.Rules == nil {
+		es.Rules = append([]Rule(nil), DefaultRules...)
+	}
+	for i := range es.Rules {
+		if err := es.Rules[i].compile(); err != nil {
+			return err
+		}
+	}
+	names := map[string]bool{}
+	for _, m := range es.members.Members {
+		names[m.Name] = true
+	}
+	for class, route := range es.Routes {
+		for _, name := range route {
+			if !names[name] {
+				return fmt.Errorf("route %q names unknown member %q", class, name)
+			}
+		}
+	}
+	for _, name := range es.Default {
+		if !names[name] {
+			return fmt.Errorf("default route names unknown member %q", name)
+		}
+	}
+	es.members.Merger = es.Merger
+	es.members.MaxPerDomain = es.MaxPerDomain
+	es.members.MaxPerSource = es.MaxPerSource
+	return es.members.Init()
+}
+
+// CheckAvailability implements models.DataSource
+// Reports whether any member is available
+func (es *DataSourceRouter) CheckAvailability() bool {
+	return es.members != nil && es.members.CheckAvailability()
+}
+
+// Close implements source.Closer
+func (es *DataSourceRouter) Close(ctx context.Context) error {
+	if es.members == nil {
+		return nil
+	}
+	return es.members.Close(ctx)
+}
+
+// FetchTopics implements models.DataSource
+// Searches the members routed for the best-scoring class that has a route, merging results as the
+// composite does. Queries that match no routed class go to Default.
+func (es *DataSourceRouter) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	if es.members == nil {
+		return nil, errors.New("at least one member is required for Router DataSource")
+	}
+	return es.members.FetchTopicsFrom(es.Route(input), count, input)
+}
+
+// FetchData implements models.DataSource
+// Forwards to the member that returned the topic
+func (es *DataSourceRouter) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if es.members == nil {
+		return nil, fmt.Errorf("unknown Router topicID %d", topicID)
+	}
+	return es.members.FetchData(count, topicID)
+}
+
+// FetchDataBatch implements source.Batcher
+func (es *DataSourceRouter) FetchDataBatch(ctx context.Context, count int, topicIDs []int64) (map[int64][]datasource.DataSourceData, error) {
+	if es.members == nil {
+		return nil, errors.New("at least one member is required for Router DataSource")
+	}
+	return es.members.FetchDataBatch(ctx, count, topicIDs)
+}
+
+// StringID implements source.IDSource
+func (es *DataSourceRouter) StringID(topicID int64) (source.ID, bool) {
+	if es.members == nil {
+		return "", false
+	}
+	return es.members.StringID(topicID)
+}
+
+// TopicID implements source.IDSource
+func (es *DataSourceRouter) TopicID(id source.ID) (int64, error) {
+	if es.members == nil {
+		return 0, fmt.Errorf("unknown Router topic ID %q: %w", id, source.ErrBadQuery)
+	}
+	return es.members.TopicID(id)
+}
+
+// LastModified implements source.Freshness
+func (es *DataSourceRouter) LastModified(topicID int64) time.Time {
+	if es.members == nil {
+		return time.Time{}
+	}
+	return es.members.LastModified(topicID)
+}
+
+// Wall implements source.Walled
+func (es *DataSourceRouter) Wall(topicID int64) string {
+	if es.members == nil {
+		return ""
+	}
+	return es.members.Wall(topicID)
+}
+
+// TopicInfo implements source.TopicInformer
+func (es *DataSourceRouter) TopicInfo(topicID int64) (source.SourceInfo, bool) {
+	if es.members == nil {
+		return source.SourceInfo{}, false
+	}
+	return es.members.TopicInfo(topicID)
+}
+
+// Details implements source.Detailed
+func (es *DataSourceRouter) Details(topicID int64) (source.TopicDetails, bool) {
+	if es.members == nil {
+		return source.TopicDetails{}, false
+	}
+	return es.members.Details(topicID)
+}
+
+// Suggest implements source.Suggester
+// Partial queries are too short to classify, so every member is asked
+func (es *DataSourceRouter) Suggest(ctx context.Context, prefix string, count int) ([]string, error) {
+	if es.members == nil {
+		return nil, nil
+	}
+	return es.members.Suggest(ctx, prefix, count)
+}
+
+// Related implements source.RelatedSource
+func (es *DataSourceRouter) Related(ctx context.Context, topicID int64, count int) ([]datasource.DataSourceTopic, error) {
+	if es.members == nil {
+		return nil, fmt.Errorf("unknown Router topicID %d", topicID)
+	}
+	return es.members.Related(ctx, topicID, count)
+}
+
+// Trending implements source.TrendingSource
+func (es *DataSourceRouter) Trending(ctx context.Context, count int) ([]datasource.DataSourceTopic, error) {
+	if es.members == nil {
+		return nil, nil
+	}
+	return es.members.Trending(ctx, count)
+}
+
+// Route returns the names of the members input would be sent to; nil means all members
+func (es *DataSourceRouter) Route(input string) []string {
+	classes := es.Classify(input)
+	for i := 0; i < len(classes); {
+		// Classes with the same score are routed together
+		j := i
+		var names []string
+		for ; j < len(classes) && classes[j].Score == classes[i].Score; j++ {
+			for _, name := range es.Routes[classes[j].Class] {
+				if !contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+		if len(names) > 0 {
+			return names
+		}
+		i = j
+	}
+	if len(es.Default) > 0 {
+		return es.Default
+	}
+	return nil
+}
+
+// Classify returns the classes whose rules match input, best first. Rules must have been compiled by Init.
+func (es *DataSourceRouter) Classify(input string) []Classification {
+	query, padded := normalize(input)
+	var classes []Classification
+	for i := range es.Rules {
+		if score := es.Rules[i].score(query, padded); score > 0 {
+			classes = append(classes, Classification{es.Rules[i].Class, score})
+		}
+	}
+	sort.SliceStable(classes, func(i, j int) bool {
+		return classes[i].Score > classes[j].Score
+	})
+	return classes
+}
+
+// Helpers
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package router
+
+import (
+	"net/url"
+	"testing"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// fixed is a member returning the same topics for every query
+type fixed struct {
+	topics []datasource.DataSourceTopic
+}
+
+func (f *fixed) Init() error             { return nil }
+func (f *fixed) CheckAvailability() bool { return true }
+
+func (f *fixed) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return f.topics, nil
+}
+
+func (f *fixed) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return []datasource.DataSourceData{{DataText: "data", AnswerID: topicID}}, nil
+}
+
+func TestMergeSettings(t *testing.T) {
+	es := New()
+	es.Add("code", &fixed{topics: []datasource.DataSourceTopic{
+		{TopicID: 1, SourceURL: "https://go.dev/a"},
+		{TopicID: 2, SourceURL: "https://go.dev/b"},
+		{TopicID: 3, SourceURL: "https://pkg.go.dev/c"},
+	}})
+	es.Add("web", &fixed{topics: []datasource.DataSourceTopic{
+		{TopicID: 4, SourceURL: "https://example.com/d"},
+	}})
+	es.MaxPerDomain = 1
+	if err := es.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	topics, err := es.FetchTopics(10, "anything")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts := map[string]int{}
+	for _, topic := range topics {
+		u, _ := url.Parse(topic.SourceURL)
+		hosts[u.Host]++
+	}
+	if len(topics) != 3 || hosts["go.dev"] != 1 {
+		t.Errorf("FetchTopics with MaxPerDomain 1 = %+v", topics)
+	}
+
+	// Topics are fetched from the member that returned them
+	if data, err := es.FetchData(1, topics[0].TopicID); err != nil || len(data) != 1 {
+		t.Errorf("FetchData(%d) = %+v, %v", topics[0].TopicID, data, err)
+	}
+}
@@ -0,0 +1,111 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Query classes used by DefaultRules
+const (
+	Code       = "code"
+	News       = "news"
+	Definition = "definition"
+	Factual    = "factual"
+	Local      = "local"
+	Academic   = "academic"
+)
+
+// Rule scores a query for one class. Each keyword found as whole words adds one point and each matching
+// pattern adds two; the class with the most points wins.
+type Rule struct {
+	Class    string   `json:"class"`
+	Keywords []string `json:"keywords,omitempty"` // Words or phrases, matched case-insensitively
+	Patterns []string `json:"patterns,omitempty"` // Regular expressions matched against the lowercased query
+
+	patterns []*regexp.Regexp
+}
+
+// DefaultRules classify English queries into the six built-in classes
+var DefaultRules = []Rule{
+	{
+		Class: Code,
+		Keywords: []string{"error", "exception", "stack trace", "compile", "compiler", "api", "sdk", "npm", "pip",
+			"cargo", "git", "regex", "segfault", "null pointer",
+			"golang", "python", "javascript", "typescript", "rust", "java", "c++", "sql", "bash", "docker", "kubernetes"},
+		Patterns: []string{"`", `\w\(\)`, `\w\.\w+\(`, `::`, `\b\w+\.(go|py|js|ts|rs|java|c|cpp|rb|php|sh|yaml|json)\b`, `\b0x[0-9a-f]+\b`},
+	},
+	{
+		Class:    News,
+		Keywords: []string{"news", "latest", "breaking", "today", "yesterday", "this week", "announced", "announces", "headlines", "election", "update on"},
+		Patterns: []string{`\bwhat happened\b`, `\b(this|last) (morning|night|week|month)\b`},
+	},
+	{
+		Class:    Definition,
+		Keywords: []string{"define", "definition", "meaning", "synonym", "synonyms", "antonym", "etymology", "pronounce", "pronunciation"},
+		Patterns: []string{`^what does \S+( \S+)? mean\b`, `^what is an? \S+\??$`, `^(define|meaning of)\b`},
+	},
+	{
+		Class:    Factual,
+		Keywords: []string{"population", "capital of", "how tall", "how old", "how far", "how many", "how much", "born", "founded", "invented"},
+		Patterns: []string{`^(who|when|where|which)\b`, `^(what year|how long)\b`},
+	},
+	{
+		Class:    Local,
+		Keywords: []string{"near me", "nearby", "open now", "directions", "opening hours", "weather", "forecast", "restaurant", "restaurants"},
+		Patterns: []string{`\b(in|around) my (area|city|town)\b`},
+	},
+	{
+		Class: Academic,
+		Keywords: []string{"paper", "papers", "study", "studies", "research", "journal", "doi", "arxiv", "preprint",
+			"meta-analysis", "systematic review", "clinical trial", "peer-reviewed", "citation", "literature"},
+		Patterns: []string{`\b10\.\d{4,9}/\S+`, `\b\d{4}\.\d{4,5}(v\d+)?\b`, `\bet al\b`},
+	},
+}
+
+// compile prepares the rule's patterns
+func (r *Rule) compile() error {
+	r.patterns = nil
+	for _, p := range r.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q for class %s: %w", p, r.Class, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return nil
+}
+
+// score returns the points the normalized query earns; padded is the query with spaces around it
+func (r *Rule) score(query, padded string) int {
+	score := 0
+	for _, k := range r.Keywords {
+		if strings.Contains(padded, " "+strings.ToLower(k)+" ") {
+			score++
+		}
+	}
+	for _, re := range r.patterns {
+		if re.MatchString(query) {
+			score += 2
+		}
+	}
+	return score
+}
+
+// normalize lowercases the query and pads punctuation with spaces so keywords match on word boundaries
+func normalize(input string) (query, padded string) {
+	query = strings.ToLower(strings.TrimSpace(input))
+	var b strings.Builder
+	b.WriteByte(' ')
+	for _, r := range query {
+		switch r {
+		case ',', '.', '?', '!', ';', ':', '"', '(', ')', '[', ']':
+			b.WriteByte(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte(' ')
+	padded = strings.Join(strings.Fields(b.String()), " ")
+	return query, " " + padded + " "
+}
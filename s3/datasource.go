@@ -0,0 +1,458 @@
+package s3
+
+// Data Source Adapter for documents in an S3 or S3-compatible (MinIO, R2) bucket: object keys are
+// matched against the query and matched objects are downloaded and extracted for data
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/auth"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/localfs"
+	"github.com/locus-search/datasource/pdftext"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultDataCount  = 3
+	// Object text is returned in chunks of about this many characters
+	chunkSize = 1500
+)
+
+// DefaultExtensions are the object types searched when Extensions is empty
+var DefaultExtensions = []string{".md", ".markdown", ".txt", ".html", ".htm", ".pdf"}
+
+type DataSourceS3 struct {
+	Client    *http.Client
+	Endpoint  string // Default "https://s3.<region>.amazonaws.com"; e.g. "http://localhost:9000" for MinIO
+	Region    string // Default "us-east-1"
+	Bucket    string
+	Prefix    string // Optional; only keys under it are listed, e.g. "docs/"
+	PathStyle bool   // Address the bucket in the path instead of the host name; MinIO needs this
+	UserAgent string
+
+	// Credentials; requests are sent unsigned when AccessKeyID is empty, for public buckets
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	Extensions    []string      // Searched object types, e.g. ".md"; DefaultExtensions when empty
+	MaxObjects    int           // Keys kept from the listing; default 10000
+	MaxObjectSize int64         // Larger objects are listed but not downloaded; default 10 MB
+	ManifestTTL   time.Duration // How long the key listing is cached; default 15 minutes
+	Site          string        // Site for topics and data; default "s3://<bucket>"
+
+	mu       sync.Mutex
+	manifest []object
+	listed   time.Time
+	objects  topicid.Map[object]
+}
+
+// object is one key from the bucket listing
+type object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	Words        []string // Lowercased words of the key
+}
+
+// listResult decodes a ListObjectsV2 response
+type listResult struct {
+	Contents []struct {
+		Key          string `xml:"Key"`
+		LastModified string `xml:"LastModified"`
+		Size         int64  `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func New() *DataSourceS3 {
+	return &DataSourceS3{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		Region:        "us-east-1",
+		UserAgent:     "locus/s3-datasource",
+		MaxObjects:    10000,
+		MaxObjectSize: 10 << 20,
+		ManifestTTL:   15 * time.Minute,
+	}
+}
+
+// Init implements models.DataSource
+func (es *DataSourceS3) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.Bucket == "" {
+		return errors.New("Bucket is required for S3 DataSource")
+	}
+	if es.AccessKeyID != "" && es.SecretAccessKey == "" {
+		return errors.New("SecretAccessKey is required with AccessKeyID for S3 DataSource")
+	}
+	if es.Region == "" {
+		es.Region = "us-east-1"
+	}
+	if es.Endpoint == "" {
+		es.Endpoint = "https://s3." + es.Region + ".amazonaws.com"
+	}
+	if _, err := url.Parse(es.Endpoint); err != nil {
+		return fmt.Errorf("invalid S3 Endpoint: %w", err)
+	}
+	if es.MaxObjects <= 0 {
+		es.MaxObjects = 10000
+	}
+	if es.MaxObjectSize <= 0 {
+		es.MaxObjectSize = 10 << 20
+	}
+	if es.ManifestTTL <= 0 {
+		es.ManifestTTL = 15 * time.Minute
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Lists a single key, which also verifies the credentials can read the bucket
+func (es *DataSourceS3) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.list(ctx, "", 1)
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceS3) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name: "S3",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Ranks object keys by how many query words appear in them, newest first among equals. Topics are
+// titled from the file name, e.g. "docs/setup-guide.md" gives "Setup guide".
+func (es *DataSourceS3) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for S3 DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	objects, err := es.load()
+	if err != nil {
+		return nil, err
+	}
+	terms := words(query)
+	type scored struct {
+		object
+		score int
+	}
+	var matches []scored
+	for _, o := range objects {
+		if score := matchCount(o.Words, terms); score > 0 {
+			matches = append(matches, scored{o, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].LastModified.After(matches[j].LastModified)
+	})
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, m := range matches {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     keyTitle(m.Key),
+			SourceURL: es.objectURL(m.Key).String(),
+			Site:      es.site(),
+			TopicID:   es.objects.Put(es.Bucket+"/"+m.Key, m.object),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Downloads the object and returns its text in chunks of about 1500 characters, each starting with the
+// title. Markdown front matter is dropped, HTML is reduced to its main text and PDFs are extracted page by
+// page.
+func (es *DataSourceS3) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	o, ok := es.objects.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown S3 topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultDataCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+	if o.Size > es.MaxObjectSize {
+		return nil, fmt.Errorf("s3 object %s is larger than %d bytes", o.Key, es.MaxObjectSize)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	resp, err := es.do(ctx, es.objectURL(o.Key))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, es.MaxObjectSize))
+	if err != nil {
+		return nil, err
+	}
+	title, texts, err := parse(ctx, o.Key, raw)
+	if err != nil {
+		return nil, fmt.Errorf("s3 object %s: %w", o.Key, err)
+	}
+
+	source := es.objectURL(o.Key).String()
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, chunk := range texts {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		if len(results) > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%s/%s#%d", es.Bucket, o.Key, len(results)))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  title + "\n" + chunk,
+			SourceURL: source,
+			Site:      es.site(),
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// LastModified implements source.Freshness
+func (es *DataSourceS3) LastModified(topicID int64) time.Time {
+	o, _ := es.objects.Get(topicID)
+	return o.LastModified
+}
+
+// load returns the cached key listing, listing the bucket again once it is older than ManifestTTL
+func (es *DataSourceS3) load() ([]object, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.manifest != nil && time.Since(es.listed) < es.ManifestTTL {
+		return es.manifest, nil
+	}
+
+	extensions := es.Extensions
+	if len(extensions) == 0 {
+		extensions = DefaultExtensions
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	objects := []object{}
+	token := ""
+	for len(objects) < es.MaxObjects {
+		page, err := es.list(ctx, token, 1000)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range page.Contents {
+			if strings.HasSuffix(c.Key, "/") || !hasExtension(c.Key, extensions) {
+				continue
+			}
+			o := object{Key: c.Key, Size: c.Size, Words: words(c.Key)}
+			o.LastModified, _ = time.Parse(time.RFC3339, c.LastModified)
+			objects = append(objects, o)
+			if len(objects) >= es.MaxObjects {
+				break
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+	es.manifest = objects
+	es.listed = time.Now()
+	return objects, nil
+}
+
+// list requests one page of ListObjectsV2 under Prefix
+func (es *DataSourceS3) list(ctx context.Context, token string, maxKeys int) (*listResult, error) {
+	u := es.bucketURL()
+	query := url.Values{}
+	query.Set("list-type", "2")
+	query.Set("max-keys", strconv.Itoa(maxKeys))
+	if es.Prefix != "" {
+		query.Set("prefix", es.Prefix)
+	}
+	if token != "" {
+		query.Set("continuation-token", token)
+	}
+	u.RawQuery = query.Encode()
+
+	resp, err := es.do(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var result listResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding s3 listing: %w", err)
+	}
+	return &result, nil
+}
+
+// do sends a GET, signed when credentials are set, and returns the response when it succeeded
+func (es *DataSourceS3) do(ctx context.Context, u *url.URL) (*http.Response, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	if es.AccessKeyID != "" {
+		signer := auth.SigV4{
+			AccessKeyID:     es.AccessKeyID,
+			SecretAccessKey: es.SecretAccessKey,
+			SessionToken:    es.SessionToken,
+			Region:          es.Region,
+			Service:         "s3",
+		}
+		if err := signer.Sign(req, auth.EmptyPayloadHash); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// bucketURL addresses the bucket as "<endpoint>/<bucket>/" in path style or "<bucket>.<host>/" otherwise
+func (es *DataSourceS3) bucketURL() *url.URL {
+	u, _ := url.Parse(strings.TrimRight(es.Endpoint, "/"))
+	if es.PathStyle {
+		u.Path += "/" + es.Bucket + "/"
+	} else {
+		u.Host = es.Bucket + "." + u.Host
+		u.Path += "/"
+	}
+	return u
+}
+
+func (es *DataSourceS3) objectURL(key string) *url.URL {
+	u := es.bucketURL()
+	u.Path += key
+	return u
+}
+
+func (es *DataSourceS3) site() string {
+	if es.Site != "" {
+		return es.Site
+	}
+	return "s3://" + es.Bucket
+}
+
+// Helpers
+
+func hasExtension(key string, extensions []string) bool {
+	ext := strings.ToLower(path.Ext(key))
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits text into lowercase letter and digit runs
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchCount counts the query terms found among words; a term also matches a word it prefixes
+func matchCount(words, terms []string) int {
+	count := 0
+	for _, term := range terms {
+		for _, w := range words {
+			if strings.HasPrefix(w, term) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// keyTitle turns the file name of a key into a title, e.g. "docs/setup-guide.md" gives "Setup guide"
+func keyTitle(key string) string {
+	base := path.Base(key)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	title := strings.Join(strings.FieldsFunc(base, func(r rune) bool { return r == '-' || r == '_' || r == '+' }), " ")
+	if title == "" {
+		return key
+	}
+	r := []rune(title)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// parse returns the title and text chunks of a downloaded file: PDFs are extracted page by page, other
+// types are parsed like local files
+func parse(ctx context.Context, name string, raw []byte) (string, []string, error) {
+	if strings.EqualFold(path.Ext(name), ".pdf") {
+		doc, err := pdftext.Default.Bytes(ctx, raw)
+		if err != nil {
+			return "", nil, err
+		}
+		title := doc.Title
+		if title == "" {
+			title = keyTitle(name)
+		}
+		return title, doc.Chunks(chunkSize), nil
+	}
+	doc := localfs.Parse(name, raw)
+	return doc.Title, localfs.Chunks(doc.Body, chunkSize), nil
+}
@@ -0,0 +1,142 @@
+package s3
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /docs-bucket/{$}", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("list-type") != "2" || q.Get("prefix") != "kb/" || q.Get("max-keys") != "1000" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			t.Errorf("unsigned listing: %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		switch q.Get("continuation-token") {
+		case "":
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+	<Contents><Key>kb/</Key><Size>0</Size></Contents>
+	<Contents><Key>kb/install-guide.md</Key><LastModified>2024-01-01T00:00:00.000Z</LastModified><Size>120</Size></Contents>
+	<Contents><Key>kb/install.png</Key><LastModified>2024-03-01T00:00:00.000Z</LastModified><Size>9000</Size></Contents>
+	<IsTruncated>true</IsTruncated>
+	<NextContinuationToken>page 2</NextContinuationToken>
+</ListBucketResult>`)
+		case "page 2":
+			fmt.Fprint(w, `<ListBucketResult>
+	<Contents><Key>kb/install_windows.txt</Key><LastModified>2024-02-01T00:00:00Z</LastModified><Size>50</Size></Contents>
+	<Contents><Key>kb/upgrade.md</Key><LastModified>2024-04-01T00:00:00Z</LastModified><Size>50</Size></Contents>
+	<IsTruncated>false</IsTruncated>
+</ListBucketResult>`)
+		default:
+			t.Errorf("unexpected continuation token %q", q.Get("continuation-token"))
+		}
+	})
+	mux.HandleFunc("GET /docs-bucket/kb/install-guide.md", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			t.Error("unsigned download")
+		}
+		fmt.Fprint(w, "---\ntitle: Installing\n---\n# Ignored heading\n\nDownload the release.\n\nRun the installer.\n")
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.Endpoint = srv.URL
+	es.PathStyle = true
+	es.Bucket = "docs-bucket"
+	es.Prefix = "kb/"
+	es.AccessKeyID = "AKID"
+	es.SecretAccessKey = "secret"
+
+	topics, err := es.FetchTopics(5, "install guide")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two matching words rank first; among single matches the newer object comes first
+	want := []string{"Install guide", "Install windows"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if topics[i].Topic != w || topics[i].Site != "s3://docs-bucket" {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, w)
+		}
+	}
+	if topics[0].SourceURL != srv.URL+"/docs-bucket/kb/install-guide.md" {
+		t.Errorf("SourceURL = %s", topics[0].SourceURL)
+	}
+	if got := es.LastModified(topics[0].TopicID); got.Year() != 2024 || got.Month() != 1 {
+		t.Errorf("LastModified = %v", got)
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "Installing\n# Ignored heading\n\nDownload the release.\n\nRun the installer."
+	if len(data) != 1 || data[0].DataText != wantData || data[0].AnswerID != topics[0].TopicID {
+		t.Errorf("FetchData = %+v, want %q", data, wantData)
+	}
+}
+
+func TestBucketURL(t *testing.T) {
+	es := New()
+	es.Bucket = "docs"
+	es.Region = "eu-west-1"
+	if err := es.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if got := es.objectURL("a/b.md").String(); got != "https://docs.s3.eu-west-1.amazonaws.com/a/b.md" {
+		t.Errorf("virtual-hosted URL = %s", got)
+	}
+	es.Endpoint = "http://localhost:9000/"
+	es.PathStyle = true
+	if got := es.objectURL("a/b.md").String(); got != "http://localhost:9000/docs/a/b.md" {
+		t.Errorf("path-style URL = %s", got)
+	}
+}
+
+func TestRequestFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `<Error><Code>AccessDenied</Code></Error>`)
+	}))
+	defer srv.Close()
+	es := New()
+	es.Endpoint = srv.URL
+	es.PathStyle = true
+	es.Bucket = "private"
+	if _, err := es.FetchTopics(5, "install"); err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("FetchTopics = %v", err)
+	}
+	if es.CheckAvailability() {
+		t.Error("CheckAvailability = true for a forbidden bucket")
+	}
+}
+
+func TestKeyTitle(t *testing.T) {
+	tests := map[string]string{
+		"docs/setup-guide.md":  "Setup guide",
+		"notes/c++_tips.txt":   "C tips",
+		"README":               "README",
+		"guides/über_alles.md": "Über alles",
+	}
+	for key, want := range tests {
+		if got := keyTitle(key); got != want {
+			t.Errorf("keyTitle(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+//go:build llm
+
+package safety
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAI classifies with OpenAI's moderations API. Its categories include Sexual, Hate and Violence,
+// plus finer ones such as "harassment" and "self-harm"; it has no Profanity category, so combine it
+// with DefaultWordlist through Any to screen for that too. Built with the "llm" build tag.
+type OpenAI struct {
+	Client   *http.Client
+	BaseURL  string // Default "https://api.openai.com/v1"
+	APIKey   string
+	Model    string // Default "omni-moderation-latest"
+	MaxInput int    // Text is cut to this many characters before sending; default 12000
+}
+
+func NewOpenAI(apiKey string) *OpenAI {
+	return &OpenAI{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:  "https://api.openai.com/v1",
+		APIKey:   apiKey,
+		Model:    "omni-moderation-latest",
+		MaxInput: 12000,
+	}
+}
+
+// Classify implements Classifier
+func (o *OpenAI) Classify(ctx context.Context, text string) (Verdict, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return Verdict{}, nil
+	}
+	if limit := o.MaxInput; limit > 0 && len(text) > limit {
+		text = strings.ToValidUTF8(text[:limit], "")
+	}
+	model := o.Model
+	if model == "" {
+		model = "omni-moderation-latest"
+	}
+	body, err := json.Marshal(struct {
+		Model string `json:"model"`
+		Input string `json:"input"`
+	}{Model: model, Input: text})
+	if err != nil {
+		return nil, err
+	}
+
+	base := o.BaseURL
+	if base == "" {
+		base = "https://api.openai.com/v1"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(base, "/")+"/moderations", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+	client := o.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("moderation request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	var response struct {
+		Results []struct {
+			CategoryScores map[string]float64 `json:"category_scores"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	if len(response.Results) == 0 {
+		return nil, errors.New("moderation response has no results")
+	}
+	return Verdict(response.Results[0].CategoryScores), nil
+}
@@ -0,0 +1,67 @@
+//go:build llm
+
+package safety
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/moderations" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s with %q", r.URL, r.Header.Get("Authorization"))
+		}
+		var request struct {
+			Model string `json:"model"`
+			Input string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+		if request.Model != "omni-moderation-latest" || request.Input != "some text" {
+			t.Errorf("unexpected request %+v", request)
+		}
+		fmt.Fprint(w, `{"id":"modr-1","results":[{"flagged":true,
+			"categories":{"violence":true},
+			"category_scores":{"sexual":0.001,"hate":0.02,"violence":0.91,"self-harm":0.0004}}]}`)
+	}))
+	defer srv.Close()
+
+	o := NewOpenAI("key")
+	o.BaseURL = srv.URL + "/v1"
+	verdict, err := o.Classify(context.Background(), "  some text\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verdict[Violence] != 0.91 || verdict[Hate] != 0.02 || verdict["self-harm"] != 0.0004 {
+		t.Errorf("Classify = %v", verdict)
+	}
+	if verdict, err := o.Classify(context.Background(), " "); len(verdict) != 0 || err != nil {
+		t.Errorf("Classify of blank text = %v, %v", verdict, err)
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	for status, body := range map[int]string{
+		http.StatusOK:              `{"results":[]}`,
+		http.StatusTooManyRequests: `{"error":{"message":"Rate limit reached"}}`,
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			fmt.Fprint(w, body)
+		}))
+		o := NewOpenAI("key")
+		o.BaseURL = srv.URL
+		if _, err := o.Classify(context.Background(), "text"); err == nil ||
+			(status != http.StatusOK && !strings.Contains(err.Error(), "Rate limit reached")) {
+			t.Errorf("Classify with status %d = %v", status, err)
+		}
+		srv.Close()
+	}
+}
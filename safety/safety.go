@@ -0,0 +1,262 @@
+// Package safety screens topics and data for explicit or offensive content.
+// A Classifier scores text by category, from a built-in wordlist or an
+// external moderation service; Filter wraps any source and flags or drops the
+// results a Policy rejects, so user-generated sources such as Reddit, Urban
+// Dictionary or image search can be served to audiences that need it.
+package safety
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+// Categories scored by the built-in wordlist; external classifiers may return others
+const (
+	Profanity = "profanity"
+	Sexual    = "sexual"
+	Hate      = "hate"
+	Violence  = "violence"
+)
+
+// Verdict holds a classifier's scores by category, from 0 (clean) to 1 (certainly in the category)
+type Verdict map[string]float64
+
+// Classifier scores text
+type Classifier interface {
+	Classify(ctx context.Context, text string) (Verdict, error)
+}
+
+// ClassifierFunc adapts a function to Classifier
+type ClassifierFunc func(ctx context.Context, text string) (Verdict, error)
+
+func (f ClassifierFunc) Classify(ctx context.Context, text string) (Verdict, error) {
+	return f(ctx, text)
+}
+
+// Any runs every classifier and keeps the highest score for each category, e.g. the wordlist as a
+// baseline plus an external service
+func Any(classifiers ...Classifier) Classifier {
+	return ClassifierFunc(func(ctx context.Context, text string) (Verdict, error) {
+		merged := Verdict{}
+		for _, c := range classifiers {
+			v, err := c.Classify(ctx, text)
+			if err != nil {
+				return nil, err
+			}
+			for category, score := range v {
+				merged[category] = max(merged[category], score)
+			}
+		}
+		return merged, nil
+	})
+}
+
+// Policy says which verdicts fail
+type Policy struct {
+	Categories []string // Categories screened; empty for all
+	Threshold  float64  // Lowest failing score; default 0.5
+}
+
+// Fails returns the categories of v that fail the policy, sorted; nil when v passes
+func (p Policy) Fails(v Verdict) []string {
+	threshold := p.Threshold
+	if threshold <= 0 {
+		threshold = 0.5
+	}
+	var failed []string
+	for category, score := range v {
+		if score < threshold {
+			continue
+		}
+		if len(p.Categories) > 0 && !contains(p.Categories, category) {
+			continue
+		}
+		failed = append(failed, category)
+	}
+	sort.Strings(failed)
+	return failed
+}
+
+// NSFWCapable is implemented by adapters whose content can be explicit or offensive, such as Urban
+// Dictionary or Reddit
+type NSFWCapable interface {
+	NSFWCapable() bool
+}
+
+// Capable reports whether s, or a source it wraps, says it can return explicit or offensive content
+func Capable(s source.Source) bool {
+	for s != nil {
+		if c, ok := s.(NSFWCapable); ok {
+			return c.NSFWCapable()
+		}
+		w, ok := s.(source.Wrapper)
+		if !ok {
+			break
+		}
+		s = w.Unwrap()
+	}
+	return false
+}
+
+// Action is what a Filter does with results that fail its policy
+type Action int
+
+const (
+	Drop Action = iota // Remove them
+	Flag               // Keep them with Label prepended to the title or text
+)
+
+// Filter wraps a source and screens topic titles and data text with a Classifier. It implements
+// source.Source.
+type Filter struct {
+	Source     source.Source
+	Classifier Classifier // Default DefaultWordlist
+	Policy     Policy
+	Action     Action
+	Label      string // Prepended to flagged titles and text; default "[NSFW] "
+	// CapableOnly screens only sources that report NSFWCapable and passes the rest through, so every
+	// member of a composite can be wrapped the same way without classifying results that need none
+	CapableOnly bool
+	// FailOpen keeps results the classifier could not score; by default they fail, so an outage of an
+	// external classifier does not let unscreened content through
+	FailOpen bool
+	Timeout  time.Duration // Deadline for classifying one response; default timeout.Topics or timeout.Data
+}
+
+// New wraps s with the wordlist classifier, dropping anything it flags
+func New(s source.Source) *Filter {
+	return &Filter{Source: s, Classifier: DefaultWordlist, Policy: Policy{Threshold: 0.5}}
+}
+
+// Init implements models.DataSource
+func (f *Filter) Init() error {
+	return f.Source.Init()
+}
+
+// CheckAvailability implements models.DataSource
+func (f *Filter) CheckAvailability() bool {
+	return f.Source.CheckAvailability()
+}
+
+// Unwrap implements source.Wrapper
+func (f *Filter) Unwrap() source.Source {
+	return f.Source
+}
+
+// FetchTopics implements models.DataSource
+// Screens topic titles
+func (f *Filter) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	topics, err := f.Source.FetchTopics(count, input)
+	if err != nil || !f.screens() {
+		return topics, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout(timeout.Topics))
+	defer cancel()
+	kept := make([]datasource.DataSourceTopic, 0, len(topics))
+	for _, t := range topics {
+		if !f.fails(ctx, t.Topic) {
+			kept = append(kept, t)
+			continue
+		}
+		if f.Action == Flag {
+			t.Topic = f.label() + t.Topic
+			kept = append(kept, t)
+		}
+	}
+	return kept, nil
+}
+
+// FetchData implements models.DataSource
+// Screens data text
+func (f *Filter) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	data, err := f.Source.FetchData(count, topicID)
+	if err != nil || !f.screens() {
+		return data, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), f.timeout(timeout.Data))
+	defer cancel()
+	kept := make([]datasource.DataSourceData, 0, len(data))
+	for _, d := range data {
+		if !f.fails(ctx, d.DataText) {
+			kept = append(kept, d)
+			continue
+		}
+		if f.Action == Flag {
+			d.DataText = f.label() + d.DataText
+			kept = append(kept, d)
+		}
+	}
+	return kept, nil
+}
+
+// Check returns the categories of text that fail the filter's policy
+func (f *Filter) Check(ctx context.Context, text string) ([]string, error) {
+	classifier := f.Classifier
+	if classifier == nil {
+		classifier = DefaultWordlist
+	}
+	v, err := classifier.Classify(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return f.Policy.Fails(v), nil
+}
+
+func (f *Filter) fails(ctx context.Context, text string) bool {
+	failed, err := f.Check(ctx, text)
+	if err != nil {
+		return !f.FailOpen
+	}
+	return len(failed) > 0
+}
+
+func (f *Filter) screens() bool {
+	return !f.CapableOnly || Capable(f.Source)
+}
+
+func (f *Filter) label() string {
+	if f.Label == "" {
+		return "[NSFW] "
+	}
+	return f.Label
+}
+
+func (f *Filter) timeout(def time.Duration) time.Duration {
+	if f.Timeout > 0 {
+		return f.Timeout
+	}
+	return def
+}
+
+// Helpers
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// words lowercases text and splits it into words, undoing common character substitutions such as
+// "sh1t" or "@ss" within words that contain letters
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '@' || r == '$' || r > 127)
+	})
+	for i, w := range fields {
+		if strings.IndexFunc(w, func(r rune) bool { return r >= 'a' && r <= 'z' }) >= 0 {
+			fields[i] = leet.Replace(w)
+		}
+	}
+	return fields
+}
+
+var leet = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
@@ -0,0 +1,194 @@
+package safety
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+func TestWordlist(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Holy SH1T, that worked", []string{Profanity}},
+		{"what an @sshole", []string{Profanity}},
+		{"Piss... off!", []string{Profanity}},
+		{"leaked sex tape", []string{Sexual}},
+		{"Fucking porn", []string{Profanity, Sexual}},
+		{"Scunthorpe United", nil},
+		{"Essex tapestry", nil},
+		{"Released in 1984", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		v, err := DefaultWordlist.Classify(context.Background(), tt.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(v) != len(DefaultWordlist) {
+			t.Errorf("Classify(%q) = %v, want a score for every category", tt.text, v)
+		}
+		if got := (Policy{}).Fails(v); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Classify(%q) fails %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyFails(t *testing.T) {
+	v := Verdict{Sexual: 0.5, Hate: 0.3, Violence: 0.9}
+	tests := []struct {
+		policy Policy
+		want   []string
+	}{
+		{Policy{}, []string{Sexual, Violence}},
+		{Policy{Threshold: 0.95}, nil},
+		{Policy{Threshold: 0.2}, []string{Hate, Sexual, Violence}},
+		{Policy{Categories: []string{Hate, Violence}}, []string{Violence}},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Fails(v); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v.Fails = %q, want %q", tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestAny(t *testing.T) {
+	external := ClassifierFunc(func(ctx context.Context, text string) (Verdict, error) {
+		return Verdict{Profanity: 0.2, Hate: 0.7}, nil
+	})
+	v, err := Any(DefaultWordlist, external).Classify(context.Background(), "bullshit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Verdict{Profanity: 1, Sexual: 0, Hate: 0.7}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("Any = %v, want %v", v, want)
+	}
+
+	failing := ClassifierFunc(func(ctx context.Context, text string) (Verdict, error) {
+		return nil, errors.New("unavailable")
+	})
+	if _, err := Any(DefaultWordlist, failing).Classify(context.Background(), "text"); err == nil {
+		t.Error("Any did not return the classifier's error")
+	}
+}
+
+// fixed returns the same topics and data for any query
+type fixed struct {
+	topics []datasource.DataSourceTopic
+	data   []datasource.DataSourceData
+	nsfw   bool
+}
+
+func (f *fixed) Init() error             { return nil }
+func (f *fixed) CheckAvailability() bool { return true }
+func (f *fixed) NSFWCapable() bool       { return f.nsfw }
+
+func (f *fixed) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return append([]datasource.DataSourceTopic(nil), f.topics...), nil
+}
+
+func (f *fixed) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return append([]datasource.DataSourceData(nil), f.data...), nil
+}
+
+func newFixed(nsfw bool) *fixed {
+	return &fixed{
+		topics: []datasource.DataSourceTopic{{TopicID: 1, Topic: "Go"}, {TopicID: 2, Topic: "Porn sites"}},
+		data:   []datasource.DataSourceData{{AnswerID: 1, DataText: "What the fuck"}, {AnswerID: 2, DataText: "A language"}},
+		nsfw:   nsfw,
+	}
+}
+
+func titles(topics []datasource.DataSourceTopic) []string {
+	var out []string
+	for _, t := range topics {
+		out = append(out, t.Topic)
+	}
+	return out
+}
+
+func texts(data []datasource.DataSourceData) []string {
+	var out []string
+	for _, d := range data {
+		out = append(out, d.DataText)
+	}
+	return out
+}
+
+func TestFilterDrop(t *testing.T) {
+	f := New(newFixed(false))
+	topics, err := f.FetchTopics(10, "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := titles(topics), []string{"Go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("topics = %q, want %q", got, want)
+	}
+	data, err := f.FetchData(10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := texts(data), []string{"A language"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("data = %q, want %q", got, want)
+	}
+}
+
+func TestFilterFlag(t *testing.T) {
+	f := &Filter{Source: newFixed(false), Action: Flag, Label: "(18+) "}
+	topics, err := f.FetchTopics(10, "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := titles(topics), []string{"Go", "(18+) Porn sites"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("topics = %q, want %q", got, want)
+	}
+	data, err := f.FetchData(10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := texts(data), []string{"(18+) What the fuck", "A language"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("data = %q, want %q", got, want)
+	}
+}
+
+func TestFilterCapableOnly(t *testing.T) {
+	for _, tt := range []struct {
+		nsfw bool
+		want int
+	}{{false, 2}, {true, 1}} {
+		f := New(newFixed(tt.nsfw))
+		f.CapableOnly = true
+		topics, err := f.FetchTopics(10, "go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(topics) != tt.want {
+			t.Errorf("NSFWCapable %v kept %q, want %d topics", tt.nsfw, titles(topics), tt.want)
+		}
+	}
+}
+
+func TestFilterClassifierError(t *testing.T) {
+	failing := ClassifierFunc(func(ctx context.Context, text string) (Verdict, error) {
+		return nil, errors.New("unavailable")
+	})
+	for _, failOpen := range []bool{false, true} {
+		f := &Filter{Source: newFixed(false), Classifier: failing, FailOpen: failOpen}
+		topics, err := f.FetchTopics(10, "go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := 0
+		if failOpen {
+			want = 2
+		}
+		if len(topics) != want {
+			t.Errorf("FailOpen %v kept %q, want %d topics", failOpen, titles(topics), want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package safety
+
+import (
+	"context"
+	"strings"
+)
+
+// Wordlist is a Classifier that scores a category 1 when the text contains one of its words or
+// phrases, ignoring case, punctuation and common character substitutions, and 0 otherwise. It is a
+// cheap baseline that misses context; pair it with an external classifier through Any when that
+// matters.
+type Wordlist map[string][]string
+
+// DefaultWordlist holds common English profanity and sexual terms. Extend or replace it for other
+// languages and audiences.
+var DefaultWordlist = Wordlist{
+	Profanity: {
+		"arsehole", "asshole", "bastard", "bitch", "bollocks", "bullshit", "cunt", "dickhead", "fuck",
+		"fucked", "fucker", "fucking", "motherfucker", "piss off", "prick", "shit", "shitty", "twat",
+		"wanker",
+	},
+	Sexual: {
+		"blowjob", "cumshot", "dildo", "erotica", "handjob", "hentai", "milf", "nsfw", "nude", "nudes",
+		"onlyfans", "orgasm", "porn", "porno", "pornography", "sex tape", "slut", "whore", "xxx",
+	},
+}
+
+// Classify implements Classifier
+func (w Wordlist) Classify(ctx context.Context, text string) (Verdict, error) {
+	// Pad with spaces so phrases match on word boundaries
+	normalized := " " + strings.Join(words(text), " ") + " "
+	v := Verdict{}
+	for category, terms := range w {
+		v[category] = 0
+		for _, term := range terms {
+			if strings.Contains(normalized, " "+strings.Join(words(term), " ")+" ") {
+				v[category] = 1
+				break
+			}
+		}
+	}
+	return v, nil
+}
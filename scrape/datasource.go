@@ -0,0 +1,486 @@
+package scrape
+
+// Data Source Adapter for HTML search pages, driven by a declarative config of CSS selectors
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/extract"
+	"github.com/locus-search/datasource/internal/readability"
+	"github.com/locus-search/datasource/internal/robots"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultDataCount  = 3
+	defaultMaxPages   = 3
+	// Paragraphs are grouped into data items of about this many characters
+	chunkSize = 1500
+)
+
+// PlaceholderQuery is replaced with the query-escaped search input in SearchURL
+const PlaceholderQuery = "{query}"
+
+// Config describes a scraped site. Selectors are CSS (goquery/cascadia syntax); XPath is not supported.
+// "${VAR}" references in header values are expanded from the environment by LoadConfig.
+type Config struct {
+	SearchURL string            `json:"search_url"` // e.g. "https://example.com/search?q={query}"
+	Headers   map[string]string `json:"headers"`
+	Site      string            `json:"site"` // Fixed Site for topics and data; defaults to the search host
+
+	// Item selects each search result; the remaining selectors are relative to one item. An empty
+	// Title uses the item's text and an empty Link uses the item itself, for results that are links.
+	Item     string `json:"item"`
+	Title    string `json:"title"`
+	Link     string `json:"link"`
+	LinkAttr string `json:"link_attr"` // Attribute holding the result URL; default "href"
+	Snippet  string `json:"snippet"`
+
+	NextPage string `json:"next_page"` // Selects the next-page link; empty for a single page
+	MaxPages int    `json:"max_pages"` // Default 3
+
+	// Content selects the text of a result page for FetchData; empty extracts the main text with
+	// readability heuristics
+	Content string `json:"content"`
+
+	RequestsPerSecond float64 `json:"requests_per_second"` // Default 1; robots.txt Crawl-delay can lower it
+	IgnoreRobots      bool    `json:"ignore_robots"`       // Only for sites you operate or have permission to scrape
+}
+
+type DataSourceScrape struct {
+	Config
+	Client    *http.Client
+	UserAgent string
+	Extractor *extract.Extractor // Used when Content is empty; default extract.Default
+	// Archive, when set, serves pages found behind a paywall or consent wall, e.g. wayback.New()
+	Archive source.Archive
+
+	rateLimiter *rate.Limiter
+	robots      *robots.Checker
+	results     topicid.Map[result]
+}
+
+type result struct {
+	Title   string
+	URL     string
+	Snippet string
+	Wall    string // Set by FetchData when the page was walled
+}
+
+func New() *DataSourceScrape {
+	return &DataSourceScrape{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		UserAgent: "locus/scrape-datasource",
+	}
+}
+
+// LoadConfig returns an adapter configured from a JSON file holding a Config
+func LoadConfig(path string) (*DataSourceScrape, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	es := New()
+	if err := json.Unmarshal(raw, &es.Config); err != nil {
+		return nil, fmt.Errorf("scrape config %s: %w", path, err)
+	}
+	for key, value := range es.Headers {
+		es.Headers[key] = os.ExpandEnv(value)
+	}
+	return es, es.Init()
+}
+
+// Init implements models.DataSource
+// Validates the configuration and its selectors
+func (es *DataSourceScrape) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if !strings.Contains(es.SearchURL, PlaceholderQuery) {
+		return fmt.Errorf("SearchURL must contain %s", PlaceholderQuery)
+	}
+	if _, err := url.Parse(strings.ReplaceAll(es.SearchURL, PlaceholderQuery, "")); err != nil {
+		return err
+	}
+	if es.Item == "" {
+		return errors.New("Item selector is required for Scrape DataSource")
+	}
+	for _, selector := range []string{es.Item, es.Title, es.Link, es.Snippet, es.NextPage, es.Content} {
+		if err := validSelector(selector); err != nil {
+			return err
+		}
+	}
+	if es.LinkAttr == "" {
+		es.LinkAttr = "href"
+	}
+	if es.rateLimiter == nil {
+		perSecond := es.RequestsPerSecond
+		if perSecond <= 0 {
+			perSecond = 1
+		}
+		es.rateLimiter = rate.NewLimiter(rate.Limit(perSecond), 1)
+	}
+	if es.robots == nil {
+		es.robots = robots.NewChecker(es.Client, es.UserAgent)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceScrape) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.fetch(ctx, es.searchURL("test"))
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceScrape) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name: es.site(),
+	}
+}
+
+// Describe implements source.Describer
+// RateLimit is the configured pace; robots.txt Crawl-delay can lower it per site
+func (es *DataSourceScrape) Describe() source.Capabilities {
+	perSecond := es.RequestsPerSecond
+	if perSecond <= 0 {
+		perSecond = 1
+	}
+	return source.Capabilities{Name: es.Info().Name, Data: true, RateLimit: source.RateLimit{Requests: 1, Per: time.Duration(float64(time.Second) / perSecond)}}
+}
+
+// FetchTopics implements models.DataSource
+// Scrapes result items from the search page, following NextPage links until count results are found
+// or MaxPages pages have been read
+func (es *DataSourceScrape) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Scrape DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	maxPages := es.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+	if es.NextPage == "" {
+		maxPages = 1
+	}
+
+	seen := map[string]bool{}
+	results := make([]datasource.DataSourceTopic, 0, count)
+	pageURL := es.searchURL(query)
+	for page := 0; page < maxPages && pageURL != "" && len(results) < count; page++ {
+		doc, err := es.fetch(ctx, pageURL)
+		if err != nil {
+			// Later pages are best effort; keep what the earlier ones returned
+			if page > 0 {
+				break
+			}
+			return nil, err
+		}
+		base := doc.Url
+		doc.Find(es.Item).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+			if len(results) >= count {
+				return false
+			}
+			r := es.parseItem(s, base)
+			if r.Title == "" || r.URL == "" || seen[r.URL] {
+				return true
+			}
+			seen[r.URL] = true
+			results = append(results, datasource.DataSourceTopic{
+				Topic:     r.Title,
+				SourceURL: r.URL,
+				Site:      es.site(),
+				TopicID:   es.results.Put(r.URL, r),
+			})
+			return true
+		})
+
+		pageURL = ""
+		if es.NextPage != "" {
+			if href, ok := doc.Find(es.NextPage).First().Attr("href"); ok {
+				pageURL = resolve(base, href)
+			}
+		}
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Fetches the result page and returns its text in items of about 1500 characters, each starting with
+// the title. Pages robots.txt disallows, or that cannot be fetched, yield the search snippet instead.
+// Pages behind a paywall or consent wall are read from Archive when it has a copy.
+func (es *DataSourceScrape) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	r, ok := es.results.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Scrape topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultDataCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var paragraphs []string
+	var wall string
+	doc, err := es.fetch(ctx, r.URL)
+	switch {
+	case err != nil && r.Snippet == "":
+		return nil, err
+	case err != nil:
+	case es.Content != "":
+		paragraphs = readability.Paragraphs(doc.Find(es.Content))
+	default:
+		extractor := es.Extractor
+		if extractor == nil {
+			extractor = extract.Default
+		}
+		// Pages with little article text are replaced by the snippet when there is one
+		article, err := extractor.Document(doc, r.URL)
+		if err == nil || r.Snippet == "" {
+			paragraphs = article.Paragraphs
+		}
+		wall = article.Wall
+	}
+	// Walled pages are read from the archive when it has a copy; otherwise the teaser is returned
+	var archived []datasource.DataSourceData
+	if wall != "" {
+		if archived = es.archived(ctx, r.URL, r.Title, count); archived != nil {
+			wall = ""
+		}
+	}
+	if r.Wall != wall {
+		r.Wall = wall
+		es.results.Set(topicID, r)
+	}
+	if archived != nil {
+		return archived, nil
+	}
+	if len(paragraphs) == 0 && r.Snippet != "" {
+		paragraphs = []string{r.Snippet}
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, chunk := range chunks(paragraphs, chunkSize) {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		if len(results) > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%s#%d", r.URL, len(results)))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  r.Title + "\n" + chunk,
+			SourceURL: r.URL,
+			Site:      es.site(),
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// Wall implements source.Walled
+func (es *DataSourceScrape) Wall(topicID int64) string {
+	r, _ := es.results.Get(topicID)
+	return r.Wall
+}
+
+// archived returns up to count items from Archive's copy of a walled page, each starting with the
+// title; nil when there is no Archive or it has no copy
+func (es *DataSourceScrape) archived(ctx context.Context, pageURL, title string, count int) []datasource.DataSourceData {
+	if es.Archive == nil {
+		return nil
+	}
+	data, err := es.Archive.FetchArchived(ctx, pageURL)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	if len(data) > count {
+		data = data[:count]
+	}
+	for i := range data {
+		data[i].DataText = title + "\n" + data[i].DataText
+	}
+	return data
+}
+
+// parseItem reads a result's title, link and snippet with the configured selectors
+func (es *DataSourceScrape) parseItem(s *goquery.Selection, base *url.URL) result {
+	titleSel, linkSel := s, s
+	if es.Title != "" {
+		titleSel = s.Find(es.Title).First()
+	}
+	if es.Link != "" {
+		linkSel = s.Find(es.Link).First()
+	}
+	r := result{Title: clean(titleSel.Text())}
+	if href, ok := linkSel.Attr(es.LinkAttr); ok {
+		r.URL = resolve(base, href)
+	}
+	if es.Snippet != "" {
+		r.Snippet = clean(s.Find(es.Snippet).First().Text())
+	}
+	return r
+}
+
+// fetch checks robots.txt, waits for the rate limiter and parses the page at uri
+func (es *DataSourceScrape) fetch(ctx context.Context, uri string) (*goquery.Document, error) {
+	if !es.IgnoreRobots && es.robots != nil {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, err
+		}
+		rules, err := es.robots.Rules(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		if !rules.Allowed(u.RequestURI()) {
+			return nil, fmt.Errorf("robots.txt disallows %s", uri)
+		}
+		if rules.CrawlDelay > 0 && rate.Every(rules.CrawlDelay) < es.rateLimiter.Limit() {
+			es.rateLimiter.SetLimit(rate.Every(rules.CrawlDelay))
+		}
+	}
+	if es.rateLimiter != nil {
+		if err := es.rateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("Accept-Language", "en")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	for key, value := range es.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("scrape request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// Relative links resolve against the final URL after redirects
+	doc.Url = resp.Request.URL
+	return doc, nil
+}
+
+func (es *DataSourceScrape) searchURL(query string) string {
+	return strings.ReplaceAll(es.SearchURL, PlaceholderQuery, url.QueryEscape(query))
+}
+
+func (es *DataSourceScrape) site() string {
+	if es.Site != "" {
+		return es.Site
+	}
+	if u, err := url.Parse(es.searchURL("")); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
+
+// Helpers
+
+// validSelector reports a malformed CSS selector, which goquery would otherwise treat as matching nothing
+func validSelector(selector string) error {
+	if selector == "" {
+		return nil
+	}
+	if _, err := cascadia.Compile(selector); err != nil {
+		return fmt.Errorf("invalid CSS selector %q: %w", selector, err)
+	}
+	return nil
+}
+
+func resolve(base *url.URL, href string) string {
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || href == "" || strings.HasPrefix(href, "#") {
+		return ""
+	}
+	if base != nil {
+		ref = base.ResolveReference(ref)
+	}
+	if ref.Scheme != "http" && ref.Scheme != "https" {
+		return ""
+	}
+	return ref.String()
+}
+
+// chunks joins paragraphs into blocks of roughly size characters without splitting a paragraph
+func chunks(paragraphs []string, size int) []string {
+	var out []string
+	var current strings.Builder
+	for _, p := range paragraphs {
+		if current.Len() > 0 && current.Len()+len(p) > size {
+			out = append(out, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(p)
+	}
+	if current.Len() > 0 {
+		out = append(out, current.String())
+	}
+	return out
+}
+
+// clean collapses runs of whitespace into single spaces
+func clean(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,120 @@
+package scrape
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cookie") != "session=1" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			if r.URL.Query().Get("q") != "tide pools" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `<ul>
+				<li class="result"><a href="/articles/1">Tide  pool
+					life</a><p class="snippet">Anemones and crabs.</p></li>
+				<li class="result"><a href="#top">Back to top</a></li>
+				<li class="result"><a href="/private/2">Members only</a><p class="snippet">A teaser for members.</p></li>
+			</ul><a class="next" href="?q=tide+pools&page=2">Next</a>`)
+		case "2":
+			fmt.Fprint(w, `<ul>
+				<li class="result"><a href="/articles/1">Tide pool life</a></li>
+				<li class="result"><a href="https://other.example/3">Elsewhere</a></li>
+				<li class="result"><a href="/articles/4">Not reached</a></li>
+			</ul>`)
+		default:
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+	})
+	mux.HandleFunc("/articles/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><nav><p>Home · About · Contact us today</p></nav>
+			<div class="story"><h2>Rock pools</h2><p>Tide pools form where the sea leaves water behind at low tide.</p>
+			<p>short</p></div></body></html>`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.SearchURL = srv.URL + "/search?q={query}"
+	es.Headers = map[string]string{"Cookie": "session=1"}
+	es.Item = "li.result"
+	es.Link = "a"
+	es.Title = "a"
+	es.Snippet = ".snippet"
+	es.NextPage = "a.next"
+	es.Content = ".story"
+	es.RequestsPerSecond = 1000
+
+	topics, err := es.FetchTopics(3, "tide pools")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Tide pool life|" + srv.URL + "/articles/1",
+		"Members only|" + srv.URL + "/private/2",
+		"Elsewhere|https://other.example/3",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].SourceURL; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+	if host := strings.TrimPrefix(srv.URL, "http://"); topics[0].Site != strings.Split(host, ":")[0] {
+		t.Errorf("Site = %q", topics[0].Site)
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "Tide pool life\nRock pools\n\nTide pools form where the sea leaves water behind at low tide."
+	if len(data) != 1 || data[0].DataText != wantData {
+		t.Errorf("FetchData = %+v, want %q", data, wantData)
+	}
+
+	// robots.txt disallows the page, so the snippet stands in for it
+	data, err = es.FetchData(3, topics[1].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].DataText != "Members only\nA teaser for members." {
+		t.Errorf("FetchData for a disallowed page = %+v", data)
+	}
+}
+
+func TestInitRejectsBadSelector(t *testing.T) {
+	es := New()
+	es.SearchURL = "https://example.com/search?q={query}"
+	es.Item = "li[class="
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted a malformed selector")
+	}
+}
+
+func TestChunks(t *testing.T) {
+	got := chunks([]string{"aaaa", "bbbb", "cccccccc", "ddd"}, 10)
+	want := []string{"aaaa\n\nbbbb", "cccccccc", "ddd"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("chunks = %q, want %q", got, want)
+	}
+}
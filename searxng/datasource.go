@@ -0,0 +1,219 @@
+package searxng
+
+// Data Source Adapter for a self-hosted SearXNG metasearch instance
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceSearXNG struct {
+	Client     *http.Client
+	BaseURL    string // Root of the SearXNG instance, e.g. https://searx.example.org
+	UserAgent  string
+	Engines    []string // Restrict searches to these engines (e.g. "google", "bing") when set
+	Categories []string // Restrict searches to these categories (e.g. "general", "it") when set
+	Language   string   // Optional language code such as "en" or "de-DE"
+
+	results topicid.Map[result]
+}
+
+// result is the part of a SearXNG search result retained for FetchData
+type result struct {
+	URL     string
+	Content string
+	Engine  string
+}
+
+func New() *DataSourceSearXNG {
+	return &DataSourceSearXNG{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "http://localhost:8080",
+		UserAgent: "locus/searxng-datasource",
+	}
+}
+
+// Init implements models.DataSource
+// SearXNG only needs a reachable instance URL
+func (es *DataSourceSearXNG) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if strings.TrimSpace(es.BaseURL) == "" {
+		return errors.New("BaseURL is required for SearXNG DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Reads the instance configuration, which is served even when the JSON search format is disabled
+func (es *DataSourceSearXNG) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	return es.doJSON(ctx, "/config", nil, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceSearXNG) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "SearXNG",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each search result becomes a topic; the engine that produced it is reported as the Site
+func (es *DataSourceSearXNG) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for SearXNG DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+	if len(es.Engines) > 0 {
+		params.Set("engines", strings.Join(es.Engines, ","))
+	}
+	if len(es.Categories) > 0 {
+		params.Set("categories", strings.Join(es.Categories, ","))
+	}
+	if es.Language != "" {
+		params.Set("language", es.Language)
+	}
+
+	var response struct {
+		Results []struct {
+			URL     string   `json:"url"`
+			Title   string   `json:"title"`
+			Content string   `json:"content"`
+			Engine  string   `json:"engine"`
+			Engines []string `json:"engines"`
+		} `json:"results"`
+	}
+	if err := es.doJSON(ctx, "/search", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	seen := map[string]struct{}{}
+	for _, item := range response.Results {
+		if len(results) >= count {
+			break
+		}
+		link := strings.TrimSpace(item.URL)
+		title := normalizeWhitespace(item.Title)
+		if link == "" || title == "" {
+			continue
+		}
+		if _, ok := seen[link]; ok {
+			continue
+		}
+		seen[link] = struct{}{}
+
+		engine := item.Engine
+		if engine == "" && len(item.Engines) > 0 {
+			engine = item.Engines[0]
+		}
+		id := es.results.Put(link, result{
+			URL:     link,
+			Content: normalizeWhitespace(item.Content),
+			Engine:  engine,
+		})
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: link,
+			Site:      engine,
+			TopicID:   id,
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// SearXNG only returns snippets, so the data is the snippet captured by FetchTopics
+func (es *DataSourceSearXNG) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	item, ok := es.results.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown SearXNG topicID %d", topicID)
+	}
+	if item.Content == "" {
+		return []datasource.DataSourceData{}, nil
+	}
+	return []datasource.DataSourceData{{
+		DataText:  item.Content,
+		SourceURL: item.URL,
+		Site:      item.Engine,
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// doJSON performs an HTTP GET request against the SearXNG instance and decodes the JSON response into target
+func (es *DataSourceSearXNG) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Instances answer 403 when the json output format is not enabled in settings.yml
+	if resp.StatusCode == http.StatusForbidden {
+		return errors.New("searxng request forbidden: enable the json format under search.formats")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("searxng request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func normalizeWhitespace(in string) string {
+	return strings.Join(strings.Fields(in), " ")
+}
@@ -0,0 +1,72 @@
+package searxng
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchTopics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/search" || q.Get("q") != "golang" || q.Get("format") != "json" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if q.Get("engines") != "google,bing" || q.Get("language") != "en" {
+			t.Errorf("engines %q, language %q", q.Get("engines"), q.Get("language"))
+		}
+		fmt.Fprint(w, `{"results":[
+			{"url":"https://go.dev/","title":"The  Go\nProgramming Language","content":"Go is an open source language","engine":"google"},
+			{"url":"https://go.dev/","title":"Duplicate","content":"again","engine":"bing"},
+			{"url":"","title":"No link"},
+			{"url":"https://example.com/","title":"Example","content":"","engines":["bing","google"]}
+		]}`)
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.BaseURL = srv.URL + "/"
+	es.Engines = []string{"google", "bing"}
+	es.Language = "en"
+	topics, err := es.FetchTopics(5, " golang ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	if topics[0].Topic != "The Go Programming Language" || topics[0].SourceURL != "https://go.dev/" || topics[0].Site != "google" {
+		t.Errorf("topic 0 = %+v", topics[0])
+	}
+	if topics[1].Site != "bing" {
+		t.Errorf("topic 1 Site = %q, want the first of its engines", topics[1].Site)
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].DataText != "Go is an open source language" || data[0].AnswerID != topics[0].TopicID {
+		t.Errorf("FetchData = %+v", data)
+	}
+	if data, err := es.FetchData(1, topics[1].TopicID); err != nil || len(data) != 0 {
+		t.Errorf("FetchData without a snippet = %+v, %v", data, err)
+	}
+	if _, err := es.FetchData(1, topics[0].TopicID+1); err == nil {
+		t.Error("FetchData accepted an unknown topic ID")
+	}
+}
+
+func TestJSONFormatDisabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.BaseURL = srv.URL
+	if _, err := es.FetchTopics(5, "golang"); err == nil {
+		t.Error("FetchTopics succeeded against an instance without the json format")
+	}
+}
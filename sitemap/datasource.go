@@ -0,0 +1,546 @@
+package sitemap
+
+// Data Source Adapter for any website with a sitemap.xml: URLs are matched against the query and pages
+// are fetched and extracted for data
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/extract"
+	"github.com/locus-search/datasource/internal/robots"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/pdftext"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultDataCount  = 3
+	// Paragraphs are grouped into data items of about this many characters
+	chunkSize = 1500
+	// Sitemaps may be at most 50 MB uncompressed
+	maxSitemapSize = 50 << 20
+)
+
+type DataSourceSitemap struct {
+	Client     *http.Client
+	SiteURL    string        // Site root, e.g. "https://go.dev"; sitemaps are found through its robots.txt
+	SitemapURL string        // Optional; skips discovery and reads this sitemap or sitemap index
+	PathPrefix string        // Optional; only URLs whose path starts with it are searched, e.g. "/blog/"
+	MaxURLs    int           // URLs kept from the sitemaps; default 50000
+	MaxAge     time.Duration // How long the URL list is cached; default 1 hour
+	UserAgent  string
+	Extractor  *extract.Extractor // Site overrides for page text; default extract.Default
+	// Archive, when set, serves pages found behind a paywall or consent wall, e.g. wayback.New()
+	Archive source.Archive
+
+	rateLimiter *rate.Limiter
+	robots      *robots.Checker
+	mu          sync.Mutex
+	entries     []entry
+	loaded      time.Time
+	pages       topicid.Map[entry]
+}
+
+// entry is one URL from a sitemap
+type entry struct {
+	URL     string
+	LastMod time.Time
+	Title   string   // From the news or image extensions, when present
+	Words   []string // Lowercased words of the path and title
+	Wall    string   // Set by FetchData when the page was walled
+}
+
+// sitemapXML decodes both <urlset> and <sitemapindex> documents
+type sitemapXML struct {
+	URLs []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+		News    string `xml:"news>title"`
+		Image   string `xml:"image>title"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+func New() *DataSourceSitemap {
+	return &DataSourceSitemap{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		MaxURLs:   50000,
+		MaxAge:    time.Hour,
+		UserAgent: "locus/sitemap-datasource",
+	}
+}
+
+// Init implements models.DataSource
+func (es *DataSourceSitemap) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.SiteURL == "" && es.SitemapURL == "" {
+		return errors.New("SiteURL or SitemapURL is required for Sitemap DataSource")
+	}
+	if es.MaxURLs <= 0 {
+		es.MaxURLs = 50000
+	}
+	if es.MaxAge <= 0 {
+		es.MaxAge = time.Hour
+	}
+	if es.rateLimiter == nil {
+		es.rateLimiter = rate.NewLimiter(2, 2)
+	}
+	if es.robots == nil {
+		es.robots = robots.NewChecker(es.Client, es.UserAgent)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceSitemap) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	sitemaps, err := es.sitemapURLs(ctx)
+	if err != nil || len(sitemaps) == 0 {
+		return false
+	}
+	resp, err := es.get(ctx, sitemaps[0])
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return true
+}
+
+// Info implements source.Informer
+func (es *DataSourceSitemap) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Sitemap",
+		Homepage: es.SiteURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Ranks sitemap URLs by how many query words appear in their path (or news/image title), newest first
+// among equals. Topics are titled from the title or the last path segment, e.g. "Go 1.22 release notes".
+func (es *DataSourceSitemap) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Sitemap DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	entries, err := es.load()
+	if err != nil {
+		return nil, err
+	}
+	terms := words(query)
+	type scored struct {
+		entry
+		score int
+	}
+	var matches []scored
+	for _, e := range entries {
+		if score := matchCount(e.Words, terms); score > 0 {
+			matches = append(matches, scored{e, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].LastMod.After(matches[j].LastMod)
+	})
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, m := range matches {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     m.Title,
+			SourceURL: m.URL,
+			Site:      host(m.URL),
+			TopicID:   es.pages.Put(m.URL, m.entry),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Fetches the page and returns its main text in items of about 1500 characters, each starting with the
+// page title. PDFs listed in the sitemap are extracted page by page, and pages behind a paywall or
+// consent wall are read from Archive when it has a copy.
+func (es *DataSourceSitemap) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	e, ok := es.pages.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Sitemap topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultDataCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	allowed, err := es.robots.Allowed(ctx, e.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("robots.txt disallows %s", e.URL)
+	}
+	resp, err := es.get(ctx, e.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	title, paragraphs, wall, err := es.pageText(ctx, resp, e)
+	if err != nil {
+		return nil, err
+	}
+	var archived []datasource.DataSourceData
+	if wall != "" {
+		if archived = es.archived(ctx, e.URL, title, count); archived != nil {
+			wall = ""
+		}
+	}
+	if e.Wall != wall {
+		e.Wall = wall
+		es.pages.Set(topicID, e)
+	}
+	if archived != nil {
+		return archived, nil
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, chunk := range chunks(paragraphs, chunkSize) {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		if len(results) > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%s#%d", e.URL, len(results)))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  title + "\n" + chunk,
+			SourceURL: e.URL,
+			Site:      host(e.URL),
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// LastModified implements source.Freshness from the sitemap's lastmod
+func (es *DataSourceSitemap) LastModified(topicID int64) time.Time {
+	e, _ := es.pages.Get(topicID)
+	return e.LastMod
+}
+
+// Wall implements source.Walled
+func (es *DataSourceSitemap) Wall(topicID int64) string {
+	e, _ := es.pages.Get(topicID)
+	return e.Wall
+}
+
+// archived returns up to count items from Archive's copy of a walled page, each starting with the
+// title; nil when there is no Archive or it has no copy
+func (es *DataSourceSitemap) archived(ctx context.Context, pageURL, title string, count int) []datasource.DataSourceData {
+	if es.Archive == nil {
+		return nil
+	}
+	data, err := es.Archive.FetchArchived(ctx, pageURL)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	if len(data) > count {
+		data = data[:count]
+	}
+	for i := range data {
+		data[i].DataText = title + "\n" + data[i].DataText
+	}
+	return data
+}
+
+// pageText extracts the title and paragraphs of a fetched page or PDF, and the wall the page was behind
+func (es *DataSourceSitemap) pageText(ctx context.Context, resp *http.Response, e entry) (string, []string, string, error) {
+	body := bufio.NewReader(resp.Body)
+	head, _ := body.Peek(5)
+	if pdftext.IsPDF(resp.Header.Get("Content-Type"), head) {
+		doc, err := pdftext.Default.Reader(ctx, body)
+		if err != nil {
+			return "", nil, "", err
+		}
+		title := doc.Title
+		if title == "" {
+			title = e.Title
+		}
+		return title, doc.Chunks(chunkSize), "", nil
+	}
+
+	extractor := es.Extractor
+	if extractor == nil {
+		extractor = extract.Default
+	}
+	// Every page in the sitemap is wanted, so short pages are kept; the description stands in for a
+	// page that yields no text at all
+	article, err := extractor.Reader(body, e.URL)
+	if err != nil && !errors.Is(err, extract.ErrLowQuality) {
+		return "", nil, "", err
+	}
+	if len(article.Paragraphs) == 0 && article.Description != "" {
+		article.Paragraphs = []string{article.Description}
+	}
+	title := article.Title
+	if title == "" {
+		title = e.Title
+	}
+	return title, article.Paragraphs, article.Wall, nil
+}
+
+// load returns the cached URL list, reading the sitemaps again once it is older than MaxAge
+func (es *DataSourceSitemap) load() ([]entry, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.entries != nil && time.Since(es.loaded) < es.MaxAge {
+		return es.entries, nil
+	}
+
+	// Large sites split their URLs over many sitemaps, so loading gets a longer deadline than a search
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	queue, err := es.sitemapURLs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var entries []entry
+	visited := map[string]bool{}
+	for len(queue) > 0 && len(entries) < es.MaxURLs && len(visited) < 100 {
+		uri := queue[0]
+		queue = queue[1:]
+		if visited[uri] {
+			continue
+		}
+		visited[uri] = true
+		doc, err := es.readSitemap(ctx, uri)
+		if err != nil {
+			// A broken child sitemap should not hide the others
+			if len(visited) > 1 {
+				continue
+			}
+			return nil, err
+		}
+		for _, s := range doc.Sitemaps {
+			queue = append(queue, strings.TrimSpace(s.Loc))
+		}
+		for _, u := range doc.URLs {
+			if len(entries) >= es.MaxURLs {
+				break
+			}
+			if e, ok := es.parseEntry(strings.TrimSpace(u.Loc), u.LastMod, u.News, u.Image); ok {
+				entries = append(entries, e)
+			}
+		}
+	}
+	es.entries, es.loaded = entries, time.Now()
+	return entries, nil
+}
+
+// sitemapURLs returns SitemapURL, or the sitemaps listed in the site's robots.txt, or /sitemap.xml
+func (es *DataSourceSitemap) sitemapURLs(ctx context.Context) ([]string, error) {
+	if es.SitemapURL != "" {
+		return []string{es.SitemapURL}, nil
+	}
+	root, err := url.Parse(strings.TrimRight(es.SiteURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	rules, err := es.robots.Rules(ctx, root)
+	if err == nil && len(rules.Sitemaps) > 0 {
+		return rules.Sitemaps, nil
+	}
+	return []string{root.String() + "/sitemap.xml"}, nil
+}
+
+func (es *DataSourceSitemap) readSitemap(ctx context.Context, uri string) (*sitemapXML, error) {
+	resp, err := es.get(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var body io.Reader = resp.Body
+	// Compressed sitemaps are served as files, so the transport does not decompress them
+	if strings.HasSuffix(resp.Request.URL.Path, ".gz") || resp.Header.Get("Content-Type") == "application/x-gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		body = gz
+	}
+	var doc sitemapXML
+	if err := xml.NewDecoder(io.LimitReader(body, maxSitemapSize)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("sitemap %s: %w", uri, err)
+	}
+	return &doc, nil
+}
+
+func (es *DataSourceSitemap) parseEntry(loc, lastMod, newsTitle, imageTitle string) (entry, bool) {
+	u, err := url.Parse(loc)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return entry{}, false
+	}
+	if es.PathPrefix != "" && !strings.HasPrefix(u.Path, es.PathPrefix) {
+		return entry{}, false
+	}
+	e := entry{URL: loc, Title: newsTitle}
+	if e.Title == "" {
+		e.Title = imageTitle
+	}
+	if e.Title == "" {
+		e.Title = slugTitle(u)
+	}
+	// W3C datetime: a date, or a date and time
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(lastMod)); err == nil {
+			e.LastMod = t
+			break
+		}
+	}
+	e.Words = words(u.Path + " " + e.Title)
+	return e, true
+}
+
+// get performs a rate-limited GET and fails on a non-2xx status
+func (es *DataSourceSitemap) get(ctx context.Context, uri string) (*http.Response, error) {
+	if es.rateLimiter != nil {
+		if err := es.rateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, fmt.Errorf("sitemap request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// Helpers
+
+// words splits text into lowercase letter and digit runs
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchCount counts the query terms found among words; a term also matches a word it prefixes
+func matchCount(words, terms []string) int {
+	count := 0
+	for _, term := range terms {
+		for _, w := range words {
+			if strings.HasPrefix(w, term) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// slugTitle turns the last path segment into a title, e.g. "/blog/go-1-22-release" gives "Go 1 22 release"
+func slugTitle(u *url.URL) string {
+	segments := strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+	if len(segments) == 0 {
+		return u.Host
+	}
+	last := segments[len(segments)-1]
+	if i := strings.LastIndexByte(last, '.'); i > 0 {
+		last = last[:i]
+	}
+	if unescaped, err := url.PathUnescape(last); err == nil {
+		last = unescaped
+	}
+	title := strings.Join(strings.FieldsFunc(last, func(r rune) bool { return r == '-' || r == '_' || r == '+' }), " ")
+	if title == "" {
+		return u.Host
+	}
+	r := []rune(title)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func host(uri string) string {
+	if u, err := url.Parse(uri); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
+
+// chunks joins paragraphs into blocks of roughly size characters without splitting a paragraph
+func chunks(paragraphs []string, size int) []string {
+	var out []string
+	var current strings.Builder
+	for _, p := range paragraphs {
+		if current.Len() > 0 && current.Len()+len(p) > size {
+			out = append(out, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(p)
+	}
+	if current.Len() > 0 {
+		out = append(out, current.String())
+	}
+	return out
+}
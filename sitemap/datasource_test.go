@@ -0,0 +1,137 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "User-agent: *\nDisallow: /private/\nSitemap: %s/sitemap-index.xml\n", srv.URL)
+	})
+	mux.HandleFunc("/sitemap-index.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>%[1]s/sitemap-blog.xml</loc></sitemap>
+  <sitemap><loc>%[1]s/missing.xml</loc></sitemap>
+  <sitemap><loc>%[1]s/sitemap-news.xml.gz</loc></sitemap>
+</sitemapindex>`, srv.URL)
+	})
+	mux.HandleFunc("/sitemap-blog.xml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>%[1]s/blog/go-1-21-release</loc><lastmod>2023-08-08</lastmod></url>
+  <url><loc> %[1]s/blog/go-1-22-release.html </loc><lastmod>2024-02-06T17:00:00Z</lastmod></url>
+  <url><loc>%[1]s/private/go-release-plans</loc></url>
+  <url><loc>%[1]s/docs/install</loc></url>
+  <url><loc>mailto:release@example.com</loc></url>
+</urlset>`, srv.URL)
+	})
+	mux.HandleFunc("/sitemap-news.xml.gz", func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		fmt.Fprintf(gz, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">
+  <url><loc>%s/news/12345</loc><news:news><news:title>Go wins an award</news:title></news:news></url>
+</urlset>`, srv.URL)
+		gz.Close()
+		w.Write(buf.Bytes())
+	})
+	mux.HandleFunc("/blog/go-1-22-release.html", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Go 1.22 is released!</title></head><body>
+<nav><a href="/">Home</a></nav>
+<article><p>Today the Go team is thrilled to release Go 1.22, which you can get from the download page.</p>
+<p>Go 1.22 comes with several important new features and improvements to the toolchain and runtime.</p></article>
+</body></html>`)
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSource(t *testing.T) (*DataSourceSitemap, *httptest.Server) {
+	t.Helper()
+	srv := newServer(t)
+	es := New()
+	es.SiteURL = srv.URL + "/"
+	es.rateLimiter = rate.NewLimiter(rate.Inf, 1)
+	return es, srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es, srv := newSource(t)
+	topics, err := es.FetchTopics(5, "Go release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Go 1 22 release|/blog/go-1-22-release.html",
+		"Go 1 21 release|/blog/go-1-21-release",
+		"Go release plans|/private/go-release-plans",
+		"Go wins an award|/news/12345",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].SourceURL[len(srv.URL):]; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+	if got := es.LastModified(topics[0].TopicID); !got.Equal(time.Date(2024, 2, 6, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("LastModified = %v", got)
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "Go 1.22 is released!\nToday the Go team is thrilled to release Go 1.22, which you can get from the download page." +
+		"\n\nGo 1.22 comes with several important new features and improvements to the toolchain and runtime."
+	if len(data) != 1 || data[0].DataText != wantData {
+		t.Errorf("FetchData = %+v, want %q", data, wantData)
+	}
+	if u, _ := url.Parse(srv.URL); data[0].Site != u.Hostname() {
+		t.Errorf("Site = %q", data[0].Site)
+	}
+
+	if _, err := es.FetchData(3, topics[2].TopicID); err == nil {
+		t.Error("FetchData fetched a page robots.txt disallows")
+	}
+}
+
+func TestPathPrefix(t *testing.T) {
+	es, _ := newSource(t)
+	es.PathPrefix = "/news/"
+	topics, err := es.FetchTopics(5, "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Go wins an award" {
+		t.Errorf("FetchTopics = %+v", topics)
+	}
+}
+
+func TestSlugTitle(t *testing.T) {
+	tests := map[string]string{
+		"https://example.com/blog/go-1-22-release":   "Go 1 22 release",
+		"https://example.com/wiki/caf%C3%A9_society": "Café society",
+		"https://example.com/a/index.html":           "Index",
+		"https://example.com/":                       "example.com",
+	}
+	for raw, want := range tests {
+		u, _ := url.Parse(raw)
+		if got := slugTitle(u); got != want {
+			t.Errorf("slugTitle(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Package snapshot records what a data source returns for a set of queries
+// into a portable archive and serves it back offline, for demos, air-gapped
+// testing and reproducible evaluations. Archives are JSON files, gzipped
+// when the name ends in ".gz".
+package snapshot
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+// Version is the archive format written by this package
+const Version = 1
+
+// Archive is the recorded output of a source
+type Archive struct {
+	Version int       `json:"version"`
+	Source  string    `json:"source,omitempty"` // Name of the recorded source
+	Created time.Time `json:"created"`
+	Queries []Query   `json:"queries"`
+	// Info is the recorded source's SourceInfo, so replayed content keeps its license and attribution
+	Info *source.SourceInfo `json:"info,omitempty"`
+	// Data items by topic ID, for every topic in Queries whose data was fetched
+	Data map[int64][]datasource.DataSourceData `json:"data"`
+}
+
+// Query is one recorded search
+type Query struct {
+	Input  string                       `json:"input"`
+	Count  int                          `json:"count"`
+	Topics []datasource.DataSourceTopic `json:"topics"`
+	Error  string                       `json:"error,omitempty"` // Set when the search failed; Topics is empty
+}
+
+// Record searches s for each query, fetches up to dataCount data items for every topic found, and
+// returns the results as an archive. Failed searches are recorded with their error and failed data
+// fetches are left out; an error is returned only when every search fails.
+func Record(name string, s source.Source, queries []string, count, dataCount int) (*Archive, error) {
+	if err := s.Init(); err != nil {
+		return nil, err
+	}
+	a := &Archive{Version: Version, Source: name, Info: info(s), Created: time.Now().UTC(), Data: map[int64][]datasource.DataSourceData{}}
+	var errs []error
+	for _, input := range queries {
+		q := Query{Input: input, Count: count}
+		topics, err := s.FetchTopics(count, input)
+		if err != nil {
+			q.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%q: %w", input, err))
+		}
+		q.Topics = topics
+		a.Queries = append(a.Queries, q)
+		for _, t := range topics {
+			if _, ok := a.Data[t.TopicID]; ok {
+				continue
+			}
+			if data, err := s.FetchData(dataCount, t.TopicID); err == nil {
+				a.Data[t.TopicID] = data
+			}
+		}
+	}
+	if len(queries) > 0 && len(errs) == len(queries) {
+		return nil, errors.Join(errs...)
+	}
+	return a, nil
+}
+
+// Load reads an archive from a file, decompressing it when the name ends in ".gz"
+func Load(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return Read(r)
+}
+
+// Read decodes an archive
+func Read(r io.Reader) (*Archive, error) {
+	var a Archive
+	if err := json.NewDecoder(r).Decode(&a); err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+	if a.Version > Version {
+		return nil, fmt.Errorf("snapshot version %d is newer than supported version %d", a.Version, Version)
+	}
+	if a.Data == nil {
+		a.Data = map[int64][]datasource.DataSourceData{}
+	}
+	return &a, nil
+}
+
+// Save writes the archive to a file, gzipped when the name ends in ".gz"
+func (a *Archive) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = f
+	var gz *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		gz = gzip.NewWriter(f)
+		w = gz
+	}
+	err = a.Write(w)
+	if gz != nil {
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Write encodes the archive as indented JSON
+func (a *Archive) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+// Recorder wraps a live source and records every search and data fetch that passes through it, for
+// capturing a session rather than a fixed query list. It implements source.Source.
+type Recorder struct {
+	Source source.Source
+	Name   string
+
+	mu      sync.Mutex
+	archive Archive
+}
+
+func NewRecorder(name string, s source.Source) *Recorder {
+	return &Recorder{Source: s, Name: name}
+}
+
+// Init implements models.DataSource
+func (r *Recorder) Init() error {
+	return r.Source.Init()
+}
+
+// CheckAvailability implements models.DataSource
+func (r *Recorder) CheckAvailability() bool {
+	return r.Source.CheckAvailability()
+}
+
+// Unwrap implements source.Wrapper
+func (r *Recorder) Unwrap() source.Source {
+	return r.Source
+}
+
+// FetchTopics implements models.DataSource
+func (r *Recorder) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	topics, err := r.Source.FetchTopics(count, input)
+	q := Query{Input: input, Count: count, Topics: topics}
+	if err != nil {
+		q.Error = err.Error()
+	}
+	r.mu.Lock()
+	r.archive.Queries = append(r.archive.Queries, q)
+	r.mu.Unlock()
+	return topics, err
+}
+
+// FetchData implements models.DataSource
+func (r *Recorder) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	data, err := r.Source.FetchData(count, topicID)
+	if err == nil {
+		r.mu.Lock()
+		if r.archive.Data == nil {
+			r.archive.Data = map[int64][]datasource.DataSourceData{}
+		}
+		// Keep the largest response when a topic is fetched more than once
+		if len(data) >= len(r.archive.Data[topicID]) {
+			r.archive.Data[topicID] = data
+		}
+		r.mu.Unlock()
+	}
+	return data, err
+}
+
+// Archive returns a copy of what has been recorded so far
+func (r *Recorder) Archive() *Archive {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	a := &Archive{
+		Version: Version,
+		Source:  r.Name,
+		Info:    info(r.Source),
+		Created: time.Now().UTC(),
+		Queries: append([]Query(nil), r.archive.Queries...),
+		Data:    make(map[int64][]datasource.DataSourceData, len(r.archive.Data)),
+	}
+	for id, data := range r.archive.Data {
+		a.Data[id] = data
+	}
+	return a
+}
+
+// info returns the SourceInfo s reports for all of its topics; nil when it has none or it varies by topic
+func info(s source.Source) *source.SourceInfo {
+	if i, ok := source.InfoFor(s, 0); ok {
+		return &i
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package snapshot
+
+// Data Source Adapter that serves a recorded archive offline
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultDataCount  = 3
+)
+
+type DataSourceSnapshot struct {
+	Path    string   // Archive file, loaded by Init unless Archive is set
+	Archive *Archive // Served directly when set
+	// Fuzzy answers queries that were not recorded with the recorded query sharing the most words,
+	// when at least half of the words match; otherwise only recorded queries match, ignoring case and
+	// spacing
+	Fuzzy bool
+
+	mu      sync.Mutex
+	queries map[string]Query
+}
+
+func New() *DataSourceSnapshot {
+	return &DataSourceSnapshot{}
+}
+
+// Init implements models.DataSource
+// Loads the archive from Path and indexes its queries
+func (es *DataSourceSnapshot) Init() error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.queries != nil {
+		return nil
+	}
+	if es.Archive == nil {
+		if es.Path == "" {
+			return errors.New("Path or Archive is required for Snapshot DataSource")
+		}
+		a, err := Load(es.Path)
+		if err != nil {
+			return err
+		}
+		es.Archive = a
+	}
+	es.queries = make(map[string]Query, len(es.Archive.Queries))
+	for _, q := range es.Archive.Queries {
+		key := normalize(q.Input)
+		// A later recording replaces an earlier one, unless it failed and the earlier one did not
+		if prev, ok := es.queries[key]; ok && prev.Error == "" && q.Error != "" {
+			continue
+		}
+		es.queries[key] = q
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceSnapshot) CheckAvailability() bool {
+	return es.Init() == nil
+}
+
+// Info implements source.Informer, returning the recorded source's info when the archive has it
+func (es *DataSourceSnapshot) Info() source.SourceInfo {
+	if es.Archive != nil && es.Archive.Info != nil {
+		return *es.Archive.Info
+	}
+	return source.SourceInfo{Name: "Snapshot"}
+}
+
+// FetchTopics implements models.DataSource
+// Returns the topics recorded for the query, up to count. Recorded failures are returned as errors, and
+// queries that were not recorded have no topics.
+func (es *DataSourceSnapshot) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Snapshot DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	q, ok := es.lookup(query)
+	if !ok {
+		return []datasource.DataSourceTopic{}, nil
+	}
+	if q.Error != "" {
+		return nil, fmt.Errorf("recorded failure: %s", q.Error)
+	}
+	topics := q.Topics
+	if len(topics) > count {
+		topics = topics[:count]
+	}
+	return append([]datasource.DataSourceTopic{}, topics...), nil
+}
+
+// FetchData implements models.DataSource
+// Returns the data recorded for the topic, up to count items
+func (es *DataSourceSnapshot) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if count <= 0 {
+		count = defaultDataCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+	data, ok := es.Archive.Data[topicID]
+	if !ok {
+		return nil, fmt.Errorf("unknown Snapshot topicID %d", topicID)
+	}
+	if len(data) > count {
+		data = data[:count]
+	}
+	return append([]datasource.DataSourceData{}, data...), nil
+}
+
+// lookup finds the recorded query for input, exactly or, with Fuzzy, by shared words
+func (es *DataSourceSnapshot) lookup(input string) (Query, bool) {
+	key := normalize(input)
+	if q, ok := es.queries[key]; ok {
+		return q, true
+	}
+	if !es.Fuzzy {
+		return Query{}, false
+	}
+	terms := strings.Fields(key)
+	var best Query
+	bestScore := 0.0
+	for recorded, q := range es.queries {
+		if score := overlap(terms, strings.Fields(recorded)); score > bestScore ||
+			(score == bestScore && score > 0 && recorded < normalize(best.Input)) {
+			best, bestScore = q, score
+		}
+	}
+	return best, bestScore >= 0.5
+}
+
+// Helpers
+
+// normalize lowercases a query and reduces it to its words
+func normalize(query string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// overlap is the Jaccard similarity of two word lists
+func overlap(a, b []string) float64 {
+	set := make(map[string]bool, len(a))
+	for _, w := range a {
+		set[w] = true
+	}
+	union := len(set)
+	shared := 0
+	for _, w := range b {
+		if set[w] {
+			shared++
+			set[w] = false
+		} else if _, ok := set[w]; !ok {
+			set[w] = false
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
@@ -0,0 +1,77 @@
+package snapshot
+
+import (
+	"bytes"
+	"testing"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// fake is a live source returning fixed topics, and data naming the topic it was asked for
+type fake struct {
+	topics []datasource.DataSourceTopic
+}
+
+func (f *fake) Init() error             { return nil }
+func (f *fake) CheckAvailability() bool { return true }
+
+func (f *fake) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return f.topics, nil
+}
+
+func (f *fake) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return []datasource.DataSourceData{{DataText: "data", AnswerID: topicID}}, nil
+}
+
+func TestReplayNegativeTopicID(t *testing.T) {
+	// DuckDuckGo hashes URLs into the full int64 range
+	live := &fake{topics: []datasource.DataSourceTopic{{TopicID: -42, Topic: "negative"}, {TopicID: 7, Topic: "positive"}}}
+	a, err := Record("fake", live, []string{"query"}, 5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := a.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	a, err = Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	es := New()
+	es.Archive = a
+	topics, err := es.FetchTopics(5, "Query")
+	if err != nil || len(topics) != 2 {
+		t.Fatalf("FetchTopics = %+v, %v", topics, err)
+	}
+	for _, topic := range topics {
+		data, err := es.FetchData(1, topic.TopicID)
+		if err != nil {
+			t.Fatalf("FetchData(%d): %v", topic.TopicID, err)
+		}
+		if len(data) != 1 || data[0].AnswerID != topic.TopicID {
+			t.Errorf("FetchData(%d) = %+v", topic.TopicID, data)
+		}
+	}
+	if _, err := es.FetchData(1, 0); err == nil {
+		t.Error("FetchData(0) succeeded for a topic that was not recorded")
+	}
+}
+
+func TestRecorderNegativeTopicID(t *testing.T) {
+	r := NewRecorder("fake", &fake{topics: []datasource.DataSourceTopic{{TopicID: -42, Topic: "negative"}}})
+	if _, err := r.FetchTopics(5, "query"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.FetchData(1, -42); err != nil {
+		t.Fatal(err)
+	}
+
+	es := New()
+	es.Archive = r.Archive()
+	data, err := es.FetchData(1, -42)
+	if err != nil || len(data) != 1 {
+		t.Fatalf("FetchData(-42) = %+v, %v", data, err)
+	}
+}
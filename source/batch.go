@@ -0,0 +1,65 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// BatchConcurrency is how many topics FetchDataEach fetches at once
+const BatchConcurrency = 8
+
+// Batcher is implemented by sources that can fetch the data of many topics in fewer requests than one
+// per topic
+type Batcher interface {
+	// FetchDataBatch returns up to count data items for each of topicIDs, keyed by topic ID; topics
+	// without content are missing from the map
+	FetchDataBatch(ctx context.Context, count int, topicIDs []int64) (map[int64][]datasource.DataSourceData, error)
+}
+
+// FetchDataBatch fetches the data of topicIDs from s, in one call when s implements Batcher and with
+// FetchDataEach otherwise
+func FetchDataBatch(ctx context.Context, s Source, count int, topicIDs []int64) (map[int64][]datasource.DataSourceData, error) {
+	if b, ok := s.(Batcher); ok {
+		return b.FetchDataBatch(ctx, count, topicIDs)
+	}
+	return FetchDataEach(ctx, s, count, topicIDs)
+}
+
+// FetchDataEach asks s for the data of each topic with FetchDataContext, BatchConcurrency at a time.
+// The data of the topics that succeeded is returned along with the errors of those that failed.
+func FetchDataEach(ctx context.Context, s Source, count int, topicIDs []int64) (map[int64][]datasource.DataSourceData, error) {
+	data := make([][]datasource.DataSourceData, len(topicIDs))
+	errs := make([]error, len(topicIDs))
+	slots := make(chan struct{}, BatchConcurrency)
+	var wg sync.WaitGroup
+	for i, id := range topicIDs {
+		wg.Add(1)
+		go func(i int, id int64) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			data[i], errs[i] = FetchDataContext(ctx, s, count, id)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("topic %d: %w", id, errs[i])
+			}
+		}(i, id)
+	}
+	wg.Wait()
+
+	results := make(map[int64][]datasource.DataSourceData, len(topicIDs))
+	for i, id := range topicIDs {
+		if len(data[i]) > 0 {
+			results[id] = data[i]
+		}
+	}
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package source
+
+import "time"
+
+// TopicOptions fields, for Capabilities.Options
+const (
+	OptionLanguage   = "language"
+	OptionRegion     = "region"
+	OptionSafeSearch = "safesearch"
+	OptionTimeRange  = "timerange"
+)
+
+// Capabilities say what a source supports, for hosts that register many sources and choose among
+// them at runtime
+type Capabilities struct {
+	Name       string
+	Data       bool      // FetchData returns content for the source's topics
+	Pagination bool      // Searches continue from cursors, see Pager
+	Context    bool      // Fetches take the caller's context, see ContextSource
+	Options    []string  // TopicOptions the source maps onto its backend, e.g. OptionLanguage
+	Languages  []string  // ISO 639-1 codes of the content's languages; empty for any or unknown
+	RateLimit  RateLimit // Limit the adapter paces its requests to
+}
+
+// RateLimit is a number of requests allowed per period; the zero value means no known limit
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// Describer is implemented by adapters that describe their capabilities
+type Describer interface {
+	Describe() Capabilities
+}
+
+// Describe returns the capabilities of s: those of s, or of the first source it wraps that implements
+// Describer, completed from the optional interfaces of the sources it wraps. Decorators that don't
+// implement Pager, ContextSource or OptionsSource drop cursors, contexts and options on the way in, so
+// each is reported only when every source down to the innermost implements it. Sources that don't
+// describe themselves are taken to return data and are named from their Info.
+func Describe(s Source) Capabilities {
+	c := Capabilities{Data: true}
+	described := false
+	pages, contexts, options := true, true, true
+	inner := s
+	for inner != nil {
+		if d, ok := inner.(Describer); ok && !described {
+			c, described = d.Describe(), true
+		}
+		if i, ok := inner.(Informer); ok && c.Name == "" {
+			c.Name = i.Info().Name
+		}
+		_, ok := inner.(Pager)
+		pages = pages && ok
+		_, ok = inner.(ContextSource)
+		contexts = contexts && ok
+		_, ok = inner.(OptionsSource)
+		options = options && ok
+		w, ok := inner.(Wrapper)
+		if !ok {
+			break
+		}
+		inner = w.Unwrap()
+	}
+	if inner == nil {
+		return c
+	}
+	c.Pagination = pages
+	c.Context = contexts
+	if !options {
+		c.Options = nil
+	}
+	return c
+}
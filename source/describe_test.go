@@ -0,0 +1,61 @@
+package source
+
+import (
+	"context"
+	"testing"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// rich is a source implementing every interface Describe looks for
+type rich struct {
+	plain
+}
+
+func (r rich) Describe() Capabilities {
+	return Capabilities{Name: "rich", Data: true, Options: []string{OptionLanguage}}
+}
+
+func (r rich) FetchTopicsContext(ctx context.Context, count int, input string) ([]datasource.DataSourceTopic, error) {
+	return r.FetchTopics(count, input)
+}
+
+func (r rich) FetchDataContext(ctx context.Context, count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return r.FetchData(count, topicID)
+}
+
+func (r rich) FetchTopicsWithOptions(ctx context.Context, query string, opts TopicOptions) ([]datasource.DataSourceTopic, error) {
+	return r.FetchTopics(opts.Count, query)
+}
+
+func (r rich) FetchTopicsPage(ctx context.Context, query string, opts TopicOptions, cursor string) (Page, error) {
+	return Page{}, nil
+}
+
+func newRich() rich {
+	return rich{plain{&keyed{ids: map[int64]ID{}}}}
+}
+
+func TestDescribe(t *testing.T) {
+	c := Describe(newRich())
+	if c.Name != "rich" || !c.Context || !c.Pagination || len(c.Options) != 1 {
+		t.Errorf("Describe = %+v", c)
+	}
+}
+
+func TestDescribeThroughWrapper(t *testing.T) {
+	// counting only forwards FetchData, so contexts, options and cursors stop there
+	c := Describe(&counting{Source: newRich()})
+	if c.Name != "rich" || !c.Data {
+		t.Errorf("Describe = %+v, want rich's name and data", c)
+	}
+	if c.Context || c.Pagination || len(c.Options) != 0 {
+		t.Errorf("Describe = %+v, reports what the wrapper drops", c)
+	}
+
+	// WithInfo embeds its source, hiding the same interfaces
+	c = Describe(WithInfo(newRich(), SourceInfo{Name: "renamed"}))
+	if c.Context || c.Pagination || len(c.Options) != 0 {
+		t.Errorf("Describe(WithInfo) = %+v", c)
+	}
+}
@@ -0,0 +1,42 @@
+package source
+
+import "time"
+
+// TopicDetails are what a backend returns about a search result beyond its title and URL, so rankers
+// and interfaces need not fetch the page
+type TopicDetails struct {
+	Snippet      string    // Plain-text excerpt, usually around the query's terms
+	PublishedAt  time.Time // Zero when unknown
+	Author       string
+	ThumbnailURL string
+	// Score is the backend's relevance for the query from 0 to 1, 1 the best match, comparable across
+	// sources; 0 when the source gives none. Sources that only rank their results use RankScore.
+	Score float64
+}
+
+// RankScore is the Score of the result at rank, counted from 0 across all pages: the reciprocal rank
+// 1/(rank+1), so the top result scores 1, the second 0.5 and so on
+func RankScore(rank int) float64 {
+	return 1 / float64(rank+1)
+}
+
+// Detailed is implemented by sources whose search results carry TopicDetails
+type Detailed interface {
+	Details(topicID int64) (TopicDetails, bool)
+}
+
+// Details returns the details s, or the first source it wraps that implements Detailed, reports for
+// the topic; false when there are none
+func Details(s Source, topicID int64) (TopicDetails, bool) {
+	for s != nil {
+		if d, ok := s.(Detailed); ok {
+			return d.Details(topicID)
+		}
+		w, ok := s.(Wrapper)
+		if !ok {
+			break
+		}
+		s = w.Unwrap()
+	}
+	return TopicDetails{}, false
+}
@@ -0,0 +1,70 @@
+package source
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Classes of failure. Adapters wrap them in the errors they return, so callers can tell them apart
+// with errors.Is, e.g. to back off from a rate-limited source or stop querying a blocked one.
+var (
+	ErrRateLimited = errors.New("rate limited")
+	ErrBlocked     = errors.New("blocked")     // Access refused, e.g. by a captcha, a 403 or robots.txt
+	ErrUnavailable = errors.New("unavailable") // The backend is down or failing
+	ErrBadQuery    = errors.New("bad query")   // The request is invalid and retrying will not help
+)
+
+// StatusError is an HTTP response a source treated as a failure. It matches the class of its status
+// with errors.Is.
+type StatusError struct {
+	Source     string
+	StatusCode int
+	RetryAfter time.Duration // From the Retry-After header; 0 without one
+	Message    string        // Start of the response body, when the adapter read it
+}
+
+// NewStatusError returns the failure for resp from the named source
+func NewStatusError(name string, resp *http.Response, message string) *StatusError {
+	e := &StatusError{Source: name, StatusCode: resp.StatusCode, Message: strings.TrimSpace(message)}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		e.RetryAfter = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(resp.Header.Get("Retry-After")); err == nil {
+		e.RetryAfter = max(time.Until(at), 0)
+	}
+	if len(e.Message) > 200 {
+		e.Message = strings.ToValidUTF8(e.Message[:200], "") + "..."
+	}
+	return e
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("%s request failed: status %d", e.Source, e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+func (e *StatusError) Unwrap() error {
+	return StatusClass(e.StatusCode)
+}
+
+// StatusClass returns the class of failure an HTTP status signals; nil for statuses without one
+func StatusClass(code int) error {
+	switch code {
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusUnavailableForLegalReasons:
+		return ErrBlocked
+	case http.StatusBadRequest, http.StatusRequestURITooLong, http.StatusUnprocessableEntity:
+		return ErrBadQuery
+	case http.StatusRequestTimeout, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return ErrUnavailable
+	}
+	return nil
+}
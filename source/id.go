@@ -0,0 +1,84 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// ID is an opaque string identifier of a topic, such as a URL, a DOI or a page ID, that the source can
+// fetch again without having returned the topic in this process. Callers store and compare IDs but
+// should not parse them.
+//
+// The SDK's TopicID and AnswerID are int64, so sources keep returning those, and IDSource maps them to
+// IDs. Sources without string identifiers use the decimal form of their int64 IDs, see IntID.
+type ID string
+
+// IntID returns the ID of a topic from a source with int64 IDs
+func IntID(id int64) ID {
+	return ID(strconv.FormatInt(id, 10))
+}
+
+// Int64 returns the int64 ID an IntID was made from; false for other IDs
+func (id ID) Int64() (int64, bool) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	return n, err == nil
+}
+
+// IDSource is implemented by sources whose topics have string identifiers. Their int64 TopicIDs stay
+// valid for FetchData, for consumers of the SDK interface.
+type IDSource interface {
+	// StringID returns the ID of a topic the source returned under topicID; false when unknown
+	StringID(topicID int64) (ID, bool)
+	// TopicID returns the int64 topic ID of the topic with the ID, and makes FetchData accept it whether
+	// or not the source returned the topic
+	TopicID(id ID) (int64, error)
+}
+
+// StringID returns the ID of a topic from s, or from the first source it wraps that implements
+// IDSource. Other sources' topics are identified by their int64 IDs, see IntID.
+func StringID(s Source, topicID int64) (ID, bool) {
+	for s != nil {
+		if i, ok := s.(IDSource); ok {
+			return i.StringID(topicID)
+		}
+		w, ok := s.(Wrapper)
+		if !ok {
+			break
+		}
+		s = w.Unwrap()
+	}
+	return IntID(topicID), true
+}
+
+// TopicID returns the int64 topic ID of the topic with the ID from s, or from the first source it wraps
+// that implements IDSource. Other sources' IDs are the IntIDs of their topic IDs.
+func TopicID(s Source, id ID) (int64, error) {
+	for s != nil {
+		if i, ok := s.(IDSource); ok {
+			return i.TopicID(id)
+		}
+		w, ok := s.(Wrapper)
+		if !ok {
+			break
+		}
+		s = w.Unwrap()
+	}
+	topicID, ok := id.Int64()
+	if !ok {
+		return 0, fmt.Errorf("topic ID %q is not numeric: %w", id, ErrBadQuery)
+	}
+	return topicID, nil
+}
+
+// FetchDataByID fetches the data of the topic with the ID from s with FetchDataContext, for the topic
+// ID TopicID returns, so the decorators around the source that resolves the ID apply as for any fetch
+func FetchDataByID(ctx context.Context, s Source, count int, id ID) ([]datasource.DataSourceData, error) {
+	topicID, err := TopicID(s, id)
+	if err != nil {
+		return nil, err
+	}
+	return FetchDataContext(ctx, s, count, topicID)
+}
@@ -0,0 +1,126 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// keyed is a source with string IDs, handing out the negative hashes DuckDuckGo does
+type keyed struct {
+	ids map[int64]ID
+}
+
+func (k *keyed) Init() error             { return nil }
+func (k *keyed) CheckAvailability() bool { return true }
+
+func (k *keyed) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return nil, nil
+}
+
+func (k *keyed) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if _, ok := k.ids[topicID]; !ok {
+		return nil, errors.New("unknown topic")
+	}
+	return []datasource.DataSourceData{{DataText: string(k.ids[topicID]), AnswerID: topicID}}, nil
+}
+
+func (k *keyed) StringID(topicID int64) (ID, bool) {
+	id, ok := k.ids[topicID]
+	return id, ok
+}
+
+func (k *keyed) TopicID(id ID) (int64, error) {
+	topicID := -int64(len(id))
+	k.ids[topicID] = id
+	return topicID, nil
+}
+
+// counting is a decorator that counts the fetches passing through it
+type counting struct {
+	Source
+	fetches int
+}
+
+func (c *counting) Unwrap() Source { return c.Source }
+
+func (c *counting) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	c.fetches++
+	return c.Source.FetchData(count, topicID)
+}
+
+func TestFetchDataByIDThroughWrappers(t *testing.T) {
+	inner := &keyed{ids: map[int64]ID{}}
+	decorator := &counting{Source: inner}
+	s := WithInfo(decorator, SourceInfo{Name: "keyed"})
+
+	data, err := FetchDataByID(context.Background(), s, 1, "https://example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].DataText != "https://example.com/a" {
+		t.Errorf("FetchDataByID = %+v", data)
+	}
+	if decorator.fetches != 1 {
+		t.Errorf("decorator saw %d fetches, want 1", decorator.fetches)
+	}
+	if id, ok := StringID(s, data[0].AnswerID); !ok || id != "https://example.com/a" {
+		t.Errorf("StringID(%d) = %q, %v", data[0].AnswerID, id, ok)
+	}
+}
+
+func TestFetchDataByIDIntSource(t *testing.T) {
+	// Without an IDSource in the chain, IDs are the decimal topic IDs
+	s := WithInfo(plain{&keyed{ids: map[int64]ID{7: "seven"}}}, SourceInfo{})
+
+	data, err := FetchDataByID(context.Background(), s, 1, IntID(7))
+	if err != nil || len(data) != 1 || data[0].AnswerID != 7 {
+		t.Fatalf("FetchDataByID(7) = %+v, %v", data, err)
+	}
+	if _, err := FetchDataByID(context.Background(), s, 1, "https://example.com/a"); !errors.Is(err, ErrBadQuery) {
+		t.Errorf("FetchDataByID with a URL = %v, want ErrBadQuery", err)
+	}
+	if id, ok := StringID(s, -3); !ok || id != "-3" {
+		t.Errorf("StringID(-3) = %q, %v", id, ok)
+	}
+}
+
+func TestIntID(t *testing.T) {
+	for _, n := range []int64{0, 7, -42, 1<<63 - 1} {
+		if got, ok := IntID(n).Int64(); !ok || got != n {
+			t.Errorf("IntID(%d).Int64() = %d, %v", n, got, ok)
+		}
+	}
+	if _, ok := ID("doi:10.1000/182").Int64(); ok {
+		t.Error("a DOI parsed as an int64 ID")
+	}
+}
+
+// plain hides the optional interfaces of the source it wraps
+type plain struct {
+	s Source
+}
+
+func (p plain) Init() error             { return p.s.Init() }
+func (p plain) CheckAvailability() bool { return p.s.CheckAvailability() }
+
+func (p plain) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return p.s.FetchTopics(count, input)
+}
+
+func (p plain) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return p.s.FetchData(count, topicID)
+}
+
+func TestFetchDataByIDContext(t *testing.T) {
+	s := &keyed{ids: map[int64]ID{}}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	if _, err := FetchDataByID(ctx, s, 1, "https://example.com/a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FetchDataByID after the deadline = %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package source
+
+import (
+	"context"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// TopicOptions are per-query search parameters. Adapters map the ones their backend supports and
+// ignore the rest; zero values keep the backend's defaults.
+type TopicOptions struct {
+	Count      int    // Topics wanted; 0 for the adapter's default
+	Language   string // ISO 639-1 code of the results' language, e.g. "de"
+	Region     string // ISO 3166-1 alpha-2 code of the region to favour, e.g. "ch"
+	SafeSearch SafeSearch
+	TimeRange  TimeRange
+}
+
+// SafeSearch is how strictly explicit results are filtered
+type SafeSearch int
+
+const (
+	SafeDefault  SafeSearch = iota // The backend's default
+	SafeOff                        // No filtering
+	SafeModerate                   // Filter explicit images and video
+	SafeStrict                     // Filter all explicit results
+)
+
+// TimeRange limits results to those published or changed recently
+type TimeRange string
+
+const (
+	AnyTime   TimeRange = ""
+	PastDay   TimeRange = "day"
+	PastWeek  TimeRange = "week"
+	PastMonth TimeRange = "month"
+	PastYear  TimeRange = "year"
+)
+
+// Since returns the start of the range ending at now; zero for AnyTime or an unknown range
+func (r TimeRange) Since(now time.Time) time.Time {
+	switch r {
+	case PastDay:
+		return now.AddDate(0, 0, -1)
+	case PastWeek:
+		return now.AddDate(0, 0, -7)
+	case PastMonth:
+		return now.AddDate(0, -1, 0)
+	case PastYear:
+		return now.AddDate(-1, 0, 0)
+	}
+	return time.Time{}
+}
+
+// OptionsSource is implemented by sources that take per-query options
+type OptionsSource interface {
+	Source
+	FetchTopicsWithOptions(ctx context.Context, query string, opts TopicOptions) ([]datasource.DataSourceTopic, error)
+}
+
+// FetchTopicsWithOptions searches s with opts when s implements OptionsSource. Other sources are
+// searched with FetchTopicsContext for opts.Count topics and the remaining options are ignored.
+func FetchTopicsWithOptions(ctx context.Context, s Source, query string, opts TopicOptions) ([]datasource.DataSourceTopic, error) {
+	if o, ok := s.(OptionsSource); ok {
+		return o.FetchTopicsWithOptions(ctx, query, opts)
+	}
+	return FetchTopicsContext(ctx, s, opts.Count, query)
+}
@@ -0,0 +1,88 @@
+package source
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// ErrCursor is returned for cursors a source did not issue
+var ErrCursor = errors.New("invalid page cursor")
+
+// Page is one page of search results
+type Page struct {
+	Topics []datasource.DataSourceTopic
+	Next   string // Opaque cursor for the following page; empty on the last page
+}
+
+// Pager is implemented by sources that can continue a search where a previous page ended
+type Pager interface {
+	Source
+	// FetchTopicsPage returns the page of results for query starting at cursor; an empty cursor starts
+	// at the first result. opts.Count is the page size.
+	FetchTopicsPage(ctx context.Context, query string, opts TopicOptions, cursor string) (Page, error)
+}
+
+// FetchTopicsPage pages through s's results for query. Sources that do not implement Pager are searched
+// again for every page, for the results up to the end of the page, and the page is cut from them; they
+// return a single page when opts.Count is 0 and the last page once they return fewer results than asked.
+func FetchTopicsPage(ctx context.Context, s Source, query string, opts TopicOptions, cursor string) (Page, error) {
+	if p, ok := s.(Pager); ok {
+		return p.FetchTopicsPage(ctx, query, opts, cursor)
+	}
+	offset, err := ParseOffsetCursor(cursor)
+	if err != nil {
+		return Page{}, err
+	}
+	if opts.Count <= 0 {
+		if offset > 0 {
+			return Page{}, nil
+		}
+		topics, err := FetchTopicsWithOptions(ctx, s, query, opts)
+		return Page{Topics: topics}, err
+	}
+	size := opts.Count
+	opts.Count = offset + size
+	topics, err := FetchTopicsWithOptions(ctx, s, query, opts)
+	if err != nil {
+		return Page{}, err
+	}
+	if offset >= len(topics) {
+		return Page{}, nil
+	}
+	page := Page{Topics: topics[offset:]}
+	if len(topics) >= offset+size {
+		page.Topics = page.Topics[:size]
+		page.Next = OffsetCursor(offset + size)
+	}
+	return page, nil
+}
+
+// OffsetCursor returns a cursor for sources that page by result offset; ParseOffsetCursor reads it
+func OffsetCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+// ParseOffsetCursor returns the offset of a cursor from OffsetCursor; 0 for an empty cursor
+func ParseOffsetCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrCursor
+	}
+	value, ok := strings.CutPrefix(string(raw), "offset:")
+	if !ok {
+		return 0, ErrCursor
+	}
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, ErrCursor
+	}
+	return offset, nil
+}
@@ -0,0 +1,31 @@
+package source
+
+import (
+	"context"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// RelatedSource is implemented by sources that can suggest topics next to one they returned, for
+// exploring from a topic the user picked
+type RelatedSource interface {
+	// Related returns up to count topics related to topicID, not including it; FetchData accepts their
+	// IDs
+	Related(ctx context.Context, topicID int64, count int) ([]datasource.DataSourceTopic, error)
+}
+
+// Related returns topics related to topicID from s, or from the first source it wraps that implements
+// RelatedSource; nil when none does
+func Related(ctx context.Context, s Source, topicID int64, count int) ([]datasource.DataSourceTopic, error) {
+	for s != nil {
+		if r, ok := s.(RelatedSource); ok {
+			return r.Related(ctx, topicID, count)
+		}
+		w, ok := s.(Wrapper)
+		if !ok {
+			break
+		}
+		s = w.Unwrap()
+	}
+	return nil, nil
+}
@@ -0,0 +1,243 @@
+// Package source defines the method set shared by every adapter in this
+// repository, so decorators and combinators can wrap any of them. It mirrors
+// datasource.DataSource from the SDK, except that the adapters take the
+// search input as plain text.
+package source
+
+import (
+	"context"
+	"io"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// Source is implemented by every DataSourceX adapter and by the wrappers in this repository
+type Source interface {
+	Init() error
+	CheckAvailability() bool
+	FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error)
+	FetchData(count int, topicID int64) ([]datasource.DataSourceData, error)
+}
+
+// ContextSource is implemented by sources whose fetches take the caller's context, so searches can be
+// cancelled and given deadlines from outside. FetchTopics and FetchData then apply the adapter's default
+// deadline to a background context.
+type ContextSource interface {
+	Source
+	FetchTopicsContext(ctx context.Context, count int, input string) ([]datasource.DataSourceTopic, error)
+	FetchDataContext(ctx context.Context, count int, topicID int64) ([]datasource.DataSourceData, error)
+}
+
+// FetchTopicsContext searches s with ctx when s implements ContextSource. Otherwise s.FetchTopics runs
+// in the background and ctx's error is returned if ctx ends first; the abandoned call finishes within
+// the adapter's own deadline.
+func FetchTopicsContext(ctx context.Context, s Source, count int, input string) ([]datasource.DataSourceTopic, error) {
+	if c, ok := s.(ContextSource); ok {
+		return c.FetchTopicsContext(ctx, count, input)
+	}
+	return await(ctx, func() ([]datasource.DataSourceTopic, error) {
+		return s.FetchTopics(count, input)
+	})
+}
+
+// FetchDataContext is FetchTopicsContext for FetchData
+func FetchDataContext(ctx context.Context, s Source, count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if c, ok := s.(ContextSource); ok {
+		return c.FetchDataContext(ctx, count, topicID)
+	}
+	return await(ctx, func() ([]datasource.DataSourceData, error) {
+		return s.FetchData(count, topicID)
+	})
+}
+
+// await runs call and returns its result, or ctx's error if ctx ends first. Without a deadline or
+// cancellation call runs in place; otherwise a panic in call is raised again in the caller.
+func await[T any](ctx context.Context, call func() (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	if ctx.Done() == nil {
+		return call()
+	}
+	type outcome struct {
+		value T
+		err   error
+		panic any
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		var o outcome
+		defer func() {
+			o.panic = recover()
+			done <- o
+		}()
+		o.value, o.err = call()
+	}()
+	select {
+	case o := <-done:
+		if o.panic != nil {
+			panic(o.panic)
+		}
+		return o.value, o.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Freshness is implemented by sources that know when a topic's content last changed, from file
+// modification times, object metadata or sitemap lastmod dates
+type Freshness interface {
+	// LastModified returns when the topic's content last changed, or the zero time when unknown
+	LastModified(topicID int64) time.Time
+}
+
+// Wrapper is implemented by decorators around a single source, so optional interfaces like Freshness
+// can be found on the source they wrap
+type Wrapper interface {
+	Unwrap() Source
+}
+
+// Closer is implemented by sources that hold resources, such as idle connections, caches or API
+// sessions, for a long-running host to release when it shuts down
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// Close releases the resources of s, or of the first source it wraps that implements Closer or
+// io.Closer; combinators close all their members. Sources without resources need no Close.
+func Close(ctx context.Context, s Source) error {
+	for s != nil {
+		switch c := s.(type) {
+		case Closer:
+			return c.Close(ctx)
+		case io.Closer:
+			return c.Close()
+		}
+		w, ok := s.(Wrapper)
+		if !ok {
+			break
+		}
+		s = w.Unwrap()
+	}
+	return nil
+}
+
+// LastModified returns when s, or the first source it wraps that implements Freshness, reports the
+// topic last changed; the zero time when none does
+func LastModified(s Source, topicID int64) time.Time {
+	for s != nil {
+		if f, ok := s.(Freshness); ok {
+			return f.LastModified(topicID)
+		}
+		w, ok := s.(Wrapper)
+		if !ok {
+			break
+		}
+		s = w.Unwrap()
+	}
+	return time.Time{}
+}
+
+// Walled is implemented by sources that fetch web pages and detect paywalls and consent walls
+type Walled interface {
+	// Wall returns extract.Paywall or extract.ConsentWall when the topic's page was last fetched from
+	// behind one, or "" when it was not or is unknown
+	Wall(topicID int64) string
+}
+
+// Wall returns what s, or the first source it wraps that implements Walled, reports for the topic
+func Wall(s Source, topicID int64) string {
+	for s != nil {
+		if w, ok := s.(Walled); ok {
+			return w.Wall(topicID)
+		}
+		w, ok := s.(Wrapper)
+		if !ok {
+			break
+		}
+		s = w.Unwrap()
+	}
+	return ""
+}
+
+// Archive fetches an archived copy of a page, such as wayback.DataSourceWayback, for adapters to fall
+// back to when the live page is walled
+type Archive interface {
+	FetchArchived(ctx context.Context, pageURL string) ([]datasource.DataSourceData, error)
+}
+
+// SourceInfo says where a source's content comes from and the terms it may be reused under, so
+// consumers can show the credit a license asks for
+type SourceInfo struct {
+	Name                string `json:"name"`
+	Homepage            string `json:"homepage,omitempty"`
+	License             string `json:"license,omitempty"` // SPDX identifier or license name; empty when it varies by item or belongs to the operator
+	LicenseURL          string `json:"license_url,omitempty"`
+	Attribution         string `json:"attribution,omitempty"` // Credit line to show with the content
+	AttributionRequired bool   `json:"attribution_required,omitempty"`
+	TermsURL            string `json:"terms_url,omitempty"`
+}
+
+// Informer is implemented by adapters that describe their content's origin and license
+type Informer interface {
+	Info() SourceInfo
+}
+
+// TopicInformer is implemented by combinators whose topics come from different sources
+type TopicInformer interface {
+	// TopicInfo returns the SourceInfo of the source that found the topic; false when unknown
+	TopicInfo(topicID int64) (SourceInfo, bool)
+}
+
+// InfoFor returns the SourceInfo for a topic from s, or from the first source it wraps that implements
+// Informer or TopicInformer; false when none does
+func InfoFor(s Source, topicID int64) (SourceInfo, bool) {
+	for s != nil {
+		if i, ok := s.(TopicInformer); ok {
+			return i.TopicInfo(topicID)
+		}
+		if i, ok := s.(Informer); ok {
+			return i.Info(), true
+		}
+		w, ok := s.(Wrapper)
+		if !ok {
+			break
+		}
+		s = w.Unwrap()
+	}
+	return SourceInfo{}, false
+}
+
+// WithInfo wraps s to report info, for configurable adapters such as restjson or scrape where only the
+// operator knows the content's license
+func WithInfo(s Source, info SourceInfo) Source {
+	return informed{s, info}
+}
+
+type informed struct {
+	Source
+	info SourceInfo
+}
+
+func (s informed) Info() SourceInfo {
+	return s.info
+}
+
+func (s informed) Unwrap() Source {
+	return s.Source
+}
+
+// SDK adapts a Source to the SDK's datasource.DataSource, searching for the question text
+func SDK(s Source) datasource.DataSource {
+	return sdk{s}
+}
+
+type sdk struct {
+	Source
+}
+
+func (s sdk) FetchTopics(count int, input datasource.NewQuestionInput) ([]datasource.DataSourceTopic, error) {
+	return s.Source.FetchTopics(count, input.QuestionText)
+}
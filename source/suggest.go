@@ -0,0 +1,25 @@
+package source
+
+import "context"
+
+// Suggester is implemented by sources that complete partial queries, for search as you type
+type Suggester interface {
+	// Suggest returns up to count queries starting with, or otherwise completing, prefix
+	Suggest(ctx context.Context, prefix string, count int) ([]string, error)
+}
+
+// Suggest returns completions of prefix from s, or from the first source it wraps that implements
+// Suggester; nil when none does
+func Suggest(ctx context.Context, s Source, prefix string, count int) ([]string, error) {
+	for s != nil {
+		if sg, ok := s.(Suggester); ok {
+			return sg.Suggest(ctx, prefix, count)
+		}
+		w, ok := s.(Wrapper)
+		if !ok {
+			break
+		}
+		s = w.Unwrap()
+	}
+	return nil, nil
+}
@@ -0,0 +1,30 @@
+package source
+
+import (
+	"context"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// TrendingSource is implemented by sources that know what is popular right now, so hosts can show
+// topics without a query
+type TrendingSource interface {
+	// Trending returns up to count popular topics, most popular first; FetchData accepts their IDs
+	Trending(ctx context.Context, count int) ([]datasource.DataSourceTopic, error)
+}
+
+// Trending returns the popular topics of s, or of the first source it wraps that implements
+// TrendingSource; nil when none does
+func Trending(ctx context.Context, s Source, count int) ([]datasource.DataSourceTopic, error) {
+	for s != nil {
+		if t, ok := s.(TrendingSource); ok {
+			return t.Trending(ctx, count)
+		}
+		w, ok := s.(Wrapper)
+		if !ok {
+			break
+		}
+		s = w.Unwrap()
+	}
+	return nil, nil
+}
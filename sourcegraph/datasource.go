@@ -0,0 +1,298 @@
+package sourcegraph
+
+// Data Source Adapter for Sourcegraph code search (GraphQL API)
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount   = 5
+	defaultMaxFileBytes = 200_000
+)
+
+const searchQuery = `query Search($query: String!) {
+  search(query: $query, version: V3, patternType: standard) {
+    results {
+      results {
+        __typename
+        ... on FileMatch {
+          repository { name }
+          file { path url }
+          chunkMatches { content contentStart { line } }
+        }
+      }
+    }
+  }
+}`
+
+const fileQuery = `query File($repo: String!, $rev: String!, $path: String!) {
+  repository(name: $repo) {
+    commit(rev: $rev) {
+      file(path: $path) { content }
+    }
+  }
+}`
+
+type DataSourceSourcegraph struct {
+	Client       *http.Client
+	BaseURL      string // Instance root, e.g. https://sourcegraph.com or a private instance
+	UserAgent    string
+	Token        string // Access token; required by most private instances
+	Filter       string // Extra search filters appended to every query, e.g. "lang:go repo:^github\.com/org/"
+	MaxFileBytes int    // Files larger than this are truncated in FetchData
+
+	files topicid.Map[file]
+}
+
+// file identifies a matched file so FetchData can load its contents
+type file struct {
+	Repo string
+	Path string
+	Rev  string
+	URL  string
+}
+
+func New() *DataSourceSourcegraph {
+	return &DataSourceSourcegraph{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:      "https://sourcegraph.com",
+		UserAgent:    "locus/sourcegraph-datasource",
+		MaxFileBytes: defaultMaxFileBytes,
+	}
+}
+
+// Init implements models.DataSource
+// Sourcegraph requires no initialization beyond an instance URL
+func (es *DataSourceSourcegraph) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if strings.TrimSpace(es.BaseURL) == "" {
+		return errors.New("BaseURL is required for Sourcegraph DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceSourcegraph) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	return es.doGraphQL(ctx, `query { site { productVersion } }`, nil, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceSourcegraph) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Sourcegraph",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each file match is a topic titled "repo/path: first matching line"; the repository is reported as the Site
+func (es *DataSourceSourcegraph) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Sourcegraph DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	search := fmt.Sprintf("%s type:file count:%d", query, count)
+	if es.Filter != "" {
+		search = es.Filter + " " + search
+	}
+
+	var response struct {
+		Search struct {
+			Results struct {
+				Results []struct {
+					Typename   string `json:"__typename"`
+					Repository struct {
+						Name string `json:"name"`
+					} `json:"repository"`
+					File struct {
+						Path string `json:"path"`
+						URL  string `json:"url"`
+					} `json:"file"`
+					ChunkMatches []struct {
+						Content      string `json:"content"`
+						ContentStart struct {
+							Line int `json:"line"`
+						} `json:"contentStart"`
+					} `json:"chunkMatches"`
+				} `json:"results"`
+			} `json:"results"`
+		} `json:"search"`
+	}
+	if err := es.doGraphQL(ctx, searchQuery, map[string]interface{}{"query": search}, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, match := range response.Search.Results.Results {
+		if len(results) >= count {
+			break
+		}
+		if match.Typename != "FileMatch" {
+			continue
+		}
+		link := strings.TrimRight(es.BaseURL, "/") + match.File.URL
+		title := match.Repository.Name + "/" + match.File.Path
+		if len(match.ChunkMatches) > 0 {
+			chunk := match.ChunkMatches[0]
+			line := strings.TrimSpace(strings.SplitN(chunk.Content, "\n", 2)[0])
+			if line != "" {
+				title = fmt.Sprintf("%s:%d: %s", title, chunk.ContentStart.Line+1, line)
+			}
+			link = fmt.Sprintf("%s?L%d", link, chunk.ContentStart.Line+1)
+		}
+		id := es.files.Put(match.Repository.Name+"/"+match.File.Path, file{
+			Repo: match.Repository.Name,
+			Path: match.File.Path,
+			Rev:  revisionFromURL(match.File.URL),
+			URL:  strings.TrimRight(es.BaseURL, "/") + match.File.URL,
+		})
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     title,
+			SourceURL: link,
+			Site:      match.Repository.Name,
+			TopicID:   id,
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the full contents of the matched file as a single data item
+func (es *DataSourceSourcegraph) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	target, ok := es.files.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Sourcegraph topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var response struct {
+		Repository *struct {
+			Commit *struct {
+				File *struct {
+					Content string `json:"content"`
+				} `json:"file"`
+			} `json:"commit"`
+		} `json:"repository"`
+	}
+	variables := map[string]interface{}{"repo": target.Repo, "rev": target.Rev, "path": target.Path}
+	if err := es.doGraphQL(ctx, fileQuery, variables, &response); err != nil {
+		return nil, err
+	}
+	if response.Repository == nil || response.Repository.Commit == nil || response.Repository.Commit.File == nil {
+		return []datasource.DataSourceData{}, nil
+	}
+
+	content := response.Repository.Commit.File.Content
+	limit := es.MaxFileBytes
+	if limit <= 0 {
+		limit = defaultMaxFileBytes
+	}
+	if len(content) > limit {
+		content = content[:limit] + "\n… (truncated)"
+	}
+	return []datasource.DataSourceData{{
+		DataText:  content,
+		SourceURL: target.URL,
+		Site:      target.Repo,
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// doGraphQL posts a GraphQL request to the Sourcegraph API and decodes its data member into target
+func (es *DataSourceSourcegraph) doGraphQL(ctx context.Context, query string, variables map[string]interface{}, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	payload, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(es.BaseURL, "/")+"/.api/graphql", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if es.Token != "" {
+		req.Header.Set("Authorization", "token "+es.Token)
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("sourcegraph request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return err
+	}
+	if len(envelope.Errors) > 0 {
+		return fmt.Errorf("sourcegraph error: %s", envelope.Errors[0].Message)
+	}
+	return json.Unmarshal(envelope.Data, target)
+}
+
+// Helpers
+
+// revisionFromURL extracts the revision from a file URL such as "/repo@rev/-/blob/path", defaulting to HEAD
+func revisionFromURL(fileURL string) string {
+	prefix, _, found := strings.Cut(fileURL, "/-/blob/")
+	if !found {
+		return "HEAD"
+	}
+	if i := strings.LastIndex(prefix, "@"); i >= 0 {
+		return prefix[i+1:]
+	}
+	return "HEAD"
+}
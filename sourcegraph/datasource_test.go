@@ -0,0 +1,95 @@
+package sourcegraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.api/graphql" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		if r.Header.Get("Authorization") != "token secret" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		switch {
+		case strings.HasPrefix(req.Query, "query Search"):
+			if req.Variables["query"] != "lang:go http.Handler type:file count:2" {
+				t.Errorf("search = %q", req.Variables["query"])
+			}
+			fmt.Fprint(w, `{"data":{"search":{"results":{"results":[
+				{"__typename":"Repository"},
+				{"__typename":"FileMatch","repository":{"name":"github.com/a/b"},"file":{"path":"server.go","url":"/github.com/a/b@v1.2/-/blob/server.go"},
+				 "chunkMatches":[{"content":"  var h http.Handler\nmore","contentStart":{"line":9}}]}
+			]}}}}`)
+		case strings.HasPrefix(req.Query, "query File"):
+			if req.Variables["repo"] != "github.com/a/b" || req.Variables["rev"] != "v1.2" || req.Variables["path"] != "server.go" {
+				t.Errorf("file variables = %v", req.Variables)
+			}
+			fmt.Fprint(w, `{"data":{"repository":{"commit":{"file":{"content":"package b\n\nvar h http.Handler\n"}}}}}`)
+		default:
+			fmt.Fprint(w, `{"errors":[{"message":"unknown query"}]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.Token = "secret"
+	es.Filter = "lang:go"
+	es.MaxFileBytes = 12
+
+	topics, err := es.FetchTopics(2, "http.Handler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "github.com/a/b/server.go:10: var h http.Handler" ||
+		topics[0].SourceURL != srv.URL+"/github.com/a/b@v1.2/-/blob/server.go?L10" || topics[0].Site != "github.com/a/b" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].DataText != "package b\n\nv\n… (truncated)" {
+		t.Errorf("FetchData = %+v", data)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.Token = "secret"
+	if err := es.doGraphQL(t.Context(), "query Other", nil, &struct{}{}); err == nil || !strings.Contains(err.Error(), "unknown query") {
+		t.Errorf("doGraphQL = %v, want the GraphQL error", err)
+	}
+}
+
+func TestRevisionFromURL(t *testing.T) {
+	for in, want := range map[string]string{
+		"/github.com/a/b@v1.2/-/blob/x.go": "v1.2",
+		"/github.com/a/b/-/blob/x.go":      "HEAD",
+		"/github.com/a/b":                  "HEAD",
+	} {
+		if got := revisionFromURL(in); got != want {
+			t.Errorf("revisionFromURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+package sparql
+
+// Data Source Adapter for any SPARQL 1.1 endpoint (Wikidata, DBpedia, institutional triple stores)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+// Template placeholders
+const (
+	// PlaceholderQuery is replaced with the search input as an escaped string literal, quotes included
+	PlaceholderQuery = "{{query}}"
+	// PlaceholderLimit is replaced with the requested topic count
+	PlaceholderLimit = "{{limit}}"
+	// PlaceholderTopic is replaced with the topic's ID binding: an IRI in angle brackets, or a string literal
+	PlaceholderTopic = "{{topic}}"
+)
+
+// Mapping names the result variables that make up topics and data. Variables are written without the "?".
+type Mapping struct {
+	ID    string // Identifies a topic; its value is substituted for {{topic}} in the data query
+	Title string // Topic title
+	URL   string // Source URL; defaults to the ID when that is an IRI
+	Site  string // Optional variable for the topic's Site
+	// Detail is an optional variable appended to the title in parentheses, e.g. a description
+	Detail string
+	// Row formats each data row, with {{var}} placeholders; by default every bound variable is
+	// written as "var: value"
+	Row string
+}
+
+type DataSourceSPARQL struct {
+	Client      *http.Client
+	Endpoint    string
+	UserAgent   string
+	Headers     map[string]string // Extra request headers, e.g. Authorization for private stores
+	TopicQuery  string            // SELECT query with {{query}} and {{limit}}
+	DataQuery   string            // SELECT query with {{topic}}; rows become data lines
+	Mapping     Mapping
+	Site        string // Site for topics and data when Mapping.Site is not set
+	RowsPerItem int    // Data rows grouped into one item; 0 puts all rows in a single item
+
+	topics topicid.Map[topic]
+}
+
+type topic struct {
+	Title string
+	Term  string // The ID binding rendered as a SPARQL term
+	URL   string
+	Site  string
+}
+
+// Binding is one variable's value in the SPARQL JSON results format
+type Binding struct {
+	Type  string `json:"type"` // "uri", "literal", "typed-literal" or "bnode"
+	Value string `json:"value"`
+}
+
+// New returns an adapter with no endpoint; set Endpoint, the queries and Mapping, or use NewWikidata
+func New() *DataSourceSPARQL {
+	return &DataSourceSPARQL{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		UserAgent: "locus/sparql-datasource",
+	}
+}
+
+// NewWikidata returns an adapter preconfigured for the Wikidata Query Service: entities are found with
+// the EntitySearch API and their direct claims are returned as "property: value" lines
+func NewWikidata() *DataSourceSPARQL {
+	es := New()
+	es.Endpoint = "https://query.wikidata.org/sparql"
+	es.Site = "wikidata.org"
+	es.TopicQuery = `SELECT ?item ?itemLabel ?itemDescription WHERE {
+  SERVICE wikibase:mwapi {
+    bd:serviceParam wikibase:endpoint "www.wikidata.org";
+                    wikibase:api "EntitySearch";
+                    mwapi:search {{query}};
+                    mwapi:language "en".
+    ?item wikibase:apiOutputItem mwapi:item.
+  }
+  SERVICE wikibase:label { bd:serviceParam wikibase:language "en". }
+} LIMIT {{limit}}`
+	es.DataQuery = `SELECT ?propertyLabel ?valueLabel WHERE {
+  {{topic}} ?claim ?value.
+  ?property wikibase:directClaim ?claim.
+  SERVICE wikibase:label { bd:serviceParam wikibase:language "en". }
+} LIMIT 300`
+	es.Mapping = Mapping{
+		ID:     "item",
+		Title:  "itemLabel",
+		Detail: "itemDescription",
+		Row:    "{{propertyLabel}}: {{valueLabel}}",
+	}
+	return es
+}
+
+// Init implements models.DataSource
+// Validates that an endpoint, both queries and the ID and title variables are configured
+func (es *DataSourceSPARQL) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.Endpoint == "" {
+		return errors.New("Endpoint is required for SPARQL DataSource")
+	}
+	if !strings.Contains(es.TopicQuery, PlaceholderQuery) {
+		return fmt.Errorf("TopicQuery must contain %s", PlaceholderQuery)
+	}
+	if !strings.Contains(es.DataQuery, PlaceholderTopic) {
+		return fmt.Errorf("DataQuery must contain %s", PlaceholderTopic)
+	}
+	if es.Mapping.ID == "" || es.Mapping.Title == "" {
+		return errors.New("Mapping.ID and Mapping.Title are required for SPARQL DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceSPARQL) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.Select(ctx, "ASK { ?s ?p ?o }")
+	// ASK returns a boolean rather than bindings; any decodable response means the endpoint is up
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceSPARQL) Info() source.SourceInfo {
+	if strings.Contains(es.Endpoint, "wikidata.org") {
+		return source.SourceInfo{
+			Name:        "Wikidata",
+			Homepage:    "https://www.wikidata.org",
+			License:     "CC0-1.0",
+			LicenseURL:  "https://creativecommons.org/publicdomain/zero/1.0/",
+			Attribution: "Wikidata",
+			TermsURL:    "https://foundation.wikimedia.org/wiki/Policy:Terms_of_Use",
+		}
+	}
+	return source.SourceInfo{
+		Name:     "SPARQL",
+		Homepage: es.Endpoint,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Each row of TopicQuery is a topic titled with the Title variable and, when mapped, "(Detail)"
+func (es *DataSourceSPARQL) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for SPARQL DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	sparql := strings.NewReplacer(
+		PlaceholderQuery, literal(query),
+		PlaceholderLimit, strconv.Itoa(count),
+	).Replace(es.TopicQuery)
+	rows, err := es.Select(ctx, sparql)
+	if err != nil {
+		return nil, err
+	}
+
+	m := es.Mapping
+	seen := map[string]bool{}
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, row := range rows {
+		if len(results) >= count {
+			break
+		}
+		id, ok := row[m.ID]
+		if !ok || seen[id.Value] {
+			continue
+		}
+		seen[id.Value] = true
+		t := topic{Title: row[m.Title].Value, Term: term(id), Site: es.Site}
+		if t.Title == "" {
+			t.Title = id.Value
+		}
+		if detail := row[m.Detail].Value; m.Detail != "" && detail != "" {
+			t.Title += " (" + detail + ")"
+		}
+		if m.URL != "" {
+			t.URL = row[m.URL].Value
+		} else if id.Type == "uri" {
+			t.URL = id.Value
+		}
+		if m.Site != "" && row[m.Site].Value != "" {
+			t.Site = row[m.Site].Value
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     t.Title,
+			SourceURL: t.URL,
+			Site:      t.Site,
+			TopicID:   es.topics.Put(es.Endpoint+"#"+id.Value, t),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Runs DataQuery for the topic and formats each row with Mapping.Row, grouped RowsPerItem to an item
+func (es *DataSourceSPARQL) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	t, ok := es.topics.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown SPARQL topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	rows, err := es.Select(ctx, strings.ReplaceAll(es.DataQuery, PlaceholderTopic, t.Term))
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if line := es.formatRow(row); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	size := es.RowsPerItem
+	if size <= 0 {
+		size = len(lines)
+	}
+	var results []datasource.DataSourceData
+	for start := 0; start < len(lines); start += size {
+		if count > 0 && len(results) >= count {
+			break
+		}
+		end := start + size
+		if end > len(lines) {
+			end = len(lines)
+		}
+		answerID := topicID
+		if start > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%d#%d", topicID, start))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  t.Title + "\n" + strings.Join(lines[start:end], "\n"),
+			SourceURL: t.URL,
+			Site:      t.Site,
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// Select runs a query against the endpoint and returns the result rows keyed by variable name.
+// It is exported for callers that need raw bindings alongside the adapter interface.
+func (es *DataSourceSPARQL) Select(ctx context.Context, query string) ([]map[string]Binding, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	form := url.Values{}
+	form.Set("query", query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, es.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/sparql-results+json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+	for key, value := range es.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("sparql request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var response struct {
+		Head struct {
+			Vars []string `json:"vars"`
+		} `json:"head"`
+		Results struct {
+			Bindings []map[string]Binding `json:"bindings"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return response.Results.Bindings, nil
+}
+
+// formatRow renders a data row with Mapping.Row, or as "var: value" pairs when no format is set.
+// Rows whose placeholders are all unbound are dropped.
+func (es *DataSourceSPARQL) formatRow(row map[string]Binding) string {
+	if es.Mapping.Row == "" {
+		names := make([]string, 0, len(row))
+		for name := range row {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parts := make([]string, 0, len(names))
+		for _, name := range names {
+			parts = append(parts, name+": "+row[name].Value)
+		}
+		return strings.Join(parts, "; ")
+	}
+	line := es.Mapping.Row
+	bound := false
+	for name, value := range row {
+		placeholder := "{{" + name + "}}"
+		if strings.Contains(line, placeholder) {
+			line = strings.ReplaceAll(line, placeholder, value.Value)
+			bound = true
+		}
+	}
+	if !bound {
+		return ""
+	}
+	return line
+}
+
+// Helpers
+
+// literal quotes s as a SPARQL string literal
+func literal(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(s) + `"`
+}
+
+// term renders a binding for substitution into a query
+func term(b Binding) string {
+	if b.Type == "uri" {
+		return "<" + b.Value + ">"
+	}
+	return literal(b.Value)
+}
@@ -0,0 +1,116 @@
+package sparql
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Accept") != "application/sparql-results+json" || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("unexpected request %s %v", r.Method, r.Header)
+		}
+		query := r.PostFormValue("query")
+		switch {
+		case strings.Contains(query, `mwapi:search "Douglas \"DNA\" Adams"`):
+			if !strings.Contains(query, "LIMIT 5") {
+				t.Errorf("unexpected query %s", query)
+			}
+			fmt.Fprint(w, `{"head":{"vars":["item","itemLabel","itemDescription"]},"results":{"bindings":[
+				{"item":{"type":"uri","value":"http://www.wikidata.org/entity/Q42"},"itemLabel":{"type":"literal","value":"Douglas Adams"},"itemDescription":{"type":"literal","value":"English writer"}},
+				{"item":{"type":"uri","value":"http://www.wikidata.org/entity/Q42"},"itemLabel":{"type":"literal","value":"Douglas Adams"}},
+				{"item":{"type":"uri","value":"http://www.wikidata.org/entity/Q1"}}
+			]}}`)
+		case strings.Contains(query, "<http://www.wikidata.org/entity/Q42> ?claim ?value."):
+			fmt.Fprint(w, `{"head":{"vars":["propertyLabel","valueLabel"]},"results":{"bindings":[
+				{"propertyLabel":{"type":"literal","value":"occupation"},"valueLabel":{"type":"literal","value":"novelist"}},
+				{"other":{"type":"literal","value":"unmapped"}},
+				{"propertyLabel":{"type":"literal","value":"occupation"},"valueLabel":{"type":"literal","value":"screenwriter"}},
+				{"propertyLabel":{"type":"literal","value":"date of birth"},"valueLabel":{"type":"literal","value":"1952-03-11"}}
+			]}}`)
+		default:
+			t.Errorf("unexpected query %s", query)
+			http.Error(w, "bad query", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := NewWikidata()
+	es.Endpoint = srv.URL
+	es.Headers = map[string]string{"Authorization": "Bearer token"}
+	es.RowsPerItem = 2
+
+	topics, err := es.FetchTopics(5, `Douglas "DNA" Adams`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Douglas Adams (English writer)", "http://www.wikidata.org/entity/Q1"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+	if topics[0].SourceURL != "http://www.wikidata.org/entity/Q42" || topics[0].Site != "wikidata.org" {
+		t.Errorf("topic 0 = %+v", topics[0])
+	}
+
+	data, err := es.FetchData(0, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := []string{
+		"Douglas Adams (English writer)\noccupation: novelist\noccupation: screenwriter",
+		"Douglas Adams (English writer)\ndate of birth: 1952-03-11",
+	}
+	if len(data) != len(wantData) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range wantData {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+	if data[0].AnswerID != topics[0].TopicID || data[1].AnswerID == topics[0].TopicID {
+		t.Errorf("AnswerIDs = %d, %d", data[0].AnswerID, data[1].AnswerID)
+	}
+}
+
+func TestFormatRowDefault(t *testing.T) {
+	es := New()
+	row := map[string]Binding{"b": {Value: "2"}, "a": {Value: "1"}}
+	if got := es.formatRow(row); got != "a: 1; b: 2" {
+		t.Errorf("formatRow = %q", got)
+	}
+}
+
+func TestTerm(t *testing.T) {
+	tests := map[Binding]string{
+		{Type: "uri", Value: "http://example.org/a"}:  "<http://example.org/a>",
+		{Type: "literal", Value: "line\none \"two\""}: `"line\none \"two\""`,
+		{Type: "literal", Value: `back\slash`}:        `"back\\slash"`,
+	}
+	for b, want := range tests {
+		if got := term(b); got != want {
+			t.Errorf("term(%+v) = %s, want %s", b, got, want)
+		}
+	}
+}
+
+func TestInit(t *testing.T) {
+	es := NewWikidata()
+	es.DataQuery = "SELECT * WHERE { ?s ?p ?o }"
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted a DataQuery without " + PlaceholderTopic)
+	}
+}
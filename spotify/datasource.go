@@ -0,0 +1,497 @@
+package spotify
+
+// Data Source Adapter for the Spotify Web API catalog (client credentials flow)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/auth"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	listSize          = 15
+)
+
+// pitchClasses names Spotify's integer key notation
+var pitchClasses = []string{"C", "C♯/D♭", "D", "D♯/E♭", "E", "F", "F♯/G♭", "G", "G♯/A♭", "A", "A♯/B♭", "B"}
+
+type DataSourceSpotify struct {
+	Client       *http.Client
+	BaseURL      string
+	AuthURL      string
+	ClientID     string
+	ClientSecret string
+	UserAgent    string
+	Types        []string // Any of "track", "album", "artist", "playlist"
+	Market       string   // ISO 3166-1 country used for availability and top tracks, e.g. "US"
+
+	credentials *auth.ClientCredentials
+	items       topicid.Map[item]
+}
+
+// item is a catalog object reference
+type item struct {
+	Type string
+	ID   string
+	URL  string
+}
+
+// named is the shape shared by artists, albums and owners in API responses
+type named struct {
+	Name string `json:"name"`
+}
+
+func New() *DataSourceSpotify {
+	return &DataSourceSpotify{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://api.spotify.com/v1",
+		AuthURL:   "https://accounts.spotify.com/api/token",
+		UserAgent: "locus/spotify-datasource",
+		Types:     []string{"track", "album", "artist"},
+		Market:    "US",
+	}
+}
+
+// Init implements models.DataSource
+// Sets up client credentials; the first token is requested lazily
+func (es *DataSourceSpotify) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.ClientID == "" || es.ClientSecret == "" {
+		return errors.New("ClientID and ClientSecret are required for Spotify DataSource")
+	}
+	if es.credentials == nil {
+		es.credentials = &auth.ClientCredentials{
+			Client:       es.Client,
+			TokenURL:     es.AuthURL,
+			ClientID:     es.ClientID,
+			ClientSecret: es.ClientSecret,
+		}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceSpotify) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	params := url.Values{}
+	params.Set("limit", "1")
+	return es.doJSON(ctx, "/browse/categories", params, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceSpotify) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "Spotify",
+		Homepage:            "https://open.spotify.com",
+		Attribution:         "Content from Spotify",
+		AttributionRequired: true,
+		TermsURL:            "https://developer.spotify.com/terms",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Results of each configured type are interleaved; topics read "Song — Artist (track, 2019)"
+func (es *DataSourceSpotify) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Spotify DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+	types := es.Types
+	if len(types) == 0 {
+		types = []string{"track"}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("type", strings.Join(types, ","))
+	params.Set("limit", strconv.Itoa(count))
+	if es.Market != "" {
+		params.Set("market", es.Market)
+	}
+	type result struct {
+		ID      string  `json:"id"`
+		Name    string  `json:"name"`
+		Artists []named `json:"artists"`
+		Owner   *struct {
+			DisplayName string `json:"display_name"`
+		} `json:"owner"`
+		Album *struct {
+			ReleaseDate string `json:"release_date"`
+		} `json:"album"`
+		ReleaseDate  string `json:"release_date"`
+		ExternalURLs struct {
+			Spotify string `json:"spotify"`
+		} `json:"external_urls"`
+	}
+	type page struct {
+		// Playlist pages can contain null entries
+		Items []*result `json:"items"`
+	}
+	var response map[string]page
+	if err := es.doJSON(ctx, "/search", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for i := 0; len(results) < count; i++ {
+		added := false
+		for _, kind := range types {
+			items := response[kind+"s"].Items
+			if i >= len(items) || len(results) >= count {
+				continue
+			}
+			added = true
+			r := items[i]
+			if r == nil {
+				continue
+			}
+			var details []string
+			if len(r.Artists) > 0 {
+				details = append(details, joinNames(r.Artists))
+			}
+			if r.Owner != nil && r.Owner.DisplayName != "" {
+				details = append(details, "by "+r.Owner.DisplayName)
+			}
+			label := kind
+			date := r.ReleaseDate
+			if r.Album != nil {
+				date = r.Album.ReleaseDate
+			}
+			if len(date) >= 4 {
+				label += ", " + date[:4]
+			}
+			title := r.Name
+			if len(details) > 0 {
+				title += " — " + strings.Join(details, " ")
+			}
+			ref := item{Type: kind, ID: r.ID, URL: r.ExternalURLs.Spotify}
+			results = append(results, datasource.DataSourceTopic{
+				Topic:     fmt.Sprintf("%s (%s)", title, label),
+				SourceURL: ref.URL,
+				Site:      kind,
+				TopicID:   es.items.Put(kind+":"+r.ID, ref),
+			})
+		}
+		if !added {
+			break
+		}
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Tracks return catalog details and, where the API still offers them, audio features.
+// Albums, artists and playlists return a summary followed by their track listing or top tracks.
+func (es *DataSourceSpotify) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	ref, ok := es.items.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Spotify topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var texts []string
+	var err error
+	switch ref.Type {
+	case "track":
+		texts, err = es.trackData(ctx, ref.ID)
+	case "album":
+		texts, err = es.albumData(ctx, ref.ID)
+	case "artist":
+		texts, err = es.artistData(ctx, ref.ID)
+	case "playlist":
+		texts, err = es.playlistData(ctx, ref.ID)
+	default:
+		err = fmt.Errorf("unsupported Spotify type %q", ref.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceData, 0, len(texts))
+	for i, text := range texts {
+		if count > 0 && len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  text,
+			SourceURL: ref.URL,
+			Site:      ref.Type,
+			AnswerID:  topicid.Hash(fmt.Sprintf("%s:%s#%d", ref.Type, ref.ID, i)),
+		})
+	}
+	return results, nil
+}
+
+func (es *DataSourceSpotify) trackData(ctx context.Context, id string) ([]string, error) {
+	var track struct {
+		Name       string  `json:"name"`
+		Artists    []named `json:"artists"`
+		DurationMS int64   `json:"duration_ms"`
+		Explicit   bool    `json:"explicit"`
+		Popularity int     `json:"popularity"`
+		Album      struct {
+			Name        string `json:"name"`
+			ReleaseDate string `json:"release_date"`
+		} `json:"album"`
+	}
+	if err := es.doJSON(ctx, "/tracks/"+url.PathEscape(id), es.marketParams(), &track); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s by %s, from %s (%s)", track.Name, joinNames(track.Artists), track.Album.Name, track.Album.ReleaseDate)
+	fmt.Fprintf(&b, "\nDuration: %s", formatDuration(track.DurationMS))
+	fmt.Fprintf(&b, "\nPopularity: %d/100", track.Popularity)
+	if track.Explicit {
+		b.WriteString("\nExplicit")
+	}
+
+	// Audio features are closed to apps registered after November 2024; the catalog data stands alone
+	var features struct {
+		Danceability     float64 `json:"danceability"`
+		Energy           float64 `json:"energy"`
+		Valence          float64 `json:"valence"`
+		Acousticness     float64 `json:"acousticness"`
+		Instrumentalness float64 `json:"instrumentalness"`
+		Tempo            float64 `json:"tempo"`
+		Key              int     `json:"key"`
+		Mode             int     `json:"mode"`
+		TimeSignature    int     `json:"time_signature"`
+	}
+	if err := es.doJSON(ctx, "/audio-features/"+url.PathEscape(id), nil, &features); err == nil && features.Tempo > 0 {
+		key := "unknown key"
+		if features.Key >= 0 && features.Key < len(pitchClasses) {
+			mode := "minor"
+			if features.Mode == 1 {
+				mode = "major"
+			}
+			key = pitchClasses[features.Key] + " " + mode
+		}
+		fmt.Fprintf(&b, "\nAudio features: %.0f BPM in %s, %d/4 time; danceability %.2f, energy %.2f, valence %.2f, acousticness %.2f, instrumentalness %.2f",
+			features.Tempo, key, features.TimeSignature, features.Danceability, features.Energy, features.Valence, features.Acousticness, features.Instrumentalness)
+	}
+	return []string{b.String()}, nil
+}
+
+func (es *DataSourceSpotify) albumData(ctx context.Context, id string) ([]string, error) {
+	var album struct {
+		Name        string   `json:"name"`
+		AlbumType   string   `json:"album_type"`
+		Artists     []named  `json:"artists"`
+		ReleaseDate string   `json:"release_date"`
+		Label       string   `json:"label"`
+		TotalTracks int      `json:"total_tracks"`
+		Popularity  int      `json:"popularity"`
+		Genres      []string `json:"genres"`
+		Tracks      struct {
+			Items []struct {
+				TrackNumber int     `json:"track_number"`
+				Name        string  `json:"name"`
+				DurationMS  int64   `json:"duration_ms"`
+				Artists     []named `json:"artists"`
+			} `json:"items"`
+		} `json:"tracks"`
+	}
+	if err := es.doJSON(ctx, "/albums/"+url.PathEscape(id), es.marketParams(), &album); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s by %s (%s, released %s)", album.Name, joinNames(album.Artists), album.AlbumType, album.ReleaseDate)
+	if album.Label != "" {
+		b.WriteString("\nLabel: " + album.Label)
+	}
+	fmt.Fprintf(&b, "\nTracks: %d\nPopularity: %d/100", album.TotalTracks, album.Popularity)
+	if len(album.Genres) > 0 {
+		b.WriteString("\nGenres: " + strings.Join(album.Genres, ", "))
+	}
+
+	var tracks []string
+	for _, track := range album.Tracks.Items {
+		tracks = append(tracks, fmt.Sprintf("%d. %s (%s)", track.TrackNumber, track.Name, formatDuration(track.DurationMS)))
+	}
+	texts := []string{b.String()}
+	if len(tracks) > 0 {
+		texts = append(texts, album.Name+" track listing:\n"+strings.Join(tracks, "\n"))
+	}
+	return texts, nil
+}
+
+func (es *DataSourceSpotify) artistData(ctx context.Context, id string) ([]string, error) {
+	var artist struct {
+		Name       string   `json:"name"`
+		Genres     []string `json:"genres"`
+		Popularity int      `json:"popularity"`
+		Followers  struct {
+			Total int64 `json:"total"`
+		} `json:"followers"`
+	}
+	if err := es.doJSON(ctx, "/artists/"+url.PathEscape(id), nil, &artist); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	b.WriteString(artist.Name)
+	if len(artist.Genres) > 0 {
+		b.WriteString("\nGenres: " + strings.Join(artist.Genres, ", "))
+	}
+	fmt.Fprintf(&b, "\nFollowers: %d\nPopularity: %d/100", artist.Followers.Total, artist.Popularity)
+	texts := []string{b.String()}
+
+	var top struct {
+		Tracks []struct {
+			Name  string `json:"name"`
+			Album struct {
+				Name string `json:"name"`
+			} `json:"album"`
+		} `json:"tracks"`
+	}
+	if err := es.doJSON(ctx, "/artists/"+url.PathEscape(id)+"/top-tracks", es.marketParams(), &top); err == nil && len(top.Tracks) > 0 {
+		lines := make([]string, 0, len(top.Tracks))
+		for i, track := range top.Tracks {
+			lines = append(lines, fmt.Sprintf("%d. %s (%s)", i+1, track.Name, track.Album.Name))
+		}
+		texts = append(texts, artist.Name+" top tracks:\n"+strings.Join(lines, "\n"))
+	}
+	return texts, nil
+}
+
+func (es *DataSourceSpotify) playlistData(ctx context.Context, id string) ([]string, error) {
+	params := es.marketParams()
+	params.Set("fields", "name,description,owner(display_name),followers(total),tracks(total,items(track(name,artists(name))))")
+	var playlist struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Owner       struct {
+			DisplayName string `json:"display_name"`
+		} `json:"owner"`
+		Followers struct {
+			Total int64 `json:"total"`
+		} `json:"followers"`
+		Tracks struct {
+			Total int `json:"total"`
+			Items []struct {
+				Track *struct {
+					Name    string  `json:"name"`
+					Artists []named `json:"artists"`
+				} `json:"track"`
+			} `json:"items"`
+		} `json:"tracks"`
+	}
+	if err := es.doJSON(ctx, "/playlists/"+url.PathEscape(id), params, &playlist); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s, a playlist by %s", playlist.Name, playlist.Owner.DisplayName)
+	if playlist.Description != "" {
+		b.WriteString("\n\n" + playlist.Description)
+	}
+	fmt.Fprintf(&b, "\n\nTracks: %d\nFollowers: %d", playlist.Tracks.Total, playlist.Followers.Total)
+	texts := []string{b.String()}
+
+	var lines []string
+	for _, entry := range playlist.Tracks.Items {
+		if len(lines) >= listSize {
+			break
+		}
+		// Removed or local tracks come back as null
+		if entry.Track == nil {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%d. %s — %s", len(lines)+1, entry.Track.Name, joinNames(entry.Track.Artists)))
+	}
+	if len(lines) > 0 {
+		texts = append(texts, playlist.Name+" opening tracks:\n"+strings.Join(lines, "\n"))
+	}
+	return texts, nil
+}
+
+func (es *DataSourceSpotify) marketParams() url.Values {
+	params := url.Values{}
+	if es.Market != "" {
+		params.Set("market", es.Market)
+	}
+	return params
+}
+
+// doJSON performs an authenticated Web API request and decodes the JSON response into target.
+// A 401 drops the cached token so the next request authenticates again.
+func (es *DataSourceSpotify) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	token, err := es.credentials.Token(ctx)
+	if err != nil {
+		return err
+	}
+	uri := strings.TrimRight(es.BaseURL, "/") + path
+	if encoded := params.Encode(); encoded != "" {
+		uri = uri + "?" + encoded
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := es.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		es.credentials.Invalidate()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("spotify request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func joinNames(items []named) string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func formatDuration(ms int64) string {
+	seconds := ms / 1000
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
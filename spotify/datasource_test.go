@@ -0,0 +1,139 @@
+package spotify
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// state counts the tokens issued and names the one the API accepts
+type state struct {
+	tokens int
+	valid  string
+}
+
+func newServer(t *testing.T) (*httptest.Server, *state) {
+	t.Helper()
+	st := &state{valid: "token1"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		st.tokens++
+		if id, secret, _ := r.BasicAuth(); id != "id" || secret != "secret" {
+			t.Errorf("token request with %q, %q", id, secret)
+		}
+		fmt.Fprintf(w, `{"access_token":"token%d","expires_in":3600}`, st.tokens)
+	})
+	authorized := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+st.valid {
+				http.Error(w, "expired", http.StatusUnauthorized)
+				return
+			}
+			h(w, r)
+		}
+	}
+	mux.HandleFunc("/v1/search", authorized(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("q") != "daft punk" || q.Get("type") != "track,album,artist" || q.Get("limit") != "5" || q.Get("market") != "US" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{
+			"tracks":{"items":[
+				{"id":"t1","name":"One More Time","artists":[{"name":"Daft Punk"}],"album":{"release_date":"2000-11-30"},"external_urls":{"spotify":"https://open.spotify.com/track/t1"}},
+				{"id":"t2","name":"Digital Love","artists":[{"name":"Daft Punk"}],"album":{"release_date":"2001"},"external_urls":{"spotify":"https://open.spotify.com/track/t2"}}
+			]},
+			"albums":{"items":[{"id":"a1","name":"Discovery","artists":[{"name":"Daft Punk"}],"release_date":"2001-03-12","external_urls":{"spotify":"https://open.spotify.com/album/a1"}}]},
+			"artists":{"items":[null,{"id":"ar1","name":"Daft Punk","external_urls":{"spotify":"https://open.spotify.com/artist/ar1"}}]}
+		}`)
+	}))
+	mux.HandleFunc("/v1/tracks/t1", authorized(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"One More Time","artists":[{"name":"Daft Punk"},{"name":"Romanthony"}],"duration_ms":320357,"popularity":80,"album":{"name":"Discovery","release_date":"2001-03-12"}}`)
+	}))
+	mux.HandleFunc("/v1/audio-features/t1", authorized(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"danceability":0.61,"energy":0.7,"valence":0.48,"acousticness":0.02,"instrumentalness":0,"tempo":122.7,"key":2,"mode":1,"time_signature":4}`)
+	}))
+	mux.HandleFunc("/v1/albums/a1", authorized(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"Discovery","album_type":"album","artists":[{"name":"Daft Punk"}],"release_date":"2001-03-12","label":"Virgin","total_tracks":2,"popularity":75,
+			"tracks":{"items":[{"track_number":1,"name":"One More Time","duration_ms":320357},{"track_number":2,"name":"Aerodynamic","duration_ms":212000}]}}`)
+	}))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, st
+}
+
+func newSource(t *testing.T) (*DataSourceSpotify, *state) {
+	t.Helper()
+	srv, st := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL + "/v1"
+	es.AuthURL = srv.URL + "/token"
+	es.ClientID = "id"
+	es.ClientSecret = "secret"
+	return es, st
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es, st := newSource(t)
+	topics, err := es.FetchTopics(5, "daft punk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"One More Time — Daft Punk (track, 2000)",
+		"Discovery — Daft Punk (album, 2001)",
+		"Digital Love — Daft Punk (track, 2001)",
+		"Daft Punk (artist)",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	track := "One More Time by Daft Punk, Romanthony, from Discovery (2001-03-12)\nDuration: 5:20\nPopularity: 80/100\n" +
+		"Audio features: 123 BPM in D major, 4/4 time; danceability 0.61, energy 0.70, valence 0.48, acousticness 0.02, instrumentalness 0.00"
+	if len(data) != 1 || data[0].DataText != track {
+		t.Errorf("track data = %+v, want %q", data, track)
+	}
+
+	data, err = es.FetchData(5, topics[1].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	album := []string{
+		"Discovery by Daft Punk (album, released 2001-03-12)\nLabel: Virgin\nTracks: 2\nPopularity: 75/100",
+		"Discovery track listing:\n1. One More Time (5:20)\n2. Aerodynamic (3:32)",
+	}
+	if len(data) != 2 || data[0].DataText != album[0] || data[1].DataText != album[1] {
+		t.Errorf("album data = %+v", data)
+	}
+	if st.tokens != 1 {
+		t.Errorf("requested %d tokens, want 1", st.tokens)
+	}
+}
+
+func TestUnauthorizedInvalidatesToken(t *testing.T) {
+	es, st := newSource(t)
+	if _, err := es.FetchTopics(5, "daft punk"); err != nil {
+		t.Fatal(err)
+	}
+	// The API revokes the first token; the 401 drops it and the next request gets a new one
+	st.valid = "token2"
+	if _, err := es.FetchTopics(5, "daft punk"); err == nil {
+		t.Fatal("FetchTopics succeeded with a revoked token")
+	}
+	if _, err := es.FetchTopics(5, "daft punk"); err != nil {
+		t.Fatal(err)
+	}
+	if st.tokens != 2 {
+		t.Errorf("requested %d tokens, want 2", st.tokens)
+	}
+}
@@ -0,0 +1,123 @@
+//go:build llm
+
+package summarize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultPrompt is the system prompt OpenAI uses when Prompt is empty
+const DefaultPrompt = "Summarize the user's text in two or three sentences. Keep names, numbers and dates; " +
+	"add nothing that is not in the text."
+
+// OpenAI summarizes with a chat completions API: OpenAI itself, or a compatible server such as Ollama,
+// vLLM or llama.cpp. Built with the "llm" build tag.
+type OpenAI struct {
+	Client    *http.Client
+	BaseURL   string // Default "https://api.openai.com/v1"; e.g. "http://localhost:11434/v1" for Ollama
+	APIKey    string // Optional for local servers
+	Model     string // Default "gpt-4o-mini"
+	Prompt    string // System prompt; default DefaultPrompt
+	MaxInput  int    // Text is cut to this many characters before sending; default 12000
+	MaxTokens int    // Summary length limit; default 200
+}
+
+func NewOpenAI(apiKey string) *OpenAI {
+	return &OpenAI{
+		Client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		BaseURL:   "https://api.openai.com/v1",
+		APIKey:    apiKey,
+		Model:     "gpt-4o-mini",
+		MaxInput:  12000,
+		MaxTokens: 200,
+	}
+}
+
+// Summarize implements Summarizer
+func (o *OpenAI) Summarize(ctx context.Context, text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", nil
+	}
+	if limit := o.MaxInput; limit > 0 && len(text) > limit {
+		text = strings.ToValidUTF8(text[:limit], "")
+	}
+	prompt := o.Prompt
+	if prompt == "" {
+		prompt = DefaultPrompt
+	}
+	model := o.Model
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	maxTokens := o.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = 200
+	}
+
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	body, err := json.Marshal(struct {
+		Model     string    `json:"model"`
+		Messages  []message `json:"messages"`
+		MaxTokens int       `json:"max_tokens"`
+	}{
+		Model:     model,
+		Messages:  []message{{Role: "system", Content: prompt}, {Role: "user", Content: text}},
+		MaxTokens: maxTokens,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	base := o.BaseURL
+	if base == "" {
+		base = "https://api.openai.com/v1"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(base, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+	client := o.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("summarize request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	var response struct {
+		Choices []struct {
+			Message message `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", err
+	}
+	if len(response.Choices) == 0 {
+		return "", errors.New("summarize response has no choices")
+	}
+	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+}
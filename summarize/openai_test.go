@@ -0,0 +1,81 @@
+//go:build llm
+
+package summarize
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s with %q", r.URL, r.Header.Get("Authorization"))
+		}
+		var request struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+			MaxTokens int `json:"max_tokens"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+		if request.Model != "llama3" || request.MaxTokens != 200 || len(request.Messages) != 2 ||
+			request.Messages[0].Content != DefaultPrompt || request.Messages[1].Content != "h" {
+			t.Errorf("unexpected request %+v", request)
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"  A greeting.\n"}}]}`)
+	}))
+	defer srv.Close()
+
+	o := NewOpenAI("key")
+	o.BaseURL = srv.URL + "/v1/"
+	o.Model = "llama3"
+	// The cut falls inside "é" and drops its first byte
+	o.MaxInput = 2
+	summary, err := o.Summarize(context.Background(), "  héllo world ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary != "A greeting." {
+		t.Errorf("Summarize = %q", summary)
+	}
+	if summary, err := o.Summarize(context.Background(), " \n"); summary != "" || err != nil {
+		t.Errorf("Summarize of blank text = %q, %v", summary, err)
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	for body, want := range map[string]string{
+		`{"choices":[]}`: "no choices",
+		`not json`:       "invalid character",
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		}))
+		o := NewOpenAI("")
+		o.BaseURL = srv.URL
+		if _, err := o.Summarize(context.Background(), "text"); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Summarize with %s = %v, want %q", body, err, want)
+		}
+		srv.Close()
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"model not found"}}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+	o := NewOpenAI("")
+	o.BaseURL = srv.URL
+	if _, err := o.Summarize(context.Background(), "text"); err == nil || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("Summarize = %v", err)
+	}
+}
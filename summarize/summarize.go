@@ -0,0 +1,33 @@
+// Package summarize defines the hook adapters and decorators call to attach
+// a summary to data items. The default does nothing; the pipeline's
+// Summarize stage runs any Summarizer over fetched data. An example
+// implementation backed by an OpenAI-compatible chat API is built with the
+// "llm" build tag.
+package summarize
+
+import "context"
+
+// Summarizer returns a summary of text. An empty summary with a nil error means there is nothing to add.
+type Summarizer interface {
+	Summarize(ctx context.Context, text string) (string, error)
+}
+
+// Func adapts a function to Summarizer
+type Func func(ctx context.Context, text string) (string, error)
+
+func (f Func) Summarize(ctx context.Context, text string) (string, error) {
+	return f(ctx, text)
+}
+
+// None is the default Summarizer; it returns no summary
+var None Summarizer = Func(func(ctx context.Context, text string) (string, error) {
+	return "", nil
+})
+
+// Or returns s, or None when s is nil
+func Or(s Summarizer) Summarizer {
+	if s == nil {
+		return None
+	}
+	return s
+}
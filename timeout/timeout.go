@@ -0,0 +1,199 @@
+// Package timeout holds the deadlines data sources apply to searches, data
+// fetches and health checks, and a decorator that enforces them per source.
+// Adapters read the process-wide defaults below instead of hard-coding their
+// own, and Timeout bounds each call to a wrapped source, so a deployment can
+// give a slow archive more time than a search engine and cut health checks
+// short everywhere.
+package timeout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/source"
+)
+
+// Deadlines the adapters in this repository apply to their own requests. Set them once at startup,
+// before any fetch. Clients created by the adapters' New functions also time out after 8 seconds;
+// replace the Client to allow longer requests.
+var (
+	Topics = 8 * time.Second // FetchTopics
+	Data   = 8 * time.Second // FetchData
+	Check  = 5 * time.Second // CheckAvailability
+)
+
+// ErrTimeout is returned when a wrapped call misses its deadline. It matches context.DeadlineExceeded
+// with errors.Is.
+var ErrTimeout = fmt.Errorf("timeout: %w", context.DeadlineExceeded)
+
+// Durations are deadlines for each operation; zero fields use the package defaults
+type Durations struct {
+	Topics time.Duration
+	Data   time.Duration
+	Check  time.Duration
+}
+
+// Config holds a deployment's deadlines: defaults for every source and overrides by source name
+type Config struct {
+	Default Durations
+	Sources map[string]Durations // Non-zero fields override Default
+}
+
+// For returns the deadlines of the named source
+func (c Config) For(name string) Durations {
+	d := c.Default
+	o := c.Sources[name]
+	if o.Topics > 0 {
+		d.Topics = o.Topics
+	}
+	if o.Data > 0 {
+		d.Data = o.Data
+	}
+	if o.Check > 0 {
+		d.Check = o.Check
+	}
+	return d
+}
+
+// Wrap wraps the named source with its deadlines
+func (c Config) Wrap(name string, s source.Source) *Timeout {
+	return New(name, s, c.For(name))
+}
+
+// Timeout wraps a source and bounds each call by the deadline for its operation. A call that misses
+// it returns ErrTimeout, or false for CheckAvailability. Sources implementing source.ContextSource are
+// cancelled; others finish in the background within the adapter's own deadline. Init is not bounded.
+// It implements source.ContextSource.
+type Timeout struct {
+	Source source.Source
+	Name   string // Used in errors
+	Durations
+}
+
+// New wraps s with the deadlines d
+func New(name string, s source.Source, d Durations) *Timeout {
+	return &Timeout{Source: s, Name: name, Durations: d}
+}
+
+// Init implements models.DataSource
+func (t *Timeout) Init() error {
+	return t.Source.Init()
+}
+
+// CheckAvailability implements models.DataSource
+func (t *Timeout) CheckAvailability() bool {
+	ok, err := run(or(t.Durations.Check, Check), func() (bool, error) {
+		return t.Source.CheckAvailability(), nil
+	})
+	return err == nil && ok
+}
+
+// Unwrap implements source.Wrapper
+func (t *Timeout) Unwrap() source.Source {
+	return t.Source
+}
+
+// FetchTopics implements models.DataSource
+func (t *Timeout) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	return t.FetchTopicsContext(context.Background(), count, input)
+}
+
+// FetchTopicsContext implements source.ContextSource
+func (t *Timeout) FetchTopicsContext(ctx context.Context, count int, input string) ([]datasource.DataSourceTopic, error) {
+	ctx, cancel := context.WithTimeout(ctx, or(t.Durations.Topics, Topics))
+	defer cancel()
+	topics, err := source.FetchTopicsContext(ctx, t.Source, count, input)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%s FetchTopics: %w", t.name(), ErrTimeout)
+	}
+	return topics, err
+}
+
+// FetchTopicsWithOptions implements source.OptionsSource
+func (t *Timeout) FetchTopicsWithOptions(ctx context.Context, query string, opts source.TopicOptions) ([]datasource.DataSourceTopic, error) {
+	ctx, cancel := context.WithTimeout(ctx, or(t.Durations.Topics, Topics))
+	defer cancel()
+	topics, err := source.FetchTopicsWithOptions(ctx, t.Source, query, opts)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%s FetchTopics: %w", t.name(), ErrTimeout)
+	}
+	return topics, err
+}
+
+// FetchTopicsPage implements source.Pager
+func (t *Timeout) FetchTopicsPage(ctx context.Context, query string, opts source.TopicOptions, cursor string) (source.Page, error) {
+	ctx, cancel := context.WithTimeout(ctx, or(t.Durations.Topics, Topics))
+	defer cancel()
+	page, err := source.FetchTopicsPage(ctx, t.Source, query, opts, cursor)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return source.Page{}, fmt.Errorf("%s FetchTopics: %w", t.name(), ErrTimeout)
+	}
+	return page, err
+}
+
+// FetchData implements models.DataSource
+func (t *Timeout) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	return t.FetchDataContext(context.Background(), count, topicID)
+}
+
+// FetchDataContext implements source.ContextSource
+func (t *Timeout) FetchDataContext(ctx context.Context, count int, topicID int64) ([]datasource.DataSourceData, error) {
+	ctx, cancel := context.WithTimeout(ctx, or(t.Durations.Data, Data))
+	defer cancel()
+	data, err := source.FetchDataContext(ctx, t.Source, count, topicID)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%s FetchData: %w", t.name(), ErrTimeout)
+	}
+	return data, err
+}
+
+// FetchDataBatch implements source.Batcher
+func (t *Timeout) FetchDataBatch(ctx context.Context, count int, topicIDs []int64) (map[int64][]datasource.DataSourceData, error) {
+	ctx, cancel := context.WithTimeout(ctx, or(t.Durations.Data, Data))
+	defer cancel()
+	data, err := source.FetchDataBatch(ctx, t.Source, count, topicIDs)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return data, fmt.Errorf("%s FetchData: %w", t.name(), ErrTimeout)
+	}
+	return data, err
+}
+
+func (t *Timeout) name() string {
+	if t.Name == "" {
+		return "source"
+	}
+	return t.Name
+}
+
+// Helpers
+
+// run calls call and waits at most d for it to return
+func run[T any](d time.Duration, call func() (T, error)) (T, error) {
+	type outcome struct {
+		value T
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		value, err := call()
+		done <- outcome{value, err}
+	}()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case o := <-done:
+		return o.value, o.err
+	case <-timer.C:
+		var zero T
+		return zero, ErrTimeout
+	}
+}
+
+func or(d, fallback time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return fallback
+}
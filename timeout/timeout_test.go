@@ -0,0 +1,111 @@
+package timeout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+)
+
+// slow is a source whose calls take delay
+type slow struct {
+	delay time.Duration
+}
+
+func (s *slow) Init() error { return nil }
+
+func (s *slow) CheckAvailability() bool {
+	time.Sleep(s.delay)
+	return true
+}
+
+func (s *slow) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	time.Sleep(s.delay)
+	return []datasource.DataSourceTopic{{TopicID: 1, Topic: input}}, nil
+}
+
+func (s *slow) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	time.Sleep(s.delay)
+	return []datasource.DataSourceData{{DataText: "data", AnswerID: topicID}}, nil
+}
+
+// cancellable is a slow source that stops when its context ends
+type cancellable struct {
+	slow
+	err error // What the last FetchDataContext returned
+}
+
+func (c *cancellable) FetchTopicsContext(ctx context.Context, count int, input string) ([]datasource.DataSourceTopic, error) {
+	return c.FetchTopics(count, input)
+}
+
+func (c *cancellable) FetchDataContext(ctx context.Context, count int, topicID int64) ([]datasource.DataSourceData, error) {
+	select {
+	case <-time.After(c.delay):
+		return c.FetchData(count, topicID)
+	case <-ctx.Done():
+		c.err = ctx.Err()
+		return nil, c.err
+	}
+}
+
+func TestConfigFor(t *testing.T) {
+	c := Config{
+		Default: Durations{Topics: 2 * time.Second, Data: 3 * time.Second, Check: time.Second},
+		Sources: map[string]Durations{"archive": {Data: 30 * time.Second}},
+	}
+	if got, want := c.For("archive"), (Durations{Topics: 2 * time.Second, Data: 30 * time.Second, Check: time.Second}); got != want {
+		t.Errorf("For(archive) = %+v, want %+v", got, want)
+	}
+	if got := c.For("wikipedia"); got != c.Default {
+		t.Errorf("For(wikipedia) = %+v, want the defaults", got)
+	}
+
+	w := c.Wrap("archive", &slow{})
+	if w.Name != "archive" || w.Durations != c.For("archive") {
+		t.Errorf("Wrap = %+v", w)
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	inner := &cancellable{slow: slow{delay: time.Second}}
+	s := New("slow", inner, Durations{Data: 20 * time.Millisecond})
+
+	start := time.Now()
+	_, err := s.FetchData(1, 1)
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FetchData = %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("FetchData returned after %v", elapsed)
+	}
+	// The source's call was cancelled, not abandoned
+	if !errors.Is(inner.err, context.DeadlineExceeded) {
+		t.Errorf("source saw %v, want its context's deadline", inner.err)
+	}
+
+	// A caller's earlier deadline is kept
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	s.Durations.Data = time.Hour
+	if _, err := s.FetchDataContext(ctx, 1, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FetchDataContext = %v, want the caller's deadline", err)
+	}
+}
+
+func TestDeadlineWithoutContext(t *testing.T) {
+	s := New("slow", &slow{delay: 200 * time.Millisecond}, Durations{Topics: 20 * time.Millisecond, Check: 20 * time.Millisecond})
+	if _, err := s.FetchTopics(1, "query"); !errors.Is(err, ErrTimeout) {
+		t.Errorf("FetchTopics = %v, want ErrTimeout", err)
+	}
+	if s.CheckAvailability() {
+		t.Error("CheckAvailability past its deadline = true")
+	}
+
+	fast := New("fast", &slow{}, Durations{Topics: time.Second})
+	if topics, err := fast.FetchTopics(1, "query"); err != nil || len(topics) != 1 {
+		t.Errorf("FetchTopics within the deadline = %+v, %v", topics, err)
+	}
+}
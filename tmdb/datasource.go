@@ -0,0 +1,358 @@
+package tmdb
+
+// Data Source Adapter for film and TV metadata from TMDB, with optional OMDb ratings
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	castSize          = 10
+)
+
+type DataSourceTMDB struct {
+	Client    *http.Client
+	BaseURL   string
+	OMDbURL   string
+	Token     string // TMDB API read access token (v4 bearer token)
+	OMDbKey   string // Optional; adds IMDb, Rotten Tomatoes and Metacritic ratings
+	UserAgent string
+	Language  string // e.g. "en-US"
+	Adult     bool   // Include adult titles in search
+
+	titles topicid.Map[title]
+}
+
+// title is a film or TV series
+type title struct {
+	Media string // "movie" or "tv"
+	ID    int64
+}
+
+func New() *DataSourceTMDB {
+	return &DataSourceTMDB{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://api.themoviedb.org/3",
+		OMDbURL:   "https://www.omdbapi.com/",
+		UserAgent: "locus/tmdb-datasource",
+		Language:  "en-US",
+	}
+}
+
+// Init implements models.DataSource
+// Requires a TMDB read access token
+func (es *DataSourceTMDB) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.Token == "" {
+		return errors.New("Token is required for TMDB DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceTMDB) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	return es.doJSON(ctx, "/configuration", nil, &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceTMDB) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "TMDB",
+		Homepage:            "https://www.themoviedb.org",
+		Attribution:         "This product uses the TMDB API but is not endorsed or certified by TMDB.",
+		AttributionRequired: true,
+		TermsURL:            "https://www.themoviedb.org/api-terms-of-use",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Films and series matching the title are topics, e.g. "Dune: Part Two (film, 2024)"; people are skipped
+func (es *DataSourceTMDB) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for TMDB DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("query", query)
+	params.Set("include_adult", strconv.FormatBool(es.Adult))
+	var response struct {
+		Results []struct {
+			ID           int64  `json:"id"`
+			MediaType    string `json:"media_type"`
+			Title        string `json:"title"`
+			Name         string `json:"name"`
+			ReleaseDate  string `json:"release_date"`
+			FirstAirDate string `json:"first_air_date"`
+		} `json:"results"`
+	}
+	if err := es.doJSON(ctx, "/search/multi", params, &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, item := range response.Results {
+		if len(results) >= count {
+			break
+		}
+		name, date, kind := item.Title, item.ReleaseDate, "film"
+		switch item.MediaType {
+		case "movie":
+		case "tv":
+			name, date, kind = item.Name, item.FirstAirDate, "TV series"
+		default:
+			continue
+		}
+		label := kind
+		if len(date) >= 4 {
+			label += ", " + date[:4]
+		}
+		t := title{Media: item.MediaType, ID: item.ID}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s (%s)", name, label),
+			SourceURL: titleLink(t),
+			TopicID:   es.titles.Put(fmt.Sprintf("%s/%d", t.Media, t.ID), t),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns a summary (plot, genres, release, runtime, ratings) and then the credits (directors or creators and top-billed cast)
+func (es *DataSourceTMDB) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	t, ok := es.titles.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown TMDB topicID %d", topicID)
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	params := url.Values{}
+	params.Set("append_to_response", "credits,external_ids")
+	var details struct {
+		Title            string  `json:"title"`
+		Name             string  `json:"name"`
+		Tagline          string  `json:"tagline"`
+		Overview         string  `json:"overview"`
+		ReleaseDate      string  `json:"release_date"`
+		FirstAirDate     string  `json:"first_air_date"`
+		LastAirDate      string  `json:"last_air_date"`
+		Status           string  `json:"status"`
+		Runtime          int     `json:"runtime"`
+		NumberOfSeasons  int     `json:"number_of_seasons"`
+		NumberOfEpisodes int     `json:"number_of_episodes"`
+		VoteAverage      float64 `json:"vote_average"`
+		VoteCount        int     `json:"vote_count"`
+		Genres           []struct {
+			Name string `json:"name"`
+		} `json:"genres"`
+		CreatedBy []struct {
+			Name string `json:"name"`
+		} `json:"created_by"`
+		Credits struct {
+			Cast []struct {
+				Name      string `json:"name"`
+				Character string `json:"character"`
+			} `json:"cast"`
+			Crew []struct {
+				Name string `json:"name"`
+				Job  string `json:"job"`
+			} `json:"crew"`
+		} `json:"credits"`
+		ExternalIDs struct {
+			IMDbID string `json:"imdb_id"`
+		} `json:"external_ids"`
+	}
+	if err := es.doJSON(ctx, fmt.Sprintf("/%s/%d", t.Media, t.ID), params, &details); err != nil {
+		return nil, err
+	}
+
+	name := details.Title
+	if t.Media == "tv" {
+		name = details.Name
+	}
+	var summary strings.Builder
+	summary.WriteString(name)
+	if details.Tagline != "" {
+		summary.WriteString(" — " + details.Tagline)
+	}
+	if details.Overview != "" {
+		summary.WriteString("\n\n" + details.Overview)
+	}
+	summary.WriteString("\n")
+	if len(details.Genres) > 0 {
+		genres := make([]string, 0, len(details.Genres))
+		for _, genre := range details.Genres {
+			genres = append(genres, genre.Name)
+		}
+		summary.WriteString("\nGenres: " + strings.Join(genres, ", "))
+	}
+	if t.Media == "movie" {
+		if details.ReleaseDate != "" {
+			summary.WriteString("\nReleased: " + details.ReleaseDate)
+		}
+		if details.Runtime > 0 {
+			fmt.Fprintf(&summary, "\nRuntime: %d min", details.Runtime)
+		}
+	} else {
+		fmt.Fprintf(&summary, "\nAired: %s to %s (%s), %d seasons, %d episodes",
+			details.FirstAirDate, details.LastAirDate, details.Status, details.NumberOfSeasons, details.NumberOfEpisodes)
+	}
+	if details.VoteCount > 0 {
+		fmt.Fprintf(&summary, "\nTMDB rating: %.1f/10 (%d votes)", details.VoteAverage, details.VoteCount)
+	}
+	if es.OMDbKey != "" && details.ExternalIDs.IMDbID != "" {
+		// OMDb is a supplement; its quota runs out long before TMDB's
+		if ratings, err := es.fetchRatings(ctx, details.ExternalIDs.IMDbID); err == nil {
+			for _, rating := range ratings {
+				summary.WriteString("\n" + rating)
+			}
+		}
+	}
+
+	results := []datasource.DataSourceData{{
+		DataText:  strings.TrimSpace(summary.String()),
+		SourceURL: titleLink(t),
+		AnswerID:  topicID,
+	}}
+	if count == 1 {
+		return results, nil
+	}
+
+	var credits []string
+	for _, creator := range details.CreatedBy {
+		credits = append(credits, "Created by: "+creator.Name)
+	}
+	for _, member := range details.Credits.Crew {
+		if member.Job == "Director" || member.Job == "Screenplay" || member.Job == "Writer" {
+			credits = append(credits, member.Job+": "+member.Name)
+		}
+	}
+	for i, member := range details.Credits.Cast {
+		if i >= castSize {
+			break
+		}
+		if member.Character != "" {
+			credits = append(credits, fmt.Sprintf("%s as %s", member.Name, member.Character))
+		} else {
+			credits = append(credits, member.Name)
+		}
+	}
+	if len(credits) > 0 {
+		results = append(results, datasource.DataSourceData{
+			DataText:  name + " credits:\n" + strings.Join(credits, "\n"),
+			SourceURL: titleLink(t) + "/cast",
+			AnswerID:  topicid.Hash(fmt.Sprintf("%s/%d#credits", t.Media, t.ID)),
+		})
+	}
+	return results, nil
+}
+
+// fetchRatings reads third-party ratings for an IMDb ID from OMDb, e.g. "Rotten Tomatoes: 92%"
+func (es *DataSourceTMDB) fetchRatings(ctx context.Context, imdbID string) ([]string, error) {
+	params := url.Values{}
+	params.Set("apikey", es.OMDbKey)
+	params.Set("i", imdbID)
+	var response struct {
+		Response string `json:"Response"`
+		Error    string `json:"Error"`
+		Ratings  []struct {
+			Source string `json:"Source"`
+			Value  string `json:"Value"`
+		} `json:"Ratings"`
+	}
+	if err := es.get(ctx, es.OMDbURL+"?"+params.Encode(), false, &response); err != nil {
+		return nil, err
+	}
+	if response.Response == "False" {
+		return nil, fmt.Errorf("omdb: %s", response.Error)
+	}
+	ratings := make([]string, 0, len(response.Ratings))
+	for _, rating := range response.Ratings {
+		ratings = append(ratings, rating.Source+": "+rating.Value)
+	}
+	return ratings, nil
+}
+
+// doJSON performs an authenticated TMDB API request and decodes the JSON response into target
+func (es *DataSourceTMDB) doJSON(ctx context.Context, path string, params url.Values, target interface{}) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	if es.Language != "" {
+		params.Set("language", es.Language)
+	}
+	return es.get(ctx, strings.TrimRight(es.BaseURL, "/")+path+"?"+params.Encode(), true, target)
+}
+
+// get performs an HTTP GET request and decodes the JSON response into target
+func (es *DataSourceTMDB) get(ctx context.Context, uri string, authorize bool, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if authorize {
+		req.Header.Set("Authorization", "Bearer "+es.Token)
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("tmdb request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func titleLink(t title) string {
+	return fmt.Sprintf("https://www.themoviedb.org/%s/%d", t.Media, t.ID)
+}
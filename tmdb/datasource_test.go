@@ -0,0 +1,108 @@
+package tmdb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/3/search/multi", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("query") != "dune" || q.Get("include_adult") != "false" || q.Get("language") != "en-US" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, `{"results":[
+			{"id":693134,"media_type":"movie","title":"Dune: Part Two","release_date":"2024-02-27"},
+			{"id":1,"media_type":"person","name":"Frank Herbert"},
+			{"id":90228,"media_type":"tv","name":"Dune: Prophecy","first_air_date":"2024-11-17"}
+		]}`)
+	})
+	mux.HandleFunc("/3/movie/693134", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("append_to_response") != "credits,external_ids" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"title":"Dune: Part Two","tagline":"Long live the fighters.","overview":"Paul unites with the Fremen.","release_date":"2024-02-27","runtime":167,
+			"vote_average":8.15,"vote_count":5000,"genres":[{"name":"Science Fiction"},{"name":"Adventure"}],
+			"credits":{"cast":[{"name":"Timothée Chalamet","character":"Paul Atreides"},{"name":"Extra"}],"crew":[{"name":"Denis Villeneuve","job":"Director"},{"name":"Someone","job":"Grip"}]},
+			"external_ids":{"imdb_id":"tt15239678"}}`)
+	})
+	mux.HandleFunc("/3/tv/90228", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"Dune: Prophecy","overview":"The Sisterhood.","first_air_date":"2024-11-17","last_air_date":"2024-12-22","status":"Returning Series","number_of_seasons":1,"number_of_episodes":6,"created_by":[{"name":"Diane Ademu-John"}]}`)
+	})
+	mux.HandleFunc("/omdb/", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("apikey") != "omdb" || q.Get("i") != "tt15239678" || r.Header.Get("Authorization") != "" {
+			t.Errorf("unexpected OMDb request %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"Response":"True","Ratings":[{"Source":"Internet Movie Database","Value":"8.5/10"},{"Source":"Rotten Tomatoes","Value":"92%"}]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSource(t *testing.T) *DataSourceTMDB {
+	t.Helper()
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL + "/3"
+	es.OMDbURL = srv.URL + "/omdb/"
+	es.Token = "token"
+	es.OMDbKey = "omdb"
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "dune")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[0].Topic != "Dune: Part Two (film, 2024)" || topics[1].Topic != "Dune: Prophecy (TV series, 2024)" ||
+		topics[0].SourceURL != "https://www.themoviedb.org/movie/693134" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Dune: Part Two — Long live the fighters.\n\nPaul unites with the Fremen.\n\nGenres: Science Fiction, Adventure\nReleased: 2024-02-27\nRuntime: 167 min\nTMDB rating: 8.2/10 (5000 votes)\nInternet Movie Database: 8.5/10\nRotten Tomatoes: 92%",
+		"Dune: Part Two credits:\nDirector: Denis Villeneuve\nTimothée Chalamet as Paul Atreides\nExtra",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+}
+
+func TestFetchDataSeries(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "dune")
+	if err != nil || len(topics) != 2 {
+		t.Fatalf("FetchTopics = %+v, %v", topics, err)
+	}
+	data, err := es.FetchData(5, topics[1].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Dune: Prophecy\n\nThe Sisterhood.\n\nAired: 2024-11-17 to 2024-12-22 (Returning Series), 1 seasons, 6 episodes",
+		"Dune: Prophecy credits:\nCreated by: Diane Ademu-John",
+	}
+	if len(data) != len(want) || data[0].DataText != want[0] || data[1].DataText != want[1] {
+		t.Errorf("FetchData = %+v", data)
+	}
+}
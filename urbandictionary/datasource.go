@@ -0,0 +1,232 @@
+package urbandictionary
+
+// Data Source Adapter for Urban Dictionary (unofficial JSON API)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceUrbanDictionary struct {
+	Client    *http.Client
+	BaseURL   string
+	UserAgent string
+	MinVotes  int // Skip definitions with fewer thumbs up than this
+
+	terms topicid.Map[[]definition]
+}
+
+type definition struct {
+	DefID      int64  `json:"defid"`
+	Word       string `json:"word"`
+	Definition string `json:"definition"`
+	Example    string `json:"example"`
+	ThumbsUp   int    `json:"thumbs_up"`
+	ThumbsDown int    `json:"thumbs_down"`
+	Author     string `json:"author"`
+	WrittenOn  string `json:"written_on"`
+	Permalink  string `json:"permalink"`
+}
+
+func New() *DataSourceUrbanDictionary {
+	return &DataSourceUrbanDictionary{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://api.urbandictionary.com/v0",
+		UserAgent: "locus/urbandictionary-datasource",
+	}
+}
+
+// NSFWCapable reports that this source can return explicit or offensive text, so content filters
+// wrapping it should screen its results
+func (es *DataSourceUrbanDictionary) NSFWCapable() bool {
+	return true
+}
+
+// Init implements models.DataSource
+func (es *DataSourceUrbanDictionary) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceUrbanDictionary) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.define(ctx, "word")
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceUrbanDictionary) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Urban Dictionary",
+		Homepage: "https://www.urbandictionary.com",
+		TermsURL: "https://about.urbandictionary.com/tos",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Definitions are grouped by headword; topics read "yeet (7 definitions, 12345 up votes)"
+func (es *DataSourceUrbanDictionary) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Urban Dictionary DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	definitions, err := es.define(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var order []string
+	groups := map[string][]definition{}
+	for _, d := range definitions {
+		if d.ThumbsUp < es.MinVotes {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(d.Word))
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], d)
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(order))
+	for _, key := range order {
+		if len(results) >= count {
+			break
+		}
+		group := groups[key]
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].ThumbsUp > group[j].ThumbsUp
+		})
+		votes := 0
+		for _, d := range group {
+			votes += d.ThumbsUp
+		}
+		word := strings.TrimSpace(group[0].Word)
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s (%d definitions, %d up votes)", word, len(group), votes),
+			SourceURL: termLink(word),
+			Site:      "urbandictionary.com",
+			TopicID:   es.terms.Put(key, group),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the term's definitions, most up-voted first, each with its example and vote counts
+func (es *DataSourceUrbanDictionary) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	definitions, ok := es.terms.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Urban Dictionary topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, d := range definitions {
+		if len(results) >= count {
+			break
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s: %s", strings.TrimSpace(d.Word), unlink(d.Definition))
+		if example := unlink(d.Example); example != "" {
+			b.WriteString("\n\nExample: " + example)
+		}
+		fmt.Fprintf(&b, "\n\n👍 %d  👎 %d", d.ThumbsUp, d.ThumbsDown)
+		if d.Author != "" {
+			b.WriteString(" — by " + d.Author)
+		}
+		if len(d.WrittenOn) >= 10 {
+			b.WriteString(", " + d.WrittenOn[:10])
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  b.String(),
+			SourceURL: d.Permalink,
+			Site:      "urbandictionary.com",
+			AnswerID:  d.DefID,
+		})
+	}
+	return results, nil
+}
+
+// define looks up every definition of a term
+func (es *DataSourceUrbanDictionary) define(ctx context.Context, term string) ([]definition, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	params := url.Values{}
+	params.Set("term", term)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(es.BaseURL, "/")+"/define?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("urbandictionary request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var response struct {
+		List []definition `json:"list"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+	return response.List, nil
+}
+
+// Helpers
+
+// unlink removes the square brackets Urban Dictionary uses to link other terms, and normalises line endings
+func unlink(text string) string {
+	text = strings.NewReplacer("[", "", "]", "", "\r\n", "\n", "\r", "\n").Replace(text)
+	return strings.TrimSpace(text)
+}
+
+func termLink(term string) string {
+	return "https://www.urbandictionary.com/define.php?term=" + url.QueryEscape(term)
+}
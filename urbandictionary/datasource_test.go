@@ -0,0 +1,68 @@
+package urbandictionary
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/define", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("term") != "yeet" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"list":[
+			{"defid":1,"word":"yeet","definition":"To [throw] something.","example":"He yeeted it.\r\nFar.","thumbs_up":10,"thumbs_down":2,"author":"a","written_on":"2019-01-01T00:00:00.000Z","permalink":"https://yeet.urbanup.com/1"},
+			{"defid":2,"word":"Yeet ","definition":"An exclamation.","thumbs_up":50,"thumbs_down":5,"permalink":"https://yeet.urbanup.com/2"},
+			{"defid":3,"word":"yeet cannon","definition":"A strong arm.","thumbs_up":1,"thumbs_down":0},
+			{"defid":4,"word":"yeeter","definition":"One who yeets.","thumbs_up":7,"thumbs_down":0}
+		]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL + "/"
+	es.MinVotes = 5
+
+	topics, err := es.FetchTopics(5, "yeet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Yeet (2 definitions, 60 up votes)", "yeeter (1 definitions, 7 up votes)"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := []string{
+		"Yeet: An exclamation.\n\n👍 50  👎 5",
+		"yeet: To throw something.\n\nExample: He yeeted it.\nFar.\n\n👍 10  👎 2 — by a, 2019-01-01",
+	}
+	if len(data) != len(wantData) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range wantData {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+	if data[0].AnswerID != 2 || data[0].SourceURL != "https://yeet.urbanup.com/2" {
+		t.Errorf("data 0 = %+v", data[0])
+	}
+}
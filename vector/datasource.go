@@ -0,0 +1,367 @@
+package vector
+
+// Data Source Adapter for semantic search over a vector database (Qdrant or Weaviate)
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/jsonpath"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+// Supported backends
+const (
+	ProviderQdrant   = "qdrant"
+	ProviderWeaviate = "weaviate"
+)
+
+// EmbedFunc turns text into a vector in the same space as the stored documents
+type EmbedFunc func(ctx context.Context, text string) ([]float32, error)
+
+type DataSourceVector struct {
+	Client     *http.Client
+	Provider   string
+	BaseURL    string // e.g. "http://localhost:6333" for Qdrant or "http://localhost:8080" for Weaviate
+	APIKey     string
+	Collection string // Qdrant collection or Weaviate class
+	VectorName string // Qdrant named vector; empty for the default vector
+	UserAgent  string
+
+	// Embed embeds the query; it must use the model the stored vectors were made with
+	Embed EmbedFunc
+
+	// Payload (Qdrant) or property (Weaviate) names; nested payload fields use dots. Weaviate rejects
+	// queries for properties the class lacks, so clear the mappings a class does not have.
+	TextField  string   // Chunk text; default "text"
+	TitleField string   // Default "title"
+	URLField   string   // Default "url"
+	DataFields []string // Extra fields written as "field: value"
+
+	MinScore float64 // Hits below this similarity are dropped
+	Site     string  // Site for topics and data; default the collection name
+
+	chunks topicid.Map[chunk]
+}
+
+type chunk struct {
+	ID     string
+	Title  string
+	URL    string
+	Text   string
+	Score  float64 // Similarity; higher is closer
+	Fields map[string]interface{}
+}
+
+func New() *DataSourceVector {
+	return &DataSourceVector{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		Provider:   ProviderQdrant,
+		BaseURL:    "http://localhost:6333",
+		UserAgent:  "locus/vector-datasource",
+		TextField:  "text",
+		TitleField: "title",
+		URLField:   "url",
+	}
+}
+
+// Init implements models.DataSource
+func (es *DataSourceVector) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	switch es.Provider {
+	case ProviderQdrant, ProviderWeaviate:
+	default:
+		return fmt.Errorf("unknown vector provider %q", es.Provider)
+	}
+	if es.Collection == "" {
+		return errors.New("Collection is required for Vector DataSource")
+	}
+	if es.Embed == nil {
+		return errors.New("Embed is required for Vector DataSource")
+	}
+	if es.TextField == "" {
+		es.TextField = "text"
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Reports whether the collection or class exists
+func (es *DataSourceVector) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	path := "/collections/" + url.PathEscape(es.Collection)
+	if es.Provider == ProviderWeaviate {
+		path = "/v1/schema/" + url.PathEscape(es.Collection)
+	}
+	var response map[string]interface{}
+	return es.doJSON(ctx, http.MethodGet, path, nil, &response) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceVector) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Vector store",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Embeds the input and returns the nearest stored chunks, titled "Title (similarity 0.87)"
+func (es *DataSourceVector) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Vector DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	vector, err := es.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("embedding query: %w", err)
+	}
+	var chunks []chunk
+	switch es.Provider {
+	case ProviderQdrant:
+		chunks, err = es.searchQdrant(ctx, vector, count)
+	case ProviderWeaviate:
+		chunks, err = es.searchWeaviate(ctx, vector, count)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(chunks))
+	for _, c := range chunks {
+		if c.Score < es.MinScore {
+			continue
+		}
+		c.Title = field(c.Fields, es.TitleField)
+		c.URL = field(c.Fields, es.URLField)
+		c.Text = field(c.Fields, es.TextField)
+		title := c.Title
+		if title == "" {
+			title = snippet(c.Text, 80)
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("%s (similarity %s)", title, strconv.FormatFloat(c.Score, 'f', 2, 64)),
+			SourceURL: c.URL,
+			Site:      es.site(),
+			TopicID:   es.chunks.Put(es.Collection+"/"+c.ID, c),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the stored chunk text with its title, extra fields and similarity score
+func (es *DataSourceVector) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	c, ok := es.chunks.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Vector topicID %d", topicID)
+	}
+
+	var lines []string
+	if c.Title != "" {
+		lines = append(lines, c.Title)
+	}
+	if c.Text != "" {
+		lines = append(lines, c.Text)
+	}
+	for _, name := range es.DataFields {
+		if v := field(c.Fields, name); v != "" {
+			lines = append(lines, name+": "+v)
+		}
+	}
+	lines = append(lines, "similarity: "+strconv.FormatFloat(c.Score, 'f', 3, 64))
+	return []datasource.DataSourceData{{
+		DataText:  strings.Join(lines, "\n"),
+		SourceURL: c.URL,
+		Site:      es.site(),
+		AnswerID:  topicID,
+	}}, nil
+}
+
+// searchQdrant runs a points search; Qdrant scores are the collection's similarity metric
+func (es *DataSourceVector) searchQdrant(ctx context.Context, vector []float32, limit int) ([]chunk, error) {
+	request := map[string]interface{}{
+		"vector":       vector,
+		"limit":        limit,
+		"with_payload": true,
+	}
+	if es.VectorName != "" {
+		request["vector"] = map[string]interface{}{"name": es.VectorName, "vector": vector}
+	}
+	var response struct {
+		Result []struct {
+			ID      interface{}            `json:"id"` // Integer or UUID
+			Score   float64                `json:"score"`
+			Payload map[string]interface{} `json:"payload"`
+		} `json:"result"`
+	}
+	path := "/collections/" + url.PathEscape(es.Collection) + "/points/search"
+	if err := es.doJSON(ctx, http.MethodPost, path, request, &response); err != nil {
+		return nil, err
+	}
+	chunks := make([]chunk, 0, len(response.Result))
+	for _, r := range response.Result {
+		chunks = append(chunks, chunk{ID: jsonpath.Format(r.ID), Score: r.Score, Fields: r.Payload})
+	}
+	return chunks, nil
+}
+
+// searchWeaviate runs a nearVector GraphQL query; certainty is used as the similarity score
+func (es *DataSourceVector) searchWeaviate(ctx context.Context, vector []float32, limit int) ([]chunk, error) {
+	properties := []string{es.TextField}
+	for _, name := range append([]string{es.TitleField, es.URLField}, es.DataFields...) {
+		if name != "" {
+			properties = append(properties, name)
+		}
+	}
+	encoded, err := json.Marshal(vector)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("{ Get { %s(nearVector: {vector: %s}, limit: %d) { %s _additional { id certainty distance } } } }",
+		es.Collection, encoded, limit, strings.Join(properties, " "))
+
+	var response struct {
+		Data struct {
+			Get map[string][]map[string]interface{} `json:"Get"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := es.doJSON(ctx, http.MethodPost, "/v1/graphql", map[string]string{"query": query}, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("weaviate query failed: %s", response.Errors[0].Message)
+	}
+	objects := response.Data.Get[es.Collection]
+	chunks := make([]chunk, 0, len(objects))
+	for _, object := range objects {
+		c := chunk{ID: jsonpath.MustCompile("_additional.id").Text(object), Fields: object}
+		additional, _ := object["_additional"].(map[string]interface{})
+		if certainty, ok := additional["certainty"].(float64); ok {
+			c.Score = certainty
+		} else if distance, ok := additional["distance"].(float64); ok {
+			// Certainty is only defined for cosine distance; other metrics fall back to 1 - distance
+			c.Score = 1 - distance
+		}
+		chunks = append(chunks, c)
+	}
+	return chunks, nil
+}
+
+// doJSON sends a request with an optional JSON body and decodes the JSON response into target
+func (es *DataSourceVector) doJSON(ctx context.Context, method, path string, body interface{}, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(es.BaseURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if es.APIKey != "" {
+		if es.Provider == ProviderQdrant {
+			req.Header.Set("api-key", es.APIKey)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+es.APIKey)
+		}
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s request failed: status %d: %s", es.Provider, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func (es *DataSourceVector) site() string {
+	if es.Site != "" {
+		return es.Site
+	}
+	return es.Collection
+}
+
+// Helpers
+
+// field renders a possibly dotted field of a payload as text
+func field(fields map[string]interface{}, name string) string {
+	if name == "" || fields == nil {
+		return ""
+	}
+	if value, ok := fields[name]; ok {
+		return strings.TrimSpace(jsonpath.Format(value))
+	}
+	text, err := jsonpath.Lookup(fields, name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(text)
+}
+
+// snippet shortens text to about max runes at a word boundary
+func snippet(text string, max int) string {
+	r := []rune(text)
+	if len(r) <= max {
+		return text
+	}
+	cut := string(r[:max])
+	if i := strings.LastIndexByte(cut, ' '); i > max/2 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
@@ -0,0 +1,145 @@
+package vector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{0.5, 0.25}, nil
+}
+
+func newSource(t *testing.T, provider string, handler http.Handler) *DataSourceVector {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	es := New()
+	es.Provider = provider
+	es.BaseURL = srv.URL
+	es.Collection = "Docs"
+	es.APIKey = "secret"
+	es.Embed = embed
+	return es
+}
+
+func TestQdrant(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /collections/Docs/points/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("api-key") != "secret" {
+			t.Errorf("api-key = %q", r.Header.Get("api-key"))
+		}
+		var request struct {
+			Vector struct {
+				Name   string    `json:"name"`
+				Vector []float32 `json:"vector"`
+			} `json:"vector"`
+			Limit       int  `json:"limit"`
+			WithPayload bool `json:"with_payload"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+		if request.Vector.Name != "body" || len(request.Vector.Vector) != 2 || request.Limit != 3 || !request.WithPayload {
+			t.Errorf("unexpected request %+v", request)
+		}
+		fmt.Fprint(w, `{"result":[
+			{"id":7,"score":0.912,"payload":{"title":"Setup","url":"https://example.com/setup","text":"Install the tools first.","meta":{"lang":"en"}}},
+			{"id":"5c56c793-69f3-4fbf-87e6-c4bf54c28c26","score":0.65,"payload":{"text":"An untitled chunk of text"}},
+			{"id":9,"score":0.2,"payload":{"title":"Far away"}}
+		]}`)
+	})
+	es := newSource(t, ProviderQdrant, mux)
+	es.VectorName = "body"
+	es.MinScore = 0.5
+	es.DataFields = []string{"meta.lang"}
+
+	topics, err := es.FetchTopics(3, "how do I install")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Setup (similarity 0.91)", "An untitled chunk of text (similarity 0.65)"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if topics[i].Topic != w || topics[i].Site != "Docs" {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, w)
+		}
+	}
+
+	data, err := es.FetchData(1, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "Setup\nInstall the tools first.\nmeta.lang: en\nsimilarity: 0.912"
+	if len(data) != 1 || data[0].DataText != wantData || data[0].SourceURL != "https://example.com/setup" {
+		t.Errorf("FetchData = %+v, want %q", data, wantData)
+	}
+}
+
+func TestWeaviate(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/graphql", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		var request struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+		wantQuery := "{ Get { Docs(nearVector: {vector: [0.5,0.25]}, limit: 5) { text title url _additional { id certainty distance } } } }"
+		if request.Query != wantQuery {
+			t.Errorf("query = %s", request.Query)
+		}
+		fmt.Fprint(w, `{"data":{"Get":{"Docs":[
+			{"title":"Setup","url":"https://example.com/setup","text":"Install the tools first.","_additional":{"id":"a1","certainty":0.875}},
+			{"title":"Dot product","text":"Scored by distance","_additional":{"id":"b2","certainty":null,"distance":0.25}}
+		]}}}`)
+	})
+	es := newSource(t, ProviderWeaviate, mux)
+
+	topics, err := es.FetchTopics(0, "install")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Setup (similarity 0.88)", "Dot product (similarity 0.75)"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if topics[i].Topic != w {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, w)
+		}
+	}
+}
+
+func TestWeaviateErrors(t *testing.T) {
+	es := newSource(t, ProviderWeaviate, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"Get":null},"errors":[{"message":"Cannot query field \"url\" on type \"Docs\"."}]}`)
+	}))
+	if _, err := es.FetchTopics(5, "install"); err == nil || !strings.Contains(err.Error(), "Cannot query field") {
+		t.Errorf("FetchTopics = %v", err)
+	}
+}
+
+func TestCheckAvailability(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /collections/Docs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"status":"green"}}`)
+	})
+	es := newSource(t, ProviderQdrant, mux)
+	if !es.CheckAvailability() {
+		t.Error("CheckAvailability = false for an existing collection")
+	}
+	es.Collection = "Missing"
+	if es.CheckAvailability() {
+		t.Error("CheckAvailability = true for a missing collection")
+	}
+}
@@ -0,0 +1,292 @@
+package wallabag
+
+// Data Source Adapter for articles saved to a Wallabag read-it-later account (wallabag.it or self-hosted)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/auth"
+	"github.com/locus-search/datasource/internal/readability"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultDataCount  = 3
+	// Paragraphs are grouped into data items of about this many characters
+	chunkSize = 1500
+)
+
+type DataSourceWallabag struct {
+	Client    *http.Client
+	BaseURL   string // Instance root, e.g. https://app.wallabag.it or https://wallabag.example.com
+	UserAgent string
+
+	// API client created under "API clients management", and the account that owns the articles
+	ClientID     string
+	ClientSecret string
+	Username     string
+	Password     string
+
+	session  *auth.PasswordGrant
+	articles topicid.Map[article]
+}
+
+type article struct {
+	ID          int64
+	Title       string
+	URL         string
+	Domain      string
+	ReadingTime int
+}
+
+// entry is a Wallabag entry as returned by the API
+type entry struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Domain      string `json:"domain_name"`
+	Content     string `json:"content"`
+	ReadingTime int    `json:"reading_time"`
+	Tags        []struct {
+		Label string `json:"label"`
+	} `json:"tags"`
+}
+
+func New() *DataSourceWallabag {
+	return &DataSourceWallabag{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://app.wallabag.it",
+		UserAgent: "locus/wallabag-datasource",
+	}
+}
+
+// Init implements models.DataSource
+// Sets up the OAuth password grant; the token itself is requested lazily on first use
+func (es *DataSourceWallabag) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.ClientID == "" || es.ClientSecret == "" || es.Username == "" || es.Password == "" {
+		return errors.New("ClientID, ClientSecret, Username and Password are required for Wallabag DataSource")
+	}
+	if es.session == nil {
+		es.session = &auth.PasswordGrant{
+			Client:       es.Client,
+			TokenURL:     strings.TrimRight(es.BaseURL, "/") + "/oauth/v2/token",
+			ClientID:     es.ClientID,
+			ClientSecret: es.ClientSecret,
+			Username:     es.Username,
+			Password:     es.Password,
+		}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceWallabag) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	return es.doJSON(ctx, "/api/user.json", &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceWallabag) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "wallabag",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Searches the titles and text of saved articles; topics are titled "Title (domain, 7 min read)"
+func (es *DataSourceWallabag) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Wallabag DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("term", query)
+	params.Set("perPage", strconv.Itoa(count))
+	params.Set("page", "1")
+	var response struct {
+		Embedded struct {
+			Items []entry `json:"items"`
+		} `json:"_embedded"`
+	}
+	if err := es.doJSON(ctx, "/api/search.json?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(response.Embedded.Items))
+	for _, e := range response.Embedded.Items {
+		a := article{ID: e.ID, Title: strings.TrimSpace(e.Title), URL: e.URL, Domain: e.Domain, ReadingTime: e.ReadingTime}
+		if a.Title == "" {
+			a.Title = a.URL
+		}
+		var details []string
+		if a.Domain != "" {
+			details = append(details, a.Domain)
+		}
+		if a.ReadingTime > 0 {
+			details = append(details, strconv.Itoa(a.ReadingTime)+" min read")
+		}
+		topic := a.Title
+		if len(details) > 0 {
+			topic += " (" + strings.Join(details, ", ") + ")"
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     topic,
+			SourceURL: a.URL,
+			Site:      es.site(a),
+			TopicID:   es.articles.Put(es.BaseURL+"/"+strconv.FormatInt(a.ID, 10), a),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns the article text Wallabag extracted when saving it, in items of about 1500 characters, each
+// starting with the title; the first also lists the article's tags
+func (es *DataSourceWallabag) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	a, ok := es.articles.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Wallabag topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultDataCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var e entry
+	if err := es.doJSON(ctx, "/api/entries/"+strconv.FormatInt(a.ID, 10)+".json", &e); err != nil {
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(e.Content))
+	if err != nil {
+		return nil, err
+	}
+	// The content is already the extracted article, so every paragraph is kept
+	paragraphs := readability.Paragraphs(doc.Find("body"))
+	var tags []string
+	for _, t := range e.Tags {
+		tags = append(tags, t.Label)
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, chunk := range chunks(paragraphs, chunkSize) {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		text := a.Title + "\n" + chunk
+		if len(results) == 0 && len(tags) > 0 {
+			text = a.Title + "\nTags: " + strings.Join(tags, ", ") + "\n\n" + chunk
+		}
+		if len(results) > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%s/%d#%d", es.BaseURL, a.ID, len(results)))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  text,
+			SourceURL: a.URL,
+			Site:      es.site(a),
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+func (es *DataSourceWallabag) doJSON(ctx context.Context, path string, target interface{}) error {
+	token, err := es.session.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(es.BaseURL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := es.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		es.session.Invalidate()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("wallabag request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func (es *DataSourceWallabag) site(a article) string {
+	if a.Domain != "" {
+		return a.Domain
+	}
+	if u, err := url.Parse(a.URL); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	return "wallabag"
+}
+
+// Helpers
+
+// chunks joins paragraphs into blocks of roughly size characters without splitting a paragraph
+func chunks(paragraphs []string, size int) []string {
+	var out []string
+	var current strings.Builder
+	for _, p := range paragraphs {
+		if current.Len() > 0 && current.Len()+len(p) > size {
+			out = append(out, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(p)
+	}
+	if current.Len() > 0 {
+		out = append(out, current.String())
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package wallabag
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newSource(t *testing.T) (*DataSourceWallabag, *int) {
+	t.Helper()
+	tokens := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /oauth/v2/token", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		r.ParseForm()
+		if r.PostForm.Get("grant_type") != "password" || r.PostForm.Get("username") != "alice" {
+			t.Errorf("token request %v", r.PostForm)
+		}
+		fmt.Fprintf(w, `{"access_token":"token%d","expires_in":3600}`, tokens)
+	})
+	mux.HandleFunc("GET /api/search.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token%d", tokens) {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		q := r.URL.Query()
+		if q.Get("term") != "rust async" || q.Get("perPage") != "3" || q.Get("page") != "1" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"page":1,"_embedded":{"items":[
+			{"id":12,"title":" Async Rust explained ","url":"https://blog.example.com/async","domain_name":"blog.example.com","reading_time":7},
+			{"id":13,"title":"","url":"https://news.example.org/item?id=1","domain_name":null,"reading_time":0}
+		]}}`)
+	})
+	mux.HandleFunc("GET /api/entries/12.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":12,"content":"<h2>Futures</h2><p>Futures are lazy and do nothing until they are awaited.</p><p>An executor polls them when their wakers fire.</p>",
+			"tags":[{"label":"rust"},{"label":"to-read"}]}`)
+	})
+	mux.HandleFunc("GET /api/user.json", func(w http.ResponseWriter, r *http.Request) {
+		if tokens == 1 {
+			// The first token is revoked, so the next request needs a new one
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"username":"alice"}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	es := New()
+	es.BaseURL = srv.URL + "/"
+	es.ClientID = "id"
+	es.ClientSecret = "secret"
+	es.Username = "alice"
+	es.Password = "password"
+	return es, &tokens
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es, tokens := newSource(t)
+	topics, err := es.FetchTopics(3, "rust async")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Async Rust explained (blog.example.com, 7 min read)|blog.example.com",
+		"https://news.example.org/item?id=1|news.example.org",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].Site; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "Async Rust explained\nTags: rust, to-read\n\nFutures\n\nFutures are lazy and do nothing until they are awaited.\n\nAn executor polls them when their wakers fire."
+	if len(data) != 1 || data[0].DataText != wantData || data[0].SourceURL != "https://blog.example.com/async" {
+		t.Errorf("FetchData = %q, want %q", data, wantData)
+	}
+	if *tokens != 1 {
+		t.Errorf("requested %d tokens, want the first reused", *tokens)
+	}
+}
+
+func TestUnauthorizedRenewsToken(t *testing.T) {
+	es, tokens := newSource(t)
+	if es.CheckAvailability() {
+		t.Error("CheckAvailability = true with a revoked token")
+	}
+	if !es.CheckAvailability() {
+		t.Error("CheckAvailability = false after renewing the token")
+	}
+	if *tokens != 2 {
+		t.Errorf("requested %d tokens, want 2", *tokens)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	got := chunks([]string{"aaaa", "bbbb", "cc", strings.Repeat("d", 12)}, 10)
+	want := []string{"aaaa\n\nbbbb", "cc", strings.Repeat("d", 12)}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("chunks = %q, want %q", got, want)
+	}
+}
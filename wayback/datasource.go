@@ -0,0 +1,346 @@
+package wayback
+
+// Data Source Adapter for the Internet Archive Wayback Machine (CDX and availability APIs)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	goquery "github.com/PuerkitoBio/goquery"
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	chunkBytes        = 4000
+	// CDX timestamps are YYYYMMDDhhmmss
+	timestampLayout = "20060102150405"
+)
+
+type DataSourceWayback struct {
+	Client        *http.Client
+	ArchiveURL    string // Wayback Machine root
+	AvailableURL  string // Availability API endpoint
+	UserAgent     string
+	CollapseDaily bool   // Keep at most one snapshot per day
+	From          string // Earliest snapshot, any prefix of YYYYMMDDhhmmss
+	To            string // Latest snapshot, any prefix of YYYYMMDDhhmmss
+
+	snapshots topicid.Map[snapshot]
+}
+
+// snapshot is one capture of a URL
+type snapshot struct {
+	Timestamp string
+	Original  string
+}
+
+func New() *DataSourceWayback {
+	return &DataSourceWayback{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		ArchiveURL:    "https://web.archive.org",
+		AvailableURL:  "https://archive.org/wayback/available",
+		UserAgent:     "locus/wayback-datasource",
+		CollapseDaily: true,
+	}
+}
+
+// Init implements models.DataSource
+// The Wayback Machine requires no initialization
+func (es *DataSourceWayback) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceWayback) CheckAvailability() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.closest(ctx, "example.com", "")
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceWayback) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:        "Wayback Machine",
+		Homepage:    "https://web.archive.org",
+		Attribution: "Internet Archive Wayback Machine",
+		TermsURL:    "https://archive.org/about/terms.php",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// The input must be a URL; its most recent successful captures are the topics, newest first,
+// titled "example.com/page archived 2023-04-01 12:00 UTC"
+func (es *DataSourceWayback) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	target := strings.TrimSpace(input)
+	if target == "" {
+		return nil, errors.New("Missing search input for Wayback DataSource")
+	}
+	if !looksLikeURL(target) {
+		return nil, fmt.Errorf("wayback input must be a URL, got %q", target)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("url", target)
+	params.Set("output", "json")
+	params.Set("fl", "timestamp,original")
+	params.Add("filter", "statuscode:200")
+	params.Add("filter", "mimetype:text/html")
+	// A negative limit returns the last captures rather than the first
+	params.Set("limit", "-"+strconv.Itoa(count))
+	if es.CollapseDaily {
+		params.Set("collapse", "timestamp:8")
+	}
+	if es.From != "" {
+		params.Set("from", es.From)
+	}
+	if es.To != "" {
+		params.Set("to", es.To)
+	}
+
+	// Rows are arrays of strings; the first is the field header
+	var rows [][]string
+	if err := es.doJSON(ctx, strings.TrimRight(es.ArchiveURL, "/")+"/cdx/search/cdx?"+params.Encode(), &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) > 0 {
+		rows = rows[1:]
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(rows))
+	for i := len(rows) - 1; i >= 0; i-- {
+		if len(rows[i]) < 2 {
+			continue
+		}
+		snap := snapshot{Timestamp: rows[i][0], Original: rows[i][1]}
+		results = append(results, es.topic(snap))
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Loads the archived page without the Wayback toolbar and returns its text in chunks, up to count
+func (es *DataSourceWayback) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	snap, ok := es.snapshots.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Wayback topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	chunks, err := es.pageChunks(ctx, snap)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]datasource.DataSourceData, 0, count)
+	for i, chunk := range chunks {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  chunk,
+			SourceURL: es.snapshotLink(snap, ""),
+			Site:      hostOf(snap.Original),
+			AnswerID:  topicid.Hash(fmt.Sprintf("%s/%s#%d", snap.Timestamp, snap.Original, i)),
+		})
+	}
+	return results, nil
+}
+
+// FetchArchived returns the text of the capture closest to now for pageURL. It lets other adapters
+// fall back to the archive when a live page is gone or paywalled.
+func (es *DataSourceWayback) FetchArchived(ctx context.Context, pageURL string) ([]datasource.DataSourceData, error) {
+	snap, err := es.closest(ctx, pageURL, "")
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := es.pageChunks(ctx, snap)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]datasource.DataSourceData, 0, len(chunks))
+	for i, chunk := range chunks {
+		results = append(results, datasource.DataSourceData{
+			DataText:  chunk,
+			SourceURL: es.snapshotLink(snap, ""),
+			Site:      hostOf(snap.Original),
+			AnswerID:  topicid.Hash(fmt.Sprintf("%s/%s#%d", snap.Timestamp, snap.Original, i)),
+		})
+	}
+	return results, nil
+}
+
+// closest asks the availability API for the capture nearest to timestamp (or the latest when empty)
+func (es *DataSourceWayback) closest(ctx context.Context, pageURL, timestamp string) (snapshot, error) {
+	params := url.Values{}
+	params.Set("url", pageURL)
+	if timestamp != "" {
+		params.Set("timestamp", timestamp)
+	}
+	var response struct {
+		ArchivedSnapshots struct {
+			Closest *struct {
+				Available bool   `json:"available"`
+				Timestamp string `json:"timestamp"`
+				Status    string `json:"status"`
+			} `json:"closest"`
+		} `json:"archived_snapshots"`
+	}
+	if err := es.doJSON(ctx, es.AvailableURL+"?"+params.Encode(), &response); err != nil {
+		return snapshot{}, err
+	}
+	closest := response.ArchivedSnapshots.Closest
+	if closest == nil || !closest.Available {
+		return snapshot{}, fmt.Errorf("no Wayback capture of %s", pageURL)
+	}
+	return snapshot{Timestamp: closest.Timestamp, Original: pageURL}, nil
+}
+
+// pageChunks downloads a capture and flattens it to text chunks of about chunkBytes
+func (es *DataSourceWayback) pageChunks(ctx context.Context, snap snapshot) ([]string, error) {
+	// The id_ flag serves the original bytes without the toolbar or rewritten links
+	body, err := es.get(ctx, es.snapshotLink(snap, "id_"))
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return nil, err
+	}
+	doc.Find("script, style, noscript, nav, footer, header, aside").Remove()
+
+	var chunks []string
+	var b strings.Builder
+	doc.Find("h1, h2, h3, h4, p, li, pre, blockquote, td").Each(func(_ int, s *goquery.Selection) {
+		if s.ParentsFiltered("li, blockquote, td").Length() > 0 {
+			return
+		}
+		text := strings.Join(strings.Fields(s.Text()), " ")
+		if text == "" {
+			return
+		}
+		if b.Len() > 0 && b.Len()+len(text) > chunkBytes {
+			chunks = append(chunks, b.String())
+			b.Reset()
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(text)
+	})
+	if b.Len() > 0 {
+		chunks = append(chunks, b.String())
+	}
+	if len(chunks) == 0 {
+		if text := strings.Join(strings.Fields(doc.Text()), " "); text != "" {
+			chunks = append(chunks, text)
+		}
+	}
+	return chunks, nil
+}
+
+func (es *DataSourceWayback) topic(snap snapshot) datasource.DataSourceTopic {
+	when := snap.Timestamp
+	if t, err := time.Parse(timestampLayout, snap.Timestamp); err == nil {
+		when = t.Format("2006-01-02 15:04 UTC")
+	}
+	return datasource.DataSourceTopic{
+		Topic:     fmt.Sprintf("%s archived %s", snap.Original, when),
+		SourceURL: es.snapshotLink(snap, ""),
+		Site:      hostOf(snap.Original),
+		TopicID:   es.snapshots.Put(snap.Timestamp+"/"+snap.Original, snap),
+	}
+}
+
+// snapshotLink builds /web/<timestamp><flag>/<original>
+func (es *DataSourceWayback) snapshotLink(snap snapshot, flag string) string {
+	return fmt.Sprintf("%s/web/%s%s/%s", strings.TrimRight(es.ArchiveURL, "/"), snap.Timestamp, flag, snap.Original)
+}
+
+// doJSON performs an HTTP GET request and decodes the JSON response into target
+func (es *DataSourceWayback) doJSON(ctx context.Context, uri string, target interface{}) error {
+	body, err := es.get(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(body).Decode(target)
+}
+
+// get performs an HTTP GET request. The caller must close the returned body.
+func (es *DataSourceWayback) get(ctx context.Context, uri string) (io.ReadCloser, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("wayback request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// Helpers
+
+// looksLikeURL accepts full URLs and bare hosts with a path, e.g. "example.com/page"
+func looksLikeURL(input string) bool {
+	if strings.ContainsAny(input, " \t\n") {
+		return false
+	}
+	if u, err := url.Parse(input); err == nil && u.Scheme != "" && u.Host != "" {
+		return true
+	}
+	host := strings.SplitN(input, "/", 2)[0]
+	return strings.Contains(host, ".")
+}
+
+func hostOf(rawURL string) string {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	if u, err := url.Parse(rawURL); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
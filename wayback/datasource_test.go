@@ -0,0 +1,98 @@
+package wayback
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const page = `<html><head><script>var x;</script></head><body>
+	<nav>Home | About</nav>
+	<h1>Example Page</h1>
+	<p>An archived   paragraph.</p>
+	<ul><li>One <p>nested</p></li></ul>
+	<footer>Copyright</footer>
+</body></html>`
+
+// newServer serves both the archive and the availability API. ServeMux is avoided because it would
+// redirect the "//" in the original URL of snapshot paths.
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.URL.Path == "/cdx/search/cdx":
+			if q.Get("url") != "example.com/page" || q.Get("limit") != "-5" || q.Get("collapse") != "timestamp:8" || len(q["filter"]) != 2 || q.Get("from") != "2023" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `[["timestamp","original"],["20230101000000","https://example.com/page"],["20230401120000","https://example.com/page"]]`)
+		case r.URL.Path == "/available":
+			if q.Get("url") == "https://example.com/gone" {
+				fmt.Fprint(w, `{"archived_snapshots":{}}`)
+				return
+			}
+			fmt.Fprint(w, `{"archived_snapshots":{"closest":{"available":true,"timestamp":"20230401120000","status":"200"}}}`)
+		case r.URL.Path == "/web/20230401120000id_/https://example.com/page":
+			fmt.Fprint(w, page)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSource(t *testing.T) *DataSourceWayback {
+	t.Helper()
+	srv := newServer(t)
+	es := New()
+	es.ArchiveURL = srv.URL
+	es.AvailableURL = srv.URL + "/available"
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	es.From = "2023"
+
+	topics, err := es.FetchTopics(5, "example.com/page")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[0].Topic != "https://example.com/page archived 2023-04-01 12:00 UTC" ||
+		topics[0].SourceURL != es.ArchiveURL+"/web/20230401120000/https://example.com/page" || topics[0].Site != "example.com" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Example Page\n\nAn archived paragraph.\n\nOne nested"
+	if len(data) != 1 || data[0].DataText != want {
+		t.Errorf("FetchData = %+v, want %q", data, want)
+	}
+}
+
+func TestFetchArchived(t *testing.T) {
+	es := newSource(t)
+	data, err := es.FetchArchived(t.Context(), "https://example.com/page")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || !strings.HasPrefix(data[0].DataText, "Example Page") {
+		t.Errorf("FetchArchived = %+v", data)
+	}
+	if _, err := es.FetchArchived(t.Context(), "https://example.com/gone"); err == nil {
+		t.Error("FetchArchived succeeded for a URL without captures")
+	}
+}
+
+func TestFetchTopicsRejectsText(t *testing.T) {
+	if _, err := New().FetchTopics(5, "not a url"); err == nil {
+		t.Error("FetchTopics accepted a search phrase")
+	}
+}
@@ -0,0 +1,507 @@
+package weather
+
+// Data Source Adapter for weather conditions and forecasts (weather.gov or OpenWeatherMap)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+// Supported backends
+const (
+	ProviderNWS            = "nws"            // US National Weather Service; no key, US locations only
+	ProviderOpenWeatherMap = "openweathermap" // Worldwide; requires an API key
+)
+
+type DataSourceWeather struct {
+	Client     *http.Client
+	Provider   string // ProviderNWS or ProviderOpenWeatherMap
+	APIKey     string // OpenWeatherMap API key
+	Units      string // "metric" or "imperial"; applies to OpenWeatherMap, NWS always reports its own units
+	NWSURL     string
+	OWMURL     string
+	GeocodeURL string // Nominatim-compatible search endpoint used to geocode locations for the NWS backend
+	UserAgent  string // weather.gov and Nominatim both require an identifying agent
+
+	locations topicid.Map[location]
+}
+
+// location is a geocoded place that can be looked up again by FetchData
+type location struct {
+	Name string
+	Lat  float64
+	Lon  float64
+}
+
+func New() *DataSourceWeather {
+	return &DataSourceWeather{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		Provider:   ProviderNWS,
+		Units:      "metric",
+		NWSURL:     "https://api.weather.gov",
+		OWMURL:     "https://api.openweathermap.org",
+		GeocodeURL: "https://nominatim.openstreetmap.org/search",
+		UserAgent:  "locus/weather-datasource (https://github.com/locus-search/datasource)",
+	}
+}
+
+// Init implements models.DataSource
+// Validates the provider configuration
+func (es *DataSourceWeather) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	switch es.Provider {
+	case ProviderNWS:
+		if es.UserAgent == "" {
+			return errors.New("UserAgent is required for the weather.gov backend")
+		}
+	case ProviderOpenWeatherMap:
+		if es.APIKey == "" {
+			return errors.New("APIKey is required for the OpenWeatherMap backend")
+		}
+	default:
+		return fmt.Errorf("unknown weather provider %q", es.Provider)
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceWeather) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	if es.Provider == ProviderOpenWeatherMap {
+		_, err := es.geocodeOWM(ctx, "London", 1)
+		return err == nil
+	}
+	return es.doJSON(ctx, strings.TrimRight(es.NWSURL, "/"), &struct{}{}) == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceWeather) Info() source.SourceInfo {
+	if es.Provider == ProviderOpenWeatherMap {
+		return source.SourceInfo{
+			Name:                "OpenWeather",
+			Homepage:            "https://openweathermap.org",
+			License:             "CC-BY-SA-4.0",
+			LicenseURL:          "https://creativecommons.org/licenses/by-sa/4.0/",
+			Attribution:         "Weather data from OpenWeather",
+			AttributionRequired: true,
+			TermsURL:            "https://openweathermap.org/terms",
+		}
+	}
+	return source.SourceInfo{
+		Name:                "National Weather Service",
+		Homepage:            "https://www.weather.gov",
+		License:             "Public domain",
+		Attribution:         "Forecast from the National Weather Service; geocoding © OpenStreetMap contributors",
+		AttributionRequired: true,
+		TermsURL:            "https://www.weather.gov/disclaimer",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Geocodes the query; each candidate place is a topic. "lat,lon" input is used as is.
+func (es *DataSourceWeather) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Weather DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	var places []location
+	if place, ok := parseCoordinates(query); ok {
+		places = []location{place}
+	} else {
+		var err error
+		if es.Provider == ProviderOpenWeatherMap {
+			places, err = es.geocodeOWM(ctx, query, count)
+		} else {
+			places, err = es.geocodeNominatim(ctx, query, count)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, len(places))
+	for _, place := range places {
+		if len(results) >= count {
+			break
+		}
+		// Four decimals (~10 m) is finer than any forecast grid and keeps IDs stable across geocoders
+		place.Lat, place.Lon = round(place.Lat, 4), round(place.Lon, 4)
+		key := fmt.Sprintf("%.4f,%.4f", place.Lat, place.Lon)
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf("Weather for %s (%s)", place.Name, key),
+			SourceURL: es.siteLink(place),
+			Site:      es.Provider,
+			TopicID:   es.locations.Put(key, place),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Returns current conditions first, then up to count-1 forecast periods
+func (es *DataSourceWeather) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	place, ok := es.locations.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Weather topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	var texts []string
+	var err error
+	if es.Provider == ProviderOpenWeatherMap {
+		texts, err = es.reportOWM(ctx, place, count)
+	} else {
+		texts, err = es.reportNWS(ctx, place, count)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceData, 0, len(texts))
+	for i, text := range texts {
+		results = append(results, datasource.DataSourceData{
+			DataText:  text,
+			SourceURL: es.siteLink(place),
+			Site:      es.Provider,
+			AnswerID:  topicid.Hash(fmt.Sprintf("%d#%d", topicID, i)),
+		})
+	}
+	return results, nil
+}
+
+// reportNWS resolves the forecast office grid for a point, then reads the latest observation
+// from the nearest station and the named forecast periods ("Tonight", "Saturday", ...)
+func (es *DataSourceWeather) reportNWS(ctx context.Context, place location, count int) ([]string, error) {
+	var point struct {
+		Properties struct {
+			Forecast            string `json:"forecast"`
+			ObservationStations string `json:"observationStations"`
+		} `json:"properties"`
+	}
+	uri := fmt.Sprintf("%s/points/%.4f,%.4f", strings.TrimRight(es.NWSURL, "/"), place.Lat, place.Lon)
+	if err := es.doJSON(ctx, uri, &point); err != nil {
+		return nil, fmt.Errorf("weather.gov has no forecast for %s (US locations only): %w", place.Name, err)
+	}
+
+	var texts []string
+	// A missing observation still leaves a useful forecast
+	if current, err := es.currentNWS(ctx, point.Properties.ObservationStations); err == nil && current != "" {
+		texts = append(texts, fmt.Sprintf("Current conditions near %s: %s", place.Name, current))
+	}
+
+	var forecast struct {
+		Properties struct {
+			Periods []struct {
+				Name             string `json:"name"`
+				DetailedForecast string `json:"detailedForecast"`
+			} `json:"periods"`
+		} `json:"properties"`
+	}
+	if err := es.doJSON(ctx, point.Properties.Forecast, &forecast); err != nil {
+		if len(texts) > 0 {
+			return texts, nil
+		}
+		return nil, err
+	}
+	for _, period := range forecast.Properties.Periods {
+		if len(texts) >= count {
+			break
+		}
+		texts = append(texts, fmt.Sprintf("%s forecast for %s: %s", period.Name, place.Name, period.DetailedForecast))
+	}
+	return texts, nil
+}
+
+// currentNWS describes the latest observation of the first station in the list
+func (es *DataSourceWeather) currentNWS(ctx context.Context, stationsURL string) (string, error) {
+	var stations struct {
+		Features []struct {
+			Properties struct {
+				StationIdentifier string `json:"stationIdentifier"`
+				Name              string `json:"name"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := es.doJSON(ctx, stationsURL, &stations); err != nil {
+		return "", err
+	}
+	if len(stations.Features) == 0 {
+		return "", nil
+	}
+	station := stations.Features[0].Properties
+
+	type measurement struct {
+		Value *float64 `json:"value"`
+	}
+	var observation struct {
+		Properties struct {
+			Timestamp        string      `json:"timestamp"`
+			TextDescription  string      `json:"textDescription"`
+			Temperature      measurement `json:"temperature"`
+			RelativeHumidity measurement `json:"relativeHumidity"`
+			WindSpeed        measurement `json:"windSpeed"`
+		} `json:"properties"`
+	}
+	uri := fmt.Sprintf("%s/stations/%s/observations/latest", strings.TrimRight(es.NWSURL, "/"), url.PathEscape(station.StationIdentifier))
+	if err := es.doJSON(ctx, uri, &observation); err != nil {
+		return "", err
+	}
+
+	obs := observation.Properties
+	parts := []string{}
+	if obs.TextDescription != "" {
+		parts = append(parts, obs.TextDescription)
+	}
+	if v := obs.Temperature.Value; v != nil {
+		parts = append(parts, fmt.Sprintf("%.1f°C (%.0f°F)", *v, *v*9/5+32))
+	}
+	if v := obs.RelativeHumidity.Value; v != nil {
+		parts = append(parts, fmt.Sprintf("humidity %.0f%%", *v))
+	}
+	if v := obs.WindSpeed.Value; v != nil {
+		parts = append(parts, fmt.Sprintf("wind %.0f km/h", *v))
+	}
+	if len(parts) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%s (station %s, %s, observed %s)", strings.Join(parts, ", "), station.StationIdentifier, station.Name, obs.Timestamp), nil
+}
+
+// reportOWM returns current weather followed by one entry per forecast day
+func (es *DataSourceWeather) reportOWM(ctx context.Context, place location, count int) ([]string, error) {
+	params := es.owmParams()
+	params.Set("lat", strconv.FormatFloat(place.Lat, 'f', 4, 64))
+	params.Set("lon", strconv.FormatFloat(place.Lon, 'f', 4, 64))
+	tempUnit, speedUnit := "°C", "m/s"
+	if es.Units == "imperial" {
+		tempUnit, speedUnit = "°F", "mph"
+	}
+
+	type conditions struct {
+		DtTxt   string `json:"dt_txt"`
+		Weather []struct {
+			Description string `json:"description"`
+		} `json:"weather"`
+		Main struct {
+			Temp      float64 `json:"temp"`
+			FeelsLike float64 `json:"feels_like"`
+			TempMin   float64 `json:"temp_min"`
+			TempMax   float64 `json:"temp_max"`
+			Humidity  float64 `json:"humidity"`
+		} `json:"main"`
+		Wind struct {
+			Speed float64 `json:"speed"`
+		} `json:"wind"`
+	}
+	describe := func(c conditions) string {
+		if len(c.Weather) > 0 {
+			return c.Weather[0].Description
+		}
+		return ""
+	}
+
+	var current conditions
+	if err := es.doJSON(ctx, strings.TrimRight(es.OWMURL, "/")+"/data/2.5/weather?"+params.Encode(), &current); err != nil {
+		return nil, err
+	}
+	texts := []string{fmt.Sprintf("Current conditions in %s: %s, %.1f%s (feels like %.1f%s), humidity %.0f%%, wind %.1f %s",
+		place.Name, describe(current), current.Main.Temp, tempUnit, current.Main.FeelsLike, tempUnit, current.Main.Humidity, current.Wind.Speed, speedUnit)}
+	if count == 1 {
+		return texts, nil
+	}
+
+	var forecast struct {
+		List []conditions `json:"list"`
+	}
+	if err := es.doJSON(ctx, strings.TrimRight(es.OWMURL, "/")+"/data/2.5/forecast?"+params.Encode(), &forecast); err != nil {
+		return texts, nil
+	}
+	// The forecast comes in 3-hour steps; fold it into daily ranges, describing each day by its midday step
+	type day struct {
+		date     string
+		low      float64
+		high     float64
+		describe string
+	}
+	var days []*day
+	for _, step := range forecast.List {
+		date, clock, _ := strings.Cut(step.DtTxt, " ")
+		if len(days) == 0 || days[len(days)-1].date != date {
+			days = append(days, &day{date: date, low: step.Main.TempMin, high: step.Main.TempMax, describe: describe(step)})
+		}
+		d := days[len(days)-1]
+		d.low = math.Min(d.low, step.Main.TempMin)
+		d.high = math.Max(d.high, step.Main.TempMax)
+		if strings.HasPrefix(clock, "12:") {
+			d.describe = describe(step)
+		}
+	}
+	for _, d := range days {
+		if len(texts) >= count {
+			break
+		}
+		texts = append(texts, fmt.Sprintf("Forecast for %s on %s: %s, low %.0f%s, high %.0f%s", place.Name, d.date, d.describe, d.low, tempUnit, d.high, tempUnit))
+	}
+	return texts, nil
+}
+
+// geocodeOWM resolves a place name with the OpenWeatherMap geocoding API
+func (es *DataSourceWeather) geocodeOWM(ctx context.Context, query string, count int) ([]location, error) {
+	params := es.owmParams()
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(count))
+	var response []struct {
+		Name    string  `json:"name"`
+		State   string  `json:"state"`
+		Country string  `json:"country"`
+		Lat     float64 `json:"lat"`
+		Lon     float64 `json:"lon"`
+	}
+	if err := es.doJSON(ctx, strings.TrimRight(es.OWMURL, "/")+"/geo/1.0/direct?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+	places := make([]location, 0, len(response))
+	for _, item := range response {
+		name := item.Name
+		for _, part := range []string{item.State, item.Country} {
+			if part != "" {
+				name += ", " + part
+			}
+		}
+		places = append(places, location{Name: name, Lat: item.Lat, Lon: item.Lon})
+	}
+	return places, nil
+}
+
+// geocodeNominatim resolves a place name with a Nominatim search endpoint
+func (es *DataSourceWeather) geocodeNominatim(ctx context.Context, query string, count int) ([]location, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "jsonv2")
+	params.Set("limit", strconv.Itoa(count))
+	var response []struct {
+		DisplayName string `json:"display_name"`
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+	}
+	if err := es.doJSON(ctx, es.GeocodeURL+"?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+	places := make([]location, 0, len(response))
+	for _, item := range response {
+		lat, errLat := strconv.ParseFloat(item.Lat, 64)
+		lon, errLon := strconv.ParseFloat(item.Lon, 64)
+		if errLat != nil || errLon != nil {
+			continue
+		}
+		places = append(places, location{Name: item.DisplayName, Lat: lat, Lon: lon})
+	}
+	return places, nil
+}
+
+func (es *DataSourceWeather) owmParams() url.Values {
+	params := url.Values{}
+	params.Set("appid", es.APIKey)
+	if es.Units != "" {
+		params.Set("units", es.Units)
+	}
+	return params
+}
+
+// siteLink points at the human-readable forecast page for a place
+func (es *DataSourceWeather) siteLink(place location) string {
+	if es.Provider == ProviderOpenWeatherMap {
+		return fmt.Sprintf("https://openweathermap.org/weathermap?lat=%.4f&lon=%.4f", place.Lat, place.Lon)
+	}
+	return fmt.Sprintf("https://forecast.weather.gov/MapClick.php?lat=%.4f&lon=%.4f", place.Lat, place.Lon)
+}
+
+// doJSON performs an HTTP GET request and decodes the JSON response into target
+func (es *DataSourceWeather) doJSON(ctx context.Context, uri string, target interface{}) error {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/geo+json, application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("weather request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+
+// parseCoordinates accepts "lat,lon" input such as "47.6062,-122.3321"
+func parseCoordinates(input string) (location, bool) {
+	latText, lonText, found := strings.Cut(input, ",")
+	if !found {
+		return location{}, false
+	}
+	lat, errLat := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	lon, errLon := strconv.ParseFloat(strings.TrimSpace(lonText), 64)
+	if errLat != nil || errLon != nil || math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+		return location{}, false
+	}
+	return location{Name: fmt.Sprintf("%.4f,%.4f", lat, lon), Lat: lat, Lon: lon}, true
+}
+
+func round(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,136 @@
+package weather
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNWS(t *testing.T) {
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "Seattle" || r.URL.Query().Get("format") != "jsonv2" {
+			t.Errorf("unexpected geocode query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[{"display_name":"Seattle, Washington","lat":"47.60621","lon":"-122.33207"},{"display_name":"Bad","lat":"x","lon":"y"}]`)
+	})
+	mux.HandleFunc("/points/47.6062,-122.3321", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"properties":{"forecast":"%[1]s/gridpoints/SEW/124,67/forecast","observationStations":"%[1]s/gridpoints/SEW/124,67/stations"}}`, srv.URL)
+	})
+	mux.HandleFunc("/gridpoints/SEW/124,67/stations", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"features":[{"properties":{"stationIdentifier":"KBFI","name":"Boeing Field"}}]}`)
+	})
+	mux.HandleFunc("/stations/KBFI/observations/latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"properties":{"timestamp":"2024-05-01T12:00:00Z","textDescription":"Cloudy","temperature":{"value":10},"relativeHumidity":{"value":80.4},"windSpeed":{"value":null}}}`)
+	})
+	mux.HandleFunc("/gridpoints/SEW/124,67/forecast", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"properties":{"periods":[{"name":"Tonight","detailedForecast":"Rain."},{"name":"Thursday","detailedForecast":"Showers."},{"name":"Friday","detailedForecast":"Sunny."}]}}`)
+	})
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("request sent without a User-Agent")
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.NWSURL = srv.URL
+	es.GeocodeURL = srv.URL + "/search"
+	topics, err := es.FetchTopics(5, "Seattle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Weather for Seattle, Washington (47.6062,-122.3321)" || topics[0].Site != ProviderNWS {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Current conditions near Seattle, Washington: Cloudy, 10.0°C (50°F), humidity 80% (station KBFI, Boeing Field, observed 2024-05-01T12:00:00Z)",
+		"Tonight forecast for Seattle, Washington: Rain.",
+		"Thursday forecast for Seattle, Washington: Showers.",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+}
+
+func TestOpenWeatherMap(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("appid") != "key" || r.URL.Query().Get("units") != "imperial" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/data/2.5/weather":
+			if r.URL.Query().Get("lat") != "51.5074" {
+				t.Errorf("lat = %q", r.URL.Query().Get("lat"))
+			}
+			fmt.Fprint(w, `{"weather":[{"description":"light rain"}],"main":{"temp":50.1,"feels_like":48,"humidity":90},"wind":{"speed":5}}`)
+		case "/data/2.5/forecast":
+			fmt.Fprint(w, `{"list":[
+				{"dt_txt":"2024-05-01 09:00:00","weather":[{"description":"fog"}],"main":{"temp_min":45,"temp_max":50}},
+				{"dt_txt":"2024-05-01 12:00:00","weather":[{"description":"clouds"}],"main":{"temp_min":48,"temp_max":58}},
+				{"dt_txt":"2024-05-02 12:00:00","weather":[{"description":"sun"}],"main":{"temp_min":50,"temp_max":65}}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	es := New()
+	es.Provider = ProviderOpenWeatherMap
+	es.APIKey = "key"
+	es.Units = "imperial"
+	es.OWMURL = srv.URL
+	topics, err := es.FetchTopics(1, "51.50740, -0.12780")
+	if err != nil || len(topics) != 1 {
+		t.Fatalf("FetchTopics = %+v, %v", topics, err)
+	}
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 3 {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	if !strings.HasPrefix(data[0].DataText, "Current conditions in 51.5074,-0.1278: light rain, 50.1°F") || !strings.HasSuffix(data[0].DataText, "wind 5.0 mph") {
+		t.Errorf("current = %q", data[0].DataText)
+	}
+	if data[1].DataText != "Forecast for 51.5074,-0.1278 on 2024-05-01: clouds, low 45°F, high 58°F" {
+		t.Errorf("day 1 = %q", data[1].DataText)
+	}
+}
+
+func TestInit(t *testing.T) {
+	es := New()
+	es.Provider = ProviderOpenWeatherMap
+	if err := es.Init(); err == nil {
+		t.Error("Init succeeded for OpenWeatherMap without an APIKey")
+	}
+	es.Provider = "met"
+	if err := es.Init(); err == nil {
+		t.Error("Init accepted an unknown provider")
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	if _, ok := parseCoordinates("91,0"); ok {
+		t.Error("parseCoordinates accepted a latitude beyond 90")
+	}
+	if _, ok := parseCoordinates("Paris, France"); ok {
+		t.Error("parseCoordinates accepted a place name")
+	}
+}
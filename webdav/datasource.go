@@ -0,0 +1,557 @@
+package webdav
+
+// Data Source Adapter for documents on a WebDAV share such as Nextcloud or ownCloud: file names are
+// matched against the query and matched files are downloaded and extracted for data
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/localfs"
+	"github.com/locus-search/datasource/pdftext"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultDataCount  = 3
+	// File text is returned in chunks of about this many characters
+	chunkSize = 1500
+)
+
+// DefaultExtensions are the file types searched when Extensions is empty
+var DefaultExtensions = []string{".md", ".markdown", ".txt", ".html", ".htm", ".pdf"}
+
+type DataSourceWebDAV struct {
+	Client *http.Client
+	// URL of the collection to search, e.g. "https://cloud.example.com/remote.php/dav/files/alice/Documents/"
+	URL       string
+	UserAgent string
+
+	// Basic auth, e.g. a Nextcloud app password, or a bearer Token
+	Username string
+	Password string
+	Token    string
+
+	// Search sends one RFC 5323 SEARCH request that matches file names on the server, as Nextcloud
+	// supports, instead of walking the share with PROPFIND and caching the listing
+	Search bool
+
+	Extensions  []string      // Searched file types, e.g. ".md"; DefaultExtensions when empty
+	MaxDepth    int           // Collection levels walked below URL; default 5
+	MaxFiles    int           // Files kept from the walk; default 5000
+	MaxFileSize int64         // Larger files are listed but not downloaded; default 10 MB
+	ManifestTTL time.Duration // How long the walked listing is cached; default 15 minutes
+	Site        string        // Site for topics and data; default the share's host
+
+	mu       sync.Mutex
+	manifest []file
+	listed   time.Time
+	files    topicid.Map[file]
+}
+
+// file is one non-collection resource on the share
+type file struct {
+	URL          string
+	Path         string // Unescaped path relative to URL
+	Size         int64
+	LastModified time.Time
+	Words        []string // Lowercased words of Path
+}
+
+// multistatus decodes the PROPFIND and SEARCH response body
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				ContentLength string    `xml:"DAV: getcontentlength"`
+				LastModified  string    `xml:"DAV: getlastmodified"`
+				Collection    *struct{} `xml:"DAV: resourcetype>collection"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/><d:getcontentlength/><d:getlastmodified/></d:prop></d:propfind>`
+
+func New() *DataSourceWebDAV {
+	return &DataSourceWebDAV{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		UserAgent:   "locus/webdav-datasource",
+		MaxDepth:    5,
+		MaxFiles:    5000,
+		MaxFileSize: 10 << 20,
+		ManifestTTL: 15 * time.Minute,
+	}
+}
+
+// Init implements models.DataSource
+func (es *DataSourceWebDAV) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.URL == "" {
+		return errors.New("URL is required for WebDAV DataSource")
+	}
+	if _, err := url.Parse(es.URL); err != nil {
+		return fmt.Errorf("invalid WebDAV URL: %w", err)
+	}
+	if !strings.HasSuffix(es.URL, "/") {
+		es.URL += "/"
+	}
+	if es.MaxDepth <= 0 {
+		es.MaxDepth = 5
+	}
+	if es.MaxFiles <= 0 {
+		es.MaxFiles = 5000
+	}
+	if es.MaxFileSize <= 0 {
+		es.MaxFileSize = 10 << 20
+	}
+	if es.ManifestTTL <= 0 {
+		es.ManifestTTL = 15 * time.Minute
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Reports whether URL is a collection the credentials can list
+func (es *DataSourceWebDAV) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.propfind(ctx, es.URL, "0")
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceWebDAV) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "WebDAV",
+		Homepage: es.URL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Ranks files by how many query words appear in their path, newest first among equals. Topics are
+// titled from the file name, e.g. "Notes/setup-guide.md" gives "Setup guide".
+func (es *DataSourceWebDAV) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for WebDAV DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	terms := words(query)
+	var files []file
+	var err error
+	if es.Search {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+		defer cancel()
+		files, err = es.search(ctx, terms)
+	} else {
+		files, err = es.load()
+	}
+	if err != nil {
+		return nil, err
+	}
+	type scored struct {
+		file
+		score int
+	}
+	var matches []scored
+	for _, f := range files {
+		if score := matchCount(f.Words, terms); score > 0 {
+			matches = append(matches, scored{f, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].LastModified.After(matches[j].LastModified)
+	})
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	for _, m := range matches {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fileTitle(m.Path),
+			SourceURL: m.URL,
+			Site:      es.site(),
+			TopicID:   es.files.Put(m.URL, m.file),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Downloads the file and returns its text in chunks of about 1500 characters, each starting with the
+// title. Markdown front matter is dropped, HTML is reduced to its main text and PDFs are extracted page by
+// page.
+func (es *DataSourceWebDAV) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	f, ok := es.files.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown WebDAV topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultDataCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+	if f.Size > es.MaxFileSize {
+		return nil, fmt.Errorf("webdav file %s is larger than %d bytes", f.Path, es.MaxFileSize)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	resp, err := es.do(ctx, http.MethodGet, f.URL, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, es.MaxFileSize))
+	if err != nil {
+		return nil, err
+	}
+	title, texts, err := parse(ctx, f.Path, raw)
+	if err != nil {
+		return nil, fmt.Errorf("webdav file %s: %w", f.Path, err)
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, chunk := range texts {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		if len(results) > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%s#%d", f.URL, len(results)))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  title + "\n" + chunk,
+			SourceURL: f.URL,
+			Site:      es.site(),
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// LastModified implements source.Freshness
+func (es *DataSourceWebDAV) LastModified(topicID int64) time.Time {
+	f, _ := es.files.Get(topicID)
+	return f.LastModified
+}
+
+// load returns the cached file listing, walking the share again once it is older than ManifestTTL.
+// Collections are listed one level at a time since many servers refuse "Depth: infinity".
+func (es *DataSourceWebDAV) load() ([]file, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if es.manifest != nil && time.Since(es.listed) < es.ManifestTTL {
+		return es.manifest, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	type collection struct {
+		url   string
+		depth int
+	}
+	files := []file{}
+	queue := []collection{{es.URL, 0}}
+	seen := map[string]bool{es.URL: true}
+	for len(queue) > 0 && len(files) < es.MaxFiles {
+		c := queue[0]
+		queue = queue[1:]
+		status, err := es.propfind(ctx, c.url, "1")
+		if err != nil {
+			return nil, err
+		}
+		children, subcollections := es.entries(c.url, status)
+		for _, f := range children {
+			if len(files) >= es.MaxFiles {
+				break
+			}
+			files = append(files, f)
+		}
+		if c.depth+1 > es.MaxDepth {
+			continue
+		}
+		for _, sub := range subcollections {
+			if !seen[sub] {
+				seen[sub] = true
+				queue = append(queue, collection{sub, c.depth + 1})
+			}
+		}
+	}
+	es.manifest = files
+	es.listed = time.Now()
+	return files, nil
+}
+
+// search sends a SEARCH request for files whose name contains any of the terms. Nextcloud answers
+// SEARCH at the DAV root ("/remote.php/dav/") with the scope given as a path below it; other servers
+// are asked at URL itself.
+func (es *DataSourceWebDAV) search(ctx context.Context, terms []string) ([]file, error) {
+	base, err := url.Parse(es.URL)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, scope := es.URL, base.Path
+	if i := strings.Index(base.Path, "/remote.php/dav/"); i >= 0 {
+		root := *base
+		root.Path = base.Path[:i+len("/remote.php/dav/")]
+		root.RawPath = ""
+		endpoint = root.String()
+		scope = "/" + strings.TrimPrefix(base.Path, root.Path)
+	}
+
+	var where strings.Builder
+	if len(terms) > 1 {
+		where.WriteString("<d:or>")
+	}
+	for _, term := range terms {
+		where.WriteString("<d:like><d:prop><d:displayname/></d:prop><d:literal>%")
+		xml.EscapeText(&where, []byte(term))
+		where.WriteString("%</d:literal></d:like>")
+	}
+	if len(terms) > 1 {
+		where.WriteString("</d:or>")
+	}
+	var scopeHref strings.Builder
+	xml.EscapeText(&scopeHref, []byte(scope))
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<d:searchrequest xmlns:d="DAV:"><d:basicsearch>` +
+		`<d:select><d:prop><d:displayname/><d:resourcetype/><d:getcontentlength/><d:getlastmodified/></d:prop></d:select>` +
+		`<d:from><d:scope><d:href>` + scopeHref.String() + `</d:href><d:depth>infinity</d:depth></d:scope></d:from>` +
+		`<d:where>` + where.String() + `</d:where>` +
+		`<d:limit><d:nresults>` + strconv.Itoa(es.MaxFiles) + `</d:nresults></d:limit>` +
+		`</d:basicsearch></d:searchrequest>`
+
+	resp, err := es.do(ctx, "SEARCH", endpoint, strings.NewReader(body), map[string]string{
+		"Content-Type": "text/xml; charset=utf-8",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var status multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("decoding webdav search response: %w", err)
+	}
+	files, _ := es.entries(es.URL, &status)
+	return files, nil
+}
+
+func (es *DataSourceWebDAV) propfind(ctx context.Context, uri, depth string) (*multistatus, error) {
+	resp, err := es.do(ctx, "PROPFIND", uri, strings.NewReader(propfindBody), map[string]string{
+		"Content-Type": "text/xml; charset=utf-8",
+		"Depth":        depth,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var status multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("decoding webdav listing: %w", err)
+	}
+	return &status, nil
+}
+
+// entries resolves the responses of a multistatus against the collection at base, returning the files
+// with a searched extension under URL and the URLs of the subcollections
+func (es *DataSourceWebDAV) entries(base string, status *multistatus) ([]file, []string) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, nil
+	}
+	rootURL, _ := url.Parse(es.URL)
+	extensions := es.Extensions
+	if len(extensions) == 0 {
+		extensions = DefaultExtensions
+	}
+
+	var files []file
+	var collections []string
+	for _, r := range status.Responses {
+		ref, err := url.Parse(r.Href)
+		if err != nil {
+			continue
+		}
+		resolved := baseURL.ResolveReference(ref)
+		if resolved.Path == baseURL.Path || !strings.HasPrefix(resolved.Path, rootURL.Path) {
+			continue
+		}
+		for _, ps := range r.Propstat {
+			if !strings.Contains(ps.Status, " 200 ") {
+				continue
+			}
+			if ps.Prop.Collection != nil {
+				if !strings.HasSuffix(resolved.Path, "/") {
+					resolved.Path += "/"
+					resolved.RawPath = ""
+				}
+				collections = append(collections, resolved.String())
+				break
+			}
+			rel := strings.TrimPrefix(resolved.Path, rootURL.Path)
+			if !hasExtension(rel, extensions) {
+				break
+			}
+			f := file{URL: resolved.String(), Path: rel, Words: words(rel)}
+			f.Size, _ = strconv.ParseInt(ps.Prop.ContentLength, 10, 64)
+			f.LastModified, _ = http.ParseTime(ps.Prop.LastModified)
+			files = append(files, f)
+			break
+		}
+	}
+	return files, collections
+}
+
+// do sends a request with the configured credentials and returns the response when it succeeded
+func (es *DataSourceWebDAV) do(ctx context.Context, method, uri string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if body == nil {
+		body = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, uri, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	switch {
+	case es.Token != "":
+		req.Header.Set("Authorization", "Bearer "+es.Token)
+	case es.Username != "":
+		req.SetBasicAuth(es.Username, es.Password)
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, fmt.Errorf("webdav request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+func (es *DataSourceWebDAV) site() string {
+	if es.Site != "" {
+		return es.Site
+	}
+	if u, err := url.Parse(es.URL); err == nil {
+		return u.Hostname()
+	}
+	return "webdav"
+}
+
+// Helpers
+
+func hasExtension(name string, extensions []string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits text into lowercase letter and digit runs
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchCount counts the query terms found among words; a term also matches a word it prefixes
+func matchCount(words, terms []string) int {
+	count := 0
+	for _, term := range terms {
+		for _, w := range words {
+			if strings.HasPrefix(w, term) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// fileTitle turns a file name into a title, e.g. "Notes/setup-guide.md" gives "Setup guide"
+func fileTitle(name string) string {
+	base := path.Base(name)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	title := strings.Join(strings.FieldsFunc(base, func(r rune) bool { return r == '-' || r == '_' || r == '+' }), " ")
+	if title == "" {
+		return name
+	}
+	r := []rune(title)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// parse returns the title and text chunks of a downloaded file: PDFs are extracted page by page, other
+// types are parsed like local files
+func parse(ctx context.Context, name string, raw []byte) (string, []string, error) {
+	if strings.EqualFold(path.Ext(name), ".pdf") {
+		doc, err := pdftext.Default.Bytes(ctx, raw)
+		if err != nil {
+			return "", nil, err
+		}
+		title := doc.Title
+		if title == "" {
+			title = fileTitle(name)
+		}
+		return title, doc.Chunks(chunkSize), nil
+	}
+	doc := localfs.Parse(name, raw)
+	return doc.Title, localfs.Chunks(doc.Body, chunkSize), nil
+}
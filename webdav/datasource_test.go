@@ -0,0 +1,148 @@
+package webdav
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// response renders a multistatus response entry; size < 0 marks a collection
+func response(href string, size int, modified string) string {
+	prop := `<d:resourcetype><d:collection/></d:resourcetype>`
+	if size >= 0 {
+		prop = fmt.Sprintf(`<d:resourcetype/><d:getcontentlength>%d</d:getcontentlength><d:getlastmodified>%s</d:getlastmodified>`, size, modified)
+	}
+	return `<d:response><d:href>` + href + `</d:href><d:propstat><d:prop>` + prop +
+		`</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`
+}
+
+func multistatusBody(responses ...string) string {
+	return `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:">` + strings.Join(responses, "") + `</d:multistatus>`
+}
+
+const root = "/remote.php/dav/files/alice/Docs/"
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("PROPFIND "+root, func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "alice" || pass != "app-password" || r.Header.Get("Depth") != "1" {
+			t.Errorf("PROPFIND as %q with Depth %q", user, r.Header.Get("Depth"))
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, multistatusBody(
+			response(root, -1, ""),
+			response(root+"Notes", -1, ""),
+			response(root+"install%20guide.md", 120, "Mon, 01 Jan 2024 00:00:00 GMT"),
+			response(root+"install.png", 9000, "Mon, 01 Jan 2024 00:00:00 GMT"),
+			// Outside the searched collection
+			response("/remote.php/dav/files/alice/install.md", 10, "Mon, 01 Jan 2024 00:00:00 GMT"),
+		))
+	})
+	mux.HandleFunc("PROPFIND "+root+"Notes/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, multistatusBody(
+			response(root+"Notes/", -1, ""),
+			response("windows-install.txt", 50, "Thu, 01 Feb 2024 00:00:00 GMT"),
+		))
+	})
+	mux.HandleFunc("GET "+root+"install guide.md", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "# Installing\n\nDownload the release.\n")
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.URL = srv.URL + root
+	es.Username = "alice"
+	es.Password = "app-password"
+
+	topics, err := es.FetchTopics(5, "install guide")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Install guide|" + srv.URL + root + "install%20guide.md",
+		"Windows install|" + srv.URL + root + "Notes/windows-install.txt",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].SourceURL; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+	if got := es.LastModified(topics[1].TopicID); got.Month() != 2 {
+		t.Errorf("LastModified = %v", got)
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].DataText != "Installing\n# Installing\n\nDownload the release." || data[0].Site != "127.0.0.1" {
+		t.Errorf("FetchData = %+v", data)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("SEARCH /remote.php/dav/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		for _, part := range []string{
+			`<d:href>/files/alice/Docs/</d:href><d:depth>infinity</d:depth>`,
+			`<d:or><d:like><d:prop><d:displayname/></d:prop><d:literal>%release%</d:literal></d:like>`,
+			`<d:literal>%notes%</d:literal></d:like></d:or>`,
+			`<d:nresults>5000</d:nresults>`,
+		} {
+			if !strings.Contains(string(body), part) {
+				t.Errorf("SEARCH body lacks %s:\n%s", part, body)
+			}
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, multistatusBody(
+			response(root+"Research/release%20notes.md", 40, "Mon, 01 Jan 2024 00:00:00 GMT"),
+			response(root+"Research/", -1, ""),
+		))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	es := New()
+	es.URL = srv.URL + root
+	es.Token = "token"
+	es.Search = true
+	topics, err := es.FetchTopics(5, "Release notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Release notes" || topics[0].SourceURL != srv.URL+root+"Research/release%20notes.md" {
+		t.Errorf("FetchTopics = %+v", topics)
+	}
+}
+
+func TestCheckAvailability(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PROPFIND" || r.Header.Get("Depth") != "0" {
+			t.Errorf("%s with Depth %q", r.Method, r.Header.Get("Depth"))
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	es := New()
+	es.URL = srv.URL + root
+	if es.CheckAvailability() {
+		t.Error("CheckAvailability = true for rejected credentials")
+	}
+}
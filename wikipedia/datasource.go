@@ -4,21 +4,59 @@ package wikipedia
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/chunker"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
 )
 
+var htmlTag = regexp.MustCompile(`<[^>]+>`)
+
+// Namespaces of pages that are not articles, by canonical name or alias; each also has a talk namespace
+var namespaces = map[string]bool{
+	"user": true, "wikipedia": true, "project": true, "wp": true, "file": true, "image": true,
+	"mediawiki": true, "template": true, "help": true, "category": true, "portal": true, "draft": true,
+	"timedtext": true, "module": true, "special": true, "media": true, "book": true, "gadget": true,
+	"gadget definition": true, "topic": true, "education program": true, "talk": true,
+}
+
+// Reference sections dropped from full articles
+var skipSections = map[string]bool{
+	"See also": true, "References": true, "Notes": true, "Citations": true, "Sources": true,
+	"Bibliography": true, "Further reading": true, "External links": true,
+}
+
 type DataSourceWikipedia struct {
 	Client    *http.Client
 	BaseURL   string
 	UserAgent string
+
+	// PageviewsURL is the Wikimedia REST endpoint of the most viewed articles, for Trending
+	PageviewsURL string
+
+	// FullArticle makes FetchData return the whole article in chunks instead of only the intro
+	FullArticle bool
+	Chunker     *chunker.Chunker // Splits full articles; default chunker.Default
+
+	hits topicid.Map[hit] // Search result details by page ID, for Details and LastModified
+}
+
+// hit is what a search said about a page
+type hit struct {
+	Snippet string
+	Edited  time.Time
+	Score   float64 // From the search rank, see source.RankScore
 }
 
 func New() *DataSourceWikipedia {
@@ -26,8 +64,9 @@ func New() *DataSourceWikipedia {
 		Client: &http.Client{
 			Timeout: 8 * time.Second,
 		},
-		BaseURL:   "https://en.wikipedia.org/w/api.php",
-		UserAgent: "locus/ask",
+		BaseURL:      "https://en.wikipedia.org/w/api.php",
+		UserAgent:    "locus/ask",
+		PageviewsURL: "https://wikimedia.org/api/rest_v1/metrics/pageviews/top",
 	}
 }
 
@@ -39,7 +78,7 @@ func (es *DataSourceWikipedia) Init() error {
 
 // CheckAvailability implements models.DataSource
 func (es *DataSourceWikipedia) CheckAvailability() bool {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
 	defer cancel()
 	params := url.Values{}
 	params.Set("action", "query")
@@ -50,123 +89,630 @@ func (es *DataSourceWikipedia) CheckAvailability() bool {
 	return err == nil
 }
 
+// Close implements source.Closer
+// Closes the client's idle connections; the adapter can still be used and opens new ones as needed
+func (es *DataSourceWikipedia) Close(ctx context.Context) error {
+	if es.Client != nil {
+		es.Client.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceWikipedia) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "Wikipedia",
+		Homepage:            "https://www.wikipedia.org",
+		License:             "CC-BY-SA-4.0",
+		LicenseURL:          "https://creativecommons.org/licenses/by-sa/4.0/",
+		Attribution:         "From Wikipedia, the free encyclopedia; text available under CC BY-SA 4.0",
+		AttributionRequired: true,
+		TermsURL:            "https://foundation.wikimedia.org/wiki/Policy:Terms_of_Use",
+	}
+}
+
+// Describe implements source.Describer
+// The language is that of the wiki BaseURL points to, e.g. "de" for de.wikipedia.org
+func (es *DataSourceWikipedia) Describe() source.Capabilities {
+	c := source.Capabilities{
+		Name:       es.Info().Name,
+		Data:       true,
+		Pagination: true,
+		Context:    true,
+		Options:    []string{source.OptionLanguage, source.OptionTimeRange},
+	}
+	if u, err := url.Parse(es.BaseURL); err == nil {
+		if language, ok := strings.CutSuffix(u.Hostname(), ".wikipedia.org"); ok && !strings.Contains(language, ".") {
+			c.Languages = []string{language}
+		}
+	}
+	return c
+}
+
 // FetchTopics implements models.DataSource
-// Fetch Wikipedia search results for the query string. Each result is a topic with title and page ID.
 func (es *DataSourceWikipedia) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	return es.FetchTopicsContext(ctx, count, input)
+}
+
+// FetchTopicsContext implements source.ContextSource
+func (es *DataSourceWikipedia) FetchTopicsContext(ctx context.Context, count int, input string) ([]datasource.DataSourceTopic, error) {
+	return es.FetchTopicsWithOptions(ctx, input, source.TopicOptions{Count: count})
+}
+
+// FetchTopicsWithOptions implements source.OptionsSource
+func (es *DataSourceWikipedia) FetchTopicsWithOptions(ctx context.Context, input string, opts source.TopicOptions) ([]datasource.DataSourceTopic, error) {
+	page, err := es.FetchTopicsPage(ctx, input, opts, "")
+	return page.Topics, err
+}
+
+// FetchTopicsPage implements source.Pager
+// Fetch Wikipedia search results for the query string. Each result is a topic with title and page ID.
+// Language adds an inlanguage: modifier to the search; TimeRange drops pages last edited before it,
+// so fewer than count topics may be returned. Region and SafeSearch do not apply to Wikipedia.
+// The cursor holds the search offset Wikipedia returns for continuing (sroffset).
+func (es *DataSourceWikipedia) FetchTopicsPage(ctx context.Context, input string, opts source.TopicOptions, cursor string) (source.Page, error) {
 	query := strings.TrimSpace(input)
 	if query == "" {
-		return nil, errors.New("Missing search input for Wikipedia DataSource")
+		return source.Page{}, fmt.Errorf("Missing search input for Wikipedia DataSource: %w", source.ErrBadQuery)
 	}
+	offset, err := source.ParseOffsetCursor(cursor)
+	if err != nil {
+		return source.Page{}, err
+	}
+	count := opts.Count
 	if count <= 0 {
 		count = 5
 	}
+	if language := strings.TrimSpace(opts.Language); language != "" {
+		query += " inlanguage:" + strings.ToLower(language)
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
-	defer cancel()
 	params := url.Values{}
 	params.Set("action", "query")
 	params.Set("list", "search")
 	params.Set("srsearch", query)
 	params.Set("srlimit", fmt.Sprintf("%d", count))
+	params.Set("srprop", "timestamp|snippet")
+	if offset > 0 {
+		params.Set("sroffset", fmt.Sprintf("%d", offset))
+	}
 	params.Set("format", "json")
 
 	var response struct {
 		Query struct {
 			Search []struct {
-				Title  string `json:"title"`
-				PageID int64  `json:"pageid"`
+				Title     string    `json:"title"`
+				PageID    int64     `json:"pageid"`
+				Timestamp time.Time `json:"timestamp"`
+				Snippet   string    `json:"snippet"`
 			} `json:"search"`
 		} `json:"query"`
-		Error *struct {
-			Info string `json:"info"`
-		} `json:"error"`
+		Continue *struct {
+			SROffset int `json:"sroffset"`
+		} `json:"continue"`
+		Error *apiError `json:"error"`
 	}
 
-	_, err := es.doJSON(ctx, params, &response)
+	_, err = es.doJSON(ctx, params, &response)
 	if err != nil {
-		return nil, err
+		return source.Page{}, err
 	}
 	if response.Error != nil {
-		return nil, fmt.Errorf("wikipedia error: %s", response.Error.Info)
+		return source.Page{}, response.Error
 	}
 
+	since := opts.TimeRange.Since(time.Now())
 	results := make([]datasource.DataSourceTopic, 0, len(response.Query.Search))
-	for _, item := range response.Query.Search {
+	for i, item := range response.Query.Search {
+		if item.Timestamp.Before(since) {
+			continue
+		}
+		es.hits.Set(item.PageID, hit{Snippet: plainText(item.Snippet), Edited: item.Timestamp, Score: source.RankScore(offset + i)})
 		results = append(results, datasource.DataSourceTopic{
 			Topic:   item.Title,
 			SourceURL:  fmt.Sprintf("https://en.wikipedia.org/?curid=%d", item.PageID),
 			TopicID: item.PageID,
 		})
 	}
-	return results, nil
+	page := source.Page{Topics: results}
+	if response.Continue != nil {
+		page.Next = source.OffsetCursor(response.Continue.SROffset)
+	}
+	return page, nil
 }
 
 // FetchData implements models.DataSource
-// Fetch the extract (intro paragraph) for the given Wikipedia page ID
-// Returns a single DataSourceData item with the extract text and source URL
 func (es *DataSourceWikipedia) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	return es.FetchDataContext(ctx, count, topicID)
+}
+
+// FetchDataContext implements source.ContextSource
+// Fetch the extract (intro paragraph) for the given Wikipedia page ID
+// Returns a single DataSourceData item with the extract text and source URL, or with FullArticle up to
+// count chunks of the article, each headed by its section title and linking to the section
+func (es *DataSourceWikipedia) FetchDataContext(ctx context.Context, count int, topicID int64) ([]datasource.DataSourceData, error) {
 	if topicID <= 0 {
-		return nil, errors.New("topicID is required")
+		return nil, fmt.Errorf("topicID is required: %w", source.ErrBadQuery)
+	}
+
+	pages, err := es.extracts(ctx, []int64{topicID})
+	if err != nil {
+		return nil, err
+	}
+	for _, page := range pages {
+		return es.pageData(count, page), nil
+	}
+
+	return []datasource.DataSourceData{}, nil
+}
+
+// StringID implements source.IDSource
+// A page's ID is its page ID in decimal
+func (es *DataSourceWikipedia) StringID(topicID int64) (source.ID, bool) {
+	if topicID <= 0 {
+		return "", false
+	}
+	return source.IntID(topicID), true
+}
+
+// TopicID implements source.IDSource
+func (es *DataSourceWikipedia) TopicID(id source.ID) (int64, error) {
+	pageID, ok := id.Int64()
+	if !ok || pageID <= 0 {
+		return 0, fmt.Errorf("topic ID %q is not a Wikipedia page ID: %w", id, source.ErrBadQuery)
+	}
+	return pageID, nil
+}
+
+// FetchDataBatch implements source.Batcher
+// Requests the intros of up to maxIntroExtracts pages at once. Full articles are only returned one per
+// request, so with FullArticle the pages are fetched concurrently.
+func (es *DataSourceWikipedia) FetchDataBatch(ctx context.Context, count int, topicIDs []int64) (map[int64][]datasource.DataSourceData, error) {
+	for _, id := range topicIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("topicID is required: %w", source.ErrBadQuery)
+		}
+	}
+	if es.FullArticle {
+		return source.FetchDataEach(ctx, es, count, topicIDs)
+	}
+
+	pages, err := es.extracts(ctx, topicIDs)
+	if err != nil {
+		return nil, err
+	}
+	results := map[int64][]datasource.DataSourceData{}
+	for _, page := range pages {
+		if data := es.pageData(count, page); len(data) > 0 {
+			results[page.PageID] = data
+		}
+	}
+	return results, nil
+}
+
+// Most extracts the API returns per request
+const (
+	maxIntroExtracts = 20
+	maxFullExtracts  = 1
+)
+
+// extract is a page's plain text from the extracts API
+type extract struct {
+	PageID  int64  `json:"pageid"`
+	Title   string `json:"title"`
+	Extract string `json:"extract"`
+}
+
+// extracts fetches the text of pages, as many per query as the API returns extracts for, and returns
+// them in the order of pageIDs; missing pages are left out
+func (es *DataSourceWikipedia) extracts(ctx context.Context, pageIDs []int64) ([]extract, error) {
+	limit := maxIntroExtracts
+	if es.FullArticle {
+		limit = maxFullExtracts
+	}
+	found := map[int64]extract{}
+	for start := 0; start < len(pageIDs); start += limit {
+		if err := es.queryExtracts(ctx, pageIDs[start:min(start+limit, len(pageIDs))], found); err != nil {
+			return nil, err
+		}
+	}
+
+	pages := make([]extract, 0, len(found))
+	for _, id := range pageIDs {
+		if page, ok := found[id]; ok {
+			pages = append(pages, page)
+		}
+	}
+	return pages, nil
+}
+
+// queryExtracts adds the text of pages to found in a single query, following its continuations
+func (es *DataSourceWikipedia) queryExtracts(ctx context.Context, pageIDs []int64, found map[int64]extract) error {
+	ids := make([]string, len(pageIDs))
+	for i, id := range pageIDs {
+		ids[i] = strconv.FormatInt(id, 10)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
-	defer cancel()
 	params := url.Values{}
 	params.Set("action", "query")
-	params.Set("pageids", fmt.Sprintf("%d", topicID))
+	params.Set("pageids", strings.Join(ids, "|"))
 	params.Set("prop", "extracts")
-	params.Set("exintro", "1")
+	params.Set("exlimit", strconv.Itoa(len(pageIDs)))
+	if es.FullArticle {
+		params.Set("exsectionformat", "wiki")
+	} else {
+		params.Set("exintro", "1")
+	}
 	params.Set("explaintext", "1")
 	params.Set("format", "json")
 
+	for {
+		var response struct {
+			Continue map[string]interface{} `json:"continue"`
+			Query    struct {
+				Pages map[string]extract `json:"pages"`
+			} `json:"query"`
+			Error *apiError `json:"error"`
+		}
+
+		_, err := es.doJSON(ctx, params, &response)
+		if err != nil {
+			return err
+		}
+		if response.Error != nil {
+			return response.Error
+		}
+
+		// Continuations repeat the pages, with the extracts that did not fit before
+		for _, page := range response.Query.Pages {
+			if _, ok := found[page.PageID]; !ok || page.Extract != "" {
+				found[page.PageID] = page
+			}
+		}
+		if len(response.Continue) == 0 {
+			break
+		}
+		for key, value := range response.Continue {
+			params.Set(key, fmt.Sprint(value))
+		}
+	}
+	return nil
+}
+
+// pageData returns the data of a page: its intro, or its chunked article with FullArticle
+func (es *DataSourceWikipedia) pageData(count int, page extract) []datasource.DataSourceData {
+	dataText := strings.TrimSpace(page.Extract)
+	if dataText == "" {
+		return []datasource.DataSourceData{}
+	}
+	if es.FullArticle {
+		return es.articleData(count, page.PageID, page.Title, dataText)
+	}
+	data := datasource.DataSourceData{
+		DataText: dataText,
+		SourceURL:  fmt.Sprintf("https://en.wikipedia.org/?curid=%d", page.PageID),
+		AnswerID:   page.PageID,
+	}
+	return []datasource.DataSourceData{data}
+}
+
+// articleData chunks a full article, dropping reference sections
+func (es *DataSourceWikipedia) articleData(count int, pageID int64, title, text string) []datasource.DataSourceData {
+	if count <= 0 {
+		count = 3
+	}
+	c := es.Chunker
+	if c == nil {
+		c = chunker.Default
+	}
+	var sections []chunker.Section
+	for _, s := range chunker.Sections(text) {
+		if !skipSections[s.Title] {
+			sections = append(sections, s)
+		}
+	}
+
+	source := fmt.Sprintf("https://en.wikipedia.org/?curid=%d", pageID)
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, chunk := range c.Sections(sections) {
+		if len(results) >= count {
+			break
+		}
+		answerID := pageID
+		if chunk.Index > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%d#%d", pageID, chunk.Index))
+		}
+		sourceURL := source
+		if chunk.Section != "" {
+			sourceURL += "#" + strings.ReplaceAll(chunk.Section, " ", "_")
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  chunk.Heading(title) + "\n" + chunk.Text,
+			SourceURL: sourceURL,
+			AnswerID:  answerID,
+		})
+	}
+	return results
+}
+
+// Details implements source.Detailed
+// Wikipedia's search results carry a snippet, and Score comes from their rank since the API no longer
+// returns search scores; the last edit is reported by LastModified
+func (es *DataSourceWikipedia) Details(topicID int64) (source.TopicDetails, bool) {
+	h, ok := es.hits.Get(topicID)
+	if !ok {
+		return source.TopicDetails{}, false
+	}
+	return source.TopicDetails{Snippet: h.Snippet, Score: h.Score}, true
+}
+
+// LastModified implements source.Freshness
+func (es *DataSourceWikipedia) LastModified(topicID int64) time.Time {
+	h, _ := es.hits.Get(topicID)
+	return h.Edited
+}
+
+// Suggest implements source.Suggester
+// Completes prefix with article titles from the opensearch API
+func (es *DataSourceWikipedia) Suggest(ctx context.Context, prefix string, count int) ([]string, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return nil, nil
+	}
+	if count <= 0 {
+		count = 10
+	}
+
+	params := url.Values{}
+	params.Set("action", "opensearch")
+	params.Set("search", prefix)
+	params.Set("limit", fmt.Sprintf("%d", count))
+	params.Set("namespace", "0")
+	params.Set("redirects", "resolve")
+	params.Set("format", "json")
+
+	// The response is [search, [titles], [descriptions], [urls]], or an object holding an error
+	var raw json.RawMessage
+	if _, err := es.doJSON(ctx, params, &raw); err != nil {
+		return nil, err
+	}
+	var failed struct {
+		Error *apiError `json:"error"`
+	}
+	if json.Unmarshal(raw, &failed) == nil && failed.Error != nil {
+		return nil, failed.Error
+	}
+	var response []json.RawMessage
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, err
+	}
+	var titles []string
+	if len(response) >= 2 {
+		if err := json.Unmarshal(response[1], &titles); err != nil {
+			return nil, err
+		}
+	}
+	return titles, nil
+}
+
+// Related implements source.RelatedSource
+// Searches for articles like the topic's with CirrusSearch's morelike: keyword
+func (es *DataSourceWikipedia) Related(ctx context.Context, topicID int64, count int) ([]datasource.DataSourceTopic, error) {
+	if topicID <= 0 {
+		return nil, fmt.Errorf("topicID is required: %w", source.ErrBadQuery)
+	}
+	title, err := es.pageTitle(ctx, topicID)
+	if err != nil {
+		return nil, err
+	}
+	topics, err := es.FetchTopicsWithOptions(ctx, "morelike:"+title, source.TopicOptions{Count: count})
+	if err != nil {
+		return nil, err
+	}
+	results := topics[:0]
+	for _, t := range topics {
+		if t.TopicID != topicID {
+			results = append(results, t)
+		}
+	}
+	return results, nil
+}
+
+// pageTitle returns the title of the page with the given ID
+func (es *DataSourceWikipedia) pageTitle(ctx context.Context, pageID int64) (string, error) {
+	params := url.Values{}
+	params.Set("action", "query")
+	params.Set("pageids", fmt.Sprintf("%d", pageID))
+	params.Set("format", "json")
+	params.Set("formatversion", "2")
+
 	var response struct {
 		Query struct {
-			Pages map[string]struct {
-				PageID  int64  `json:"pageid"`
+			Pages []struct {
 				Title   string `json:"title"`
-				Extract string `json:"extract"`
+				Missing bool   `json:"missing"`
 			} `json:"pages"`
 		} `json:"query"`
-		Error *struct {
-			Info string `json:"info"`
-		} `json:"error"`
+		Error *apiError `json:"error"`
 	}
+	if _, err := es.doJSON(ctx, params, &response); err != nil {
+		return "", err
+	}
+	if response.Error != nil {
+		return "", response.Error
+	}
+	if len(response.Query.Pages) == 0 || response.Query.Pages[0].Missing {
+		return "", fmt.Errorf("wikipedia page %d not found: %w", pageID, source.ErrBadQuery)
+	}
+	return response.Query.Pages[0].Title, nil
+}
 
-	_, err := es.doJSON(ctx, params, &response)
+// Trending implements source.TrendingSource
+// Returns the articles most viewed on the latest day the pageviews API has published, usually
+// yesterday, leaving out the main page and special pages
+func (es *DataSourceWikipedia) Trending(ctx context.Context, count int) ([]datasource.DataSourceTopic, error) {
+	if count <= 0 {
+		count = 5
+	}
+	project, err := es.project()
 	if err != nil {
 		return nil, err
 	}
-	if response.Error != nil {
-		return nil, fmt.Errorf("wikipedia error: %s", response.Error.Info)
+	endpoint := es.PageviewsURL
+	if endpoint == "" {
+		endpoint = "https://wikimedia.org/api/rest_v1/metrics/pageviews/top"
 	}
 
-	for _, page := range response.Query.Pages {
-		dataText := strings.TrimSpace(page.Extract)
-		if dataText == "" {
-			return []datasource.DataSourceData{}, nil
+	var response struct {
+		Items []struct {
+			Articles []struct {
+				Article string `json:"article"`
+			} `json:"articles"`
+		} `json:"items"`
+	}
+	// A day's ranking is published some hours after it ends
+	day := time.Now().UTC()
+	for attempt := 0; attempt < 3; attempt++ {
+		day = day.AddDate(0, 0, -1)
+		uri := fmt.Sprintf("%s/%s/all-access/%s", strings.TrimRight(endpoint, "/"), project, day.Format("2006/01/02"))
+		status, err := es.getJSON(ctx, uri, &response)
+		if status == http.StatusNotFound {
+			continue
 		}
-		data := datasource.DataSourceData{
-			DataText: dataText,
-			SourceURL:  fmt.Sprintf("https://en.wikipedia.org/?curid=%d", page.PageID),
-			AnswerID:   page.PageID,
+		if err != nil {
+			return nil, err
 		}
-		return []datasource.DataSourceData{data}, nil
+		break
+	}
+	if len(response.Items) == 0 {
+		return nil, nil
 	}
 
-	return []datasource.DataSourceData{}, nil
+	var titles []string
+	for _, a := range response.Items[0].Articles {
+		if len(titles) >= count {
+			break
+		}
+		if a.Article == "Main_Page" || !mainspace(a.Article) {
+			continue
+		}
+		titles = append(titles, a.Article)
+	}
+	return es.titleTopics(ctx, titles)
+}
+
+// titleTopics looks up the page IDs of titles and returns them as topics in the same order; titles
+// that no longer exist are left out
+func (es *DataSourceWikipedia) titleTopics(ctx context.Context, titles []string) ([]datasource.DataSourceTopic, error) {
+	results := make([]datasource.DataSourceTopic, 0, len(titles))
+	for start := 0; start < len(titles); start += 50 {
+		batch := titles[start:min(start+50, len(titles))]
+		params := url.Values{}
+		params.Set("action", "query")
+		params.Set("titles", strings.Join(batch, "|"))
+		params.Set("format", "json")
+		params.Set("formatversion", "2")
+
+		var response struct {
+			Query struct {
+				Normalized []struct {
+					From string `json:"from"`
+					To   string `json:"to"`
+				} `json:"normalized"`
+				Pages []struct {
+					PageID  int64  `json:"pageid"`
+					Title   string `json:"title"`
+					Missing bool   `json:"missing"`
+				} `json:"pages"`
+			} `json:"query"`
+			Error *apiError `json:"error"`
+		}
+		if _, err := es.doJSON(ctx, params, &response); err != nil {
+			return nil, err
+		}
+		if response.Error != nil {
+			return nil, response.Error
+		}
+		normalized := map[string]string{}
+		for _, n := range response.Query.Normalized {
+			normalized[n.From] = n.To
+		}
+		ids := map[string]int64{}
+		for _, p := range response.Query.Pages {
+			if !p.Missing && p.PageID > 0 {
+				ids[p.Title] = p.PageID
+			}
+		}
+		for _, title := range batch {
+			if to, ok := normalized[title]; ok {
+				title = to
+			}
+			id, ok := ids[title]
+			if !ok {
+				continue
+			}
+			results = append(results, datasource.DataSourceTopic{
+				Topic:     title,
+				SourceURL: fmt.Sprintf("https://en.wikipedia.org/?curid=%d", id),
+				TopicID:   id,
+			})
+		}
+	}
+	return results, nil
+}
+
+// project returns the Wikimedia project of BaseURL for the REST API, e.g. "en.wikipedia"
+func (es *DataSourceWikipedia) project() (string, error) {
+	u, err := url.Parse(es.BaseURL)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("wikipedia: cannot tell the project from BaseURL %q", es.BaseURL)
+	}
+	return strings.TrimSuffix(u.Hostname(), ".org"), nil
+}
+
+// apiError is an error the MediaWiki API reported in a response
+type apiError struct {
+	Code string `json:"code"`
+	Info string `json:"info"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("wikipedia error: %s", e.Info)
+}
+
+// Unwrap classifies the API's error codes; the rest reject the request's parameters
+func (e *apiError) Unwrap() error {
+	switch {
+	case e.Code == "ratelimited":
+		return source.ErrRateLimited
+	case e.Code == "maxlag" || e.Code == "readonly" || strings.HasPrefix(e.Code, "internal_api_error"):
+		return source.ErrUnavailable
+	case strings.Contains(e.Code, "blocked"):
+		return source.ErrBlocked
+	}
+	return source.ErrBadQuery
 }
 
 // doJSON performs an HTTP GET request to the Wikipedia API with the specified parameters and decodes the JSON response into the target structure
 func (es *DataSourceWikipedia) doJSON(ctx context.Context, params url.Values, target interface{}) (int, error) {
-	client := es.Client
-	if client == nil {
-		client = &http.Client{Timeout: 8 * time.Second}
-	}
 	endpoint := strings.TrimRight(es.BaseURL, "/")
 	uri := endpoint
 	if encoded := params.Encode(); encoded != "" {
 		uri = uri + "?" + encoded
 	}
+	return es.getJSON(ctx, uri, target)
+}
+
+// getJSON performs an HTTP GET request to uri and decodes the JSON response into the target structure
+func (es *DataSourceWikipedia) getJSON(ctx context.Context, uri string, target interface{}) (int, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
@@ -185,7 +731,7 @@ func (es *DataSourceWikipedia) doJSON(ctx context.Context, params url.Values, ta
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return resp.StatusCode, fmt.Errorf("wikipedia request failed: %s", strings.TrimSpace(string(body)))
+		return resp.StatusCode, source.NewStatusError("wikipedia", resp, string(body))
 	}
 
 	if target == nil {
@@ -198,3 +744,19 @@ func (es *DataSourceWikipedia) doJSON(ctx context.Context, params url.Values, ta
 	}
 	return resp.StatusCode, nil
 }
+
+// mainspace reports whether title is an article rather than a page in another namespace. Articles may
+// contain colons, e.g. "Star Wars: Episode I – The Phantom Menace".
+func mainspace(title string) bool {
+	prefix, _, ok := strings.Cut(title, ":")
+	if !ok {
+		return true
+	}
+	prefix = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(prefix, "_", " ")))
+	return !namespaces[strings.TrimSuffix(prefix, " talk")]
+}
+
+// plainText removes the markup of search snippets, which highlight the matched terms
+func plainText(snippet string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(snippet, ""))), " ")
+}
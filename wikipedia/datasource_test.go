@@ -0,0 +1,163 @@
+package wikipedia
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// extractsServer answers extracts queries with an extract per page ID, returning at most exlimit of
+// them per response as the API does, and records the queries it was sent
+func extractsServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		mu.Lock()
+		queries = append(queries, q.Get("pageids"))
+		mu.Unlock()
+		limit := 1
+		if n, err := strconv.Atoi(q.Get("exlimit")); err == nil {
+			limit = n
+		}
+		pages := map[string]any{}
+		for i, id := range strings.Split(q.Get("pageids"), "|") {
+			page := map[string]any{"pageid": json.Number(id), "title": "Page " + id}
+			if i < limit {
+				page["extract"] = "Text of " + id
+			}
+			pages[id] = page
+		}
+		json.NewEncoder(w).Encode(map[string]any{"query": map[string]any{"pages": pages}})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+}
+
+func TestFetchDataBatch(t *testing.T) {
+	srv, queries := extractsServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+
+	ids := make([]int64, 50)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	results, err := es.FetchDataBatch(context.Background(), 3, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(ids) {
+		t.Errorf("got data for %d of %d pages", len(results), len(ids))
+	}
+	for _, id := range ids {
+		data := results[id]
+		if len(data) != 1 || data[0].DataText != fmt.Sprintf("Text of %d", id) || data[0].AnswerID != id {
+			t.Errorf("page %d: %+v", id, data)
+		}
+	}
+	if got := queries(); len(got) != 3 {
+		t.Errorf("sent %d queries for 50 pages, want 3: %q", len(got), got)
+	}
+}
+
+func TestFetchDataBatchFullArticle(t *testing.T) {
+	srv, queries := extractsServer(t)
+	es := New()
+	es.BaseURL = srv.URL
+	es.FullArticle = true
+
+	results, err := es.FetchDataBatch(context.Background(), 3, []int64{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Errorf("got data for %d of 3 pages", len(results))
+	}
+	for _, q := range queries() {
+		if strings.Contains(q, "|") {
+			t.Errorf("full articles requested together: %q", q)
+		}
+	}
+}
+
+func TestFetchDataBatchRejectsInvalidIDs(t *testing.T) {
+	es := New()
+	es.BaseURL = "http://127.0.0.1:0"
+	if _, err := es.FetchDataBatch(context.Background(), 3, []int64{1, 0}); err == nil {
+		t.Error("FetchDataBatch accepted page ID 0")
+	}
+}
+
+func TestMainspace(t *testing.T) {
+	tests := map[string]bool{
+		"Go_(programming_language)":                 true,
+		"Star_Wars:_Episode_I_–_The_Phantom_Menace": true,
+		"Mission:_Impossible":                       true,
+		"Pillow_Talk:_A_Story":                      true,
+		"Special:Search":                            false,
+		"File:Example.jpg":                          false,
+		"Wikipedia:Featured_articles":               false,
+		"Portal:Current_events":                     false,
+		"Talk:Go_(programming_language)":            false,
+		"User_talk:Example":                         false,
+		"Category:Programming_languages":            false,
+	}
+	for title, want := range tests {
+		if got := mainspace(title); got != want {
+			t.Errorf("mainspace(%q) = %v, want %v", title, got, want)
+		}
+	}
+}
+
+func TestTrending(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pageviews/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items":[{"articles":[
+			{"article":"Main_Page"},
+			{"article":"Special:Search"},
+			{"article":"Star_Wars:_Episode_I_–_The_Phantom_Menace"},
+			{"article":"Go_(programming_language)"}
+		]}]}`)
+	})
+	mux.HandleFunc("/w/api.php", func(w http.ResponseWriter, r *http.Request) {
+		var pages []map[string]any
+		for i, title := range strings.Split(r.URL.Query().Get("titles"), "|") {
+			pages = append(pages, map[string]any{"pageid": i + 1, "title": strings.ReplaceAll(title, "_", " ")})
+		}
+		var normalized []map[string]string
+		for _, title := range strings.Split(r.URL.Query().Get("titles"), "|") {
+			normalized = append(normalized, map[string]string{"from": title, "to": strings.ReplaceAll(title, "_", " ")})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"query": map[string]any{"normalized": normalized, "pages": pages}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	es := New()
+	es.BaseURL = srv.URL + "/w/api.php"
+	es.PageviewsURL = srv.URL + "/pageviews"
+	topics, err := es.Trending(context.Background(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, topic := range topics {
+		titles = append(titles, topic.Topic)
+	}
+	want := []string{"Star Wars: Episode I – The Phantom Menace", "Go (programming language)"}
+	if strings.Join(titles, "|") != strings.Join(want, "|") {
+		t.Errorf("Trending = %q, want %q", titles, want)
+	}
+}
@@ -0,0 +1,315 @@
+package wolframalpha
+
+// Data Source Adapter for WolframAlpha (Full Results API)
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const defaultTopicCount = 5
+
+type DataSourceWolframAlpha struct {
+	Client    *http.Client
+	BaseURL   string
+	AppID     string
+	UserAgent string
+	Units     string // "metric" or "nonmetric"; empty lets WolframAlpha decide from the caller's location
+
+	queries topicid.Map[query]
+}
+
+// query is an interpreted input and, once evaluated, its pods
+type query struct {
+	Input string
+	Pods  []pod
+}
+
+type pod struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Primary bool   `json:"primary"`
+	Subpods []struct {
+		Title     string `json:"title"`
+		Plaintext string `json:"plaintext"`
+	} `json:"subpods"`
+}
+
+func New() *DataSourceWolframAlpha {
+	return &DataSourceWolframAlpha{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://api.wolframalpha.com/v2/query",
+		UserAgent: "locus/wolframalpha-datasource",
+	}
+}
+
+// Init implements models.DataSource
+// Requires an AppID from the WolframAlpha developer portal
+func (es *DataSourceWolframAlpha) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.AppID == "" {
+		return errors.New("AppID is required for WolframAlpha DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceWolframAlpha) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, _, err := es.evaluate(ctx, "1+1")
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceWolframAlpha) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "Wolfram|Alpha",
+		Homepage:            "https://www.wolframalpha.com",
+		Attribution:         "Results from Wolfram|Alpha",
+		AttributionRequired: true,
+		TermsURL:            "https://products.wolframalpha.com/api/termsofuse",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Evaluates the input once; the topic is WolframAlpha's interpretation with its primary result,
+// e.g. "distance from Earth to Moon = 384400 km". Suggested rephrasings follow as further topics.
+func (es *DataSourceWolframAlpha) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	text := strings.TrimSpace(input)
+	if text == "" {
+		return nil, errors.New("Missing search input for WolframAlpha DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	pods, suggestions, err := es.evaluate(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]datasource.DataSourceTopic, 0, count)
+	if len(pods) > 0 {
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     headline(text, pods),
+			SourceURL: queryLink(text),
+			TopicID:   es.queries.Put(text, query{Input: text, Pods: pods}),
+		})
+	}
+	for _, suggestion := range suggestions {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     "Did you mean: " + suggestion,
+			SourceURL: queryLink(suggestion),
+			TopicID:   es.queries.Put(suggestion, query{Input: suggestion}),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Each pod ("Result", "Unit conversions", "Comparison", ...) becomes one data item, primary pods first
+func (es *DataSourceWolframAlpha) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	q, ok := es.queries.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown WolframAlpha topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if q.Pods == nil {
+		if err := es.Init(); err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+		defer cancel()
+		pods, _, err := es.evaluate(ctx, q.Input)
+		if err != nil {
+			return nil, err
+		}
+		q.Pods = pods
+	}
+
+	pods := append([]pod(nil), q.Pods...)
+	sort.SliceStable(pods, func(i, j int) bool {
+		return pods[i].Primary && !pods[j].Primary
+	})
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, p := range pods {
+		if len(results) >= count {
+			break
+		}
+		text := podText(p)
+		if text == "" {
+			continue
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  text,
+			SourceURL: queryLink(q.Input),
+			AnswerID:  topicid.Hash(q.Input + "#" + p.ID),
+		})
+	}
+	return results, nil
+}
+
+// evaluate runs a Full Results query and returns its pods, or the suggested rephrasings when
+// WolframAlpha could not interpret the input
+func (es *DataSourceWolframAlpha) evaluate(ctx context.Context, input string) ([]pod, []string, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	params := url.Values{}
+	params.Set("appid", es.AppID)
+	params.Set("input", input)
+	params.Set("output", "json")
+	params.Set("format", "plaintext")
+	if es.Units != "" {
+		params.Set("units", es.Units)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, es.BaseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, nil, fmt.Errorf("wolframalpha request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var response struct {
+		QueryResult struct {
+			Success bool            `json:"success"`
+			Error   json.RawMessage `json:"error"` // false, or an object with a msg
+			Pods    []pod           `json:"pods"`
+			// A single suggestion is sent as an object rather than a one-element array
+			DidYouMeans json.RawMessage `json:"didyoumeans"`
+		} `json:"queryresult"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, nil, err
+	}
+	result := response.QueryResult
+	var failure struct {
+		Msg string `json:"msg"`
+	}
+	if json.Unmarshal(result.Error, &failure) == nil && failure.Msg != "" {
+		return nil, nil, fmt.Errorf("wolframalpha error: %s", failure.Msg)
+	}
+	if result.Success {
+		return result.Pods, nil, nil
+	}
+
+	type suggestion struct {
+		Val string `json:"val"`
+	}
+	var many []suggestion
+	if json.Unmarshal(result.DidYouMeans, &many) != nil {
+		var one suggestion
+		if json.Unmarshal(result.DidYouMeans, &one) == nil {
+			many = []suggestion{one}
+		}
+	}
+	suggestions := make([]string, 0, len(many))
+	for _, s := range many {
+		if s.Val != "" {
+			suggestions = append(suggestions, s.Val)
+		}
+	}
+	return nil, suggestions, nil
+}
+
+// Helpers
+
+// headline pairs the input interpretation with the primary result when both are present
+func headline(input string, pods []pod) string {
+	var interpretation, primary string
+	for _, p := range pods {
+		value := ""
+		for _, sub := range p.Subpods {
+			if value = strings.TrimSpace(sub.Plaintext); value != "" {
+				break
+			}
+		}
+		if value == "" {
+			continue
+		}
+		switch {
+		case p.ID == "Input" && interpretation == "":
+			interpretation = value
+		case p.Primary && primary == "":
+			primary = value
+		}
+	}
+	if interpretation == "" {
+		interpretation = input
+	}
+	if primary == "" {
+		return interpretation
+	}
+	return strings.ReplaceAll(interpretation+" = "+primary, "\n", "; ")
+}
+
+// podText renders a pod as "Title: value", with one line per subpod
+func podText(p pod) string {
+	var lines []string
+	for _, sub := range p.Subpods {
+		text := strings.TrimSpace(sub.Plaintext)
+		if text == "" {
+			continue
+		}
+		if sub.Title != "" {
+			text = sub.Title + ": " + text
+		}
+		lines = append(lines, text)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	if len(lines) == 1 {
+		return p.Title + ": " + lines[0]
+	}
+	return p.Title + ":\n" + strings.Join(lines, "\n")
+}
+
+func queryLink(input string) string {
+	return "https://www.wolframalpha.com/input?i=" + url.QueryEscape(input)
+}
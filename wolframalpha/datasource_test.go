@@ -0,0 +1,97 @@
+package wolframalpha
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("appid") != "app" || q.Get("output") != "json" || q.Get("format") != "plaintext" || q.Get("units") != "metric" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch q.Get("input") {
+		case "distance to the moon":
+			fmt.Fprint(w, `{"queryresult":{"success":true,"error":false,"pods":[
+				{"id":"Input","title":"Input interpretation","subpods":[{"plaintext":"Moon | distance from Earth"}]},
+				{"id":"Comparison","title":"Comparison","subpods":[{"plaintext":""}]},
+				{"id":"UnitConversion","title":"Unit conversions","subpods":[{"plaintext":"238900 miles"},{"title":"light time","plaintext":"1.28 seconds"}]},
+				{"id":"Result","title":"Result","primary":true,"subpods":[{"plaintext":"384400 km"}]}
+			]}}`)
+		case "moon distanse":
+			fmt.Fprint(w, `{"queryresult":{"success":false,"error":false,"didyoumeans":{"score":"0.5","val":"moon distance"}}}`)
+		case "moon distance":
+			fmt.Fprint(w, `{"queryresult":{"success":true,"error":false,"pods":[{"id":"Result","title":"Result","primary":true,"subpods":[{"plaintext":"384400 km"}]}]}}`)
+		default:
+			fmt.Fprint(w, `{"queryresult":{"success":false,"error":{"code":"1","msg":"Invalid appid"}}}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSource(t *testing.T) *DataSourceWolframAlpha {
+	t.Helper()
+	es := New()
+	es.BaseURL = newServer(t).URL
+	es.AppID = "app"
+	es.Units = "metric"
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "distance to the moon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Moon | distance from Earth = 384400 km" ||
+		topics[0].SourceURL != "https://www.wolframalpha.com/input?i=distance+to+the+moon" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Result: 384400 km",
+		"Input interpretation: Moon | distance from Earth",
+		"Unit conversions:\n238900 miles\nlight time: 1.28 seconds",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+}
+
+func TestFetchTopicsSuggestion(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "moon distanse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Did you mean: moon distance" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	// The suggestion is evaluated when its data is fetched
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil || len(data) != 1 || data[0].DataText != "Result: 384400 km" {
+		t.Errorf("FetchData = %+v, %v", data, err)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	es := newSource(t)
+	if _, err := es.FetchTopics(5, "anything"); err == nil || err.Error() != "wolframalpha error: Invalid appid" {
+		t.Errorf("FetchTopics error = %v", err)
+	}
+}
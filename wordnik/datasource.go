@@ -0,0 +1,396 @@
+package wordnik
+
+// Data Source Adapter for Wordnik definitions, examples, related words and word of the day
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultItemCount  = 10
+)
+
+// Facets of a word, each offered as its own topic
+const (
+	facetDefinitions = "definitions"
+	facetExamples    = "examples"
+	facetRelated     = "related"
+	facetWordOfDay   = "wordoftheday"
+)
+
+var (
+	// wordOfTheDay matches "wotd", "word of the day" and either followed by a date
+	wordOfTheDay = regexp.MustCompile(`^(?i:wotd|word of the day)\s*(\d{4}-\d{2}-\d{2})?$`)
+	markup       = regexp.MustCompile(`<[^>]+>`)
+)
+
+type DataSourceWordnik struct {
+	Client    *http.Client
+	BaseURL   string
+	APIKey    string
+	UserAgent string
+
+	topics topicid.Map[topic]
+}
+
+type topic struct {
+	Word  string
+	Facet string
+	Date  string // Word of the day only
+}
+
+type definition struct {
+	Text             string `json:"text"`
+	PartOfSpeech     string `json:"partOfSpeech"`
+	AttributionText  string `json:"attributionText"`
+	SourceDictionary string `json:"sourceDictionary"`
+}
+
+type example struct {
+	Text  string `json:"text"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Year  int    `json:"year"`
+}
+
+func New() *DataSourceWordnik {
+	return &DataSourceWordnik{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "https://api.wordnik.com/v4",
+		UserAgent: "locus/wordnik-datasource",
+	}
+}
+
+// Init implements models.DataSource
+// Requires an API key
+func (es *DataSourceWordnik) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if es.APIKey == "" {
+		return errors.New("APIKey is required for Wordnik DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+func (es *DataSourceWordnik) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	_, err := es.doJSON(ctx, "/words.json/wordOfTheDay", url.Values{}, &struct{}{})
+	return err == nil
+}
+
+// Info implements source.Informer
+func (es *DataSourceWordnik) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:                "Wordnik",
+		Homepage:            "https://www.wordnik.com",
+		Attribution:         "Definitions from Wordnik (https://www.wordnik.com)",
+		AttributionRequired: true,
+		TermsURL:            "https://developer.wordnik.com/",
+	}
+}
+
+// FetchTopics implements models.DataSource
+// A known word yields three topics: its definitions, example sentences and related words.
+// Input of "wotd" or "word of the day", optionally followed by YYYY-MM-DD, yields that word of the day.
+func (es *DataSourceWordnik) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for Wordnik DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	if m := wordOfTheDay.FindStringSubmatch(query); m != nil {
+		params := url.Values{}
+		if m[1] != "" {
+			params.Set("date", m[1])
+		}
+		var response struct {
+			Word        string `json:"word"`
+			PublishDate string `json:"publishDate"`
+		}
+		found, err := es.doJSON(ctx, "/words.json/wordOfTheDay", params, &response)
+		if err != nil || !found {
+			return []datasource.DataSourceTopic{}, err
+		}
+		date := m[1]
+		if date == "" && len(response.PublishDate) >= 10 {
+			date = response.PublishDate[:10]
+		}
+		t := topic{Word: response.Word, Facet: facetWordOfDay, Date: date}
+		return []datasource.DataSourceTopic{{
+			Topic:     fmt.Sprintf("Word of the day for %s: %s", date, response.Word),
+			SourceURL: "https://www.wordnik.com/word-of-the-day/" + strings.ReplaceAll(date, "-", "/"),
+			Site:      "wordnik.com",
+			TopicID:   es.topics.Put(facetWordOfDay+"/"+date, t),
+		}}, nil
+	}
+
+	// Confirm the word exists and find its canonical form ("Cats" becomes "cat")
+	params := url.Values{}
+	params.Set("limit", "1")
+	params.Set("useCanonical", "true")
+	var probe []struct {
+		Word string `json:"word"`
+	}
+	found, err := es.doJSON(ctx, "/word.json/"+url.PathEscape(query)+"/definitions", params, &probe)
+	if err != nil || !found || len(probe) == 0 {
+		return []datasource.DataSourceTopic{}, err
+	}
+	word := probe[0].Word
+	if word == "" {
+		word = query
+	}
+
+	facets := []struct {
+		facet string
+		title string
+	}{
+		{facetDefinitions, "%s: definitions"},
+		{facetExamples, "%s: example sentences"},
+		{facetRelated, "%s: synonyms and related words"},
+	}
+	results := make([]datasource.DataSourceTopic, 0, len(facets))
+	for _, f := range facets {
+		if len(results) >= count {
+			break
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     fmt.Sprintf(f.title, word),
+			SourceURL: wordLink(word),
+			Site:      "wordnik.com",
+			TopicID:   es.topics.Put(f.facet+"/"+word, topic{Word: word, Facet: f.facet}),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Definitions and examples are returned one per item with their source attribution; related words
+// are one item per relationship type (synonym, antonym, rhyme and so on)
+func (es *DataSourceWordnik) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	t, ok := es.topics.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown Wordnik topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultItemCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	path := "/word.json/" + url.PathEscape(t.Word)
+	switch t.Facet {
+	case facetWordOfDay:
+		return es.wordOfTheDay(ctx, t, topicID)
+	case facetDefinitions:
+		params := url.Values{}
+		params.Set("limit", strconv.Itoa(count))
+		params.Set("includeRelated", "false")
+		var definitions []definition
+		if _, err := es.doJSON(ctx, path+"/definitions", params, &definitions); err != nil {
+			return nil, err
+		}
+		results := make([]datasource.DataSourceData, 0, len(definitions))
+		for i, d := range definitions {
+			// Some sources return entries with only attribution or cross-references
+			if strings.TrimSpace(d.Text) == "" {
+				continue
+			}
+			results = append(results, datasource.DataSourceData{
+				DataText:  formatDefinition(t.Word, d),
+				SourceURL: wordLink(t.Word),
+				Site:      d.SourceDictionary,
+				AnswerID:  topicid.Hash(fmt.Sprintf("%s/%s#%d", t.Facet, t.Word, i)),
+			})
+		}
+		return results, nil
+	case facetExamples:
+		params := url.Values{}
+		params.Set("limit", strconv.Itoa(count))
+		var response struct {
+			Examples []example `json:"examples"`
+		}
+		if _, err := es.doJSON(ctx, path+"/examples", params, &response); err != nil {
+			return nil, err
+		}
+		results := make([]datasource.DataSourceData, 0, len(response.Examples))
+		for i, e := range response.Examples {
+			link := e.URL
+			if link == "" {
+				link = wordLink(t.Word)
+			}
+			results = append(results, datasource.DataSourceData{
+				DataText:  formatExample(e),
+				SourceURL: link,
+				Site:      e.Title,
+				AnswerID:  topicid.Hash(fmt.Sprintf("%s/%s#%d", t.Facet, t.Word, i)),
+			})
+		}
+		return results, nil
+	}
+
+	params := url.Values{}
+	params.Set("useCanonical", "true")
+	params.Set("limitPerRelationshipType", strconv.Itoa(count))
+	var related []struct {
+		RelationshipType string   `json:"relationshipType"`
+		Words            []string `json:"words"`
+	}
+	if _, err := es.doJSON(ctx, path+"/relatedWords", params, &related); err != nil {
+		return nil, err
+	}
+	results := make([]datasource.DataSourceData, 0, len(related))
+	for _, r := range related {
+		if len(r.Words) == 0 {
+			continue
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  fmt.Sprintf("%s — %s: %s", t.Word, strings.ReplaceAll(r.RelationshipType, "-", " "), strings.Join(r.Words, ", ")),
+			SourceURL: wordLink(t.Word),
+			Site:      "wordnik.com",
+			AnswerID:  topicid.Hash(fmt.Sprintf("%s/%s#%s", t.Facet, t.Word, r.RelationshipType)),
+		})
+	}
+	return results, nil
+}
+
+// wordOfTheDay returns the editor's note with the word's definitions, then its example sentences
+func (es *DataSourceWordnik) wordOfTheDay(ctx context.Context, t topic, topicID int64) ([]datasource.DataSourceData, error) {
+	params := url.Values{}
+	if t.Date != "" {
+		params.Set("date", t.Date)
+	}
+	var response struct {
+		Word        string       `json:"word"`
+		Note        string       `json:"note"`
+		Definitions []definition `json:"definitions"`
+		Examples    []example    `json:"examples"`
+	}
+	if _, err := es.doJSON(ctx, "/words.json/wordOfTheDay", params, &response); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Word of the day for %s: %s", t.Date, response.Word)
+	for _, d := range response.Definitions {
+		b.WriteString("\n" + formatDefinition(response.Word, d))
+	}
+	if note := strings.TrimSpace(response.Note); note != "" {
+		b.WriteString("\n\n" + note)
+	}
+	link := "https://www.wordnik.com/word-of-the-day/" + strings.ReplaceAll(t.Date, "-", "/")
+	results := []datasource.DataSourceData{{
+		DataText:  b.String(),
+		SourceURL: link,
+		Site:      "wordnik.com",
+		AnswerID:  topicID,
+	}}
+	for i, e := range response.Examples {
+		results = append(results, datasource.DataSourceData{
+			DataText:  formatExample(e),
+			SourceURL: e.URL,
+			Site:      e.Title,
+			AnswerID:  topicid.Hash(fmt.Sprintf("%s/%s#%d", t.Facet, t.Date, i)),
+		})
+	}
+	return results, nil
+}
+
+// doJSON performs an authenticated API request and decodes the JSON response into target;
+// a 404 (unknown word or date) reports found as false
+func (es *DataSourceWordnik) doJSON(ctx context.Context, path string, params url.Values, target interface{}) (bool, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	params.Set("api_key", es.APIKey)
+	uri := strings.TrimRight(es.BaseURL, "/") + path + "?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return false, fmt.Errorf("wordnik request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return true, json.NewDecoder(resp.Body).Decode(target)
+}
+
+// Helpers
+func formatDefinition(word string, d definition) string {
+	text := word
+	if d.PartOfSpeech != "" {
+		text += " (" + d.PartOfSpeech + ")"
+	}
+	text += ": " + strings.TrimSpace(markup.ReplaceAllString(d.Text, ""))
+	if d.AttributionText != "" {
+		text += "\n— " + d.AttributionText
+	}
+	return text
+}
+
+func formatExample(e example) string {
+	text := "“" + strings.TrimSpace(e.Text) + "”"
+	source := e.Title
+	if e.Year > 0 && source != "" {
+		source = fmt.Sprintf("%s, %d", source, e.Year)
+	} else if e.Year > 0 {
+		source = strconv.Itoa(e.Year)
+	}
+	if source != "" {
+		text += "\n— " + source
+	}
+	return text
+}
+
+func wordLink(word string) string {
+	return "https://www.wordnik.com/words/" + url.PathEscape(word)
+}
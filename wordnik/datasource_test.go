@@ -0,0 +1,131 @@
+package wordnik
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/word.json/Cats/definitions", func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query(); q.Get("api_key") != "key" || q.Get("useCanonical") != "true" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[{"word":"cat"}]`)
+	})
+	mux.HandleFunc("/word.json/cat/definitions", func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query(); q.Get("limit") != "10" || q.Get("includeRelated") != "false" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[
+			{"text":"A small <xref>carnivorous</xref> mammal.","partOfSpeech":"noun","attributionText":"from The American Heritage Dictionary","sourceDictionary":"ahd-5"},
+			{"text":"","sourceDictionary":"wiktionary"}
+		]`)
+	})
+	mux.HandleFunc("/word.json/cat/examples", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"examples":[{"text":" The cat sat. ","title":"A Novel","url":"https://example.com/novel","year":1901}]}`)
+	})
+	mux.HandleFunc("/word.json/cat/relatedWords", func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query(); q.Get("limitPerRelationshipType") != "10" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[{"relationshipType":"synonym","words":["feline","puss"]},{"relationshipType":"same-context","words":[]},{"relationshipType":"rhyme","words":["hat"]}]`)
+	})
+	mux.HandleFunc("/words.json/wordOfTheDay", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("date") != "2024-03-01" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"word":"apricity","publishDate":"2024-03-01T03:00:00.000+0000","note":" The warmth of the sun in winter. ",
+			"definitions":[{"text":"The warmth of the sun in winter.","partOfSpeech":"noun"}],
+			"examples":[{"text":"Basking in the apricity.","title":"A Diary","url":"https://example.com/diary"}]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newSource(t *testing.T) *DataSourceWordnik {
+	t.Helper()
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL + "/"
+	es.APIKey = "key"
+	return es
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "Cats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"cat: definitions", "cat: example sentences", "cat: synonyms and related words"}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, title := range want {
+		if topics[i].Topic != title {
+			t.Errorf("topic %d = %q, want %q", i, topics[i].Topic, title)
+		}
+	}
+
+	wantData := [][]string{
+		{"cat (noun): A small carnivorous mammal.\n— from The American Heritage Dictionary"},
+		{"“The cat sat.”\n— A Novel, 1901"},
+		{"cat — synonym: feline, puss", "cat — rhyme: hat"},
+	}
+	for i, topic := range topics {
+		data, err := es.FetchData(0, topic.TopicID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(data) != len(wantData[i]) {
+			t.Fatalf("FetchData(%q) = %+v", topic.Topic, data)
+		}
+		for j, text := range wantData[i] {
+			if data[j].DataText != text {
+				t.Errorf("%s data %d = %q, want %q", topic.Topic, j, data[j].DataText, text)
+			}
+		}
+	}
+}
+
+func TestFetchTopicsUnknownWord(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "qwxz")
+	if err != nil || len(topics) != 0 {
+		t.Errorf("FetchTopics for an unknown word = %+v, %v", topics, err)
+	}
+}
+
+func TestWordOfTheDay(t *testing.T) {
+	es := newSource(t)
+	topics, err := es.FetchTopics(5, "Word of the day 2024-03-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 1 || topics[0].Topic != "Word of the day for 2024-03-01: apricity" ||
+		topics[0].SourceURL != "https://www.wordnik.com/word-of-the-day/2024/03/01" {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+
+	data, err := es.FetchData(5, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Word of the day for 2024-03-01: apricity\napricity (noun): The warmth of the sun in winter.\n\nThe warmth of the sun in winter.",
+		"“Basking in the apricity.”\n— A Diary",
+	}
+	if len(data) != len(want) {
+		t.Fatalf("FetchData = %+v", data)
+	}
+	for i, text := range want {
+		if data[i].DataText != text {
+			t.Errorf("data %d = %q, want %q", i, data[i].DataText, text)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package zim
+
+// Data Source Adapter for ZIM archives (offline Wikipedia, Stack Exchange, Gutenberg) served by kiwix-serve,
+// for air-gapped deployments
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	datasource "github.com/locus-search/datasource-sdk"
+	"github.com/locus-search/datasource/internal/readability"
+	"github.com/locus-search/datasource/internal/topicid"
+	"github.com/locus-search/datasource/source"
+	"github.com/locus-search/datasource/timeout"
+)
+
+const (
+	defaultTopicCount = 5
+	defaultDataCount  = 3
+	// Paragraphs are grouped into data items of about this many characters
+	chunkSize = 1500
+)
+
+var tags = regexp.MustCompile(`<[^>]*>`)
+
+type DataSourceZIM struct {
+	Client    *http.Client
+	BaseURL   string   // kiwix-serve root, e.g. http://localhost:8080
+	Books     []string // Book names to search, e.g. "wikipedia_en_all_maxi_2024-01"; default all served books
+	UserAgent string
+
+	articles topicid.Map[entry]
+}
+
+type entry struct {
+	Title   string
+	URL     string
+	Book    string
+	Snippet string
+}
+
+// searchFeed decodes the OpenSearch RSS that /search returns with format=xml
+type searchFeed struct {
+	Items []struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		Book        struct {
+			Title string `xml:"title"`
+		} `xml:"book"`
+	} `xml:"channel>item"`
+}
+
+func New() *DataSourceZIM {
+	return &DataSourceZIM{
+		Client: &http.Client{
+			Timeout: 8 * time.Second,
+		},
+		BaseURL:   "http://localhost:8080",
+		UserAgent: "locus/zim-datasource",
+	}
+}
+
+// Init implements models.DataSource
+func (es *DataSourceZIM) Init() error {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if strings.TrimSpace(es.BaseURL) == "" {
+		return errors.New("BaseURL is required for ZIM DataSource")
+	}
+	return nil
+}
+
+// CheckAvailability implements models.DataSource
+// Reports whether kiwix-serve answers its OPDS catalog
+func (es *DataSourceZIM) CheckAvailability() bool {
+	if err := es.Init(); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Check)
+	defer cancel()
+	resp, err := es.get(ctx, "/catalog/v2/entries?count=1")
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return true
+}
+
+// Info implements source.Informer
+func (es *DataSourceZIM) Info() source.SourceInfo {
+	return source.SourceInfo{
+		Name:     "Kiwix",
+		Homepage: es.BaseURL,
+	}
+}
+
+// FetchTopics implements models.DataSource
+// Runs a full-text search over the books; articles are topics titled by the article title
+func (es *DataSourceZIM) FetchTopics(count int, input string) ([]datasource.DataSourceTopic, error) {
+	query := strings.TrimSpace(input)
+	if query == "" {
+		return nil, errors.New("Missing search input for ZIM DataSource")
+	}
+	if count <= 0 {
+		count = defaultTopicCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Topics)
+	defer cancel()
+	params := url.Values{}
+	params.Set("pattern", query)
+	params.Set("start", "0")
+	params.Set("pageLength", strconv.Itoa(count))
+	params.Set("format", "xml")
+	for _, book := range es.Books {
+		params.Add("books.name", book)
+	}
+	resp, err := es.get(ctx, "/search?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var feed searchFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("decoding kiwix search results: %w", err)
+	}
+
+	base, err := url.Parse(strings.TrimRight(es.BaseURL, "/") + "/")
+	if err != nil {
+		return nil, err
+	}
+	results := make([]datasource.DataSourceTopic, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		link, err := url.Parse(strings.TrimSpace(item.Link))
+		if err != nil || item.Title == "" {
+			continue
+		}
+		e := entry{
+			Title:   strings.TrimSpace(item.Title),
+			URL:     base.ResolveReference(link).String(),
+			Book:    strings.TrimSpace(item.Book.Title),
+			Snippet: strings.Join(strings.Fields(html.UnescapeString(tags.ReplaceAllString(item.Description, ""))), " "),
+		}
+		if e.Book == "" {
+			e.Book = "kiwix"
+		}
+		results = append(results, datasource.DataSourceTopic{
+			Topic:     e.Title,
+			SourceURL: e.URL,
+			Site:      e.Book,
+			TopicID:   es.articles.Put(e.URL, e),
+		})
+	}
+	return results, nil
+}
+
+// FetchData implements models.DataSource
+// Fetches the article from the archive and returns its text in items of about 1500 characters, each
+// starting with the title. An article without readable paragraphs falls back to the search snippet.
+func (es *DataSourceZIM) FetchData(count int, topicID int64) ([]datasource.DataSourceData, error) {
+	if topicID <= 0 {
+		return nil, errors.New("topicID is required")
+	}
+	e, ok := es.articles.Get(topicID)
+	if !ok {
+		return nil, fmt.Errorf("unknown ZIM topicID %d", topicID)
+	}
+	if count <= 0 {
+		count = defaultDataCount
+	}
+	if err := es.Init(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout.Data)
+	defer cancel()
+	resp, err := es.get(ctx, e.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	article, err := readability.FromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	paragraphs := article.Paragraphs
+	if len(paragraphs) == 0 && e.Snippet != "" {
+		paragraphs = []string{e.Snippet}
+	}
+
+	results := make([]datasource.DataSourceData, 0, count)
+	for _, chunk := range chunks(paragraphs, chunkSize) {
+		if len(results) >= count {
+			break
+		}
+		answerID := topicID
+		if len(results) > 0 {
+			answerID = topicid.Hash(fmt.Sprintf("%s#%d", e.URL, len(results)))
+		}
+		results = append(results, datasource.DataSourceData{
+			DataText:  e.Title + "\n" + chunk,
+			SourceURL: e.URL,
+			Site:      e.Book,
+			AnswerID:  answerID,
+		})
+	}
+	return results, nil
+}
+
+// get requests a path below BaseURL, or an absolute URL, and returns the response when it succeeded
+func (es *DataSourceZIM) get(ctx context.Context, uri string) (*http.Response, error) {
+	client := es.Client
+	if client == nil {
+		client = &http.Client{Timeout: 8 * time.Second}
+	}
+	if strings.HasPrefix(uri, "/") {
+		uri = strings.TrimRight(es.BaseURL, "/") + uri
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	if es.UserAgent != "" {
+		req.Header.Set("User-Agent", es.UserAgent)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, fmt.Errorf("kiwix request failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// Helpers
+
+// chunks joins paragraphs into blocks of roughly size characters without splitting a paragraph
+func chunks(paragraphs []string, size int) []string {
+	var out []string
+	var current strings.Builder
+	for _, p := range paragraphs {
+		if current.Len() > 0 && current.Len()+len(p) > size {
+			out = append(out, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(p)
+	}
+	if current.Len() > 0 {
+		out = append(out, current.String())
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package zim
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /kiwix/search", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("pattern") != "photosynthesis" || q.Get("pageLength") != "3" || q.Get("format") != "xml" ||
+			fmt.Sprint(q["books.name"]) != "[wikipedia_en_all stackexchange_biology]" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">
+	<channel>
+		<title>Search: photosynthesis</title>
+		<opensearch:totalResults>120</opensearch:totalResults>
+		<item>
+			<title>Photosynthesis</title>
+			<link>/kiwix/content/wikipedia_en_all/A/Photosynthesis</link>
+			<description>...process used by plants... &lt;b&gt;Photosynthesis&lt;/b&gt; converts light &amp;amp; water...</description>
+			<book><title>Wikipedia</title></book>
+			<wordCount>9000</wordCount>
+		</item>
+		<item>
+			<title>Why are leaves green?</title>
+			<link>content/stackexchange_biology/questions/1</link>
+			<description>Chlorophyll absorbs red and blue light.</description>
+		</item>
+		<item>
+			<title></title>
+			<link>/kiwix/content/wikipedia_en_all/A/Untitled</link>
+		</item>
+	</channel>
+</rss>`)
+	})
+	mux.HandleFunc("GET /kiwix/content/wikipedia_en_all/A/Photosynthesis", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Photosynthesis</title></head><body><div id="content">
+			<p>Photosynthesis is a process used by plants to convert light energy into chemical energy.</p>
+			<p>Most plants, algae and cyanobacteria perform photosynthesis.</p>
+		</div></body></html>`)
+	})
+	mux.HandleFunc("GET /kiwix/content/stackexchange_biology/questions/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><img src="leaf.png"></body></html>`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchTopicsAndData(t *testing.T) {
+	srv := newServer(t)
+	es := New()
+	es.BaseURL = srv.URL + "/kiwix"
+	es.Books = []string{"wikipedia_en_all", "stackexchange_biology"}
+
+	topics, err := es.FetchTopics(3, "photosynthesis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Photosynthesis|Wikipedia|" + srv.URL + "/kiwix/content/wikipedia_en_all/A/Photosynthesis",
+		"Why are leaves green?|kiwix|" + srv.URL + "/kiwix/content/stackexchange_biology/questions/1",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("FetchTopics = %+v", topics)
+	}
+	for i, w := range want {
+		if got := topics[i].Topic + "|" + topics[i].Site + "|" + topics[i].SourceURL; got != w {
+			t.Errorf("topic %d = %q, want %q", i, got, w)
+		}
+	}
+
+	data, err := es.FetchData(3, topics[0].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := "Photosynthesis\nPhotosynthesis is a process used by plants to convert light energy into chemical energy.\n\n" +
+		"Most plants, algae and cyanobacteria perform photosynthesis."
+	if len(data) != 1 || data[0].DataText != wantData || data[0].AnswerID != topics[0].TopicID {
+		t.Errorf("FetchData = %q, want %q", data, wantData)
+	}
+
+	// Without readable paragraphs the search snippet is used
+	data, err = es.FetchData(3, topics[1].TopicID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0].DataText != "Why are leaves green?\nChlorophyll absorbs red and blue light." {
+		t.Errorf("FetchData = %q", data)
+	}
+}
+
+func TestCheckAvailability(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog/v2/entries" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `<feed xmlns="http://www.w3.org/2005/Atom"></feed>`)
+	}))
+	defer srv.Close()
+	es := New()
+	es.BaseURL = srv.URL
+	if !es.CheckAvailability() {
+		t.Error("CheckAvailability = false")
+	}
+	es.BaseURL = srv.URL + "/missing"
+	if es.CheckAvailability() {
+		t.Error("CheckAvailability = true without a catalog")
+	}
+}